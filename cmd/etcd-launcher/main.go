@@ -28,7 +28,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/minio/minio-go"
 	"github.com/pkg/errors"
 	"go.etcd.io/etcd/v3/clientv3"
 	"go.etcd.io/etcd/v3/clientv3/snapshot"
@@ -40,6 +39,7 @@ import (
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
 	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/util/encryption"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -558,16 +558,32 @@ func (e *etcdCluster) restoreDatadirFromBackupIfNeeded(ctx context.Context, k8cC
 
 	log.Infow("restoring datadir from backup", "backup-name", activeRestore.Spec.BackupName)
 
-	s3Client, bucketName, err := resources.GetEtcdRestoreS3Client(ctx, activeRestore, false, client, k8cCluster)
+	store, err := resources.GetEtcdRestoreBackupStore(ctx, activeRestore, false, client, k8cCluster)
 	if err != nil {
-		return fmt.Errorf("failed to get s3 client: %w", err)
+		return fmt.Errorf("failed to get backup store: %w", err)
 	}
 
 	objectName := fmt.Sprintf("%s-%s", k8cCluster.GetName(), activeRestore.Spec.BackupName)
 	downloadedSnapshotFile := fmt.Sprintf("/tmp/%s", objectName)
 
-	if err := s3Client.FGetObject(bucketName, objectName, downloadedSnapshotFile, minio.GetObjectOptions{}); err != nil {
-		return fmt.Errorf("failed to download backup (%s/%s): %w", bucketName, objectName, err)
+	if err := store.Download(objectName, downloadedSnapshotFile); err != nil {
+		return fmt.Errorf("failed to download backup %s: %w", objectName, err)
+	}
+
+	encrypted, err := encryption.IsEncrypted(downloadedSnapshotFile)
+	if err != nil {
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+	if encrypted {
+		keys, err := resources.GetEtcdRestoreDecryptionKeys(ctx, activeRestore, false, client, k8cCluster)
+		if err != nil {
+			return fmt.Errorf("failed to get backup decryption keys: %w", err)
+		}
+		header, err := encryption.DecryptFile(downloadedSnapshotFile, downloadedSnapshotFile, keys)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt backup: %w", err)
+		}
+		log.Infow("decrypted backup", "key-id", header.KeyID)
 	}
 
 	if err := os.RemoveAll(e.dataDir); err != nil {
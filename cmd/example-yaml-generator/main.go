@@ -162,6 +162,9 @@ func createExampleSeed() *kubermaticv1.Seed {
 				},
 			},
 			ProxySettings: &proxySettings,
+			NodeportProxy: kubermaticv1.NodeportProxyConfig{
+				Limits: &kubermaticv1.NodeportProxyLimits{},
+			},
 		},
 	}
 
@@ -178,6 +181,7 @@ func createExampleSeed() *kubermaticv1.Seed {
 }
 
 func createExampleKubermaticConfiguration() *operatorv1alpha1.KubermaticConfiguration {
+	earlyPercentage := 5
 	cfg := &operatorv1alpha1.KubermaticConfiguration{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: operatorv1alpha1.SchemeGroupVersion.String(),
@@ -195,6 +199,23 @@ func createExampleKubermaticConfiguration() *operatorv1alpha1.KubermaticConfigur
 			API: operatorv1alpha1.KubermaticAPIConfiguration{
 				AccessibleAddons: []string{},
 			},
+			Versions: operatorv1alpha1.KubermaticVersionsConfiguration{
+				Kubernetes: operatorv1alpha1.KubermaticVersioningConfiguration{
+					// rollout waves are disabled by default, but all fields should be documented
+					Rollout: &operatorv1alpha1.KubermaticRolloutConfiguration{
+						Waves: []operatorv1alpha1.KubermaticRolloutWave{
+							{
+								Name:     "canary",
+								Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}},
+							},
+							{
+								Name:       "early",
+								Percentage: &earlyPercentage,
+							},
+						},
+					},
+				},
+			},
 		},
 	}
 
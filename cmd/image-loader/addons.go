@@ -24,6 +24,7 @@ import (
 	"go.uber.org/zap"
 
 	addonutil "k8c.io/kubermatic/v2/pkg/addon"
+	"k8c.io/kubermatic/v2/pkg/cni"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/resources"
 
@@ -52,7 +53,24 @@ func getImagesFromAddons(log *zap.SugaredLogger, addonsPath string, cluster *kub
 			continue
 		}
 		addonName := info.Name()
-		addonImages, err := getImagesFromAddon(log, path.Join(addonsPath, addonName), serializer, addonData)
+		addonPath := path.Join(addonsPath, addonName)
+
+		metadata, err := addonutil.LoadMetadata(addonPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load metadata of addon %s: %v", addonName, err)
+		}
+
+		// CNI plugin addons render the images of the version of the cluster, so every supported version needs to be rendered
+		if metadata.CNIPlugin != "" {
+			cniImages, err := getImagesFromCNIPluginAddon(log, addonPath, serializer, cluster, credentials, metadata.CNIPlugin)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get images for addon %s: %v", addonName, err)
+			}
+			images = append(images, cniImages...)
+			continue
+		}
+
+		addonImages, err := getImagesFromAddon(log, addonPath, serializer, addonData)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get images for addon %s: %v", addonName, err)
 		}
@@ -62,6 +80,31 @@ func getImagesFromAddons(log *zap.SugaredLogger, addonsPath string, cluster *kub
 	return images, nil
 }
 
+func getImagesFromCNIPluginAddon(log *zap.SugaredLogger, addonPath string, decoder runtime.Decoder, cluster *kubermaticv1.Cluster, credentials resources.Credentials, pluginType kubermaticv1.CNIPluginType) ([]string, error) {
+	versions, err := cni.SupportedVersions(pluginType)
+	if err != nil {
+		return nil, err
+	}
+
+	var images []string
+	for _, version := range versions {
+		pluginCluster := cluster.DeepCopy()
+		pluginCluster.Spec.CNIPlugin = &kubermaticv1.CNIPluginSettings{Type: pluginType, Version: version}
+
+		addonData, err := addonutil.NewTemplateData(pluginCluster, credentials, "", "", "", nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create addon template data: %v", err)
+		}
+
+		versionImages, err := getImagesFromAddon(log, addonPath, decoder, addonData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get images for version %s: %v", version, err)
+		}
+		images = append(images, versionImages...)
+	}
+	return images, nil
+}
+
 func getImagesFromAddon(log *zap.SugaredLogger, addonPath string, decoder runtime.Decoder, data *addonutil.TemplateData) ([]string, error) {
 	log = log.With(zap.String("addon", path.Base(addonPath)))
 	log.Debug("Processing manifests...")
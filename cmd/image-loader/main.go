@@ -442,6 +442,7 @@ func getTemplateData(clusterVersion *kubermaticversion.Version, kubermaticVersio
 		resources.PrometheusApiserverClientCertificateSecretName,
 		resources.MetricsServerKubeconfigSecretName,
 		resources.MachineControllerWebhookServingCertSecretName,
+		resources.UserClusterControllerWebhookServingCertSecretName,
 		resources.InternalUserClusterAdminKubeconfigSecretName,
 		resources.ClusterAutoscalerKubeconfigSecretName,
 		resources.KubernetesDashboardKubeconfigSecretName,
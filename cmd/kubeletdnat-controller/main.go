@@ -47,8 +47,9 @@ func main() {
 	kubeconfigFlag := flag.String("kubeconfig", "", "Path to a kubeconfig.")
 	master := flag.String("master", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig")
 	networkFlag := flag.String("node-access-network", "", "The network in CIDR notation to translate to.")
-	chainNameFlag := flag.String("chain-name", "node-access-dnat", "Name of the chain in nat table.")
+	chainNameFlag := flag.String("chain-name", "node-access-dnat", "Name of the chain in nat table. The nftables backend also uses it as the name of its table.")
 	vpnInterfaceFlag := flag.String("vpn-interface", "tun0", "Name of the vpn interface.")
+	backendFlag := flag.String("backend", string(kubeletdnat.BackendIPTables), "The backend used to apply the rules, either iptables or nftables.")
 	flag.Parse()
 
 	rawLog := kubermaticlog.New(logOpts.Debug, logOpts.Format)
@@ -93,7 +94,7 @@ func main() {
 		log.Fatalw("failed to create mgr", zap.Error(err))
 	}
 
-	if err := kubeletdnat.Add(mgr, *chainNameFlag, nodeAccessNetwork, log, *vpnInterfaceFlag); err != nil {
+	if err := kubeletdnat.Add(mgr, *chainNameFlag, nodeAccessNetwork, log, *vpnInterfaceFlag, kubeletdnat.Backend(*backendFlag)); err != nil {
 		log.Fatalw("failed to add the kubelet dnat controller", zap.Error(err))
 	}
 
@@ -50,8 +50,8 @@ import (
 	"go.uber.org/zap"
 
 	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+	"k8c.io/kubermatic/v2/pkg/audit"
 	"k8c.io/kubermatic/v2/pkg/cluster/client"
-	"k8c.io/kubermatic/v2/pkg/controller/master-controller-manager/rbac"
 	kubermaticclientset "k8c.io/kubermatic/v2/pkg/crd/client/clientset/versioned"
 	kubermaticinformers "k8c.io/kubermatic/v2/pkg/crd/client/informers/externalversions"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
@@ -78,6 +78,7 @@ import (
 	"k8s.io/klog"
 	"k8s.io/metrics/pkg/apis/metrics/v1beta1"
 	ctrlruntime "sigs.k8s.io/controller-runtime"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
@@ -197,7 +198,8 @@ func createInitProviders(ctx context.Context, options serverRunOptions) (provide
 	}
 
 	seedClientGetter := provider.SeedClientGetterFactory(seedKubeconfigGetter)
-	clusterProviderGetter := clusterProviderFactory(mgr.GetRESTMapper(), seedKubeconfigGetter, seedClientGetter, options)
+	projectRoleProvider := kubernetesprovider.NewProjectRoleProvider(client)
+	clusterProviderGetter := clusterProviderFactory(mgr.GetRESTMapper(), seedKubeconfigGetter, seedClientGetter, projectRoleProvider, options)
 
 	presetsProvider, err := kubernetesprovider.NewPresetsProvider(ctx, client, options.presetsFile, options.dynamicPresets)
 	if err != nil {
@@ -235,6 +237,8 @@ func createInitProviders(ctx context.Context, options serverRunOptions) (provide
 
 	serviceAccountProvider := kubernetesprovider.NewServiceAccountProvider(defaultImpersonationClient.CreateImpersonatedClient, client, options.domain)
 	projectMemberProvider := kubernetesprovider.NewProjectMemberProvider(defaultImpersonationClient.CreateImpersonatedClient, client, kubernetesprovider.IsProjectServiceAccount)
+	groupProjectBindingProvider := kubernetesprovider.NewGroupProjectBindingProvider(defaultImpersonationClient.CreateImpersonatedClient, client)
+	clusterTemplateProvider := kubernetesprovider.NewClusterTemplateProvider(client)
 	projectProvider, err := kubernetesprovider.NewProjectProvider(defaultImpersonationClient.CreateImpersonatedClient, client)
 	if err != nil {
 		return providers{}, fmt.Errorf("failed to create project provider due to %v", err)
@@ -283,6 +287,18 @@ func createInitProviders(ctx context.Context, options serverRunOptions) (provide
 		return providers{}, fmt.Errorf("failed to create user watcher due to %v", err)
 	}
 
+	// the audit events are only listed when admins query them, so they are read from the apiserver
+	// instead of caching all events of the cluster
+	auditClient, err := ctrlruntimeclient.New(masterCfg, ctrlruntimeclient.Options{Mapper: mgr.GetRESTMapper()})
+	if err != nil {
+		return providers{}, fmt.Errorf("failed to create audit client: %v", err)
+	}
+	auditLogger, err := createAuditLogger(options, auditClient)
+	if err != nil {
+		return providers{}, err
+	}
+	go auditLogger.Start(ctx)
+
 	return providers{
 		sshKey:                                sshKeyProvider,
 		privilegedSSHKeyProvider:              privilegedSSHKeyProvider,
@@ -314,9 +330,35 @@ func createInitProviders(ctx context.Context, options serverRunOptions) (provide
 		constraintTemplateProvider:            constraintTemplateProvider,
 		constraintProviderGetter:              constraintProviderGetter,
 		alertmanagerProviderGetter:            alertmanagerProviderGetter,
+		auditLogger:                           auditLogger,
+		projectRoleProvider:                   projectRoleProvider,
+		groupProjectBindingProvider:           groupProjectBindingProvider,
+		privilegedGroupProjectBindingProvider: groupProjectBindingProvider,
+		clusterTemplateProvider:               clusterTemplateProvider,
 	}, nil
 }
 
+// createAuditLogger returns a logger which writes the audit events to the configured sinks
+func createAuditLogger(options serverRunOptions, client ctrlruntimeclient.Client) (*audit.Logger, error) {
+	var sinks []audit.Sink
+
+	if options.auditLogFile != "" {
+		fileSink, err := audit.NewFileSink(options.auditLogFile)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, fileSink)
+	}
+	if options.auditWebhookURL != "" {
+		sinks = append(sinks, audit.NewWebhookSink(options.auditWebhookURL, nil))
+	}
+	if options.auditKubernetesEvents {
+		sinks = append(sinks, audit.NewEventSink(client, options.namespace))
+	}
+
+	return audit.New(kubermaticlog.Logger, sinks...), nil
+}
+
 func createOIDCClients(options serverRunOptions) (auth.OIDCIssuerVerifier, error) {
 	return auth.NewOpenIDClient(
 		options.oidcURL,
@@ -351,9 +393,14 @@ func createAuthClients(options serverRunOptions, prov providers) (auth.TokenVeri
 		return nil, nil, fmt.Errorf("failed to create OIDC Authenticator: %v", err)
 	}
 
+	serviceAccountTokenAuth, err := createServiceAccountTokenAuthenticator(options)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	jwtExtractorVerifier := auth.NewServiceAccountAuthClient(
 		auth.NewHeaderBearerTokenExtractor("Authorization"),
-		serviceaccount.JWTTokenAuthenticator([]byte(options.serviceAccountSigningKey)),
+		serviceAccountTokenAuth,
 		prov.privilegedServiceAccountTokenProvider,
 	)
 
@@ -362,6 +409,16 @@ func createAuthClients(options serverRunOptions, prov providers) (auth.TokenVeri
 	return tokenVerifiers, tokenExtractors, nil
 }
 
+// createServiceAccountTokenAuthenticator returns an authenticator which accepts the tokens signed
+// with the current signing key and with the keys used before a key rotation
+func createServiceAccountTokenAuthenticator(options serverRunOptions) (serviceaccount.TokenAuthenticator, error) {
+	tokenAuth, err := serviceaccount.JWTTokenAuthenticator([]byte(options.serviceAccountSigningKey), []byte(options.serviceAccountVerificationKeys))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create service account token authenticator: %v", err)
+	}
+	return tokenAuth, nil
+}
+
 func createAPIHandler(options serverRunOptions, prov providers, oidcIssuerVerifier auth.OIDCIssuerVerifier, tokenVerifiers auth.TokenVerifier, tokenExtractors auth.TokenExtractor, updateManager common.UpdateManager) (http.HandlerFunc, error) {
 	var prometheusClient prometheusapi.Client
 	if options.featureGates.Enabled(features.PrometheusEndpoint) {
@@ -377,8 +434,12 @@ func createAPIHandler(options serverRunOptions, prov providers, oidcIssuerVerifi
 	if err != nil {
 		return nil, fmt.Errorf("failed to create service account token generator due to %v", err)
 	}
-	serviceAccountTokenAuth := serviceaccount.JWTTokenAuthenticator([]byte(options.serviceAccountSigningKey))
+	serviceAccountTokenAuth, err := createServiceAccountTokenAuthenticator(options)
+	if err != nil {
+		return nil, err
+	}
 
+	mainRouter := mux.NewRouter()
 	routingParams := handler.RoutingParams{
 		Log:                                   kubermaticlog.New(options.log.Debug, options.log.Format).Sugar(),
 		PresetsProvider:                       prov.presetProvider,
@@ -409,6 +470,7 @@ func createAPIHandler(options serverRunOptions, prov providers, oidcIssuerVerifi
 		EventRecorderProvider:                 prov.eventRecorderProvider,
 		ExposeStrategy:                        options.exposeStrategy,
 		AccessibleAddons:                      options.accessibleAddons,
+		AddonCatalog:                          options.addonCatalog,
 		UserInfoGetter:                        prov.userInfoGetter,
 		SettingsProvider:                      prov.settingsProvider,
 		AdminProvider:                         prov.adminProvider,
@@ -422,6 +484,12 @@ func createAPIHandler(options serverRunOptions, prov providers, oidcIssuerVerifi
 		AlertmanagerProviderGetter:            prov.alertmanagerProviderGetter,
 		Versions:                              options.versions,
 		CABundle:                              options.caBundle.CertPool(),
+		AuditLogger:                           prov.auditLogger,
+		AuditHandler:                          mainRouter,
+		ProjectRoleProvider:                   prov.projectRoleProvider,
+		GroupProjectBindingProvider:           prov.groupProjectBindingProvider,
+		PrivilegedGroupProjectBindingProvider: prov.privilegedGroupProjectBindingProvider,
+		ClusterTemplateProvider:               prov.clusterTemplateProvider,
 	}
 
 	r := handler.NewRouting(routingParams)
@@ -429,7 +497,6 @@ func createAPIHandler(options serverRunOptions, prov providers, oidcIssuerVerifi
 
 	registerMetrics()
 
-	mainRouter := mux.NewRouter()
 	mainRouter.Use(setSecureHeaders)
 	v1Router := mainRouter.PathPrefix("/api/v1").Subrouter()
 	v2Router := mainRouter.PathPrefix("/api/v2").Subrouter()
@@ -509,7 +576,7 @@ func setSecureHeaders(next http.Handler) http.Handler {
 	})
 }
 
-func clusterProviderFactory(mapper meta.RESTMapper, seedKubeconfigGetter provider.SeedKubeconfigGetter, seedClientGetter provider.SeedClientGetter, options serverRunOptions) provider.ClusterProviderGetter {
+func clusterProviderFactory(mapper meta.RESTMapper, seedKubeconfigGetter provider.SeedKubeconfigGetter, seedClientGetter provider.SeedClientGetter, projectRoleProvider provider.ProjectRoleProvider, options serverRunOptions) provider.ClusterProviderGetter {
 	return func(seed *kubermaticv1.Seed) (provider.ClusterProvider, error) {
 		cfg, err := seedKubeconfigGetter(seed)
 		if err != nil {
@@ -536,7 +603,7 @@ func clusterProviderFactory(mapper meta.RESTMapper, seedKubeconfigGetter provide
 			defaultImpersonationClientForSeed.CreateImpersonatedClient,
 			userClusterConnectionProvider,
 			options.workerName,
-			rbac.ExtractGroupPrefix,
+			projectRoleProvider.UserClusterGroupPrefix,
 			seedCtrlruntimeClient,
 			kubeClient,
 			options.featureGates.Enabled(features.OIDCKubeCfgEndpoint),
@@ -22,6 +22,8 @@ import (
 	"fmt"
 	"strings"
 
+	addonutils "k8c.io/kubermatic/v2/pkg/addon"
+	"k8c.io/kubermatic/v2/pkg/audit"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/features"
 	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
@@ -50,6 +52,7 @@ type serverRunOptions struct {
 	namespace        string
 	log              kubermaticlog.Options
 	accessibleAddons sets.String
+	addonCatalog     addonutils.Catalog
 	caBundle         *certificates.CABundle
 
 	// OIDC configuration
@@ -64,7 +67,13 @@ type serverRunOptions struct {
 	oidcIssuerOfflineAccessAsScope bool
 
 	//service account configuration
-	serviceAccountSigningKey string
+	serviceAccountSigningKey       string
+	serviceAccountVerificationKeys string
+
+	// audit configuration
+	auditLogFile          string
+	auditWebhookURL       string
+	auditKubernetesEvents bool
 
 	featureGates features.FeatureGate
 	versions     kubermatic.Versions
@@ -75,6 +84,7 @@ func newServerRunOptions() (serverRunOptions, error) {
 	var (
 		rawExposeStrategy   string
 		rawAccessibleAddons string
+		addonsPath          string
 		caBundleFile        string
 	)
 
@@ -91,6 +101,7 @@ func newServerRunOptions() (serverRunOptions, error) {
 	flag.StringVar(&s.presetsFile, "presets", "", "The optional file path for a file containing presets")
 	flag.StringVar(&s.swaggerFile, "swagger", "./cmd/kubermatic-api/swagger.json", "The swagger.json file path")
 	flag.StringVar(&rawAccessibleAddons, "accessible-addons", "", "Comma-separated list of user cluster addons to expose via the API")
+	flag.StringVar(&addonsPath, "kubernetes-addons-path", "", "The optional path to the addon manifests, used to expose the versions and dependencies of the accessible addons")
 	flag.StringVar(&caBundleFile, "ca-bundle", "", "The path to the certificate for the CA that signed your identity provider’s web certificate.")
 	flag.StringVar(&s.oidcURL, "oidc-url", "", "URL of the OpenID token issuer. Example: http://auth.int.kubermatic.io")
 	flag.BoolVar(&s.oidcSkipTLSVerify, "oidc-skip-tls-verify", false, "Skip TLS verification for the token issuer")
@@ -103,7 +114,11 @@ func newServerRunOptions() (serverRunOptions, error) {
 	flag.BoolVar(&s.oidcIssuerOfflineAccessAsScope, "oidc-issuer-offline-access-as-scope", true, "Set it to false if OIDC provider requires to set \"access_type=offline\" query param when accessing the refresh token")
 	flag.Var(&s.featureGates, "feature-gates", "A set of key=value pairs that describe feature gates for various features.")
 	flag.StringVar(&s.domain, "domain", "localhost", "A domain name on which the server is deployed")
-	flag.StringVar(&s.serviceAccountSigningKey, "service-account-signing-key", "", "Signing key authenticates the service account's token value using HMAC. It is recommended to use a key with 32 bytes or longer.")
+	flag.StringVar(&s.serviceAccountSigningKey, "service-account-signing-key", "", "Signing key authenticates the service account's token value. A PEM encoded RSA or ECDSA private key signs the tokens with RS256 or ES256, any other value is used as HMAC key, which should be 32 bytes or longer.")
+	flag.StringVar(&s.serviceAccountVerificationKeys, "service-account-verification-keys", "", "Former service account signing keys, either a HMAC key or PEM encoded RSA and ECDSA keys. Tokens signed with these keys are accepted until they expire, so that the signing key can be rotated.")
+	flag.StringVar(&s.auditLogFile, "audit-log-file", "", "The optional path to a file the audit events of the mutating API requests are appended to as JSON lines")
+	flag.StringVar(&s.auditWebhookURL, "audit-webhook-url", "", "The optional URL the audit events of the mutating API requests are posted to as JSON")
+	flag.BoolVar(&s.auditKubernetesEvents, "audit-kubernetes-events", false, "Whether to record the audit events of the mutating API requests as Kubernetes events in the namespace kubermatic runs in, admins can only query the audit events if this is enabled")
 	flag.StringVar(&rawExposeStrategy, "expose-strategy", "NodePort", "The strategy to expose the controlplane with, either \"NodePort\" which creates NodePorts with a \"nodeport-proxy.k8s.io/expose: true\" annotation or \"LoadBalancer\", which creates a LoadBalancer")
 	flag.BoolVar(&s.dynamicPresets, "dynamic-presets", false, "Whether to enable dynamic presets")
 	flag.StringVar(&s.namespace, "namespace", "kubermatic", "The namespace kubermatic runs in, uses to determine where to look for datacenter custom resources")
@@ -119,6 +134,14 @@ func newServerRunOptions() (serverRunOptions, error) {
 	s.accessibleAddons = sets.NewString(strings.Split(rawAccessibleAddons, ",")...)
 	s.accessibleAddons.Delete("")
 
+	if addonsPath != "" {
+		catalog, err := addonutils.LoadCatalog(addonsPath)
+		if err != nil {
+			return s, fmt.Errorf("failed to load addon catalog from %q: %v", addonsPath, err)
+		}
+		s.addonCatalog = catalog
+	}
+
 	if len(caBundleFile) == 0 {
 		return s, errors.New("no -ca-bundle configured")
 	}
@@ -173,4 +196,9 @@ type providers struct {
 	constraintTemplateProvider            provider.ConstraintTemplateProvider
 	constraintProviderGetter              provider.ConstraintProviderGetter
 	alertmanagerProviderGetter            provider.AlertmanagerProviderGetter
+	auditLogger                           *audit.Logger
+	projectRoleProvider                   provider.ProjectRoleProvider
+	groupProjectBindingProvider           provider.GroupProjectBindingProvider
+	privilegedGroupProjectBindingProvider provider.PrivilegedGroupProjectBindingProvider
+	clusterTemplateProvider               provider.ClusterTemplateProvider
 }
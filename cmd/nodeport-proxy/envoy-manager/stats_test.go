@@ -0,0 +1,136 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"testing"
+
+	envoybootstrapv3 "github.com/envoyproxy/go-control-plane/envoy/config/bootstrap/v3"
+	"github.com/golang/protobuf/jsonpb"
+
+	"k8c.io/kubermatic/v2/pkg/controller/nodeport-proxy/envoymanager"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// envoyClusterStats are the stats of the Envoy clusters which the recording rules of the nodeport-proxy are based on.
+var envoyClusterStats = []string{
+	"upstream_cx_active",
+	"upstream_cx_connect_fail",
+	"upstream_cx_overflow",
+	"membership_healthy",
+	"outlier_detection.ejections_active",
+}
+
+var nonAlphanumeric = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// TestUserClusterStatsTag verifies that the series the recording rules select are exported by Envoy for the
+// clusters of the control planes: the stats are tagged as Envoy does with the regex of the bootstrap config and
+// named as on its Prometheus endpoint.
+func TestUserClusterStatsTag(t *testing.T) {
+	raw, err := ioutil.ReadFile("../envoy.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawJSON, err := yaml.YAMLToJSON(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bootstrap := &envoybootstrapv3.Bootstrap{}
+	if err := jsonpb.UnmarshalString(string(rawJSON), bootstrap); err != nil {
+		t.Fatalf("failed to unmarshal the bootstrap config: %v", err)
+	}
+	if err := bootstrap.Validate(); err != nil {
+		t.Fatalf("invalid bootstrap config: %v", err)
+	}
+
+	var tagRegex *regexp.Regexp
+	for _, tag := range bootstrap.GetStatsConfig().GetStatsTags() {
+		if tag.GetTagName() == "user_cluster" {
+			tagRegex = regexp.MustCompile(tag.GetRegex())
+		}
+	}
+	if tagRegex == nil {
+		t.Fatal("expected the bootstrap config to define the user_cluster stats tag")
+	}
+
+	// series returns the Prometheus series of the Envoy cluster stats of the given service with their user_cluster
+	// label, the first submatch of the tag regex is removed from the name and the second one is the value.
+	series := func(namespace string) map[string]string {
+		svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "apiserver-external"}}
+		clusterName := envoymanager.ServicePortKey(envoymanager.ServiceKey(svc), &corev1.ServicePort{Name: "secure"})
+
+		res := map[string]string{}
+		for _, stat := range envoyClusterStats {
+			name := "cluster." + clusterName + "." + stat
+			value := ""
+			if m := tagRegex.FindStringSubmatchIndex(name); m != nil {
+				value = name[m[4]:m[5]]
+				name = name[:m[2]] + name[m[3]:]
+			}
+			res["envoy_"+nonAlphanumeric.ReplaceAllString(name, "_")] = value
+		}
+		return res
+	}
+
+	userClusterSeries := series("cluster-xyz")
+	for name, value := range series("kube-system") {
+		if value != "" {
+			t.Errorf("expected series %s of a service outside of the user cluster namespaces to not be tagged, got %q", name, value)
+		}
+	}
+
+	raw, err = ioutil.ReadFile("../../../charts/monitoring/prometheus/rules/src/kubermatic-seed/nodeport-proxy.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := struct {
+		Groups []struct {
+			Rules []struct {
+				Record string `json:"record"`
+				Expr   string `json:"expr"`
+			} `json:"rules"`
+		} `json:"groups"`
+	}{}
+	if err := yaml.Unmarshal(raw, &rules); err != nil {
+		t.Fatal(err)
+	}
+
+	metricName := regexp.MustCompile(`envoy_[a-z_]+`)
+	for _, group := range rules.Groups {
+		for _, rule := range group.Rules {
+			if !strings.Contains(rule.Expr, `user_cluster!=""`) {
+				t.Errorf("expected rule %s to select the series of the user clusters", rule.Record)
+			}
+			for _, name := range metricName.FindAllString(rule.Expr, -1) {
+				value, ok := userClusterSeries[name]
+				if !ok {
+					t.Errorf("rule %s selects series %s which is not exported by Envoy", rule.Record, name)
+					continue
+				}
+				if value != "xyz" {
+					t.Errorf("expected series %s of rule %s to have the user_cluster label xyz, got %q", name, rule.Record, value)
+				}
+			}
+		}
+	}
+}
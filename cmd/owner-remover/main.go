@@ -21,14 +21,15 @@ import (
 	"flag"
 	"fmt"
 	"io/ioutil"
-	"os/exec"
 
 	kubermaticclientset "k8c.io/kubermatic/v2/pkg/crd/client/clientset/versioned"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog"
+	"sigs.k8s.io/yaml"
 )
 
 var (
@@ -142,10 +143,16 @@ func main() {
 			}
 		}
 
-		cmd := exec.Command("kubectl", "get", "cluster", cluster.Name, "-o", "yaml")
-		out, err := cmd.CombinedOutput()
+		currentCluster, err := kubermaticClient.KubermaticV1().Clusters().Get(ctx, cluster.Name, metav1.GetOptions{})
 		if err != nil {
-			klog.Fatal(err, string(out))
+			klog.Fatal(err)
+		}
+		// typed clients do not populate the TypeMeta
+		currentCluster.APIVersion = kubermaticv1.SchemeGroupVersion.String()
+		currentCluster.Kind = kubermaticv1.ClusterKindName
+		out, err := yaml.Marshal(currentCluster)
+		if err != nil {
+			klog.Fatal(err)
 		}
 		if err := ioutil.WriteFile(fmt.Sprintf("cluster-%s.yaml", cluster.Name), out, 0644); err != nil {
 			klog.Fatal(err)
@@ -19,14 +19,25 @@ package main
 import (
 	"crypto/x509"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"strings"
 
 	"github.com/urfave/cli"
 	"go.uber.org/zap"
 
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/log"
 	"k8c.io/kubermatic/v2/pkg/resources/certificates"
 	"k8c.io/kubermatic/v2/pkg/storeuploader"
+	"k8c.io/kubermatic/v2/pkg/util/encryption"
+	"k8c.io/kubermatic/v2/pkg/util/retention"
+)
+
+const (
+	backendS3         = string(kubermaticv1.BackupDestinationTypeS3)
+	backendFilesystem = "filesystem"
+	backendAzureBlob  = string(kubermaticv1.BackupDestinationTypeAzureBlob)
 )
 
 var logger *zap.SugaredLogger
@@ -35,9 +46,39 @@ func main() {
 	app := cli.NewApp()
 	app.Name = "S3 storer"
 	app.Usage = ""
-	app.Version = "v0.1.6"
-	app.Description = "Helper tool to backup files to S3 and maintain a given number of revisions"
+	app.Version = "v0.1.8"
+	app.Description = "Helper tool to backup files to S3, Azure Blob Storage or a local directory and maintain a given number of revisions"
 
+	backendFlag := cli.StringFlag{
+		Name:   "backend",
+		Value:  backendS3,
+		EnvVar: "BACKUP_DESTINATION_TYPE",
+		Usage:  fmt.Sprintf("Backend to store the backups in, one of [%s]", strings.Join([]string{backendS3, backendFilesystem, backendAzureBlob}, ", ")),
+	}
+	directoryFlag := cli.StringFlag{
+		Name:   "directory",
+		Value:  "",
+		EnvVar: "BACKUP_DESTINATION_PATH",
+		Usage:  "Directory in which to store the snapshots (filesystem backend only)",
+	}
+	azureAccountNameFlag := cli.StringFlag{
+		Name:   "azure-account-name",
+		Value:  "",
+		EnvVar: "AZURE_STORAGE_ACCOUNT",
+		Usage:  "Azure storage account name (azureblob backend only)",
+	}
+	azureAccountKeyFlag := cli.StringFlag{
+		Name:   "azure-account-key",
+		Value:  "",
+		EnvVar: "AZURE_STORAGE_KEY",
+		Usage:  "Azure storage account key (azureblob backend only)",
+	}
+	azureContainerFlag := cli.StringFlag{
+		Name:   "azure-container",
+		Value:  "",
+		EnvVar: "AZURE_CONTAINER_NAME",
+		Usage:  "Azure Blob Storage container in which to store the snapshots (azureblob backend only)",
+	}
 	endpointFlag := cli.StringFlag{
 		Name:  "endpoint, e",
 		Value: "",
@@ -87,6 +128,51 @@ func main() {
 		Value: 20,
 		Usage: "Maximum number of revisions of the file to keep in S3. Older ones will be deleted",
 	}
+	keepHourlyFlag := cli.IntFlag{
+		Name:  "keep-hourly",
+		Usage: "Number of hours for which to additionally keep the most recent revision",
+	}
+	keepDailyFlag := cli.IntFlag{
+		Name:  "keep-daily",
+		Usage: "Number of days for which to additionally keep the most recent revision",
+	}
+	keepWeeklyFlag := cli.IntFlag{
+		Name:  "keep-weekly",
+		Usage: "Number of weeks for which to additionally keep the most recent revision",
+	}
+	keepMonthlyFlag := cli.IntFlag{
+		Name:  "keep-monthly",
+		Usage: "Number of months for which to additionally keep the most recent revision",
+	}
+	objectNameFlag := cli.StringFlag{
+		Name:  "object-name",
+		Value: "",
+		Usage: "Name of the object in the backup store",
+	}
+	keyFileFlag := cli.StringFlag{
+		Name:  "key-file",
+		Value: "",
+		Usage: "Path to the file containing the encryption key",
+	}
+	keyIDFlag := cli.StringFlag{
+		Name:  "key-id",
+		Value: "",
+		Usage: "ID of the encryption key, stored in the encrypted file to find the key for decryption",
+	}
+
+	storeFlags := []cli.Flag{
+		backendFlag,
+		endpointFlag,
+		secureFlag,
+		caBundleFlag,
+		accessKeyIDFlag,
+		secretAccessKeyFlag,
+		bucketFlag,
+		directoryFlag,
+		azureAccountNameFlag,
+		azureAccountKeyFlag,
+		azureContainerFlag,
+	}
 
 	logDebugFlag := cli.BoolFlag{
 		Name:  "log-debug",
@@ -108,48 +194,62 @@ func main() {
 	app.Commands = []cli.Command{
 		{
 			Name:   "store",
-			Usage:  "Stores the given file on S3",
+			Usage:  "Stores the given file in the backup store",
 			Action: store,
-			Flags: []cli.Flag{
-				endpointFlag,
-				secureFlag,
-				caBundleFlag,
-				accessKeyIDFlag,
-				secretAccessKeyFlag,
-				bucketFlag,
+			Flags: append(storeFlags,
 				prefixFlag,
 				fileFlag,
 				createBucketFlag,
-			},
+			),
 		},
 		{
 			Name:   "delete-old-revisions",
-			Usage:  "Deletes backups which are older than max-revisions",
+			Usage:  "Deletes backups which are older than max-revisions and not kept by any of the keep-* flags",
 			Action: deleteOldRevisions,
-			Flags: []cli.Flag{
-				endpointFlag,
-				secureFlag,
-				caBundleFlag,
-				accessKeyIDFlag,
-				secretAccessKeyFlag,
-				bucketFlag,
+			Flags: append(storeFlags,
 				prefixFlag,
 				maxRevisionsFlag,
+				keepHourlyFlag,
+				keepDailyFlag,
+				keepWeeklyFlag,
+				keepMonthlyFlag,
 				fileFlag, // unused but kept for BC compatibility with old cleanup scripts
-			},
+			),
 		},
 		{
 			Name:   "delete-all",
 			Usage:  "deletes all backups of the filename",
 			Action: deleteAll,
-			Flags: []cli.Flag{
-				endpointFlag,
-				secureFlag,
-				caBundleFlag,
-				accessKeyIDFlag,
-				secretAccessKeyFlag,
-				bucketFlag,
+			Flags: append(storeFlags,
 				prefixFlag,
+			),
+		},
+		{
+			Name:   "upload",
+			Usage:  "Uploads the given file as an object with the given name, overwriting an existing object",
+			Action: upload,
+			Flags: append(storeFlags,
+				fileFlag,
+				objectNameFlag,
+				createBucketFlag,
+			),
+		},
+		{
+			Name:   "delete",
+			Usage:  "Deletes the object with the given name, deleting an object that does not exist is not an error",
+			Action: deleteObject,
+			Flags: append(storeFlags,
+				objectNameFlag,
+			),
+		},
+		{
+			Name:   "encrypt",
+			Usage:  "Encrypts the given file in place",
+			Action: encrypt,
+			Flags: []cli.Flag{
+				fileFlag,
+				keyFileFlag,
+				keyIDFlag,
 			},
 		},
 	}
@@ -180,31 +280,52 @@ func main() {
 	}
 }
 
-func getUploaderFromCtx(c *cli.Context) (*storeuploader.StoreUploader, error) {
-	var rootCAs *x509.CertPool
+func getStoreFromCtx(c *cli.Context) (storeuploader.BackupStore, error) {
+	switch backend := c.String("backend"); backend {
+	case backendS3:
+		var rootCAs *x509.CertPool
+
+		if caBundleFile := c.String("ca-bundle"); caBundleFile != "" {
+			bundle, err := certificates.NewCABundleFromFile(caBundleFile)
+			if err != nil {
+				return nil, fmt.Errorf("cannot open CA bundle: %v", err)
+			}
 
-	if caBundleFile := c.String("ca-bundle"); caBundleFile != "" {
-		bundle, err := certificates.NewCABundleFromFile(caBundleFile)
-		if err != nil {
-			return nil, fmt.Errorf("cannot open CA bundle: %v", err)
+			rootCAs = bundle.CertPool()
 		}
 
-		rootCAs = bundle.CertPool()
+		return storeuploader.NewS3Store(
+			c.String("endpoint"),
+			c.Bool("secure"),
+			c.String("access-key-id"),
+			c.String("secret-access-key"),
+			c.String("bucket"),
+			c.Bool("create-bucket"),
+			rootCAs,
+		)
+
+	case backendFilesystem:
+		return storeuploader.NewFilesystemStore(c.String("directory"))
+
+	case backendAzureBlob:
+		return storeuploader.NewAzureBlobStore(
+			c.String("azure-account-name"),
+			c.String("azure-account-key"),
+			c.String("azure-container"),
+		)
+
+	default:
+		return nil, fmt.Errorf("unknown backend %q", backend)
 	}
+}
 
-	uploader, err := storeuploader.New(
-		c.String("endpoint"),
-		c.Bool("secure"),
-		c.String("access-key-id"),
-		c.String("secret-access-key"),
-		logger,
-		rootCAs,
-	)
+func getUploaderFromCtx(c *cli.Context) (*storeuploader.StoreUploader, error) {
+	store, err := getStoreFromCtx(c)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create store uploader: %v", err)
+		return nil, fmt.Errorf("failed to create backup store: %v", err)
 	}
 
-	return uploader, nil
+	return storeuploader.New(store, logger), nil
 }
 
 func store(c *cli.Context) error {
@@ -215,9 +336,7 @@ func store(c *cli.Context) error {
 
 	return uploader.Store(
 		c.String("file"),
-		c.String("bucket"),
 		c.String("prefix"),
-		c.Bool("create-bucket"),
 	)
 }
 
@@ -228,9 +347,14 @@ func deleteOldRevisions(c *cli.Context) error {
 	}
 
 	return uploader.DeleteOldBackups(
-		c.String("bucket"),
 		c.String("prefix"),
-		c.Int("max-revisions"),
+		retention.Policy{
+			Latest:  c.Int("max-revisions"),
+			Hourly:  c.Int("keep-hourly"),
+			Daily:   c.Int("keep-daily"),
+			Weekly:  c.Int("keep-weekly"),
+			Monthly: c.Int("keep-monthly"),
+		},
 	)
 }
 
@@ -241,7 +365,55 @@ func deleteAll(c *cli.Context) error {
 	}
 
 	return uploader.DeleteAll(
-		c.String("bucket"),
 		c.String("prefix"),
 	)
 }
+
+func upload(c *cli.Context) error {
+	objectName := c.String("object-name")
+	if objectName == "" {
+		return fmt.Errorf("--object-name must be set")
+	}
+
+	store, err := getStoreFromCtx(c)
+	if err != nil {
+		return fmt.Errorf("failed to create backup store: %v", err)
+	}
+
+	logger.Infow("Uploading file", "src", c.String("file"), "dst", objectName)
+	return store.Upload(c.String("file"), objectName)
+}
+
+func deleteObject(c *cli.Context) error {
+	objectName := c.String("object-name")
+	if objectName == "" {
+		return fmt.Errorf("--object-name must be set")
+	}
+
+	store, err := getStoreFromCtx(c)
+	if err != nil {
+		return fmt.Errorf("failed to create backup store: %v", err)
+	}
+
+	logger.Infow("Removing object", "object", objectName)
+	return store.Delete(objectName)
+}
+
+func encrypt(c *cli.Context) error {
+	keyID := c.String("key-id")
+	if keyID == "" {
+		return fmt.Errorf("--key-id must be set")
+	}
+
+	key, err := ioutil.ReadFile(c.String("key-file"))
+	if err != nil {
+		return fmt.Errorf("failed to read key: %v", err)
+	}
+
+	if err := encryption.EncryptFile(c.String("file"), keyID, key); err != nil {
+		return fmt.Errorf("failed to encrypt %s: %v", c.String("file"), err)
+	}
+
+	logger.Infow("Encrypted file", "file", c.String("file"), "key-id", keyID)
+	return nil
+}
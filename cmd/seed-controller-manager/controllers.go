@@ -22,6 +22,7 @@ import (
 	"io/ioutil"
 	"time"
 
+	addonutils "k8c.io/kubermatic/v2/pkg/addon"
 	"k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/addon"
 	"k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/addoninstaller"
 	backupcontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/backup"
@@ -30,6 +31,7 @@ import (
 	constrainttemplatecontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/constraint-template-controller"
 	etcdbackupcontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/etcdbackup"
 	etcdrestorecontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/etcdrestore"
+	"k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/hibernation"
 	"k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/initialmachinedeployment"
 	kubernetescontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/kubernetes"
 	"k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/mla"
@@ -67,6 +69,7 @@ var AllControllers = map[string]controllerCreator{
 	constrainttemplatecontroller.ControllerName:   createConstraintTemplateController,
 	initialmachinedeployment.ControllerName:       createInitialMachineDeploymentController,
 	mla.ControllerName:                            createMLAController,
+	hibernation.ControllerName:                    createHibernationController,
 }
 
 type controllerCreator func(*controllerContext) error
@@ -168,6 +171,7 @@ func createKubernetesController(ctrlCtx *controllerContext) error {
 		ctrlCtx.runOptions.kubermaticImage,
 		ctrlCtx.runOptions.etcdLauncherImage,
 		ctrlCtx.runOptions.dnatControllerImage,
+		ctrlCtx.runOptions.dnatControllerBackend,
 		ctrlCtx.runOptions.tunnelingAgentIP.String(),
 		ctrlCtx.runOptions.caBundle,
 		kubernetescontroller.Features{
@@ -211,6 +215,7 @@ func createEtcdBackupController(ctrlCtx *controllerContext) error {
 		deleteContainer,
 		cleanupContainer,
 		ctrlCtx.runOptions.backupContainerImage,
+		ctrlCtx.runOptions.backupEncryptionImage,
 		ctrlCtx.versions,
 		ctrlCtx.runOptions.caBundle,
 	)
@@ -331,6 +336,18 @@ func createUpdateController(ctrlCtx *controllerContext) error {
 	)
 }
 
+func createHibernationController(ctrlCtx *controllerContext) error {
+	return hibernation.Add(
+		ctrlCtx.mgr,
+		ctrlCtx.log,
+		ctrlCtx.runOptions.workerCount,
+		ctrlCtx.runOptions.workerName,
+		ctrlCtx.clientProvider,
+		ctrlCtx.runOptions.enableEtcdBackupRestoreController,
+		ctrlCtx.versions,
+	)
+}
+
 func createAddonController(ctrlCtx *controllerContext) error {
 	return addon.Add(
 		ctrlCtx.mgr,
@@ -352,12 +369,18 @@ func createAddonController(ctrlCtx *controllerContext) error {
 }
 
 func createAddonInstallerController(ctrlCtx *controllerContext) error {
+	catalog, err := addonutils.LoadCatalog(ctrlCtx.runOptions.kubernetesAddonsPath)
+	if err != nil {
+		return fmt.Errorf("failed to load addon catalog: %v", err)
+	}
+
 	return addoninstaller.Add(
 		ctrlCtx.log,
 		ctrlCtx.mgr,
 		ctrlCtx.runOptions.workerCount,
 		ctrlCtx.runOptions.workerName,
 		ctrlCtx.runOptions.kubernetesAddons,
+		catalog,
 		ctrlCtx.versions,
 	)
 }
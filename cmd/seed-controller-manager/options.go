@@ -30,8 +30,10 @@ import (
 	"go.uber.org/zap"
 
 	"k8c.io/kubermatic/v2/pkg/cluster/client"
+	"k8c.io/kubermatic/v2/pkg/controller/kubeletdnat"
 	"k8c.io/kubermatic/v2/pkg/controller/operator/common"
 	backupcontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/backup"
+	etcdbackupcontroller "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/etcdbackup"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/features"
 	"k8c.io/kubermatic/v2/pkg/provider"
@@ -68,6 +70,7 @@ type controllerRunOptions struct {
 	backupDeleteContainerFile                        string
 	cleanupContainerFile                             string
 	backupContainerImage                             string
+	backupEncryptionImage                            string
 	backupInterval                                   string
 	etcdDiskSize                                     resource.Quantity
 	inClusterPrometheusRulesFile                     string
@@ -80,6 +83,7 @@ type controllerRunOptions struct {
 	etcdLauncherImage                                string
 	enableEtcdBackupRestoreController                bool
 	dnatControllerImage                              string
+	dnatControllerBackend                            string
 	namespace                                        string
 	apiServerDefaultReplicas                         int
 	apiServerEndpointReconcilingDisabled             bool
@@ -143,6 +147,7 @@ func newControllerRunOptions() (controllerRunOptions, error) {
 	flag.StringVar(&c.backupDeleteContainerFile, "backup-delete-container", "", "Filepath of a backup deletion container yaml. It receives the name of the backup to delete in an env variable ($BACKUP_TO_DELETE). If not specified, the backup container must handle deletion.")
 	flag.StringVar(&c.cleanupContainerFile, "cleanup-container", "", "(Only required for the old backup controller) Filepath of a cleanup container yaml. The container will be used to cleanup the backup directory for a cluster after it got deleted.")
 	flag.StringVar(&c.backupContainerImage, "backup-container-init-image", backupcontroller.DefaultBackupContainerImage, "Docker image to use for the init container in the backup job, must be an etcd v3 image. Only set this if your cluster can not use the public quay.io registry")
+	flag.StringVar(&c.backupEncryptionImage, "backup-encryption-image", etcdbackupcontroller.DefaultBackupEncryptionImage, "Docker image to use for encrypting backups of EtcdBackupConfigs with encryption enabled, must contain the s3-storeuploader binary. Only set this if your cluster can not use the public quay.io registry")
 	flag.StringVar(&c.backupInterval, "backup-interval", backupcontroller.DefaultBackupInterval, "Interval in which the etcd gets backed up")
 	flag.StringVar(&rawEtcdDiskSize, "etcd-disk-size", "5Gi", "Size for the etcd PV's. Only applies to new clusters.")
 	flag.StringVar(&c.inClusterPrometheusRulesFile, "in-cluster-prometheus-rules-file", "", "The file containing the custom alerting rules for the prometheus running in the cluster-foo namespaces.")
@@ -159,6 +164,7 @@ func newControllerRunOptions() (controllerRunOptions, error) {
 	flag.StringVar(&c.etcdLauncherImage, "etcd-launcher-image", resources.DefaultEtcdLauncherImage, "The location from which to pull the etcd launcher image")
 	flag.BoolVar(&c.enableEtcdBackupRestoreController, "enable-etcd-backups-restores", false, "Whether to enable the new etcd backup and restore controllers")
 	flag.StringVar(&c.dnatControllerImage, "dnatcontroller-image", resources.DefaultDNATControllerImage, "The location of the dnatcontroller-image")
+	flag.StringVar(&c.dnatControllerBackend, "dnatcontroller-backend", string(kubeletdnat.BackendIPTables), "The backend the dnatcontroller uses to translate the node addresses, either iptables or nftables")
 	flag.StringVar(&c.namespace, "namespace", "kubermatic", "The namespace kubermatic runs in, uses to determine where to look for datacenter custom resources")
 	flag.IntVar(&c.apiServerDefaultReplicas, "apiserver-default-replicas", 2, "The default number of replicas for usercluster api servers")
 	flag.BoolVar(&c.apiServerEndpointReconcilingDisabled, "apiserver-reconciling-disabled-by-default", false, "Whether to disable reconciling for the apiserver endpoints by default")
@@ -233,6 +239,10 @@ func (o controllerRunOptions) validate() error {
 		return fmt.Errorf("datacenter-name is undefined")
 	}
 
+	if backend := kubeletdnat.Backend(o.dnatControllerBackend); backend != kubeletdnat.BackendIPTables && backend != kubeletdnat.BackendNFTables {
+		return fmt.Errorf("dnatcontroller-backend must be either %q or %q", kubeletdnat.BackendIPTables, kubeletdnat.BackendNFTables)
+	}
+
 	if o.backupContainerFile == "" {
 		return fmt.Errorf("backup-container is undefined")
 	}
@@ -0,0 +1,94 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+
+	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+	"k8c.io/kubermatic/v2/pkg/tunneling"
+	"k8c.io/kubermatic/v2/pkg/util/cli"
+
+	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
+)
+
+func main() {
+	logOpts := kubermaticlog.NewDefaultOptions()
+	logOpts.AddFlags(flag.CommandLine)
+
+	opts := tunneling.AgentOptions{}
+	var addresses, tokenFile, metricsListenAddress string
+	flag.StringVar(&opts.ProxyAddress, "proxy-address", "", "The host:port of the HTTP CONNECT listener of the nodeport-proxy.")
+	flag.StringVar(&opts.Authority, "authority", "", "The host:port of the tunneling server requested to the nodeport-proxy.")
+	flag.StringVar(&opts.NodeName, "node-name", "", "The name of the node the agent is running on.")
+	flag.StringVar(&addresses, "node-addresses", "", "Comma separated list of the addresses of the node.")
+	flag.StringVar(&tokenFile, "token-file", "", "The file containing the token shared with the tunneling server.")
+	flag.IntVar(&opts.Tunnels, "tunnels", 4, "The number of idle tunnels kept open to the tunneling server.")
+	flag.DurationVar(&opts.DialTimeout, "dial-timeout", 10*time.Second, "The timeout for opening a tunnel or dialing a target.")
+	flag.DurationVar(&opts.IdleTimeout, "idle-timeout", 90*time.Second, "The time after which an idle tunnel without pings is reconnected.")
+	flag.DurationVar(&opts.MinBackoff, "min-backoff", time.Second, "The initial backoff between failed attempts to open a tunnel.")
+	flag.DurationVar(&opts.MaxBackoff, "max-backoff", 30*time.Second, "The maximum backoff between failed attempts to open a tunnel.")
+	flag.StringVar(&metricsListenAddress, "metrics-listen-address", "127.0.0.1:9904", "The address on which the metrics and health endpoints are served.")
+	flag.Parse()
+
+	rawLog := kubermaticlog.New(logOpts.Debug, logOpts.Format)
+	log := rawLog.Sugar()
+
+	cli.Hello(log, "Tunneling Agent", logOpts.Debug, nil)
+
+	if opts.ProxyAddress == "" || opts.Authority == "" || opts.NodeName == "" {
+		log.Fatal("-proxy-address, -authority and -node-name are required")
+	}
+	if opts.Tunnels < 1 {
+		log.Fatal("-tunnels must be at least 1")
+	}
+	for _, address := range strings.Split(addresses, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			opts.Addresses = append(opts.Addresses, address)
+		}
+	}
+
+	token, err := ioutil.ReadFile(tokenFile)
+	if err != nil {
+		log.Fatalw("Failed to read token", zap.Error(err))
+	}
+	opts.Token = strings.TrimSpace(string(token))
+
+	metrics := tunneling.NewAgentMetrics()
+	metrics.MustRegister(prometheus.DefaultRegisterer)
+
+	agent := tunneling.NewAgent(opts, log, metrics)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.Handle("/healthz", agent.HealthzHandler())
+	go func() {
+		if err := http.ListenAndServe(metricsListenAddress, mux); err != nil {
+			log.Fatalw("Failed to serve metrics", zap.Error(err))
+		}
+	}()
+
+	agent.Run(signals.SetupSignalHandler())
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+
+	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+	"k8c.io/kubermatic/v2/pkg/tunneling"
+	"k8c.io/kubermatic/v2/pkg/util/cli"
+)
+
+func main() {
+	logOpts := kubermaticlog.NewDefaultOptions()
+	logOpts.AddFlags(flag.CommandLine)
+
+	opts := tunneling.ServerOptions{}
+	var agentListenAddress, egressListenAddress, metricsListenAddress, tokenFile string
+	var tlsCertFile, tlsKeyFile, clientCAFile string
+	flag.StringVar(&agentListenAddress, "agent-listen-address", "0.0.0.0:8132", "The address on which the tunnels of the agents are accepted.")
+	flag.StringVar(&egressListenAddress, "egress-listen-address", "0.0.0.0:8131", "The address on which the HTTP CONNECT proxy is served.")
+	flag.StringVar(&metricsListenAddress, "metrics-listen-address", "0.0.0.0:8085", "The address on which the metrics and health endpoints are served.")
+	flag.StringVar(&tokenFile, "token-file", "", "The file containing the token shared with the tunneling agents.")
+	flag.DurationVar(&opts.HandshakeTimeout, "handshake-timeout", 10*time.Second, "The timeout for the registration of a tunnel.")
+	flag.DurationVar(&opts.PingInterval, "ping-interval", 30*time.Second, "The interval between the pings sent on idle tunnels.")
+	flag.DurationVar(&opts.DialTimeout, "dial-timeout", 10*time.Second, "The timeout for the agents to dial a target.")
+	flag.StringVar(&tlsCertFile, "tls-cert-file", "", "The serving certificate of the HTTP CONNECT proxy, it is served without TLS if empty.")
+	flag.StringVar(&tlsKeyFile, "tls-key-file", "", "The key of the serving certificate of the HTTP CONNECT proxy.")
+	flag.StringVar(&clientCAFile, "client-ca-file", "", "The CA verifying the client certificates of the HTTP CONNECT proxy, required with -tls-cert-file.")
+	flag.StringVar(&opts.ClientCommonName, "client-common-name", "", "The common name of the only client certificate accepted by the HTTP CONNECT proxy.")
+	flag.Parse()
+
+	rawLog := kubermaticlog.New(logOpts.Debug, logOpts.Format)
+	log := rawLog.Sugar()
+
+	cli.Hello(log, "Tunneling Server", logOpts.Debug, nil)
+
+	token, err := ioutil.ReadFile(tokenFile)
+	if err != nil {
+		log.Fatalw("Failed to read token", zap.Error(err))
+	}
+	opts.Token = strings.TrimSpace(string(token))
+	if opts.Token == "" {
+		log.Fatal("The token must not be empty")
+	}
+
+	metrics := tunneling.NewServerMetrics()
+	metrics.MustRegister(prometheus.DefaultRegisterer)
+
+	server := tunneling.NewServer(opts, log, metrics)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	go func() {
+		if err := http.ListenAndServe(metricsListenAddress, mux); err != nil {
+			log.Fatalw("Failed to serve metrics", zap.Error(err))
+		}
+	}()
+
+	agentListener, err := net.Listen("tcp", agentListenAddress)
+	if err != nil {
+		log.Fatalw("Failed to listen for agents", zap.Error(err))
+	}
+	go func() {
+		if err := server.ServeAgents(agentListener); err != nil {
+			log.Fatalw("Failed to serve agents", zap.Error(err))
+		}
+	}()
+
+	egressServer := &http.Server{
+		Addr:    egressListenAddress,
+		Handler: server,
+		// The connections are hijacked, which is not supported by HTTP/2.
+		TLSNextProto: map[string]func(*http.Server, *tls.Conn, http.Handler){},
+	}
+	if tlsCertFile == "" {
+		if opts.ClientCommonName != "" {
+			log.Fatal("The -client-common-name flag requires -tls-cert-file")
+		}
+		err = egressServer.ListenAndServe()
+	} else {
+		clientCA, readErr := ioutil.ReadFile(clientCAFile)
+		if readErr != nil {
+			log.Fatalw("Failed to read client CA", zap.Error(readErr))
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(clientCA) {
+			log.Fatal("The client CA does not contain any certificate")
+		}
+		egressServer.TLSConfig = &tls.Config{
+			ClientAuth: tls.RequireAndVerifyClientCert,
+			ClientCAs:  clientCAs,
+			MinVersion: tls.VersionTLS12,
+		}
+		err = egressServer.ListenAndServeTLS(tlsCertFile, tlsKeyFile)
+	}
+	if err != nil {
+		log.Fatalw("Failed to serve egress proxy", zap.Error(err))
+	}
+}
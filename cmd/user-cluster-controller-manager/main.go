@@ -33,6 +33,7 @@ import (
 	nodelabeler "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/node-labeler"
 	ownerbindingcreator "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/owner-binding-creator"
 	rbacusercluster "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/rbac"
+	resourceusage "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resource-usage"
 	usercluster "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resources"
 	machinecontrolerresources "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resources/resources/machine-controller"
 	rolecloner "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/role-cloner"
@@ -44,6 +45,7 @@ import (
 	"k8c.io/kubermatic/v2/pkg/util/cli"
 	"k8c.io/kubermatic/v2/pkg/util/flagopts"
 	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
+	"k8c.io/kubermatic/v2/pkg/webhook"
 
 	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -82,6 +84,7 @@ type controllerRunOptions struct {
 	mlaGatewayURL         string
 	userClusterLogging    bool
 	userClusterMonitoring bool
+	admissionWebhook      webhook.Options
 }
 
 func main() {
@@ -91,6 +94,7 @@ func main() {
 	pprofOpts.AddFlags(flag.CommandLine)
 	logOpts := kubermaticlog.NewDefaultOptions()
 	logOpts.AddFlags(flag.CommandLine)
+	runOp.admissionWebhook.AddFlags(flag.CommandLine, false)
 
 	flag.StringVar(&runOp.metricsListenAddr, "metrics-listen-address", "127.0.0.1:8085", "The address on which the internal HTTP /metrics server is running on")
 	flag.StringVar(&runOp.healthListenAddr, "health-listen-address", "127.0.0.1:8086", "The address on which the internal HTTP /ready & /live server is running on")
@@ -146,6 +150,9 @@ func main() {
 	if len(runOp.caBundleFile) == 0 {
 		log.Fatal("-ca-bundle must be set")
 	}
+	if err := runOp.admissionWebhook.Validate(); err != nil {
+		log.Fatalw("invalid admission webhook configuration", zap.Error(err))
+	}
 	if runOp.userClusterLogging || runOp.userClusterMonitoring {
 		if runOp.mlaGatewayURL == "" {
 			log.Fatal("-mla-gateway-url must be set when enabling user cluster logging or monitoring")
@@ -247,29 +254,31 @@ func main() {
 	}
 	log.Info("Registered usercluster controller")
 
-	if len(runOp.networks) > 0 {
-		if err := clusterv1alpha1.AddToScheme(mgr.GetScheme()); err != nil {
-			log.Fatalw("Failed to add clusterv1alpha1 scheme", zap.Error(err))
-		}
-		// We need to add the machine CRDs once here, because otherwise the IPAM
-		// controller keeps the manager from starting as it can not establish a
-		// watch for machine CRs, keeping us from creating them
-		creators := []reconciling.NamedCustomResourceDefinitionCreatorGetter{
-			machinecontrolerresources.MachineCRDCreator(),
-		}
-		if err := reconciling.ReconcileCustomResourceDefinitions(rootCtx, creators, "", mgr.GetClient()); err != nil {
-			// The mgr.Client is uninitianlized here and hence always returns a 404, regardless of the object existing or not
-			if !strings.Contains(err.Error(), `customresourcedefinitions.apiextensions.k8s.io "machines.cluster.k8s.io" already exists`) {
-				log.Fatalw("Failed to initially create the Machine CR", zap.Error(err))
-			}
+	if err := clusterv1alpha1.AddToScheme(mgr.GetScheme()); err != nil {
+		log.Fatalw("Failed to add clusterv1alpha1 scheme", zap.Error(err))
+	}
+	// We need to add the machine CRDs once here, because otherwise the IPAM
+	// and resource usage controllers keep the manager from starting as they can
+	// not establish a watch for machine CRs, keeping us from creating them
+	creators := []reconciling.NamedCustomResourceDefinitionCreatorGetter{
+		machinecontrolerresources.MachineCRDCreator(),
+		machinecontrolerresources.MachineDeploymentCRDCreator(),
+	}
+	if err := reconciling.ReconcileCustomResourceDefinitions(rootCtx, creators, "", mgr.GetClient()); err != nil {
+		// The mgr.Client is uninitianlized here and hence always returns a 404, regardless of the object existing or not
+		if !strings.Contains(err.Error(), "already exists") {
+			log.Fatalw("Failed to initially create the Machine CRs", zap.Error(err))
 		}
+	}
+
+	if len(runOp.networks) > 0 {
 		if err := ipam.Add(mgr, runOp.networks, log); err != nil {
 			log.Fatalw("Failed to add IPAM controller to mgr", zap.Error(err))
 		}
 		log.Infof("Added IPAM controller to mgr")
 	}
 
-	if err := rbacusercluster.Add(mgr, mgr.AddReadyzCheck); err != nil {
+	if err := rbacusercluster.Add(mgr, seedMgr, strings.TrimPrefix(runOp.namespace, "cluster-"), mgr.AddReadyzCheck); err != nil {
 		log.Fatalw("Failed to add user RBAC controller to mgr", zap.Error(err))
 	}
 	log.Info("Registered user RBAC controller")
@@ -302,6 +311,19 @@ func main() {
 	}
 	log.Info("Registered ownerbindingcreator controller")
 
+	if err := resourceusage.Add(rootCtx, log, seedMgr, mgr, strings.TrimPrefix(runOp.namespace, "cluster-")); err != nil {
+		log.Fatalw("Failed to register resourceusage controller", zap.Error(err))
+	}
+	log.Info("Registered resourceusage controller")
+
+	if runOp.admissionWebhook.Configured() {
+		if err := runOp.admissionWebhook.Configure(mgr.GetWebhookServer()); err != nil {
+			log.Fatalw("Failed to configure admission webhook server", zap.Error(err))
+		}
+		resourceusage.NewMachineDeploymentValidator(seedMgr.GetClient(), mgr.GetClient(), strings.TrimPrefix(runOp.namespace, "cluster-")).SetupWebhookWithManager(mgr)
+		log.Info("Registered machine deployment resource quota webhook")
+	}
+
 	if runOp.opaIntegration {
 		if err := constraintsyncer.Add(rootCtx, log, seedMgr, mgr, runOp.namespace); err != nil {
 			log.Fatalw("Failed to register constraintsyncer controller", zap.Error(err))
@@ -40,6 +40,8 @@ import (
 func main() {
 	logOpts := kubermaticlog.NewDefaultOptions()
 	logOpts.AddFlags(flag.CommandLine)
+	nodeName := flag.String("node-name", "", "The name of the node the agent is running on, used to record the changes of the authorized keys as events.")
+	sshdConfigDir := flag.String("sshd-config-dir", "", "The directory of the sshd config, required to make sshd trust the ssh certificate authority of the project.")
 	flag.Parse()
 
 	rawLog := kubermaticlog.New(logOpts.Debug, logOpts.Format)
@@ -64,7 +66,7 @@ func main() {
 	if err != nil {
 		log.Fatalw("Failed to get users directories", zap.Error(err))
 	}
-	if err := usersshkeys.Add(mgr, log, paths); err != nil {
+	if err := usersshkeys.Add(mgr, log, paths, *nodeName, *sshdConfigDir); err != nil {
 		log.Fatalw("Failed registering user ssh key controller", zap.Error(err))
 	}
 
@@ -21,6 +21,7 @@
 ### * quay.io/kubermatic/nodeport-proxy
 ### * quay.io/kubermatic/kubeletdnat-controller
 ### * quay.io/kubermatic/user-ssh-keys-agent
+### * quay.io/kubermatic/tunneling-agent
 ### * quay.io/kubermatic/etcd-launcher
 ###
 ### The images are tagged with all arguments given to the script, i.e
@@ -60,6 +61,7 @@ make docker-build TAGS="${PRIMARY_TAG}"
 make -C cmd/nodeport-proxy docker TAG="${PRIMARY_TAG}"
 make -C cmd/kubeletdnat-controller docker TAG="${PRIMARY_TAG}"
 make -C cmd/user-ssh-keys-agent docker TAG="${PRIMARY_TAG}"
+make -C cmd/tunneling-agent docker TAG="${PRIMARY_TAG}"
 docker build -t "${DOCKER_REPO}/addons:${PRIMARY_TAG}" addons
 docker build -t "${DOCKER_REPO}/etcd-launcher:${PRIMARY_TAG}" -f cmd/etcd-launcher/Dockerfile .
 
@@ -80,6 +82,7 @@ for TAG in "$@"; do
   docker tag "${DOCKER_REPO}/kubeletdnat-controller:${PRIMARY_TAG}" "${DOCKER_REPO}/kubeletdnat-controller:${TAG}"
   docker tag "${DOCKER_REPO}/addons:${PRIMARY_TAG}" "${DOCKER_REPO}/addons:${TAG}"
   docker tag "${DOCKER_REPO}/user-ssh-keys-agent:${PRIMARY_TAG}" "${DOCKER_REPO}/user-ssh-keys-agent:${TAG}"
+  docker tag "${DOCKER_REPO}/tunneling-agent:${PRIMARY_TAG}" "${DOCKER_REPO}/tunneling-agent:${TAG}"
   docker tag "${DOCKER_REPO}/etcd-launcher:${PRIMARY_TAG}" "${DOCKER_REPO}/etcd-launcher:${TAG}"
 
   echodate "Pushing images"
@@ -88,6 +91,7 @@ for TAG in "$@"; do
   docker push "${DOCKER_REPO}/kubeletdnat-controller:${TAG}"
   docker push "${DOCKER_REPO}/addons:${TAG}"
   docker push "${DOCKER_REPO}/user-ssh-keys-agent:${TAG}"
+  docker push "${DOCKER_REPO}/tunneling-agent:${TAG}"
   docker push "${DOCKER_REPO}/etcd-launcher:${TAG}"
 
   if [ "$KUBERMATIC_EDITION" == "ee" ]; then
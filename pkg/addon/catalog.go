@@ -0,0 +1,205 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+
+	"sigs.k8s.io/yaml"
+)
+
+// MetadataFilename is the name of the file in an addon folder which describes the addon.
+// It is not part of the addon manifests.
+const MetadataFilename = "addon.yaml"
+
+// Metadata describes an addon of the catalog. All fields are optional, addons
+// without a metadata file are unversioned and have no dependencies.
+type Metadata struct {
+	// Name is the name of the addon, which is the name of its folder
+	Name string `json:"-"`
+	// Version is the version of the addon manifests
+	Version string `json:"version,omitempty"`
+	// Dependencies are the names of addons which must be installed and ready before this addon is installed
+	Dependencies []string `json:"dependencies,omitempty"`
+	// KubernetesVersions is a semver constraint of the Kubernetes versions the addon supports, e.g. ">= 1.18, < 1.22"
+	KubernetesVersions string `json:"kubernetesVersions,omitempty"`
+	// CNIPlugin is the type of the CNI plugin the addon installs. Such an addon must be named after the type
+	// and is only installed into clusters using the plugin. Its manifests render the version of the cluster.
+	CNIPlugin kubermaticv1.CNIPluginType `json:"cniPlugin,omitempty"`
+	// RequiresCNIPlugin makes the CNI plugin addon of the cluster a dependency of the addon
+	RequiresCNIPlugin bool `json:"requiresCNIPlugin,omitempty"`
+}
+
+// SupportsCNIPlugin returns whether the addon can be installed into clusters using the given CNI plugin.
+func (m *Metadata) SupportsCNIPlugin(plugin kubermaticv1.CNIPluginType) bool {
+	return m.CNIPlugin == "" || m.CNIPlugin == plugin
+}
+
+// ClusterDependencies returns the dependencies of the addon in clusters using the given CNI plugin. Clusters
+// without a CNI plugin addon have to bring their own, so there is nothing to wait for.
+func (m *Metadata) ClusterDependencies(plugin kubermaticv1.CNIPluginType) []string {
+	if !m.RequiresCNIPlugin || plugin == kubermaticv1.CNIPluginTypeNone {
+		return m.Dependencies
+	}
+	return append(append([]string{}, m.Dependencies...), string(plugin))
+}
+
+// SupportsKubernetesVersion returns whether the addon can be installed into clusters of the given version.
+func (m *Metadata) SupportsKubernetesVersion(version *semver.Version) (bool, error) {
+	if m.KubernetesVersions == "" {
+		return true, nil
+	}
+	if version == nil {
+		return false, fmt.Errorf("unknown Kubernetes version")
+	}
+
+	constraint, err := semver.NewConstraint(m.KubernetesVersions)
+	if err != nil {
+		return false, fmt.Errorf("invalid kubernetesVersions %q of addon %s: %v", m.KubernetesVersions, m.Name, err)
+	}
+	return constraint.Check(version), nil
+}
+
+// LoadMetadata loads the metadata of the addon in the given folder.
+func LoadMetadata(addonPath string) (*Metadata, error) {
+	metadata := &Metadata{Name: path.Base(addonPath)}
+
+	content, err := ioutil.ReadFile(path.Join(addonPath, MetadataFilename))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return metadata, nil
+		}
+		return nil, err
+	}
+	if err := yaml.UnmarshalStrict(content, metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse %s of addon %s: %v", MetadataFilename, metadata.Name, err)
+	}
+
+	if metadata.Version != "" {
+		if _, err := semver.NewVersion(metadata.Version); err != nil {
+			return nil, fmt.Errorf("invalid version %q of addon %s: %v", metadata.Version, metadata.Name, err)
+		}
+	}
+	if metadata.KubernetesVersions != "" {
+		if _, err := semver.NewConstraint(metadata.KubernetesVersions); err != nil {
+			return nil, fmt.Errorf("invalid kubernetesVersions %q of addon %s: %v", metadata.KubernetesVersions, metadata.Name, err)
+		}
+	}
+	if metadata.CNIPlugin != "" {
+		if string(metadata.CNIPlugin) != metadata.Name {
+			return nil, fmt.Errorf("addon %s installs the CNI plugin %s and must be named after it", metadata.Name, metadata.CNIPlugin)
+		}
+		if metadata.RequiresCNIPlugin {
+			return nil, fmt.Errorf("addon %s installs the CNI plugin %s and can not require one", metadata.Name, metadata.CNIPlugin)
+		}
+	}
+
+	return metadata, nil
+}
+
+// Catalog contains the metadata of all available addons, by name.
+type Catalog map[string]*Metadata
+
+// LoadCatalog loads the metadata of all addons in the given folder, which contains one folder per addon.
+func LoadCatalog(addonsPath string) (Catalog, error) {
+	infos, err := ioutil.ReadDir(addonsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := Catalog{}
+	for _, info := range infos {
+		if !info.IsDir() {
+			continue
+		}
+		metadata, err := LoadMetadata(path.Join(addonsPath, info.Name()))
+		if err != nil {
+			return nil, err
+		}
+		catalog[metadata.Name] = metadata
+	}
+
+	return catalog, nil
+}
+
+// Resolve returns the given addons together with all of their dependencies, ordered so that
+// every addon comes after its dependencies. The order is otherwise stable, so addons without
+// dependencies keep the order in which they were given.
+func (c Catalog) Resolve(names []string) ([]string, error) {
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := map[string]int{}
+	var result []string
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("addons have a dependency cycle: %s", strings.Join(append(append([]string{}, path...), name), " -> "))
+		}
+
+		metadata, ok := c[name]
+		if !ok {
+			if len(path) > 0 {
+				return fmt.Errorf("addon %s depends on unknown addon %s", path[len(path)-1], name)
+			}
+			return fmt.Errorf("unknown addon %s", name)
+		}
+
+		state[name] = visiting
+		for _, dependency := range metadata.Dependencies {
+			if err := visit(dependency, append(append([]string{}, path...), name)); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		result = append(result, name)
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// Names returns the names of all addons of the catalog in alphabetical order.
+func (c Catalog) Names() []string {
+	names := make([]string, 0, len(c))
+	for name := range c {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
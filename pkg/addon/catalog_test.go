@@ -0,0 +1,136 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/go-test/deep"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+)
+
+// TestLoadCatalog ensures that the metadata of our default addons is valid and resolvable.
+func TestLoadCatalog(t *testing.T) {
+	catalog, err := LoadCatalog("../../addons")
+	if err != nil {
+		t.Fatalf("failed to load catalog: %v", err)
+	}
+	if _, err := catalog.Resolve(catalog.Names()); err != nil {
+		t.Fatalf("failed to resolve catalog: %v", err)
+	}
+}
+
+func TestCatalogResolve(t *testing.T) {
+	catalog := Catalog{
+		"a": {Name: "a", Dependencies: []string{"b", "c"}},
+		"b": {Name: "b", Dependencies: []string{"c"}},
+		"c": {Name: "c"},
+		"d": {Name: "d"},
+		"e": {Name: "e", Dependencies: []string{"f"}},
+		"f": {Name: "f", Dependencies: []string{"e"}},
+		"g": {Name: "g", Dependencies: []string{"unknown"}},
+	}
+
+	testCases := []struct {
+		name          string
+		addons        []string
+		expected      []string
+		expectedError string
+	}{
+		{
+			name:     "addons without dependencies keep their order",
+			addons:   []string{"d", "c"},
+			expected: []string{"d", "c"},
+		},
+		{
+			name:     "dependencies are installed first and included",
+			addons:   []string{"d", "a"},
+			expected: []string{"d", "c", "b", "a"},
+		},
+		{
+			name:          "cycles are rejected",
+			addons:        []string{"e"},
+			expectedError: "addons have a dependency cycle: e -> f -> e",
+		},
+		{
+			name:          "unknown dependencies are rejected",
+			addons:        []string{"g"},
+			expectedError: "addon g depends on unknown addon unknown",
+		},
+		{
+			name:          "unknown addons are rejected",
+			addons:        []string{"unknown"},
+			expectedError: "unknown addon unknown",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := catalog.Resolve(tc.addons)
+			if tc.expectedError != "" {
+				if err == nil || err.Error() != tc.expectedError {
+					t.Fatalf("expected error %q, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to resolve: %v", err)
+			}
+			if diff := deep.Equal(result, tc.expected); diff != nil {
+				t.Errorf("unexpected order, diff: %v", diff)
+			}
+		})
+	}
+}
+
+func TestSupportsKubernetesVersion(t *testing.T) {
+	metadata := &Metadata{Name: "test", KubernetesVersions: ">= 1.18, < 1.21"}
+
+	for version, expected := range map[string]bool{
+		"1.17.9":  false,
+		"1.18.0":  true,
+		"1.20.15": true,
+		"1.21.0":  false,
+	} {
+		supported, err := metadata.SupportsKubernetesVersion(semver.MustParse(version))
+		if err != nil {
+			t.Fatalf("failed to check version: %v", err)
+		}
+		if supported != expected {
+			t.Errorf("expected version %s to be supported=%v, got %v", version, expected, supported)
+		}
+	}
+}
+
+func TestClusterDependencies(t *testing.T) {
+	metadata := &Metadata{Name: "multus", Dependencies: []string{"rbac"}, RequiresCNIPlugin: true}
+
+	for plugin, expected := range map[kubermaticv1.CNIPluginType][]string{
+		kubermaticv1.CNIPluginTypeCanal:  {"rbac", "canal"},
+		kubermaticv1.CNIPluginTypeCilium: {"rbac", "cilium"},
+		kubermaticv1.CNIPluginTypeNone:   {"rbac"},
+	} {
+		if diff := deep.Equal(metadata.ClusterDependencies(plugin), expected); diff != nil {
+			t.Errorf("unexpected dependencies for CNI plugin %s, diff: %v", plugin, diff)
+		}
+	}
+	if diff := deep.Equal(metadata.Dependencies, []string{"rbac"}); diff != nil {
+		t.Errorf("static dependencies were modified, diff: %v", diff)
+	}
+}
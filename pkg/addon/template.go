@@ -29,6 +29,7 @@ import (
 	"github.com/Masterminds/sprig/v3"
 	"go.uber.org/zap"
 
+	"k8c.io/kubermatic/v2/pkg/cni"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/provider"
 	"k8c.io/kubermatic/v2/pkg/resources"
@@ -84,6 +85,17 @@ func NewTemplateData(
 		variables = make(map[string]interface{})
 	}
 
+	cniPlugin := cni.ClusterPlugin(&cluster.Spec)
+
+	podCIDRIPv4, err := resources.IPv4CIDRBlock(cluster.Spec.ClusterNetwork.Pods)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine pod network: %v", err)
+	}
+	serviceCIDRIPv4, err := resources.IPv4CIDRBlock(cluster.Spec.ClusterNetwork.Services)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine service network: %v", err)
+	}
+
 	return &TemplateData{
 		DatacenterName: cluster.Spec.Cloud.DatacenterName,
 		Variables:      variables,
@@ -111,8 +123,16 @@ func NewTemplateData(
 				DNSResolverIP:     dnsResolverIP,
 				PodCIDRBlocks:     cluster.Spec.ClusterNetwork.Pods.CIDRBlocks,
 				ServiceCIDRBlocks: cluster.Spec.ClusterNetwork.Services.CIDRBlocks,
+				PodCIDRIPv4:       podCIDRIPv4,
+				PodCIDRIPv6:       resources.IPv6CIDRBlock(cluster.Spec.ClusterNetwork.Pods),
+				ServiceCIDRIPv4:   serviceCIDRIPv4,
+				ServiceCIDRIPv6:   resources.IPv6CIDRBlock(cluster.Spec.ClusterNetwork.Services),
 				ProxyMode:         cluster.Spec.ClusterNetwork.ProxyMode,
 			},
+			CNIPlugin: CNIPlugin{
+				Type:    string(cniPlugin.Type),
+				Version: cniPlugin.Version,
+			},
 		},
 	}, nil
 }
@@ -164,15 +184,35 @@ type ClusterData struct {
 	Network ClusterNetwork
 	// Features is a set of enabled features for this cluster.
 	Features sets.String
+	// CNIPlugin is the CNI plugin installed into the cluster.
+	CNIPlugin CNIPlugin
 }
 
 type ClusterNetwork struct {
-	DNSDomain         string
-	DNSClusterIP      string
-	DNSResolverIP     string
-	PodCIDRBlocks     []string
+	DNSDomain     string
+	DNSClusterIP  string
+	DNSResolverIP string
+	// PodCIDRBlocks contains an IPv4 block and, for dual-stack clusters, an IPv6
+	// block. The family of the first block is the primary one of the cluster.
+	PodCIDRBlocks []string
+	// ServiceCIDRBlocks contains the service blocks, in the same way as PodCIDRBlocks.
 	ServiceCIDRBlocks []string
-	ProxyMode         string
+	// PodCIDRIPv4 is the IPv4 block of PodCIDRBlocks.
+	PodCIDRIPv4 string
+	// PodCIDRIPv6 is the IPv6 block of PodCIDRBlocks. It is empty for single-stack clusters.
+	PodCIDRIPv6 string
+	// ServiceCIDRIPv4 is the IPv4 block of ServiceCIDRBlocks.
+	ServiceCIDRIPv4 string
+	// ServiceCIDRIPv6 is the IPv6 block of ServiceCIDRBlocks. It is empty for single-stack clusters.
+	ServiceCIDRIPv6 string
+	ProxyMode       string
+}
+
+type CNIPlugin struct {
+	// Type is one of "canal", "cilium" or "none".
+	Type string
+	// Version is the version of the plugin, e.g. "v3.8". It is empty for the type "none".
+	Version string
 }
 
 func ParseFromFolder(log *zap.SugaredLogger, overwriteRegistry string, manifestPath string, data *TemplateData) ([]runtime.RawExtension, error) {
@@ -187,6 +227,11 @@ func ParseFromFolder(log *zap.SugaredLogger, overwriteRegistry string, manifestP
 		filename := path.Join(manifestPath, info.Name())
 		infoLog := log.With("file", filename)
 
+		// the metadata describes the addon and is not a manifest
+		if info.Name() == MetadataFilename {
+			continue
+		}
+
 		// recurse into subdirectory
 		if info.IsDir() {
 			subManifests, err := ParseFromFolder(log, overwriteRegistry, filename, data)
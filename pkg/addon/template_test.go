@@ -21,6 +21,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"go.uber.org/zap"
@@ -112,6 +113,10 @@ func TestNewTemplateData(t *testing.T) {
 			Features: map[string]bool{
 				feature: true,
 			},
+			ClusterNetwork: kubermaticv1.ClusterNetworkingConfig{
+				Pods:     kubermaticv1.NetworkRanges{CIDRBlocks: []string{"172.25.0.0/16", "fd00::/56"}},
+				Services: kubermaticv1.NetworkRanges{CIDRBlocks: []string{"10.240.16.0/20", "fd01::/108"}},
+			},
 		},
 	}
 
@@ -125,4 +130,62 @@ func TestNewTemplateData(t *testing.T) {
 	if !templateData.Cluster.Features.Has(feature) {
 		t.Fatalf("Expected cluster features to contain %q, but does not.", feature)
 	}
+
+	if templateData.Cluster.Network.PodCIDRIPv4 != "172.25.0.0/16" || templateData.Cluster.Network.PodCIDRIPv6 != "fd00::/56" {
+		t.Fatalf("Expected the pod network to be split into 172.25.0.0/16 and fd00::/56, but got %q and %q.",
+			templateData.Cluster.Network.PodCIDRIPv4, templateData.Cluster.Network.PodCIDRIPv6)
+	}
+}
+
+func TestRenderDualStackAddons(t *testing.T) {
+	content, err := ioutil.ReadFile("testdata/cluster-kubernetes-do-dualstack.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cluster := kubermaticv1.Cluster{}
+	if err := yaml.Unmarshal(content, &cluster); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := NewTemplateData(&cluster, resources.Credentials{}, "kubeconfig", "1.2.3.4", "5.6.7.8", nil)
+	if err != nil {
+		t.Fatalf("Failed to create template data: %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		addon    string
+		expected string
+	}{
+		{
+			name:     "cilium enables IPv6",
+			addon:    "cilium",
+			expected: `"enable-ipv6":"true"`,
+		},
+		{
+			name:     "cilium routes the IPv4 pod block natively",
+			addon:    "cilium",
+			expected: `"native-routing-cidr":"172.25.0.0/16"`,
+		},
+		{
+			name:     "kube-proxy knows both pod blocks",
+			addon:    "kube-proxy",
+			expected: `clusterCIDR: \"172.25.0.0/16,fd00::/56\"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			manifests, err := ParseFromFolder(zap.NewNop().Sugar(), "", filepath.Join("../../addons", tc.addon), data)
+			if err != nil {
+				t.Fatalf("Rendering addon %s failed: %v", tc.addon, err)
+			}
+			for _, manifest := range manifests {
+				if strings.Contains(string(manifest.Raw), tc.expected) {
+					return
+				}
+			}
+			t.Errorf("Expected the manifests of addon %s to contain %s", tc.addon, tc.expected)
+		})
+	}
 }
@@ -431,6 +431,32 @@ type SSHKey struct {
 type SSHKeySpec struct {
 	Fingerprint string `json:"fingerprint"`
 	PublicKey   string `json:"publicKey"`
+	// User is the Linux user the key is authorized for on the nodes. The key is
+	// authorized for all users when it is empty.
+	User string `json:"user,omitempty"`
+	// ExpiresAt is the time after which the key is removed from the nodes.
+	ExpiresAt *Time `json:"expiresAt,omitempty"`
+}
+
+// SSHCertificateSigningRequest represents a public key to sign with the ssh certificate authority of a project
+// swagger:model SSHCertificateSigningRequest
+type SSHCertificateSigningRequest struct {
+	// PublicKey is the public key to sign in the authorized_keys format
+	PublicKey string `json:"publicKey"`
+}
+
+// SSHCertificate represents a user certificate signed by the ssh certificate authority of a project
+// swagger:model SSHCertificate
+type SSHCertificate struct {
+	// Certificate is the certificate in the authorized_keys format, ssh picks it up when it is saved
+	// next to the private key with the "-cert.pub" suffix
+	Certificate string `json:"certificate"`
+	// Principals are the principals of the certificate, they decide which users of the nodes it allows to log in as
+	Principals []string `json:"principals"`
+	// ValidAfter is the time the certificate becomes valid
+	ValidAfter Time `json:"validAfter"`
+	// ValidBefore is the time the certificate expires
+	ValidBefore Time `json:"validBefore"`
 }
 
 // User represent an API user
@@ -529,6 +555,13 @@ type PublicServiceAccountToken struct {
 	// Expiry is a timestamp representing the time when this token will expire.
 	// swagger:strfmt date-time
 	Expiry Time `json:"expiry,omitempty"`
+	// ExpirationSeconds is the requested lifetime of the token when it is created or regenerated,
+	// at least 600 seconds and at most three years. Defaults to three years.
+	ExpirationSeconds int64 `json:"expirationSeconds,omitempty"`
+	// LastUsed is a timestamp representing the last time this token authenticated a request,
+	// it is only updated once a minute.
+	// swagger:strfmt date-time
+	LastUsed *Time `json:"lastUsed,omitempty"`
 }
 
 // ServiceAccountToken represent an API service account token
@@ -539,6 +572,32 @@ type ServiceAccountToken struct {
 	Token string `json:"token,omitempty"`
 }
 
+// JSONWebKeySet contains the public keys which verify the service account tokens
+// swagger:model JSONWebKeySet
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// JSONWebKey is a public key as defined by RFC 7517
+type JSONWebKey struct {
+	// KeyType is either RSA or EC
+	KeyType string `json:"kty"`
+	// KeyID is referenced in the header of the tokens signed with the key
+	KeyID     string `json:"kid"`
+	Use       string `json:"use,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+	// N is the modulus of RSA keys
+	N string `json:"n,omitempty"`
+	// E is the exponent of RSA keys
+	E string `json:"e,omitempty"`
+	// Curve is the curve of EC keys
+	Curve string `json:"crv,omitempty"`
+	// X is the x coordinate of EC keys
+	X string `json:"x,omitempty"`
+	// Y is the y coordinate of EC keys
+	Y string `json:"y,omitempty"`
+}
+
 // Project is a top-level container for a set of resources
 // swagger:model Project
 type Project struct {
@@ -548,6 +607,11 @@ type Project struct {
 	// Owners an optional owners list for the given project
 	Owners         []User `json:"owners,omitempty"`
 	ClustersNumber int    `json:"clustersNumber,omitempty"`
+	// UpdateWindow is the default update window of the clusters of the project
+	UpdateWindow *kubermaticv1.UpdateWindow `json:"updateWindow,omitempty"`
+	// SSHCertificateAuthority makes the nodes of the clusters of the project trust the ssh certificates
+	// signed by the project instead of the ssh keys assigned to the clusters
+	SSHCertificateAuthority *kubermaticv1.ProjectSSHCertificateAuthority `json:"sshCertificateAuthority,omitempty"`
 }
 
 // Kubeconfig is a clusters kubeconfig
@@ -711,6 +775,21 @@ type MasterVersion struct {
 	RestrictedByKubeletVersion bool `json:"restrictedByKubeletVersion,omitempty"`
 }
 
+// ClusterUpgradeSchedule describes when a cluster is upgraded automatically
+// swagger:model ClusterUpgradeSchedule
+type ClusterUpgradeSchedule struct {
+	// UpdateWindow is the update window which applies to the cluster, either its own or the one of its project
+	UpdateWindow *kubermaticv1.UpdateWindow `json:"updateWindow,omitempty"`
+	// TargetVersion is the version the cluster is upgraded to automatically, it is empty if there is no automatic upgrade
+	TargetVersion string `json:"targetVersion,omitempty"`
+	// NextUpgradeTime is the earliest time the upgrade is applied at, it is empty if there is no automatic upgrade
+	NextUpgradeTime *Time `json:"nextUpgradeTime,omitempty"`
+	// Stage is the current stage of the automatic upgrade in progress
+	Stage string `json:"stage,omitempty"`
+	// Message explains why the current stage of the automatic upgrade in progress has not finished yet
+	Message string `json:"message,omitempty"`
+}
+
 // CreateClusterSpec is the structure that is used to create cluster with its initial node deployment
 // swagger:model CreateClusterSpec
 type CreateClusterSpec struct {
@@ -748,6 +827,14 @@ type ClusterSpec struct {
 	// MachineNetworks optionally specifies the parameters for IPAM.
 	MachineNetworks []kubermaticv1.MachineNetworkingConfig `json:"machineNetworks,omitempty"`
 
+	// ClusterNetwork specifies the network ranges of the pods and services, they are defaulted if not set.
+	// An additional IPv6 block makes the cluster dual-stack, the IP families can not be changed after the cluster was created.
+	ClusterNetwork *kubermaticv1.ClusterNetworkingConfig `json:"clusterNetwork,omitempty"`
+
+	// CNIPlugin selects the CNI plugin of the cluster, defaults to the latest version of canal.
+	// The type can not be changed after the cluster was created, the version can be upgraded.
+	CNIPlugin *kubermaticv1.CNIPluginSettings `json:"cniPlugin,omitempty"`
+
 	// Version desired version of the kubernetes master components
 	Version ksemver.Semver `json:"version"`
 
@@ -757,6 +844,10 @@ type ClusterSpec struct {
 	// Configure cluster upgrade window, currently used for flatcar node reboots
 	UpdateWindow *kubermaticv1.UpdateWindow `json:"updateWindow,omitempty"`
 
+	// Hibernation controls whether the control plane and the workers of the cluster are scaled to zero,
+	// either on demand or on a schedule.
+	Hibernation *kubermaticv1.HibernationSettings `json:"hibernation,omitempty"`
+
 	// If active the PodSecurityPolicy admission plugin is configured at the apiserver
 	UsePodSecurityPolicyAdmissionPlugin bool `json:"usePodSecurityPolicyAdmissionPlugin,omitempty"`
 
@@ -804,9 +895,12 @@ func (cs *ClusterSpec) MarshalJSON() ([]byte, error) {
 	ret, err := json.Marshal(struct {
 		Cloud                                PublicCloudSpec                        `json:"cloud"`
 		MachineNetworks                      []kubermaticv1.MachineNetworkingConfig `json:"machineNetworks,omitempty"`
+		ClusterNetwork                       *kubermaticv1.ClusterNetworkingConfig  `json:"clusterNetwork,omitempty"`
+		CNIPlugin                            *kubermaticv1.CNIPluginSettings        `json:"cniPlugin,omitempty"`
 		Version                              ksemver.Semver                         `json:"version"`
 		OIDC                                 kubermaticv1.OIDCSettings              `json:"oidc"`
 		UpdateWindow                         *kubermaticv1.UpdateWindow             `json:"updateWindow,omitempty"`
+		Hibernation                          *kubermaticv1.HibernationSettings      `json:"hibernation,omitempty"`
 		UsePodSecurityPolicyAdmissionPlugin  bool                                   `json:"usePodSecurityPolicyAdmissionPlugin,omitempty"`
 		UsePodNodeSelectorAdmissionPlugin    bool                                   `json:"usePodNodeSelectorAdmissionPlugin,omitempty"`
 		EnableUserSSHKeyAgent                *bool                                  `json:"enableUserSSHKeyAgent,omitempty"`
@@ -835,8 +929,11 @@ func (cs *ClusterSpec) MarshalJSON() ([]byte, error) {
 		},
 		Version:                              cs.Version,
 		MachineNetworks:                      cs.MachineNetworks,
+		ClusterNetwork:                       cs.ClusterNetwork,
+		CNIPlugin:                            cs.CNIPlugin,
 		OIDC:                                 cs.OIDC,
 		UpdateWindow:                         cs.UpdateWindow,
+		Hibernation:                          cs.Hibernation,
 		UsePodSecurityPolicyAdmissionPlugin:  cs.UsePodSecurityPolicyAdmissionPlugin,
 		UsePodNodeSelectorAdmissionPlugin:    cs.UsePodNodeSelectorAdmissionPlugin,
 		EnableUserSSHKeyAgent:                cs.EnableUserSSHKeyAgent,
@@ -1052,18 +1149,56 @@ type ClusterHealth struct {
 	UserClusterControllerManager kubermaticv1.HealthStatus `json:"userClusterControllerManager"`
 	GatekeeperController         kubermaticv1.HealthStatus `json:"gatekeeperController,omitempty"`
 	GatekeeperAudit              kubermaticv1.HealthStatus `json:"gatekeeperAudit,omitempty"`
+	TunnelingAgent               kubermaticv1.HealthStatus `json:"tunnelingAgent,omitempty"`
+	Addons                       kubermaticv1.HealthStatus `json:"addons,omitempty"`
 }
 
 // AccessibleAddons represents an array of addons that can be configured in the user clusters.
 // swagger:model AccessibleAddons
 type AccessibleAddons []string
 
+// InstallableAddon represents an addon that can be installed into the user cluster
+// swagger:model InstallableAddon
+type InstallableAddon struct {
+	// Name of the addon
+	Name string `json:"name"`
+	// Version of the addon provided by the addon catalog
+	Version string `json:"version,omitempty"`
+	// Dependencies are the addons which are installed before this addon
+	Dependencies []string `json:"dependencies,omitempty"`
+	// KubernetesVersions is a semver constraint of the Kubernetes versions the addon supports
+	KubernetesVersions string `json:"kubernetesVersions,omitempty"`
+}
+
 // Addon represents a predefined addon that users may install into their cluster
 // swagger:model Addon
 type Addon struct {
 	ObjectMeta `json:",inline"`
 
-	Spec AddonSpec `json:"spec"`
+	Spec   AddonSpec    `json:"spec"`
+	Status *AddonStatus `json:"status,omitempty"`
+}
+
+// AddonStatus addon status
+// swagger:model AddonStatus
+type AddonStatus struct {
+	// Conditions contains the conditions of the addon, like AddonReady
+	Conditions []AddonCondition `json:"conditions,omitempty"`
+	// Version is the version of the addon which is installed
+	Version string `json:"version,omitempty"`
+}
+
+// AddonCondition represents a condition of an addon
+// swagger:model AddonCondition
+type AddonCondition struct {
+	// Type of the condition, e.g. AddonReady
+	Type string `json:"type"`
+	// Status of the condition, one of True, False, Unknown
+	Status string `json:"status"`
+	// Message contains details about the status, e.g. which workloads are not ready
+	Message string `json:"message,omitempty"`
+	// LastTransitionTime is the last time the condition changed its status
+	LastTransitionTime Time `json:"lastTransitionTime,omitempty"`
 }
 
 // AddonSpec addon specification
@@ -1075,6 +1210,9 @@ type AddonSpec struct {
 	IsDefault bool `json:"isDefault,omitempty"`
 	// ContinuouslyReconcile indicates that the addon cannot be deleted or modified outside of the UI after installation
 	ContinuouslyReconcile bool `json:"continuouslyReconcile,omitempty"`
+	// Version pins the addon to a version, it is not updated while a different version is available.
+	// If empty, the addon is updated to the available version.
+	Version string `json:"version,omitempty"`
 }
 
 // AddonConfig represents a addon configuration
@@ -2165,6 +2303,8 @@ const (
 	SeedProjectCleanupFinalizer = "kubermatic.io/cleanup-seed-projects"
 	// SeedUserProjectBindingCleanupFinalizer indicates that Kubermatic UserProjectBindings on the seed clusters need cleanup
 	SeedUserProjectBindingCleanupFinalizer = "kubermatic.io/cleanup-seed-user-project-bindings"
+	// SeedGroupProjectBindingCleanupFinalizer indicates that Kubermatic GroupProjectBindings on the seed clusters need cleanup
+	SeedGroupProjectBindingCleanupFinalizer = "kubermatic.io/cleanup-seed-group-project-bindings"
 	// ClusterRoleBindingsCleanupFinalizer indicates that the cluster ClusterRoleBindings on the seed cluster need cleanup
 	ClusterRoleBindingsCleanupFinalizer = "kubermatic.io/cleanup-cluster-role-bindings"
 )
@@ -17,9 +17,13 @@ limitations under the License.
 package v2
 
 import (
+	"encoding/json"
+
 	"github.com/open-policy-agent/frameworks/constraint/pkg/apis/templates/v1beta1"
 
+	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
 	crdapiv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	ksemver "k8c.io/kubermatic/v2/pkg/semver"
 )
 
 // ConstraintTemplate represents a gatekeeper ConstraintTemplate
@@ -158,3 +162,136 @@ type MLA struct {
 	// whether the user cluster MLA (Monitoring, Logging & Alerting) stack is enabled in the seed
 	UserClusterMLAEnabled bool `json:"user_cluster_mla_enabled"`
 }
+
+// AuditEvent records a mutating request to the Kubermatic API
+// swagger:model AuditEvent
+type AuditEvent struct {
+	// Time is the time the request was received
+	// swagger:strfmt date-time
+	Time apiv1.Time `json:"time"`
+	// User is the email of the user who sent the request
+	User string `json:"user"`
+	// UserID is the name of the User resource of the user
+	UserID string `json:"userID"`
+	// Admin is true if the user is a Kubermatic admin
+	Admin bool `json:"admin,omitempty"`
+	// ProjectRole is the group of the user in the project of the request, for example owners
+	ProjectRole string `json:"projectRole,omitempty"`
+	// Method is the HTTP method of the request
+	Method string `json:"method"`
+	// Endpoint is the route of the request, for example /api/v1/projects/{project_id}/clusters
+	Endpoint string `json:"endpoint"`
+	// Resources are the IDs in the path of the request, for example the project_id
+	Resources map[string]string `json:"resources,omitempty"`
+	// Request is the body of the request, for patch requests the patch, with all secret values redacted
+	// swagger:type object
+	Request json.RawMessage `json:"request,omitempty"`
+	// Before are the fields of the resource which the successful request changed, with their values before the
+	// request and all secret values redacted. It is empty for created resources.
+	// swagger:type object
+	Before json.RawMessage `json:"before,omitempty"`
+	// After are the fields of the resource which the successful request changed, with their values after the
+	// request and all secret values redacted. It is empty for deleted resources.
+	// swagger:type object
+	After json.RawMessage `json:"after,omitempty"`
+	// Outcome is either Succeeded or Failed
+	Outcome AuditOutcome `json:"outcome"`
+	// Code is the HTTP status code of a failed request
+	Code int `json:"code,omitempty"`
+	// Error is the error message of a failed request
+	Error string `json:"error,omitempty"`
+}
+
+// AuditOutcome is the outcome of an audited request
+type AuditOutcome string
+
+const (
+	AuditOutcomeSucceeded AuditOutcome = "Succeeded"
+	AuditOutcomeFailed    AuditOutcome = "Failed"
+)
+
+// GroupProjectBinding binds a group of the OIDC tokens to a project, all users of the group are members of the project
+// swagger:model GroupProjectBinding
+type GroupProjectBinding struct {
+	// Name is the name of the binding
+	Name string `json:"name"`
+	// Group is the name of the group as contained in the groups claim of the OIDC tokens
+	Group string `json:"group"`
+	// ProjectID is the ID of the project
+	ProjectID string `json:"projectID"`
+	// Role is the role of the group in the project, either owners, editors, viewers or the name of a custom project role
+	Role string `json:"role"`
+}
+
+// ClusterTemplate is a saved cluster configuration from which new clusters can be created
+// swagger:model ClusterTemplate
+type ClusterTemplate struct {
+	// ID is the identifier of the template, it is generated when the template is created
+	ID string `json:"id"`
+	// Name is the name of the template
+	Name string `json:"name"`
+	// Scope is either global, user or project. Global templates can be used in all projects and are managed by the admins,
+	// user templates can be used by their owner in all projects and project templates by all members of the project.
+	Scope string `json:"scope"`
+	// ProjectID is the ID of the project of project scoped templates
+	ProjectID string `json:"projectID,omitempty"`
+	// User is the email of the user who created the template
+	User string `json:"user,omitempty"`
+	// Cluster is the cluster created from the template. The cloud credentials are taken from the preset named by its
+	// credential field, the template must not contain any credentials itself.
+	Cluster *apiv1.Cluster `json:"cluster"`
+	// NodeDeployment is the initial node deployment of the clusters
+	NodeDeployment *apiv1.NodeDeployment `json:"nodeDeployment,omitempty"`
+}
+
+// ClusterTemplateInstances defines the clusters to create from a cluster template and overrides its parameters
+// swagger:model ClusterTemplateInstances
+type ClusterTemplateInstances struct {
+	// Replicas is the number of clusters to create, it defaults to 1
+	Replicas int `json:"replicas,omitempty"`
+	// Name overrides the name of the clusters. The clusters are numbered if more than one is created.
+	Name string `json:"name,omitempty"`
+	// Version overrides the Kubernetes version of the clusters
+	Version *ksemver.Semver `json:"version,omitempty"`
+	// Labels are added to the labels of the clusters, overriding the ones of the template
+	Labels map[string]string `json:"labels,omitempty"`
+	// Credential overrides the name of the preset providing the cloud credentials of the clusters
+	Credential string `json:"credential,omitempty"`
+}
+
+// ProjectResourceQuota is the resource quota of a project together with the resources its clusters use
+// swagger:model ProjectResourceQuota
+type ProjectResourceQuota struct {
+	// Quota holds the limits of the project, the limits which are not set are not enforced
+	Quota ProjectResources `json:"quota"`
+	// Usage holds the resources used by all clusters of the project
+	Usage ProjectResources `json:"usage"`
+}
+
+// ProjectResources is an amount of resources of a project
+// swagger:model ProjectResources
+type ProjectResources struct {
+	Clusters *int64 `json:"clusters,omitempty"`
+	Nodes    *int64 `json:"nodes,omitempty"`
+	// CPU is the number of vCPUs as a quantity, for example "8"
+	CPU string `json:"cpu,omitempty"`
+	// Memory is a quantity, for example "32Gi"
+	Memory string `json:"memory,omitempty"`
+	// Storage is a quantity, for example "500Gi"
+	Storage string `json:"storage,omitempty"`
+}
+
+// ClusterHibernation describes the hibernation of a cluster
+// swagger:model ClusterHibernation
+type ClusterHibernation struct {
+	// Settings are the hibernation settings of the cluster, including the schedule
+	Settings *crdapiv1.HibernationSettings `json:"settings,omitempty"`
+	// Phase is the current phase of the hibernation or resumption, it is empty if the cluster was never hibernated
+	Phase string `json:"phase,omitempty"`
+	// Message explains why the current phase has not finished yet
+	Message string `json:"message,omitempty"`
+	// NextHibernationTime is the next time the schedule hibernates the cluster at, it is empty if there is no schedule
+	NextHibernationTime *apiv1.Time `json:"nextHibernationTime,omitempty"`
+	// NextResumptionTime is the next time the schedule resumes the cluster at, it is empty if there is no schedule
+	NextResumptionTime *apiv1.Time `json:"nextResumptionTime,omitempty"`
+}
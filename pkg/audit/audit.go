@@ -0,0 +1,138 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit records the mutating requests to the Kubermatic API. The events are written to
+// the configured sinks in the background, admins can query them if one of the sinks is a Reader.
+package audit
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+
+	apiv2 "k8c.io/kubermatic/v2/pkg/api/v2"
+)
+
+// queueSize is the number of events waiting to be written to the sinks, further events are dropped
+const queueSize = 1000
+
+// ErrNoReader is returned by List if none of the sinks can be queried
+var ErrNoReader = errors.New("none of the audit sinks can be queried")
+
+// Sink writes audit events to a storage
+type Sink interface {
+	Write(ctx context.Context, event *apiv2.AuditEvent) error
+}
+
+// Reader is a Sink whose events can be queried
+type Reader interface {
+	Sink
+	// List returns the events matching the filter, the newest event first
+	List(ctx context.Context, filter Filter) ([]apiv2.AuditEvent, error)
+}
+
+// Logger records audit events
+type Logger struct {
+	log   *zap.SugaredLogger
+	sinks []Sink
+	queue chan *apiv2.AuditEvent
+}
+
+// New returns a logger writing the events to the given sinks. Start must be called to write them.
+func New(log *zap.SugaredLogger, sinks ...Sink) *Logger {
+	return &Logger{
+		log:   log,
+		sinks: sinks,
+		queue: make(chan *apiv2.AuditEvent, queueSize),
+	}
+}
+
+// NewSynchronous returns a logger writing the events to the given sinks before Log returns,
+// so that they can be listed right away. It is meant for tests, Start must not be called.
+func NewSynchronous(log *zap.SugaredLogger, sinks ...Sink) *Logger {
+	return &Logger{
+		log:   log,
+		sinks: sinks,
+	}
+}
+
+// Start writes the events to the sinks until the context is cancelled
+func (l *Logger) Start(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-l.queue:
+			l.write(ctx, event)
+		}
+	}
+}
+
+func (l *Logger) write(ctx context.Context, event *apiv2.AuditEvent) {
+	for _, sink := range l.sinks {
+		if err := sink.Write(ctx, event); err != nil {
+			l.log.Errorw("failed to write audit event", "endpoint", event.Endpoint, "user", event.User, zap.Error(err))
+		}
+	}
+}
+
+// Log records the event
+func (l *Logger) Log(event *apiv2.AuditEvent) {
+	if len(l.sinks) == 0 {
+		return
+	}
+	if l.queue == nil {
+		l.write(context.Background(), event)
+		return
+	}
+	select {
+	case l.queue <- event:
+	default:
+		l.log.Errorw("dropped audit event because the sinks are too slow", "endpoint", event.Endpoint, "user", event.User)
+	}
+}
+
+// List returns the events matching the filter from the first sink which is a Reader, the newest event first.
+// ErrNoReader is returned if none of the sinks is a Reader.
+func (l *Logger) List(ctx context.Context, filter Filter) ([]apiv2.AuditEvent, error) {
+	for _, sink := range l.sinks {
+		if reader, ok := sink.(Reader); ok {
+			return reader.List(ctx, filter)
+		}
+	}
+	return nil, ErrNoReader
+}
+
+// Filter selects audit events
+type Filter struct {
+	// User selects the events of the user with this email
+	User string
+	// ProjectID selects the events of requests to this project
+	ProjectID string
+	// Limit is the maximum number of events
+	Limit int
+}
+
+func (f *Filter) matches(event *apiv2.AuditEvent) bool {
+	if f.User != "" && event.User != f.User {
+		return false
+	}
+	if f.ProjectID != "" && event.Resources["project_id"] != f.ProjectID {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
+	apiv2 "k8c.io/kubermatic/v2/pkg/api/v2"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func genEvent(i int, user, projectID string) *apiv2.AuditEvent {
+	return &apiv2.AuditEvent{
+		Time:      apiv1.NewTime(time.Date(2021, 4, 1, 12, 0, 0, i*int(time.Millisecond), time.UTC)),
+		User:      user,
+		Method:    "DELETE",
+		Endpoint:  fmt.Sprintf("/api/v1/projects/{project_id}/sshkeys/%d", i),
+		Resources: map[string]string{"project_id": projectID},
+		Outcome:   apiv2.AuditOutcomeSucceeded,
+	}
+}
+
+func endpoints(events []apiv2.AuditEvent) []string {
+	result := []string{}
+	for _, event := range events {
+		result = append(result, event.Endpoint)
+	}
+	return result
+}
+
+func TestEventSink(t *testing.T) {
+	testCases := []struct {
+		name              string
+		events            int
+		filter            Filter
+		expectedEndpoints []string
+	}{
+		{
+			name:              "no events",
+			expectedEndpoints: []string{},
+		},
+		{
+			name:   "newest event first",
+			events: 3,
+			expectedEndpoints: []string{
+				"/api/v1/projects/{project_id}/sshkeys/2",
+				"/api/v1/projects/{project_id}/sshkeys/1",
+				"/api/v1/projects/{project_id}/sshkeys/0",
+			},
+		},
+		{
+			name:   "limited",
+			events: 3,
+			filter: Filter{Limit: 1},
+			expectedEndpoints: []string{
+				"/api/v1/projects/{project_id}/sshkeys/2",
+			},
+		},
+		{
+			name:   "filtered by user and project",
+			events: 6,
+			filter: Filter{User: "bob@acme.com", ProjectID: "project-1"},
+			expectedEndpoints: []string{
+				"/api/v1/projects/{project_id}/sshkeys/4",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := fakectrlruntimeclient.NewClientBuilder().Build()
+			// events of other components are ignored
+			if err := client.Create(context.Background(), &corev1.Event{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "kubermatic"}}); err != nil {
+				t.Fatalf("failed to create event: %v", err)
+			}
+
+			logger := NewSynchronous(zap.NewNop().Sugar(), NewEventSink(client, "kubermatic"))
+			for i := 0; i < tc.events; i++ {
+				user := "bob@acme.com"
+				if i%2 == 1 {
+					user = "john@acme.com"
+				}
+				logger.Log(genEvent(i, user, fmt.Sprintf("project-%d", i%3)))
+			}
+
+			events, err := logger.List(context.Background(), tc.filter)
+			if err != nil {
+				t.Fatalf("failed to list the events: %v", err)
+			}
+			if result := endpoints(events); fmt.Sprint(result) != fmt.Sprint(tc.expectedEndpoints) {
+				t.Errorf("expected events %v, got %v", tc.expectedEndpoints, result)
+			}
+		})
+	}
+}
+
+func TestListWithoutReader(t *testing.T) {
+	sink, err := NewFileSink(filepath.Join(t.TempDir(), "audit.log"))
+	if err != nil {
+		t.Fatalf("failed to create the sink: %v", err)
+	}
+
+	logger := NewSynchronous(zap.NewNop().Sugar(), sink)
+	logger.Log(genEvent(0, "bob@acme.com", "project-0"))
+
+	if _, err := logger.List(context.Background(), Filter{}); !errors.Is(err, ErrNoReader) {
+		t.Errorf("expected %v, got %v", ErrNoReader, err)
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	sink, err := NewFileSink(path)
+	if err != nil {
+		t.Fatalf("failed to create the sink: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger := New(zap.NewNop().Sugar(), sink)
+	go logger.Start(ctx)
+
+	logger.Log(genEvent(0, "bob@acme.com", "project-0"))
+	logger.Log(genEvent(1, "john@acme.com", "project-1"))
+
+	var events []apiv2.AuditEvent
+	for start := time.Now(); len(events) < 2 && time.Since(start) < 10*time.Second; time.Sleep(10 * time.Millisecond) {
+		events = nil
+
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatalf("failed to open the audit log: %v", err)
+		}
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			event := apiv2.AuditEvent{}
+			if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+				// the sink might still be writing the line
+				break
+			}
+			events = append(events, event)
+		}
+		file.Close()
+	}
+
+	expected := []string{"/api/v1/projects/{project_id}/sshkeys/0", "/api/v1/projects/{project_id}/sshkeys/1"}
+	if result := endpoints(events); fmt.Sprint(result) != fmt.Sprint(expected) {
+		t.Errorf("expected events %v in the audit log, got %v", expected, result)
+	}
+}
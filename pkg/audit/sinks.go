@@ -0,0 +1,189 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	apiv2 "k8c.io/kubermatic/v2/pkg/api/v2"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// EventLabel is the label of the Kubernetes events which are audit events
+	EventLabel = "kubermatic.io/audit"
+	// EventAnnotation is the annotation of the Kubernetes events containing the audit event as JSON
+	EventAnnotation = "kubermatic.io/audit-event"
+	// EventReason is the reason of the Kubernetes events which are audit events
+	EventReason = "Audit"
+)
+
+// fileSink appends the events as JSON lines to a file
+type fileSink struct {
+	lock sync.Mutex
+	file *os.File
+}
+
+// NewFileSink returns a sink which appends the events as JSON lines to the file at the given path
+func NewFileSink(path string) (Sink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log file: %v", err)
+	}
+	return &fileSink{file: file}, nil
+}
+
+func (s *fileSink) Write(_ context.Context, event *apiv2.AuditEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, err = s.file.Write(append(data, '\n'))
+	return err
+}
+
+// webhookSink posts every event as JSON to a URL
+type webhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSink returns a sink which posts every event as JSON to the given URL
+func NewWebhookSink(url string, client *http.Client) Sink {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &webhookSink{url: url, client: client}
+}
+
+func (s *webhookSink) Write(ctx context.Context, event *apiv2.AuditEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// eventSink creates a Kubernetes event for every audit event
+type eventSink struct {
+	client    ctrlruntimeclient.Client
+	namespace string
+}
+
+// NewEventSink returns a sink which creates a Kubernetes event involving the User of the request
+// in the given namespace for every audit event. The events can be listed again, so the client
+// should read from the apiserver instead of caching all events of the cluster.
+func NewEventSink(client ctrlruntimeclient.Client, namespace string) Reader {
+	return &eventSink{client: client, namespace: namespace}
+}
+
+func (s *eventSink) Write(ctx context.Context, event *apiv2.AuditEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("%s %s %s: %s", event.User, event.Method, event.Endpoint, event.Outcome)
+	if event.Error != "" {
+		message = fmt.Sprintf("%s: %s", message, event.Error)
+	}
+
+	kubeEvent := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			// like the names of the events of the client-go recorder, the names sort by the time
+			// with nanosecond precision, which the time of the audit event lacks
+			Name:        fmt.Sprintf("audit.%x", event.Time.UnixNano()),
+			Namespace:   s.namespace,
+			Labels:      map[string]string{EventLabel: "true"},
+			Annotations: map[string]string{EventAnnotation: string(data)},
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: kubermaticv1.SchemeGroupVersion.String(),
+			Kind:       kubermaticv1.UserKindName,
+			Name:       event.UserID,
+		},
+		Reason:         EventReason,
+		Message:        message,
+		Type:           corev1.EventTypeNormal,
+		Source:         corev1.EventSource{Component: "kubermatic-api"},
+		FirstTimestamp: metav1.NewTime(event.Time.Time),
+		LastTimestamp:  metav1.NewTime(event.Time.Time),
+		Count:          1,
+	}
+
+	return s.client.Create(ctx, kubeEvent)
+}
+
+func (s *eventSink) List(ctx context.Context, filter Filter) ([]apiv2.AuditEvent, error) {
+	kubeEvents := &corev1.EventList{}
+	if err := s.client.List(ctx, kubeEvents, ctrlruntimeclient.InNamespace(s.namespace), ctrlruntimeclient.MatchingLabels{EventLabel: "true"}); err != nil {
+		return nil, fmt.Errorf("failed to list audit events: %v", err)
+	}
+
+	sort.Slice(kubeEvents.Items, func(i, j int) bool {
+		return kubeEvents.Items[i].Name > kubeEvents.Items[j].Name
+	})
+
+	events := []apiv2.AuditEvent{}
+	for _, kubeEvent := range kubeEvents.Items {
+		event := apiv2.AuditEvent{}
+		if err := json.Unmarshal([]byte(kubeEvent.Annotations[EventAnnotation]), &event); err != nil {
+			return nil, fmt.Errorf("failed to decode audit event %s: %v", kubeEvent.Name, err)
+		}
+		if !filter.matches(&event) {
+			continue
+		}
+		events = append(events, event)
+		if filter.Limit > 0 && len(events) >= filter.Limit {
+			break
+		}
+	}
+
+	return events, nil
+}
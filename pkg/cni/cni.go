@@ -0,0 +1,144 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cni contains the CNI plugins and versions which can be installed into user clusters.
+package cni
+
+import (
+	"fmt"
+	"sort"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+)
+
+// supportedVersions are the versions of every CNI plugin type, ordered from the oldest to the newest.
+// The addon of the plugin has to be able to render all of them.
+var supportedVersions = map[kubermaticv1.CNIPluginType][]string{
+	kubermaticv1.CNIPluginTypeCanal:  {"v3.8"},
+	kubermaticv1.CNIPluginTypeCilium: {"v1.9", "v1.10"},
+	kubermaticv1.CNIPluginTypeNone:   {""},
+}
+
+// defaultVersions are the versions of new clusters which do not specify one.
+var defaultVersions = map[kubermaticv1.CNIPluginType]string{
+	kubermaticv1.CNIPluginTypeCanal:  "v3.8",
+	kubermaticv1.CNIPluginTypeCilium: "v1.10",
+	kubermaticv1.CNIPluginTypeNone:   "",
+}
+
+// dualStackTypes are the CNI plugin types which can give the pods an IPv4 and an IPv6 address.
+// Users bringing their own plugin are responsible for it.
+var dualStackTypes = map[kubermaticv1.CNIPluginType]bool{
+	kubermaticv1.CNIPluginTypeCilium: true,
+	kubermaticv1.CNIPluginTypeNone:   true,
+}
+
+// DefaultType is the type of the CNI plugin of new clusters which do not specify one.
+const DefaultType = kubermaticv1.CNIPluginTypeCanal
+
+// legacyPlugin is the CNI plugin of the clusters created before it could be selected.
+var legacyPlugin = kubermaticv1.CNIPluginSettings{Type: kubermaticv1.CNIPluginTypeCanal, Version: "v3.8"}
+
+// SupportedTypes returns the supported CNI plugin types in alphabetical order.
+func SupportedTypes() []string {
+	var types []string
+	for t := range supportedVersions {
+		types = append(types, string(t))
+	}
+	sort.Strings(types)
+	return types
+}
+
+// SupportedVersions returns the supported versions of the CNI plugin type, from the oldest to the newest.
+func SupportedVersions(t kubermaticv1.CNIPluginType) ([]string, error) {
+	versions, ok := supportedVersions[t]
+	if !ok {
+		return nil, fmt.Errorf("CNI plugin type %q is not supported, supported types are %v", t, SupportedTypes())
+	}
+	return versions, nil
+}
+
+// DefaultVersion returns the version of the CNI plugin type which is installed if none is specified.
+func DefaultVersion(t kubermaticv1.CNIPluginType) (string, error) {
+	version, ok := defaultVersions[t]
+	if !ok {
+		return "", fmt.Errorf("CNI plugin type %q is not supported, supported types are %v", t, SupportedTypes())
+	}
+	return version, nil
+}
+
+// ClusterPlugin returns the CNI plugin of the cluster. Clusters created before the plugin could be
+// selected have none set and run the legacy version of Canal.
+func ClusterPlugin(spec *kubermaticv1.ClusterSpec) kubermaticv1.CNIPluginSettings {
+	if spec.CNIPlugin == nil {
+		return legacyPlugin
+	}
+	return *spec.CNIPlugin
+}
+
+// SupportsDualStack returns true if the CNI plugin type can be used by dual-stack clusters.
+func SupportsDualStack(t kubermaticv1.CNIPluginType) bool {
+	return dualStackTypes[t]
+}
+
+// ValidatePlugin returns an error if the type or the version of the CNI plugin is not supported.
+func ValidatePlugin(plugin kubermaticv1.CNIPluginSettings) error {
+	versions, err := SupportedVersions(plugin.Type)
+	if err != nil {
+		return err
+	}
+	if versionIndex(versions, plugin.Version) < 0 {
+		return fmt.Errorf("version %q of CNI plugin type %q is not supported, supported versions are %q", plugin.Version, plugin.Type, versions)
+	}
+	return nil
+}
+
+// ValidateUpgrade returns an error if the CNI plugin of a cluster can not be changed from the old to the
+// new one. The type can not be changed, as the pods would lose their network, and the version can only be
+// upgraded to the next supported one, like the upgrade guides of the plugins demand.
+func ValidateUpgrade(oldPlugin, newPlugin kubermaticv1.CNIPluginSettings) error {
+	if oldPlugin.Type != newPlugin.Type {
+		return fmt.Errorf("changing the CNI plugin type from %q to %q is not supported", oldPlugin.Type, newPlugin.Type)
+	}
+	if err := ValidatePlugin(newPlugin); err != nil {
+		return err
+	}
+	if oldPlugin.Version == newPlugin.Version {
+		return nil
+	}
+
+	versions := supportedVersions[newPlugin.Type]
+	oldIndex, newIndex := versionIndex(versions, oldPlugin.Version), versionIndex(versions, newPlugin.Version)
+	switch {
+	case oldIndex < 0:
+		// The old version was removed from the supported ones, any supported version is an upgrade.
+		return nil
+	case newIndex < oldIndex:
+		return fmt.Errorf("downgrading the CNI plugin %q from %s to %s is not supported", newPlugin.Type, oldPlugin.Version, newPlugin.Version)
+	case newIndex > oldIndex+1:
+		return fmt.Errorf("the CNI plugin %q can only be upgraded from %s to %s", newPlugin.Type, oldPlugin.Version, versions[oldIndex+1])
+	}
+	return nil
+}
+
+func versionIndex(versions []string, version string) int {
+	for i := range versions {
+		if versions[i] == version {
+			return i
+		}
+	}
+	return -1
+}
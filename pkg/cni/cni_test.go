@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cni
+
+import (
+	"testing"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+)
+
+func TestValidatePlugin(t *testing.T) {
+	testCases := []struct {
+		name        string
+		plugin      kubermaticv1.CNIPluginSettings
+		expectError bool
+	}{
+		{
+			name:   "supported canal version",
+			plugin: kubermaticv1.CNIPluginSettings{Type: kubermaticv1.CNIPluginTypeCanal, Version: "v3.8"},
+		},
+		{
+			name:   "supported cilium version",
+			plugin: kubermaticv1.CNIPluginSettings{Type: kubermaticv1.CNIPluginTypeCilium, Version: "v1.9"},
+		},
+		{
+			name:   "no plugin",
+			plugin: kubermaticv1.CNIPluginSettings{Type: kubermaticv1.CNIPluginTypeNone},
+		},
+		{
+			name:        "unsupported version",
+			plugin:      kubermaticv1.CNIPluginSettings{Type: kubermaticv1.CNIPluginTypeCilium, Version: "v1.8"},
+			expectError: true,
+		},
+		{
+			name:        "version without plugin",
+			plugin:      kubermaticv1.CNIPluginSettings{Type: kubermaticv1.CNIPluginTypeNone, Version: "v1.0"},
+			expectError: true,
+		},
+		{
+			name:        "unsupported type",
+			plugin:      kubermaticv1.CNIPluginSettings{Type: "weave", Version: "v2.8"},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidatePlugin(tc.plugin)
+			if (err != nil) != tc.expectError {
+				t.Errorf("expected error to be %v, got %v", tc.expectError, err)
+			}
+		})
+	}
+}
+
+func TestValidateUpgrade(t *testing.T) {
+	cilium := func(version string) kubermaticv1.CNIPluginSettings {
+		return kubermaticv1.CNIPluginSettings{Type: kubermaticv1.CNIPluginTypeCilium, Version: version}
+	}
+
+	testCases := []struct {
+		name        string
+		oldPlugin   kubermaticv1.CNIPluginSettings
+		newPlugin   kubermaticv1.CNIPluginSettings
+		expectError bool
+	}{
+		{
+			name:      "unchanged",
+			oldPlugin: cilium("v1.9"),
+			newPlugin: cilium("v1.9"),
+		},
+		{
+			name:      "upgrade to the next version",
+			oldPlugin: cilium("v1.9"),
+			newPlugin: cilium("v1.10"),
+		},
+		{
+			name:        "downgrade",
+			oldPlugin:   cilium("v1.10"),
+			newPlugin:   cilium("v1.9"),
+			expectError: true,
+		},
+		{
+			name:      "upgrade from a version which is no longer supported",
+			oldPlugin: cilium("v1.8"),
+			newPlugin: cilium("v1.9"),
+		},
+		{
+			name:        "upgrade to an unsupported version",
+			oldPlugin:   cilium("v1.10"),
+			newPlugin:   cilium("v1.11"),
+			expectError: true,
+		},
+		{
+			name:        "type change",
+			oldPlugin:   kubermaticv1.CNIPluginSettings{Type: kubermaticv1.CNIPluginTypeCanal, Version: "v3.8"},
+			newPlugin:   cilium("v1.10"),
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateUpgrade(tc.oldPlugin, tc.newPlugin)
+			if (err != nil) != tc.expectError {
+				t.Errorf("expected error to be %v, got %v", tc.expectError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeletdnat
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+// iptablesApplier applies the rules to a chain of the nat table by shelling
+// out to iptables-save and iptables-restore.
+type iptablesApplier struct {
+	chain        string
+	vpnInterface string
+
+	log *zap.SugaredLogger
+}
+
+func (a *iptablesApplier) Apply(rules []*dnatRule) error {
+	desiredRules := restoreLines(a.chain, rules)
+
+	// Get the actual state (current iptable rules)
+	allActualRules, err := execSave()
+	if err != nil {
+		return fmt.Errorf("failed to read iptable rules: %v", err)
+	}
+	// filter out everything that's not relevant for us
+	actualRules, haveJump, haveMasquerade := a.filterDnatRules(allActualRules)
+
+	if !equality.Semantic.DeepEqual(actualRules, desiredRules) || !haveJump || !haveMasquerade {
+		// Need to update chain in kernel.
+		a.log.Debugw("Updating iptables chain in kernel", "rules-count", len(desiredRules))
+		if err := a.applyDNATRules(desiredRules, haveJump, haveMasquerade); err != nil {
+			return fmt.Errorf("failed to apply iptable rules: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func (a *iptablesApplier) Cleanup() error {
+	allActualRules, err := execSave()
+	if err != nil {
+		return fmt.Errorf("failed to read iptable rules: %v", err)
+	}
+	_, haveJump, haveMasquerade := a.filterDnatRules(allActualRules)
+	haveChain := false
+	for _, rule := range allActualRules {
+		if strings.HasPrefix(rule, fmt.Sprintf(":%s ", a.chain)) {
+			haveChain = true
+		}
+	}
+
+	restore := []string{"*nat"}
+	if haveJump {
+		restore = append(restore, fmt.Sprintf("-D OUTPUT -j %s", a.chain))
+	}
+	if haveMasquerade {
+		restore = append(restore, fmt.Sprintf("-D POSTROUTING -o %s -j MASQUERADE", a.vpnInterface))
+	}
+	if haveChain {
+		restore = append(restore, fmt.Sprintf("-F %s", a.chain), fmt.Sprintf("-X %s", a.chain))
+	}
+	if len(restore) == 1 {
+		return nil
+	}
+	restore = append(restore, "COMMIT")
+
+	a.log.Debugw("Removing iptables chain from kernel", "chain", a.chain)
+	if err := execRestore(restore); err != nil {
+		return fmt.Errorf("failed to remove iptable rules: %v", err)
+	}
+	return nil
+}
+
+// restoreLines returns the lines of `iptables-save`-file representing the
+// rules in the chain.
+func restoreLines(chain string, rules []*dnatRule) []string {
+	lines := []string{}
+	for _, rule := range rules {
+		lines = append(lines, rule.RestoreLine(chain))
+	}
+	return lines
+}
+
+// applyRules creates a iptables-save file and pipes it to stdin of
+// a iptables-restore process for atomically setting new rules.
+// This function replaces a complete chain (removing all pre-existing rules).
+func (a *iptablesApplier) applyDNATRules(rules []string, haveJump, haveMasquerade bool) error {
+	restore := []string{
+		"*nat",
+		fmt.Sprintf(":%s - [0:0]", a.chain)}
+
+	if !haveJump {
+		restore = append(restore,
+			fmt.Sprintf("-I OUTPUT -j %s", a.chain))
+	}
+
+	if !haveMasquerade {
+		restore = append(restore,
+			fmt.Sprintf("-I POSTROUTING -o %s -j MASQUERADE", a.vpnInterface))
+	}
+
+	restore = append(restore, rules...)
+	restore = append(restore, "COMMIT")
+
+	return execRestore(restore)
+}
+
+func execSave() ([]string, error) {
+	cmd := exec.Command("iptables-save", []string{"-t", "nat"}...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute %q: %v. Output: \n%s", strings.Join(cmd.Args, " "), err, out)
+	}
+	return strings.Split(string(out), "\n"), err
+}
+
+func execRestore(rules []string) error {
+	cmd := exec.Command("iptables-restore", []string{"--noflush", "-v", "-T", "nat"}...)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(stdin, strings.Join(rules, "\n")+"\n"); err != nil {
+		return fmt.Errorf("failed to write to iptables-restore stdin: %v", err)
+	}
+	if err := stdin.Close(); err != nil {
+		return fmt.Errorf("failed to close iptables-restore stdin: %v", err)
+	}
+
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	if len(out) > 0 {
+		return fmt.Errorf("iptables-restore failed: %v (output: %s)", err, string(out))
+	}
+	return fmt.Errorf("iptables-restore failed: %v", err)
+}
+
+// GetMatchArgs returns iptables arguments to match for the
+// rule's originalTargetAddress and Port.
+func (rule *dnatRule) GetMatchArgs() []string {
+	return []string{
+		"-d", rule.originalTargetAddress + "/32",
+		"-p", "tcp",
+		"-m", "tcp",
+		"--dport", rule.originalTargetPort,
+	}
+}
+
+// GetTargetArgs returns iptables arguments to specify the
+// rule's target after translation.
+func (rule *dnatRule) GetTargetArgs() []string {
+	var target string
+	if len(rule.translatedAddress) > 0 {
+		target = rule.translatedAddress
+	}
+	target += ":"
+	if len(rule.translatedPort) > 0 {
+		target += rule.translatedPort
+	}
+	if len(target) == 0 {
+		return []string{}
+	}
+	return []string{
+		"-j", "DNAT",
+		"--to-destination", target,
+	}
+}
+
+// RestoreLine returns a line of `iptables-save`-file representing
+// the rule.
+func (rule *dnatRule) RestoreLine(chain string) string {
+	args := []string{"-A", chain}
+	args = append(args, rule.GetMatchArgs()...)
+	args = append(args, rule.GetTargetArgs()...)
+	return strings.Join(args, " ")
+}
+
+// filterDnatRules enumerates through all given rules and returns all
+// rules matching the chain. It also returns two booleans to
+// indicate if the jump and the masquerade rule are present.
+func (a *iptablesApplier) filterDnatRules(rules []string) ([]string, bool, bool) {
+	out := []string{}
+	haveJump := false
+	haveMasquerade := false
+
+	rulePrefix := fmt.Sprintf("-A %s ", a.chain)
+	jumpPattern := fmt.Sprintf("-A OUTPUT -j %s", a.chain)
+	masqPattern := fmt.Sprintf("-A POSTROUTING -o %s -j MASQUERADE", a.vpnInterface)
+	for _, rule := range rules {
+		if rule == jumpPattern {
+			haveJump = true
+		}
+		if rule == masqPattern {
+			haveMasquerade = true
+		}
+		if !strings.HasPrefix(rule, rulePrefix) {
+			continue
+		}
+		out = append(out, rule)
+	}
+	return out, haveJump, haveMasquerade
+}
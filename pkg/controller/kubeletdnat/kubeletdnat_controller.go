@@ -19,9 +19,7 @@ package kubeletdnat
 import (
 	"context"
 	"fmt"
-	"io"
 	"net"
-	"os/exec"
 	"sort"
 	"strconv"
 	"strings"
@@ -32,7 +30,6 @@ import (
 	"k8c.io/kubermatic/v2/pkg/provider"
 
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/client-go/util/workqueue"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -47,7 +44,17 @@ const (
 	ControllerName = "kubermatic_kubelet_dnat_controller"
 )
 
-// Reconciler updates iptable rules to match node addresses.
+// Backend is the kernel interface used to apply the translation rules.
+type Backend string
+
+const (
+	// BackendIPTables applies the rules with iptables-save and iptables-restore.
+	BackendIPTables Backend = "iptables"
+	// BackendNFTables applies the rules to a table of their own with nftables transactions.
+	BackendNFTables Backend = "nftables"
+)
+
+// Reconciler updates the NAT rules to match node addresses.
 // Every node address gets a translation to the respective node-access (vpn) address.
 type Reconciler struct {
 	ctrlruntimeclient.Client
@@ -55,10 +62,21 @@ type Reconciler struct {
 	nodeTranslationChainName string
 	nodeAccessNetwork        net.IP
 	vpnInterface             string
+	applier                  ruleApplier
 
 	log *zap.SugaredLogger
 }
 
+// ruleApplier makes the kernel translate the node addresses.
+type ruleApplier interface {
+	// Apply atomically replaces all translation rules with the given ones and
+	// makes sure that the local traffic goes through them and is masqueraded
+	// on the vpn interface.
+	Apply(rules []*dnatRule) error
+	// Cleanup removes everything applied by the backend from the kernel.
+	Cleanup() error
+}
+
 // dnatRule stores address+port before translation (match) and
 // provides address+port after translation.
 type dnatRule struct {
@@ -74,13 +92,32 @@ func Add(
 	nodeTranslationChainName string,
 	nodeAccessNetwork net.IP,
 	log *zap.SugaredLogger,
-	vpnInterface string) error {
+	vpnInterface string,
+	backend Backend) error {
+
+	applier, err := newRuleApplier(backend, nodeTranslationChainName, vpnInterface, log)
+	if err != nil {
+		return err
+	}
+
+	// The rules of the other backend would still translate the node addresses
+	// after the backend has been switched, even to nodes which are gone.
+	otherBackend := BackendNFTables
+	if backend == BackendNFTables {
+		otherBackend = BackendIPTables
+	}
+	if err := cleanupBackend(otherBackend, nodeTranslationChainName, vpnInterface, log); err != nil {
+		// The other backend might not be available at all, in which case it
+		// has nothing to clean up either.
+		log.Warnw("Failed to remove the rules of the previous backend", "backend", otherBackend, zap.Error(err))
+	}
 
 	reconciler := &Reconciler{
 		Client:                   mgr.GetClient(),
 		nodeTranslationChainName: nodeTranslationChainName,
 		nodeAccessNetwork:        nodeAccessNetwork,
 		vpnInterface:             vpnInterface,
+		applier:                  applier,
 		log:                      log,
 	}
 
@@ -127,19 +164,43 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 	return reconcile.Result{}, err
 }
 
-func (r *Reconciler) getDesiredRules(nodes []corev1.Node) []string {
-	rules := []string{}
+// newRuleApplier returns the rule applier of the backend.
+func newRuleApplier(backend Backend, chain, vpnInterface string, log *zap.SugaredLogger) (ruleApplier, error) {
+	switch backend {
+	case BackendIPTables:
+		return &iptablesApplier{chain: chain, vpnInterface: vpnInterface, log: log}, nil
+	case BackendNFTables:
+		return newNFTablesApplier(chain, vpnInterface)
+	default:
+		return nil, fmt.Errorf("unknown backend %q, must be one of %q or %q", backend, BackendIPTables, BackendNFTables)
+	}
+}
+
+// cleanupBackend removes the rules applied by the backend.
+func cleanupBackend(backend Backend, chain, vpnInterface string, log *zap.SugaredLogger) error {
+	applier, err := newRuleApplier(backend, chain, vpnInterface, log)
+	if err != nil {
+		return err
+	}
+	return applier.Cleanup()
+}
+
+func (r *Reconciler) getDesiredRules(nodes []corev1.Node) []*dnatRule {
+	rules := []*dnatRule{}
 	for _, node := range nodes {
 		nodeRules, err := r.getRulesForNode(node)
 		if err != nil {
 			r.log.Errorw("could not generate rules for node, skipping", "node", node.Name, zap.Error(err))
 			continue
 		}
-		for _, rule := range nodeRules {
-			rules = append(rules, rule.RestoreLine(r.nodeTranslationChainName))
-		}
+		rules = append(rules, nodeRules...)
 	}
-	sort.Strings(rules)
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].originalTargetAddress != rules[j].originalTargetAddress {
+			return rules[i].originalTargetAddress < rules[j].originalTargetAddress
+		}
+		return rules[i].originalTargetPort < rules[j].originalTargetPort
+	})
 	return rules
 }
 
@@ -155,20 +216,8 @@ func (r *Reconciler) syncDnatRules(ctx context.Context) error {
 	// Create the set of rules from all listed nodes.
 	desiredRules := r.getDesiredRules(nodeList.Items)
 
-	// Get the actual state (current iptable rules)
-	allActualRules, err := execSave()
-	if err != nil {
-		return fmt.Errorf("failed to read iptable rules: %v", err)
-	}
-	// filter out everything that's not relevant for us
-	actualRules, haveJump, haveMasquerade := r.filterDnatRules(allActualRules, r.nodeTranslationChainName)
-
-	if !equality.Semantic.DeepEqual(actualRules, desiredRules) || !haveJump || !haveMasquerade {
-		// Need to update chain in kernel.
-		r.log.Debugw("Updating iptables chain in kernel", "rules-count", len(desiredRules))
-		if err := r.applyDNATRules(desiredRules, haveJump, haveMasquerade); err != nil {
-			return fmt.Errorf("failed to apply iptable rules: %v", err)
-		}
+	if err := r.applier.Apply(desiredRules); err != nil {
+		return fmt.Errorf("failed to apply rules: %v", err)
 	}
 
 	return nil
@@ -237,126 +286,3 @@ func (r *Reconciler) getRulesForNode(node corev1.Node) ([]*dnatRule, error) {
 	}
 	return rules, nil
 }
-
-// applyRules creates a iptables-save file and pipes it to stdin of
-// a iptables-restore process for atomically setting new rules.
-// This function replaces a complete chain (removing all pre-existing rules).
-func (r *Reconciler) applyDNATRules(rules []string, haveJump, haveMasquerade bool) error {
-	restore := []string{
-		"*nat",
-		fmt.Sprintf(":%s - [0:0]", r.nodeTranslationChainName)}
-
-	if !haveJump {
-		restore = append(restore,
-			fmt.Sprintf("-I OUTPUT -j %s", r.nodeTranslationChainName))
-	}
-
-	if !haveMasquerade {
-		restore = append(restore,
-			fmt.Sprintf("-I POSTROUTING -o %s -j MASQUERADE", r.vpnInterface))
-	}
-
-	restore = append(restore, rules...)
-	restore = append(restore, "COMMIT")
-
-	return execRestore(restore)
-}
-
-func execSave() ([]string, error) {
-	cmd := exec.Command("iptables-save", []string{"-t", "nat"}...)
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute %q: %v. Output: \n%s", strings.Join(cmd.Args, " "), err, out)
-	}
-	return strings.Split(string(out), "\n"), err
-}
-
-func execRestore(rules []string) error {
-	cmd := exec.Command("iptables-restore", []string{"--noflush", "-v", "-T", "nat"}...)
-
-	stdin, err := cmd.StdinPipe()
-	if err != nil {
-		return err
-	}
-	if _, err := io.WriteString(stdin, strings.Join(rules, "\n")+"\n"); err != nil {
-		return fmt.Errorf("failed to write to iptables-restore stdin: %v", err)
-	}
-	if err := stdin.Close(); err != nil {
-		return fmt.Errorf("failed to close iptables-restore stdin: %v", err)
-	}
-
-	out, err := cmd.CombinedOutput()
-	if err == nil {
-		return nil
-	}
-	if len(out) > 0 {
-		return fmt.Errorf("iptables-restore failed: %v (output: %s)", err, string(out))
-	}
-	return fmt.Errorf("iptables-restore failed: %v", err)
-}
-
-// GetMatchArgs returns iptables arguments to match for the
-// rule's originalTargetAddress and Port.
-func (rule *dnatRule) GetMatchArgs() []string {
-	return []string{
-		"-d", rule.originalTargetAddress + "/32",
-		"-p", "tcp",
-		"-m", "tcp",
-		"--dport", rule.originalTargetPort,
-	}
-}
-
-// GetTargetArgs returns iptables arguments to specify the
-// rule's target after translation.
-func (rule *dnatRule) GetTargetArgs() []string {
-	var target string
-	if len(rule.translatedAddress) > 0 {
-		target = rule.translatedAddress
-	}
-	target += ":"
-	if len(rule.translatedPort) > 0 {
-		target += rule.translatedPort
-	}
-	if len(target) == 0 {
-		return []string{}
-	}
-	return []string{
-		"-j", "DNAT",
-		"--to-destination", target,
-	}
-}
-
-// RestoreLine returns a line of `iptables-save`-file representing
-// the rule.
-func (rule *dnatRule) RestoreLine(chain string) string {
-	args := []string{"-A", chain}
-	args = append(args, rule.GetMatchArgs()...)
-	args = append(args, rule.GetTargetArgs()...)
-	return strings.Join(args, " ")
-}
-
-// filterDnatRules enumerates through all given rules and returns all
-// rules matching the given chain. It also returns two booleans to
-// indicate if the jump and the masquerade rule are present.
-func (r *Reconciler) filterDnatRules(rules []string, chain string) ([]string, bool, bool) {
-	out := []string{}
-	haveJump := false
-	haveMasquerade := false
-
-	rulePrefix := fmt.Sprintf("-A %s ", chain)
-	jumpPattern := fmt.Sprintf("-A OUTPUT -j %s", chain)
-	masqPattern := fmt.Sprintf("-A POSTROUTING -o %s -j MASQUERADE", r.vpnInterface)
-	for _, rule := range rules {
-		if rule == jumpPattern {
-			haveJump = true
-		}
-		if rule == masqPattern {
-			haveMasquerade = true
-		}
-		if !strings.HasPrefix(rule, rulePrefix) {
-			continue
-		}
-		out = append(out, rule)
-	}
-	return out, haveJump, haveMasquerade
-}
@@ -63,7 +63,7 @@ func TestRuleGeneration(t *testing.T) {
 		},
 	}
 
-	rules := ctrl.getDesiredRules(nodes)
+	rules := restoreLines(ctrl.nodeTranslationChainName, ctrl.getDesiredRules(nodes))
 
 	expectedRules := []string{
 		"-A test-chain -d 10.1.1.11/32 -p tcp -m tcp --dport 10250 -j DNAT --to-destination 10.254.1.11:10250",
@@ -0,0 +1,207 @@
+// +build linux
+
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeletdnat
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/google/nftables"
+	"github.com/google/nftables/expr"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// nftOutputChain and nftPostroutingChain are the base chains of the
+	// table, hooked like the OUTPUT and POSTROUTING chains of the nat table.
+	nftOutputChain      = "output"
+	nftPostroutingChain = "postrouting"
+)
+
+// nftablesApplier applies the rules to a table of its own holding the
+// translation chain along with the base chains jumping to it and masquerading
+// the traffic on the vpn interface. The table is replaced as a whole in a
+// single netlink transaction, so the kernel never sees a partial rule set.
+// As the table is small and only replaced when the node addresses change, it
+// is not compared with the one in the kernel beforehand.
+type nftablesApplier struct {
+	table        *nftables.Table
+	chain        string
+	vpnInterface string
+
+	// connOptions are passed to the netlink connection, tests use them to
+	// intercept the messages.
+	connOptions []nftables.ConnOption
+}
+
+func newNFTablesApplier(chain, vpnInterface string) (ruleApplier, error) {
+	if len(vpnInterface) >= unix.IFNAMSIZ {
+		return nil, fmt.Errorf("interface name %q is longer than %d characters", vpnInterface, unix.IFNAMSIZ-1)
+	}
+	return &nftablesApplier{
+		table:        &nftables.Table{Name: chain, Family: nftables.TableFamilyIPv4},
+		chain:        chain,
+		vpnInterface: vpnInterface,
+	}, nil
+}
+
+func (a *nftablesApplier) Apply(rules []*dnatRule) error {
+	chains, nftRules, err := a.ruleset(rules)
+	if err != nil {
+		return err
+	}
+
+	conn, err := nftables.New(a.connOptions...)
+	if err != nil {
+		return fmt.Errorf("failed to create nftables connection: %v", err)
+	}
+	a.deleteTable(conn)
+	conn.AddTable(a.table)
+	for _, chain := range chains {
+		conn.AddChain(chain)
+	}
+	for _, rule := range nftRules {
+		conn.AddRule(rule)
+	}
+	if err := conn.Flush(); err != nil {
+		return fmt.Errorf("failed to replace nftables table %s: %v", a.table.Name, err)
+	}
+	return nil
+}
+
+func (a *nftablesApplier) Cleanup() error {
+	conn, err := nftables.New(a.connOptions...)
+	if err != nil {
+		return fmt.Errorf("failed to create nftables connection: %v", err)
+	}
+	a.deleteTable(conn)
+	if err := conn.Flush(); err != nil {
+		return fmt.Errorf("failed to delete nftables table %s: %v", a.table.Name, err)
+	}
+	return nil
+}
+
+// deleteTable queues the deletion of the table. Creating the table before
+// deleting it makes the deletion succeed when the table does not exist, all
+// changes are only visible once the batch has been committed.
+func (a *nftablesApplier) deleteTable(conn *nftables.Conn) {
+	conn.AddTable(a.table)
+	conn.DelTable(a.table)
+}
+
+// ruleset returns the chains and the rules of the table.
+func (a *nftablesApplier) ruleset(rules []*dnatRule) ([]*nftables.Chain, []*nftables.Rule, error) {
+	output := &nftables.Chain{
+		Name:     nftOutputChain,
+		Table:    a.table,
+		Type:     nftables.ChainTypeNAT,
+		Hooknum:  nftables.ChainHookOutput,
+		Priority: nftables.ChainPriorityNATDest,
+	}
+	postrouting := &nftables.Chain{
+		Name:     nftPostroutingChain,
+		Table:    a.table,
+		Type:     nftables.ChainTypeNAT,
+		Hooknum:  nftables.ChainHookPostrouting,
+		Priority: nftables.ChainPriorityNATSource,
+	}
+	translation := &nftables.Chain{Name: a.chain, Table: a.table}
+
+	ifname := make([]byte, unix.IFNAMSIZ)
+	copy(ifname, a.vpnInterface)
+	nftRules := []*nftables.Rule{
+		{
+			Table: a.table,
+			Chain: output,
+			Exprs: []expr.Any{&expr.Verdict{Kind: expr.VerdictJump, Chain: a.chain}},
+		},
+		{
+			Table: a.table,
+			Chain: postrouting,
+			Exprs: []expr.Any{
+				&expr.Meta{Key: expr.MetaKeyOIFNAME, Register: 1},
+				&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: ifname},
+				&expr.Masq{},
+			},
+		},
+	}
+	for _, rule := range rules {
+		exprs, err := rule.nftExprs()
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid rule %q: %v", rule.RestoreLine(a.chain), err)
+		}
+		nftRules = append(nftRules, &nftables.Rule{Table: a.table, Chain: translation, Exprs: exprs})
+	}
+	return []*nftables.Chain{output, postrouting, translation}, nftRules, nil
+}
+
+// nftExprs returns the expressions matching the original address and port
+// of the rule and translating them.
+func (rule *dnatRule) nftExprs() ([]expr.Any, error) {
+	daddr, err := parseIPv4(rule.originalTargetAddress)
+	if err != nil {
+		return nil, err
+	}
+	dport, err := parsePort(rule.originalTargetPort)
+	if err != nil {
+		return nil, err
+	}
+	addr, err := parseIPv4(rule.translatedAddress)
+	if err != nil {
+		return nil, err
+	}
+	port, err := parsePort(rule.translatedPort)
+	if err != nil {
+		return nil, err
+	}
+
+	return []expr.Any{
+		&expr.Meta{Key: expr.MetaKeyL4PROTO, Register: 1},
+		&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{unix.IPPROTO_TCP}},
+		// ip daddr
+		&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseNetworkHeader, Offset: 16, Len: 4},
+		&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: daddr},
+		// tcp dport
+		&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseTransportHeader, Offset: 2, Len: 2},
+		&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: dport},
+		&expr.Immediate{Register: 1, Data: addr},
+		&expr.Immediate{Register: 2, Data: port},
+		&expr.NAT{Type: expr.NATTypeDestNAT, Family: unix.NFPROTO_IPV4, RegAddrMin: 1, RegProtoMin: 2},
+	}, nil
+}
+
+func parseIPv4(address string) ([]byte, error) {
+	ip := net.ParseIP(address).To4()
+	if ip == nil {
+		return nil, fmt.Errorf("%q is not an IPv4 address", address)
+	}
+	return ip, nil
+}
+
+func parsePort(port string) ([]byte, error) {
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port %q: %v", port, err)
+	}
+	b := make([]byte, 2)
+	binary.BigEndian.PutUint16(b, uint16(p))
+	return b, nil
+}
@@ -0,0 +1,158 @@
+// +build linux
+
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeletdnat
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/google/nftables"
+	"github.com/google/nftables/expr"
+	"github.com/mdlayher/netlink"
+	"golang.org/x/sys/unix"
+)
+
+func TestNFTablesRuleGeneration(t *testing.T) {
+	applier, err := newNFTablesApplier("test-chain", "tun0")
+	if err != nil {
+		t.Fatalf("failed to create applier: %v", err)
+	}
+	rules := []*dnatRule{
+		{originalTargetAddress: "10.1.1.11", originalTargetPort: "10250", translatedAddress: "10.254.1.11", translatedPort: "10250"},
+		{originalTargetAddress: "192.0.2.101", originalTargetPort: "10250", translatedAddress: "10.254.1.11", translatedPort: "10250"},
+	}
+
+	chains, nftRules, err := applier.(*nftablesApplier).ruleset(rules)
+	if err != nil {
+		t.Fatalf("failed to generate ruleset: %v", err)
+	}
+
+	table := &nftables.Table{Name: "test-chain", Family: nftables.TableFamilyIPv4}
+	output := &nftables.Chain{Name: "output", Table: table, Type: nftables.ChainTypeNAT, Hooknum: nftables.ChainHookOutput, Priority: nftables.ChainPriorityNATDest}
+	postrouting := &nftables.Chain{Name: "postrouting", Table: table, Type: nftables.ChainTypeNAT, Hooknum: nftables.ChainHookPostrouting, Priority: nftables.ChainPriorityNATSource}
+	translation := &nftables.Chain{Name: "test-chain", Table: table}
+	if diff := deep.Equal(chains, []*nftables.Chain{output, postrouting, translation}); diff != nil {
+		t.Errorf("unexpected chains: %v", diff)
+	}
+
+	dnat := func(daddr, addr []byte) []expr.Any {
+		return []expr.Any{
+			&expr.Meta{Key: expr.MetaKeyL4PROTO, Register: 1},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{unix.IPPROTO_TCP}},
+			&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseNetworkHeader, Offset: 16, Len: 4},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: daddr},
+			&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseTransportHeader, Offset: 2, Len: 2},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{0x28, 0x0a}},
+			&expr.Immediate{Register: 1, Data: addr},
+			&expr.Immediate{Register: 2, Data: []byte{0x28, 0x0a}},
+			&expr.NAT{Type: expr.NATTypeDestNAT, Family: unix.NFPROTO_IPV4, RegAddrMin: 1, RegProtoMin: 2},
+		}
+	}
+	expectedRules := []*nftables.Rule{
+		{Table: table, Chain: output, Exprs: []expr.Any{&expr.Verdict{Kind: expr.VerdictJump, Chain: "test-chain"}}},
+		{Table: table, Chain: postrouting, Exprs: []expr.Any{
+			&expr.Meta{Key: expr.MetaKeyOIFNAME, Register: 1},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte("tun0\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00")},
+			&expr.Masq{},
+		}},
+		{Table: table, Chain: translation, Exprs: dnat([]byte{10, 1, 1, 11}, []byte{10, 254, 1, 11})},
+		{Table: table, Chain: translation, Exprs: dnat([]byte{192, 0, 2, 101}, []byte{10, 254, 1, 11})},
+	}
+	if diff := deep.Equal(nftRules, expectedRules); diff != nil {
+		t.Errorf("unexpected rules: %v", diff)
+	}
+}
+
+func TestNFTablesInvalidRule(t *testing.T) {
+	applier, err := newNFTablesApplier("test-chain", "tun0")
+	if err != nil {
+		t.Fatalf("failed to create applier: %v", err)
+	}
+	rules := []*dnatRule{
+		{originalTargetAddress: "2001:db8::1", originalTargetPort: "10250", translatedAddress: "10.254.1.11", translatedPort: "10250"},
+	}
+	if err := applier.Apply(rules); err == nil {
+		t.Error("expected an error for an IPv6 address, got none")
+	}
+}
+
+func TestNFTablesTransactions(t *testing.T) {
+	nft := func(typ int) netlink.HeaderType { return netlink.HeaderType(unix.NFNL_SUBSYS_NFTABLES<<8 | typ) }
+	rules := []*dnatRule{
+		{originalTargetAddress: "10.1.1.11", originalTargetPort: "10250", translatedAddress: "10.254.1.11", translatedPort: "10250"},
+	}
+
+	testCases := []struct {
+		name          string
+		apply         func(a *nftablesApplier) error
+		expectedTypes []netlink.HeaderType
+	}{
+		{
+			name:  "apply replaces the table",
+			apply: func(a *nftablesApplier) error { return a.Apply(rules) },
+			expectedTypes: []netlink.HeaderType{
+				unix.NFNL_MSG_BATCH_BEGIN,
+				nft(unix.NFT_MSG_NEWTABLE),
+				nft(unix.NFT_MSG_DELTABLE),
+				nft(unix.NFT_MSG_NEWTABLE),
+				nft(unix.NFT_MSG_NEWCHAIN),
+				nft(unix.NFT_MSG_NEWCHAIN),
+				nft(unix.NFT_MSG_NEWCHAIN),
+				nft(unix.NFT_MSG_NEWRULE),
+				nft(unix.NFT_MSG_NEWRULE),
+				nft(unix.NFT_MSG_NEWRULE),
+				unix.NFNL_MSG_BATCH_END,
+			},
+		},
+		{
+			name:  "cleanup deletes the table",
+			apply: func(a *nftablesApplier) error { return a.Cleanup() },
+			expectedTypes: []netlink.HeaderType{
+				unix.NFNL_MSG_BATCH_BEGIN,
+				nft(unix.NFT_MSG_NEWTABLE),
+				nft(unix.NFT_MSG_DELTABLE),
+				unix.NFNL_MSG_BATCH_END,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var sent []netlink.HeaderType
+			applier, err := newNFTablesApplier("test-chain", "tun0")
+			if err != nil {
+				t.Fatalf("failed to create applier: %v", err)
+			}
+			a := applier.(*nftablesApplier)
+			a.connOptions = []nftables.ConnOption{nftables.WithTestDial(func(req []netlink.Message) ([]netlink.Message, error) {
+				for _, msg := range req {
+					sent = append(sent, msg.Header.Type)
+				}
+				return req, nil
+			})}
+
+			if err := tc.apply(a); err != nil {
+				t.Fatalf("failed to send the transaction: %v", err)
+			}
+			if diff := deep.Equal(sent, tc.expectedTypes); diff != nil {
+				t.Errorf("unexpected messages: %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package groupprojectbindingsync
+
+import (
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources/reconciling"
+)
+
+func groupProjectBindingCreatorGetter(groupProjectBinding *kubermaticv1.GroupProjectBinding) reconciling.NamedKubermaticV1GroupProjectBindingCreatorGetter {
+	return func() (string, reconciling.KubermaticV1GroupProjectBindingCreator) {
+		return groupProjectBinding.Name, func(p *kubermaticv1.GroupProjectBinding) (*kubermaticv1.GroupProjectBinding, error) {
+			p.Name = groupProjectBinding.Name
+			p.Labels = groupProjectBinding.Labels
+			p.Spec = groupProjectBinding.Spec
+			return p, nil
+		}
+	}
+}
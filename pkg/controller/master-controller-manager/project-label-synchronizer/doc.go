@@ -17,6 +17,7 @@ limitations under the License.
 /*
 Package projectlabelsynchronizer contains a controller that synchronizes labels from a project
 onto all the clusters that belong to the project, without allowing them to be overridden. This
-is used to allow filtering clusters by projects.
+is used to allow filtering clusters by projects. The update window of the project is synchronized
+as well, it applies to all clusters which do not have an update window of their own.
 */
 package projectlabelsynchronizer
@@ -27,6 +27,7 @@ import (
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/util/workerlabel"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
@@ -151,11 +152,6 @@ func (r *reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, requ
 		return fmt.Errorf("failed to get project %s: %v", request.Name, err)
 	}
 
-	if len(project.Labels) == 0 {
-		log.Debug("Project has no labels, nothing to do")
-		return nil
-	}
-
 	workerNameLabelSelectorRequirements, _ := r.workerNameLabelSelector.Requirements()
 	projectLabelRequirement, err := labels.NewRequirement(kubermaticv1.ProjectIDLabelKey, selection.Equals, []string{project.Name})
 	if err != nil {
@@ -186,15 +182,17 @@ func (r *reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, requ
 		filteredClusters := r.filterClustersByProjectID(log, project.Name, unfilteredClusters)
 		for _, cluster := range filteredClusters {
 			log := log.With("cluster", cluster.Name)
-			changed, newClusterLabels := getLabelsForCluster(log, cluster.ObjectMeta.DeepCopy().Labels, project.Labels)
-			if !changed {
-				log.Debug("Labels on cluster are already up to date")
+			oldCluster := cluster.DeepCopy()
+			if changed, newClusterLabels := getLabelsForCluster(log, cluster.ObjectMeta.DeepCopy().Labels, project.Labels); changed {
+				cluster.Labels = newClusterLabels
+				cluster.Status.InheritedLabels = getInheritedLabels(project.Labels)
+			}
+			cluster.Status.InheritedUpdateWindow = project.Spec.UpdateWindow.DeepCopy()
+			if equality.Semantic.DeepEqual(oldCluster, cluster) {
+				log.Debug("Labels and update window of cluster are already up to date")
 				continue
 			}
-			oldCluster := cluster.DeepCopy()
-			cluster.Labels = newClusterLabels
-			cluster.Status.InheritedLabels = getInheritedLabels(project.Labels)
-			log.Debug("Updating labels on cluster")
+			log.Debug("Updating labels and update window of cluster")
 			if err := seedClient.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
 				errs = append(errs, fmt.Errorf("failed to update cluster %q", cluster.Name))
 			}
@@ -229,9 +227,9 @@ func getLabelsForCluster(
 	clusterLabels map[string]string,
 	projectLabels map[string]string,
 ) (changed bool, newClusterLabels map[string]string) {
-	// They shouldn't be nil as we skip projects without labels
-	// and need a label on the cluster to associate it to a project
-	// but better be safe than panicking.
+	// The project labels are nil once all of them were removed, the removals are
+	// synced nevertheless. The cluster labels shouldn't be nil as we need a label
+	// on the cluster to associate it to a project, but better be safe than panicking.
 	if clusterLabels == nil {
 		clusterLabels = map[string]string{}
 	}
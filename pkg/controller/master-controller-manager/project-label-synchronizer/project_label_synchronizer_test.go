@@ -42,6 +42,8 @@ func TestReconciliation(t *testing.T) {
 		// expectedLabels is a map clustername -> LabelMap
 		expectedLabels          map[string]map[string]string
 		expectedInheritedLabels map[string]map[string]string
+		// expectedUpdateWindows is a map clustername -> inherited update window
+		expectedUpdateWindows map[string]*kubermaticv1.UpdateWindow
 	}{
 		{
 			name:         "Label gets set on matching projectID",
@@ -105,6 +107,24 @@ func TestReconciliation(t *testing.T) {
 				kubermaticv1.ProjectIDLabelKey: projectName,
 			}},
 		},
+		{
+			name: "Update window is inherited",
+			masterClient: fakectrlruntimeclient.NewClientBuilder().WithObjects(&kubermaticv1.Project{
+				ObjectMeta: metav1.ObjectMeta{Name: projectName},
+				Spec: kubermaticv1.ProjectSpec{
+					UpdateWindow: &kubermaticv1.UpdateWindow{Schedule: "0 2 * * 1-5", Length: "2h"},
+				},
+			}).Build(),
+			seedClient: namedClusterWithLabels("baz", map[string]string{
+				kubermaticv1.ProjectIDLabelKey: projectName,
+			}),
+			expectedLabels: map[string]map[string]string{"baz": {
+				kubermaticv1.ProjectIDLabelKey: projectName,
+			}},
+			expectedUpdateWindows: map[string]*kubermaticv1.UpdateWindow{
+				"baz": {Schedule: "0 2 * * 1-5", Length: "2h"},
+			},
+		},
 		{
 			name: "Absent project is handled gracefully",
 		},
@@ -148,6 +168,10 @@ func TestReconciliation(t *testing.T) {
 				if diff := deep.Equal(cluster.Status.InheritedLabels, tc.expectedInheritedLabels[cluster.Name]); diff != nil {
 					t.Errorf("Expected inherited labels on cluster %q do not match actual inherited labels, diff: %v", cluster.Name, diff)
 				}
+
+				if diff := deep.Equal(cluster.Status.InheritedUpdateWindow, tc.expectedUpdateWindows[cluster.Name]); diff != nil {
+					t.Errorf("Expected inherited update window on cluster %q does not match actual inherited update window, diff: %v", cluster.Name, diff)
+				}
 			}
 		})
 	}
@@ -0,0 +1,183 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package projectresourceusage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	controllerutil "k8c.io/kubermatic/v2/pkg/controller/util"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/quota"
+	"k8c.io/kubermatic/v2/pkg/util/workerlabel"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const ControllerName = "kubermatic_project_resource_usage"
+
+type reconciler struct {
+	log                     *zap.SugaredLogger
+	masterClient            ctrlruntimeclient.Client
+	seedClients             map[string]ctrlruntimeclient.Client
+	workerNameLabelSelector labels.Selector
+}
+
+// requestFromCluster returns a reconcile.Request for the project the given
+// cluster belongs to, if any.
+func requestFromCluster(log *zap.SugaredLogger) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(mo ctrlruntimeclient.Object) []reconcile.Request {
+		cluster, ok := mo.(*kubermaticv1.Cluster)
+		if !ok {
+			err := fmt.Errorf("Object was not a cluster but a %T", mo)
+			log.Error(err)
+			utilruntime.HandleError(err)
+			return nil
+		}
+
+		projectID, ok := cluster.Labels[kubermaticv1.ProjectIDLabelKey]
+		if !ok {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: projectID}}}
+	})
+}
+
+func Add(
+	ctx context.Context,
+	masterManager manager.Manager,
+	seedManagers map[string]manager.Manager,
+	log *zap.SugaredLogger,
+	numWorkers int,
+	workerName string,
+) error {
+	workerSelector, err := workerlabel.LabelSelector(workerName)
+	if err != nil {
+		return fmt.Errorf("failed to build worker-name selector: %v", err)
+	}
+
+	log = log.Named(ControllerName)
+	r := &reconciler{
+		log:                     log,
+		masterClient:            masterManager.GetClient(),
+		seedClients:             map[string]ctrlruntimeclient.Client{},
+		workerNameLabelSelector: workerSelector,
+	}
+
+	c, err := controller.New(ControllerName, masterManager, controller.Options{Reconciler: r, MaxConcurrentReconciles: numWorkers})
+	if err != nil {
+		return fmt.Errorf("failed to construct controller: %v", err)
+	}
+
+	for seedName, seedManager := range seedManagers {
+		r.seedClients[seedName] = seedManager.GetClient()
+
+		seedClusterWatch := &source.Kind{Type: &kubermaticv1.Cluster{}}
+		if err := seedClusterWatch.InjectCache(seedManager.GetCache()); err != nil {
+			return fmt.Errorf("failed to inject cache for seed %q into watch: %v", seedName, err)
+		}
+		if err := c.Watch(seedClusterWatch, requestFromCluster(log), workerlabel.Predicates(workerName)); err != nil {
+			return fmt.Errorf("failed to watch clusters in seed %q: %v", seedName, err)
+		}
+	}
+
+	if err := c.Watch(&source.Kind{Type: &kubermaticv1.Project{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return fmt.Errorf("failed to watch projects: %v", err)
+	}
+
+	return nil
+}
+
+func (r *reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	log := r.log.With(kubermaticv1.ProjectIDLabelKey, request.Name)
+	log.Debug("Processing")
+
+	err := r.reconcile(ctx, log, request)
+	if controllerutil.IsCacheNotStarted(err) {
+		return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+	if err != nil {
+		log.Errorw("ReconcilingError", zap.Error(err))
+	}
+	return reconcile.Result{}, err
+}
+
+func (r *reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, request reconcile.Request) error {
+	project := &kubermaticv1.Project{}
+	if err := r.masterClient.Get(ctx, request.NamespacedName, project); err != nil {
+		if kerrors.IsNotFound(err) {
+			log.Debug("Didn't find project, returning")
+			return nil
+		}
+		return err
+	}
+
+	workerNameLabelSelectorRequirements, _ := r.workerNameLabelSelector.Requirements()
+	projectLabelRequirement, err := labels.NewRequirement(kubermaticv1.ProjectIDLabelKey, selection.Equals, []string{project.Name})
+	if err != nil {
+		return fmt.Errorf("failed to construct label requirement for project: %v", err)
+	}
+	listOpts := &ctrlruntimeclient.ListOptions{
+		LabelSelector: labels.NewSelector().Add(append(workerNameLabelSelectorRequirements, *projectLabelRequirement)...),
+	}
+
+	// A partial sum would let the clusters of the project exceed its quota, so the usage is only
+	// updated once the clusters of all seeds could be listed.
+	usage := kubermaticv1.ResourceUsage{}
+	for seedName, seedClient := range r.seedClients {
+		clusters := &kubermaticv1.ClusterList{}
+		if err := seedClient.List(ctx, clusters, listOpts); err != nil {
+			if controllerutil.IsCacheNotStarted(err) {
+				return err
+			}
+			return fmt.Errorf("failed to list clusters in seed %q: %v", seedName, err)
+		}
+
+		for i := range clusters.Items {
+			clusterUsage, err := quota.CachedClusterUsage(&clusters.Items[i])
+			if err != nil {
+				return fmt.Errorf("failed to get the resource usage of cluster %q in seed %q: %v", clusters.Items[i].Name, seedName, err)
+			}
+			usage.Add(clusterUsage)
+		}
+	}
+
+	if project.Status.ResourceUsage != nil && equality.Semantic.DeepEqual(*project.Status.ResourceUsage, usage) {
+		return nil
+	}
+
+	oldProject := project.DeepCopy()
+	project.Status.ResourceUsage = &usage
+	if err := r.masterClient.Patch(ctx, project, ctrlruntimeclient.MergeFrom(oldProject)); err != nil {
+		return fmt.Errorf("failed to update the resource usage of the project: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package projectresourceusage
+
+import (
+	"context"
+	"testing"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const projectName = "resource-usage-test"
+
+func TestReconciliation(t *testing.T) {
+	testCases := []struct {
+		name          string
+		seedClusters  map[string][]ctrlruntimeclient.Object
+		expectedUsage kubermaticv1.ResourceUsage
+	}{
+		{
+			name:          "project without clusters",
+			seedClusters:  map[string][]ctrlruntimeclient.Object{"first": nil},
+			expectedUsage: kubermaticv1.ResourceUsage{},
+		},
+		{
+			name: "usage of the clusters of the project in all seeds is aggregated",
+			seedClusters: map[string][]ctrlruntimeclient.Object{
+				"first": {
+					genCluster("a", projectName, &kubermaticv1.ResourceUsage{Clusters: 1, Nodes: 2, CPU: resource.MustParse("4"), Memory: resource.MustParse("8Gi")}),
+					genCluster("b", projectName, nil),
+					genCluster("c", "other-project", &kubermaticv1.ResourceUsage{Clusters: 1, Nodes: 10, CPU: resource.MustParse("40")}),
+				},
+				"second": {
+					genCluster("d", projectName, &kubermaticv1.ResourceUsage{Clusters: 1, Nodes: 1, CPU: resource.MustParse("2"), Storage: resource.MustParse("20Gi")}),
+				},
+			},
+			expectedUsage: kubermaticv1.ResourceUsage{
+				Clusters: 3,
+				Nodes:    3,
+				CPU:      resource.MustParse("6"),
+				Memory:   resource.MustParse("8Gi"),
+				Storage:  resource.MustParse("20Gi"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			project := &kubermaticv1.Project{ObjectMeta: metav1.ObjectMeta{Name: projectName}}
+			masterClient := fakectrlruntimeclient.NewClientBuilder().WithObjects(project).Build()
+
+			seedClients := map[string]ctrlruntimeclient.Client{}
+			for seedName, clusters := range tc.seedClusters {
+				seedClients[seedName] = fakectrlruntimeclient.NewClientBuilder().WithObjects(clusters...).Build()
+			}
+
+			r := &reconciler{
+				log:                     kubermaticlog.Logger,
+				masterClient:            masterClient,
+				seedClients:             seedClients,
+				workerNameLabelSelector: labels.Everything(),
+			}
+
+			request := reconcile.Request{NamespacedName: types.NamespacedName{Name: projectName}}
+			if _, err := r.Reconcile(ctx, request); err != nil {
+				t.Fatalf("failed reconciling: %v", err)
+			}
+
+			if err := masterClient.Get(ctx, request.NamespacedName, project); err != nil {
+				t.Fatalf("failed to get project: %v", err)
+			}
+			if project.Status.ResourceUsage == nil {
+				t.Fatal("expected the resource usage of the project to be set")
+			}
+			if !equality.Semantic.DeepEqual(*project.Status.ResourceUsage, tc.expectedUsage) {
+				t.Errorf("expected usage %+v, got %+v", tc.expectedUsage, *project.Status.ResourceUsage)
+			}
+		})
+	}
+}
+
+func genCluster(name, projectID string, usage *kubermaticv1.ResourceUsage) *kubermaticv1.Cluster {
+	return &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{kubermaticv1.ProjectIDLabelKey: projectID},
+		},
+		Status: kubermaticv1.ClusterStatus{ResourceUsage: usage},
+	}
+}
@@ -0,0 +1,23 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package projectresourceusage contains a controller that aggregates the resource usage of the clusters of a
+project in all seeds into the status of the project. The usage of every cluster is recorded in its status
+by its user cluster controller manager, so the resource quota of the project can be enforced without
+contacting the user clusters.
+*/
+package projectresourceusage
@@ -478,6 +478,11 @@ func generateRBACRoleBindingForClusterNamespaceResourceAndServiceAccount(cluster
 	return binding
 }
 
+// isProjectMemberKind checks if the given kind binds members to a project
+func isProjectMemberKind(resourceKind string) bool {
+	return resourceKind == kubermaticv1.UserProjectBindingKind || resourceKind == kubermaticv1.GroupProjectBindingKind
+}
+
 // generateVerbsForNamedResource generates a set of verbs for a named resource
 // for example a "cluster" named "beefy-john"
 func generateVerbsForNamedResource(groupName, resourceKind string) ([]string, error) {
@@ -495,8 +500,8 @@ func generateVerbsForNamedResource(groupName, resourceKind string) ([]string, er
 	if strings.HasPrefix(groupName, EditorGroupNamePrefix) && resourceKind == kubermaticv1.ProjectKindName {
 		return []string{"get", "update"}, nil
 	}
-	// special case - editors are not allowed to interact with members of a project (UserProjectBinding)
-	if strings.HasPrefix(groupName, EditorGroupNamePrefix) && resourceKind == kubermaticv1.UserProjectBindingKind {
+	// special case - editors are not allowed to interact with members of a project (UserProjectBinding and GroupProjectBinding)
+	if strings.HasPrefix(groupName, EditorGroupNamePrefix) && isProjectMemberKind(resourceKind) {
 		return nil, nil
 	}
 	// special case - editors are not allowed to interact with service accounts (User)
@@ -512,8 +517,8 @@ func generateVerbsForNamedResource(groupName, resourceKind string) ([]string, er
 	// verbs for editors
 	//
 	// viewers of a named resource
-	// special case - viewers are not allowed to interact with members of a project (UserProjectBinding)
-	if strings.HasPrefix(groupName, ViewerGroupNamePrefix) && resourceKind == kubermaticv1.UserProjectBindingKind {
+	// special case - viewers are not allowed to interact with members of a project (UserProjectBinding and GroupProjectBinding)
+	if strings.HasPrefix(groupName, ViewerGroupNamePrefix) && isProjectMemberKind(resourceKind) {
 		return nil, nil
 	}
 	// special case - viewers are not allowed to interact with service accounts (User)
@@ -533,9 +538,9 @@ func generateVerbsForNamedResource(groupName, resourceKind string) ([]string, er
 func generateVerbsForResource(groupName, resourceKind string) ([]string, error) {
 	// special case - only the owners of a project can manipulate members
 	//
-	if strings.HasPrefix(groupName, OwnerGroupNamePrefix) && resourceKind == kubermaticv1.UserProjectBindingKind {
+	if strings.HasPrefix(groupName, OwnerGroupNamePrefix) && isProjectMemberKind(resourceKind) {
 		return []string{"create"}, nil
-	} else if resourceKind == kubermaticv1.UserProjectBindingKind {
+	} else if isProjectMemberKind(resourceKind) {
 		return nil, nil
 	}
 
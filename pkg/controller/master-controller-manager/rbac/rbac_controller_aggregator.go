@@ -101,6 +101,15 @@ func New(ctx context.Context, metrics *Metrics, mgr manager.Manager, seedManager
 			},
 		},
 
+		{
+			object: &kubermaticv1.GroupProjectBinding{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: kubermaticv1.SchemeGroupVersion.String(),
+					Kind:       kubermaticv1.GroupProjectBindingKind,
+				},
+			},
+		},
+
 		{
 			object: &k8scorev1.Secret{
 				TypeMeta: metav1.TypeMeta{
@@ -18,10 +18,12 @@ package rbac
 
 import (
 	"context"
+	"fmt"
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 
 	"k8s.io/apimachinery/pkg/api/meta"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/util/workqueue"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -79,9 +81,40 @@ func newProjectRBACController(ctx context.Context, metrics *Metrics, mgr manager
 		return err
 	}
 
+	// Watch for changes to ProjectRoles, they apply to all projects
+	err = cc.Watch(&source.Kind{Type: &kubermaticv1.ProjectRole{}}, enqueueAll(c.client, &kubermaticv1.ProjectList{}), workerPredicate)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// enqueueAll enqueues all objects of the given list type
+func enqueueAll(client ctrlruntimeclient.Client, list ctrlruntimeclient.ObjectList) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(_ ctrlruntimeclient.Object) []reconcile.Request {
+		objects := list.DeepCopyObject().(ctrlruntimeclient.ObjectList)
+		if err := client.List(context.Background(), objects); err != nil {
+			utilruntime.HandleError(fmt.Errorf("failed to list %T: %v", objects, err))
+			return nil
+		}
+
+		items, err := meta.ExtractList(objects)
+		if err != nil {
+			utilruntime.HandleError(fmt.Errorf("failed to extract the items of %T: %v", objects, err))
+			return nil
+		}
+
+		requests := []reconcile.Request{}
+		for _, item := range items {
+			if object, ok := item.(ctrlruntimeclient.Object); ok {
+				requests = append(requests, reconcile.Request{NamespacedName: ctrlruntimeclient.ObjectKeyFromObject(object)})
+			}
+		}
+		return requests
+	})
+}
+
 func (c *projectController) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
 	err := c.sync(ctx, req.NamespacedName)
 	if err != nil {
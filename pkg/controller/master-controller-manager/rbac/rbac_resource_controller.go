@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	predicateutil "k8c.io/kubermatic/v2/pkg/controller/util/predicate"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/client-go/util/workqueue"
@@ -38,6 +39,7 @@ type resourcesController struct {
 	metrics               *Metrics
 	projectResources      []projectResource
 	client                ctrlruntimeclient.Client
+	masterClient          ctrlruntimeclient.Client
 	restMapper            meta.RESTMapper
 	providerName          string
 	objectType            ctrlruntimeclient.Object
@@ -56,6 +58,7 @@ func newResourcesControllers(ctx context.Context, metrics *Metrics, mgr manager.
 			metrics:               metrics,
 			projectResources:      resources,
 			client:                mgr.GetClient(),
+			masterClient:          mgr.GetClient(),
 			restMapper:            mgr.GetRESTMapper(),
 			providerName:          "master",
 			objectType:            clonedObject.(ctrlruntimeclient.Object),
@@ -75,6 +78,12 @@ func newResourcesControllers(ctx context.Context, metrics *Metrics, mgr manager.
 		if err = rcc.Watch(&source.Kind{Type: clonedObject.(ctrlruntimeclient.Object)}, &handler.EnqueueRequestForObject{}, predicateutil.Factory(resource.predicate)); err != nil {
 			return nil, err
 		}
+
+		if list, ok := projectRoleListForKind[resource.object.GetObjectKind().GroupVersionKind().Kind]; ok {
+			if err = rcc.Watch(&source.Kind{Type: &kubermaticv1.ProjectRole{}}, enqueueAll(mc.client, list)); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	for seedName, seedManager := range seedManagerMap {
@@ -87,6 +96,7 @@ func newResourcesControllers(ctx context.Context, metrics *Metrics, mgr manager.
 				metrics:               metrics,
 				projectResources:      resources,
 				client:                seedManager.GetClient(),
+				masterClient:          mgr.GetClient(),
 				restMapper:            seedManager.GetRESTMapper(),
 				providerName:          seedName,
 				objectType:            clonedObject.(ctrlruntimeclient.Object),
@@ -106,6 +116,17 @@ func newResourcesControllers(ctx context.Context, metrics *Metrics, mgr manager.
 			if err = rc.Watch(&source.Kind{Type: clonedObject.(ctrlruntimeclient.Object)}, &handler.EnqueueRequestForObject{}, predicateutil.Factory(resource.predicate)); err != nil {
 				return nil, err
 			}
+
+			if list, ok := projectRoleListForKind[resource.object.GetObjectKind().GroupVersionKind().Kind]; ok {
+				// the ProjectRoles live in the master cluster
+				projectRoleSource := &source.Kind{Type: &kubermaticv1.ProjectRole{}}
+				if err := projectRoleSource.InjectCache(mgr.GetCache()); err != nil {
+					return nil, fmt.Errorf("failed to inject cache into projectRoleSource for seed %s: %v", seedName, err)
+				}
+				if err = rc.Watch(projectRoleSource, enqueueAll(c.client, list)); err != nil {
+					return nil, err
+				}
+			}
 		}
 
 		// allControllers = append(allControllers, c)
@@ -77,6 +77,9 @@ func (c *projectController) sync(ctx context.Context, key ctrlruntimeclient.Obje
 	if err := c.ensureClusterRBACRoleBindingForResources(ctx, project.Name); err != nil {
 		return fmt.Errorf("failed to ensure that the RBAC ClusterRoleBindings for the project's resources exists: %v", err)
 	}
+	if err := c.ensureRBACForProjectRoles(ctx, project); err != nil {
+		return fmt.Errorf("failed to ensure that the RBAC for the custom project roles exists: %v", err)
+	}
 	if err := c.ensureRBACRoleForResources(ctx); err != nil {
 		return fmt.Errorf("failed to ensure that the RBAC Roles for the project's resources exists: %v", err)
 	}
@@ -555,6 +558,10 @@ func (c *projectController) ensureProjectCleanup(ctx context.Context, project *k
 		}
 	}
 
+	if err := c.cleanUpRBACForProjectRoles(ctx, project); err != nil {
+		return err
+	}
+
 	kuberneteshelper.RemoveFinalizer(project, CleanupFinalizerName)
 	return c.client.Update(ctx, project)
 }
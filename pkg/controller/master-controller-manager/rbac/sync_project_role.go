@@ -0,0 +1,611 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ProjectRoleLabelKey is the label of the RBAC Roles and Bindings generated for custom project roles,
+	// its value is the name of the ProjectRole
+	ProjectRoleLabelKey = "kubermatic.io/project-role"
+)
+
+// IsBuiltinGroupPrefix returns true if the given group prefix is one of the built-in groups
+// that are not defined by a ProjectRole
+func IsBuiltinGroupPrefix(groupPrefix string) bool {
+	return sets.NewString(AllGroupsPrefixes...).Has(groupPrefix)
+}
+
+// projectRoleResourceForKind maps the kinds of the project's resources to the resource types of the ProjectRoles
+var projectRoleResourceForKind = map[string]kubermaticv1.ProjectRoleResource{
+	kubermaticv1.ClusterKindName: kubermaticv1.ProjectRoleResourceClusters,
+	kubermaticv1.SSHKeyKind:      kubermaticv1.ProjectRoleResourceSSHKeys,
+}
+
+// projectRoleListForKind are the list types of the project's resources whose RBAC depends on the ProjectRoles
+var projectRoleListForKind = map[string]ctrlruntimeclient.ObjectList{
+	kubermaticv1.ClusterKindName: &kubermaticv1.ClusterList{},
+	kubermaticv1.SSHKeyKind:      &kubermaticv1.UserSSHKeyList{},
+}
+
+func listProjectRoles(ctx context.Context, cli ctrlruntimeclient.Client) ([]kubermaticv1.ProjectRole, error) {
+	var roleList kubermaticv1.ProjectRoleList
+	if err := cli.List(ctx, &roleList); err != nil {
+		return nil, fmt.Errorf("failed to list project roles: %v", err)
+	}
+	// the group of a role with a dash in its name would be mistaken for another one, see ExtractGroupPrefix
+	roles := make([]kubermaticv1.ProjectRole, 0, len(roleList.Items))
+	for _, role := range roleList.Items {
+		if !strings.Contains(role.Name, "-") {
+			roles = append(roles, role)
+		}
+	}
+	return roles, nil
+}
+
+// generateVerbsForProjectRoleNamedResource generates a set of verbs a custom project role grants on a named resource,
+// the members of every role can get the project itself
+func generateVerbsForProjectRoleNamedResource(role *kubermaticv1.ProjectRole, kind string) []string {
+	if kind == kubermaticv1.ProjectKindName {
+		return []string{"get"}
+	}
+	resource, ok := projectRoleResourceForKind[kind]
+	if !ok {
+		return nil
+	}
+	return sets.NewString(role.Verbs(resource)...).Intersection(sets.NewString("get", "update", "delete")).List()
+}
+
+// generateVerbsForProjectRoleResource generates verbs a custom project role grants on a resource for example "cluster",
+// that is whether the members can create the resource
+func generateVerbsForProjectRoleResource(role *kubermaticv1.ProjectRole, kind string) []string {
+	resource, ok := projectRoleResourceForKind[kind]
+	if !ok || !role.Allows(resource, kubermaticv1.ProjectRoleVerbCreate) {
+		return nil
+	}
+	return []string{"create"}
+}
+
+// generateVerbsForProjectRoleClusterNamespaceResource generates verbs a custom project role grants on the addons of a cluster
+func generateVerbsForProjectRoleClusterNamespaceResource(role *kubermaticv1.ProjectRole) []string {
+	verbs := sets.NewString(role.Verbs(kubermaticv1.ProjectRoleResourceAddons)...)
+	if verbs.Has("get") {
+		verbs.Insert("list")
+	}
+	return verbs.List()
+}
+
+// generateVerbsForProjectRoleClusterNamespaceNamedResource generates verbs a custom project role grants
+// on the alertmanager of a cluster and its config secret
+func generateVerbsForProjectRoleClusterNamespaceNamedResource(role *kubermaticv1.ProjectRole, kind string) []string {
+	allowed := sets.NewString("get", "update")
+	if kind == secretV1Kind {
+		allowed.Insert("delete")
+	}
+	return sets.NewString(role.Verbs(kubermaticv1.ProjectRoleResourceAlertmanagers)...).Intersection(allowed).List()
+}
+
+func projectRoleLabels(role *kubermaticv1.ProjectRole) map[string]string {
+	return map[string]string{ProjectRoleLabelKey: role.Name}
+}
+
+// ensureClusterRBACForProjectRoles generates ClusterRoles and ClusterRoleBindings for a named resource,
+// which grant the members of the custom project roles the verbs of the roles. The generated objects of
+// roles which no longer grant anything on the resource are removed.
+func ensureClusterRBACForProjectRoles(ctx context.Context, cli ctrlruntimeclient.Client, roles []kubermaticv1.ProjectRole, projectName, objectResource, objectKind string, object metav1.Object) error {
+	oRef := metav1.OwnerReference{
+		APIVersion: kubermaticv1.SchemeGroupVersion.String(),
+		Kind:       objectKind,
+		UID:        object.GetUID(),
+		Name:       object.GetName(),
+	}
+
+	wanted := sets.NewString()
+	for i := range roles {
+		role := &roles[i]
+		verbs := generateVerbsForProjectRoleNamedResource(role, objectKind)
+		if len(verbs) == 0 {
+			continue
+		}
+		groupName := GenerateActualGroupNameFor(projectName, role.Name)
+
+		generatedRole := &rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            generateRBACRoleNameForNamedResource(objectKind, object.GetName(), groupName),
+				Labels:          projectRoleLabels(role),
+				OwnerReferences: []metav1.OwnerReference{oRef},
+			},
+			Rules: []rbacv1.PolicyRule{
+				{
+					APIGroups:     []string{kubermaticv1.SchemeGroupVersion.Group},
+					Resources:     []string{objectResource},
+					ResourceNames: []string{object.GetName()},
+					Verbs:         verbs,
+				},
+			},
+		}
+		if err := ensureClusterRole(ctx, cli, generatedRole); err != nil {
+			return err
+		}
+
+		generatedRoleBinding := generateClusterRBACRoleBindingNamedResource(objectKind, object.GetName(), groupName, oRef)
+		generatedRoleBinding.Labels = projectRoleLabels(role)
+		if err := ensureClusterRoleBinding(ctx, cli, generatedRoleBinding); err != nil {
+			return err
+		}
+		wanted.Insert(generatedRole.Name)
+	}
+
+	// remove the objects of deleted roles or of roles which were changed to grant nothing
+	var roleList rbacv1.ClusterRoleList
+	if err := cli.List(ctx, &roleList, ctrlruntimeclient.HasLabels{ProjectRoleLabelKey}); err != nil {
+		return err
+	}
+	for i := range roleList.Items {
+		if isOwnedBy(&roleList.Items[i], object) && !wanted.Has(roleList.Items[i].Name) {
+			if err := ctrlruntimeclient.IgnoreNotFound(cli.Delete(ctx, &roleList.Items[i])); err != nil {
+				return err
+			}
+		}
+	}
+	var bindingList rbacv1.ClusterRoleBindingList
+	if err := cli.List(ctx, &bindingList, ctrlruntimeclient.HasLabels{ProjectRoleLabelKey}); err != nil {
+		return err
+	}
+	for i := range bindingList.Items {
+		if isOwnedBy(&bindingList.Items[i], object) && !wanted.Has(bindingList.Items[i].Name) {
+			if err := ctrlruntimeclient.IgnoreNotFound(cli.Delete(ctx, &bindingList.Items[i])); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ensureClusterRBACForProjectRolesResource generates a ClusterRole for the given resource for every custom project role
+// that allows to create the resource and binds the members of the project to it.
+// The members of roles which no longer allow it are removed from the bindings.
+func ensureClusterRBACForProjectRolesResource(ctx context.Context, cli ctrlruntimeclient.Client, roles []kubermaticv1.ProjectRole, projectName, resource, kind string) error {
+	for i := range roles {
+		role := &roles[i]
+		groupName := GenerateActualGroupNameFor(projectName, role.Name)
+
+		verbs := generateVerbsForProjectRoleResource(role, kind)
+		if len(verbs) == 0 {
+			if err := removeClusterRBACRoleBindingSubject(ctx, cli, groupName, resource); err != nil {
+				return err
+			}
+			continue
+		}
+
+		generatedRole := &rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   generateRBACRoleNameForResources(resource, groupName),
+				Labels: projectRoleLabels(role),
+			},
+			Rules: []rbacv1.PolicyRule{
+				{
+					APIGroups: []string{kubermaticv1.SchemeGroupVersion.Group},
+					Resources: []string{resource},
+					Verbs:     verbs,
+				},
+			},
+		}
+		if err := ensureClusterRole(ctx, cli, generatedRole); err != nil {
+			return err
+		}
+
+		generatedRoleBinding := generateClusterRBACRoleBindingForResource(resource, groupName)
+		generatedRoleBinding.Labels = projectRoleLabels(role)
+
+		var existingRoleBinding rbacv1.ClusterRoleBinding
+		if err := cli.Get(ctx, ctrlruntimeclient.ObjectKey{Name: generatedRoleBinding.Name}, &existingRoleBinding); err != nil {
+			if !kerrors.IsNotFound(err) {
+				return err
+			}
+			if err := cli.Create(ctx, generatedRoleBinding); err != nil {
+				return err
+			}
+			continue
+		}
+		if hasSubject(existingRoleBinding.Subjects, generatedRoleBinding.Subjects[0]) {
+			continue
+		}
+		updatedRoleBinding := existingRoleBinding.DeepCopy()
+		updatedRoleBinding.Subjects = append(updatedRoleBinding.Subjects, generatedRoleBinding.Subjects[0])
+		if err := cli.Update(ctx, updatedRoleBinding); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cleanUpClusterRBACForProjectRolesResource removes the members of the project from the bindings for the given resource
+// of all custom project roles
+func cleanUpClusterRBACForProjectRolesResource(ctx context.Context, cli ctrlruntimeclient.Client, roles []kubermaticv1.ProjectRole, projectName, resource string) error {
+	for _, role := range roles {
+		if err := removeClusterRBACRoleBindingSubject(ctx, cli, GenerateActualGroupNameFor(projectName, role.Name), resource); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeClusterRBACRoleBindingSubject removes the given group from the ClusterRoleBinding for the given resource, if it exists
+func removeClusterRBACRoleBindingSubject(ctx context.Context, cli ctrlruntimeclient.Client, groupName, resource string) error {
+	generatedRoleBinding := generateClusterRBACRoleBindingForResource(resource, groupName)
+
+	var existingRoleBinding rbacv1.ClusterRoleBinding
+	if err := cli.Get(ctx, ctrlruntimeclient.ObjectKey{Name: generatedRoleBinding.Name}, &existingRoleBinding); err != nil {
+		return ctrlruntimeclient.IgnoreNotFound(err)
+	}
+	if !hasSubject(existingRoleBinding.Subjects, generatedRoleBinding.Subjects[0]) {
+		return nil
+	}
+	return cleanUpClusterRBACRoleBindingFor(ctx, cli, groupName, resource)
+}
+
+// cleanUpClusterRBACForDeletedProjectRoles removes the ClusterRoles and ClusterRoleBindings shared by all projects
+// that were generated for no longer existing custom project roles
+func cleanUpClusterRBACForDeletedProjectRoles(ctx context.Context, cli ctrlruntimeclient.Client, roles []kubermaticv1.ProjectRole) error {
+	existing := sets.NewString()
+	for _, role := range roles {
+		existing.Insert(role.Name)
+	}
+
+	var roleList rbacv1.ClusterRoleList
+	if err := cli.List(ctx, &roleList, ctrlruntimeclient.HasLabels{ProjectRoleLabelKey}); err != nil {
+		return err
+	}
+	for i := range roleList.Items {
+		if len(roleList.Items[i].OwnerReferences) == 0 && !existing.Has(roleList.Items[i].Labels[ProjectRoleLabelKey]) {
+			if err := ctrlruntimeclient.IgnoreNotFound(cli.Delete(ctx, &roleList.Items[i])); err != nil {
+				return err
+			}
+		}
+	}
+	var bindingList rbacv1.ClusterRoleBindingList
+	if err := cli.List(ctx, &bindingList, ctrlruntimeclient.HasLabels{ProjectRoleLabelKey}); err != nil {
+		return err
+	}
+	for i := range bindingList.Items {
+		if len(bindingList.Items[i].OwnerReferences) == 0 && !existing.Has(bindingList.Items[i].Labels[ProjectRoleLabelKey]) {
+			if err := ctrlruntimeclient.IgnoreNotFound(cli.Delete(ctx, &bindingList.Items[i])); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ensureRBACForProjectRolesInClusterNamespace generates Roles and RoleBindings in the cluster namespace which grant
+// the members of the custom project roles access to the addons and the alertmanager of the cluster
+func ensureRBACForProjectRolesInClusterNamespace(ctx context.Context, cli ctrlruntimeclient.Client, roles []kubermaticv1.ProjectRole, projectName string, cluster *kubermaticv1.Cluster) error {
+	namespace := cluster.Status.NamespaceName
+	if namespace == "" {
+		return nil
+	}
+
+	wanted := sets.NewString()
+	ensure := func(role *kubermaticv1.ProjectRole, generatedRole *rbacv1.Role, generatedRoleBinding *rbacv1.RoleBinding) error {
+		generatedRole.Labels = projectRoleLabels(role)
+		generatedRoleBinding.Labels = projectRoleLabels(role)
+		if err := ensureRole(ctx, cli, generatedRole); err != nil {
+			return err
+		}
+		if err := ensureRoleBinding(ctx, cli, generatedRoleBinding); err != nil {
+			return err
+		}
+		wanted.Insert(generatedRole.Name)
+		return nil
+	}
+
+	for i := range roles {
+		role := &roles[i]
+		groupName := GenerateActualGroupNameFor(projectName, role.Name)
+
+		if verbs := generateVerbsForProjectRoleClusterNamespaceResource(role); len(verbs) > 0 {
+			generatedRole := &rbacv1.Role{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      generateRBACRoleNameForClusterNamespaceResource(kubermaticv1.AddonKindName, groupName),
+					Namespace: namespace,
+				},
+				Rules: []rbacv1.PolicyRule{
+					{
+						APIGroups: []string{kubermaticv1.GroupName},
+						Resources: []string{kubermaticv1.AddonResourceName},
+						Verbs:     verbs,
+					},
+				},
+			}
+			generatedRoleBinding := generateRBACRoleBindingForClusterNamespaceResource(cluster, groupName, kubermaticv1.AddonKindName)
+			if err := ensure(role, generatedRole, generatedRoleBinding); err != nil {
+				return err
+			}
+		}
+
+		if cluster.Spec.MLA == nil || !cluster.Spec.MLA.MonitoringEnabled {
+			continue
+		}
+
+		namedResources := []struct {
+			apiGroup string
+			resource string
+			kind     string
+			name     string
+		}{
+			{apiGroup: kubermaticv1.GroupName, resource: kubermaticv1.AlertmanagerResourceName, kind: kubermaticv1.AlertmanagerKindName, name: alertmanagerName},
+			{apiGroup: "", resource: "secrets", kind: secretV1Kind, name: defaultAlertmanagerConfigSecretName},
+		}
+		for _, namedResource := range namedResources {
+			verbs := generateVerbsForProjectRoleClusterNamespaceNamedResource(role, namedResource.kind)
+			if len(verbs) == 0 {
+				continue
+			}
+			generatedRole := &rbacv1.Role{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      generateRBACRoleNameForClusterNamespaceNamedResource(namedResource.kind, namedResource.name, groupName),
+					Namespace: namespace,
+				},
+				Rules: []rbacv1.PolicyRule{
+					{
+						APIGroups:     []string{namedResource.apiGroup},
+						Resources:     []string{namedResource.resource},
+						ResourceNames: []string{namedResource.name},
+						Verbs:         verbs,
+					},
+				},
+			}
+			generatedRoleBinding := generateRBACRoleBindingForClusterNamespaceNamedResource(cluster, groupName, namedResource.kind, namedResource.name)
+			if err := ensure(role, generatedRole, generatedRoleBinding); err != nil {
+				return err
+			}
+		}
+	}
+
+	// remove the objects of deleted roles or of roles which were changed to grant nothing
+	opts := []ctrlruntimeclient.ListOption{ctrlruntimeclient.InNamespace(namespace), ctrlruntimeclient.HasLabels{ProjectRoleLabelKey}}
+	var roleList rbacv1.RoleList
+	if err := cli.List(ctx, &roleList, opts...); err != nil {
+		return err
+	}
+	for i := range roleList.Items {
+		if !wanted.Has(roleList.Items[i].Name) {
+			if err := ctrlruntimeclient.IgnoreNotFound(cli.Delete(ctx, &roleList.Items[i])); err != nil {
+				return err
+			}
+		}
+	}
+	var bindingList rbacv1.RoleBindingList
+	if err := cli.List(ctx, &bindingList, opts...); err != nil {
+		return err
+	}
+	for i := range bindingList.Items {
+		if !wanted.Has(bindingList.Items[i].Name) {
+			if err := ctrlruntimeclient.IgnoreNotFound(cli.Delete(ctx, &bindingList.Items[i])); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func ensureClusterRole(ctx context.Context, cli ctrlruntimeclient.Client, generatedRole *rbacv1.ClusterRole) error {
+	var existingRole rbacv1.ClusterRole
+	if err := cli.Get(ctx, ctrlruntimeclient.ObjectKey{Name: generatedRole.Name}, &existingRole); err != nil {
+		if kerrors.IsNotFound(err) {
+			return cli.Create(ctx, generatedRole)
+		}
+		return err
+	}
+	if equality.Semantic.DeepEqual(existingRole.Rules, generatedRole.Rules) {
+		return nil
+	}
+	updatedRole := existingRole.DeepCopy()
+	updatedRole.Rules = generatedRole.Rules
+	return cli.Update(ctx, updatedRole)
+}
+
+func ensureClusterRoleBinding(ctx context.Context, cli ctrlruntimeclient.Client, generatedRoleBinding *rbacv1.ClusterRoleBinding) error {
+	var existingRoleBinding rbacv1.ClusterRoleBinding
+	if err := cli.Get(ctx, ctrlruntimeclient.ObjectKey{Name: generatedRoleBinding.Name}, &existingRoleBinding); err != nil {
+		if kerrors.IsNotFound(err) {
+			return cli.Create(ctx, generatedRoleBinding)
+		}
+		return err
+	}
+	if equality.Semantic.DeepEqual(existingRoleBinding.Subjects, generatedRoleBinding.Subjects) {
+		return nil
+	}
+	updatedRoleBinding := existingRoleBinding.DeepCopy()
+	updatedRoleBinding.Subjects = generatedRoleBinding.Subjects
+	return cli.Update(ctx, updatedRoleBinding)
+}
+
+func ensureRole(ctx context.Context, cli ctrlruntimeclient.Client, generatedRole *rbacv1.Role) error {
+	var existingRole rbacv1.Role
+	if err := cli.Get(ctx, ctrlruntimeclient.ObjectKey{Name: generatedRole.Name, Namespace: generatedRole.Namespace}, &existingRole); err != nil {
+		if kerrors.IsNotFound(err) {
+			return cli.Create(ctx, generatedRole)
+		}
+		return err
+	}
+	if equality.Semantic.DeepEqual(existingRole.Rules, generatedRole.Rules) {
+		return nil
+	}
+	updatedRole := existingRole.DeepCopy()
+	updatedRole.Rules = generatedRole.Rules
+	return cli.Update(ctx, updatedRole)
+}
+
+func ensureRoleBinding(ctx context.Context, cli ctrlruntimeclient.Client, generatedRoleBinding *rbacv1.RoleBinding) error {
+	var existingRoleBinding rbacv1.RoleBinding
+	if err := cli.Get(ctx, ctrlruntimeclient.ObjectKey{Name: generatedRoleBinding.Name, Namespace: generatedRoleBinding.Namespace}, &existingRoleBinding); err != nil {
+		if kerrors.IsNotFound(err) {
+			return cli.Create(ctx, generatedRoleBinding)
+		}
+		return err
+	}
+	if equality.Semantic.DeepEqual(existingRoleBinding.Subjects, generatedRoleBinding.Subjects) {
+		return nil
+	}
+	updatedRoleBinding := existingRoleBinding.DeepCopy()
+	updatedRoleBinding.Subjects = generatedRoleBinding.Subjects
+	return cli.Update(ctx, updatedRoleBinding)
+}
+
+func isOwnedBy(object metav1.Object, owner metav1.Object) bool {
+	for _, ref := range object.GetOwnerReferences() {
+		if ref.UID == owner.GetUID() {
+			return true
+		}
+	}
+	return false
+}
+
+func hasSubject(subjects []rbacv1.Subject, subject rbacv1.Subject) bool {
+	for _, existing := range subjects {
+		if equality.Semantic.DeepEqual(existing, subject) {
+			return true
+		}
+	}
+	return false
+}
+
+// ensureRBACForProjectRoles generates the RBAC for the project itself and the creation of the project's resources
+// for the members of all custom project roles
+func (c *projectController) ensureRBACForProjectRoles(ctx context.Context, project *kubermaticv1.Project) error {
+	roles, err := listProjectRoles(ctx, c.client)
+	if err != nil {
+		return err
+	}
+
+	if err := ensureClusterRBACForProjectRoles(ctx, c.client, roles, project.Name, kubermaticv1.ProjectResourceName, kubermaticv1.ProjectKindName, project.GetObjectMeta()); err != nil {
+		return err
+	}
+
+	for _, projectResource := range c.projectResources {
+		if len(projectResource.namespace) > 0 {
+			continue
+		}
+
+		gvk := projectResource.object.GetObjectKind().GroupVersionKind()
+		if _, ok := projectRoleResourceForKind[gvk.Kind]; !ok {
+			continue
+		}
+		rmapping, err := c.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return err
+		}
+
+		if projectResource.destination == destinationSeed {
+			for _, seedClient := range c.seedClientMap {
+				if err := ensureClusterRBACForProjectRolesResource(ctx, seedClient, roles, project.Name, rmapping.Resource.Resource, gvk.Kind); err != nil {
+					return err
+				}
+			}
+		} else {
+			if err := ensureClusterRBACForProjectRolesResource(ctx, c.client, roles, project.Name, rmapping.Resource.Resource, gvk.Kind); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := cleanUpClusterRBACForDeletedProjectRoles(ctx, c.client, roles); err != nil {
+		return err
+	}
+	for _, seedClient := range c.seedClientMap {
+		if err := cleanUpClusterRBACForDeletedProjectRoles(ctx, seedClient, roles); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cleanUpRBACForProjectRoles removes the members of the project of all custom project roles
+// from the bindings for the project's resources
+func (c *projectController) cleanUpRBACForProjectRoles(ctx context.Context, project *kubermaticv1.Project) error {
+	roles, err := listProjectRoles(ctx, c.client)
+	if err != nil {
+		return err
+	}
+
+	for _, projectResource := range c.projectResources {
+		if len(projectResource.namespace) > 0 {
+			continue
+		}
+
+		gvk := projectResource.object.GetObjectKind().GroupVersionKind()
+		if _, ok := projectRoleResourceForKind[gvk.Kind]; !ok {
+			continue
+		}
+		rmapping, err := c.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return err
+		}
+
+		if projectResource.destination == destinationSeed {
+			for _, seedClient := range c.seedClientMap {
+				if err := cleanUpClusterRBACForProjectRolesResource(ctx, seedClient, roles, project.Name, rmapping.Resource.Resource); err != nil {
+					return err
+				}
+			}
+		} else {
+			if err := cleanUpClusterRBACForProjectRolesResource(ctx, c.client, roles, project.Name, rmapping.Resource.Resource); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ensureRBACForProjectRoles generates the RBAC for a named resource of the project
+// for the members of all custom project roles
+func (c *resourcesController) ensureRBACForProjectRoles(ctx context.Context, projectName, objectResource, objectKind string, object metav1.Object) error {
+	if _, ok := projectRoleResourceForKind[objectKind]; !ok {
+		return nil
+	}
+
+	// the roles live in the master cluster, the resources of the seed controllers in the seeds
+	roles, err := listProjectRoles(ctx, c.masterClient)
+	if err != nil {
+		return err
+	}
+
+	if err := ensureClusterRBACForProjectRoles(ctx, c.client, roles, projectName, objectResource, objectKind, object); err != nil {
+		return err
+	}
+	if cluster, ok := object.(*kubermaticv1.Cluster); ok {
+		return ensureRBACForProjectRolesInClusterNamespace(ctx, c.client, roles, projectName, cluster)
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"k8c.io/kubermatic/v2/pkg/controller/master-controller-manager/rbac/test"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+
+	k8scorev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func genOnCallRole() *kubermaticv1.ProjectRole {
+	return &kubermaticv1.ProjectRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "oncall"},
+		Spec: kubermaticv1.ProjectRoleSpec{
+			Rules: []kubermaticv1.ProjectRoleRule{
+				{Resource: kubermaticv1.ProjectRoleResourceClusters, Verbs: []string{"get"}},
+				{Resource: kubermaticv1.ProjectRoleResourceMachineDeployments, Verbs: []string{"get", "update"}},
+				{Resource: kubermaticv1.ProjectRoleResourceAddons, Verbs: []string{"get"}},
+				{Resource: kubermaticv1.ProjectRoleResourceAlertmanagers, Verbs: []string{"get"}},
+			},
+		},
+	}
+}
+
+func genSSHKeyCreatorRole() *kubermaticv1.ProjectRole {
+	return &kubermaticv1.ProjectRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "keymanager"},
+		Spec: kubermaticv1.ProjectRoleSpec{
+			Rules: []kubermaticv1.ProjectRoleRule{
+				{Resource: kubermaticv1.ProjectRoleResourceSSHKeys, Verbs: []string{"get", "create", "delete"}},
+			},
+		},
+	}
+}
+
+// rbacSummary describes the generated RBAC as "<name>: <verbs> -> <subjects>" lines
+func rbacSummary(t *testing.T, cli ctrlruntimeclient.Client) []string {
+	ctx := context.Background()
+	summary := []string{}
+
+	var clusterRoles rbacv1.ClusterRoleList
+	if err := cli.List(ctx, &clusterRoles); err != nil {
+		t.Fatal(err)
+	}
+	for _, role := range clusterRoles.Items {
+		summary = append(summary, fmt.Sprintf("ClusterRole %s: %v", role.Name, role.Rules[0].Verbs))
+	}
+	var clusterRoleBindings rbacv1.ClusterRoleBindingList
+	if err := cli.List(ctx, &clusterRoleBindings); err != nil {
+		t.Fatal(err)
+	}
+	for _, binding := range clusterRoleBindings.Items {
+		summary = append(summary, fmt.Sprintf("ClusterRoleBinding %s: %v", binding.Name, subjectNames(binding.Subjects)))
+	}
+	var roles rbacv1.RoleList
+	if err := cli.List(ctx, &roles); err != nil {
+		t.Fatal(err)
+	}
+	for _, role := range roles.Items {
+		summary = append(summary, fmt.Sprintf("Role %s/%s: %v", role.Namespace, role.Name, role.Rules[0].Verbs))
+	}
+	var roleBindings rbacv1.RoleBindingList
+	if err := cli.List(ctx, &roleBindings); err != nil {
+		t.Fatal(err)
+	}
+	for _, binding := range roleBindings.Items {
+		summary = append(summary, fmt.Sprintf("RoleBinding %s/%s: %v", binding.Namespace, binding.Name, subjectNames(binding.Subjects)))
+	}
+
+	sort.Strings(summary)
+	return summary
+}
+
+func subjectNames(subjects []rbacv1.Subject) []string {
+	names := []string{}
+	for _, subject := range subjects {
+		names = append(names, subject.Name)
+	}
+	return names
+}
+
+func TestEnsureRBACForProjectRolesForCluster(t *testing.T) {
+	cluster := &kubermaticv1.Cluster{
+		TypeMeta: metav1.TypeMeta{Kind: kubermaticv1.ClusterKindName, APIVersion: kubermaticv1.SchemeGroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "abcd",
+			UID:    types.UID("abcdID"),
+			Labels: map[string]string{kubermaticv1.ProjectIDLabelKey: "thunderball"},
+		},
+		Spec: kubermaticv1.ClusterSpec{
+			MLA: &kubermaticv1.MLASettings{MonitoringEnabled: true},
+		},
+		Status: kubermaticv1.ClusterStatus{NamespaceName: "cluster-abcd"},
+	}
+
+	tests := []struct {
+		name            string
+		existingRoles   []ctrlruntimeclient.Object
+		existingObjects []ctrlruntimeclient.Object
+		expectedRBAC    []string
+	}{
+		{
+			name:          "scenario 1: the RBAC for a role which can see but not delete a cluster is generated",
+			existingRoles: []ctrlruntimeclient.Object{genOnCallRole(), genSSHKeyCreatorRole()},
+			expectedRBAC: []string{
+				"ClusterRole kubermatic:cluster-abcd:oncall-thunderball: [get]",
+				"ClusterRoleBinding kubermatic:cluster-abcd:oncall-thunderball: [oncall-thunderball]",
+				"Role cluster-abcd/kubermatic:addon:oncall: [get list]",
+				"Role cluster-abcd/kubermatic:alertmanager-alertmanager:oncall: [get]",
+				"Role cluster-abcd/kubermatic:secret-alertmanager:oncall: [get]",
+				"RoleBinding cluster-abcd/kubermatic:addon:oncall: [oncall-thunderball]",
+				"RoleBinding cluster-abcd/kubermatic:alertmanager-alertmanager:oncall: [oncall-thunderball]",
+				"RoleBinding cluster-abcd/kubermatic:secret-alertmanager:oncall: [oncall-thunderball]",
+			},
+		},
+		{
+			name: "scenario 2: the RBAC of a deleted role is removed",
+			existingObjects: []ctrlruntimeclient.Object{
+				&rbacv1.ClusterRole{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "kubermatic:cluster-abcd:oncall-thunderball",
+						Labels:          map[string]string{ProjectRoleLabelKey: "oncall"},
+						OwnerReferences: []metav1.OwnerReference{{Kind: kubermaticv1.ClusterKindName, Name: "abcd", UID: "abcdID"}},
+					},
+				},
+				&rbacv1.ClusterRole{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "kubermatic:cluster-efgh:oncall-thunderball",
+						Labels:          map[string]string{ProjectRoleLabelKey: "oncall"},
+						OwnerReferences: []metav1.OwnerReference{{Kind: kubermaticv1.ClusterKindName, Name: "efgh", UID: "efghID"}},
+					},
+					Rules: []rbacv1.PolicyRule{{Verbs: []string{"get"}}},
+				},
+				&rbacv1.Role{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "kubermatic:addon:oncall",
+						Namespace: "cluster-abcd",
+						Labels:    map[string]string{ProjectRoleLabelKey: "oncall"},
+					},
+				},
+			},
+			expectedRBAC: []string{
+				"ClusterRole kubermatic:cluster-efgh:oncall-thunderball: [get]",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			masterClient := fakectrlruntimeclient.NewClientBuilder().WithObjects(tc.existingRoles...).Build()
+			seedClient := fakectrlruntimeclient.NewClientBuilder().WithObjects(tc.existingObjects...).Build()
+
+			target := resourcesController{
+				client:       seedClient,
+				masterClient: masterClient,
+				restMapper:   getFakeRestMapper(t),
+				objectType:   &kubermaticv1.Cluster{},
+			}
+			if err := target.ensureRBACForProjectRoles(context.Background(), "thunderball", kubermaticv1.ClusterResourceName, kubermaticv1.ClusterKindName, cluster); err != nil {
+				t.Fatal(err)
+			}
+
+			if result := rbacSummary(t, seedClient); fmt.Sprint(result) != fmt.Sprint(tc.expectedRBAC) {
+				t.Errorf("expected RBAC\n%v\ngot\n%v", tc.expectedRBAC, result)
+			}
+		})
+	}
+}
+
+func TestEnsureRBACForProjectRolesForProject(t *testing.T) {
+	project := test.CreateProject("thunderball", test.CreateUser("James Bond"))
+
+	projectResources := []projectResource{
+		{
+			object: &kubermaticv1.Cluster{
+				TypeMeta: metav1.TypeMeta{APIVersion: kubermaticv1.SchemeGroupVersion.String(), Kind: kubermaticv1.ClusterKindName},
+			},
+			destination: destinationSeed,
+		},
+		{
+			object: &kubermaticv1.UserSSHKey{
+				TypeMeta: metav1.TypeMeta{APIVersion: kubermaticv1.SchemeGroupVersion.String(), Kind: kubermaticv1.SSHKeyKind},
+			},
+		},
+		{
+			object: &k8scorev1.Secret{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			},
+			namespace: "kubermatic",
+		},
+	}
+
+	masterClient := fakectrlruntimeclient.NewClientBuilder().WithObjects(
+		genOnCallRole(),
+		genSSHKeyCreatorRole(),
+		// the shared binding of another project and a role of a deleted ProjectRole
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubermatic:usersshkeies:keymanager", Labels: map[string]string{ProjectRoleLabelKey: "keymanager"}},
+			Subjects:   []rbacv1.Subject{{APIGroup: rbacv1.GroupName, Kind: "Group", Name: "keymanager-moonraker"}},
+		},
+		&rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubermatic:usersshkeies:deleted", Labels: map[string]string{ProjectRoleLabelKey: "deleted"}},
+			Rules:      []rbacv1.PolicyRule{{Verbs: []string{"create"}}},
+		},
+	).Build()
+	seedClient := fakectrlruntimeclient.NewClientBuilder().Build()
+
+	target := projectController{
+		client:           masterClient,
+		restMapper:       getFakeRestMapper(t),
+		seedClientMap:    map[string]ctrlruntimeclient.Client{"seed": seedClient},
+		projectResources: projectResources,
+	}
+	if err := target.ensureRBACForProjectRoles(context.Background(), project); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedMasterRBAC := []string{
+		"ClusterRole kubermatic:project-thunderball:keymanager-thunderball: [get]",
+		"ClusterRole kubermatic:project-thunderball:oncall-thunderball: [get]",
+		"ClusterRole kubermatic:usersshkeies:keymanager: [create]",
+		"ClusterRoleBinding kubermatic:project-thunderball:keymanager-thunderball: [keymanager-thunderball]",
+		"ClusterRoleBinding kubermatic:project-thunderball:oncall-thunderball: [oncall-thunderball]",
+		"ClusterRoleBinding kubermatic:usersshkeies:keymanager: [keymanager-moonraker keymanager-thunderball]",
+	}
+	if result := rbacSummary(t, masterClient); fmt.Sprint(result) != fmt.Sprint(expectedMasterRBAC) {
+		t.Errorf("expected RBAC in the master\n%v\ngot\n%v", expectedMasterRBAC, result)
+	}
+	// none of the roles can create clusters
+	if result := rbacSummary(t, seedClient); len(result) != 0 {
+		t.Errorf("expected no RBAC in the seed, got %v", result)
+	}
+
+	// the members of the project are removed from the shared bindings when the project is deleted
+	if err := target.cleanUpRBACForProjectRoles(context.Background(), project); err != nil {
+		t.Fatal(err)
+	}
+	var binding rbacv1.ClusterRoleBinding
+	if err := masterClient.Get(context.Background(), ctrlruntimeclient.ObjectKey{Name: "kubermatic:usersshkeies:keymanager"}, &binding); err != nil {
+		t.Fatal(err)
+	}
+	if names := subjectNames(binding.Subjects); fmt.Sprint(names) != "[keymanager-moonraker]" {
+		t.Errorf("expected only the members of the other project to be bound, got %v", names)
+	}
+}
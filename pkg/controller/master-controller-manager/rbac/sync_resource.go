@@ -89,6 +89,9 @@ func (c *resourcesController) syncProjectResource(ctx context.Context, key ctrlr
 		if err := ensureClusterRBACRoleBindingForNamedResource(ctx, c.client, projectName, rmapping.Resource.Resource, gvk.Kind, metaObject); err != nil {
 			return fmt.Errorf("failed to sync RBAC ClusterRoleBinding for %s resource for %s cluster provider, due to = %v", rmapping, c.providerName, err)
 		}
+		if err := c.ensureRBACForProjectRoles(ctx, projectName, rmapping.Resource.Resource, gvk.Kind, metaObject); err != nil {
+			return fmt.Errorf("failed to sync RBAC for the custom project roles for %s resource for %s cluster provider, due to = %v", rmapping, c.providerName, err)
+		}
 		if gvk.Kind == kubermaticv1.ClusterKindName {
 			if err := c.ensureRBACRoleForClusterAddons(ctx, projectName, metaObject); err != nil {
 				return fmt.Errorf("failed to sync RBAC Role for %s resource for %s cluster provider in namespace %s, due to = %v", rmapping, c.providerName, metaObject.GetNamespace(), err)
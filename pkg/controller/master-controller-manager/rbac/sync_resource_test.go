@@ -713,9 +713,10 @@ func TestSyncProjectResourcesClusterWide(t *testing.T) {
 
 			// act
 			target := resourcesController{
-				client:     fakeMasterClusterClient,
-				restMapper: getFakeRestMapper(t),
-				objectType: test.dependantToSync.DeepCopyObject().(ctrlruntimeclient.Object),
+				client:       fakeMasterClusterClient,
+				masterClient: fakeMasterClusterClient,
+				restMapper:   getFakeRestMapper(t),
+				objectType:   test.dependantToSync.DeepCopyObject().(ctrlruntimeclient.Object),
 			}
 			objmeta, err := meta.Accessor(test.dependantToSync)
 			assert.NoError(t, err)
@@ -887,9 +888,10 @@ func TestSyncProjectResourcesNamespaced(t *testing.T) {
 			fakeMasterClusterClient := fakectrlruntimeclient.NewClientBuilder().WithObjects(objs...).Build()
 			// act
 			target := resourcesController{
-				client:     fakeMasterClusterClient,
-				restMapper: getFakeRestMapper(t),
-				objectType: test.dependantToSync.DeepCopyObject().(ctrlruntimeclient.Object),
+				client:       fakeMasterClusterClient,
+				masterClient: fakeMasterClusterClient,
+				restMapper:   getFakeRestMapper(t),
+				objectType:   test.dependantToSync.DeepCopyObject().(ctrlruntimeclient.Object),
 			}
 			objmeta, err := meta.Accessor(test.dependantToSync)
 			assert.NoError(t, err)
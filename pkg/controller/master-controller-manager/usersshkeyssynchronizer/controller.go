@@ -18,6 +18,7 @@ package usersshkeyssynchronizer
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -29,6 +30,7 @@ import (
 	"k8c.io/kubermatic/v2/pkg/kubernetes"
 	"k8c.io/kubermatic/v2/pkg/resources"
 	"k8c.io/kubermatic/v2/pkg/resources/reconciling"
+	kubermaticssh "k8c.io/kubermatic/v2/pkg/ssh"
 	"k8c.io/kubermatic/v2/pkg/util/workerlabel"
 
 	corev1 "k8s.io/api/core/v1"
@@ -121,6 +123,13 @@ func Add(
 		return fmt.Errorf("failed to create watch for userSSHKey: %v", err)
 	}
 
+	if err := c.Watch(
+		&source.Kind{Type: &kubermaticv1.Project{}},
+		enqueueAllClusters(reconciler.seedClients, workerSelector),
+	); err != nil {
+		return fmt.Errorf("failed to create watch for projects: %v", err)
+	}
+
 	return nil
 }
 
@@ -128,37 +137,37 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 	log := r.log.With("request", request)
 	log.Debug("Processing")
 
-	err := r.reconcile(ctx, log, request)
+	result, err := r.reconcile(ctx, log, request)
 	if controllerutil.IsCacheNotStarted(err) {
 		return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
 	}
 	if err != nil {
 		log.Errorw("Reconciliation failed", zap.Error(err))
 	}
-	return reconcile.Result{}, err
+	return result, err
 }
 
-func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, request reconcile.Request) error {
+func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, request reconcile.Request) (reconcile.Result, error) {
 	seedClient, ok := r.seedClients[request.Namespace]
 	if !ok {
 		log.Errorw("Got request for seed we don't have a client for", "seed", request.Namespace)
 		// The clients are inserted during controller initialization, so there is no point in retrying
-		return nil
+		return reconcile.Result{}, nil
 	}
 
 	// find all clusters in this seed
 	cluster := &kubermaticv1.Cluster{}
 	if err := seedClient.Get(ctx, types.NamespacedName{Name: request.Name}, cluster); err != nil {
 		if controllerutil.IsCacheNotStarted(err) {
-			return err
+			return reconcile.Result{}, err
 		}
 
 		if kubeapierrors.IsNotFound(err) {
 			log.Debug("Could not find cluster")
-			return nil
+			return reconcile.Result{}, nil
 		}
 
-		return fmt.Errorf("failed to get cluster %s from seed %s: %v", cluster.Name, request.Namespace, err)
+		return reconcile.Result{}, fmt.Errorf("failed to get cluster %s from seed %s: %v", cluster.Name, request.Namespace, err)
 	}
 
 	if cluster.Labels[kubermaticv1.WorkerNameLabelKey] != r.workerName {
@@ -166,54 +175,78 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, requ
 			"Skipping because the cluster has a different worker name set",
 			"cluster-worker-name", cluster.Labels[kubermaticv1.WorkerNameLabelKey],
 		)
-		return nil
+		return reconcile.Result{}, nil
 	}
 
 	if cluster.Spec.Pause {
 		log.Debug("Skipping cluster reconciling because it was set to paused")
-		return nil
+		return reconcile.Result{}, nil
 	}
 
 	userSSHKeys := &kubermaticv1.UserSSHKeyList{}
 	if err := r.client.List(ctx, userSSHKeys); err != nil {
-		return fmt.Errorf("failed to list userSSHKeys: %v", err)
+		return reconcile.Result{}, fmt.Errorf("failed to list userSSHKeys: %v", err)
 	}
 
 	if cluster.DeletionTimestamp != nil {
 		if err := r.cleanupUserSSHKeys(ctx, userSSHKeys.Items, cluster.Name); err != nil {
-			return fmt.Errorf("failed reconciling usersshkey: %v", err)
+			return reconcile.Result{}, fmt.Errorf("failed reconciling usersshkey: %v", err)
 		}
 
 		if kubernetes.HasFinalizer(cluster, UserSSHKeysClusterIDsCleanupFinalizer) {
 			oldCluster := cluster.DeepCopy()
 			kubernetes.RemoveFinalizer(cluster, UserSSHKeysClusterIDsCleanupFinalizer)
 			if err := seedClient.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
-				return fmt.Errorf("failed removing %s finalizer: %v", UserSSHKeysClusterIDsCleanupFinalizer, err)
+				return reconcile.Result{}, fmt.Errorf("failed removing %s finalizer: %v", UserSSHKeysClusterIDsCleanupFinalizer, err)
 			}
 		}
-		return nil
+		return reconcile.Result{}, nil
 	}
 
-	keys := buildUserSSHKeysForCluster(cluster.Name, userSSHKeys)
+	ca, err := r.projectCertificateAuthority(ctx, cluster)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to get the ssh certificate authority: %v", err)
+	}
+
+	now := time.Now()
+	var keys []kubermaticv1.UserSSHKey
+	var secretCreator reconciling.NamedSecretCreatorGetter
+	if ca != nil {
+		// The nodes trust the certificates signed by the project, there are no keys to distribute.
+		secretCreator = updateUserSSHKeysCertificateAuthoritySecret(ca.PublicKey())
+	} else {
+		keys = buildUserSSHKeysForCluster(cluster.Name, userSSHKeys, now)
+		secretCreator = updateUserSSHKeysSecrets(keys)
+	}
 
 	if err := reconciling.ReconcileSecrets(
 		ctx,
-		[]reconciling.NamedSecretCreatorGetter{updateUserSSHKeysSecrets(keys)},
+		[]reconciling.NamedSecretCreatorGetter{secretCreator},
 		cluster.Status.NamespaceName,
 		seedClient,
 	); err != nil {
-		return fmt.Errorf("failed to reconcile ssh key secret: %v", err)
+		return reconcile.Result{}, fmt.Errorf("failed to reconcile ssh key secret: %v", err)
 	}
 
 	oldCluster := cluster.DeepCopy()
 	if !kubernetes.HasFinalizer(cluster, UserSSHKeysClusterIDsCleanupFinalizer) {
 		kubernetes.AddFinalizer(cluster, UserSSHKeysClusterIDsCleanupFinalizer)
 		if err := seedClient.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
-			return fmt.Errorf("failed adding %s finalizer: %v", UserSSHKeysClusterIDsCleanupFinalizer, err)
+			return reconcile.Result{}, fmt.Errorf("failed adding %s finalizer: %v", UserSSHKeysClusterIDsCleanupFinalizer, err)
 		}
 	}
 
-	return nil
+	// Come back when the next key expires to remove it from the secret.
+	result := reconcile.Result{}
+	for _, key := range keys {
+		if key.Spec.ExpiresAt == nil {
+			continue
+		}
+		if after := key.Spec.ExpiresAt.Sub(now); result.RequeueAfter == 0 || after < result.RequeueAfter {
+			result.RequeueAfter = after
+		}
+	}
+	return result, nil
 }
 
 func (r *Reconciler) cleanupUserSSHKeys(ctx context.Context, keys []kubermaticv1.UserSSHKey, clusterName string) error {
@@ -227,9 +260,36 @@ func (r *Reconciler) cleanupUserSSHKeys(ctx context.Context, keys []kubermaticv1
 	return nil
 }
 
-func buildUserSSHKeysForCluster(clusterName string, list *kubermaticv1.UserSSHKeyList) []kubermaticv1.UserSSHKey {
+// projectCertificateAuthority returns the ssh certificate authority of the project of the cluster,
+// nil if the project didn't enable it.
+func (r *Reconciler) projectCertificateAuthority(ctx context.Context, cluster *kubermaticv1.Cluster) (*kubermaticssh.CertificateAuthority, error) {
+	projectID := cluster.Labels[kubermaticv1.ProjectIDLabelKey]
+	if projectID == "" {
+		return nil, nil
+	}
+
+	project := &kubermaticv1.Project{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: projectID}, project); err != nil {
+		if kubeapierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get project %s: %v", projectID, err)
+	}
+	if !project.SSHCertificateAuthorityEnabled() {
+		return nil, nil
+	}
+
+	return kubermaticssh.EnsureCertificateAuthority(ctx, r.client, resources.KubermaticNamespace, project)
+}
+
+// buildUserSSHKeysForCluster returns the keys of the cluster which are not
+// expired yet.
+func buildUserSSHKeysForCluster(clusterName string, list *kubermaticv1.UserSSHKeyList, now time.Time) []kubermaticv1.UserSSHKey {
 	var clusterKeys []kubermaticv1.UserSSHKey
 	for _, item := range list.Items {
+		if item.IsExpired(now) {
+			continue
+		}
 		for _, clusterID := range item.Spec.Clusters {
 			if clusterName == clusterID {
 				clusterKeys = append(clusterKeys, item)
@@ -273,8 +333,23 @@ func updateUserSSHKeysSecrets(list []kubermaticv1.UserSSHKey) reconciling.NamedS
 		return resources.UserSSHKeys, func(existing *corev1.Secret) (secret *corev1.Secret, e error) {
 			existing.Data = map[string][]byte{}
 
+			metadata := map[string]resources.UserSSHKeyMetadata{}
 			for _, key := range list {
 				existing.Data[key.Name] = []byte(key.Spec.PublicKey)
+				if key.Spec.User != "" || key.Spec.ExpiresAt != nil {
+					metadata[key.Name] = resources.UserSSHKeyMetadata{
+						User:      key.Spec.User,
+						ExpiresAt: key.Spec.ExpiresAt,
+					}
+				}
+			}
+
+			if len(metadata) > 0 {
+				rawMetadata, err := json.Marshal(metadata)
+				if err != nil {
+					return nil, fmt.Errorf("failed to encode the metadata of the keys: %v", err)
+				}
+				existing.Data[resources.UserSSHKeysMetadataKey] = rawMetadata
 			}
 
 			existing.Type = corev1.SecretTypeOpaque
@@ -283,3 +358,18 @@ func updateUserSSHKeysSecrets(list []kubermaticv1.UserSSHKey) reconciling.NamedS
 		}
 	}
 }
+
+// updateUserSSHKeysCertificateAuthoritySecret creates a secret in the seed cluster holding only the public key
+// of the ssh certificate authority of the project.
+func updateUserSSHKeysCertificateAuthoritySecret(publicKey []byte) reconciling.NamedSecretCreatorGetter {
+	return func() (string, reconciling.SecretCreator) {
+		return resources.UserSSHKeys, func(existing *corev1.Secret) (*corev1.Secret, error) {
+			existing.Data = map[string][]byte{
+				resources.UserSSHKeysCertificateAuthorityKey: publicKey,
+			}
+			existing.Type = corev1.SecretTypeOpaque
+
+			return existing, nil
+		}
+	}
+}
@@ -20,10 +20,14 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	kubermaticssh "k8c.io/kubermatic/v2/pkg/ssh"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -108,3 +112,102 @@ func TestUserSSHKeysClusterRemove(t *testing.T) {
 		})
 	}
 }
+
+func TestUserSSHKeysSecretSkipsExpiredKeys(t *testing.T) {
+	now := time.Now()
+	expired := metav1.NewTime(now.Add(-time.Minute))
+	expiring := metav1.NewTime(now.Add(time.Hour).Truncate(time.Second))
+
+	list := &kubermaticv1.UserSSHKeyList{
+		Items: []kubermaticv1.UserSSHKey{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "key-plain"},
+				Spec:       kubermaticv1.SSHKeySpec{PublicKey: "ssh-rsa plain", Clusters: []string{"test_cluster"}},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "key-expired"},
+				Spec:       kubermaticv1.SSHKeySpec{PublicKey: "ssh-rsa expired", Clusters: []string{"test_cluster"}, ExpiresAt: &expired},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "key-expiring"},
+				Spec:       kubermaticv1.SSHKeySpec{PublicKey: "ssh-rsa expiring", Clusters: []string{"test_cluster"}, ExpiresAt: &expiring, User: "ubuntu"},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "key-other-cluster"},
+				Spec:       kubermaticv1.SSHKeySpec{PublicKey: "ssh-rsa other", Clusters: []string{"other_cluster"}},
+			},
+		},
+	}
+
+	keys := buildUserSSHKeysForCluster("test_cluster", list, now)
+
+	_, create := updateUserSSHKeysSecrets(keys)()
+	secret, err := create(&corev1.Secret{})
+	if err != nil {
+		t.Fatalf("failed to create secret: %v", err)
+	}
+
+	expectedData := map[string][]byte{
+		"key-plain":    []byte("ssh-rsa plain"),
+		"key-expiring": []byte("ssh-rsa expiring"),
+		resources.UserSSHKeysMetadataKey: []byte(`{"key-expiring":{"user":"ubuntu","expiresAt":"` +
+			expiring.UTC().Format(time.RFC3339) + `"}}`),
+	}
+	if !reflect.DeepEqual(secret.Data, expectedData) {
+		t.Fatalf("unexpected secret data: want: %q, got: %q", expectedData, secret.Data)
+	}
+}
+
+func TestUserSSHKeysSecretWithCertificateAuthority(t *testing.T) {
+	ctx := context.Background()
+	project := &kubermaticv1.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: "test_project"},
+		Spec: kubermaticv1.ProjectSpec{
+			SSHCertificateAuthority: &kubermaticv1.ProjectSSHCertificateAuthority{Enabled: true},
+		},
+	}
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test_cluster",
+			Labels: map[string]string{kubermaticv1.ProjectIDLabelKey: project.Name},
+		},
+		Status: kubermaticv1.ClusterStatus{NamespaceName: "cluster-test_cluster"},
+	}
+	key := &kubermaticv1.UserSSHKey{
+		ObjectMeta: metav1.ObjectMeta{Name: "key-plain"},
+		Spec:       kubermaticv1.SSHKeySpec{PublicKey: "ssh-rsa plain", Clusters: []string{cluster.Name}},
+	}
+
+	seedClient := fakectrlruntimeclient.NewClientBuilder().WithObjects(cluster).Build()
+	reconciler := &Reconciler{
+		log:         kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
+		client:      fakectrlruntimeclient.NewClientBuilder().WithObjects(project, key).Build(),
+		seedClients: map[string]ctrlruntimeclient.Client{"seed_test": seedClient},
+	}
+
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: cluster.Name, Namespace: "seed_test"}}
+	if _, err := reconciler.Reconcile(ctx, request); err != nil {
+		t.Fatalf("failed reconciling test: %v", err)
+	}
+
+	caSecret := &corev1.Secret{}
+	caSecretName := types.NamespacedName{Namespace: resources.KubermaticNamespace, Name: kubermaticssh.CertificateAuthoritySecretName(project.Name)}
+	if err := reconciler.client.Get(ctx, caSecretName, caSecret); err != nil {
+		t.Fatalf("failed to get the secret of the certificate authority: %v", err)
+	}
+	ca, err := kubermaticssh.NewCertificateAuthority(caSecret.Data[kubermaticssh.CertificateAuthorityKeySecretKey])
+	if err != nil {
+		t.Fatalf("failed to load the certificate authority: %v", err)
+	}
+
+	secret := &corev1.Secret{}
+	if err := seedClient.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: resources.UserSSHKeys}, secret); err != nil {
+		t.Fatalf("failed to get the user ssh keys secret: %v", err)
+	}
+	expectedData := map[string][]byte{
+		resources.UserSSHKeysCertificateAuthorityKey: ca.PublicKey(),
+	}
+	if !reflect.DeepEqual(secret.Data, expectedData) {
+		t.Fatalf("unexpected secret data: want: %q, got: %q", expectedData, secret.Data)
+	}
+}
@@ -18,6 +18,7 @@ limitations under the License.
 The usersshkeyssynchronizer controller is responsible for synchronizing usersshkeys into
 a secret in the cluster namespace. From there, the usercluster controller synchronizes them
 into the usercluster and then a DaemonSet that runs on all nodes synchronizes them onto the
-.ssh/authorized_keys file.
+.ssh/authorized_keys file. If the project of the cluster enabled an ssh certificate authority,
+the secret holds the public key of the authority instead of the keys.
 */
 package usersshkeyssynchronizer
@@ -25,6 +25,8 @@ import (
 	"github.com/gogo/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/any"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"github.com/golang/protobuf/ptypes/wrappers"
 	"go.uber.org/zap/zaptest"
 
 	envoyclusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
@@ -32,7 +34,10 @@ import (
 	envoyendpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
 	envoylistenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	envoyroutev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoylocalratelimitv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/local_ratelimit/v3"
 	envoytcpfilterv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	envoytlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	envoytypev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	envoycachetype "github.com/envoyproxy/go-control-plane/pkg/cache/types"
 	envoywellknown "github.com/envoyproxy/go-control-plane/pkg/wellknown"
 
@@ -108,6 +113,55 @@ func TestSync(t *testing.T) {
 				"test/my-nodeport-http": makeNodePortListener(t, "test/my-nodeport-http", 32001),
 			},
 		},
+		{
+			name: "1-port-with-limits",
+			resources: []ctrlruntimeclient.Object{
+				test.NewServiceBuilder(test.NamespacedName{Name: "my-nodeport", Namespace: "test"}).
+					WithAnnotation(nodeportproxy.DefaultExposeAnnotationKey, "NodePort").
+					WithAnnotation(nodeportproxy.MaxConnectionsAnnotationKey, "100").
+					WithAnnotation(nodeportproxy.ConnectionsPerSecondAnnotationKey, "10").
+					WithServiceType(corev1.ServiceTypeNodePort).
+					WithServicePort("http", 80, 32001, intstr.FromString("http"), corev1.ProtocolTCP).
+					Build(),
+				test.NewEndpointsBuilder(test.NamespacedName{Name: "my-nodeport", Namespace: "test"}).
+					WithEndpointsSubset().
+					WithEndpointPort("http", 8080, corev1.ProtocolTCP).
+					WithReadyAddressIP("172.16.0.1").
+					DoneWithEndpointSubset().Build(),
+			},
+			expectedClusters: map[string]*envoyclusterv3.Cluster{
+				"test/my-nodeport-http": withMaxConnections(makeCluster(t, "test/my-nodeport-http", 8080, "172.16.0.1"), 100),
+			},
+			expectedListener: map[string]*envoylistenerv3.Listener{
+				"test/my-nodeport-http": withConnectionsPerSecond(t, makeNodePortListener(t, "test/my-nodeport-http", 32001), 10),
+			},
+		},
+		{
+			name: "1-port-with-https-health-check",
+			resources: []ctrlruntimeclient.Object{
+				test.NewServiceBuilder(test.NamespacedName{Name: "my-nodeport", Namespace: "test"}).
+					WithAnnotation(nodeportproxy.DefaultExposeAnnotationKey, "NodePort").
+					WithAnnotation(nodeportproxy.HealthCheckMappingAnnotationKey, `{"https": {"path": "/healthz", "tls": true}}`).
+					WithServiceType(corev1.ServiceTypeNodePort).
+					WithServicePort("https", 443, 32000, intstr.FromString("https"), corev1.ProtocolTCP).
+					WithServicePort("http", 80, 32001, intstr.FromString("http"), corev1.ProtocolTCP).
+					Build(),
+				test.NewEndpointsBuilder(test.NamespacedName{Name: "my-nodeport", Namespace: "test"}).
+					WithEndpointsSubset().
+					WithEndpointPort("https", 8443, corev1.ProtocolTCP).
+					WithEndpointPort("http", 8080, corev1.ProtocolTCP).
+					WithReadyAddressIP("172.16.0.1").
+					DoneWithEndpointSubset().Build(),
+			},
+			expectedClusters: map[string]*envoyclusterv3.Cluster{
+				"test/my-nodeport-https": withHTTPSHealthCheck(t, makeCluster(t, "test/my-nodeport-https", 8443, "172.16.0.1"), "my-nodeport.test.svc", "/healthz"),
+				"test/my-nodeport-http":  makeCluster(t, "test/my-nodeport-http", 8080, "172.16.0.1"),
+			},
+			expectedListener: map[string]*envoylistenerv3.Listener{
+				"test/my-nodeport-https": makeNodePortListener(t, "test/my-nodeport-https", 32000),
+				"test/my-nodeport-http":  makeNodePortListener(t, "test/my-nodeport-http", 32001),
+			},
+		},
 		{
 			name: "1-port-service-without-annotation",
 			resources: []ctrlruntimeclient.Object{
@@ -535,9 +589,49 @@ func makeCluster(t *testing.T, name string, portValue uint32, addresses ...strin
 				},
 			},
 		},
+		HealthChecks: []*envoycorev3.HealthCheck{
+			{
+				Timeout:            ptypes.DurationProto(healthCheckTimeout),
+				Interval:           ptypes.DurationProto(healthCheckInterval),
+				UnhealthyThreshold: &wrappers.UInt32Value{Value: healthCheckUnhealthyThreshold},
+				HealthyThreshold:   &wrappers.UInt32Value{Value: 1},
+				HealthChecker: &envoycorev3.HealthCheck_TcpHealthCheck_{
+					TcpHealthCheck: &envoycorev3.HealthCheck_TcpHealthCheck{},
+				},
+			},
+		},
+		OutlierDetection: makeOutlierDetection(),
 	}
 }
 
+func withHTTPSHealthCheck(t *testing.T, c *envoyclusterv3.Cluster, host, path string) *envoyclusterv3.Cluster {
+	match := &structpb.Struct{
+		Fields: map[string]*structpb.Value{
+			"healthCheck": {Kind: &structpb.Value_StringValue{StringValue: "tls"}},
+		},
+	}
+	c.HealthChecks[0].HealthChecker = &envoycorev3.HealthCheck_HttpHealthCheck_{
+		HttpHealthCheck: &envoycorev3.HealthCheck_HttpHealthCheck{
+			Host: host,
+			Path: path,
+		},
+	}
+	c.HealthChecks[0].TransportSocketMatchCriteria = match
+	c.TransportSocketMatches = []*envoyclusterv3.Cluster_TransportSocketMatch{
+		{
+			Name:  healthCheckTransportSocketName,
+			Match: match,
+			TransportSocket: &envoycorev3.TransportSocket{
+				Name: envoywellknown.TransportSocketTls,
+				ConfigType: &envoycorev3.TransportSocket_TypedConfig{
+					TypedConfig: marshalMessage(t, &envoytlsv3.UpstreamTlsContext{}),
+				},
+			},
+		},
+	}
+	return c
+}
+
 func TestEndpointToService(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -670,6 +764,37 @@ func TestExposeAnnotationPredicate(t *testing.T) {
 	}
 }
 
+func withMaxConnections(c *envoyclusterv3.Cluster, maxConnections uint32) *envoyclusterv3.Cluster {
+	c.CircuitBreakers = &envoyclusterv3.CircuitBreakers{
+		Thresholds: []*envoyclusterv3.CircuitBreakers_Thresholds{
+			{
+				Priority:       envoycorev3.RoutingPriority_DEFAULT,
+				MaxConnections: &wrappers.UInt32Value{Value: maxConnections},
+			},
+		},
+	}
+	return c
+}
+
+func withConnectionsPerSecond(t *testing.T, l *envoylistenerv3.Listener, connectionsPerSecond uint32) *envoylistenerv3.Listener {
+	l.FilterChains[0].Filters = append([]*envoylistenerv3.Filter{
+		{
+			Name: localRateLimitFilterName,
+			ConfigType: &envoylistenerv3.Filter_TypedConfig{
+				TypedConfig: marshalMessage(t, &envoylocalratelimitv3.LocalRateLimit{
+					StatPrefix: "ingress_ratelimit",
+					TokenBucket: &envoytypev3.TokenBucket{
+						MaxTokens:     connectionsPerSecond,
+						TokensPerFill: &wrappers.UInt32Value{Value: connectionsPerSecond},
+						FillInterval:  ptypes.DurationProto(time.Second),
+					},
+				}),
+			},
+		},
+	}, l.FilterChains[0].Filters...)
+	return l
+}
+
 func marshalMessage(t *testing.T, msg proto.Message) *any.Any {
 	marshalled, err := ptypes.MarshalAny(msg)
 	if err != nil {
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/ptypes"
+	structpb "github.com/golang/protobuf/ptypes/struct"
 	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
@@ -38,7 +39,10 @@ import (
 	envoylistenerlogv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/access_loggers/file/v3"
 	envoyhealthv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/health_check/v3"
 	envoyhttpconnectionmanagerv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoylocalratelimitv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/local_ratelimit/v3"
 	envoytcpfilterv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	envoytlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	envoytypev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	envoycachetype "github.com/envoyproxy/go-control-plane/pkg/cache/types"
 	envoycachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
@@ -53,10 +57,33 @@ import (
 
 const clusterConnectTimeout = 1 * time.Second
 
+const (
+	healthCheckTimeout  = 1 * time.Second
+	healthCheckInterval = 2 * time.Second
+	// healthCheckUnhealthyThreshold is the number of consecutive failed
+	// health checks after which an endpoint is considered unhealthy.
+	healthCheckUnhealthyThreshold = 2
+	// healthCheckTransportSocketName is the name of the transport socket
+	// used to health check the endpoints serving HTTPS.
+	healthCheckTransportSocketName = "health-check-tls"
+
+	// outlierDetectionConsecutiveFailures is the number of consecutive
+	// connection failures after which an endpoint is ejected, this catches
+	// the endpoints going away between two health checks.
+	outlierDetectionConsecutiveFailures = 3
+	outlierDetectionInterval            = 5 * time.Second
+	outlierDetectionBaseEjectionTime    = 10 * time.Second
+	outlierDetectionMaxEjectionPercent  = 50
+)
+
 const (
 	UpgradeType = "CONNECT"
 )
 
+// localRateLimitFilterName is the name of the network filter limiting the
+// rate of new connections, it is missing from the wellknown names.
+const localRateLimitFilterName = "envoy.filters.network.local_ratelimit"
+
 // portHostMappingGetter returns the portHostMapping for the given Service or
 // an error.
 type portHostMappingGetter func(*corev1.Service) (portHostMapping, error)
@@ -101,6 +128,14 @@ func (sb *snapshotBuilder) addService(svc *corev1.Service, eps *corev1.Endpoints
 	if len(expTypes) == 0 {
 		svcLog.Debug("skipping service: no expose types provided")
 	}
+	limits, err := limitsFromAnnotations(svc)
+	if err != nil {
+		svcLog.Warnw("ignoring invalid limits", "error", err)
+	}
+	healthChecks, err := healthCheckMappingFromAnnotation(svc)
+	if err != nil {
+		svcLog.Warnw("using TCP health checks for all ports", "error", err)
+	}
 
 	// Exclude all ports by default, to avoid creating unused clusters.
 	var includePorts sets.String
@@ -111,14 +146,14 @@ func (sb *snapshotBuilder) addService(svc *corev1.Service, eps *corev1.Endpoints
 			svcLog.Warn("skipping service: it is not of type NodePort", "service")
 		} else {
 			// Add listeners for nodeport services
-			ls, ports := sb.makeListenersForNodePortService(svc)
+			ls, ports := sb.makeListenersForNodePortService(svc, limits)
 			includePorts = ports.Union(includePorts)
 			sb.listeners = append(sb.listeners, ls...)
 		}
 	}
 	// Create filter chains for SNIType
 	if expTypes.Has(nodeportproxy.SNIType) && sb.IsSNIEnabled() {
-		fcs, ports := sb.makeSNIFilterChains(svcLog, svc)
+		fcs, ports := sb.makeSNIFilterChains(svcLog, svc, limits)
 		includePorts = ports.Union(includePorts)
 		sb.fcs = append(sb.fcs, fcs...)
 	}
@@ -131,13 +166,13 @@ func (sb *snapshotBuilder) addService(svc *corev1.Service, eps *corev1.Endpoints
 
 	// Create clusters
 	sb.log.Debugw("creating clusters", "includePorts", includePorts)
-	sb.clusters = append(sb.clusters, sb.makeClusters(svc, eps, includePorts)...)
+	sb.clusters = append(sb.clusters, sb.makeClusters(svc, eps, includePorts, limits, healthChecks)...)
 }
 
 // makeSNIFilterChains returns the FilterChains for the given service and the
 // set of ports that are exposed. Note that the set can be nil, don't try to
 // write to it before doing a nil check.
-func (sb *snapshotBuilder) makeSNIFilterChains(svcLog *zap.SugaredLogger, svc *corev1.Service) ([]*envoylistenerv3.FilterChain, sets.String) {
+func (sb *snapshotBuilder) makeSNIFilterChains(svcLog *zap.SugaredLogger, svc *corev1.Service, limits serviceLimits) ([]*envoylistenerv3.FilterChain, sets.String) {
 	m, err := sb.portHostMappingGetter(svc)
 	if err != nil {
 		svcLog.Warnw("port host mapping is required with SNI expose type", "error", err)
@@ -162,7 +197,7 @@ func (sb *snapshotBuilder) makeSNIFilterChains(svcLog *zap.SugaredLogger, svc *c
 
 	svcLog.Debugw("creating sni filter chains", "portHostMapping", m)
 	// Besides the filter chains returns the ports that are exposed.
-	return makeSNIFilterChains(svc, m), ports
+	return makeSNIFilterChains(svc, m, limits), ports
 }
 
 // build returns a new Snapshot from the resources derived by the Services
@@ -204,7 +239,127 @@ func makeAccessLog() []*envoyaccesslogv3.AccessLog {
 	return accessLog
 }
 
-func makeSNIFilterChains(service *corev1.Service, p portHostMapping) []*envoylistenerv3.FilterChain {
+// makeRateLimitFilters returns the filters limiting the rate of new
+// connections that must precede the proxy filter, if any.
+func makeRateLimitFilters(limits serviceLimits) []*envoylistenerv3.Filter {
+	if limits.ConnectionsPerSecond == nil {
+		return nil
+	}
+	rateLimitConfig := &envoylocalratelimitv3.LocalRateLimit{
+		StatPrefix: "ingress_ratelimit",
+		TokenBucket: &envoytypev3.TokenBucket{
+			MaxTokens:     *limits.ConnectionsPerSecond,
+			TokensPerFill: &wrappers.UInt32Value{Value: *limits.ConnectionsPerSecond},
+			FillInterval:  ptypes.DurationProto(time.Second),
+		},
+	}
+	rateLimitConfigMarshalled, err := ptypes.MarshalAny(rateLimitConfig)
+	if err != nil {
+		panic(errors.Wrap(err, "failed to marshal rateLimitConfig"))
+	}
+	return []*envoylistenerv3.Filter{
+		{
+			Name: localRateLimitFilterName,
+			ConfigType: &envoylistenerv3.Filter_TypedConfig{
+				TypedConfig: rateLimitConfigMarshalled,
+			},
+		},
+	}
+}
+
+// makeCircuitBreakers returns the circuit breakers of the clusters, or nil
+// when no threshold is set.
+func makeCircuitBreakers(limits serviceLimits) *envoyclusterv3.CircuitBreakers {
+	if limits.MaxConnections == nil && limits.MaxPendingRequests == nil && limits.MaxRequests == nil {
+		return nil
+	}
+	thresholds := &envoyclusterv3.CircuitBreakers_Thresholds{
+		Priority: envoycorev3.RoutingPriority_DEFAULT,
+	}
+	if limits.MaxConnections != nil {
+		thresholds.MaxConnections = &wrappers.UInt32Value{Value: *limits.MaxConnections}
+	}
+	if limits.MaxPendingRequests != nil {
+		thresholds.MaxPendingRequests = &wrappers.UInt32Value{Value: *limits.MaxPendingRequests}
+	}
+	if limits.MaxRequests != nil {
+		thresholds.MaxRequests = &wrappers.UInt32Value{Value: *limits.MaxRequests}
+	}
+	return &envoyclusterv3.CircuitBreakers{
+		Thresholds: []*envoyclusterv3.CircuitBreakers_Thresholds{thresholds},
+	}
+}
+
+// makeHealthChecks returns the active health checks of the endpoints of the
+// service port, and the transport socket matches they require. The endpoints
+// are checked by opening a TCP connection unless an HTTP health check is
+// mapped to the port.
+func makeHealthChecks(service *corev1.Service, servicePort *corev1.ServicePort, healthChecks healthCheckMapping) ([]*envoycorev3.HealthCheck, []*envoyclusterv3.Cluster_TransportSocketMatch) {
+	healthCheck := &envoycorev3.HealthCheck{
+		Timeout:            ptypes.DurationProto(healthCheckTimeout),
+		Interval:           ptypes.DurationProto(healthCheckInterval),
+		UnhealthyThreshold: &wrappers.UInt32Value{Value: healthCheckUnhealthyThreshold},
+		HealthyThreshold:   &wrappers.UInt32Value{Value: 1},
+		HealthChecker: &envoycorev3.HealthCheck_TcpHealthCheck_{
+			TcpHealthCheck: &envoycorev3.HealthCheck_TcpHealthCheck{},
+		},
+	}
+
+	httpHealthCheck, ok := healthChecks[servicePort.Name]
+	if !ok {
+		return []*envoycorev3.HealthCheck{healthCheck}, nil
+	}
+	healthCheck.HealthChecker = &envoycorev3.HealthCheck_HttpHealthCheck_{
+		HttpHealthCheck: &envoycorev3.HealthCheck_HttpHealthCheck{
+			// The default is the cluster name, which is not a valid host.
+			Host: fmt.Sprintf("%s.%s.svc", service.Name, service.Namespace),
+			Path: httpHealthCheck.Path,
+		},
+	}
+	if !httpHealthCheck.TLS {
+		return []*envoycorev3.HealthCheck{healthCheck}, nil
+	}
+
+	// The proxied streams are not terminated, only the health checks use a
+	// TLS transport socket.
+	match := &structpb.Struct{
+		Fields: map[string]*structpb.Value{
+			"healthCheck": {Kind: &structpb.Value_StringValue{StringValue: "tls"}},
+		},
+	}
+	healthCheck.TransportSocketMatchCriteria = match
+
+	tlsContext, err := ptypes.MarshalAny(&envoytlsv3.UpstreamTlsContext{})
+	if err != nil {
+		panic(errors.Wrap(err, "failed to marshal UpstreamTlsContext"))
+	}
+	return []*envoycorev3.HealthCheck{healthCheck}, []*envoyclusterv3.Cluster_TransportSocketMatch{
+		{
+			Name:  healthCheckTransportSocketName,
+			Match: match,
+			TransportSocket: &envoycorev3.TransportSocket{
+				Name: envoywellknown.TransportSocketTls,
+				ConfigType: &envoycorev3.TransportSocket_TypedConfig{
+					TypedConfig: tlsContext,
+				},
+			},
+		},
+	}
+}
+
+// makeOutlierDetection returns the outlier detection of the clusters, it
+// ejects the endpoints that fail to accept connections.
+func makeOutlierDetection() *envoyclusterv3.OutlierDetection {
+	return &envoyclusterv3.OutlierDetection{
+		SplitExternalLocalOriginErrors: true,
+		ConsecutiveLocalOriginFailure:  &wrappers.UInt32Value{Value: outlierDetectionConsecutiveFailures},
+		Interval:                       ptypes.DurationProto(outlierDetectionInterval),
+		BaseEjectionTime:               ptypes.DurationProto(outlierDetectionBaseEjectionTime),
+		MaxEjectionPercent:             &wrappers.UInt32Value{Value: outlierDetectionMaxEjectionPercent},
+	}
+}
+
+func makeSNIFilterChains(service *corev1.Service, p portHostMapping, limits serviceLimits) []*envoylistenerv3.FilterChain {
 	var sniFilterChains []*envoylistenerv3.FilterChain
 
 	serviceKey := ServiceKey(service)
@@ -226,14 +381,12 @@ func makeSNIFilterChains(service *corev1.Service, p portHostMapping) []*envoylis
 			}
 
 			sniFilterChains = append(sniFilterChains, &envoylistenerv3.FilterChain{
-				Filters: []*envoylistenerv3.Filter{
-					{
-						Name: envoywellknown.TCPProxy,
-						ConfigType: &envoylistenerv3.Filter_TypedConfig{
-							TypedConfig: tcpProxyConfigMarshalled,
-						},
+				Filters: append(makeRateLimitFilters(limits), &envoylistenerv3.Filter{
+					Name: envoywellknown.TCPProxy,
+					ConfigType: &envoylistenerv3.Filter_TypedConfig{
+						TypedConfig: tcpProxyConfigMarshalled,
 					},
-				},
+				}),
 				FilterChainMatch: &envoylistenerv3.FilterChainMatch{
 					ServerNames:       []string{name},
 					TransportProtocol: "tls",
@@ -379,7 +532,7 @@ func (sb *snapshotBuilder) makeTunnelingListener(vhs ...*envoyroutev3.VirtualHos
 	return tunnelingListener
 }
 
-func (sb *snapshotBuilder) makeClusters(service *corev1.Service, endpoints *corev1.Endpoints, includePorts sets.String) (clusters []envoycachetype.Resource) {
+func (sb *snapshotBuilder) makeClusters(service *corev1.Service, endpoints *corev1.Endpoints, includePorts sets.String, limits serviceLimits, healthChecks healthCheckMapping) (clusters []envoycachetype.Resource) {
 	serviceKey := ServiceKey(service)
 	for _, servicePort := range service.Spec.Ports {
 		if !includePorts.Has(servicePort.Name) {
@@ -396,6 +549,8 @@ func (sb *snapshotBuilder) makeClusters(service *corev1.Service, endpoints *core
 			return addrI < addrJ
 		})
 
+		hcs, transportSocketMatches := makeHealthChecks(service, &servicePort, healthChecks)
+
 		cluster := &envoyclusterv3.Cluster{
 			Name:           servicePortKey,
 			ConnectTimeout: ptypes.DurationProto(clusterConnectTimeout),
@@ -411,13 +566,17 @@ func (sb *snapshotBuilder) makeClusters(service *corev1.Service, endpoints *core
 					},
 				},
 			},
+			CircuitBreakers:        makeCircuitBreakers(limits),
+			HealthChecks:           hcs,
+			TransportSocketMatches: transportSocketMatches,
+			OutlierDetection:       makeOutlierDetection(),
 		}
 		clusters = append(clusters, cluster)
 	}
 	return
 }
 
-func (sb *snapshotBuilder) makeListenersForNodePortService(service *corev1.Service) (listeners []envoycachetype.Resource, exposedPorts sets.String) {
+func (sb *snapshotBuilder) makeListenersForNodePortService(service *corev1.Service, limits serviceLimits) (listeners []envoycachetype.Resource, exposedPorts sets.String) {
 	serviceKey := ServiceKey(service)
 	exposedPorts = sets.NewString()
 	for _, servicePort := range service.Spec.Ports {
@@ -457,14 +616,12 @@ func (sb *snapshotBuilder) makeListenersForNodePortService(service *corev1.Servi
 			},
 			FilterChains: []*envoylistenerv3.FilterChain{
 				{
-					Filters: []*envoylistenerv3.Filter{
-						{
-							Name: envoywellknown.TCPProxy,
-							ConfigType: &envoylistenerv3.Filter_TypedConfig{
-								TypedConfig: tcpProxyConfigMarshalled,
-							},
+					Filters: append(makeRateLimitFilters(limits), &envoylistenerv3.Filter{
+						Name: envoywellknown.TCPProxy,
+						ConfigType: &envoylistenerv3.Filter_TypedConfig{
+							TypedConfig: tcpProxyConfigMarshalled,
 						},
-					},
+					}),
 				},
 			},
 		}
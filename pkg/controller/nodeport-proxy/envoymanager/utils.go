@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -28,6 +29,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
@@ -135,6 +137,22 @@ func portHostMappingFromAnnotation(svc *corev1.Service) (portHostMapping, error)
 	return m, nil
 }
 
+// healthCheckMapping contains the mapping between port name and HTTP health
+// check of its endpoints.
+type healthCheckMapping map[string]nodeportproxy.HTTPHealthCheck
+
+func healthCheckMappingFromAnnotation(svc *corev1.Service) (healthCheckMapping, error) {
+	m := healthCheckMapping{}
+	val, ok := svc.GetAnnotations()[nodeportproxy.HealthCheckMappingAnnotationKey]
+	if !ok {
+		return m, nil
+	}
+	if err := json.Unmarshal([]byte(val), &m); err != nil {
+		return healthCheckMapping{}, errors.Wrap(err, "failed to unmarshal health check mapping")
+	}
+	return m, nil
+}
+
 func (p portHostMapping) validate(svc *corev1.Service) error {
 	// TODO(irozzo): validate that hosts are well formed FQDN
 	portNames, hosts := p.portHostSets()
@@ -147,3 +165,42 @@ func (p portHostMapping) validate(svc *corev1.Service) error {
 	}
 	return nil
 }
+
+// serviceLimits contains the limits enforced on each exposed port of a
+// service, nil values are not enforced.
+type serviceLimits struct {
+	MaxConnections       *uint32
+	MaxPendingRequests   *uint32
+	MaxRequests          *uint32
+	ConnectionsPerSecond *uint32
+}
+
+// limitsFromAnnotations returns the limits configured by the annotations of
+// the service. Invalid values are reported in the error and not enforced.
+func limitsFromAnnotations(svc *corev1.Service) (serviceLimits, error) {
+	l := serviceLimits{}
+	var errs []error
+	for key, dst := range map[string]**uint32{
+		nodeportproxy.MaxConnectionsAnnotationKey:       &l.MaxConnections,
+		nodeportproxy.MaxPendingRequestsAnnotationKey:   &l.MaxPendingRequests,
+		nodeportproxy.MaxRequestsAnnotationKey:          &l.MaxRequests,
+		nodeportproxy.ConnectionsPerSecondAnnotationKey: &l.ConnectionsPerSecond,
+	} {
+		val, ok := svc.GetAnnotations()[key]
+		if !ok {
+			continue
+		}
+		v, err := strconv.ParseUint(val, 10, 32)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid value for %s: %v", key, err))
+			continue
+		}
+		if v == 0 && key == nodeportproxy.ConnectionsPerSecondAnnotationKey {
+			errs = append(errs, fmt.Errorf("invalid value for %s: must be greater than 0", key))
+			continue
+		}
+		v32 := uint32(v)
+		*dst = &v32
+	}
+	return l, utilerrors.NewAggregate(errs)
+}
@@ -134,6 +134,108 @@ func TestExtractPortHostMappingFromService(t *testing.T) {
 	}
 }
 
+func TestExtractHealthCheckMappingFromService(t *testing.T) {
+	var testcases = []struct {
+		name        string
+		annotations map[string]string
+		wantMapping healthCheckMapping
+		wantErr     bool
+	}{
+		{
+			name:        "Missing annotation",
+			wantMapping: healthCheckMapping{},
+		},
+		{
+			name: "HTTPS health check",
+			annotations: map[string]string{
+				nodeportproxy.HealthCheckMappingAnnotationKey: `{"secure": {"path": "/healthz", "tls": true}}`,
+			},
+			wantMapping: healthCheckMapping{"secure": {Path: "/healthz", TLS: true}},
+		},
+		{
+			name: "Annotation contains malformed json",
+			annotations: map[string]string{
+				nodeportproxy.HealthCheckMappingAnnotationKey: `{"secure": "/healthz"}`,
+			},
+			wantMapping: healthCheckMapping{},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := healthCheckMappingFromAnnotation(&corev1.Service{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("wantErr: %t, got %v", tt.wantErr, err)
+			}
+
+			if diff := deep.Equal(tt.wantMapping, m); diff != nil {
+				t.Errorf("Got unexpected health check mapping. Diff to expected: %v", diff)
+			}
+		})
+	}
+}
+
+func TestLimitsFromAnnotations(t *testing.T) {
+	uint32Ptr := func(v uint32) *uint32 { return &v }
+	var testcases = []struct {
+		name        string
+		annotations map[string]string
+		wantLimits  serviceLimits
+		wantErr     bool
+	}{
+		{
+			name:       "No limits",
+			wantLimits: serviceLimits{},
+		},
+		{
+			name: "All limits",
+			annotations: map[string]string{
+				nodeportproxy.MaxConnectionsAnnotationKey:       "100",
+				nodeportproxy.MaxPendingRequestsAnnotationKey:   "50",
+				nodeportproxy.MaxRequestsAnnotationKey:          "200",
+				nodeportproxy.ConnectionsPerSecondAnnotationKey: "10",
+			},
+			wantLimits: serviceLimits{
+				MaxConnections:       uint32Ptr(100),
+				MaxPendingRequests:   uint32Ptr(50),
+				MaxRequests:          uint32Ptr(200),
+				ConnectionsPerSecond: uint32Ptr(10),
+			},
+		},
+		{
+			name: "Invalid limit is ignored",
+			annotations: map[string]string{
+				nodeportproxy.MaxConnectionsAnnotationKey:       "-1",
+				nodeportproxy.ConnectionsPerSecondAnnotationKey: "10",
+			},
+			wantLimits: serviceLimits{
+				ConnectionsPerSecond: uint32Ptr(10),
+			},
+			wantErr: true,
+		},
+		{
+			name: "Zero connections per second",
+			annotations: map[string]string{
+				nodeportproxy.ConnectionsPerSecondAnnotationKey: "0",
+			},
+			wantLimits: serviceLimits{},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := limitsFromAnnotations(&corev1.Service{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("wantErr: %t, got %v", tt.wantErr, err)
+			}
+
+			if diff := deep.Equal(tt.wantLimits, l); diff != nil {
+				t.Errorf("Got unexpected limits. Diff to expected: %v", diff)
+			}
+		})
+	}
+}
+
 func TestPortHostMappingValidate(t *testing.T) {
 	var testcases = []struct {
 		name    string
@@ -18,8 +18,10 @@ package common
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/docker/distribution/reference"
@@ -32,9 +34,12 @@ import (
 	operatorv1alpha1 "k8c.io/kubermatic/v2/pkg/crd/operator/v1alpha1"
 	"k8c.io/kubermatic/v2/pkg/resources"
 	"k8c.io/kubermatic/v2/pkg/version"
+	"k8c.io/kubermatic/v2/pkg/version/rollout"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/utils/pointer"
 )
 
@@ -42,6 +47,7 @@ const (
 	DefaultPProfEndpoint                          = ":6600"
 	DefaultNodePortRange                          = "30000-32767"
 	DefaultEtcdVolumeSize                         = "5Gi"
+	DefaultDNATControllerBackend                  = "iptables"
 	DefaultAuthClientID                           = "kubermatic"
 	DefaultIngressClass                           = "nginx"
 	DefaultCABundleConfigMapName                  = "ca-bundle"
@@ -57,6 +63,8 @@ const (
 	DefaultEnvoyDockerRepository                  = "docker.io/envoyproxy/envoy-alpine"
 	DefaultMaximumParallelReconciles              = 10
 	DefaultS3Endpoint                             = "s3.amazonaws.com"
+	DefaultRolloutSoakTime                        = time.Hour
+	DefaultRolloutMaxUnhealthyPercentage          = 10
 
 	// DefaultNoProxy is a set of domains/networks that should never be
 	// routed through a proxy. All user-supplied values are appended to
@@ -376,6 +384,11 @@ func DefaultConfiguration(config *operatorv1alpha1.KubermaticConfiguration, logg
 		logger.Debugw("Defaulting field", "field", "userCluster.etcdVolumeSize", "value", copy.Spec.UserCluster.EtcdVolumeSize)
 	}
 
+	if copy.Spec.UserCluster.DNATControllerBackend == "" {
+		copy.Spec.UserCluster.DNATControllerBackend = DefaultDNATControllerBackend
+		logger.Debugw("Defaulting field", "field", "userCluster.dnatControllerBackend", "value", copy.Spec.UserCluster.DNATControllerBackend)
+	}
+
 	if copy.Spec.Ingress.ClassName == "" {
 		copy.Spec.Ingress.ClassName = DefaultIngressClass
 		logger.Debugw("Defaulting field", "field", "ingress.className", "value", copy.Spec.Ingress.ClassName)
@@ -610,6 +623,62 @@ func defaultVersioning(settings *operatorv1alpha1.KubermaticVersioningConfigurat
 		settings.Updates = defaults.Updates
 	}
 
+	if settings.Rollout != nil {
+		if settings.Rollout.SoakTime == nil {
+			settings.Rollout.SoakTime = &metav1.Duration{Duration: DefaultRolloutSoakTime}
+			logger.Debugw("Defaulting field", "field", key+".rollout.soakTime", "value", settings.Rollout.SoakTime.Duration)
+		}
+
+		if settings.Rollout.MaxUnhealthyPercentage == nil {
+			maxUnhealthyPercentage := DefaultRolloutMaxUnhealthyPercentage
+			settings.Rollout.MaxUnhealthyPercentage = &maxUnhealthyPercentage
+			logger.Debugw("Defaulting field", "field", key+".rollout.maxUnhealthyPercentage", "value", *settings.Rollout.MaxUnhealthyPercentage)
+		}
+
+		if err := validateRollout(settings.Rollout); err != nil {
+			return fmt.Errorf("invalid %s.rollout: %v", key, err)
+		}
+	}
+
+	return nil
+}
+
+func validateRollout(config *operatorv1alpha1.KubermaticRolloutConfiguration) error {
+	if p := *config.MaxUnhealthyPercentage; p < 0 || p > 100 {
+		return fmt.Errorf("maxUnhealthyPercentage must be between 0 and 100, got %d", p)
+	}
+
+	names := sets.NewString(rollout.FinalWaveName)
+	percentage := 0
+	for _, wave := range config.Waves {
+		if wave.Name == "" {
+			return errors.New("all waves must have a name")
+		}
+		if names.Has(wave.Name) {
+			return fmt.Errorf("wave name %q is not unique", wave.Name)
+		}
+		names.Insert(wave.Name)
+
+		if (wave.Selector == nil) == (wave.Percentage == nil) {
+			return fmt.Errorf("wave %s must have either a selector or a percentage", wave.Name)
+		}
+		if wave.Selector != nil {
+			if _, err := metav1.LabelSelectorAsSelector(wave.Selector); err != nil {
+				return fmt.Errorf("invalid selector of wave %s: %v", wave.Name, err)
+			}
+		}
+		if wave.Percentage != nil {
+			if *wave.Percentage <= 0 {
+				return fmt.Errorf("percentage of wave %s must be positive", wave.Name)
+			}
+			percentage += *wave.Percentage
+		}
+	}
+
+	if percentage > 100 {
+		return fmt.Errorf("the percentages of all waves must not exceed 100, got %d", percentage)
+	}
+
 	return nil
 }
 
@@ -657,19 +726,22 @@ volumeMounts:
 
 const DefaultNewBackupStoreContainer = `
 name: store-container
-image: d3fk/s3cmd@sha256:2061883abbf0ebcf0ea3d5d218558c9c229f212e9c08af4acdaa3758980eb67a
+image: quay.io/kubermatic/s3-storer:v0.1.8
 command:
 - /bin/sh
 - -c
 - |
-  set -e
-  s3cmd \
-    --ca-certs=/etc/ca-bundle/ca-bundle.pem \
-    --access_key=$ACCESS_KEY_ID \
-    --secret_key=$SECRET_ACCESS_KEY \
-    --host=$ENDPOINT \
-    --host-bucket='%(bucket).'$ENDPOINT \
-    put /backup/snapshot.db s3://$BUCKET_NAME/$CLUSTER-$BACKUP_TO_CREATE
+  set -euo pipefail
+
+  # the backend is configured by $BACKUP_DESTINATION_TYPE and its environment
+  # variables if the EtcdBackupConfig has a destination, S3 is used otherwise
+  s3-storeuploader upload \
+    --ca-bundle=/etc/ca-bundle/ca-bundle.pem \
+    --secure \
+    --endpoint "$ENDPOINT" \
+    --bucket "$BUCKET_NAME" \
+    --file /backup/snapshot.db \
+    --object-name "$CLUSTER-$BACKUP_TO_CREATE"
 env:
 - name: ACCESS_KEY_ID
   valueFrom:
@@ -698,31 +770,20 @@ volumeMounts:
 
 const DefaultNewBackupDeleteContainer = `
 name: delete-container
-image: d3fk/s3cmd@sha256:2061883abbf0ebcf0ea3d5d218558c9c229f212e9c08af4acdaa3758980eb67a
+image: quay.io/kubermatic/s3-storer:v0.1.8
 command:
 - /bin/sh
 - -c
 - |
-  s3cmd \
-    --ca-certs=/etc/ca-bundle/ca-bundle.pem \
-    --access_key=$ACCESS_KEY_ID \
-    --secret_key=$SECRET_ACCESS_KEY \
-    --host=$ENDPOINT \
-    --host-bucket='%(bucket).'$ENDPOINT \
-    del s3://$BUCKET_NAME/$CLUSTER-$BACKUP_TO_DELETE
-
-  case $? in
-  12)
-    # backup no longer exists, which is fine
-    exit 0
-    ;;
-  0)
-    exit 0
-    ;;
-  *)
-    exit $?
-    ;;
-  esac
+  set -euo pipefail
+
+  # deleting a backup which no longer exists is not an error
+  s3-storeuploader delete \
+    --ca-bundle=/etc/ca-bundle/ca-bundle.pem \
+    --secure \
+    --endpoint "$ENDPOINT" \
+    --bucket "$BUCKET_NAME" \
+    --object-name "$CLUSTER-$BACKUP_TO_DELETE"
 env:
 - name: ACCESS_KEY_ID
   valueFrom:
@@ -800,6 +861,12 @@ items:
     name: canal
     labels:
       addons.kubermatic.io/ensure: true
+- apiVersion: kubermatic.k8s.io/v1
+  kind: Addon
+  metadata:
+    name: cilium
+    labels:
+      addons.kubermatic.io/ensure: true
 - apiVersion: kubermatic.k8s.io/v1
   kind: Addon
   metadata:
@@ -851,13 +918,17 @@ type versionsYAML struct {
 }
 
 func CreateVersionsYAML(config *operatorv1alpha1.KubermaticVersionsConfiguration) (string, error) {
-	output := versionsYAML{
-		Versions: make([]*version.Version, 0),
-	}
+	return toYAML(versionsYAML{
+		Versions: managerVersions(config),
+	})
+}
+
+func managerVersions(config *operatorv1alpha1.KubermaticVersionsConfiguration) []*version.Version {
+	versions := make([]*version.Version, 0)
 
 	appendOrchestrator := func(cfg *operatorv1alpha1.KubermaticVersioningConfiguration, kind string) {
 		for _, v := range cfg.Versions {
-			output.Versions = append(output.Versions, &version.Version{
+			versions = append(versions, &version.Version{
 				Version: v,
 				Default: v.Equal(cfg.Default),
 				Type:    kind,
@@ -866,17 +937,23 @@ func CreateVersionsYAML(config *operatorv1alpha1.KubermaticVersionsConfiguration
 	}
 
 	appendOrchestrator(&config.Kubernetes, kubermaticapiv1.KubernetesClusterType)
-	return toYAML(output)
+	return versions
 }
 
 type updatesYAML struct {
 	Updates []*version.Update `json:"updates"`
+	Rollout *version.Rollout  `json:"rollout,omitempty"`
 }
 
 func CreateUpdatesYAML(config *operatorv1alpha1.KubermaticVersionsConfiguration) (string, error) {
-	output := updatesYAML{
-		Updates: make([]*version.Update, 0),
-	}
+	return toYAML(updatesYAML{
+		Updates: managerUpdates(config),
+		Rollout: managerRollout(config.Kubernetes.Rollout),
+	})
+}
+
+func managerUpdates(config *operatorv1alpha1.KubermaticVersionsConfiguration) []*version.Update {
+	updates := make([]*version.Update, 0)
 
 	appendOrchestrator := func(cfg *operatorv1alpha1.KubermaticVersioningConfiguration, kind string) {
 		for _, u := range cfg.Updates {
@@ -885,7 +962,7 @@ func CreateUpdatesYAML(config *operatorv1alpha1.KubermaticVersionsConfiguration)
 			automaticNodeUpdate := (u.AutomaticNodeUpdate != nil && *u.AutomaticNodeUpdate)
 			automatic := (u.Automatic != nil && *u.Automatic) || automaticNodeUpdate
 
-			output.Updates = append(output.Updates, &version.Update{
+			updates = append(updates, &version.Update{
 				From:                u.From,
 				To:                  u.To,
 				Automatic:           automatic,
@@ -896,7 +973,36 @@ func CreateUpdatesYAML(config *operatorv1alpha1.KubermaticVersionsConfiguration)
 	}
 
 	appendOrchestrator(&config.Kubernetes, kubermaticapiv1.KubernetesClusterType)
-	return toYAML(output)
+	return updates
+}
+
+func managerRollout(config *operatorv1alpha1.KubermaticRolloutConfiguration) *version.Rollout {
+	if config == nil {
+		return nil
+	}
+
+	rollout := &version.Rollout{
+		SoakTime:               *config.SoakTime,
+		MaxUnhealthyPercentage: *config.MaxUnhealthyPercentage,
+	}
+	for _, wave := range config.Waves {
+		w := version.RolloutWave{
+			Name:     wave.Name,
+			Selector: wave.Selector,
+		}
+		if wave.Percentage != nil {
+			w.Percentage = *wave.Percentage
+		}
+		rollout.Waves = append(rollout.Waves, w)
+	}
+
+	return rollout
+}
+
+// VersionManager returns a version manager for the defaulted configuration, which is
+// equivalent to the one the seed-controller-manager loads from the generated YAML files.
+func VersionManager(config *operatorv1alpha1.KubermaticVersionsConfiguration) *version.Manager {
+	return version.NewWithRollout(managerVersions(config), managerUpdates(config), managerRollout(config.Kubernetes.Rollout))
 }
 
 func toYAML(data interface{}) (string, error) {
@@ -328,3 +328,32 @@ func WebhookCABundle(cfg *operatorv1alpha1.KubermaticConfiguration, client ctrlr
 
 	return cert, nil
 }
+
+// KubernetesAddonsInitContainer returns an init container which copies the Kubernetes addons
+// into the given volume, where they are available at /opt/addons/kubernetes.
+func KubernetesAddonsInitContainer(cfg operatorv1alpha1.KubermaticAddonConfiguration, addonVolume string, version string) corev1.Container {
+	return corev1.Container{
+		Name:    "copy-addons-kubernetes",
+		Image:   cfg.DockerRepository + ":" + AddonDockerTag(cfg, version),
+		Command: []string{"/bin/sh"},
+		Args: []string{
+			"-c",
+			"mkdir -p /opt/addons/kubernetes && cp -r /addons/* /opt/addons/kubernetes",
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      addonVolume,
+				MountPath: "/opt/addons/",
+			},
+		},
+	}
+}
+
+// AddonDockerTag returns the tag of the addons image for the given KKP version.
+func AddonDockerTag(cfg operatorv1alpha1.KubermaticAddonConfiguration, version string) string {
+	if cfg.DockerTagSuffix != "" {
+		version = fmt.Sprintf("%s-%s", version, cfg.DockerTagSuffix)
+	}
+
+	return version
+}
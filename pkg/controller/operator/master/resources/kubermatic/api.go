@@ -72,7 +72,14 @@ func APIDeploymentCreator(cfg *operatorv1alpha1.KubermaticConfiguration, workerN
 
 			d.Spec.Template.Spec.ServiceAccountName = serviceAccountName
 
+			sharedAddonVolume := "addons"
 			volumes := []corev1.Volume{
+				{
+					Name: sharedAddonVolume,
+					VolumeSource: corev1.VolumeSource{
+						EmptyDir: &corev1.EmptyDirVolumeSource{},
+					},
+				},
 				{
 					Name: "extra-files",
 					VolumeSource: corev1.VolumeSource{
@@ -94,6 +101,11 @@ func APIDeploymentCreator(cfg *operatorv1alpha1.KubermaticConfiguration, workerN
 			}
 
 			volumeMounts := []corev1.VolumeMount{
+				{
+					Name:      sharedAddonVolume,
+					MountPath: "/opt/addons/",
+					ReadOnly:  true,
+				},
 				{
 					MountPath: "/opt/extra-files/",
 					Name:      "extra-files",
@@ -126,6 +138,7 @@ func APIDeploymentCreator(cfg *operatorv1alpha1.KubermaticConfiguration, workerN
 				fmt.Sprintf("-feature-gates=%s", featureGates(cfg)),
 				fmt.Sprintf("-pprof-listen-address=%s", *cfg.Spec.API.PProfEndpoint),
 				fmt.Sprintf("-accessible-addons=%s", strings.Join(cfg.Spec.API.AccessibleAddons, ",")),
+				"-kubernetes-addons-path=/opt/addons/kubernetes",
 			}
 
 			// Only EE does support dynamic-datacenters
@@ -133,6 +146,18 @@ func APIDeploymentCreator(cfg *operatorv1alpha1.KubermaticConfiguration, workerN
 				args = append(args, "-dynamic-datacenters=true")
 			}
 
+			if cfg.Spec.Auth.ServiceAccountVerificationKeys != "" {
+				args = append(args, fmt.Sprintf("-service-account-verification-keys=%s", cfg.Spec.Auth.ServiceAccountVerificationKeys))
+			}
+
+			if cfg.Spec.API.Audit.WebhookURL != "" {
+				args = append(args, fmt.Sprintf("-audit-webhook-url=%s", cfg.Spec.API.Audit.WebhookURL))
+			}
+
+			if cfg.Spec.API.Audit.KubernetesEvents {
+				args = append(args, "-audit-kubernetes-events")
+			}
+
 			if cfg.Spec.API.DebugLog {
 				args = append(args, "-v=4", "-log-debug=true")
 			} else {
@@ -154,6 +179,9 @@ func APIDeploymentCreator(cfg *operatorv1alpha1.KubermaticConfiguration, workerN
 			}
 
 			d.Spec.Template.Spec.Volumes = volumes
+			d.Spec.Template.Spec.InitContainers = []corev1.Container{
+				common.KubernetesAddonsInitContainer(cfg.Spec.UserCluster.Addons.Kubernetes, sharedAddonVolume, versions.Kubermatic),
+			}
 			d.Spec.Template.Spec.Containers = []corev1.Container{
 				{
 					Name:    "api",
@@ -188,6 +188,11 @@ func createSeedWatches(controller controller.Controller, seedName string, seedMa
 		return err
 	}
 
+	// the progress of automatic updates is recorded in the KubermaticConfiguration
+	if err := watch(&kubermaticv1.Cluster{}, clusterRolloutPredicate); err != nil {
+		return err
+	}
+
 	// namespaces are not managed by the operator and so can use neither namespacePredicate
 	// nor ManagedByPredicate, but still need to get their labels reconciled
 	if err := watch(&corev1.Namespace{}, predicateutil.ByName(namespace)); err != nil {
@@ -172,6 +172,10 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, seed
 		return err
 	}
 
+	if err := r.reconcileRolloutStatus(ctx, &config, defaulted, seedName, seedClient); err != nil {
+		return fmt.Errorf("failed to reconcile rollout status: %v", err)
+	}
+
 	return nil
 }
 
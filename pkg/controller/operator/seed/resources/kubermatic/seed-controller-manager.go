@@ -76,6 +76,7 @@ func SeedControllerManagerDeploymentCreator(workerName string, versions kubermat
 				fmt.Sprintf("-worker-name=%s", workerName),
 				fmt.Sprintf("-kubermatic-image=%s", cfg.Spec.UserCluster.KubermaticDockerRepository),
 				fmt.Sprintf("-dnatcontroller-image=%s", cfg.Spec.UserCluster.DNATControllerDockerRepository),
+				fmt.Sprintf("-dnatcontroller-backend=%s", cfg.Spec.UserCluster.DNATControllerBackend),
 				fmt.Sprintf("-etcd-launcher-image=%s", cfg.Spec.UserCluster.EtcdLauncherDockerRepository),
 				fmt.Sprintf("-overwrite-registry=%s", cfg.Spec.UserCluster.OverwriteRegistry),
 				fmt.Sprintf("-apiserver-default-replicas=%d", *cfg.Spec.UserCluster.APIServerReplicas),
@@ -279,7 +280,7 @@ func SeedControllerManagerDeploymentCreator(workerName string, versions kubermat
 
 			d.Spec.Template.Spec.Volumes = volumes
 			d.Spec.Template.Spec.InitContainers = []corev1.Container{
-				createKubernetesAddonsInitContainer(cfg.Spec.UserCluster.Addons.Kubernetes, sharedAddonVolume, versions.Kubermatic),
+				common.KubernetesAddonsInitContainer(cfg.Spec.UserCluster.Addons.Kubernetes, sharedAddonVolume, versions.Kubermatic),
 			}
 			d.Spec.Template.Spec.Containers = []corev1.Container{
 				{
@@ -305,32 +306,6 @@ func SeedControllerManagerDeploymentCreator(workerName string, versions kubermat
 	}
 }
 
-func createKubernetesAddonsInitContainer(cfg operatorv1alpha1.KubermaticAddonConfiguration, addonVolume string, version string) corev1.Container {
-	return corev1.Container{
-		Name:    "copy-addons-kubernetes",
-		Image:   cfg.DockerRepository + ":" + getAddonDockerTag(cfg, version),
-		Command: []string{"/bin/sh"},
-		Args: []string{
-			"-c",
-			"mkdir -p /opt/addons/kubernetes && cp -r /addons/* /opt/addons/kubernetes",
-		},
-		VolumeMounts: []corev1.VolumeMount{
-			{
-				Name:      addonVolume,
-				MountPath: "/opt/addons/",
-			},
-		},
-	}
-}
-
-func getAddonDockerTag(cfg operatorv1alpha1.KubermaticAddonConfiguration, version string) string {
-	if cfg.DockerTagSuffix != "" {
-		version = fmt.Sprintf("%s-%s", version, cfg.DockerTagSuffix)
-	}
-
-	return version
-}
-
 func SeedControllerManagerPDBCreator(cfg *operatorv1alpha1.KubermaticConfiguration) reconciling.NamedPodDisruptionBudgetCreatorGetter {
 	name := "kubermatic-seed-controller-manager"
 
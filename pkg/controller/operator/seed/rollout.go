@@ -0,0 +1,148 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package seed
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"k8c.io/kubermatic/v2/pkg/controller/operator/common"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	operatorv1alpha1 "k8c.io/kubermatic/v2/pkg/crd/operator/v1alpha1"
+	"k8c.io/kubermatic/v2/pkg/version/rollout"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// reconcileRolloutStatus records the progress of the automatic updates in the clusters of the seed
+// in the status of the KubermaticConfiguration. The seed-controller-manager evaluates the rollout
+// the same way to decide which clusters are updated.
+func (r *Reconciler) reconcileRolloutStatus(ctx context.Context, config *operatorv1alpha1.KubermaticConfiguration, defaulted *operatorv1alpha1.KubermaticConfiguration, seedName string, client ctrlruntimeclient.Client) error {
+	var seedRollouts []operatorv1alpha1.KubermaticRolloutStatus
+
+	if defaulted.Spec.Versions.Kubernetes.Rollout != nil {
+		clusters := &kubermaticv1.ClusterList{}
+		if err := client.List(ctx, clusters); err != nil {
+			return fmt.Errorf("failed to list clusters: %v", err)
+		}
+
+		plan, err := rollout.Evaluate(common.VersionManager(&defaulted.Spec.Versions), clusters.Items, time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to evaluate the rollout: %v", err)
+		}
+
+		for _, rollout := range plan.Rollouts {
+			seedRollouts = append(seedRollouts, rolloutStatus(seedName, rollout))
+		}
+	}
+
+	oldConfig := config.DeepCopy()
+
+	rollouts := seedRollouts
+	for _, status := range config.Status.Rollouts {
+		if status.Seed != seedName {
+			rollouts = append(rollouts, status)
+		}
+	}
+	sort.SliceStable(rollouts, func(i, j int) bool {
+		return rollouts[i].Seed < rollouts[j].Seed
+	})
+	config.Status.Rollouts = rollouts
+
+	if equality.Semantic.DeepEqual(oldConfig.Status, config.Status) {
+		return nil
+	}
+
+	// all seeds are reconciled in parallel, so prevent them from overwriting each others' status
+	patch := ctrlruntimeclient.MergeFromWithOptions(oldConfig, ctrlruntimeclient.MergeFromWithOptimisticLock{})
+	if err := r.masterClient.Status().Patch(ctx, config, patch); err != nil {
+		return fmt.Errorf("failed to update rollout status: %v", err)
+	}
+
+	return nil
+}
+
+func rolloutStatus(seedName string, rollout *rollout.Rollout) operatorv1alpha1.KubermaticRolloutStatus {
+	status := operatorv1alpha1.KubermaticRolloutStatus{
+		Seed:    seedName,
+		Version: rollout.Version,
+		Paused:  rollout.Paused,
+		Message: rollout.Message,
+	}
+	if rollout.CurrentWave >= 0 {
+		status.CurrentWave = rollout.Waves[rollout.CurrentWave].Name
+	}
+
+	for _, wave := range rollout.Waves {
+		status.Waves = append(status.Waves, operatorv1alpha1.KubermaticRolloutWaveStatus{
+			Name:           wave.Name,
+			Clusters:       wave.Clusters,
+			Updated:        wave.Updated,
+			Unhealthy:      wave.Unhealthy,
+			CompletionTime: wave.CompletionTime,
+		})
+	}
+
+	return status
+}
+
+// clusterRolloutPredicate filters the cluster events which can change the progress of a rollout
+var clusterRolloutPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldCluster, ok := e.ObjectOld.(*kubermaticv1.Cluster)
+		if !ok {
+			return false
+		}
+		newCluster, ok := e.ObjectNew.(*kubermaticv1.Cluster)
+		if !ok {
+			return false
+		}
+
+		return rolloutRelevantState(oldCluster) != rolloutRelevantState(newCluster) ||
+			!reflect.DeepEqual(oldCluster.Labels, newCluster.Labels) ||
+			!equality.Semantic.DeepEqual(oldCluster.Status.Upgrade, newCluster.Status.Upgrade)
+	},
+}
+
+// rolloutState contains the fields of a cluster, besides its labels and upgrade status,
+// which are considered by a rollout
+type rolloutState struct {
+	version       string
+	paused        bool
+	deleted       bool
+	healthy       bool
+	upToDate      bool
+	upgradeHalted bool
+}
+
+func rolloutRelevantState(cluster *kubermaticv1.Cluster) rolloutState {
+	return rolloutState{
+		version:       cluster.Spec.Version.String(),
+		paused:        cluster.Spec.Pause,
+		deleted:       cluster.DeletionTimestamp != nil,
+		healthy:       cluster.Status.ExtendedHealth.AllHealthy(),
+		upToDate:      cluster.Status.HasConditionValue(kubermaticv1.ClusterConditionSeedResourcesUpToDate, corev1.ConditionTrue),
+		upgradeHalted: cluster.Status.HasConditionValue(kubermaticv1.ClusterConditionUpgradeHalted, corev1.ConditionTrue),
+	}
+}
@@ -17,18 +17,13 @@ limitations under the License.
 package addon
 
 import (
-	"bytes"
 	"context"
 	"fmt"
-	"io/ioutil"
-	"os"
-	"os/exec"
 	"path"
 	"reflect"
 	"strings"
 	"time"
 
-	"github.com/ghodss/yaml"
 	"go.uber.org/zap"
 
 	addonutils "k8c.io/kubermatic/v2/pkg/addon"
@@ -250,26 +245,56 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, addo
 		if err := r.removeCleanupFinalizer(ctx, log, addon); err != nil {
 			return nil, fmt.Errorf("failed to ensure that the cleanup finalizer got removed from the addon: %v", err)
 		}
+		if err := r.ensureClusterAddonsHealth(ctx, addon, cluster); err != nil {
+			return nil, fmt.Errorf("failed to update the addons health of the cluster: %v", err)
+		}
 		return nil, nil
 	}
 	// This is true when the addon: 1) is fully deployed, 2) doesn't have a `addonEnsureLabelKey` set to true.
 	// we do this to allow users to "edit/delete" resources deployed by unlabeled addons,
-	// while we enfornce the labeled ones
+	// while we enfornce the labeled ones. The readiness of their workloads is still reported.
 	if addonResourcesCreated(addon) && !hasEnsureResourcesLabel(addon) {
-		return nil, nil
+		return r.ensureReadyConditionIsSet(ctx, log, addon, cluster)
+	}
+
+	metadata, err := addonutils.LoadMetadata(path.Join(r.kubernetesAddonDir, addon.Spec.Name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load addon metadata: %v", err)
+	}
+	message, err := incompatibility(addon, cluster, metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check addon compatibility: %v", err)
+	}
+	if message != "" {
+		log.Debugw("Skipping addon installation", "reason", message)
+		return nil, r.setReadyCondition(ctx, log, addon, cluster, false, message)
+	}
+	if isHeldBack(addon, metadata) {
+		log.Debugw("Addon is pinned, reconciling the installed version", "version", addon.Spec.Version, "available", metadata.Version)
+		if err := r.ensureInstalledVersion(ctx, log, addon, cluster); err != nil {
+			return nil, fmt.Errorf("failed to reconcile the installed version of the addon: %v", err)
+		}
+		return r.ensureReadyConditionIsSet(ctx, log, addon, cluster)
+	}
+	result, err := r.ensureDependenciesAreReady(ctx, log, addon, cluster, metadata)
+	if err != nil || result != nil {
+		return result, err
 	}
 
 	// Reconciling
-	if err := r.ensureIsInstalled(ctx, log, addon, cluster); err != nil {
+	if err := r.ensureIsInstalled(ctx, log, addon, cluster, metadata.Version); err != nil {
 		return nil, fmt.Errorf("failed to deploy the addon manifests into the cluster: %v", err)
 	}
+	if err := r.ensureVersionStatus(ctx, addon, metadata.Version); err != nil {
+		return nil, fmt.Errorf("failed to set the addon version: %v", err)
+	}
 	if err := r.ensureFinalizerIsSet(ctx, addon); err != nil {
 		return nil, fmt.Errorf("failed to ensure that the cleanup finalizer exists on the addon: %v", err)
 	}
 	if err := r.ensureResourcesCreatedConditionIsSet(ctx, addon); err != nil {
 		return nil, fmt.Errorf("failed to set add ResourcesCreated Condition: %v", err)
 	}
-	return nil, nil
+	return r.ensureReadyConditionIsSet(ctx, log, addon, cluster)
 }
 
 func (r *Reconciler) removeCleanupFinalizer(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon) error {
@@ -339,23 +364,9 @@ func (r *Reconciler) getAddonManifests(ctx context.Context, log *zap.SugaredLogg
 	return allManifests, nil
 }
 
-// combineManifests returns all manifests combined into a multi document yaml
-func (r *Reconciler) combineManifests(manifests []*bytes.Buffer) *bytes.Buffer {
-	parts := make([]string, len(manifests))
-	for i, m := range manifests {
-		s := m.String()
-		s = strings.TrimSuffix(s, "\n")
-		s = strings.TrimSpace(s)
-		parts[i] = s
-	}
-
-	return bytes.NewBufferString(strings.Join(parts, "\n---\n") + "\n")
-}
-
-// ensureAddonLabelOnManifests adds the addonLabelKey label to all manifests.
-// For this to happen we need to decode all yaml files to json, parse them, add the label and finally encode to yaml again
-func (r *Reconciler) ensureAddonLabelOnManifests(addon *kubermaticv1.Addon, manifests []runtime.RawExtension) ([]*bytes.Buffer, error) {
-	var rawManifests []*bytes.Buffer
+// ensureAddonLabelOnManifests parses all manifests and adds the addonLabelKey label to them
+func (r *Reconciler) ensureAddonLabelOnManifests(addon *kubermaticv1.Addon, manifests []runtime.RawExtension) ([]*metav1unstructured.Unstructured, error) {
+	var objects []*metav1unstructured.Unstructured
 
 	wantLabels := r.getAddonLabel(addon)
 	for _, m := range manifests {
@@ -375,22 +386,10 @@ func (r *Reconciler) ensureAddonLabelOnManifests(addon *kubermaticv1.Addon, mani
 		}
 		parsedUnstructuredObj.SetLabels(existingLabels)
 
-		jsonBuffer := &bytes.Buffer{}
-		if err := metav1unstructured.UnstructuredJSONScheme.Encode(parsedUnstructuredObj, jsonBuffer); err != nil {
-			return nil, fmt.Errorf("encoding json failed: %v", err)
-		}
-
-		// Must be encoding back to yaml, otherwise kubectl fails to apply because it tries to parse the whole
-		// thing as json
-		yamlBytes, err := yaml.JSONToYAML(jsonBuffer.Bytes())
-		if err != nil {
-			return nil, err
-		}
-
-		rawManifests = append(rawManifests, bytes.NewBuffer(yamlBytes))
+		objects = append(objects, parsedUnstructuredObj)
 	}
 
-	return rawManifests, nil
+	return objects, nil
 }
 
 func (r *Reconciler) getAddonLabel(addon *kubermaticv1.Addon) map[string]string {
@@ -399,106 +398,72 @@ func (r *Reconciler) getAddonLabel(addon *kubermaticv1.Addon) map[string]string
 	}
 }
 
-type fileHandlingDone func()
-
-func getFileDeleteFinalizer(log *zap.SugaredLogger, filename string) fileHandlingDone {
-	return func() {
-		if err := os.RemoveAll(filename); err != nil {
-			log.Errorw("Failed to delete file", zap.Error(err), "file", filename)
-		}
-	}
-}
-
-func (r *Reconciler) writeCombinedManifest(log *zap.SugaredLogger, manifest *bytes.Buffer, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) (string, fileHandlingDone, error) {
-	//Write combined Manifest to disk
-	manifestFilename := path.Join("/tmp", fmt.Sprintf("cluster-%s-%s.yaml", cluster.Name, addon.Name))
-	if err := ioutil.WriteFile(manifestFilename, manifest.Bytes(), 0644); err != nil {
-		return "", nil, fmt.Errorf("failed to write combined manifest to %s: %v", manifestFilename, err)
-	}
-	log.Debugw("Wrote combined manifest", "file", manifestFilename)
-
-	return manifestFilename, getFileDeleteFinalizer(log, manifestFilename), nil
-}
-
-func (r *Reconciler) writeAdminKubeconfig(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) (string, fileHandlingDone, error) {
-	// Write kubeconfig to disk
-	kubeconfig, err := r.KubeconfigProvider.GetAdminKubeconfig(ctx, cluster)
-	if err != nil {
-		return "", nil, fmt.Errorf("failed to get admin kubeconfig for cluster %s: %v", cluster.Name, err)
-	}
-	kubeconfigFilename := path.Join("/tmp", fmt.Sprintf("cluster-%s-addon-%s-kubeconfig", cluster.Name, addon.Name))
-	if err := ioutil.WriteFile(kubeconfigFilename, kubeconfig, 0644); err != nil {
-		return "", nil, fmt.Errorf("failed to write admin kubeconfig for cluster %s: %v", cluster.Name, err)
-	}
-	log.Debugw("Wrote admin kubeconfig", "file", kubeconfigFilename)
-
-	return kubeconfigFilename, getFileDeleteFinalizer(log, kubeconfigFilename), nil
-}
-
-func (r *Reconciler) setupManifestInteraction(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) (string, string, fileHandlingDone, error) {
+func (r *Reconciler) getAddonObjects(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) ([]*metav1unstructured.Unstructured, error) {
 	manifests, err := r.getAddonManifests(ctx, log, addon, cluster)
 	if err != nil {
-		return "", "", nil, fmt.Errorf("failed to get addon manifests: %v", err)
+		return nil, fmt.Errorf("failed to get addon manifests: %v", err)
 	}
 
-	rawManifests, err := r.ensureAddonLabelOnManifests(addon, manifests)
+	objects, err := r.ensureAddonLabelOnManifests(addon, manifests)
 	if err != nil {
-		return "", "", nil, fmt.Errorf("failed to add the addon specific label to all addon resources: %v", err)
+		return nil, fmt.Errorf("failed to add the addon specific label to all addon resources: %v", err)
 	}
 
-	rawManifest := r.combineManifests(rawManifests)
-	manifestFilename, manifestDone, err := r.writeCombinedManifest(log, rawManifest, addon, cluster)
-	if err != nil {
-		return "", "", nil, fmt.Errorf("failed to write all addon resources into a combined manifest file: %v", err)
-	}
+	return objects, nil
+}
 
-	kubeconfigFilename, kubeconfigDone, err := r.writeAdminKubeconfig(ctx, log, addon, cluster)
+func (r *Reconciler) getApplier(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) (*applier, error) {
+	userClusterClient, err := r.KubeconfigProvider.GetClient(ctx, cluster)
 	if err != nil {
-		return "", "", nil, fmt.Errorf("failed to write the admin kubeconfig to the local filesystem: %v", err)
-	}
-
-	done := func() {
-		kubeconfigDone()
-		manifestDone()
+		return nil, fmt.Errorf("failed to get client for usercluster: %v", err)
 	}
-	return kubeconfigFilename, manifestFilename, done, nil
-}
 
-func (r *Reconciler) getApplyCommand(ctx context.Context, kubeconfigFilename, manifestFilename string, selector fmt.Stringer) *exec.Cmd {
-	// kubectl apply --prune -f manifest.yaml -l app=nginx
-	cmd := exec.CommandContext(ctx, "kubectl", "--kubeconfig", kubeconfigFilename, "apply", "--prune", "-f", manifestFilename, "-l", selector.String())
-	return cmd
+	return &applier{client: userClusterClient, log: log}, nil
 }
 
-func (r *Reconciler) ensureIsInstalled(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) error {
-	kubeconfigFilename, manifestFilename, done, err := r.setupManifestInteraction(ctx, log, addon, cluster)
+func (r *Reconciler) ensureIsInstalled(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster, version string) error {
+	objects, err := r.getAddonObjects(ctx, log, addon, cluster)
 	if err != nil {
 		return err
 	}
-	defer done()
-
-	d, err := ioutil.ReadFile(manifestFilename)
-	if err != nil {
+	if err := r.applyObjects(ctx, log, addon, cluster, objects); err != nil {
 		return err
 	}
-	sd := strings.TrimSpace(string(d))
-	if len(sd) == 0 {
+	return r.storeAppliedObjects(ctx, addon, version, objects)
+}
+
+func (r *Reconciler) applyObjects(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster, objects []*metav1unstructured.Unstructured) error {
+	if len(objects) == 0 {
 		log.Debug("Skipping addon installation as the manifest is empty after parsing")
 		return nil
 	}
 
-	// We delete all resources with this label which are not in the combined manifest
+	addonApplier, err := r.getApplier(ctx, log, cluster)
+	if err != nil {
+		return err
+	}
+
+	// We delete all resources with this label which are not in the manifests
 	selector := labels.SelectorFromSet(r.getAddonLabel(addon))
-	cmd := r.getApplyCommand(ctx, kubeconfigFilename, manifestFilename, selector)
-	cmdLog := log.With("cmd", strings.Join(cmd.Args, " "))
 
-	cmdLog.Debug("Applying manifest...")
-	out, err := cmd.CombinedOutput()
-	cmdLog.Debugw("Finished executing command", "output", string(out))
-	if err != nil {
-		return fmt.Errorf("failed to execute '%s' for addon %s of cluster %s: %v\n%s", strings.Join(cmd.Args, " "), addon.Name, cluster.Name, err, string(out))
+	log.Debug("Applying manifests...")
+	results, applyErr := addonApplier.apply(ctx, objects, selector, addon.Status.Resources)
+	if err := r.ensureResourcesStatus(ctx, addon, results); err != nil {
+		return fmt.Errorf("failed to update the resources status: %v", err)
 	}
-	return err
+	if applyErr != nil {
+		return fmt.Errorf("failed to apply manifests of addon %s of cluster %s: %v", addon.Name, cluster.Name, applyErr)
+	}
+	return nil
+}
+
+func (r *Reconciler) ensureResourcesStatus(ctx context.Context, addon *kubermaticv1.Addon, results []kubermaticv1.AddonResourceStatus) error {
+	if reflect.DeepEqual(addon.Status.Resources, results) {
+		return nil
+	}
+	oldAddon := addon.DeepCopy()
+	addon.Status.Resources = results
+	return r.Client.Patch(ctx, addon, ctrlruntimeclient.MergeFrom(oldAddon))
 }
 
 func (r *Reconciler) ensureFinalizerIsSet(ctx context.Context, addon *kubermaticv1.Addon) error {
@@ -517,12 +482,12 @@ func (r *Reconciler) ensureResourcesCreatedConditionIsSet(ctx context.Context, a
 		return nil
 	}
 	oldAddon := addon.DeepCopy()
-	setAddonCodition(addon, kubermaticv1.AddonResourcesCreated, corev1.ConditionTrue)
+	setAddonCodition(addon, kubermaticv1.AddonResourcesCreated, corev1.ConditionTrue, "")
 	return r.Client.Patch(ctx, addon, ctrlruntimeclient.MergeFrom(oldAddon))
 }
 
 func (r *Reconciler) cleanupManifests(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) error {
-	kubeconfigFilename, manifestFilename, done, err := r.setupManifestInteraction(ctx, log, addon, cluster)
+	objects, err := r.getAddonObjects(ctx, log, addon, cluster)
 	if err != nil {
 		// FIXME: use a dedicated error type and proper error unwrapping when we have the technology to do it
 		if strings.Contains(err.Error(), "no such file or directory") { // if the manifest is already deleted, that's ok
@@ -531,16 +496,15 @@ func (r *Reconciler) cleanupManifests(ctx context.Context, log *zap.SugaredLogge
 		}
 		return err
 	}
-	defer done()
 
-	cmd := deleteCommand(ctx, kubeconfigFilename, manifestFilename)
-	cmdLog := log.With("cmd", strings.Join(cmd.Args, " "))
-
-	cmdLog.Debug("Deleting resources...")
-	out, err := cmd.CombinedOutput()
-	cmdLog.Debugw("Finished executing command", "output", string(out))
+	addonApplier, err := r.getApplier(ctx, log, cluster)
 	if err != nil {
-		return fmt.Errorf("failed to execute '%s' for addon %s of cluster %s: %v\n%s", strings.Join(cmd.Args, " "), addon.Name, cluster.Name, err, string(out))
+		return err
+	}
+
+	log.Debug("Deleting resources...")
+	if err := addonApplier.delete(ctx, objects); err != nil {
+		return fmt.Errorf("failed to delete manifests of addon %s of cluster %s: %v", addon.Name, cluster.Name, err)
 	}
 	return nil
 }
@@ -577,16 +541,13 @@ func (r *Reconciler) ensureRequiredResourceTypesExist(ctx context.Context, log *
 	return nil, nil
 }
 
-func deleteCommand(ctx context.Context, kubeconfigFilename, manifestFilename string) *exec.Cmd {
-	return exec.CommandContext(ctx, "kubectl", "--kubeconfig", kubeconfigFilename, "delete", "-f", manifestFilename, "--ignore-not-found")
-}
-
-func setAddonCodition(a *kubermaticv1.Addon, condType kubermaticv1.AddonConditionType, status corev1.ConditionStatus) {
+func setAddonCodition(a *kubermaticv1.Addon, condType kubermaticv1.AddonConditionType, status corev1.ConditionStatus, message string) {
 	idx, cond := getAddonCondition(a, condType)
 	if cond == nil {
 		cond = &kubermaticv1.AddonCondition{}
 		cond.Type = condType
 		cond.Status = status
+		cond.Message = message
 		cond.LastHeartbeatTime = metav1.Now()
 		cond.LastTransitionTime = metav1.Now()
 		a.Status.Conditions = append(a.Status.Conditions, *cond)
@@ -596,6 +557,7 @@ func setAddonCodition(a *kubermaticv1.Addon, condType kubermaticv1.AddonConditio
 		cond.LastTransitionTime = metav1.Now()
 		cond.Status = status
 	}
+	cond.Message = message
 	cond.LastHeartbeatTime = metav1.Now()
 	a.Status.Conditions[idx] = *cond
 }
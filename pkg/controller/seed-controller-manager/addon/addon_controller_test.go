@@ -27,6 +27,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/ghodss/yaml"
+
 	clusterclient "k8c.io/kubermatic/v2/pkg/cluster/client"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
@@ -34,7 +36,6 @@ import (
 	"k8c.io/kubermatic/v2/pkg/semver"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	kyaml "k8s.io/apimachinery/pkg/util/yaml"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -116,11 +117,6 @@ spec:
 `
 )
 
-var (
-	// testManifest1 & testManifest3 have a linebreak at the end, testManifest2 not
-	combinedTestManifest = fmt.Sprintf("%s---\n%s\n---\n%s", testManifests[0], testManifests[1], testManifests[2])
-)
-
 type fakeKubeconfigProvider struct{}
 
 func (f *fakeKubeconfigProvider) GetAdminKubeconfig(_ context.Context, c *kubermaticv1.Cluster) ([]byte, error) {
@@ -131,21 +127,6 @@ func (f *fakeKubeconfigProvider) GetClient(_ context.Context, c *kubermaticv1.Cl
 	return nil, errors.New("not implemented")
 }
 
-func TestController_combineManifests(t *testing.T) {
-	controller := &Reconciler{}
-
-	var manifests []*bytes.Buffer
-	for _, m := range testManifests {
-		manifests = append(manifests, bytes.NewBufferString(m))
-	}
-
-	manifest := controller.combineManifests(manifests)
-
-	if manifest.String() != combinedTestManifest {
-		t.Fatalf("invalid combined manifest returned. Expected \n%s, Got \n%s", combinedTestManifest, manifest.String())
-	}
-}
-
 func setupTestCluster(cidrBlock string) *kubermaticv1.Cluster {
 	return &kubermaticv1.Cluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -395,18 +376,12 @@ func TestController_ensureAddonLabelOnManifests(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if labeledManifests[0].String() != testManifest1WithLabel {
-		t.Fatalf("invalid labeled manifest returned. Expected \n%q, Got \n%q", testManifest1WithLabel, labeledManifests[0].String())
+	labeled, err := yaml.Marshal(labeledManifests[0].Object)
+	if err != nil {
+		t.Fatal(err)
 	}
-}
-
-func TestController_getApplyCommand(t *testing.T) {
-	controller := &Reconciler{}
-	cmd := controller.getApplyCommand(context.Background(), "/opt/kubeconfig", "/opt/manifest.yaml", labels.SelectorFromSet(map[string]string{"foo": "bar"}))
-	expected := "kubectl --kubeconfig /opt/kubeconfig apply --prune -f /opt/manifest.yaml -l foo=bar"
-	got := strings.Join(cmd.Args, " ")
-	if got != expected {
-		t.Fatalf("invalid apply command returned. Expected \n%s, Got \n%s", expected, got)
+	if string(labeled) != testManifest1WithLabel {
+		t.Fatalf("invalid labeled manifest returned. Expected \n%q, Got \n%q", testManifest1WithLabel, string(labeled))
 	}
 }
 
@@ -418,7 +393,7 @@ func TestHugeManifest(t *testing.T) {
 		kubernetesAddonDir: "./testdata",
 		KubeconfigProvider: &fakeKubeconfigProvider{},
 	}
-	if _, _, _, err := r.setupManifestInteraction(context.Background(), log, addon, cluster); err != nil {
-		t.Fatalf("failed to setup manifest interaction: %v", err)
+	if _, err := r.getAddonObjects(context.Background(), log, addon, cluster); err != nil {
+		t.Fatalf("failed to get addon objects: %v", err)
 	}
 }
@@ -0,0 +1,390 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fieldManager is the field manager used when applying addon manifests
+const fieldManager = "kubermatic-addon"
+
+// clientSideApplyFieldManagers are the field managers of kubectl client-side apply, which applied the addon
+// manifests before they were applied server-side
+var clientSideApplyFieldManagers = sets.NewString("kubectl-client-side-apply", "kubectl")
+
+// defaultPruneTypes are the types which are always checked for objects that are no longer part of
+// an addon. This is the same list kubectl uses for `apply --prune`. Types of objects applied by
+// the current or the previous reconciliation are checked in addition to these.
+var defaultPruneTypes = []schema.GroupVersionKind{
+	{Version: "v1", Kind: "ConfigMap"},
+	{Version: "v1", Kind: "Endpoints"},
+	{Version: "v1", Kind: "Namespace"},
+	{Version: "v1", Kind: "PersistentVolumeClaim"},
+	{Version: "v1", Kind: "PersistentVolume"},
+	{Version: "v1", Kind: "Pod"},
+	{Version: "v1", Kind: "ReplicationController"},
+	{Version: "v1", Kind: "Secret"},
+	{Version: "v1", Kind: "Service"},
+	{Group: "batch", Version: "v1", Kind: "Job"},
+	{Group: "batch", Version: "v1beta1", Kind: "CronJob"},
+	{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"},
+	{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+	{Group: "apps", Version: "v1", Kind: "Deployment"},
+	{Group: "apps", Version: "v1", Kind: "ReplicaSet"},
+	{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+}
+
+// applier applies addon manifests to a user cluster using server-side apply and prunes
+// objects of the addon which are no longer part of its manifests.
+type applier struct {
+	client ctrlruntimeclient.Client
+	log    *zap.SugaredLogger
+}
+
+type objectKey struct {
+	schema.GroupKind
+	namespace string
+	name      string
+}
+
+func keyOf(obj *metav1unstructured.Unstructured) objectKey {
+	return objectKey{
+		GroupKind: obj.GroupVersionKind().GroupKind(),
+		namespace: obj.GetNamespace(),
+		name:      obj.GetName(),
+	}
+}
+
+func resourceStatusOf(obj *metav1unstructured.Unstructured, action kubermaticv1.AddonResourceAction, err error) kubermaticv1.AddonResourceStatus {
+	status := kubermaticv1.AddonResourceStatus{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+		Action:     action,
+	}
+	if err != nil {
+		status.Message = err.Error()
+	}
+	return status
+}
+
+// apply applies all objects and afterwards deletes all objects matching the selector which are not part
+// of objects. previous is the result of the last apply and is used to find the types of objects which
+// were removed from the manifests. Pruning is skipped if any object failed to apply.
+func (a *applier) apply(ctx context.Context, objects []*metav1unstructured.Unstructured, selector labels.Selector, previous []kubermaticv1.AddonResourceStatus) ([]kubermaticv1.AddonResourceStatus, error) {
+	// Namespaces and CRDs need to exist before the objects using them can be created
+	objects = append([]*metav1unstructured.Unstructured{}, objects...)
+	sort.SliceStable(objects, func(i, j int) bool {
+		return applyPriority(objects[i]) < applyPriority(objects[j])
+	})
+
+	var (
+		results []kubermaticv1.AddonResourceStatus
+		errs    []error
+	)
+	desired := map[objectKey]struct{}{}
+	for _, obj := range objects {
+		if err := a.defaultNamespace(obj); err != nil {
+			results = append(results, resourceStatusOf(obj, kubermaticv1.AddonResourceFailed, err))
+			errs = append(errs, fmt.Errorf("failed to apply %s %s: %v", obj.GetKind(), obj.GetName(), err))
+			continue
+		}
+		desired[keyOf(obj)] = struct{}{}
+
+		action, err := a.applyObject(ctx, obj)
+		results = append(results, resourceStatusOf(obj, action, err))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to apply %s %s: %v", obj.GetKind(), ctrlruntimeclient.ObjectKeyFromObject(obj), err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return results, utilerrors.NewAggregate(errs)
+	}
+
+	pruned, err := a.prune(ctx, desired, selector, pruneTypes(objects, previous))
+	return append(results, pruned...), err
+}
+
+// applyPriority returns the order in which objects are applied, lower values first
+func applyPriority(obj *metav1unstructured.Unstructured) int {
+	switch obj.GroupVersionKind().GroupKind() {
+	case schema.GroupKind{Kind: "Namespace"}:
+		return 0
+	case schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// defaultNamespace sets the default namespace on namespaced objects without a namespace, like kubectl does
+func (a *applier) defaultNamespace(obj *metav1unstructured.Unstructured) error {
+	if obj.GetNamespace() != "" {
+		return nil
+	}
+
+	gvk := obj.GroupVersionKind()
+	mapping, err := a.client.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return err
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		obj.SetNamespace(metav1.NamespaceDefault)
+	}
+
+	return nil
+}
+
+func (a *applier) applyObject(ctx context.Context, obj *metav1unstructured.Unstructured) (kubermaticv1.AddonResourceAction, error) {
+	existing := &metav1unstructured.Unstructured{}
+	existing.SetGroupVersionKind(obj.GroupVersionKind())
+	err := a.client.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(obj), existing)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return kubermaticv1.AddonResourceFailed, err
+	}
+	exists := err == nil
+
+	if exists {
+		if err := a.upgradeManagedFields(ctx, existing); err != nil {
+			return kubermaticv1.AddonResourceFailed, fmt.Errorf("failed to upgrade the managed fields: %v", err)
+		}
+	}
+
+	// Patch updates the object with the response, keep the manifest untouched
+	applied := obj.DeepCopy()
+	if err := a.client.Patch(ctx, applied, ctrlruntimeclient.Apply, ctrlruntimeclient.FieldOwner(fieldManager), ctrlruntimeclient.ForceOwnership); err != nil {
+		return kubermaticv1.AddonResourceFailed, err
+	}
+
+	switch {
+	case !exists:
+		a.log.Debugw("Created object", "kind", obj.GetKind(), "object", ctrlruntimeclient.ObjectKeyFromObject(obj))
+		return kubermaticv1.AddonResourceCreated, nil
+	case applied.GetResourceVersion() != existing.GetResourceVersion():
+		a.log.Debugw("Updated object", "kind", obj.GetKind(), "object", ctrlruntimeclient.ObjectKeyFromObject(obj))
+		return kubermaticv1.AddonResourceUpdated, nil
+	default:
+		return kubermaticv1.AddonResourceUnchanged, nil
+	}
+}
+
+// upgradeManagedFields transfers the ownership of all fields kubectl client-side apply applied to the field
+// manager of the applier, like `kubectl apply --server-side` does when it upgrades objects. Without this, fields
+// which are removed from the manifests would be kept forever, as server-side apply only removes fields owned
+// by the field manager applying the object. existing is updated with the upgraded object.
+func (a *applier) upgradeManagedFields(ctx context.Context, existing *metav1unstructured.Unstructured) error {
+	managedFields, upgraded, err := upgradedManagedFields(existing.GetManagedFields())
+	if err != nil || !upgraded {
+		return err
+	}
+
+	// the test for the resourceVersion prevents overwriting managed fields which changed in the meantime
+	patch, err := json.Marshal([]map[string]interface{}{
+		{"op": "test", "path": "/metadata/resourceVersion", "value": existing.GetResourceVersion()},
+		{"op": "replace", "path": "/metadata/managedFields", "value": managedFields},
+	})
+	if err != nil {
+		return err
+	}
+	if err := a.client.Patch(ctx, existing, ctrlruntimeclient.RawPatch(types.JSONPatchType, patch)); err != nil {
+		return err
+	}
+
+	a.log.Debugw("Upgraded managed fields of client-side applied object", "kind", existing.GetKind(), "object", ctrlruntimeclient.ObjectKeyFromObject(existing))
+	return nil
+}
+
+// upgradedManagedFields merges the fields of all client-side apply field managers into the apply entry of the
+// applier's field manager. The returned bool is false if there are no client-side apply field managers.
+func upgradedManagedFields(entries []metav1.ManagedFieldsEntry) ([]metav1.ManagedFieldsEntry, bool, error) {
+	var (
+		result  []metav1.ManagedFieldsEntry
+		applied *metav1.ManagedFieldsEntry
+		fields  = map[string]interface{}{}
+	)
+	for _, entry := range entries {
+		entry := entry
+		switch {
+		case entry.Manager == fieldManager && entry.Operation == metav1.ManagedFieldsOperationApply:
+			applied = &entry
+		case clientSideApplyFieldManagers.Has(entry.Manager) && entry.Operation == metav1.ManagedFieldsOperationUpdate:
+			if err := mergeFields(fields, entry.FieldsV1); err != nil {
+				return nil, false, fmt.Errorf("failed to decode the fields of %s: %v", entry.Manager, err)
+			}
+			if applied == nil {
+				// the first client-side apply entry becomes the apply entry, unless there is one already
+				applied = &metav1.ManagedFieldsEntry{
+					Manager:    fieldManager,
+					Operation:  metav1.ManagedFieldsOperationApply,
+					APIVersion: entry.APIVersion,
+					Time:       entry.Time,
+				}
+			}
+		default:
+			result = append(result, entry)
+		}
+	}
+	if len(fields) == 0 {
+		return entries, false, nil
+	}
+
+	if err := mergeFields(fields, applied.FieldsV1); err != nil {
+		return nil, false, fmt.Errorf("failed to decode the fields of %s: %v", fieldManager, err)
+	}
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return nil, false, err
+	}
+	applied.FieldsType = "FieldsV1"
+	applied.FieldsV1 = &metav1.FieldsV1{Raw: raw}
+
+	return append(result, *applied), true, nil
+}
+
+// mergeFields adds the fields of the set to fields, sets of fields are trees of JSON objects
+func mergeFields(fields map[string]interface{}, set *metav1.FieldsV1) error {
+	if set == nil || len(set.Raw) == 0 {
+		return nil
+	}
+
+	decoded := map[string]interface{}{}
+	if err := json.Unmarshal(set.Raw, &decoded); err != nil {
+		return err
+	}
+	mergeFieldTrees(fields, decoded)
+	return nil
+}
+
+func mergeFieldTrees(dst, src map[string]interface{}) {
+	for key, value := range src {
+		srcChild, srcIsTree := value.(map[string]interface{})
+		dstChild, dstIsTree := dst[key].(map[string]interface{})
+		if srcIsTree && dstIsTree {
+			mergeFieldTrees(dstChild, srcChild)
+			continue
+		}
+		if _, ok := dst[key]; !ok {
+			dst[key] = value
+		}
+	}
+}
+
+// pruneTypes returns the types which are checked for objects to prune, deduplicated by group and kind
+func pruneTypes(objects []*metav1unstructured.Unstructured, previous []kubermaticv1.AddonResourceStatus) []schema.GroupVersionKind {
+	types := append([]schema.GroupVersionKind{}, defaultPruneTypes...)
+	for _, obj := range objects {
+		types = append(types, obj.GroupVersionKind())
+	}
+	for _, status := range previous {
+		types = append(types, schema.FromAPIVersionAndKind(status.APIVersion, status.Kind))
+	}
+
+	var result []schema.GroupVersionKind
+	seen := map[schema.GroupKind]struct{}{}
+	for _, gvk := range types {
+		if _, ok := seen[gvk.GroupKind()]; ok {
+			continue
+		}
+		seen[gvk.GroupKind()] = struct{}{}
+		result = append(result, gvk)
+	}
+	return result
+}
+
+// prune deletes all objects of the given types which match the selector and are not desired
+func (a *applier) prune(ctx context.Context, desired map[objectKey]struct{}, selector labels.Selector, types []schema.GroupVersionKind) ([]kubermaticv1.AddonResourceStatus, error) {
+	var (
+		results []kubermaticv1.AddonResourceStatus
+		errs    []error
+	)
+	for _, gvk := range types {
+		list := &metav1unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := a.client.List(ctx, list, ctrlruntimeclient.MatchingLabelsSelector{Selector: selector}); err != nil {
+			if meta.IsNoMatchError(err) {
+				// The type is not served by the cluster, so there is nothing to prune
+				continue
+			}
+			errs = append(errs, fmt.Errorf("failed to list %s: %v", gvk.Kind, err))
+			continue
+		}
+
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if _, ok := desired[keyOf(obj)]; ok || obj.GetDeletionTimestamp() != nil {
+				continue
+			}
+
+			err := a.client.Delete(ctx, obj, ctrlruntimeclient.PropagationPolicy(metav1.DeletePropagationBackground))
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				results = append(results, resourceStatusOf(obj, kubermaticv1.AddonResourceFailed, err))
+				errs = append(errs, fmt.Errorf("failed to prune %s %s: %v", obj.GetKind(), ctrlruntimeclient.ObjectKeyFromObject(obj), err))
+				continue
+			}
+			a.log.Debugw("Pruned object", "kind", obj.GetKind(), "object", ctrlruntimeclient.ObjectKeyFromObject(obj))
+			results = append(results, resourceStatusOf(obj, kubermaticv1.AddonResourcePruned, nil))
+		}
+	}
+
+	return results, utilerrors.NewAggregate(errs)
+}
+
+// delete deletes all objects in reverse order, ignoring objects which do not exist (anymore)
+func (a *applier) delete(ctx context.Context, objects []*metav1unstructured.Unstructured) error {
+	var errs []error
+	for i := len(objects) - 1; i >= 0; i-- {
+		obj := objects[i]
+		if err := a.defaultNamespace(obj); err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("failed to delete %s %s: %v", obj.GetKind(), obj.GetName(), err))
+			continue
+		}
+
+		err := a.client.Delete(ctx, obj, ctrlruntimeclient.PropagationPolicy(metav1.DeletePropagationBackground))
+		if err != nil && !kerrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			errs = append(errs, fmt.Errorf("failed to delete %s %s: %v", obj.GetKind(), ctrlruntimeclient.ObjectKeyFromObject(obj), err))
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,469 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/go-test/deep"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeApplyClient emulates server-side apply on top of the fake client, which does not support it.
+// Like server-side apply, it only removes fields which are owned by the field manager applying the
+// object, but it ignores conflicts and the ownership of the metadata.
+type fakeApplyClient struct {
+	ctrlruntimeclient.Client
+}
+
+func (c *fakeApplyClient) RESTMapper() meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Namespace"), meta.RESTScopeRoot)
+	mapper.Add(appsv1.SchemeGroupVersion.WithKind("Deployment"), meta.RESTScopeNamespace)
+	mapper.Add(rbacv1.SchemeGroupVersion.WithKind("ClusterRole"), meta.RESTScopeRoot)
+	return mapper
+}
+
+func (c *fakeApplyClient) Patch(ctx context.Context, obj ctrlruntimeclient.Object, patch ctrlruntimeclient.Patch, opts ...ctrlruntimeclient.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+
+	applied := obj.(*metav1unstructured.Unstructured)
+	existing := &metav1unstructured.Unstructured{}
+	existing.SetGroupVersionKind(applied.GroupVersionKind())
+	if err := c.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(applied), existing); err != nil {
+		if kerrors.IsNotFound(err) {
+			applied.SetManagedFields([]metav1.ManagedFieldsEntry{appliedFieldsEntry(applied)})
+			return c.write(ctx, applied, true)
+		}
+		return err
+	}
+
+	var (
+		owned         map[string]interface{}
+		managedFields []metav1.ManagedFieldsEntry
+	)
+	for _, entry := range existing.GetManagedFields() {
+		if entry.Manager == fieldManager && entry.Operation == metav1.ManagedFieldsOperationApply {
+			if err := json.Unmarshal(entry.FieldsV1.Raw, &owned); err != nil {
+				return err
+			}
+			continue
+		}
+		managedFields = append(managedFields, entry)
+	}
+
+	merged := existing.DeepCopy()
+	content := fakeApplyFields(withoutMetadata(existing.Object), withoutMetadata(applied.Object), owned).(map[string]interface{})
+	content["metadata"] = merged.Object["metadata"]
+	merged.Object = content
+	merged.SetLabels(applied.GetLabels())
+	merged.SetAnnotations(applied.GetAnnotations())
+	merged.SetManagedFields(append(managedFields, appliedFieldsEntry(applied)))
+
+	if !reflect.DeepEqual(merged.Object, existing.Object) {
+		return c.write(ctx, merged, false)
+	}
+	applied.Object = merged.Object
+	return nil
+}
+
+func withoutMetadata(content map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for k, v := range content {
+		if k != "metadata" {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// fakeApplyFields merges the applied value into the existing one and removes all fields which are owned
+// but not applied
+func fakeApplyFields(existing, applied interface{}, owned map[string]interface{}) interface{} {
+	existingFields, existingIsObject := existing.(map[string]interface{})
+	appliedFields, appliedIsObject := applied.(map[string]interface{})
+	if !existingIsObject || !appliedIsObject {
+		return applied
+	}
+
+	result := map[string]interface{}{}
+	for k, v := range existingFields {
+		if _, ok := owned["f:"+k]; !ok {
+			result[k] = v
+		}
+	}
+	for k, v := range appliedFields {
+		ownedChild, _ := owned["f:"+k].(map[string]interface{})
+		result[k] = fakeApplyFields(existingFields[k], v, ownedChild)
+	}
+	return result
+}
+
+// appliedFieldsEntry returns the managed fields entry of the applier for the applied object
+func appliedFieldsEntry(applied *metav1unstructured.Unstructured) metav1.ManagedFieldsEntry {
+	raw, _ := json.Marshal(fieldsOf(withoutMetadata(applied.Object)))
+	return metav1.ManagedFieldsEntry{
+		Manager:    fieldManager,
+		Operation:  metav1.ManagedFieldsOperationApply,
+		APIVersion: applied.GetAPIVersion(),
+		FieldsType: "FieldsV1",
+		FieldsV1:   &metav1.FieldsV1{Raw: raw},
+	}
+}
+
+// fieldsOf returns the set of fields of the value in the FieldsV1 format
+func fieldsOf(value interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	if object, ok := value.(map[string]interface{}); ok {
+		for k, v := range object {
+			if k == "apiVersion" || k == "kind" {
+				continue
+			}
+			fields["f:"+k] = fieldsOf(v)
+		}
+	}
+	return fields
+}
+
+// write converts obj to its typed counterpart before writing it, as the fake client fails to list
+// typed objects which were written as unstructured. obj is updated with the written object.
+func (c *fakeApplyClient) write(ctx context.Context, obj *metav1unstructured.Unstructured, create bool) error {
+	typed, err := scheme.Scheme.New(obj.GroupVersionKind())
+	if err != nil {
+		return err
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, typed); err != nil {
+		return err
+	}
+	if create {
+		err = c.Create(ctx, typed.(ctrlruntimeclient.Object))
+	} else {
+		err = c.Update(ctx, typed.(ctrlruntimeclient.Object))
+	}
+	if err != nil {
+		return err
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(typed)
+	if err != nil {
+		return err
+	}
+	obj.Object = content
+	obj.SetGroupVersionKind(typed.GetObjectKind().GroupVersionKind())
+	return nil
+}
+
+func addonConfigMap(name string, addonLabel bool, data string) *corev1.ConfigMap {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "kube-system",
+		},
+		Data: map[string]string{"foo": data},
+	}
+	if addonLabel {
+		cm.Labels = map[string]string{addonLabelKey: "test"}
+	}
+	return cm
+}
+
+func toUnstructured(t *testing.T, obj runtime.Object, apiVersion, kind string) *metav1unstructured.Unstructured {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		t.Fatalf("failed to convert object: %v", err)
+	}
+	u := &metav1unstructured.Unstructured{Object: content}
+	u.SetAPIVersion(apiVersion)
+	u.SetKind(kind)
+	// the converter adds an empty creationTimestamp, which is not part of a manifest
+	metav1unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+	return u
+}
+
+// appliedByAddon returns the object with the managed fields of the applier, unless it has managed fields already
+func appliedByAddon(t *testing.T, obj ctrlruntimeclient.Object) ctrlruntimeclient.Object {
+	if len(obj.GetManagedFields()) > 0 {
+		return obj
+	}
+
+	gvk, err := apiutil.GVKForObject(obj, scheme.Scheme)
+	if err != nil {
+		t.Fatalf("failed to get the kind of the object: %v", err)
+	}
+	obj = obj.DeepCopyObject().(ctrlruntimeclient.Object)
+	obj.SetManagedFields([]metav1.ManagedFieldsEntry{appliedFieldsEntry(toUnstructured(t, obj, gvk.GroupVersion().String(), gvk.Kind))})
+	return obj
+}
+
+func TestApplierApply(t *testing.T) {
+	selector := labels.SelectorFromSet(map[string]string{addonLabelKey: "test"})
+
+	testCases := []struct {
+		name            string
+		existing        []ctrlruntimeclient.Object
+		objects         func(t *testing.T) []*metav1unstructured.Unstructured
+		previous        []kubermaticv1.AddonResourceStatus
+		expected        []kubermaticv1.AddonResourceStatus
+		expectedDeleted []ctrlruntimeclient.Object
+		expectedKept    []ctrlruntimeclient.Object
+	}{
+		{
+			name:     "objects are created, updated or left unchanged",
+			existing: []ctrlruntimeclient.Object{addonConfigMap("unchanged", true, "bar"), addonConfigMap("updated", true, "old")},
+			objects: func(t *testing.T) []*metav1unstructured.Unstructured {
+				return []*metav1unstructured.Unstructured{
+					toUnstructured(t, addonConfigMap("created", true, "bar"), "v1", "ConfigMap"),
+					toUnstructured(t, addonConfigMap("unchanged", true, "bar"), "v1", "ConfigMap"),
+					toUnstructured(t, addonConfigMap("updated", true, "new"), "v1", "ConfigMap"),
+				}
+			},
+			expected: []kubermaticv1.AddonResourceStatus{
+				{APIVersion: "v1", Kind: "ConfigMap", Namespace: "kube-system", Name: "created", Action: kubermaticv1.AddonResourceCreated},
+				{APIVersion: "v1", Kind: "ConfigMap", Namespace: "kube-system", Name: "unchanged", Action: kubermaticv1.AddonResourceUnchanged},
+				{APIVersion: "v1", Kind: "ConfigMap", Namespace: "kube-system", Name: "updated", Action: kubermaticv1.AddonResourceUpdated},
+			},
+			expectedKept: []ctrlruntimeclient.Object{addonConfigMap("created", true, "bar")},
+		},
+		{
+			name:     "only labeled objects which are not part of the manifests are pruned",
+			existing: []ctrlruntimeclient.Object{addonConfigMap("kept", true, "bar"), addonConfigMap("stale", true, "bar"), addonConfigMap("unrelated", false, "bar")},
+			objects: func(t *testing.T) []*metav1unstructured.Unstructured {
+				return []*metav1unstructured.Unstructured{
+					toUnstructured(t, addonConfigMap("kept", true, "bar"), "v1", "ConfigMap"),
+				}
+			},
+			expected: []kubermaticv1.AddonResourceStatus{
+				{APIVersion: "v1", Kind: "ConfigMap", Namespace: "kube-system", Name: "kept", Action: kubermaticv1.AddonResourceUnchanged},
+				{APIVersion: "v1", Kind: "ConfigMap", Namespace: "kube-system", Name: "stale", Action: kubermaticv1.AddonResourcePruned},
+			},
+			expectedDeleted: []ctrlruntimeclient.Object{addonConfigMap("stale", true, "bar")},
+			expectedKept:    []ctrlruntimeclient.Object{addonConfigMap("unrelated", false, "bar")},
+		},
+		{
+			name: "types of previously applied objects are pruned",
+			existing: []ctrlruntimeclient.Object{
+				addonConfigMap("kept", true, "bar"),
+				&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "stale", Labels: map[string]string{addonLabelKey: "test"}}},
+			},
+			objects: func(t *testing.T) []*metav1unstructured.Unstructured {
+				return []*metav1unstructured.Unstructured{
+					toUnstructured(t, addonConfigMap("kept", true, "bar"), "v1", "ConfigMap"),
+				}
+			},
+			previous: []kubermaticv1.AddonResourceStatus{
+				{APIVersion: "v1", Kind: "ConfigMap", Namespace: "kube-system", Name: "kept", Action: kubermaticv1.AddonResourceCreated},
+				{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole", Name: "stale", Action: kubermaticv1.AddonResourceCreated},
+			},
+			expected: []kubermaticv1.AddonResourceStatus{
+				{APIVersion: "v1", Kind: "ConfigMap", Namespace: "kube-system", Name: "kept", Action: kubermaticv1.AddonResourceUnchanged},
+				{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole", Name: "stale", Action: kubermaticv1.AddonResourcePruned},
+			},
+			expectedDeleted: []ctrlruntimeclient.Object{&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "stale"}}},
+		},
+		{
+			name: "namespaced objects without a namespace are applied to the default namespace",
+			objects: func(t *testing.T) []*metav1unstructured.Unstructured {
+				deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "test", Labels: map[string]string{addonLabelKey: "test"}}}
+				return []*metav1unstructured.Unstructured{toUnstructured(t, deployment, "apps/v1", "Deployment")}
+			},
+			expected: []kubermaticv1.AddonResourceStatus{
+				{APIVersion: "apps/v1", Kind: "Deployment", Namespace: metav1.NamespaceDefault, Name: "test", Action: kubermaticv1.AddonResourceCreated},
+			},
+			expectedKept: []ctrlruntimeclient.Object{&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: metav1.NamespaceDefault}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			var existing []ctrlruntimeclient.Object
+			for _, obj := range tc.existing {
+				existing = append(existing, appliedByAddon(t, obj))
+			}
+			client := &fakeApplyClient{
+				Client: ctrlruntimefakeclient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(existing...).Build(),
+			}
+			a := &applier{
+				client: client,
+				log:    kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
+			}
+
+			results, err := a.apply(ctx, tc.objects(t), selector, tc.previous)
+			if err != nil {
+				t.Fatalf("failed to apply: %v", err)
+			}
+			if diff := deep.Equal(results, tc.expected); diff != nil {
+				t.Errorf("unexpected results, diff: %v", diff)
+			}
+
+			for _, obj := range tc.expectedDeleted {
+				if err := client.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(obj), obj); !kerrors.IsNotFound(err) {
+					t.Errorf("expected %s to be deleted, got: %v", ctrlruntimeclient.ObjectKeyFromObject(obj), err)
+				}
+			}
+			for _, obj := range tc.expectedKept {
+				if err := client.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(obj), obj); err != nil {
+					t.Errorf("expected %s to exist, got: %v", ctrlruntimeclient.ObjectKeyFromObject(obj), err)
+				}
+			}
+		})
+	}
+}
+
+func TestApplierUpgradesClientSideAppliedObjects(t *testing.T) {
+	ctx := context.Background()
+
+	existing := addonConfigMap("upgraded", true, "bar")
+	existing.Data["removed"] = "old"
+	existing.Data["other"] = "kept"
+	existing.ManagedFields = []metav1.ManagedFieldsEntry{
+		{
+			Manager:    "kubectl-client-side-apply",
+			Operation:  metav1.ManagedFieldsOperationUpdate,
+			APIVersion: "v1",
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:data":{".":{},"f:foo":{},"f:removed":{}},"f:metadata":{"f:labels":{".":{},"f:kubermatic-addon":{}}}}`)},
+		},
+		{
+			Manager:    "kubermatic-operator",
+			Operation:  metav1.ManagedFieldsOperationUpdate,
+			APIVersion: "v1",
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:other":{}}}`)},
+		},
+	}
+
+	client := &fakeApplyClient{
+		Client: ctrlruntimefakeclient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(existing).Build(),
+	}
+	a := &applier{
+		client: client,
+		log:    kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
+	}
+
+	objects := []*metav1unstructured.Unstructured{toUnstructured(t, addonConfigMap("upgraded", true, "bar"), "v1", "ConfigMap")}
+	results, err := a.apply(ctx, objects, labels.SelectorFromSet(map[string]string{addonLabelKey: "test"}), nil)
+	if err != nil {
+		t.Fatalf("failed to apply: %v", err)
+	}
+	expected := []kubermaticv1.AddonResourceStatus{
+		{APIVersion: "v1", Kind: "ConfigMap", Namespace: "kube-system", Name: "upgraded", Action: kubermaticv1.AddonResourceUpdated},
+	}
+	if diff := deep.Equal(results, expected); diff != nil {
+		t.Errorf("unexpected results, diff: %v", diff)
+	}
+
+	upgraded := &corev1.ConfigMap{}
+	if err := client.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(existing), upgraded); err != nil {
+		t.Fatalf("failed to get ConfigMap: %v", err)
+	}
+	// the field removed from the manifest is removed, the field of the other manager is kept
+	if diff := deep.Equal(upgraded.Data, map[string]string{"foo": "bar", "other": "kept"}); diff != nil {
+		t.Errorf("unexpected data, diff: %v", diff)
+	}
+
+	var managers []string
+	for _, entry := range upgraded.ManagedFields {
+		managers = append(managers, fmt.Sprintf("%s/%s", entry.Manager, entry.Operation))
+	}
+	if diff := deep.Equal(managers, []string{"kubermatic-operator/Update", fieldManager + "/Apply"}); diff != nil {
+		t.Errorf("unexpected field managers, diff: %v", diff)
+	}
+}
+
+func TestUpgradedManagedFields(t *testing.T) {
+	entries := []metav1.ManagedFieldsEntry{
+		{
+			Manager:    fieldManager,
+			Operation:  metav1.ManagedFieldsOperationApply,
+			APIVersion: "v1",
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:foo":{}}}`)},
+		},
+		{
+			Manager:    "kubectl",
+			Operation:  metav1.ManagedFieldsOperationUpdate,
+			APIVersion: "v1",
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:data":{".":{},"f:foo":{},"f:bar":{}}}`)},
+		},
+	}
+
+	upgraded, ok, err := upgradedManagedFields(entries)
+	if err != nil {
+		t.Fatalf("failed to upgrade the managed fields: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected the managed fields to be upgraded")
+	}
+	if len(upgraded) != 1 || upgraded[0].Manager != fieldManager {
+		t.Fatalf("expected only the entry of %s, got %v", fieldManager, upgraded)
+	}
+	if fields := string(upgraded[0].FieldsV1.Raw); fields != `{"f:data":{".":{},"f:bar":{},"f:foo":{}}}` {
+		t.Errorf("unexpected fields %s", fields)
+	}
+
+	if _, ok, _ := upgradedManagedFields(upgraded); ok {
+		t.Error("expected the upgraded managed fields to need no upgrade")
+	}
+}
+
+func TestApplierDelete(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeApplyClient{
+		Client: ctrlruntimefakeclient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(addonConfigMap("existing", true, "bar")).Build(),
+	}
+	a := &applier{
+		client: client,
+		log:    kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
+	}
+
+	objects := []*metav1unstructured.Unstructured{
+		toUnstructured(t, addonConfigMap("existing", true, "bar"), "v1", "ConfigMap"),
+		toUnstructured(t, addonConfigMap("missing", true, "bar"), "v1", "ConfigMap"),
+	}
+	if err := a.delete(ctx, objects); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+
+	if err := client.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: "existing"}, &corev1.ConfigMap{}); !kerrors.IsNotFound(err) {
+		t.Errorf("expected ConfigMap to be deleted, got: %v", err)
+	}
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	addonutils "k8c.io/kubermatic/v2/pkg/addon"
+	"k8c.io/kubermatic/v2/pkg/cni"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources/reconciling"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// dependencyRequeueInterval is the interval in which addons waiting for their dependencies are checked again
+	dependencyRequeueInterval = 10 * time.Second
+
+	appliedVersionKey   = "version"
+	appliedManifestsKey = "manifests.json.gz"
+)
+
+// incompatibility checks the addon against its metadata from the addon catalog. If the addon can not be
+// installed into the cluster, the returned message explains why.
+func incompatibility(addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster, metadata *addonutils.Metadata) (string, error) {
+	supported, err := metadata.SupportsKubernetesVersion(cluster.Spec.Version.Semver())
+	if err != nil {
+		return "", err
+	}
+	if !supported {
+		return fmt.Sprintf("The addon does not support Kubernetes %s, supported versions are %s", cluster.Spec.Version.String(), metadata.KubernetesVersions), nil
+	}
+
+	if plugin := cni.ClusterPlugin(&cluster.Spec); !metadata.SupportsCNIPlugin(plugin.Type) {
+		return fmt.Sprintf("The addon installs the CNI plugin %s, but the cluster uses %s", metadata.CNIPlugin, plugin.Type), nil
+	}
+
+	// A pinned version which was installed before is kept, see isHeldBack
+	if addon.Spec.Version != "" && addon.Spec.Version != metadata.Version && addon.Status.Version != addon.Spec.Version {
+		return fmt.Sprintf("Version %s of the addon is not available, the catalog provides version %q", addon.Spec.Version, metadata.Version), nil
+	}
+
+	return "", nil
+}
+
+// isHeldBack returns whether the addon is pinned to the version which is installed, while the catalog
+// provides a different version. Such addons are not updated until the pinned version is changed.
+func isHeldBack(addon *kubermaticv1.Addon, metadata *addonutils.Metadata) bool {
+	return addon.Spec.Version != "" && addon.Spec.Version != metadata.Version && addon.Status.Version == addon.Spec.Version
+}
+
+// ensureDependenciesAreReady checks that all dependencies of the addon exist in the cluster and are ready.
+// If they are not, the addon is checked again after dependencyRequeueInterval.
+func (r *Reconciler) ensureDependenciesAreReady(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster, metadata *addonutils.Metadata) (*reconcile.Result, error) {
+	dependencies := metadata.ClusterDependencies(cni.ClusterPlugin(&cluster.Spec).Type)
+	if len(dependencies) == 0 {
+		return nil, nil
+	}
+
+	addonList := &kubermaticv1.AddonList{}
+	if err := r.List(ctx, addonList, ctrlruntimeclient.InNamespace(addon.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list addons: %v", err)
+	}
+
+	var pending []string
+	for _, dependency := range dependencies {
+		if !dependencyIsReady(addonList.Items, dependency) {
+			pending = append(pending, dependency)
+		}
+	}
+	if len(pending) > 0 {
+		log.Debugw("Dependencies are not ready yet, trying again later", "dependencies", strings.Join(pending, ","))
+		return &reconcile.Result{RequeueAfter: dependencyRequeueInterval}, nil
+	}
+
+	return nil, nil
+}
+
+func dependencyIsReady(addons []kubermaticv1.Addon, name string) bool {
+	for i := range addons {
+		if addons[i].Spec.Name != name || addons[i].DeletionTimestamp != nil {
+			continue
+		}
+		_, cond := getAddonCondition(&addons[i], kubermaticv1.AddonReady)
+		return cond != nil && cond.Status == corev1.ConditionTrue
+	}
+	return false
+}
+
+func (r *Reconciler) ensureVersionStatus(ctx context.Context, addon *kubermaticv1.Addon, version string) error {
+	if addon.Status.Version == version {
+		return nil
+	}
+	oldAddon := addon.DeepCopy()
+	addon.Status.Version = version
+	return r.Client.Patch(ctx, addon, ctrlruntimeclient.MergeFrom(oldAddon))
+}
+
+// appliedManifestsSecretName returns the name of the secret which stores the objects of the addon as they were
+// applied last. The catalog only provides the current version of an addon, so an addon which is held back at
+// the installed version is reconciled from these objects.
+func appliedManifestsSecretName(addon *kubermaticv1.Addon) string {
+	return fmt.Sprintf("addon-%s-applied-manifests", addon.Name)
+}
+
+func (r *Reconciler) storeAppliedObjects(ctx context.Context, addon *kubermaticv1.Addon, version string, objects []*metav1unstructured.Unstructured) error {
+	raw, err := json.Marshal(objects)
+	if err != nil {
+		return fmt.Errorf("failed to encode the applied objects: %v", err)
+	}
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	if _, err := writer.Write(raw); err != nil {
+		return fmt.Errorf("failed to compress the applied objects: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to compress the applied objects: %v", err)
+	}
+
+	creator := func() (string, reconciling.SecretCreator) {
+		return appliedManifestsSecretName(addon), func(s *corev1.Secret) (*corev1.Secret, error) {
+			s.Data = map[string][]byte{
+				appliedVersionKey:   []byte(version),
+				appliedManifestsKey: compressed.Bytes(),
+			}
+			return s, nil
+		}
+	}
+	ownerRef := *metav1.NewControllerRef(addon, kubermaticv1.SchemeGroupVersion.WithKind(kubermaticv1.AddonKindName))
+	if err := reconciling.ReconcileSecrets(ctx, []reconciling.NamedSecretCreatorGetter{creator}, addon.Namespace, r.Client, reconciling.OwnerRefWrapper(ownerRef)); err != nil {
+		return fmt.Errorf("failed to store the applied objects: %v", err)
+	}
+	return nil
+}
+
+// loadAppliedObjects returns the objects of the addon stored by storeAppliedObjects, if they have been stored
+// for the given version.
+func (r *Reconciler) loadAppliedObjects(ctx context.Context, addon *kubermaticv1.Addon, version string) ([]*metav1unstructured.Unstructured, bool, error) {
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: addon.Namespace, Name: appliedManifestsSecretName(addon)}, secret); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to get the applied objects: %v", err)
+	}
+	if string(secret.Data[appliedVersionKey]) != version {
+		return nil, false, nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(secret.Data[appliedManifestsKey]))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to decompress the applied objects: %v", err)
+	}
+	raw, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to decompress the applied objects: %v", err)
+	}
+	var objects []*metav1unstructured.Unstructured
+	if err := json.Unmarshal(raw, &objects); err != nil {
+		return nil, false, fmt.Errorf("failed to decode the applied objects: %v", err)
+	}
+	return objects, true, nil
+}
+
+// ensureInstalledVersion re-applies the objects of the installed version of an addon which is held back, so
+// that changes to them in the user cluster are reverted like for any other addon.
+func (r *Reconciler) ensureInstalledVersion(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) error {
+	objects, found, err := r.loadAppliedObjects(ctx, addon, addon.Status.Version)
+	if err != nil {
+		return err
+	}
+	if !found {
+		log.Debugw("The objects of the installed version have not been stored, skipping reconciliation", "version", addon.Status.Version)
+		return nil
+	}
+	return r.applyObjects(ctx, log, addon, cluster, objects)
+}
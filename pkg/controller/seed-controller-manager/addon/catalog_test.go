@@ -0,0 +1,242 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"testing"
+
+	addonutils "k8c.io/kubermatic/v2/pkg/addon"
+	clusterclient "k8c.io/kubermatic/v2/pkg/cluster/client"
+	"k8c.io/kubermatic/v2/pkg/crd/client/clientset/versioned/scheme"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+	"k8c.io/kubermatic/v2/pkg/semver"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	kubernetesscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestIncompatibility(t *testing.T) {
+	cluster := &kubermaticv1.Cluster{Spec: kubermaticv1.ClusterSpec{Version: *semver.NewSemverOrDie("1.20.2")}}
+	addon := func(specVersion, statusVersion string) *kubermaticv1.Addon {
+		return &kubermaticv1.Addon{
+			Spec:   kubermaticv1.AddonSpec{Name: "test", Version: specVersion},
+			Status: kubermaticv1.AddonStatus{Version: statusVersion},
+		}
+	}
+
+	testCases := []struct {
+		name                 string
+		addon                *kubermaticv1.Addon
+		metadata             *addonutils.Metadata
+		expectedIncompatible bool
+		expectedHeldBack     bool
+	}{
+		{
+			name:     "unpinned addon follows the catalog",
+			addon:    addon("", "1.0.0"),
+			metadata: &addonutils.Metadata{Name: "test", Version: "1.1.0"},
+		},
+		{
+			name:     "addon pinned to the catalog version is installed",
+			addon:    addon("1.1.0", ""),
+			metadata: &addonutils.Metadata{Name: "test", Version: "1.1.0"},
+		},
+		{
+			name:             "addon pinned to the installed version is held back",
+			addon:            addon("1.0.0", "1.0.0"),
+			metadata:         &addonutils.Metadata{Name: "test", Version: "1.1.0"},
+			expectedHeldBack: true,
+		},
+		{
+			name:                 "addon pinned to an unavailable version is not installed",
+			addon:                addon("0.9.0", "1.0.0"),
+			metadata:             &addonutils.Metadata{Name: "test", Version: "1.1.0"},
+			expectedIncompatible: true,
+		},
+		{
+			name:                 "addon not supporting the cluster version is not installed",
+			addon:                addon("", ""),
+			metadata:             &addonutils.Metadata{Name: "test", KubernetesVersions: "< 1.20"},
+			expectedIncompatible: true,
+		},
+		{
+			name:                 "addon installing another CNI plugin is not installed",
+			addon:                addon("", ""),
+			metadata:             &addonutils.Metadata{Name: "cilium", CNIPlugin: kubermaticv1.CNIPluginTypeCilium},
+			expectedIncompatible: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			message, err := incompatibility(tc.addon, cluster, tc.metadata)
+			if err != nil {
+				t.Fatalf("failed to check compatibility: %v", err)
+			}
+			if incompat := message != ""; incompat != tc.expectedIncompatible {
+				t.Errorf("expected incompatibility to be %v, got message %q", tc.expectedIncompatible, message)
+			}
+			if heldBack := isHeldBack(tc.addon, tc.metadata); heldBack != tc.expectedHeldBack {
+				t.Errorf("expected held back to be %v, got %v", tc.expectedHeldBack, heldBack)
+			}
+		})
+	}
+}
+
+func TestEnsureDependenciesAreReady(t *testing.T) {
+	dependency := func(name string, ready corev1.ConditionStatus) *kubermaticv1.Addon {
+		a := &kubermaticv1.Addon{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "cluster-test"},
+			Spec:       kubermaticv1.AddonSpec{Name: name},
+		}
+		setAddonCodition(a, kubermaticv1.AddonReady, ready, "")
+		return a
+	}
+	addon := &kubermaticv1.Addon{
+		ObjectMeta: metav1.ObjectMeta{Name: "multus", Namespace: "cluster-test"},
+		Spec:       kubermaticv1.AddonSpec{Name: "multus"},
+	}
+	metadata := &addonutils.Metadata{Name: "multus", Dependencies: []string{"rbac"}, RequiresCNIPlugin: true}
+	cilium := &kubermaticv1.CNIPluginSettings{Type: kubermaticv1.CNIPluginTypeCilium, Version: "v1.10"}
+	none := &kubermaticv1.CNIPluginSettings{Type: kubermaticv1.CNIPluginTypeNone}
+
+	testCases := []struct {
+		name          string
+		cniPlugin     *kubermaticv1.CNIPluginSettings
+		existing      []ctrlruntimeclient.Object
+		expectRequeue bool
+	}{
+		{
+			name:          "missing dependency",
+			expectRequeue: true,
+		},
+		{
+			name:          "dependency not ready",
+			existing:      []ctrlruntimeclient.Object{dependency("rbac", corev1.ConditionTrue), dependency("canal", corev1.ConditionFalse)},
+			expectRequeue: true,
+		},
+		{
+			name:     "dependency ready",
+			existing: []ctrlruntimeclient.Object{dependency("rbac", corev1.ConditionTrue), dependency("canal", corev1.ConditionTrue)},
+		},
+		{
+			name:          "CNI plugin of the cluster not ready",
+			cniPlugin:     cilium,
+			existing:      []ctrlruntimeclient.Object{dependency("rbac", corev1.ConditionTrue), dependency("canal", corev1.ConditionTrue)},
+			expectRequeue: true,
+		},
+		{
+			name:      "CNI plugin of the cluster ready",
+			cniPlugin: cilium,
+			existing:  []ctrlruntimeclient.Object{dependency("rbac", corev1.ConditionTrue), dependency("cilium", corev1.ConditionTrue)},
+		},
+		{
+			name:      "cluster bringing its own CNI plugin",
+			cniPlugin: none,
+			existing:  []ctrlruntimeclient.Object{dependency("rbac", corev1.ConditionTrue)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &Reconciler{
+				Client: ctrlruntimefakeclient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(tc.existing...).Build(),
+			}
+			log := kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar()
+
+			cluster := &kubermaticv1.Cluster{Spec: kubermaticv1.ClusterSpec{CNIPlugin: tc.cniPlugin}}
+
+			result, err := r.ensureDependenciesAreReady(context.Background(), log, addon, cluster, metadata)
+			if err != nil {
+				t.Fatalf("failed to check dependencies: %v", err)
+			}
+			if requeue := result != nil; requeue != tc.expectRequeue {
+				t.Errorf("expected requeue to be %v, got %v", tc.expectRequeue, requeue)
+			}
+		})
+	}
+}
+
+type userClusterKubeconfigProvider struct {
+	fakeKubeconfigProvider
+	client ctrlruntimeclient.Client
+}
+
+func (p *userClusterKubeconfigProvider) GetClient(_ context.Context, _ *kubermaticv1.Cluster, _ ...clusterclient.ConfigOption) (ctrlruntimeclient.Client, error) {
+	return p.client, nil
+}
+
+func TestEnsureInstalledVersion(t *testing.T) {
+	ctx := context.Background()
+	log := kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar()
+
+	addon := &kubermaticv1.Addon{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "cluster-test"},
+		Spec:       kubermaticv1.AddonSpec{Name: "test", Version: "1.0.0"},
+		Status:     kubermaticv1.AddonStatus{Version: "1.0.0"},
+	}
+	userClient := &fakeApplyClient{
+		Client: ctrlruntimefakeclient.NewClientBuilder().WithScheme(kubernetesscheme.Scheme).WithObjects(addonConfigMap("drifted", true, "changed")).Build(),
+	}
+	r := &Reconciler{
+		Client:             ctrlruntimefakeclient.NewClientBuilder().WithScheme(kubernetesscheme.Scheme).WithObjects(addon).Build(),
+		KubeconfigProvider: &userClusterKubeconfigProvider{client: userClient},
+	}
+	cluster := &kubermaticv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+
+	// nothing is applied before the objects of the installed version have been stored
+	if err := r.ensureInstalledVersion(ctx, log, addon, cluster); err != nil {
+		t.Fatalf("failed to reconcile the installed version: %v", err)
+	}
+	assertConfigMapData(t, userClient, "drifted", "changed")
+
+	installed := []*metav1unstructured.Unstructured{toUnstructured(t, addonConfigMap("drifted", true, "installed"), "v1", "ConfigMap")}
+	if err := r.storeAppliedObjects(ctx, addon, "1.0.0", installed); err != nil {
+		t.Fatalf("failed to store the applied objects: %v", err)
+	}
+
+	// objects stored for another version are not applied
+	heldBack := addon.DeepCopy()
+	heldBack.Status.Version = "0.9.0"
+	if err := r.ensureInstalledVersion(ctx, log, heldBack, cluster); err != nil {
+		t.Fatalf("failed to reconcile the installed version: %v", err)
+	}
+	assertConfigMapData(t, userClient, "drifted", "changed")
+
+	if err := r.ensureInstalledVersion(ctx, log, addon, cluster); err != nil {
+		t.Fatalf("failed to reconcile the installed version: %v", err)
+	}
+	assertConfigMapData(t, userClient, "drifted", "installed")
+}
+
+func assertConfigMapData(t *testing.T, client ctrlruntimeclient.Client, name, expected string) {
+	t.Helper()
+	cm := &corev1.ConfigMap{}
+	if err := client.Get(context.Background(), types.NamespacedName{Namespace: "kube-system", Name: name}, cm); err != nil {
+		t.Fatalf("failed to get config map %s: %v", name, err)
+	}
+	if cm.Data["foo"] != expected {
+		t.Errorf("expected config map %s to contain %q, got %q", name, expected, cm.Data["foo"])
+	}
+}
@@ -17,7 +17,12 @@ limitations under the License.
 /*
 Package addon contains a controller that applies addons based on a Addon CRD. It needs
 a folder per addon that contains all manifests, then adds a label to all objects and applies
-the addon via `kubectl apply --purge -l $added-label`, which result in all objects that
-do have the label but are not in the on-disk manifests being removed.
+them via server-side apply. Afterwards all objects that do have the label but are not in the
+on-disk manifests are removed, similar to `kubectl apply --prune -l $added-label`. The fields of
+objects which were applied by kubectl client-side apply before are taken over by the controller.
+
+Addons are only applied once all dependencies declared in their addon.yaml are ready and if they
+support the Kubernetes version of the cluster. Addons pinned to a version are not updated while
+the addon catalog provides a different version.
 */
 package addon
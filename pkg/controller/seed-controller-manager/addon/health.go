@@ -0,0 +1,273 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1/helper"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// notReadyRequeueInterval is the interval in which the readiness of addons which are not ready is checked again
+const notReadyRequeueInterval = 30 * time.Second
+
+// readinessFunc checks whether the given object is ready. If it is not, the returned message explains why.
+type readinessFunc func(obj *metav1unstructured.Unstructured) (bool, string, error)
+
+// readinessChecks contains the readiness checks for all kinds of objects which are considered workloads of an addon
+var readinessChecks = map[schema.GroupKind]readinessFunc{
+	{Group: appsv1.GroupName, Kind: "Deployment"}:                        deploymentReadiness,
+	{Group: appsv1.GroupName, Kind: "DaemonSet"}:                         daemonSetReadiness,
+	{Group: appsv1.GroupName, Kind: "StatefulSet"}:                       statefulSetReadiness,
+	{Group: apiextensionsv1.GroupName, Kind: "CustomResourceDefinition"}: crdReadiness,
+}
+
+// ensureReadyConditionIsSet evaluates the readiness of the workloads applied for the addon, records it in the
+// AddonReady condition and updates the aggregated addons health of the cluster. Addons which are not ready are
+// checked again after notReadyRequeueInterval.
+func (r *Reconciler) ensureReadyConditionIsSet(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	ready, message, err := r.addonReadiness(ctx, addon, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check addon readiness: %v", err)
+	}
+
+	if err := r.setReadyCondition(ctx, log, addon, cluster, ready, message); err != nil {
+		return nil, err
+	}
+
+	if !ready {
+		return &reconcile.Result{RequeueAfter: notReadyRequeueInterval}, nil
+	}
+	return nil, nil
+}
+
+// setReadyCondition records the readiness in the AddonReady condition and updates the aggregated addons health of the cluster.
+func (r *Reconciler) setReadyCondition(ctx context.Context, log *zap.SugaredLogger, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster, ready bool, message string) error {
+	status := corev1.ConditionTrue
+	if !ready {
+		status = corev1.ConditionFalse
+	}
+	if _, cond := getAddonCondition(addon, kubermaticv1.AddonReady); cond == nil || cond.Status != status || cond.Message != message {
+		log.Debugw("Addon readiness changed", "ready", ready, "message", message)
+		oldAddon := addon.DeepCopy()
+		setAddonCodition(addon, kubermaticv1.AddonReady, status, message)
+		if err := r.Client.Patch(ctx, addon, ctrlruntimeclient.MergeFrom(oldAddon)); err != nil {
+			return fmt.Errorf("failed to set the AddonReady condition: %v", err)
+		}
+	}
+
+	if err := r.ensureClusterAddonsHealth(ctx, addon, cluster); err != nil {
+		return fmt.Errorf("failed to update the addons health of the cluster: %v", err)
+	}
+	return nil
+}
+
+// addonReadiness checks all workloads which were applied during the last reconciliation of the addon.
+func (r *Reconciler) addonReadiness(ctx context.Context, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) (bool, string, error) {
+	var workloads []kubermaticv1.AddonResourceStatus
+	for _, resource := range addon.Status.Resources {
+		gk := schema.FromAPIVersionAndKind(resource.APIVersion, resource.Kind).GroupKind()
+		if _, ok := readinessChecks[gk]; ok && resource.Action != kubermaticv1.AddonResourcePruned && resource.Action != kubermaticv1.AddonResourceFailed {
+			workloads = append(workloads, resource)
+		}
+	}
+	if len(workloads) == 0 {
+		return true, "The addon has no workloads", nil
+	}
+
+	userClusterClient, err := r.KubeconfigProvider.GetClient(ctx, cluster)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to get client for usercluster: %v", err)
+	}
+
+	return workloadsReadiness(ctx, userClusterClient, workloads)
+}
+
+func workloadsReadiness(ctx context.Context, client ctrlruntimeclient.Client, workloads []kubermaticv1.AddonResourceStatus) (bool, string, error) {
+	var notReady []string
+	for _, workload := range workloads {
+		gvk := schema.FromAPIVersionAndKind(workload.APIVersion, workload.Kind)
+		name := workload.Name
+		if workload.Namespace != "" {
+			name = workload.Namespace + "/" + workload.Name
+		}
+
+		obj := &metav1unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		if err := client.Get(ctx, types.NamespacedName{Namespace: workload.Namespace, Name: workload.Name}, obj); err != nil {
+			if kerrors.IsNotFound(err) {
+				notReady = append(notReady, fmt.Sprintf("%s %s: not found", workload.Kind, name))
+				continue
+			}
+			return false, "", fmt.Errorf("failed to get %s %s: %v", workload.Kind, name, err)
+		}
+
+		ready, message, err := readinessChecks[gvk.GroupKind()](obj)
+		if err != nil {
+			return false, "", fmt.Errorf("failed to check readiness of %s %s: %v", workload.Kind, name, err)
+		}
+		if !ready {
+			notReady = append(notReady, fmt.Sprintf("%s %s: %s", workload.Kind, name, message))
+		}
+	}
+
+	if len(notReady) > 0 {
+		return false, strings.Join(notReady, "; "), nil
+	}
+	return true, "All workloads are ready", nil
+}
+
+func deploymentReadiness(obj *metav1unstructured.Unstructured) (bool, string, error) {
+	deployment := &appsv1.Deployment{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, deployment); err != nil {
+		return false, "", err
+	}
+
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+
+	switch {
+	case deployment.Status.ObservedGeneration < deployment.Generation:
+		return false, "rollout has not been observed yet", nil
+	case deployment.Status.UpdatedReplicas < replicas:
+		return false, fmt.Sprintf("%d of %d replicas updated", deployment.Status.UpdatedReplicas, replicas), nil
+	case deployment.Status.AvailableReplicas < replicas:
+		return false, fmt.Sprintf("%d of %d replicas available", deployment.Status.AvailableReplicas, replicas), nil
+	default:
+		return true, "", nil
+	}
+}
+
+func daemonSetReadiness(obj *metav1unstructured.Unstructured) (bool, string, error) {
+	daemonSet := &appsv1.DaemonSet{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, daemonSet); err != nil {
+		return false, "", err
+	}
+
+	desired := daemonSet.Status.DesiredNumberScheduled
+	switch {
+	case daemonSet.Status.ObservedGeneration < daemonSet.Generation:
+		return false, "rollout has not been observed yet", nil
+	case daemonSet.Status.UpdatedNumberScheduled < desired:
+		return false, fmt.Sprintf("%d of %d pods updated", daemonSet.Status.UpdatedNumberScheduled, desired), nil
+	case daemonSet.Status.NumberAvailable < desired:
+		return false, fmt.Sprintf("%d of %d pods available", daemonSet.Status.NumberAvailable, desired), nil
+	default:
+		return true, "", nil
+	}
+}
+
+func statefulSetReadiness(obj *metav1unstructured.Unstructured) (bool, string, error) {
+	statefulSet := &appsv1.StatefulSet{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, statefulSet); err != nil {
+		return false, "", err
+	}
+
+	replicas := int32(1)
+	if statefulSet.Spec.Replicas != nil {
+		replicas = *statefulSet.Spec.Replicas
+	}
+	// with the OnDelete strategy pods are only updated once they are deleted manually
+	rollingUpdate := statefulSet.Spec.UpdateStrategy.Type != appsv1.OnDeleteStatefulSetStrategyType
+
+	switch {
+	case statefulSet.Status.ObservedGeneration < statefulSet.Generation:
+		return false, "rollout has not been observed yet", nil
+	case rollingUpdate && statefulSet.Status.UpdatedReplicas < replicas:
+		return false, fmt.Sprintf("%d of %d replicas updated", statefulSet.Status.UpdatedReplicas, replicas), nil
+	case statefulSet.Status.ReadyReplicas < replicas:
+		return false, fmt.Sprintf("%d of %d replicas ready", statefulSet.Status.ReadyReplicas, replicas), nil
+	default:
+		return true, "", nil
+	}
+}
+
+func crdReadiness(obj *metav1unstructured.Unstructured) (bool, string, error) {
+	// the conditions are the same in v1 and v1beta1
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, crd); err != nil {
+		return false, "", err
+	}
+
+	for _, cond := range crd.Status.Conditions {
+		if cond.Type == apiextensionsv1.Established && cond.Status == apiextensionsv1.ConditionTrue {
+			return true, "", nil
+		}
+	}
+	return false, "not established", nil
+}
+
+// ensureClusterAddonsHealth updates the aggregated addons health of the cluster. addon is used instead of the
+// cached version, as it may not contain the latest condition yet.
+func (r *Reconciler) ensureClusterAddonsHealth(ctx context.Context, addon *kubermaticv1.Addon, cluster *kubermaticv1.Cluster) error {
+	addonList := &kubermaticv1.AddonList{}
+	if err := r.List(ctx, addonList, ctrlruntimeclient.InNamespace(cluster.Status.NamespaceName)); err != nil {
+		return fmt.Errorf("failed to list addons: %v", err)
+	}
+	for i := range addonList.Items {
+		if addonList.Items[i].Name == addon.Name {
+			addonList.Items[i] = *addon
+		}
+	}
+
+	health := kubermaticv1helper.GetHealthStatus(addonsHealth(addonList.Items), cluster, r.versions)
+	if cluster.Status.ExtendedHealth.Addons == health {
+		return nil
+	}
+
+	oldCluster := cluster.DeepCopy()
+	cluster.Status.ExtendedHealth.Addons = health
+	return r.Client.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster))
+}
+
+// addonsHealth aggregates the AddonReady conditions of the given addons. Addons which are being deleted are ignored.
+func addonsHealth(addons []kubermaticv1.Addon) kubermaticv1.HealthStatus {
+	health := kubermaticv1.HealthStatusUp
+	for i := range addons {
+		if addons[i].DeletionTimestamp != nil {
+			continue
+		}
+
+		_, cond := getAddonCondition(&addons[i], kubermaticv1.AddonReady)
+		switch {
+		case cond == nil:
+			health = kubermaticv1.HealthStatusProvisioning
+		case cond.Status != corev1.ConditionTrue:
+			return kubermaticv1.HealthStatusDown
+		}
+	}
+	return health
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"testing"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestWorkloadsReadiness(t *testing.T) {
+	deployment := func(replicas, available int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "deployment", Namespace: "kube-system", Generation: 2},
+			Spec:       appsv1.DeploymentSpec{Replicas: pointer.Int32Ptr(replicas)},
+			Status: appsv1.DeploymentStatus{
+				ObservedGeneration: 2,
+				UpdatedReplicas:    replicas,
+				AvailableReplicas:  available,
+			},
+		}
+	}
+	daemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "daemonset", Namespace: "kube-system", Generation: 3},
+		Status: appsv1.DaemonSetStatus{
+			ObservedGeneration:     2,
+			DesiredNumberScheduled: 3,
+			UpdatedNumberScheduled: 3,
+			NumberAvailable:        3,
+		},
+	}
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "statefulset", Namespace: "kube-system"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: pointer.Int32Ptr(3)},
+		Status: appsv1.StatefulSetStatus{
+			UpdatedReplicas: 3,
+			ReadyReplicas:   2,
+		},
+	}
+
+	workload := func(apiVersion, kind, name string) kubermaticv1.AddonResourceStatus {
+		return kubermaticv1.AddonResourceStatus{APIVersion: apiVersion, Kind: kind, Namespace: "kube-system", Name: name, Action: kubermaticv1.AddonResourceCreated}
+	}
+
+	testCases := []struct {
+		name            string
+		existing        []ctrlruntimeclient.Object
+		workloads       []kubermaticv1.AddonResourceStatus
+		expectedReady   bool
+		expectedMessage string
+	}{
+		{
+			name:            "available deployment is ready",
+			existing:        []ctrlruntimeclient.Object{deployment(2, 2)},
+			workloads:       []kubermaticv1.AddonResourceStatus{workload("apps/v1", "Deployment", "deployment")},
+			expectedReady:   true,
+			expectedMessage: "All workloads are ready",
+		},
+		{
+			name:            "unavailable deployment is not ready",
+			existing:        []ctrlruntimeclient.Object{deployment(2, 1)},
+			workloads:       []kubermaticv1.AddonResourceStatus{workload("apps/v1", "Deployment", "deployment")},
+			expectedMessage: "Deployment kube-system/deployment: 1 of 2 replicas available",
+		},
+		{
+			name:     "all workloads which are not ready are reported",
+			existing: []ctrlruntimeclient.Object{deployment(1, 1), daemonSet, statefulSet},
+			workloads: []kubermaticv1.AddonResourceStatus{
+				workload("apps/v1", "Deployment", "deployment"),
+				workload("apps/v1", "DaemonSet", "daemonset"),
+				workload("apps/v1", "StatefulSet", "statefulset"),
+				workload("apps/v1", "Deployment", "missing"),
+			},
+			expectedMessage: "DaemonSet kube-system/daemonset: rollout has not been observed yet; " +
+				"StatefulSet kube-system/statefulset: 2 of 3 replicas ready; " +
+				"Deployment kube-system/missing: not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := ctrlruntimefakeclient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(tc.existing...).Build()
+
+			ready, message, err := workloadsReadiness(context.Background(), client, tc.workloads)
+			if err != nil {
+				t.Fatalf("failed to check readiness: %v", err)
+			}
+			if ready != tc.expectedReady {
+				t.Errorf("expected ready to be %v, got %v", tc.expectedReady, ready)
+			}
+			if message != tc.expectedMessage {
+				t.Errorf("expected message %q, got %q", tc.expectedMessage, message)
+			}
+		})
+	}
+}
+
+func TestCRDReadiness(t *testing.T) {
+	crd := toUnstructured(t, &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "tests.example.com"}}, "apiextensions.k8s.io/v1", "CustomResourceDefinition")
+
+	if ready, _, err := crdReadiness(crd); err != nil || ready {
+		t.Errorf("expected CRD without conditions not to be ready, got ready=%v, err=%v", ready, err)
+	}
+
+	crd.Object["status"] = map[string]interface{}{
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "Established", "status": "True"},
+		},
+	}
+	if ready, _, err := crdReadiness(crd); err != nil || !ready {
+		t.Errorf("expected established CRD to be ready, got ready=%v, err=%v", ready, err)
+	}
+}
+
+func TestAddonsHealth(t *testing.T) {
+	addon := func(ready *corev1.ConditionStatus, deleted bool) kubermaticv1.Addon {
+		a := kubermaticv1.Addon{}
+		if ready != nil {
+			setAddonCodition(&a, kubermaticv1.AddonReady, *ready, "")
+		}
+		if deleted {
+			now := metav1.Now()
+			a.DeletionTimestamp = &now
+		}
+		return a
+	}
+	ready := corev1.ConditionTrue
+	notReady := corev1.ConditionFalse
+
+	testCases := []struct {
+		name     string
+		addons   []kubermaticv1.Addon
+		expected kubermaticv1.HealthStatus
+	}{
+		{
+			name:     "no addons",
+			expected: kubermaticv1.HealthStatusUp,
+		},
+		{
+			name:     "all addons ready",
+			addons:   []kubermaticv1.Addon{addon(&ready, false), addon(&ready, false)},
+			expected: kubermaticv1.HealthStatusUp,
+		},
+		{
+			name:     "addon without readiness yet",
+			addons:   []kubermaticv1.Addon{addon(&ready, false), addon(nil, false)},
+			expected: kubermaticv1.HealthStatusProvisioning,
+		},
+		{
+			name:     "addon not ready",
+			addons:   []kubermaticv1.Addon{addon(nil, false), addon(&notReady, false)},
+			expected: kubermaticv1.HealthStatusDown,
+		},
+		{
+			name:     "addons being deleted are ignored",
+			addons:   []kubermaticv1.Addon{addon(&ready, false), addon(&notReady, true)},
+			expected: kubermaticv1.HealthStatusUp,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if health := addonsHealth(tc.addons); health != tc.expected {
+				t.Errorf("expected health %v, got %v", tc.expected, health)
+			}
+		})
+	}
+}
@@ -25,6 +25,8 @@ import (
 
 	"go.uber.org/zap"
 
+	addonutils "k8c.io/kubermatic/v2/pkg/addon"
+	"k8c.io/kubermatic/v2/pkg/cni"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1/helper"
 	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
@@ -54,6 +56,7 @@ type Reconciler struct {
 
 	log              *zap.SugaredLogger
 	kubernetesAddons kubermaticv1.AddonList
+	catalog          addonutils.Catalog
 	workerName       string
 	recorder         record.EventRecorder
 	versions         kubermatic.Versions
@@ -65,15 +68,26 @@ func Add(
 	numWorkers int,
 	workerName string,
 	kubernetesAddons kubermaticv1.AddonList,
+	catalog addonutils.Catalog,
 	versions kubermatic.Versions,
 ) error {
 	log = log.Named(ControllerName)
 
+	// Fail early if the default addons can not be installed in any order
+	var names []string
+	for _, addon := range kubernetesAddons.Items {
+		names = append(names, addon.Name)
+	}
+	if _, err := catalog.Resolve(names); err != nil {
+		return fmt.Errorf("invalid default addons: %v", err)
+	}
+
 	reconciler := &Reconciler{
 		Client:           mgr.GetClient(),
 		log:              log,
 		workerName:       workerName,
 		kubernetesAddons: kubernetesAddons,
+		catalog:          catalog,
 		recorder:         mgr.GetEventRecorderFor(ControllerName),
 		versions:         versions,
 	}
@@ -163,7 +177,52 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, clus
 		return &reconcile.Result{RequeueAfter: 1 * time.Second}, nil
 	}
 
-	return nil, r.ensureAddons(ctx, log, cluster, *r.kubernetesAddons.DeepCopy())
+	addons, err := r.defaultAddons(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, r.ensureAddons(ctx, log, cluster, addons)
+}
+
+// defaultAddons returns the default addons which support the Kubernetes version and the CNI plugin of the
+// cluster, ordered so that every addon is created after its dependencies. Default addons which became
+// incompatible, e.g. after an upgrade of the cluster, are therefore deleted.
+func (r *Reconciler) defaultAddons(cluster *kubermaticv1.Cluster) (kubermaticv1.AddonList, error) {
+	defaultAddons := map[string]kubermaticv1.Addon{}
+	var names []string
+	for _, addon := range r.kubernetesAddons.Items {
+		defaultAddons[addon.Name] = *addon.DeepCopy()
+		names = append(names, addon.Name)
+	}
+
+	order, err := r.catalog.Resolve(names)
+	if err != nil {
+		return kubermaticv1.AddonList{}, fmt.Errorf("failed to resolve addon dependencies: %v", err)
+	}
+
+	cniPlugin := cni.ClusterPlugin(&cluster.Spec)
+
+	addons := kubermaticv1.AddonList{}
+	for _, name := range order {
+		addon, ok := defaultAddons[name]
+		if !ok {
+			// dependencies which are no default addons have to be installed by the user
+			continue
+		}
+		if !r.catalog[name].SupportsCNIPlugin(cniPlugin.Type) {
+			continue
+		}
+		supported, err := r.catalog[name].SupportsKubernetesVersion(cluster.Spec.Version.Semver())
+		if err != nil {
+			return kubermaticv1.AddonList{}, err
+		}
+		if supported {
+			addons.Items = append(addons.Items, addon)
+		}
+	}
+
+	return addons, nil
 }
 
 func (r *Reconciler) ensureAddons(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster, addons kubermaticv1.AddonList) error {
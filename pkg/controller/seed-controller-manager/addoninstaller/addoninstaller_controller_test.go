@@ -22,9 +22,11 @@ import (
 
 	"github.com/go-test/deep"
 
+	addonutils "k8c.io/kubermatic/v2/pkg/addon"
 	"k8c.io/kubermatic/v2/pkg/crd/client/clientset/versioned/scheme"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+	"k8c.io/kubermatic/v2/pkg/semver"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -42,6 +44,11 @@ var addons = kubermaticv1.AddonList{Items: []kubermaticv1.Addon{
 	}},
 }}
 
+var catalog = addonutils.Catalog{
+	"Foo": {Name: "Foo"},
+	"Bar": {Name: "Bar"},
+}
+
 func truePtr() *bool {
 	b := true
 	return &b
@@ -146,6 +153,7 @@ func TestCreateAddon(t *testing.T) {
 				log:              kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
 				Client:           client,
 				kubernetesAddons: addons,
+				catalog:          catalog,
 			}
 
 			if _, err := reconciler.reconcile(context.Background(), reconciler.log, test.cluster); err != nil {
@@ -409,6 +417,7 @@ func TestUpdateAddon(t *testing.T) {
 				log:              kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
 				Client:           client,
 				kubernetesAddons: addons,
+				catalog:          catalog,
 			}
 
 			if _, err := reconciler.reconcile(context.Background(), reconciler.log, test.cluster); err != nil {
@@ -430,3 +439,73 @@ func TestUpdateAddon(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultAddons(t *testing.T) {
+	catalog := addonutils.Catalog{
+		"cni":      {Name: "cni"},
+		"multus":   {Name: "multus", Dependencies: []string{"cni"}},
+		"legacy":   {Name: "legacy", KubernetesVersions: "< 1.20"},
+		"optional": {Name: "optional"},
+		"canal":    {Name: "canal", CNIPlugin: kubermaticv1.CNIPluginTypeCanal},
+		"cilium":   {Name: "cilium", CNIPlugin: kubermaticv1.CNIPluginTypeCilium},
+	}
+	defaultAddons := kubermaticv1.AddonList{Items: []kubermaticv1.Addon{
+		{ObjectMeta: metav1.ObjectMeta{Name: "canal"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cilium"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "multus"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "legacy"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cni"}},
+	}}
+
+	tests := []struct {
+		name      string
+		version   string
+		cniPlugin *kubermaticv1.CNIPluginSettings
+		expected  []string
+	}{
+		{
+			name:     "dependencies are created first",
+			version:  "1.19.3",
+			expected: []string{"canal", "cni", "multus", "legacy"},
+		},
+		{
+			name:     "incompatible addons are skipped",
+			version:  "1.20.1",
+			expected: []string{"canal", "cni", "multus"},
+		},
+		{
+			name:      "only the CNI plugin of the cluster is installed",
+			version:   "1.20.1",
+			cniPlugin: &kubermaticv1.CNIPluginSettings{Type: kubermaticv1.CNIPluginTypeCilium, Version: "v1.10"},
+			expected:  []string{"cilium", "cni", "multus"},
+		},
+		{
+			name:      "no CNI plugin is installed for clusters bringing their own",
+			version:   "1.20.1",
+			cniPlugin: &kubermaticv1.CNIPluginSettings{Type: kubermaticv1.CNIPluginTypeNone},
+			expected:  []string{"cni", "multus"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reconciler := Reconciler{
+				kubernetesAddons: defaultAddons,
+				catalog:          catalog,
+			}
+			cluster := &kubermaticv1.Cluster{Spec: kubermaticv1.ClusterSpec{Version: *semver.NewSemverOrDie(test.version), CNIPlugin: test.cniPlugin}}
+
+			result, err := reconciler.defaultAddons(cluster)
+			if err != nil {
+				t.Fatalf("failed to get default addons: %v", err)
+			}
+			var names []string
+			for _, addon := range result.Items {
+				names = append(names, addon.Name)
+			}
+			if diff := deep.Equal(names, test.expected); diff != nil {
+				t.Errorf("unexpected addons, diff: %v", diff)
+			}
+		})
+	}
+}
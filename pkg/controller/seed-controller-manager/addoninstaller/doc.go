@@ -17,6 +17,7 @@ limitations under the License.
 /*
 Package addoninstaller contains a controller that is responsible for making sure a set of addons
 that are configured via a flag on the controller-manager and are required for basic cluster functionality
-exist for all clusters.
+exist for all clusters. Addons are created in dependency order and only if they support the Kubernetes
+version of the cluster.
 */
 package addoninstaller
@@ -0,0 +1,161 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdbackup
+
+import (
+	"errors"
+	"strings"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// backupDestinationTypeEnvVarKey defines the environment variable key for the type of a custom backup destination
+	backupDestinationTypeEnvVarKey = "BACKUP_DESTINATION_TYPE"
+	// azureStorageAccountEnvVarKey defines the environment variable key for the account of an Azure Blob backup destination
+	azureStorageAccountEnvVarKey = "AZURE_STORAGE_ACCOUNT"
+	// azureStorageKeyEnvVarKey defines the environment variable key for the account key of an Azure Blob backup destination
+	azureStorageKeyEnvVarKey = "AZURE_STORAGE_KEY"
+	// azureContainerNameEnvVarKey defines the environment variable key for the container of an Azure Blob backup destination
+	azureContainerNameEnvVarKey = "AZURE_CONTAINER_NAME"
+)
+
+// validateBackupDestination ensures that exactly one destination type with all required fields is set.
+func validateBackupDestination(destination *kubermaticv1.BackupDestination) error {
+	if destination == nil {
+		return nil
+	}
+
+	configured := 0
+	if destination.S3 != nil {
+		configured++
+		if destination.S3.Endpoint == "" || destination.S3.BucketName == "" {
+			return errors.New("s3 backup destination requires endpoint and bucketName")
+		}
+	}
+	if destination.AzureBlob != nil {
+		configured++
+		if destination.AzureBlob.AccountName == "" || destination.AzureBlob.ContainerName == "" || destination.AzureBlob.CredentialsSecretName == "" {
+			return errors.New("azureBlob backup destination requires accountName, containerName and credentialsSecretName")
+		}
+	}
+
+	if configured != 1 {
+		return errors.New("exactly one backup destination type must be set")
+	}
+
+	return nil
+}
+
+// supportsBackupDestinations returns whether the configured store and delete containers use the s3-storeuploader,
+// which reads the backend from the environment set by applyBackupDestination. Custom containers, like the former
+// s3cmd based defaults, would silently ignore the destination and keep using the seed-wide S3 settings.
+func (r *Reconciler) supportsBackupDestinations() bool {
+	for _, container := range []*corev1.Container{r.storeContainer, r.deleteContainer} {
+		if container != nil && !usesStoreUploader(container) {
+			return false
+		}
+	}
+	return true
+}
+
+func usesStoreUploader(container *corev1.Container) bool {
+	for _, arg := range append(append([]string{}, container.Command...), container.Args...) {
+		if strings.Contains(arg, "s3-storeuploader") {
+			return true
+		}
+	}
+	return false
+}
+
+// applyBackupDestination configures all containers of the given pod to store backups in the given
+// destination, overriding the seed-wide defaults the containers were configured with.
+func applyBackupDestination(destination *kubermaticv1.BackupDestination, podSpec *corev1.PodSpec) {
+	if destination == nil {
+		return
+	}
+
+	env := []corev1.EnvVar{{
+		Name:  backupDestinationTypeEnvVarKey,
+		Value: string(destination.Type()),
+	}}
+
+	switch {
+	case destination.S3 != nil:
+		env = append(env,
+			corev1.EnvVar{
+				Name:  resources.EtcdRestoreS3EndpointKey,
+				Value: destination.S3.Endpoint,
+			},
+			corev1.EnvVar{
+				Name:  resources.EtcdRestoreS3BucketNameKey,
+				Value: destination.S3.BucketName,
+			})
+		if destination.S3.CredentialsSecretName != "" {
+			env = append(env,
+				secretEnvVar(resources.EtcdRestoreS3AccessKeyIDKey, destination.S3.CredentialsSecretName, resources.EtcdRestoreS3AccessKeyIDKey),
+				secretEnvVar(resources.EtcdRestoreS3SecretKeyAccessKeyKey, destination.S3.CredentialsSecretName, resources.EtcdRestoreS3SecretKeyAccessKeyKey))
+		}
+
+	case destination.AzureBlob != nil:
+		env = append(env,
+			corev1.EnvVar{
+				Name:  azureStorageAccountEnvVarKey,
+				Value: destination.AzureBlob.AccountName,
+			},
+			corev1.EnvVar{
+				Name:  azureContainerNameEnvVarKey,
+				Value: destination.AzureBlob.ContainerName,
+			},
+			secretEnvVar(azureStorageKeyEnvVarKey, destination.AzureBlob.CredentialsSecretName, resources.EtcdBackupAzureAccountKeyKey))
+	}
+
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		for _, envVar := range env {
+			setEnvVar(container, envVar)
+		}
+	}
+}
+
+func secretEnvVar(name, secretName, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: secretName,
+				},
+				Key: key,
+			},
+		},
+	}
+}
+
+// setEnvVar replaces the container's environment variable of the same name, or appends it if it does not exist.
+func setEnvVar(container *corev1.Container, envVar corev1.EnvVar) {
+	for i := range container.Env {
+		if container.Env[i].Name == envVar.Name {
+			container.Env[i] = envVar
+			return
+		}
+	}
+	container.Env = append(container.Env, envVar)
+}
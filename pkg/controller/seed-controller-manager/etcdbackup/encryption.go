@@ -0,0 +1,113 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdbackup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// DefaultBackupEncryptionImage holds the default image used for encrypting the etcd backups
+	DefaultBackupEncryptionImage = "quay.io/kubermatic/s3-storer:v0.1.8"
+
+	// backupEncryptionVolumeName is the name of the volume containing the active encryption key
+	backupEncryptionVolumeName = "backup-encryption"
+	// backupEncryptionMountPath is the path the active encryption key is mounted at
+	backupEncryptionMountPath = "/etc/backup-encryption"
+)
+
+// validateBackupEncryption ensures that the key Secret exists and contains a valid active key.
+func (r *Reconciler) validateBackupEncryption(ctx context.Context, encryption *kubermaticv1.BackupEncryption) error {
+	if encryption == nil {
+		return nil
+	}
+
+	if encryption.KeySecretName == "" || encryption.ActiveKeyID == "" {
+		return errors.New("backup encryption requires keySecretName and activeKeyID")
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: encryption.KeySecretName}, secret); err != nil {
+		return fmt.Errorf("failed to get backup encryption key secret: %v", err)
+	}
+
+	key, ok := secret.Data[encryption.ActiveKeyID]
+	if !ok {
+		return fmt.Errorf("backup encryption key secret %s does not contain the active key %q", encryption.KeySecretName, encryption.ActiveKeyID)
+	}
+	if l := len(key); l != 16 && l != 24 && l != 32 {
+		return fmt.Errorf("backup encryption key %q must be 16, 24 or 32 bytes long, got %d bytes", encryption.ActiveKeyID, l)
+	}
+
+	return nil
+}
+
+// applyBackupEncryption adds an init container to the backup pod which encrypts the snapshot
+// written by the backup-creator init container, before the store container uploads it.
+func (r *Reconciler) applyBackupEncryption(encryption *kubermaticv1.BackupEncryption, podSpec *corev1.PodSpec) {
+	if encryption == nil {
+		return
+	}
+
+	podSpec.InitContainers = append(podSpec.InitContainers, corev1.Container{
+		Name:  "backup-encryptor",
+		Image: r.backupEncryptionImage,
+		Command: []string{
+			"/usr/local/bin/s3-storeuploader",
+			"encrypt",
+			"--file", "/backup/snapshot.db",
+			"--key-file", path.Join(backupEncryptionMountPath, encryption.ActiveKeyID),
+			"--key-id", encryption.ActiveKeyID,
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      SharedVolumeName,
+				MountPath: "/backup",
+			},
+			{
+				Name:      backupEncryptionVolumeName,
+				MountPath: backupEncryptionMountPath,
+				ReadOnly:  true,
+			},
+		},
+	})
+
+	// only mount the active key, the backup job has no use for the others
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: backupEncryptionVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: encryption.KeySecretName,
+				Items: []corev1.KeyToPath{
+					{
+						Key:  encryption.ActiveKeyID,
+						Path: encryption.ActiveKeyID,
+					},
+				},
+			},
+		},
+	})
+}
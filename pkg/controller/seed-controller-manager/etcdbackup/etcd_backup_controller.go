@@ -81,7 +81,7 @@ const (
 	backupToDeleteEnvVarKey = "BACKUP_TO_DELETE"
 	// backupScheduleEnvVarKey defines the environment variable key for the backup schedule
 	backupScheduleEnvVarKey = "BACKUP_SCHEDULE"
-	// backupKeepCountEnvVarKey defines the environment variable key for the number of backups to keep
+	// backupKeepCountEnvVarKey defines the environment variable key for the maximum number of backups retained by the retention policy
 	backupKeepCountEnvVarKey = "BACKUP_KEEP_COUNT"
 	// backupConfigEnvVarKey defines the environment variable key for the name of the backup configuration resource
 	backupConfigEnvVarKey = "BACKUP_CONFIG"
@@ -113,11 +113,14 @@ type Reconciler struct {
 	// backupContainerImage holds the image used for creating the etcd backup
 	// It must be configurable to cover offline use cases
 	backupContainerImage string
-	clock                clock.Clock
-	randStringGenerator  func() string
-	caBundle             resources.CABundle
-	recorder             record.EventRecorder
-	versions             kubermatic.Versions
+	// backupEncryptionImage holds the image used for encrypting the etcd backup,
+	// it must contain the s3-storeuploader binary
+	backupEncryptionImage string
+	clock                 clock.Clock
+	randStringGenerator   func() string
+	caBundle              resources.CABundle
+	recorder              record.EventRecorder
+	versions              kubermatic.Versions
 }
 
 // Add creates a new Backup controller that is responsible for
@@ -131,6 +134,7 @@ func Add(
 	deleteContainer *corev1.Container,
 	cleanupContainer *corev1.Container,
 	backupContainerImage string,
+	backupEncryptionImage string,
 	versions kubermatic.Versions,
 	caBundle resources.CABundle,
 ) error {
@@ -140,20 +144,24 @@ func Add(
 	if backupContainerImage == "" {
 		backupContainerImage = DefaultBackupContainerImage
 	}
+	if backupEncryptionImage == "" {
+		backupEncryptionImage = DefaultBackupEncryptionImage
+	}
 
 	reconciler := &Reconciler{
-		Client:               client,
-		log:                  log,
-		scheme:               mgr.GetScheme(),
-		workerName:           workerName,
-		storeContainer:       storeContainer,
-		deleteContainer:      deleteContainer,
-		cleanupContainer:     cleanupContainer,
-		backupContainerImage: backupContainerImage,
-		recorder:             mgr.GetEventRecorderFor(ControllerName),
-		versions:             versions,
-		clock:                &clock.RealClock{},
-		caBundle:             caBundle,
+		Client:                client,
+		log:                   log,
+		scheme:                mgr.GetScheme(),
+		workerName:            workerName,
+		storeContainer:        storeContainer,
+		deleteContainer:       deleteContainer,
+		cleanupContainer:      cleanupContainer,
+		backupContainerImage:  backupContainerImage,
+		backupEncryptionImage: backupEncryptionImage,
+		recorder:              mgr.GetEventRecorderFor(ControllerName),
+		versions:              versions,
+		clock:                 &clock.RealClock{},
+		caBundle:              caBundle,
 		randStringGenerator: func() string {
 			return rand.String(10)
 		},
@@ -233,6 +241,22 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 }
 
 func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	if err := validateBackupDestination(backupConfig.Spec.Destination); err != nil {
+		return nil, errors.Wrap(err, "invalid backup destination")
+	}
+
+	if backupConfig.Spec.Destination != nil && !r.supportsBackupDestinations() {
+		return nil, errors.New("backup destinations require the backup store and delete containers to use the s3-storeuploader")
+	}
+
+	if err := r.validateBackupEncryption(ctx, backupConfig.Spec.Encryption); err != nil {
+		return nil, errors.Wrap(err, "invalid backup encryption")
+	}
+
+	if err := validateRetentionPolicy(backupConfig.Spec.Retention); err != nil {
+		return nil, errors.Wrap(err, "invalid retention policy")
+	}
+
 	if err := r.ensureSecrets(ctx, cluster); err != nil {
 		return nil, errors.Wrap(err, "failed to create backup secrets")
 	}
@@ -302,7 +326,7 @@ func (r *Reconciler) ensurePendingBackupIsScheduled(ctx context.Context, backupC
 		return nil, nil
 	}
 
-	if len(backupConfig.Status.CurrentBackups) > 2*backupConfig.GetKeptBackupsCount() {
+	if len(backupConfig.Status.CurrentBackups) > 2*maxRetainedBackups(backupConfig) {
 		// keeping track of many backups already, don't schedule new ones.
 		if r.setBackupConfigCondition(
 			backupConfig,
@@ -483,19 +507,21 @@ func (r *Reconciler) startPendingBackupJobs(ctx context.Context, backupConfig *k
 	return returnReconcile, nil
 }
 
-// create any backup delete jobs that can be created, i.e. for all completed backups older than the last backupConfig.GetKeptBackupsCount() ones.
+// create any backup delete jobs that can be created, i.e. for all failed backups and all completed backups
+// which are not retained by the backupConfig's retention policy.
 func (r *Reconciler) startPendingBackupDeleteJobs(ctx context.Context, backupConfig *kubermaticv1.EtcdBackupConfig, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
 	// one-shot backups are not deleted until their backupConfig is deleted
 	if backupConfig.Spec.Schedule == "" && backupConfig.DeletionTimestamp == nil {
 		return nil, nil
 	}
 
-	var backupsToDelete []*kubermaticv1.BackupStatus
-	keepCount := backupConfig.GetKeptBackupsCount()
-	if backupConfig.DeletionTimestamp != nil {
-		keepCount = 0
+	notRetained, statusModified := evaluateRetention(backupConfig, backupConfig.DeletionTimestamp != nil)
+	expired := make(map[*kubermaticv1.BackupStatus]bool, len(notRetained))
+	for _, backup := range notRetained {
+		expired[backup] = true
 	}
-	kept := 0
+
+	var backupsToDelete []*kubermaticv1.BackupStatus
 	runningDeleteJobsCount := 0
 	for i := len(backupConfig.Status.CurrentBackups) - 1; i >= 0; i-- {
 		backup := &backupConfig.Status.CurrentBackups[i]
@@ -504,11 +530,8 @@ func (r *Reconciler) startPendingBackupDeleteJobs(ctx context.Context, backupCon
 		}
 		if backup.BackupPhase == kubermaticv1.BackupStatusPhaseFailed && backup.DeletePhase == "" {
 			backupsToDelete = append(backupsToDelete, backup)
-		} else if backup.BackupPhase == kubermaticv1.BackupStatusPhaseCompleted {
-			kept++
-			if kept > keepCount && backup.DeletePhase == "" {
-				backupsToDelete = append(backupsToDelete, backup)
-			}
+		} else if expired[backup] {
+			backupsToDelete = append(backupsToDelete, backup)
 		}
 	}
 
@@ -523,10 +546,13 @@ func (r *Reconciler) startPendingBackupDeleteJobs(ctx context.Context, backupCon
 		}
 	}
 
-	if modified {
+	if modified || statusModified {
 		if err := r.Update(ctx, backupConfig); err != nil {
 			return nil, errors.Wrap(err, "failed to update backup config")
 		}
+	}
+
+	if modified {
 		return &reconcile.Result{RequeueAfter: assumedJobRuntime}, nil
 	}
 
@@ -808,7 +834,7 @@ func (r *Reconciler) backupJob(backupConfig *kubermaticv1.EtcdBackupConfig, clus
 		},
 		corev1.EnvVar{
 			Name:  backupKeepCountEnvVarKey,
-			Value: strconv.Itoa(backupConfig.GetKeptBackupsCount()),
+			Value: strconv.Itoa(maxRetainedBackups(backupConfig)),
 		},
 		corev1.EnvVar{
 			Name:  backupConfigEnvVarKey,
@@ -902,6 +928,9 @@ func (r *Reconciler) backupJob(backupConfig *kubermaticv1.EtcdBackupConfig, clus
 		},
 	}
 
+	r.applyBackupEncryption(backupConfig.Spec.Encryption, &job.Spec.Template.Spec)
+	applyBackupDestination(backupConfig.Spec.Destination, &job.Spec.Template.Spec)
+
 	return job
 }
 
@@ -923,7 +952,7 @@ func (r *Reconciler) backupDeleteJob(backupConfig *kubermaticv1.EtcdBackupConfig
 		},
 		corev1.EnvVar{
 			Name:  backupKeepCountEnvVarKey,
-			Value: strconv.Itoa(backupConfig.GetKeptBackupsCount()),
+			Value: strconv.Itoa(maxRetainedBackups(backupConfig)),
 		},
 		corev1.EnvVar{
 			Name:  backupConfigEnvVarKey,
@@ -951,6 +980,7 @@ func (r *Reconciler) backupDeleteJob(backupConfig *kubermaticv1.EtcdBackupConfig
 			},
 		},
 	}
+	applyBackupDestination(backupConfig.Spec.Destination, &job.Spec.Template.Spec)
 	return job
 }
 
@@ -985,6 +1015,7 @@ func (r *Reconciler) cleanupJob(backupConfig *kubermaticv1.EtcdBackupConfig, clu
 			},
 		},
 	}
+	applyBackupDestination(backupConfig.Spec.Destination, &job.Spec.Template.Spec)
 	return job
 }
 
@@ -660,6 +660,7 @@ func TestStartPendingBackupDeleteJobs(t *testing.T) {
 		name              string
 		currentTime       time.Time
 		keep              int
+		retention         *kubermaticv1.BackupRetentionPolicy
 		existingBackups   []kubermaticv1.BackupStatus
 		existingJobs      []batchv1.Job
 		expectedBackups   []kubermaticv1.BackupStatus
@@ -716,6 +717,7 @@ func TestStartPendingBackupDeleteJobs(t *testing.T) {
 					BackupPhase:        kubermaticv1.BackupStatusPhaseCompleted,
 					BackupMessage:      "job completed",
 					DeleteJobName:      "testcluster-backup-testbackup-delete-bbbb",
+					RetainedBy:         []kubermaticv1.BackupRetentionRule{kubermaticv1.BackupRetentionRuleLatest},
 				},
 				{
 					ScheduledTime: &metav1.Time{Time: time.Unix(180, 0).UTC()},
@@ -826,6 +828,7 @@ func TestStartPendingBackupDeleteJobs(t *testing.T) {
 					BackupPhase:        kubermaticv1.BackupStatusPhaseCompleted,
 					BackupMessage:      "job completed",
 					DeleteJobName:      "testcluster-backup-testbackup-delete-cccc",
+					RetainedBy:         []kubermaticv1.BackupRetentionRule{kubermaticv1.BackupRetentionRuleLatest},
 				},
 			},
 			expectedReconcile: &reconcile.Result{RequeueAfter: assumedJobRuntime},
@@ -899,6 +902,9 @@ func TestStartPendingBackupDeleteJobs(t *testing.T) {
 				if i > 0 && i <= maxSimultaneousDeleteJobsPerConfig {
 					result.DeletePhase = kubermaticv1.BackupStatusPhaseRunning
 				}
+				if i == maxSimultaneousDeleteJobsPerConfig+1 {
+					result.RetainedBy = []kubermaticv1.BackupRetentionRule{kubermaticv1.BackupRetentionRuleLatest}
+				}
 				return result
 			}),
 			expectedReconcile: &reconcile.Result{RequeueAfter: assumedJobRuntime},
@@ -906,6 +912,53 @@ func TestStartPendingBackupDeleteJobs(t *testing.T) {
 				return *genBackupDeleteJob(fmt.Sprintf("testbackup-%v", i+1), fmt.Sprintf("testcluster-backup-testbackup-%v-delete", i+1))
 			}),
 		},
+		{
+			name:        "generational retention policy",
+			currentTime: time.Date(2021, 3, 10, 12, 30, 0, 0, time.UTC),
+			keep:        1,
+			retention:   &kubermaticv1.BackupRetentionPolicy{Hourly: 2, Daily: 2},
+			existingBackups: genBackupStatusList(4, func(i int) kubermaticv1.BackupStatus {
+				// 2021-03-09 11:00, 2021-03-09 12:00, 2021-03-10 11:00, 2021-03-10 12:00
+				scheduled := time.Date(2021, 3, 9+i/2, 11+i%2, 0, 0, 0, time.UTC)
+				return kubermaticv1.BackupStatus{
+					ScheduledTime:      &metav1.Time{Time: scheduled},
+					BackupName:         fmt.Sprintf("testbackup-%v", i),
+					JobName:            fmt.Sprintf("testcluster-backup-testbackup-%v-create", i),
+					BackupFinishedTime: &metav1.Time{Time: scheduled.Add(time.Minute)},
+					BackupPhase:        kubermaticv1.BackupStatusPhaseCompleted,
+					BackupMessage:      "job completed",
+					DeleteJobName:      fmt.Sprintf("testcluster-backup-testbackup-%v-delete", i),
+				}
+			}),
+			existingJobs: []batchv1.Job{},
+			expectedBackups: genBackupStatusList(4, func(i int) kubermaticv1.BackupStatus {
+				scheduled := time.Date(2021, 3, 9+i/2, 11+i%2, 0, 0, 0, time.UTC)
+				result := kubermaticv1.BackupStatus{
+					ScheduledTime:      &metav1.Time{Time: scheduled},
+					BackupName:         fmt.Sprintf("testbackup-%v", i),
+					JobName:            fmt.Sprintf("testcluster-backup-testbackup-%v-create", i),
+					BackupFinishedTime: &metav1.Time{Time: scheduled.Add(time.Minute)},
+					BackupPhase:        kubermaticv1.BackupStatusPhaseCompleted,
+					BackupMessage:      "job completed",
+					DeleteJobName:      fmt.Sprintf("testcluster-backup-testbackup-%v-delete", i),
+				}
+				switch i {
+				case 0:
+					result.DeletePhase = kubermaticv1.BackupStatusPhaseRunning
+				case 1:
+					result.RetainedBy = []kubermaticv1.BackupRetentionRule{kubermaticv1.BackupRetentionRuleDaily}
+				case 2:
+					result.RetainedBy = []kubermaticv1.BackupRetentionRule{kubermaticv1.BackupRetentionRuleHourly}
+				case 3:
+					result.RetainedBy = []kubermaticv1.BackupRetentionRule{kubermaticv1.BackupRetentionRuleHourly, kubermaticv1.BackupRetentionRuleDaily}
+				}
+				return result
+			}),
+			expectedReconcile: &reconcile.Result{RequeueAfter: assumedJobRuntime},
+			expectedJobs: []batchv1.Job{
+				*genBackupDeleteJob("testbackup-0", "testcluster-backup-testbackup-0-delete"),
+			},
+		},
 	}
 	for _, tc := range testCases {
 		tc := tc
@@ -918,6 +971,7 @@ func TestStartPendingBackupDeleteJobs(t *testing.T) {
 			backupConfig.SetCreationTimestamp(metav1.Time{Time: clock.Now()})
 			backupConfig.Spec.Schedule = "xxx" // must be non-empty
 			backupConfig.Spec.Keep = intPtr(tc.keep)
+			backupConfig.Spec.Retention = tc.retention
 			backupConfig.Status.CurrentBackups = tc.existingBackups
 
 			initObjs := []client.Object{
@@ -1860,3 +1914,304 @@ func constRandStringGenerator(str string) func() string {
 		return str
 	}
 }
+
+func TestBackupJobDestination(t *testing.T) {
+	envValue := func(container corev1.Container, name string) *corev1.EnvVar {
+		for i := range container.Env {
+			if container.Env[i].Name == name {
+				return &container.Env[i]
+			}
+		}
+		return nil
+	}
+
+	testCases := []struct {
+		name        string
+		destination *kubermaticv1.BackupDestination
+		validate    func(t *testing.T, job *batchv1.Job)
+		expectedErr bool
+	}{
+		{
+			name: "no destination keeps the container's defaults",
+			validate: func(t *testing.T, job *batchv1.Job) {
+				container := job.Spec.Template.Spec.Containers[0]
+				if env := envValue(container, resources.EtcdRestoreS3BucketNameKey); env == nil || env.Value != "default-bucket" {
+					t.Errorf("expected default bucket to be kept, got %v", env)
+				}
+				if env := envValue(container, backupDestinationTypeEnvVarKey); env != nil {
+					t.Errorf("expected no destination type, got %v", env)
+				}
+			},
+		},
+		{
+			name: "s3 destination overrides bucket and credentials",
+			destination: &kubermaticv1.BackupDestination{
+				S3: &kubermaticv1.S3BackupDestination{
+					Endpoint:              "s3.example.com",
+					BucketName:            "regulated",
+					CredentialsSecretName: "regulated-credentials",
+				},
+			},
+			validate: func(t *testing.T, job *batchv1.Job) {
+				container := job.Spec.Template.Spec.Containers[0]
+				if env := envValue(container, resources.EtcdRestoreS3BucketNameKey); env == nil || env.Value != "regulated" {
+					t.Errorf("expected bucket to be overridden, got %v", env)
+				}
+				if env := envValue(container, resources.EtcdRestoreS3EndpointKey); env == nil || env.Value != "s3.example.com" {
+					t.Errorf("expected endpoint to be overridden, got %v", env)
+				}
+				env := envValue(container, resources.EtcdRestoreS3AccessKeyIDKey)
+				if env == nil || env.ValueFrom == nil || env.ValueFrom.SecretKeyRef.Name != "regulated-credentials" {
+					t.Errorf("expected access key to be read from regulated-credentials, got %v", env)
+				}
+				if env := envValue(container, backupDestinationTypeEnvVarKey); env == nil || env.Value != "s3" {
+					t.Errorf("expected destination type s3, got %v", env)
+				}
+			},
+		},
+		{
+			name: "multiple destination types are rejected",
+			destination: &kubermaticv1.BackupDestination{
+				S3: &kubermaticv1.S3BackupDestination{
+					Endpoint:   "s3.example.com",
+					BucketName: "regulated",
+				},
+				AzureBlob: &kubermaticv1.AzureBlobBackupDestination{
+					AccountName:           "account",
+					ContainerName:         "backups",
+					CredentialsSecretName: "azure-credentials",
+				},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := validateBackupDestination(tc.destination); (err != nil) != tc.expectedErr {
+				t.Fatalf("expected error: %v, got %v", tc.expectedErr, err)
+			}
+			if tc.expectedErr {
+				return
+			}
+
+			cluster := genTestCluster()
+			backupConfig := genBackupConfig(cluster, "testbackup")
+			backupConfig.Spec.Destination = tc.destination
+
+			storeContainer := genStoreContainer()
+			storeContainer.Env = []corev1.EnvVar{{Name: resources.EtcdRestoreS3BucketNameKey, Value: "default-bucket"}}
+
+			reconciler := Reconciler{
+				storeContainer: storeContainer,
+			}
+			job := reconciler.backupJob(backupConfig, cluster, &kubermaticv1.BackupStatus{BackupName: "backup", JobName: "job"})
+			tc.validate(t, job)
+		})
+	}
+}
+
+func TestSupportsBackupDestinations(t *testing.T) {
+	storeUploaderContainer := func() *corev1.Container {
+		return &corev1.Container{
+			Name:    "test-store-container",
+			Image:   "s3-storer:latest",
+			Command: []string{"/bin/sh", "-c", "s3-storeuploader upload ..."},
+		}
+	}
+
+	testCases := []struct {
+		name            string
+		storeContainer  *corev1.Container
+		deleteContainer *corev1.Container
+		expected        bool
+	}{
+		{
+			name:            "store and delete containers use the s3-storeuploader",
+			storeContainer:  storeUploaderContainer(),
+			deleteContainer: storeUploaderContainer(),
+			expected:        true,
+		},
+		{
+			name:           "store container uses the s3-storeuploader without delete container",
+			storeContainer: storeUploaderContainer(),
+			expected:       true,
+		},
+		{
+			name:            "custom s3cmd store container",
+			storeContainer:  genStoreContainer(),
+			deleteContainer: storeUploaderContainer(),
+			expected:        false,
+		},
+		{
+			name:            "custom s3cmd delete container",
+			storeContainer:  storeUploaderContainer(),
+			deleteContainer: genDeleteContainer(),
+			expected:        false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reconciler := Reconciler{
+				storeContainer:  tc.storeContainer,
+				deleteContainer: tc.deleteContainer,
+			}
+			if supported := reconciler.supportsBackupDestinations(); supported != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, supported)
+			}
+		})
+	}
+}
+
+func TestBackupJobEncryption(t *testing.T) {
+	keySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "backup-keys",
+			Namespace: metav1.NamespaceSystem,
+		},
+		Data: map[string][]byte{
+			"2021-01": make([]byte, 32),
+			"2021-06": make([]byte, 32),
+			"short":   make([]byte, 10),
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		encryption  *kubermaticv1.BackupEncryption
+		expectedErr bool
+	}{
+		{
+			name: "no encryption",
+		},
+		{
+			name: "active key is used",
+			encryption: &kubermaticv1.BackupEncryption{
+				KeySecretName: "backup-keys",
+				ActiveKeyID:   "2021-06",
+			},
+		},
+		{
+			name: "missing key is rejected",
+			encryption: &kubermaticv1.BackupEncryption{
+				KeySecretName: "backup-keys",
+				ActiveKeyID:   "2021-12",
+			},
+			expectedErr: true,
+		},
+		{
+			name: "invalid key size is rejected",
+			encryption: &kubermaticv1.BackupEncryption{
+				KeySecretName: "backup-keys",
+				ActiveKeyID:   "short",
+			},
+			expectedErr: true,
+		},
+		{
+			name: "missing secret is rejected",
+			encryption: &kubermaticv1.BackupEncryption{
+				KeySecretName: "other-keys",
+				ActiveKeyID:   "2021-06",
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reconciler := Reconciler{
+				Client:                ctrlruntimefakeclient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(keySecret).Build(),
+				storeContainer:        genStoreContainer(),
+				backupEncryptionImage: DefaultBackupEncryptionImage,
+			}
+
+			if err := reconciler.validateBackupEncryption(context.Background(), tc.encryption); (err != nil) != tc.expectedErr {
+				t.Fatalf("expected error: %v, got %v", tc.expectedErr, err)
+			}
+			if tc.expectedErr {
+				return
+			}
+
+			cluster := genTestCluster()
+			backupConfig := genBackupConfig(cluster, "testbackup")
+			backupConfig.Spec.Encryption = tc.encryption
+
+			job := reconciler.backupJob(backupConfig, cluster, &kubermaticv1.BackupStatus{BackupName: "backup", JobName: "job"})
+			initContainers := job.Spec.Template.Spec.InitContainers
+
+			if tc.encryption == nil {
+				if len(initContainers) != 1 {
+					t.Fatalf("expected only the backup-creator init container, got %d init containers", len(initContainers))
+				}
+				return
+			}
+
+			// the snapshot must be encrypted after it was created
+			if len(initContainers) != 2 || initContainers[1].Name != "backup-encryptor" {
+				t.Fatalf("expected backup-encryptor to be the second init container, got %v", initContainers)
+			}
+			expectedCommand := []string{
+				"/usr/local/bin/s3-storeuploader", "encrypt",
+				"--file", "/backup/snapshot.db",
+				"--key-file", "/etc/backup-encryption/2021-06",
+				"--key-id", "2021-06",
+			}
+			if diff := deep.Equal(initContainers[1].Command, expectedCommand); diff != nil {
+				t.Errorf("unexpected encryption command, diff: %v", diff)
+			}
+
+			var keyVolume *corev1.Volume
+			for i, volume := range job.Spec.Template.Spec.Volumes {
+				if volume.Name == backupEncryptionVolumeName {
+					keyVolume = &job.Spec.Template.Spec.Volumes[i]
+				}
+			}
+			if keyVolume == nil || keyVolume.Secret == nil {
+				t.Fatal("expected job to mount the encryption key secret")
+			}
+			if len(keyVolume.Secret.Items) != 1 || keyVolume.Secret.Items[0].Key != "2021-06" {
+				t.Errorf("expected only the active key to be mounted, got %v", keyVolume.Secret.Items)
+			}
+		})
+	}
+}
+
+func TestValidateRetentionPolicy(t *testing.T) {
+	testCases := []struct {
+		name        string
+		policy      *kubermaticv1.BackupRetentionPolicy
+		expectedErr bool
+	}{
+		{
+			name: "no policy",
+		},
+		{
+			name:   "generational policy",
+			policy: &kubermaticv1.BackupRetentionPolicy{Hourly: 24, Daily: 7, Weekly: 4, Monthly: 12},
+		},
+		{
+			name:        "empty policy",
+			policy:      &kubermaticv1.BackupRetentionPolicy{},
+			expectedErr: true,
+		},
+		{
+			name:        "negative count",
+			policy:      &kubermaticv1.BackupRetentionPolicy{Latest: 5, Daily: -1},
+			expectedErr: true,
+		},
+		{
+			name:        "too many backups",
+			policy:      &kubermaticv1.BackupRetentionPolicy{Hourly: 72, Daily: 30},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := validateRetentionPolicy(tc.policy); (err != nil) != tc.expectedErr {
+				t.Errorf("expected error: %v, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
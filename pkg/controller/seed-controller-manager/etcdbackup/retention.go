@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdbackup
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/util/retention"
+)
+
+// validateRetentionPolicy ensures that the policy retains at least one and not too many backups.
+func validateRetentionPolicy(policy *kubermaticv1.BackupRetentionPolicy) error {
+	if policy == nil {
+		return nil
+	}
+
+	for _, count := range []int{policy.Latest, policy.Hourly, policy.Daily, policy.Weekly, policy.Monthly} {
+		if count < 0 {
+			return errors.New("retention counts must not be negative")
+		}
+	}
+
+	total := toRetentionPolicy(*policy).MaxRetained()
+	if total == 0 {
+		return errors.New("retention policy must retain at least one backup")
+	}
+	if total > kubermaticv1.MaxRetainedBackupsCount {
+		return fmt.Errorf("retention policy retains up to %d backups, which exceeds the maximum of %d", total, kubermaticv1.MaxRetainedBackupsCount)
+	}
+
+	return nil
+}
+
+func toRetentionPolicy(policy kubermaticv1.BackupRetentionPolicy) retention.Policy {
+	return retention.Policy{
+		Latest:  policy.Latest,
+		Hourly:  policy.Hourly,
+		Daily:   policy.Daily,
+		Weekly:  policy.Weekly,
+		Monthly: policy.Monthly,
+	}
+}
+
+// maxRetainedBackups returns the maximum number of backups retained by the retention policy of the backup config.
+func maxRetainedBackups(backupConfig *kubermaticv1.EtcdBackupConfig) int {
+	return toRetentionPolicy(backupConfig.GetRetentionPolicy()).MaxRetained()
+}
+
+// evaluateRetention records the rules each completed backup is retained under in its status and returns
+// the completed backups which are not retained by any rule and not being deleted yet.
+// If retainNone is set, no backups are retained. The second return value indicates whether the status
+// of any backup was changed.
+func evaluateRetention(backupConfig *kubermaticv1.EtcdBackupConfig, retainNone bool) ([]*kubermaticv1.BackupStatus, bool) {
+	policy := retention.Policy{}
+	if !retainNone {
+		policy = toRetentionPolicy(backupConfig.GetRetentionPolicy())
+	}
+
+	// backups which are already being deleted must not retain a period on behalf of others
+	var candidates []*kubermaticv1.BackupStatus
+	var times []time.Time
+	for i := range backupConfig.Status.CurrentBackups {
+		backup := &backupConfig.Status.CurrentBackups[i]
+		if backup.BackupPhase == kubermaticv1.BackupStatusPhaseCompleted && backup.DeletePhase == "" {
+			candidates = append(candidates, backup)
+			times = append(times, backup.ScheduledTime.Time)
+		}
+	}
+
+	modified := false
+	var notRetained []*kubermaticv1.BackupStatus
+	rules := retention.Evaluate(policy, times)
+	for i, backup := range candidates {
+		var retainedBy []kubermaticv1.BackupRetentionRule
+		for _, rule := range rules[i] {
+			retainedBy = append(retainedBy, kubermaticv1.BackupRetentionRule(rule))
+		}
+		if !reflect.DeepEqual(backup.RetainedBy, retainedBy) {
+			backup.RetainedBy = retainedBy
+			modified = true
+		}
+
+		if len(retainedBy) == 0 {
+			notRetained = append(notRetained, backup)
+		}
+	}
+
+	return notRetained, modified
+}
@@ -18,17 +18,18 @@ package etcdrestore
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"time"
 
-	"github.com/minio/minio-go"
 	"go.uber.org/zap"
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1/helper"
 	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
 	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/storeuploader"
 	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
 
 	v1 "k8s.io/api/apps/v1"
@@ -67,6 +68,8 @@ type Reconciler struct {
 	ctrlruntimeclient.Client
 	recorder record.EventRecorder
 	versions kubermatic.Versions
+	// backupStore returns the store to download the backup of the restore from
+	backupStore func(ctx context.Context, restore *kubermaticv1.EtcdRestore, cluster *kubermaticv1.Cluster) (storeuploader.BackupStore, error)
 }
 
 // Add creates a new etcd restore controller that is responsible for
@@ -87,6 +90,9 @@ func Add(
 		workerName: workerName,
 		recorder:   mgr.GetEventRecorderFor(ControllerName),
 		versions:   versions,
+		backupStore: func(ctx context.Context, restore *kubermaticv1.EtcdRestore, cluster *kubermaticv1.Cluster) (storeuploader.BackupStore, error) {
+			return resources.GetEtcdRestoreBackupStore(ctx, restore, true, client, cluster)
+		},
 	}
 
 	ctrlOptions := controller.Options{
@@ -101,17 +107,22 @@ func Add(
 	incompleteRestorePredicates := predicate.Funcs{
 		CreateFunc: func(e event.CreateEvent) bool {
 			restore := e.Object.(*kubermaticv1.EtcdRestore)
-			return restore.Status.Phase != kubermaticv1.EtcdRestorePhaseCompleted
+			return !isRestoreFinished(restore)
 		},
 		UpdateFunc: func(e event.UpdateEvent) bool {
 			restore := e.ObjectNew.(*kubermaticv1.EtcdRestore)
-			return restore.Status.Phase != kubermaticv1.EtcdRestorePhaseCompleted
+			return !isRestoreFinished(restore)
 		},
 	}
 
 	return c.Watch(&source.Kind{Type: &kubermaticv1.EtcdRestore{}}, &handler.EnqueueRequestForObject{}, incompleteRestorePredicates)
 }
 
+func isRestoreFinished(restore *kubermaticv1.EtcdRestore) bool {
+	return restore.Status.Phase == kubermaticv1.EtcdRestorePhaseCompleted ||
+		restore.Status.Phase == kubermaticv1.EtcdRestorePhaseValidationFailed
+}
+
 func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
 	log := r.log.With("request", request)
 	log.Debug("Processing")
@@ -150,11 +161,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 }
 
 func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, restore *kubermaticv1.EtcdRestore, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
-	if restore.Status.Phase == kubermaticv1.EtcdRestorePhaseCompleted {
+	if isRestoreFinished(restore) {
 		return nil, nil
 	}
 
-	log.Infof("performing etcd restore from backup %v", restore.Spec.BackupName)
+	log.Infof("performing etcd restore from backup %q", restore.Spec.BackupName)
 
 	if restore.DeletionTimestamp == nil {
 		if err := r.updateRestore(ctx, restore, func(restore *kubermaticv1.EtcdRestore) {
@@ -189,15 +200,15 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, rest
 		return r.rebuildEtcdStatefulset(ctx, log, restore, cluster)
 	}
 
-	// check that the backup to restore from exists and is accessible
-	s3Client, bucketName, err := resources.GetEtcdRestoreS3Client(ctx, restore, true, r.Client, cluster)
-	if err != nil {
-		return nil, fmt.Errorf("failed to obtain S3 client: %w", err)
-	}
-
-	objectName := fmt.Sprintf("%s-%s", cluster.GetName(), restore.Spec.BackupName)
-	if _, err := s3Client.StatObject(bucketName, objectName, minio.StatObjectOptions{}); err != nil {
-		return nil, fmt.Errorf("could not access backup object %s: %w", objectName, err)
+	// check that the backup to restore from exists and is intact before the etcd statefulset is torn down
+	if restore.Status.Snapshot == nil {
+		valid, err := r.validateBackup(ctx, log, restore, cluster)
+		if err != nil {
+			return nil, err
+		}
+		if !valid {
+			return nil, nil
+		}
 	}
 
 	// pause cluster
@@ -215,7 +226,7 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, rest
 
 	// delete etcd sts
 	sts := &v1.StatefulSet{}
-	err = r.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: resources.EtcdStatefulSetName}, sts)
+	err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: resources.EtcdStatefulSetName}, sts)
 	if err == nil {
 		if err := r.Delete(ctx, sts); err != nil && !kerrors.IsNotFound(err) {
 			return nil, fmt.Errorf("failed to delete etcd statefulset: %v", err)
@@ -259,6 +270,93 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, rest
 	return r.rebuildEtcdStatefulset(ctx, log, restore, cluster)
 }
 
+// validateBackup determines the backup to restore from if none was given, downloads it and verifies its integrity.
+// The result of the validation is recorded in the restore's status. If the backup is missing or corrupt, the
+// restore is marked as failed and false is returned.
+func (r *Reconciler) validateBackup(ctx context.Context, log *zap.SugaredLogger, restore *kubermaticv1.EtcdRestore, cluster *kubermaticv1.Cluster) (bool, error) {
+	backupConfigs := &kubermaticv1.EtcdBackupConfigList{}
+	if err := r.List(ctx, backupConfigs, ctrlruntimeclient.InNamespace(cluster.Status.NamespaceName)); err != nil {
+		return false, fmt.Errorf("failed to list etcd backup configs: %v", err)
+	}
+
+	if restore.Spec.BackupName == "" {
+		backup, err := selectBackup(availableBackups(backupConfigs.Items, cluster.Name, restore.Spec.BackupConfigName), restore.Spec.PointInTime)
+		if err != nil {
+			return false, r.failValidation(ctx, restore, cluster, err.Error())
+		}
+
+		log.Infow("Selected backup to restore from", "backup", backup.BackupName, "finished", backup.BackupFinishedTime)
+
+		if err := r.updateRestore(ctx, restore, func(restore *kubermaticv1.EtcdRestore) {
+			restore.Spec.BackupName = backup.BackupName
+		}); err != nil {
+			return false, fmt.Errorf("failed to set selected backup name: %v", err)
+		}
+	}
+
+	if restore.Spec.Destination == nil {
+		if destination := backupDestination(backupConfigs.Items, cluster.Name, restore.Spec.BackupName); destination != nil {
+			if err := r.updateRestore(ctx, restore, func(restore *kubermaticv1.EtcdRestore) {
+				restore.Spec.Destination = destination.DeepCopy()
+			}); err != nil {
+				return false, fmt.Errorf("failed to set backup destination: %v", err)
+			}
+		}
+	}
+
+	store, err := r.backupStore(ctx, restore, cluster)
+	if err != nil {
+		return false, fmt.Errorf("failed to obtain backup store: %w", err)
+	}
+
+	objectName := fmt.Sprintf("%s-%s", cluster.GetName(), restore.Spec.BackupName)
+	getDecryptionKeys := func() (map[string][]byte, error) {
+		return resources.GetEtcdRestoreDecryptionKeys(ctx, restore, true, r.Client, cluster)
+	}
+	snapshotStatus, err := downloadAndValidateSnapshot(log, store, objectName, getDecryptionKeys)
+	if err != nil {
+		var integrityErr *snapshotIntegrityError
+		if errors.As(err, &integrityErr) {
+			return false, r.failValidation(ctx, restore, cluster, integrityErr.Error())
+		}
+		return false, err
+	}
+
+	if err := r.updateRestore(ctx, restore, func(restore *kubermaticv1.EtcdRestore) {
+		restore.Status.Phase = kubermaticv1.EtcdRestorePhaseValidated
+		restore.Status.Snapshot = snapshotStatus
+		restore.Status.Message = ""
+	}); err != nil {
+		return false, fmt.Errorf("failed to set EtcdRestore validated phase: %v", err)
+	}
+
+	r.recorder.Eventf(restore, corev1.EventTypeNormal, "SnapshotValidated",
+		"snapshot %s passed validation (revision %d, %d bytes)", objectName, snapshotStatus.Revision, snapshotStatus.Size)
+
+	return true, nil
+}
+
+// failValidation marks the restore as failed and releases the cluster, which has not been modified yet.
+func (r *Reconciler) failValidation(ctx context.Context, restore *kubermaticv1.EtcdRestore, cluster *kubermaticv1.Cluster, message string) error {
+	r.recorder.Event(restore, corev1.EventTypeWarning, "ValidationFailed", message)
+
+	if err := r.updateCluster(ctx, cluster, func(cluster *kubermaticv1.Cluster) {
+		delete(cluster.Annotations, ActiveRestoreAnnotationName)
+	}); err != nil {
+		return fmt.Errorf("failed to clear cluster active restore annotation: %v", err)
+	}
+
+	if err := r.updateRestore(ctx, restore, func(restore *kubermaticv1.EtcdRestore) {
+		restore.Status.Phase = kubermaticv1.EtcdRestorePhaseValidationFailed
+		restore.Status.Message = message
+		kuberneteshelper.RemoveFinalizer(restore, FinishRestoreFinalizer)
+	}); err != nil {
+		return fmt.Errorf("failed to mark restore as failed: %v", err)
+	}
+
+	return nil
+}
+
 func (r *Reconciler) rebuildEtcdStatefulset(ctx context.Context, log *zap.SugaredLogger, restore *kubermaticv1.EtcdRestore, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
 	log.Infof("rebuildEtcdStatefulset")
 
@@ -0,0 +1,169 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdrestore
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+	"k8c.io/kubermatic/v2/pkg/storeuploader"
+	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestValidateBackupFromDestination(t *testing.T) {
+	azureDestination := &kubermaticv1.BackupDestination{
+		AzureBlob: &kubermaticv1.AzureBlobBackupDestination{
+			AccountName:           "account",
+			ContainerName:         "backups",
+			CredentialsSecretName: "azure-credentials",
+		},
+	}
+
+	testCases := []struct {
+		name                string
+		destination         *kubermaticv1.BackupDestination
+		restoreDestination  *kubermaticv1.BackupDestination
+		expectedPhase       kubermaticv1.EtcdRestorePhase
+		expectedDestination *kubermaticv1.BackupDestination
+	}{
+		{
+			name:                "backup in a custom destination is restored from it",
+			destination:         azureDestination,
+			expectedPhase:       kubermaticv1.EtcdRestorePhaseValidated,
+			expectedDestination: azureDestination,
+		},
+		{
+			name:          "backup in the default location is restored from it",
+			expectedPhase: kubermaticv1.EtcdRestorePhaseValidated,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			log := kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar()
+
+			dir, err := ioutil.TempDir("", "restore-test-")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			// each destination is backed by its own directory, the backup is only stored in the one of the backup config
+			stores := map[string]storeuploader.BackupStore{}
+			storeFor := func(destination *kubermaticv1.BackupDestination) storeuploader.BackupStore {
+				key := "default"
+				if destination != nil {
+					key = string(destination.Type())
+				}
+				if _, ok := stores[key]; !ok {
+					store, err := storeuploader.NewFilesystemStore(fmt.Sprintf("%s/%s", dir, key))
+					if err != nil {
+						t.Fatalf("failed to create store: %v", err)
+					}
+					stores[key] = store
+				}
+				return stores[key]
+			}
+
+			cluster := &kubermaticv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test",
+					Annotations: map[string]string{ActiveRestoreAnnotationName: "restore"},
+				},
+				Status: kubermaticv1.ClusterStatus{NamespaceName: "cluster-test"},
+			}
+			backupConfig := &kubermaticv1.EtcdBackupConfig{
+				ObjectMeta: metav1.ObjectMeta{Namespace: cluster.Status.NamespaceName, Name: "daily"},
+				Spec: kubermaticv1.EtcdBackupConfigSpec{
+					Cluster:     corev1.ObjectReference{Name: cluster.Name},
+					Destination: tc.destination,
+				},
+				Status: kubermaticv1.EtcdBackupConfigStatus{
+					CurrentBackups: []kubermaticv1.BackupStatus{
+						genBackupStatus("daily-1", time.Now(), kubermaticv1.BackupStatusPhaseCompleted),
+					},
+				},
+			}
+			restore := &kubermaticv1.EtcdRestore{
+				ObjectMeta: metav1.ObjectMeta{Namespace: cluster.Status.NamespaceName, Name: "restore"},
+				Spec: kubermaticv1.EtcdRestoreSpec{
+					Cluster:     corev1.ObjectReference{Name: cluster.Name},
+					Destination: tc.restoreDestination,
+				},
+			}
+
+			// the backup job uploads the snapshot to the destination of the backup config as <cluster>-<backup>
+			snapshotDir, err := ioutil.TempDir("", "snapshot-")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(snapshotDir)
+			if err := storeFor(tc.destination).Upload(writeTestSnapshot(t, snapshotDir, 42), "test-daily-1"); err != nil {
+				t.Fatalf("failed to upload backup: %v", err)
+			}
+
+			client := ctrlruntimefakeclient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(cluster, backupConfig, restore).Build()
+			r := &Reconciler{
+				log:      log,
+				Client:   client,
+				recorder: record.NewFakeRecorder(10),
+				versions: kubermatic.NewFakeVersions(),
+				backupStore: func(_ context.Context, restore *kubermaticv1.EtcdRestore, _ *kubermaticv1.Cluster) (storeuploader.BackupStore, error) {
+					return storeFor(restore.Spec.Destination), nil
+				},
+			}
+
+			if _, err := r.validateBackup(ctx, log, restore, cluster); err != nil {
+				t.Fatalf("failed to validate backup: %v", err)
+			}
+
+			if err := client.Get(ctx, types.NamespacedName{Namespace: restore.Namespace, Name: restore.Name}, restore); err != nil {
+				t.Fatalf("failed to get restore: %v", err)
+			}
+			if restore.Status.Phase != tc.expectedPhase {
+				t.Errorf("expected phase %q, got %q (%s)", tc.expectedPhase, restore.Status.Phase, restore.Status.Message)
+			}
+			if diff := deep.Equal(restore.Spec.Destination, tc.expectedDestination); diff != nil {
+				t.Errorf("unexpected destination: %v", diff)
+			}
+			if tc.expectedPhase == kubermaticv1.EtcdRestorePhaseValidated {
+				if restore.Spec.BackupName != "daily-1" {
+					t.Errorf("expected backup daily-1 to be selected, got %q", restore.Spec.BackupName)
+				}
+				if restore.Status.Snapshot == nil || restore.Status.Snapshot.Revision != 42 {
+					t.Errorf("expected the snapshot with revision 42 to be validated, got %+v", restore.Status.Snapshot)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdrestore
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"go.etcd.io/etcd/v3/clientv3/snapshot"
+	"go.uber.org/zap"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/storeuploader"
+	"k8c.io/kubermatic/v2/pkg/util/encryption"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// snapshotIntegrityError is returned when a snapshot could be downloaded, but is not usable
+// for a restore. Other errors returned while validating a snapshot are considered transient.
+type snapshotIntegrityError struct {
+	err error
+}
+
+func (e *snapshotIntegrityError) Error() string {
+	return fmt.Sprintf("snapshot integrity check failed: %v", e.err)
+}
+
+func (e *snapshotIntegrityError) Unwrap() error {
+	return e.err
+}
+
+// availableBackups returns all completed, not deleted backups of the given cluster, ordered by the time
+// they finished (oldest first). If configName is set, only backups of the EtcdBackupConfig with that name
+// are returned.
+func availableBackups(backupConfigs []kubermaticv1.EtcdBackupConfig, clusterName, configName string) []kubermaticv1.BackupStatus {
+	var backups []kubermaticv1.BackupStatus
+	for _, backupConfig := range backupConfigs {
+		if backupConfig.Spec.Cluster.Name != clusterName {
+			continue
+		}
+		if configName != "" && backupConfig.Name != configName {
+			continue
+		}
+		for _, backup := range backupConfig.Status.CurrentBackups {
+			if backup.BackupPhase != kubermaticv1.BackupStatusPhaseCompleted || backup.BackupFinishedTime == nil {
+				continue
+			}
+			if backup.DeletePhase != "" {
+				continue
+			}
+			backups = append(backups, backup)
+		}
+	}
+
+	sort.SliceStable(backups, func(i, j int) bool {
+		return backups[i].BackupFinishedTime.Before(backups[j].BackupFinishedTime)
+	})
+
+	return backups
+}
+
+// backupDestination returns the destination of the EtcdBackupConfig of the cluster which lists the given backup.
+// It returns nil if the backup was stored in the seed's default S3 location or is not listed by any EtcdBackupConfig.
+func backupDestination(backupConfigs []kubermaticv1.EtcdBackupConfig, clusterName, backupName string) *kubermaticv1.BackupDestination {
+	for _, backupConfig := range backupConfigs {
+		if backupConfig.Spec.Cluster.Name != clusterName {
+			continue
+		}
+		for _, backup := range backupConfig.Status.CurrentBackups {
+			if backup.BackupName == backupName {
+				return backupConfig.Spec.Destination
+			}
+		}
+	}
+	return nil
+}
+
+// selectBackup returns the most recent backup which finished at or before pointInTime, or the most recent
+// backup altogether if pointInTime is nil. backups must be ordered as returned by availableBackups.
+func selectBackup(backups []kubermaticv1.BackupStatus, pointInTime *metav1.Time) (*kubermaticv1.BackupStatus, error) {
+	for i := len(backups) - 1; i >= 0; i-- {
+		if pointInTime == nil || !pointInTime.Before(backups[i].BackupFinishedTime) {
+			return backups[i].DeepCopy(), nil
+		}
+	}
+
+	if pointInTime != nil {
+		return nil, fmt.Errorf("no completed backup found which finished at or before %s", pointInTime.UTC().Format(metav1.RFC3339Micro))
+	}
+	return nil, errors.New("no completed backup found")
+}
+
+// downloadAndValidateSnapshot downloads the given object to a temporary file and verifies that it is a
+// consistent etcd snapshot which can be restored by the etcd-launcher. Encrypted snapshots are decrypted
+// first, getDecryptionKeys is only called in that case.
+func downloadAndValidateSnapshot(log *zap.SugaredLogger, store storeuploader.BackupStore, objectName string, getDecryptionKeys func() (map[string][]byte, error)) (*kubermaticv1.EtcdRestoreSnapshotStatus, error) {
+	objects, err := store.List(objectName)
+	if err != nil {
+		return nil, fmt.Errorf("could not access backup object %s: %w", objectName, err)
+	}
+	var object *storeuploader.Object
+	for i := range objects {
+		if objects[i].Name == objectName {
+			object = &objects[i]
+			break
+		}
+	}
+	if object == nil {
+		return nil, &snapshotIntegrityError{err: fmt.Errorf("backup object %s does not exist", objectName)}
+	}
+
+	tmpDir, err := ioutil.TempDir("", "etcd-restore-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(tmpDir); err != nil {
+			log.Warnw("Failed to remove temporary snapshot directory", "directory", tmpDir, zap.Error(err))
+		}
+	}()
+
+	snapshotFile := fmt.Sprintf("%s/snapshot.db", tmpDir)
+	if err := store.Download(objectName, snapshotFile); err != nil {
+		return nil, fmt.Errorf("failed to download backup %s: %w", objectName, err)
+	}
+
+	keyID, err := decryptSnapshotFile(snapshotFile, getDecryptionKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := verifySnapshotFile(log, snapshotFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kubermaticv1.EtcdRestoreSnapshotStatus{
+		ObjectName:      objectName,
+		Revision:        status.Revision,
+		Size:            object.Size,
+		TotalKeys:       status.TotalKey,
+		Hash:            status.Hash,
+		EncryptionKeyID: keyID,
+		ValidationTime:  metav1.Now(),
+	}, nil
+}
+
+// decryptSnapshotFile decrypts the given snapshot in place if it is encrypted and returns the ID of the
+// key it was encrypted with. Unencrypted snapshots are left untouched and an empty key ID is returned.
+func decryptSnapshotFile(path string, getDecryptionKeys func() (map[string][]byte, error)) (string, error) {
+	encrypted, err := encryption.IsEncrypted(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	if !encrypted {
+		return "", nil
+	}
+
+	keys, err := getDecryptionKeys()
+	if err != nil {
+		return "", fmt.Errorf("failed to get backup decryption keys: %w", err)
+	}
+
+	header, err := encryption.DecryptFile(path, path, keys)
+	if err != nil {
+		return "", &snapshotIntegrityError{err: err}
+	}
+
+	return header.KeyID, nil
+}
+
+// verifySnapshotFile performs the same checks as `etcdctl snapshot status` and the sha256 check done by
+// `etcdctl snapshot restore`, without modifying the snapshot file.
+func verifySnapshotFile(log *zap.SugaredLogger, path string) (*snapshot.Status, error) {
+	if err := verifySnapshotChecksum(path); err != nil {
+		return nil, err
+	}
+
+	status, err := snapshot.NewV3(log.Desugar()).Status(path)
+	if err != nil {
+		return nil, &snapshotIntegrityError{err: err}
+	}
+	if status.Revision == 0 {
+		return nil, &snapshotIntegrityError{err: errors.New("snapshot does not contain any revision")}
+	}
+
+	return &status, nil
+}
+
+// verifySnapshotChecksum checks the sha256 checksum which etcd appends to snapshots saved via the
+// maintenance API. Snapshots without checksum are rejected, as the etcd-launcher refuses to restore them.
+func verifySnapshotChecksum(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	// 512 is the minimum disk sector size, which etcd uses to detect whether a checksum was appended
+	size := info.Size()
+	if size%512 != sha256.Size {
+		return &snapshotIntegrityError{err: errors.New("snapshot does not contain a sha256 checksum")}
+	}
+
+	h := sha256.New()
+	if _, err := io.CopyN(h, f, size-sha256.Size); err != nil {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	expected := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(f, expected); err != nil {
+		return fmt.Errorf("failed to read snapshot checksum: %w", err)
+	}
+
+	if actual := h.Sum(nil); !bytes.Equal(expected, actual) {
+		return &snapshotIntegrityError{err: fmt.Errorf("expected sha256 %x, got %x", expected, actual)}
+	}
+
+	return nil
+}
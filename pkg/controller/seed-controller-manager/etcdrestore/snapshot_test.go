@@ -0,0 +1,323 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdrestore
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.etcd.io/etcd/v3/mvcc/backend"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+	"k8c.io/kubermatic/v2/pkg/util/encryption"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func genBackupStatus(name string, finished time.Time, phase kubermaticv1.BackupStatusPhase) kubermaticv1.BackupStatus {
+	return kubermaticv1.BackupStatus{
+		BackupName:         name,
+		BackupPhase:        phase,
+		BackupFinishedTime: &metav1.Time{Time: finished},
+	}
+}
+
+func TestSelectBackup(t *testing.T) {
+	base := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	backupConfigs := []kubermaticv1.EtcdBackupConfig{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "daily"},
+			Spec: kubermaticv1.EtcdBackupConfigSpec{
+				Cluster: corev1.ObjectReference{Name: "testcluster"},
+			},
+			Status: kubermaticv1.EtcdBackupConfigStatus{
+				CurrentBackups: []kubermaticv1.BackupStatus{
+					genBackupStatus("daily-1", base, kubermaticv1.BackupStatusPhaseCompleted),
+					genBackupStatus("daily-2", base.Add(24*time.Hour), kubermaticv1.BackupStatusPhaseCompleted),
+					genBackupStatus("daily-3", base.Add(48*time.Hour), kubermaticv1.BackupStatusPhaseFailed),
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "hourly"},
+			Spec: kubermaticv1.EtcdBackupConfigSpec{
+				Cluster: corev1.ObjectReference{Name: "testcluster"},
+			},
+			Status: kubermaticv1.EtcdBackupConfigStatus{
+				CurrentBackups: []kubermaticv1.BackupStatus{
+					genBackupStatus("hourly-1", base.Add(23*time.Hour), kubermaticv1.BackupStatusPhaseCompleted),
+					genBackupStatus("hourly-2", base.Add(25*time.Hour), kubermaticv1.BackupStatusPhaseCompleted),
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "other"},
+			Spec: kubermaticv1.EtcdBackupConfigSpec{
+				Cluster: corev1.ObjectReference{Name: "othercluster"},
+			},
+			Status: kubermaticv1.EtcdBackupConfigStatus{
+				CurrentBackups: []kubermaticv1.BackupStatus{
+					genBackupStatus("other-1", base.Add(72*time.Hour), kubermaticv1.BackupStatusPhaseCompleted),
+				},
+			},
+		},
+	}
+	deleted := genBackupStatus("hourly-3", base.Add(26*time.Hour), kubermaticv1.BackupStatusPhaseCompleted)
+	deleted.DeletePhase = kubermaticv1.BackupStatusPhaseRunning
+	backupConfigs[1].Status.CurrentBackups = append(backupConfigs[1].Status.CurrentBackups, deleted)
+
+	testCases := []struct {
+		name           string
+		configName     string
+		pointInTime    *metav1.Time
+		expectedBackup string
+		expectedErr    bool
+	}{
+		{
+			name:           "latest backup of all configs",
+			expectedBackup: "hourly-2",
+		},
+		{
+			name:           "latest backup of a single config",
+			configName:     "daily",
+			expectedBackup: "daily-2",
+		},
+		{
+			name:           "latest backup before point in time",
+			pointInTime:    &metav1.Time{Time: base.Add(24*time.Hour + time.Minute)},
+			expectedBackup: "daily-2",
+		},
+		{
+			name:           "backup finished exactly at point in time",
+			pointInTime:    &metav1.Time{Time: base.Add(23 * time.Hour)},
+			expectedBackup: "hourly-1",
+		},
+		{
+			name:        "no backup before point in time",
+			pointInTime: &metav1.Time{Time: base.Add(-time.Hour)},
+			expectedErr: true,
+		},
+		{
+			name:        "unknown config",
+			configName:  "weekly",
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			backup, err := selectBackup(availableBackups(backupConfigs, "testcluster", tc.configName), tc.pointInTime)
+			if tc.expectedErr {
+				if err == nil {
+					t.Fatalf("expected error, but got backup %q", backup.BackupName)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if backup.BackupName != tc.expectedBackup {
+				t.Errorf("expected backup %q, got %q", tc.expectedBackup, backup.BackupName)
+			}
+		})
+	}
+}
+
+// writeTestSnapshot creates a minimal etcd database containing a single revision and appends
+// a sha256 checksum the same way the etcd maintenance API does.
+func writeTestSnapshot(t *testing.T, dir string, revision int64) string {
+	path := filepath.Join(dir, "snapshot.db")
+
+	be := backend.NewDefaultBackend(path)
+	tx := be.BatchTx()
+	tx.Lock()
+	tx.UnsafeCreateBucket([]byte("key"))
+	// revision keys are encoded as <8 byte main revision>_<8 byte sub revision>
+	key := make([]byte, 17)
+	binary.BigEndian.PutUint64(key, uint64(revision))
+	key[8] = '_'
+	tx.UnsafePut([]byte("key"), key, []byte("value"))
+	tx.Unlock()
+	if err := be.Close(); err != nil {
+		t.Fatalf("failed to close database: %v", err)
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read database: %v", err)
+	}
+	checksum := sha256.Sum256(content)
+	if err := ioutil.WriteFile(path, append(content, checksum[:]...), 0600); err != nil {
+		t.Fatalf("failed to write snapshot: %v", err)
+	}
+
+	return path
+}
+
+func TestVerifySnapshotFile(t *testing.T) {
+	log := kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar()
+
+	testCases := []struct {
+		name                 string
+		modify               func(t *testing.T, path string)
+		expectedIntegrityErr bool
+	}{
+		{
+			name:   "valid snapshot",
+			modify: func(t *testing.T, path string) {},
+		},
+		{
+			name: "corrupt content",
+			modify: func(t *testing.T, path string) {
+				f, err := os.OpenFile(path, os.O_WRONLY, 0600)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer f.Close()
+				if _, err := f.WriteAt([]byte("corrupt"), 4096); err != nil {
+					t.Fatal(err)
+				}
+			},
+			expectedIntegrityErr: true,
+		},
+		{
+			name: "missing checksum",
+			modify: func(t *testing.T, path string) {
+				info, err := os.Stat(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.Truncate(path, info.Size()-sha256.Size); err != nil {
+					t.Fatal(err)
+				}
+			},
+			expectedIntegrityErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "snapshot-test-")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			path := writeTestSnapshot(t, dir, 42)
+			tc.modify(t, path)
+
+			status, err := verifySnapshotFile(log, path)
+			if tc.expectedIntegrityErr {
+				var integrityErr *snapshotIntegrityError
+				if !errors.As(err, &integrityErr) {
+					t.Fatalf("expected integrity error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if status.Revision != 42 {
+				t.Errorf("expected revision 42, got %d", status.Revision)
+			}
+			if status.TotalKey != 1 {
+				t.Errorf("expected 1 key, got %d", status.TotalKey)
+			}
+		})
+	}
+}
+
+func TestDecryptSnapshotFile(t *testing.T) {
+	log := kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar()
+	key := make([]byte, 32)
+
+	testCases := []struct {
+		name                 string
+		encryptWithKeyID     string
+		keys                 map[string][]byte
+		expectedKeyID        string
+		expectedIntegrityErr bool
+	}{
+		{
+			name: "unencrypted snapshot is left untouched",
+		},
+		{
+			name:             "encrypted snapshot is decrypted",
+			encryptWithKeyID: "old",
+			keys:             map[string][]byte{"old": key, "new": make([]byte, 16)},
+			expectedKeyID:    "old",
+		},
+		{
+			name:                 "missing key",
+			encryptWithKeyID:     "old",
+			keys:                 map[string][]byte{"new": key},
+			expectedIntegrityErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "snapshot-test-")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			path := writeTestSnapshot(t, dir, 42)
+			if tc.encryptWithKeyID != "" {
+				if err := encryption.EncryptFile(path, tc.encryptWithKeyID, key); err != nil {
+					t.Fatalf("failed to encrypt snapshot: %v", err)
+				}
+			}
+
+			getKeys := func() (map[string][]byte, error) {
+				if tc.keys == nil {
+					t.Error("keys must only be requested for encrypted snapshots")
+				}
+				return tc.keys, nil
+			}
+
+			keyID, err := decryptSnapshotFile(path, getKeys)
+			if tc.expectedIntegrityErr {
+				var integrityErr *snapshotIntegrityError
+				if !errors.As(err, &integrityErr) {
+					t.Fatalf("expected integrity error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if keyID != tc.expectedKeyID {
+				t.Errorf("expected key ID %q, got %q", tc.expectedKeyID, keyID)
+			}
+
+			if _, err := verifySnapshotFile(log, path); err != nil {
+				t.Errorf("decrypted snapshot failed verification: %v", err)
+			}
+		})
+	}
+}
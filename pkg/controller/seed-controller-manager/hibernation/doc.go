@@ -0,0 +1,31 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package hibernation contains a controller that hibernates and resumes clusters.
+
+A cluster is hibernated by setting spec.hibernation.hibernated, either directly or through the
+hibernation schedule of the cluster, which changes it at each of its activations. The hibernation
+passes through phases which are recorded in the cluster status and reported by the Hibernated
+condition: if requested, a one-off etcd snapshot is taken; then the MachineDeployments are scaled
+to zero and the controller waits for their machines to be deleted; finally the Deployments and
+StatefulSets in the cluster namespace are scaled to zero. The replicas of every scaled object are
+recorded in an annotation, resuming the cluster restores them in the opposite order.
+
+While the control plane is scaled down, the other seed controllers leave the cluster alone. Deleting
+a hibernated cluster resumes its control plane, so that the cluster can be cleaned up.
+*/
+package hibernation
@@ -0,0 +1,221 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hibernation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"k8c.io/kubermatic/v2/pkg/cluster/client"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1/helper"
+	"k8c.io/kubermatic/v2/pkg/util/hibernationschedule"
+	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	ControllerName = "kubermatic_hibernation_controller"
+
+	// phaseRequeueInterval is the interval in which the progress of the current phase is checked
+	phaseRequeueInterval = 30 * time.Second
+)
+
+// UserClusterClientProvider provides clients for user clusters
+type UserClusterClientProvider interface {
+	GetClient(ctx context.Context, c *kubermaticv1.Cluster, options ...client.ConfigOption) (ctrlruntimeclient.Client, error)
+}
+
+type Reconciler struct {
+	ctrlruntimeclient.Client
+
+	log                       *zap.SugaredLogger
+	workerName                string
+	recorder                  record.EventRecorder
+	userClusterClientProvider UserClusterClientProvider
+	versions                  kubermatic.Versions
+	// etcdSnapshots is whether the etcd backup and restore controllers, which take the snapshots, are enabled
+	etcdSnapshots bool
+
+	now func() time.Time
+}
+
+// Add creates a new hibernation controller
+func Add(mgr manager.Manager, log *zap.SugaredLogger, numWorkers int, workerName string,
+	userClusterClientProvider UserClusterClientProvider, etcdSnapshots bool, versions kubermatic.Versions) error {
+	reconciler := &Reconciler{
+		Client: mgr.GetClient(),
+
+		log:                       log.Named(ControllerName),
+		workerName:                workerName,
+		recorder:                  mgr.GetEventRecorderFor(ControllerName),
+		userClusterClientProvider: userClusterClientProvider,
+		versions:                  versions,
+		etcdSnapshots:             etcdSnapshots,
+		now:                       time.Now,
+	}
+
+	c, err := controller.New(ControllerName, mgr, controller.Options{
+		Reconciler:              reconciler,
+		MaxConcurrentReconciles: numWorkers,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create controller: %v", err)
+	}
+
+	if err := c.Watch(&source.Kind{Type: &kubermaticv1.Cluster{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return fmt.Errorf("failed to create watch: %v", err)
+	}
+
+	return nil
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	log := r.log.With("request", request)
+	log.Debug("Processing")
+
+	cluster := &kubermaticv1.Cluster{}
+	if err := r.Get(ctx, request.NamespacedName, cluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	// The ClusterReconcileWrapper is not used, as it skips clusters whose control plane is hibernated
+	if cluster.Labels[kubermaticv1.WorkerNameLabelKey] != r.workerName || cluster.Spec.Pause {
+		return reconcile.Result{}, nil
+	}
+	// The cluster has not been set up yet
+	if cluster.Status.NamespaceName == "" {
+		return reconcile.Result{}, nil
+	}
+
+	result, err := r.reconcile(ctx, log.With("cluster", cluster.Name), cluster)
+	if err != nil {
+		log.Errorw("Reconciling failed", zap.Error(err))
+		r.recorder.Event(cluster, corev1.EventTypeWarning, "ReconcilingError", err.Error())
+	}
+	return result, err
+}
+
+func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) (reconcile.Result, error) {
+	nextActivation, err := r.applySchedule(ctx, log, cluster)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to apply the hibernation schedule: %v", err)
+	}
+
+	finished, err := r.reconcilePhases(ctx, log, cluster)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	requeueAfter := nextActivation
+	if !finished && (requeueAfter == 0 || requeueAfter > phaseRequeueInterval) {
+		requeueAfter = phaseRequeueInterval
+	}
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// applySchedule changes spec.hibernation.hibernated if the schedule of the cluster was activated since it
+// was checked last. It returns the duration until the next activation, or zero if there is no schedule.
+func (r *Reconciler) applySchedule(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) (time.Duration, error) {
+	schedule, err := hibernationschedule.ForCluster(cluster)
+	if err != nil {
+		return 0, err
+	}
+
+	oldCluster := cluster.DeepCopy()
+	now := r.now()
+
+	if schedule == nil {
+		// Forget when the last schedule was checked, so that a new schedule is only applied from now on
+		if cluster.Status.Hibernation != nil && cluster.Status.Hibernation.ScheduleCheckedTime != nil {
+			cluster.Status.Hibernation.ScheduleCheckedTime = nil
+			if err := r.patchCluster(ctx, cluster, oldCluster); err != nil {
+				return 0, err
+			}
+		}
+		return 0, nil
+	}
+
+	if cluster.Status.Hibernation == nil {
+		cluster.Status.Hibernation = &kubermaticv1.ClusterHibernationStatus{}
+	}
+	status := cluster.Status.Hibernation
+
+	if status.ScheduleCheckedTime == nil {
+		status.ScheduleCheckedTime = &metav1.Time{Time: now}
+	} else if due, hibernate := schedule.Due(status.ScheduleCheckedTime.Time, now); due {
+		// The checked time is only updated if the schedule was activated, as every update
+		// of the cluster triggers another reconciliation
+		status.ScheduleCheckedTime = &metav1.Time{Time: now}
+		if hibernate != cluster.Spec.Hibernation.Hibernated {
+			cluster.Spec.Hibernation.Hibernated = hibernate
+			if hibernate {
+				log.Info("Hibernating cluster on schedule")
+				r.recorder.Event(cluster, corev1.EventTypeNormal, "ScheduledHibernation", "Hibernating the cluster according to its hibernation schedule")
+			} else {
+				log.Info("Resuming cluster on schedule")
+				r.recorder.Event(cluster, corev1.EventTypeNormal, "ScheduledResumption", "Resuming the cluster according to its hibernation schedule")
+			}
+		}
+	}
+
+	if err := r.patchCluster(ctx, cluster, oldCluster); err != nil {
+		return 0, err
+	}
+
+	next := nextActivation(schedule, now)
+	if next.IsZero() {
+		return 0, nil
+	}
+	return next.Sub(now), nil
+}
+
+// nextActivation returns the earliest activation of the schedule after now, or a zero time.
+func nextActivation(schedule *hibernationschedule.Schedule, now time.Time) time.Time {
+	hibernate, resume := schedule.Next(now)
+	if hibernate.IsZero() || (!resume.IsZero() && resume.Before(hibernate)) {
+		return resume
+	}
+	return hibernate
+}
+
+// setHibernatedCondition reports the current phase in the Hibernated condition of the cluster. The
+// cluster is patched by the caller.
+func (r *Reconciler) setHibernatedCondition(cluster *kubermaticv1.Cluster) {
+	status := cluster.Status.Hibernation
+	conditionStatus := corev1.ConditionFalse
+	if status.Phase == kubermaticv1.ClusterHibernationPhaseHibernated {
+		conditionStatus = corev1.ConditionTrue
+	}
+	kubermaticv1helper.SetClusterCondition(cluster, r.versions, kubermaticv1.ClusterConditionHibernated, conditionStatus, string(status.Phase), status.Message)
+}
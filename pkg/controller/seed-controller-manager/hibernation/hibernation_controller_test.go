@@ -0,0 +1,404 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hibernation
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+	clusterclient "k8c.io/kubermatic/v2/pkg/cluster/client"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1/helper"
+	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func init() {
+	if err := clusterv1alpha1.SchemeBuilder.AddToScheme(scheme.Scheme); err != nil {
+		panic(fmt.Sprintf("failed to add clusterv1alpha1 to scheme: %v", err))
+	}
+}
+
+type fakeUserClusterClientProvider struct {
+	client ctrlruntimeclient.Client
+}
+
+func (p *fakeUserClusterClientProvider) GetClient(_ context.Context, _ *kubermaticv1.Cluster, _ ...clusterclient.ConfigOption) (ctrlruntimeclient.Client, error) {
+	return p.client, nil
+}
+
+func TestReconcile(t *testing.T) {
+	// 2021-06-01 is a Tuesday
+	now := time.Date(2021, 6, 1, 19, 0, 0, 0, time.UTC)
+
+	cluster := func(hibernated bool, phase kubermaticv1.ClusterHibernationPhase, apiserver kubermaticv1.HealthStatus) *kubermaticv1.Cluster {
+		c := &kubermaticv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Spec: kubermaticv1.ClusterSpec{
+				Hibernation: &kubermaticv1.HibernationSettings{Hibernated: hibernated},
+			},
+			Status: kubermaticv1.ClusterStatus{
+				NamespaceName:  "cluster-test",
+				ExtendedHealth: kubermaticv1.ExtendedClusterHealth{Apiserver: apiserver, Etcd: apiserver},
+			},
+		}
+		if phase != "" {
+			c.Status.Hibernation = &kubermaticv1.ClusterHibernationStatus{Phase: phase}
+		}
+		return c
+	}
+	annotated := func(replicas string) map[string]string {
+		if replicas == "" {
+			return nil
+		}
+		return map[string]string{kubermaticv1.HibernationReplicasAnnotation: replicas}
+	}
+	machineDeployment := func(replicas, remaining, available int32, recorded string) *clusterv1alpha1.MachineDeployment {
+		return &clusterv1alpha1.MachineDeployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "workers", Namespace: metav1.NamespaceSystem, Annotations: annotated(recorded)},
+			Spec:       clusterv1alpha1.MachineDeploymentSpec{Replicas: pointer.Int32Ptr(replicas)},
+			Status:     clusterv1alpha1.MachineDeploymentStatus{Replicas: remaining, AvailableReplicas: available},
+		}
+	}
+	apiserver := func(replicas, remaining int32, recorded string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: resources.ApiserverDeploymentName, Namespace: "cluster-test", Annotations: annotated(recorded)},
+			Spec:       appsv1.DeploymentSpec{Replicas: pointer.Int32Ptr(replicas)},
+			Status:     appsv1.DeploymentStatus{Replicas: remaining},
+		}
+	}
+	etcd := func(replicas, remaining int32, recorded string) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: resources.EtcdStatefulSetName, Namespace: "cluster-test", Annotations: annotated(recorded)},
+			Spec:       appsv1.StatefulSetSpec{Replicas: pointer.Int32Ptr(replicas)},
+			Status:     appsv1.StatefulSetStatus{Replicas: remaining},
+		}
+	}
+
+	testCases := []struct {
+		name                  string
+		cluster               *kubermaticv1.Cluster
+		seedObjects           []ctrlruntimeclient.Object
+		userObjects           []ctrlruntimeclient.Object
+		expectedHibernated    bool
+		expectedPhase         kubermaticv1.ClusterHibernationPhase
+		expectedMessage       string
+		expectedCondition     corev1.ConditionStatus
+		expectedMDReplicas    int32
+		expectedMDRecorded    string
+		expectedAPIReplicas   int32
+		expectedAPIRecorded   string
+		expectedEtcdReplicas  int32
+		expectedEtcdRecorded  string
+		expectedSnapshotCount int
+	}{
+		{
+			name:                 "clusters which were never hibernated are left alone",
+			cluster:              cluster(false, "", kubermaticv1.HealthStatusUp),
+			seedObjects:          []ctrlruntimeclient.Object{apiserver(2, 2, ""), etcd(3, 3, "")},
+			userObjects:          []ctrlruntimeclient.Object{machineDeployment(3, 3, 3, "")},
+			expectedMDReplicas:   3,
+			expectedAPIReplicas:  2,
+			expectedEtcdReplicas: 3,
+		},
+		{
+			name:                 "the hibernation waits for the machines to be deleted",
+			cluster:              cluster(true, "", kubermaticv1.HealthStatusUp),
+			seedObjects:          []ctrlruntimeclient.Object{apiserver(2, 2, ""), etcd(3, 3, "")},
+			userObjects:          []ctrlruntimeclient.Object{machineDeployment(3, 3, 3, "")},
+			expectedHibernated:   true,
+			expectedPhase:        kubermaticv1.ClusterHibernationPhaseScalingDownMachineDeployments,
+			expectedMessage:      "MachineDeployment workers: 3 machines remaining",
+			expectedCondition:    corev1.ConditionFalse,
+			expectedMDReplicas:   0,
+			expectedMDRecorded:   "3",
+			expectedAPIReplicas:  2,
+			expectedEtcdReplicas: 3,
+		},
+		{
+			name:                 "the hibernation waits for the control plane pods to be deleted",
+			cluster:              cluster(true, kubermaticv1.ClusterHibernationPhaseScalingDownMachineDeployments, kubermaticv1.HealthStatusUp),
+			seedObjects:          []ctrlruntimeclient.Object{apiserver(2, 2, ""), etcd(3, 3, "")},
+			userObjects:          []ctrlruntimeclient.Object{machineDeployment(0, 0, 0, "3")},
+			expectedHibernated:   true,
+			expectedPhase:        kubermaticv1.ClusterHibernationPhaseScalingDownControlPlane,
+			expectedMessage:      "Deployment apiserver: 2 pods remaining; StatefulSet etcd: 3 pods remaining",
+			expectedCondition:    corev1.ConditionFalse,
+			expectedMDRecorded:   "3",
+			expectedAPIRecorded:  "2",
+			expectedEtcdRecorded: "3",
+		},
+		{
+			name:                 "the cluster is hibernated",
+			cluster:              cluster(true, kubermaticv1.ClusterHibernationPhaseScalingDownControlPlane, kubermaticv1.HealthStatusDown),
+			seedObjects:          []ctrlruntimeclient.Object{apiserver(0, 0, "2"), etcd(0, 0, "3")},
+			userObjects:          []ctrlruntimeclient.Object{machineDeployment(0, 0, 0, "3")},
+			expectedHibernated:   true,
+			expectedPhase:        kubermaticv1.ClusterHibernationPhaseHibernated,
+			expectedCondition:    corev1.ConditionTrue,
+			expectedMDRecorded:   "3",
+			expectedAPIRecorded:  "2",
+			expectedEtcdRecorded: "3",
+		},
+		{
+			name:                 "the resumption restores the control plane and waits for the apiserver",
+			cluster:              cluster(false, kubermaticv1.ClusterHibernationPhaseHibernated, kubermaticv1.HealthStatusDown),
+			seedObjects:          []ctrlruntimeclient.Object{apiserver(0, 0, "2"), etcd(0, 0, "3")},
+			userObjects:          []ctrlruntimeclient.Object{machineDeployment(0, 0, 0, "3")},
+			expectedPhase:        kubermaticv1.ClusterHibernationPhaseResumingControlPlane,
+			expectedMessage:      "Waiting for the apiserver to be healthy",
+			expectedCondition:    corev1.ConditionFalse,
+			expectedMDRecorded:   "3",
+			expectedAPIReplicas:  2,
+			expectedEtcdReplicas: 3,
+		},
+		{
+			name:                 "the resumption restores the machine deployments and waits for them",
+			cluster:              cluster(false, kubermaticv1.ClusterHibernationPhaseResumingControlPlane, kubermaticv1.HealthStatusUp),
+			seedObjects:          []ctrlruntimeclient.Object{apiserver(2, 2, ""), etcd(3, 3, "")},
+			userObjects:          []ctrlruntimeclient.Object{machineDeployment(0, 0, 0, "3")},
+			expectedPhase:        kubermaticv1.ClusterHibernationPhaseResumingMachineDeployments,
+			expectedMessage:      "MachineDeployment workers: 0 of 3 replicas available",
+			expectedCondition:    corev1.ConditionFalse,
+			expectedMDReplicas:   3,
+			expectedAPIReplicas:  2,
+			expectedEtcdReplicas: 3,
+		},
+		{
+			name:                 "the cluster is resumed",
+			cluster:              cluster(false, kubermaticv1.ClusterHibernationPhaseResumingMachineDeployments, kubermaticv1.HealthStatusUp),
+			seedObjects:          []ctrlruntimeclient.Object{apiserver(2, 2, ""), etcd(3, 3, "")},
+			userObjects:          []ctrlruntimeclient.Object{machineDeployment(3, 3, 3, "")},
+			expectedPhase:        kubermaticv1.ClusterHibernationPhaseRunning,
+			expectedCondition:    corev1.ConditionFalse,
+			expectedMDReplicas:   3,
+			expectedAPIReplicas:  2,
+			expectedEtcdReplicas: 3,
+		},
+		{
+			name: "a hibernated cluster which is being deleted is resumed",
+			cluster: func() *kubermaticv1.Cluster {
+				c := cluster(true, kubermaticv1.ClusterHibernationPhaseHibernated, kubermaticv1.HealthStatusDown)
+				c.DeletionTimestamp = &metav1.Time{Time: now}
+				c.Finalizers = []string{"test"}
+				return c
+			}(),
+			seedObjects:          []ctrlruntimeclient.Object{apiserver(0, 0, "2"), etcd(0, 0, "3")},
+			userObjects:          []ctrlruntimeclient.Object{machineDeployment(0, 0, 0, "3")},
+			expectedHibernated:   true,
+			expectedPhase:        kubermaticv1.ClusterHibernationPhaseResumingControlPlane,
+			expectedMessage:      "Waiting for the apiserver to be healthy",
+			expectedCondition:    corev1.ConditionFalse,
+			expectedMDRecorded:   "3",
+			expectedAPIReplicas:  2,
+			expectedEtcdReplicas: 3,
+		},
+		{
+			name: "the etcd snapshot is taken first",
+			cluster: func() *kubermaticv1.Cluster {
+				c := cluster(true, "", kubermaticv1.HealthStatusUp)
+				c.Spec.Hibernation.EtcdSnapshot = true
+				return c
+			}(),
+			seedObjects:           []ctrlruntimeclient.Object{apiserver(2, 2, ""), etcd(3, 3, "")},
+			userObjects:           []ctrlruntimeclient.Object{machineDeployment(3, 3, 3, "")},
+			expectedHibernated:    true,
+			expectedPhase:         kubermaticv1.ClusterHibernationPhaseEtcdSnapshot,
+			expectedMessage:       "Waiting for the etcd snapshot to be taken",
+			expectedCondition:     corev1.ConditionFalse,
+			expectedMDReplicas:    3,
+			expectedAPIReplicas:   2,
+			expectedEtcdReplicas:  3,
+			expectedSnapshotCount: 1,
+		},
+		{
+			name: "the schedule hibernates the cluster",
+			cluster: func() *kubermaticv1.Cluster {
+				c := cluster(false, "", kubermaticv1.HealthStatusUp)
+				c.Spec.Hibernation.Schedule = &kubermaticv1.HibernationSchedule{Hibernate: "0 20 * * *", Resume: "0 7 * * 1-5", TimeZone: "Europe/Berlin"}
+				c.Status.Hibernation = &kubermaticv1.ClusterHibernationStatus{
+					ScheduleCheckedTime: &metav1.Time{Time: now.Add(-2 * time.Hour)},
+				}
+				return c
+			}(),
+			seedObjects:          []ctrlruntimeclient.Object{apiserver(2, 2, ""), etcd(3, 3, "")},
+			userObjects:          []ctrlruntimeclient.Object{machineDeployment(3, 0, 0, "")},
+			expectedHibernated:   true,
+			expectedPhase:        kubermaticv1.ClusterHibernationPhaseScalingDownControlPlane,
+			expectedMessage:      "Deployment apiserver: 2 pods remaining; StatefulSet etcd: 3 pods remaining",
+			expectedCondition:    corev1.ConditionFalse,
+			expectedMDRecorded:   "3",
+			expectedAPIRecorded:  "2",
+			expectedEtcdRecorded: "3",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			seedClient := ctrlruntimefakeclient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(append(tc.seedObjects, tc.cluster)...).Build()
+			userClusterClient := ctrlruntimefakeclient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(tc.userObjects...).Build()
+
+			r := &Reconciler{
+				Client:                    seedClient,
+				log:                       kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
+				recorder:                  record.NewFakeRecorder(10),
+				userClusterClientProvider: &fakeUserClusterClientProvider{client: userClusterClient},
+				versions:                  kubermatic.NewFakeVersions(),
+				etcdSnapshots:             true,
+				now:                       func() time.Time { return now },
+			}
+
+			cluster := &kubermaticv1.Cluster{}
+			if err := seedClient.Get(ctx, types.NamespacedName{Name: tc.cluster.Name}, cluster); err != nil {
+				t.Fatalf("failed to get cluster: %v", err)
+			}
+			if _, err := r.reconcile(ctx, r.log, cluster); err != nil {
+				t.Fatalf("failed to reconcile: %v", err)
+			}
+
+			if err := seedClient.Get(ctx, types.NamespacedName{Name: tc.cluster.Name}, cluster); err != nil {
+				t.Fatalf("failed to get cluster: %v", err)
+			}
+			if cluster.Spec.Hibernation.Hibernated != tc.expectedHibernated {
+				t.Errorf("expected hibernated %v, got %v", tc.expectedHibernated, cluster.Spec.Hibernation.Hibernated)
+			}
+			var phase kubermaticv1.ClusterHibernationPhase
+			var message string
+			if cluster.Status.Hibernation != nil {
+				phase, message = cluster.Status.Hibernation.Phase, cluster.Status.Hibernation.Message
+			}
+			if phase != tc.expectedPhase {
+				t.Errorf("expected phase %q, got %q", tc.expectedPhase, phase)
+			}
+			if message != tc.expectedMessage {
+				t.Errorf("expected message %q, got %q", tc.expectedMessage, message)
+			}
+			var condition corev1.ConditionStatus
+			if _, cond := kubermaticv1helper.GetClusterCondition(cluster, kubermaticv1.ClusterConditionHibernated); cond != nil {
+				condition = cond.Status
+			}
+			if condition != tc.expectedCondition {
+				t.Errorf("expected Hibernated condition %q, got %q", tc.expectedCondition, condition)
+			}
+
+			md := &clusterv1alpha1.MachineDeployment{}
+			if err := userClusterClient.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: "workers"}, md); err != nil {
+				t.Fatalf("failed to get MachineDeployment: %v", err)
+			}
+			checkReplicas(t, "MachineDeployment", md.ObjectMeta, *md.Spec.Replicas, tc.expectedMDReplicas, tc.expectedMDRecorded)
+
+			deployment := &appsv1.Deployment{}
+			if err := seedClient.Get(ctx, types.NamespacedName{Namespace: "cluster-test", Name: resources.ApiserverDeploymentName}, deployment); err != nil {
+				t.Fatalf("failed to get Deployment: %v", err)
+			}
+			checkReplicas(t, "Deployment", deployment.ObjectMeta, *deployment.Spec.Replicas, tc.expectedAPIReplicas, tc.expectedAPIRecorded)
+
+			statefulSet := &appsv1.StatefulSet{}
+			if err := seedClient.Get(ctx, types.NamespacedName{Namespace: "cluster-test", Name: resources.EtcdStatefulSetName}, statefulSet); err != nil {
+				t.Fatalf("failed to get StatefulSet: %v", err)
+			}
+			checkReplicas(t, "StatefulSet", statefulSet.ObjectMeta, *statefulSet.Spec.Replicas, tc.expectedEtcdReplicas, tc.expectedEtcdRecorded)
+
+			snapshots := &kubermaticv1.EtcdBackupConfigList{}
+			if err := seedClient.List(ctx, snapshots, ctrlruntimeclient.MatchingLabels{etcdSnapshotLabelKey: "true"}); err != nil {
+				t.Fatalf("failed to list EtcdBackupConfigs: %v", err)
+			}
+			if len(snapshots.Items) != tc.expectedSnapshotCount {
+				t.Errorf("expected %d etcd snapshots, got %d", tc.expectedSnapshotCount, len(snapshots.Items))
+			}
+		})
+	}
+}
+
+func checkReplicas(t *testing.T, kind string, meta metav1.ObjectMeta, replicas, expectedReplicas int32, expectedRecorded string) {
+	if replicas != expectedReplicas {
+		t.Errorf("expected %s to have %d replicas, got %d", kind, expectedReplicas, replicas)
+	}
+	if recorded := meta.Annotations[kubermaticv1.HibernationReplicasAnnotation]; recorded != expectedRecorded {
+		t.Errorf("expected %s to have recorded replicas %q, got %q", kind, expectedRecorded, recorded)
+	}
+}
+
+func TestEtcdSnapshotCompleted(t *testing.T) {
+	ctx := context.Background()
+	phaseStart := time.Date(2021, 6, 1, 18, 0, 0, 0, time.UTC)
+
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: kubermaticv1.ClusterSpec{
+			Hibernation: &kubermaticv1.HibernationSettings{Hibernated: true, EtcdSnapshot: true},
+		},
+		Status: kubermaticv1.ClusterStatus{
+			NamespaceName: "cluster-test",
+			Hibernation: &kubermaticv1.ClusterHibernationStatus{
+				Phase:          kubermaticv1.ClusterHibernationPhaseEtcdSnapshot,
+				PhaseStartTime: metav1.NewTime(phaseStart),
+			},
+		},
+	}
+	snapshot := func(name string, phase kubermaticv1.BackupStatusPhase) *kubermaticv1.EtcdBackupConfig {
+		return &kubermaticv1.EtcdBackupConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "cluster-test", Labels: map[string]string{etcdSnapshotLabelKey: "true"}},
+			Status: kubermaticv1.EtcdBackupConfigStatus{
+				CurrentBackups: []kubermaticv1.BackupStatus{{BackupPhase: phase}},
+			},
+		}
+	}
+	current := fmt.Sprintf("hibernation-%d", phaseStart.Unix())
+
+	seedClient := ctrlruntimefakeclient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		cluster,
+		snapshot(current, kubermaticv1.BackupStatusPhaseCompleted),
+		snapshot("hibernation-1", kubermaticv1.BackupStatusPhaseCompleted),
+	).Build()
+	r := &Reconciler{
+		Client:        seedClient,
+		recorder:      record.NewFakeRecorder(10),
+		etcdSnapshots: true,
+	}
+
+	finished, message, err := r.takeEtcdSnapshot(ctx, kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(), cluster)
+	if err != nil {
+		t.Fatalf("failed to take the etcd snapshot: %v", err)
+	}
+	if !finished {
+		t.Fatalf("expected the etcd snapshot to be finished, got %q", message)
+	}
+
+	snapshots := &kubermaticv1.EtcdBackupConfigList{}
+	if err := seedClient.List(ctx, snapshots); err != nil {
+		t.Fatalf("failed to list EtcdBackupConfigs: %v", err)
+	}
+	if len(snapshots.Items) != 1 || snapshots.Items[0].Name != current {
+		t.Errorf("expected only the snapshot %s to be kept, got %v", current, snapshots.Items)
+	}
+}
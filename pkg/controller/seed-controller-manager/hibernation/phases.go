@@ -0,0 +1,467 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hibernation
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// etcdSnapshotLabelKey marks the EtcdBackupConfigs of the hibernation snapshots
+	etcdSnapshotLabelKey = "kubermatic.io/hibernation-snapshot"
+)
+
+// nextPhases maps each phase of the hibernation and the resumption to the phase following it
+var nextPhases = map[kubermaticv1.ClusterHibernationPhase]kubermaticv1.ClusterHibernationPhase{
+	kubermaticv1.ClusterHibernationPhaseEtcdSnapshot:                  kubermaticv1.ClusterHibernationPhaseScalingDownMachineDeployments,
+	kubermaticv1.ClusterHibernationPhaseScalingDownMachineDeployments: kubermaticv1.ClusterHibernationPhaseScalingDownControlPlane,
+	kubermaticv1.ClusterHibernationPhaseScalingDownControlPlane:       kubermaticv1.ClusterHibernationPhaseHibernated,
+	kubermaticv1.ClusterHibernationPhaseResumingControlPlane:          kubermaticv1.ClusterHibernationPhaseResumingMachineDeployments,
+	kubermaticv1.ClusterHibernationPhaseResumingMachineDeployments:    kubermaticv1.ClusterHibernationPhaseRunning,
+}
+
+// startPhase returns the phase in which the hibernation or resumption continues from the given phase. If the
+// cluster is resumed while it is being hibernated or the other way around, it continues in the opposite phase.
+func startPhase(hibernate bool, phase kubermaticv1.ClusterHibernationPhase) kubermaticv1.ClusterHibernationPhase {
+	if hibernate {
+		switch phase {
+		case "", kubermaticv1.ClusterHibernationPhaseRunning:
+			return kubermaticv1.ClusterHibernationPhaseEtcdSnapshot
+		case kubermaticv1.ClusterHibernationPhaseResumingMachineDeployments:
+			return kubermaticv1.ClusterHibernationPhaseScalingDownMachineDeployments
+		case kubermaticv1.ClusterHibernationPhaseResumingControlPlane:
+			return kubermaticv1.ClusterHibernationPhaseScalingDownControlPlane
+		}
+		return phase
+	}
+
+	switch phase {
+	case "", kubermaticv1.ClusterHibernationPhaseEtcdSnapshot:
+		return kubermaticv1.ClusterHibernationPhaseRunning
+	case kubermaticv1.ClusterHibernationPhaseScalingDownMachineDeployments:
+		return kubermaticv1.ClusterHibernationPhaseResumingMachineDeployments
+	case kubermaticv1.ClusterHibernationPhaseScalingDownControlPlane, kubermaticv1.ClusterHibernationPhaseHibernated:
+		return kubermaticv1.ClusterHibernationPhaseResumingControlPlane
+	}
+	return phase
+}
+
+// reconcilePhases moves the cluster through the phases of the hibernation or resumption until a phase has
+// not finished yet. It returns whether the cluster reached the Hibernated or Running phase.
+func (r *Reconciler) reconcilePhases(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) (bool, error) {
+	// A cluster which is being deleted is resumed, so that its resources in the user cluster can be cleaned up
+	hibernate := cluster.Spec.Hibernation != nil && cluster.Spec.Hibernation.Hibernated && cluster.DeletionTimestamp == nil
+
+	var phase kubermaticv1.ClusterHibernationPhase
+	if cluster.Status.Hibernation != nil {
+		phase = cluster.Status.Hibernation.Phase
+	}
+	// Clusters which were never hibernated get no hibernation status
+	if !hibernate && phase == "" {
+		return true, nil
+	}
+	phase = startPhase(hibernate, phase)
+
+	for {
+		oldCluster := cluster.DeepCopy()
+		if cluster.Status.Hibernation == nil {
+			cluster.Status.Hibernation = &kubermaticv1.ClusterHibernationStatus{}
+		}
+		status := cluster.Status.Hibernation
+
+		if status.Phase != phase {
+			log.Infow("Entering hibernation phase", "phase", phase)
+			status.Phase = phase
+			status.PhaseStartTime = metav1.NewTime(r.now())
+			status.Message = ""
+			if phase == kubermaticv1.ClusterHibernationPhaseScalingDownControlPlane {
+				// The health is not synchronized while the control plane is hibernated
+				setControlPlaneHealth(cluster, kubermaticv1.HealthStatusDown)
+			}
+			r.setHibernatedCondition(cluster)
+			// The phase is recorded before it is started, so that the other controllers stop
+			// reconciling the control plane before it is scaled down
+			if err := r.patchCluster(ctx, cluster, oldCluster); err != nil {
+				return false, err
+			}
+
+			switch phase {
+			case kubermaticv1.ClusterHibernationPhaseHibernated:
+				r.recorder.Event(cluster, corev1.EventTypeNormal, "Hibernated", "The cluster was hibernated")
+				return true, nil
+			case kubermaticv1.ClusterHibernationPhaseRunning:
+				r.recorder.Event(cluster, corev1.EventTypeNormal, "Resumed", "The cluster was resumed")
+				return true, nil
+			}
+			oldCluster = cluster.DeepCopy()
+		}
+
+		if phase == kubermaticv1.ClusterHibernationPhaseHibernated || phase == kubermaticv1.ClusterHibernationPhaseRunning {
+			return true, nil
+		}
+
+		finished, message, err := r.runPhase(ctx, log, cluster, phase)
+		if err != nil {
+			return false, fmt.Errorf("failed to run the %s hibernation phase: %v", phase, err)
+		}
+		if !finished {
+			cluster.Status.Hibernation.Message = message
+			r.setHibernatedCondition(cluster)
+			return false, r.patchCluster(ctx, cluster, oldCluster)
+		}
+
+		log.Infow("Hibernation phase finished", "phase", phase)
+		phase = nextPhases[phase]
+	}
+}
+
+// runPhase performs the changes of the given phase and returns whether the phase has finished. If it
+// has not, the returned message explains why. The phases can be run repeatedly.
+func (r *Reconciler) runPhase(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster, phase kubermaticv1.ClusterHibernationPhase) (bool, string, error) {
+	switch phase {
+	case kubermaticv1.ClusterHibernationPhaseEtcdSnapshot:
+		return r.takeEtcdSnapshot(ctx, log, cluster)
+
+	case kubermaticv1.ClusterHibernationPhaseScalingDownMachineDeployments:
+		if cluster.Status.ExtendedHealth.Apiserver != kubermaticv1.HealthStatusUp {
+			return false, "Waiting for the apiserver to be healthy", nil
+		}
+		return r.scaleDownMachineDeployments(ctx, cluster)
+
+	case kubermaticv1.ClusterHibernationPhaseScalingDownControlPlane:
+		return r.scaleDownControlPlane(ctx, cluster)
+
+	case kubermaticv1.ClusterHibernationPhaseResumingControlPlane:
+		if err := r.resumeControlPlane(ctx, cluster); err != nil {
+			return false, "", err
+		}
+		if cluster.Status.ExtendedHealth.Apiserver != kubermaticv1.HealthStatusUp {
+			return false, "Waiting for the apiserver to be healthy", nil
+		}
+		return true, "", nil
+
+	case kubermaticv1.ClusterHibernationPhaseResumingMachineDeployments:
+		// The MachineDeployments of a cluster which is being deleted are deleted anyway
+		if cluster.DeletionTimestamp != nil {
+			return true, "", nil
+		}
+		return r.resumeMachineDeployments(ctx, cluster)
+	}
+
+	return false, "", fmt.Errorf("unknown hibernation phase %q", phase)
+}
+
+// takeEtcdSnapshot creates a one-off EtcdBackupConfig and waits for its backup to be completed. Once it is,
+// the snapshots of previous hibernations are deleted.
+func (r *Reconciler) takeEtcdSnapshot(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) (bool, string, error) {
+	if !cluster.Spec.Hibernation.EtcdSnapshot {
+		return true, "", nil
+	}
+	if !r.etcdSnapshots {
+		log.Info("Skipping the etcd snapshot, the etcd backup controllers are not enabled")
+		r.recorder.Event(cluster, corev1.EventTypeWarning, "EtcdSnapshotSkipped", "No etcd snapshot was taken, the etcd backup controllers are not enabled on the seed")
+		return true, "", nil
+	}
+
+	// The name is derived from the start of the phase, so that every hibernation takes a new snapshot
+	name := fmt.Sprintf("hibernation-%d", cluster.Status.Hibernation.PhaseStartTime.Unix())
+	config := &kubermaticv1.EtcdBackupConfig{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: name}, config)
+	if kerrors.IsNotFound(err) {
+		config = &kubermaticv1.EtcdBackupConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       cluster.Status.NamespaceName,
+				Labels:          map[string]string{etcdSnapshotLabelKey: "true"},
+				OwnerReferences: []metav1.OwnerReference{resources.GetClusterRef(cluster)},
+			},
+			Spec: kubermaticv1.EtcdBackupConfigSpec{
+				Name: name,
+				Cluster: corev1.ObjectReference{
+					Kind:       kubermaticv1.ClusterKindName,
+					Name:       cluster.Name,
+					UID:        cluster.UID,
+					APIVersion: "kubermatic.k8s.io/v1",
+				},
+			},
+		}
+		if err := r.Create(ctx, config); err != nil {
+			return false, "", fmt.Errorf("failed to create EtcdBackupConfig %s: %v", name, err)
+		}
+		return false, "Waiting for the etcd snapshot to be taken", nil
+	}
+	if err != nil {
+		return false, "", fmt.Errorf("failed to get EtcdBackupConfig %s: %v", name, err)
+	}
+
+	completed := false
+	for _, backup := range config.Status.CurrentBackups {
+		switch backup.BackupPhase {
+		case kubermaticv1.BackupStatusPhaseCompleted:
+			completed = true
+		case kubermaticv1.BackupStatusPhaseFailed:
+			return false, fmt.Sprintf("The etcd snapshot failed: %s", backup.BackupMessage), nil
+		}
+	}
+	if !completed {
+		return false, "Waiting for the etcd snapshot to be taken", nil
+	}
+
+	previous := &kubermaticv1.EtcdBackupConfigList{}
+	if err := r.List(ctx, previous, ctrlruntimeclient.InNamespace(cluster.Status.NamespaceName), ctrlruntimeclient.MatchingLabels{etcdSnapshotLabelKey: "true"}); err != nil {
+		return false, "", fmt.Errorf("failed to list EtcdBackupConfigs: %v", err)
+	}
+	for i := range previous.Items {
+		if previous.Items[i].Name == name {
+			continue
+		}
+		if err := r.Delete(ctx, &previous.Items[i]); ctrlruntimeclient.IgnoreNotFound(err) != nil {
+			return false, "", fmt.Errorf("failed to delete EtcdBackupConfig %s: %v", previous.Items[i].Name, err)
+		}
+	}
+	return true, "", nil
+}
+
+// scaleDownMachineDeployments scales all MachineDeployments to zero and waits for their machines to be deleted.
+func (r *Reconciler) scaleDownMachineDeployments(ctx context.Context, cluster *kubermaticv1.Cluster) (bool, string, error) {
+	userClusterClient, err := r.userClusterClientProvider.GetClient(ctx, cluster)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to get user cluster client: %v", err)
+	}
+
+	machineDeployments, err := listMachineDeployments(ctx, userClusterClient)
+	if err != nil {
+		return false, "", err
+	}
+
+	var pending []string
+	for i := range machineDeployments {
+		md := &machineDeployments[i]
+		if replicas := replicasOf(md.Spec.Replicas); replicas > 0 {
+			recordReplicas(&md.ObjectMeta, replicas)
+			md.Spec.Replicas = pointer.Int32Ptr(0)
+			if err := userClusterClient.Update(ctx, md); err != nil {
+				return false, "", fmt.Errorf("failed to scale down MachineDeployment %s: %v", md.Name, err)
+			}
+		}
+		if md.Status.ObservedGeneration < md.Generation || md.Status.Replicas > 0 {
+			pending = append(pending, fmt.Sprintf("MachineDeployment %s: %d machines remaining", md.Name, md.Status.Replicas))
+		}
+	}
+
+	if len(pending) > 0 {
+		return false, strings.Join(pending, "; "), nil
+	}
+	return true, "", nil
+}
+
+// resumeMachineDeployments restores the replicas of the MachineDeployments and waits for their machines to be available.
+func (r *Reconciler) resumeMachineDeployments(ctx context.Context, cluster *kubermaticv1.Cluster) (bool, string, error) {
+	userClusterClient, err := r.userClusterClientProvider.GetClient(ctx, cluster)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to get user cluster client: %v", err)
+	}
+
+	machineDeployments, err := listMachineDeployments(ctx, userClusterClient)
+	if err != nil {
+		return false, "", err
+	}
+
+	var pending []string
+	for i := range machineDeployments {
+		md := &machineDeployments[i]
+		if replicas, recorded := restoreReplicas(&md.ObjectMeta); recorded {
+			md.Spec.Replicas = pointer.Int32Ptr(replicas)
+			if err := userClusterClient.Update(ctx, md); err != nil {
+				return false, "", fmt.Errorf("failed to restore MachineDeployment %s: %v", md.Name, err)
+			}
+		}
+		replicas := replicasOf(md.Spec.Replicas)
+		if md.Status.ObservedGeneration < md.Generation || md.Status.AvailableReplicas < replicas {
+			pending = append(pending, fmt.Sprintf("MachineDeployment %s: %d of %d replicas available", md.Name, md.Status.AvailableReplicas, replicas))
+		}
+	}
+
+	if len(pending) > 0 {
+		return false, strings.Join(pending, "; "), nil
+	}
+	return true, "", nil
+}
+
+func listMachineDeployments(ctx context.Context, userClusterClient ctrlruntimeclient.Client) ([]clusterv1alpha1.MachineDeployment, error) {
+	machineDeployments := &clusterv1alpha1.MachineDeploymentList{}
+	// Kubermatic only creates MachineDeployments in the kube-system namespace, everything else is essentially unsupported
+	if err := userClusterClient.List(ctx, machineDeployments, ctrlruntimeclient.InNamespace(metav1.NamespaceSystem)); err != nil {
+		// The CRD does not exist if the cluster was never fully set up
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list MachineDeployments: %v", err)
+	}
+	return machineDeployments.Items, nil
+}
+
+// scaleDownControlPlane scales all Deployments and StatefulSets in the cluster namespace to zero and waits for their pods to be deleted.
+func (r *Reconciler) scaleDownControlPlane(ctx context.Context, cluster *kubermaticv1.Cluster) (bool, string, error) {
+	deployments := &appsv1.DeploymentList{}
+	if err := r.List(ctx, deployments, ctrlruntimeclient.InNamespace(cluster.Status.NamespaceName)); err != nil {
+		return false, "", fmt.Errorf("failed to list Deployments: %v", err)
+	}
+	statefulSets := &appsv1.StatefulSetList{}
+	if err := r.List(ctx, statefulSets, ctrlruntimeclient.InNamespace(cluster.Status.NamespaceName)); err != nil {
+		return false, "", fmt.Errorf("failed to list StatefulSets: %v", err)
+	}
+
+	var pending []string
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		if replicas := replicasOf(deployment.Spec.Replicas); replicas > 0 {
+			recordReplicas(&deployment.ObjectMeta, replicas)
+			deployment.Spec.Replicas = pointer.Int32Ptr(0)
+			if err := r.Update(ctx, deployment); err != nil {
+				return false, "", fmt.Errorf("failed to scale down Deployment %s: %v", deployment.Name, err)
+			}
+		}
+		if deployment.Status.Replicas > 0 {
+			pending = append(pending, fmt.Sprintf("Deployment %s: %d pods remaining", deployment.Name, deployment.Status.Replicas))
+		}
+	}
+	for i := range statefulSets.Items {
+		statefulSet := &statefulSets.Items[i]
+		if replicas := replicasOf(statefulSet.Spec.Replicas); replicas > 0 {
+			recordReplicas(&statefulSet.ObjectMeta, replicas)
+			statefulSet.Spec.Replicas = pointer.Int32Ptr(0)
+			if err := r.Update(ctx, statefulSet); err != nil {
+				return false, "", fmt.Errorf("failed to scale down StatefulSet %s: %v", statefulSet.Name, err)
+			}
+		}
+		if statefulSet.Status.Replicas > 0 {
+			pending = append(pending, fmt.Sprintf("StatefulSet %s: %d pods remaining", statefulSet.Name, statefulSet.Status.Replicas))
+		}
+	}
+
+	if len(pending) > 0 {
+		return false, strings.Join(pending, "; "), nil
+	}
+	return true, "", nil
+}
+
+// resumeControlPlane restores the replicas of the Deployments and StatefulSets in the cluster namespace.
+func (r *Reconciler) resumeControlPlane(ctx context.Context, cluster *kubermaticv1.Cluster) error {
+	deployments := &appsv1.DeploymentList{}
+	if err := r.List(ctx, deployments, ctrlruntimeclient.InNamespace(cluster.Status.NamespaceName)); err != nil {
+		return fmt.Errorf("failed to list Deployments: %v", err)
+	}
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		if replicas, recorded := restoreReplicas(&deployment.ObjectMeta); recorded {
+			deployment.Spec.Replicas = pointer.Int32Ptr(replicas)
+			if err := r.Update(ctx, deployment); err != nil {
+				return fmt.Errorf("failed to restore Deployment %s: %v", deployment.Name, err)
+			}
+		}
+	}
+
+	statefulSets := &appsv1.StatefulSetList{}
+	if err := r.List(ctx, statefulSets, ctrlruntimeclient.InNamespace(cluster.Status.NamespaceName)); err != nil {
+		return fmt.Errorf("failed to list StatefulSets: %v", err)
+	}
+	for i := range statefulSets.Items {
+		statefulSet := &statefulSets.Items[i]
+		if replicas, recorded := restoreReplicas(&statefulSet.ObjectMeta); recorded {
+			statefulSet.Spec.Replicas = pointer.Int32Ptr(replicas)
+			if err := r.Update(ctx, statefulSet); err != nil {
+				return fmt.Errorf("failed to restore StatefulSet %s: %v", statefulSet.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func replicasOf(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// recordReplicas records the replicas of an object before it is scaled down. Replicas which were
+// recorded before are kept, as the object might have been scaled up again by its controller.
+func recordReplicas(meta *metav1.ObjectMeta, replicas int32) {
+	if _, recorded := meta.Annotations[kubermaticv1.HibernationReplicasAnnotation]; recorded {
+		return
+	}
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	meta.Annotations[kubermaticv1.HibernationReplicasAnnotation] = strconv.Itoa(int(replicas))
+}
+
+// restoreReplicas removes the recorded replicas from the object and returns them.
+func restoreReplicas(meta *metav1.ObjectMeta) (int32, bool) {
+	value, recorded := meta.Annotations[kubermaticv1.HibernationReplicasAnnotation]
+	if !recorded {
+		return 0, false
+	}
+	delete(meta.Annotations, kubermaticv1.HibernationReplicasAnnotation)
+	replicas, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || replicas < 0 {
+		// An invalid annotation restores the default of a single replica
+		return 1, true
+	}
+	return int32(replicas), true
+}
+
+func setControlPlaneHealth(cluster *kubermaticv1.Cluster, health kubermaticv1.HealthStatus) {
+	h := &cluster.Status.ExtendedHealth
+	h.Apiserver = health
+	h.Scheduler = health
+	h.Controller = health
+	h.MachineController = health
+	h.Etcd = health
+	h.OpenVPN = health
+	h.UserClusterControllerManager = health
+}
+
+func (r *Reconciler) patchCluster(ctx context.Context, cluster, oldCluster *kubermaticv1.Cluster) error {
+	if reflect.DeepEqual(oldCluster, cluster) {
+		return nil
+	}
+	if err := r.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+		return fmt.Errorf("failed to update cluster: %v", err)
+	}
+	return nil
+}
@@ -94,6 +94,7 @@ type Reconciler struct {
 	kubermaticImage                                  string
 	etcdLauncherImage                                string
 	dnatControllerImage                              string
+	dnatControllerBackend                            string
 	concurrentClusterUpdates                         int
 	etcdBackupRestoreController                      bool
 	backupSchedule                                   time.Duration
@@ -138,6 +139,7 @@ func Add(
 	kubermaticImage string,
 	etcdLauncherImage string,
 	dnatControllerImage string,
+	dnatControllerBackend string,
 
 	tunnelingAgentIP string,
 	caBundle *certificates.CABundle,
@@ -168,6 +170,7 @@ func Add(
 		kubermaticImage:                                  kubermaticImage,
 		etcdLauncherImage:                                etcdLauncherImage,
 		dnatControllerImage:                              dnatControllerImage,
+		dnatControllerBackend:                            dnatControllerBackend,
 		concurrentClusterUpdates:                         concurrentClusterUpdates,
 		etcdBackupRestoreController:                      etcdBackupRestoreController,
 		backupSchedule:                                   backupSchedule,
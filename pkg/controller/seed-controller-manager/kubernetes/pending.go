@@ -21,6 +21,7 @@ import (
 	"time"
 
 	kubermaticapiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
+	"k8c.io/kubermatic/v2/pkg/cni"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
 	"k8c.io/kubermatic/v2/pkg/resources"
@@ -144,6 +145,15 @@ func (r *Reconciler) ensureClusterNetworkDefaults(ctx context.Context, cluster *
 		modifiers = append(modifiers, setProxyMode)
 	}
 
+	if cluster.Spec.CNIPlugin == nil {
+		setCNIPlugin := func(c *kubermaticv1.Cluster) {
+			// clusters created before the CNI plugin could be selected run the legacy one
+			plugin := cni.ClusterPlugin(&c.Spec)
+			c.Spec.CNIPlugin = &plugin
+		}
+		modifiers = append(modifiers, setCNIPlugin)
+	}
+
 	return r.updateCluster(ctx, cluster, func(c *kubermaticv1.Cluster) {
 		for _, modify := range modifiers {
 			modify(c)
@@ -39,6 +39,7 @@ import (
 	"k8c.io/kubermatic/v2/pkg/resources/rancherserver"
 	"k8c.io/kubermatic/v2/pkg/resources/reconciling"
 	"k8c.io/kubermatic/v2/pkg/resources/scheduler"
+	tunnelingserver "k8c.io/kubermatic/v2/pkg/resources/tunneling-server"
 	"k8c.io/kubermatic/v2/pkg/resources/usercluster"
 
 	corev1 "k8s.io/api/core/v1"
@@ -191,6 +192,7 @@ func (r *Reconciler) getClusterTemplateData(ctx context.Context, cluster *kuberm
 		WithKubermaticImage(r.kubermaticImage).
 		WithEtcdLauncherImage(r.etcdLauncherImage).
 		WithDnatControllerImage(r.dnatControllerImage).
+		WithDnatControllerBackend(r.dnatControllerBackend).
 		WithBackupPeriod(r.backupSchedule).
 		WithFailureDomainZoneAntiaffinity(supportsFailureDomainZoneAntiAffinity).
 		WithVersions(r.versions).
@@ -228,18 +230,23 @@ func (r *Reconciler) ensureNamespaceExists(ctx context.Context, cluster *kuberma
 
 // GetServiceCreators returns all service creators that are currently in use
 func GetServiceCreators(data *resources.TemplateData) []reconciling.NamedServiceCreatorGetter {
+	limits := data.NodeportProxyLimits()
 	creators := []reconciling.NamedServiceCreatorGetter{
-		apiserver.ServiceCreator(data.Cluster().Spec.ExposeStrategy, data.Cluster().Address.ExternalName),
-		openvpn.ServiceCreator(data.Cluster().Spec.ExposeStrategy),
+		nodeportproxy.WithLimits(apiserver.ServiceCreator(data.Cluster().Spec.ExposeStrategy, data.Cluster().Address.ExternalName), limits),
+		nodeportproxy.WithLimits(openvpn.ServiceCreator(data.Cluster().Spec.ExposeStrategy), limits),
 		etcd.ServiceCreator(data),
 		dns.ServiceCreator(),
 		machinecontroller.ServiceCreator(),
 		metricsserver.ServiceCreator(),
+		usercluster.WebhookServiceCreator(),
 	}
 
 	if data.Cluster().Spec.ExposeStrategy == kubermaticv1.ExposeStrategyLoadBalancer {
 		creators = append(creators, nodeportproxy.FrontLoadBalancerServiceCreator())
 	}
+	if data.Cluster().Spec.ExposeStrategy == kubermaticv1.ExposeStrategyTunneling {
+		creators = append(creators, nodeportproxy.WithLimits(tunnelingserver.ServiceCreator(), limits), tunnelingserver.EgressServiceCreator())
+	}
 	if flag := data.Cluster().Spec.Features[kubermaticv1.ClusterFeatureRancherIntegration]; flag {
 		creators = append(creators, rancherserver.ServiceCreator(data.Cluster().Spec.ExposeStrategy))
 	}
@@ -269,6 +276,9 @@ func GetDeploymentCreators(data *resources.TemplateData, enableAPIserverOIDCAuth
 	if data.Cluster().Annotations[kubermaticv1.AnnotationNameClusterAutoscalerEnabled] != "" {
 		deployments = append(deployments, clusterautoscaler.DeploymentCreator(data))
 	}
+	if data.Cluster().Spec.ExposeStrategy == kubermaticv1.ExposeStrategyTunneling {
+		deployments = append(deployments, tunnelingserver.DeploymentCreator(data))
+	}
 	// If CCM migration is ongoing defer the deployment of the CCM to the
 	// moment in which cloud controllers or the full in-tree cloud provider
 	// have been deactivated.
@@ -281,8 +291,34 @@ func GetDeploymentCreators(data *resources.TemplateData, enableAPIserverOIDCAuth
 	return deployments
 }
 
+// upgradeStageDeployments maps the control plane Deployments to the stage of an automatic upgrade in which they are rolled out
+var upgradeStageDeployments = map[string]kubermaticv1.ClusterUpgradeStage{
+	resources.ApiserverDeploymentName:         kubermaticv1.ClusterUpgradeStageApiserver,
+	resources.ControllerManagerDeploymentName: kubermaticv1.ClusterUpgradeStageControllerManagerAndScheduler,
+	resources.SchedulerDeploymentName:         kubermaticv1.ClusterUpgradeStageControllerManagerAndScheduler,
+}
+
+// filterUpgradeStageDeployments leaves out the control plane Deployments whose stage of the running automatic
+// upgrade has not been entered yet, so they keep running the previous version until the stage before is healthy.
+func filterUpgradeStageDeployments(cluster *kubermaticv1.Cluster, creators []reconciling.NamedDeploymentCreatorGetter) []reconciling.NamedDeploymentCreatorGetter {
+	upgrade := cluster.Status.Upgrade
+	if !upgrade.InProgress() {
+		return creators
+	}
+
+	var filtered []reconciling.NamedDeploymentCreatorGetter
+	for _, creator := range creators {
+		name, _ := creator()
+		if stage, ok := upgradeStageDeployments[name]; ok && !upgrade.HasReached(stage) {
+			continue
+		}
+		filtered = append(filtered, creator)
+	}
+	return filtered
+}
+
 func (r *Reconciler) ensureDeployments(ctx context.Context, cluster *kubermaticv1.Cluster, data *resources.TemplateData) error {
-	creators := GetDeploymentCreators(data, r.features.KubernetesOIDCAuthentication)
+	creators := filterUpgradeStageDeployments(cluster, GetDeploymentCreators(data, r.features.KubernetesOIDCAuthentication))
 	return reconciling.ReconcileDeployments(ctx, creators, cluster.Status.NamespaceName, r, reconciling.OwnerRefWrapper(resources.GetClusterRef(cluster)))
 }
 
@@ -303,6 +339,7 @@ func (r *Reconciler) GetSecretCreators(data *resources.TemplateData) []reconcili
 		openvpn.InternalClientCertificateCreator(data),
 		machinecontroller.TLSServingCertificateCreator(data),
 		metricsserver.TLSServingCertSecretCreator(data.GetRootCA),
+		usercluster.WebhookServingCertificateCreator(data),
 
 		// Kubeconfigs
 		resources.GetInternalKubeconfigCreator(resources.SchedulerKubeconfigSecretName, resources.SchedulerCertUsername, nil, data),
@@ -330,6 +367,14 @@ func (r *Reconciler) GetSecretCreators(data *resources.TemplateData) []reconcili
 		creators = append(creators, resources.ServiceAccountSecretCreator(data))
 	}
 
+	if data.Cluster().Spec.ExposeStrategy == kubermaticv1.ExposeStrategyTunneling {
+		creators = append(creators,
+			tunnelingserver.TokenSecretCreator(),
+			tunnelingserver.EgressServingCertificateCreator(data),
+			apiserver.TunnelingEgressClientCertificateCreator(data),
+		)
+	}
+
 	return creators
 }
 
@@ -400,7 +445,7 @@ func (r *Reconciler) ensureClusterRoleBindings(ctx context.Context, c *kubermati
 
 // GetConfigMapCreators returns all ConfigMapCreators that are currently in use
 func GetConfigMapCreators(data *resources.TemplateData) []reconciling.NamedConfigMapCreatorGetter {
-	return []reconciling.NamedConfigMapCreatorGetter{
+	creators := []reconciling.NamedConfigMapCreatorGetter{
 		cloudconfig.ConfigMapCreator(data),
 		openvpn.ServerClientConfigsConfigMapCreator(data),
 		dns.ConfigMapCreator(data),
@@ -408,6 +453,12 @@ func GetConfigMapCreators(data *resources.TemplateData) []reconciling.NamedConfi
 		apiserver.AdmissionControlCreator(data),
 		apiserver.CABundleCreator(data),
 	}
+
+	if data.Cluster().Spec.ExposeStrategy == kubermaticv1.ExposeStrategyTunneling {
+		creators = append(creators, apiserver.EgressSelectorConfigCreator(data))
+	}
+
+	return creators
 }
 
 func (r *Reconciler) ensureConfigMaps(ctx context.Context, c *kubermaticv1.Cluster, data *resources.TemplateData) error {
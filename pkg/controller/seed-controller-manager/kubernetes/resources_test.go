@@ -26,6 +26,7 @@ import (
 	"k8c.io/kubermatic/v2/pkg/resources/apiserver"
 	"k8c.io/kubermatic/v2/pkg/resources/certificates"
 	"k8c.io/kubermatic/v2/pkg/resources/cloudcontroller"
+	"k8c.io/kubermatic/v2/pkg/resources/reconciling"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -375,3 +376,63 @@ func (k KCMDeploymentConfig) Create(td *resources.TemplateData) *appsv1.Deployme
 	d.Spec.Template.Spec = *wrappedPodSpec
 	return &d
 }
+
+func TestFilterUpgradeStageDeployments(t *testing.T) {
+	creator := func(name string) reconciling.NamedDeploymentCreatorGetter {
+		return func() (string, reconciling.DeploymentCreator) {
+			return name, func(d *appsv1.Deployment) (*appsv1.Deployment, error) { return d, nil }
+		}
+	}
+	creators := []reconciling.NamedDeploymentCreatorGetter{
+		creator(resources.DNSResolverDeploymentName),
+		creator(resources.ApiserverDeploymentName),
+		creator(resources.ControllerManagerDeploymentName),
+		creator(resources.SchedulerDeploymentName),
+	}
+
+	testCases := []struct {
+		name     string
+		upgrade  *kubermaticv1.ClusterUpgradeStatus
+		expected sets.String
+	}{
+		{
+			name:     "no upgrade in progress",
+			expected: sets.NewString(resources.DNSResolverDeploymentName, resources.ApiserverDeploymentName, resources.ControllerManagerDeploymentName, resources.SchedulerDeploymentName),
+		},
+		{
+			name:     "etcd stage",
+			upgrade:  &kubermaticv1.ClusterUpgradeStatus{Stage: kubermaticv1.ClusterUpgradeStageEtcd},
+			expected: sets.NewString(resources.DNSResolverDeploymentName),
+		},
+		{
+			name:     "apiserver stage",
+			upgrade:  &kubermaticv1.ClusterUpgradeStatus{Stage: kubermaticv1.ClusterUpgradeStageApiserver},
+			expected: sets.NewString(resources.DNSResolverDeploymentName, resources.ApiserverDeploymentName),
+		},
+		{
+			name:     "machine deployments stage",
+			upgrade:  &kubermaticv1.ClusterUpgradeStatus{Stage: kubermaticv1.ClusterUpgradeStageMachineDeployments},
+			expected: sets.NewString(resources.DNSResolverDeploymentName, resources.ApiserverDeploymentName, resources.ControllerManagerDeploymentName, resources.SchedulerDeploymentName),
+		},
+		{
+			name:     "completed upgrade",
+			upgrade:  &kubermaticv1.ClusterUpgradeStatus{Stage: kubermaticv1.ClusterUpgradeStageCompleted},
+			expected: sets.NewString(resources.DNSResolverDeploymentName, resources.ApiserverDeploymentName, resources.ControllerManagerDeploymentName, resources.SchedulerDeploymentName),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := &kubermaticv1.Cluster{Status: kubermaticv1.ClusterStatus{Upgrade: tc.upgrade}}
+
+			names := sets.NewString()
+			for _, creator := range filterUpgradeStageDeployments(cluster, creators) {
+				name, _ := creator()
+				names.Insert(name)
+			}
+			if !names.Equal(tc.expected) {
+				t.Errorf("expected Deployments %v, got %v", tc.expected.List(), names.List())
+			}
+		})
+	}
+}
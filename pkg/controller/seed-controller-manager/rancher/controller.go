@@ -17,31 +17,29 @@ limitations under the License.
 package rancher
 
 import (
-	"bytes"
 	"context"
 	"crypto/tls"
 	"fmt"
-	"io/ioutil"
 	"math/rand"
 	"net/http"
-	"os"
-	"os/exec"
-	"path"
 	"strings"
 	"time"
 
 	"go.uber.org/zap"
 
+	"k8c.io/kubermatic/v2/pkg/cluster/client"
 	rancherclient "k8c.io/kubermatic/v2/pkg/controller/seed-controller-manager/rancher/client"
 	predicateutil "k8c.io/kubermatic/v2/pkg/controller/util/predicate"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1/helper"
 	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/util/yaml"
 	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	kubeapierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -58,21 +56,21 @@ const (
 	// keep the linter happy
 	// trueStr                   = "true"
 	rancherRandPasswordLength = 16
+	// fieldManager is the field manager used when applying the rancher registration manifest
+	fieldManager = "kubermatic-rancher"
 )
 
-// KubeconfigProvider provides functionality to get a clusters admin kubeconfig
-type KubeconfigProvider interface {
-	GetAdminKubeconfig(ctx context.Context, c *kubermaticv1.Cluster) ([]byte, error)
+// UserClusterClientProvider provides functionality to get a user cluster client
+type UserClusterClientProvider interface {
+	GetClient(ctx context.Context, c *kubermaticv1.Cluster, options ...client.ConfigOption) (ctrlruntimeclient.Client, error)
 }
 
-type fileHandlingDone func()
-
 type Reconciler struct {
 	ctrlruntimeclient.Client
 
-	log                *zap.SugaredLogger
-	kubeconfigProvider KubeconfigProvider
-	versions           kubermatic.Versions
+	log                       *zap.SugaredLogger
+	userClusterClientProvider UserClusterClientProvider
+	versions                  kubermatic.Versions
 }
 
 var (
@@ -82,7 +80,7 @@ var (
 func Add(
 	mgr manager.Manager,
 	log *zap.SugaredLogger,
-	kubeconfigProvider KubeconfigProvider,
+	userClusterClientProvider UserClusterClientProvider,
 	versions kubermatic.Versions,
 ) error {
 
@@ -90,9 +88,9 @@ func Add(
 	reconciler := &Reconciler{
 		Client: mgr.GetClient(),
 
-		log:                log,
-		kubeconfigProvider: kubeconfigProvider,
-		versions:           versions,
+		log:                       log,
+		userClusterClientProvider: userClusterClientProvider,
+		versions:                  versions,
 	}
 
 	c, err := controller.New(ControllerName, mgr, controller.Options{
@@ -322,59 +320,33 @@ func (r *Reconciler) getRancherInitSecret(ctx context.Context, namespace string)
 }
 
 func (r *Reconciler) applyRancherRegstrationCommand(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster, regToken *rancherclient.ClusterRegistrationToken) error {
-	client := getHTTPClient(true)
-	resp, err := client.Get(regToken.ManifestURL)
+	httpClient := getHTTPClient(true)
+	resp, err := httpClient.Get(regToken.ManifestURL)
 	if err != nil {
 		return fmt.Errorf("failed to get HTTP client: %v", err)
 	}
 	defer resp.Body.Close()
-	manifest, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read http response: %v", err)
-	}
-	kubeconfigFilename, kubeconfigDone, err := r.writeAdminKubeconfig(ctx, log, cluster)
-	if err != nil {
-		return fmt.Errorf("failed to write the admin kubeconfig to the local filesystem: %v", err)
-	}
-	defer kubeconfigDone()
-	cmd := getApplyCommand(ctx, kubeconfigFilename)
-	buffer := bytes.Buffer{}
-
-	buffer.Write(manifest)
-	cmd.Stdin = &buffer
-
-	_, err = cmd.CombinedOutput()
+	manifests, err := yaml.ParseMultipleDocuments(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to run rancher kubectl apply command: %v", err)
+		return fmt.Errorf("failed to parse the rancher registration manifest: %v", err)
 	}
-	return nil
-}
 
-func (r *Reconciler) writeAdminKubeconfig(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster) (string, fileHandlingDone, error) {
-	// Write kubeconfig to disk
-	kubeconfig, err := r.kubeconfigProvider.GetAdminKubeconfig(ctx, cluster)
+	userClusterClient, err := r.userClusterClientProvider.GetClient(ctx, cluster)
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to get admin kubeconfig for cluster %s: %v", cluster.Name, err)
-	}
-	kubeconfigFilename := path.Join("/tmp", fmt.Sprintf("cluster-%s-rancher-kubeconfig", cluster.Name))
-	if err := ioutil.WriteFile(kubeconfigFilename, kubeconfig, 0644); err != nil {
-		return "", nil, fmt.Errorf("failed to write admin kubeconfig for cluster %s: %v", cluster.Name, err)
+		return fmt.Errorf("failed to get usercluster client: %v", err)
 	}
-	log.Debugw("Wrote admin kubeconfig", "file", kubeconfigFilename)
-
-	return kubeconfigFilename, getFileDeleteFinalizer(log, kubeconfigFilename), nil
-}
 
-func getFileDeleteFinalizer(log *zap.SugaredLogger, filename string) fileHandlingDone {
-	return func() {
-		if err := os.RemoveAll(filename); err != nil {
-			log.Errorw("Failed to delete file", zap.Error(err), "file", filename)
+	for _, manifest := range manifests {
+		obj := &metav1unstructured.Unstructured{}
+		if _, _, err := metav1unstructured.UnstructuredJSONScheme.Decode(manifest.Raw, nil, obj); err != nil {
+			return fmt.Errorf("failed to decode the rancher registration manifest: %v", err)
 		}
+		if err := userClusterClient.Patch(ctx, obj, ctrlruntimeclient.Apply, ctrlruntimeclient.FieldOwner(fieldManager), ctrlruntimeclient.ForceOwnership); err != nil {
+			return fmt.Errorf("failed to apply %s %s: %v", obj.GetKind(), ctrlruntimeclient.ObjectKeyFromObject(obj), err)
+		}
+		log.Debugw("Applied rancher registration object", "kind", obj.GetKind(), "object", ctrlruntimeclient.ObjectKeyFromObject(obj))
 	}
-}
-
-func getApplyCommand(ctx context.Context, kubeconfigFilename string) *exec.Cmd {
-	return exec.CommandContext(ctx, "kubectl", "--kubeconfig", kubeconfigFilename, "apply", "-f", "-")
+	return nil
 }
 
 func getHTTPClient(insecure bool) http.Client {
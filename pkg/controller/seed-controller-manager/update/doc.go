@@ -18,8 +18,22 @@ limitations under the License.
 Package update contains a controller that auto applies updates to both the cluster version
 and the machine version based on a configuration file.
 
-TODO: Make this controller wait for successfully convergation after an update was applied. Currently,
-it may apply an update and then instantly apply another one, which is not supported, only n+1 minor
-version updates are supported.
+Automatic control plane upgrades pass through stages which are recorded in the cluster status:
+the preflight checks (etcd health, requests to APIs removed in the target version and
+PodDisruptionBudgets which prevent draining nodes), etcd, the apiserver, the controller-manager
+and scheduler, and finally the machine deployments. The next stage is only entered once the
+current stage is healthy. The cluster controller only rolls out the apiserver, controller-manager
+and scheduler Deployments once their stage has been entered, until then they keep running the
+previous version. If the preflight checks fail or a stage does not become healthy in time, the
+upgrade halts and the UpgradeHalted condition is set on the cluster. A halted upgrade continues
+by itself as soon as the stage becomes healthy.
+
+Upgrades are only started, and the version and machine deployments only changed, while the update
+window of the cluster is open. Clusters without an update window of their own use the window of
+their project.
+
+If rollout waves are configured, an upgrade is only started once the rollout has reached the wave
+of the cluster, see the rollout package for how the waves progress. Automatic node updates of
+clusters whose control plane is not upgraded automatically are not part of the rollout.
 */
 package update
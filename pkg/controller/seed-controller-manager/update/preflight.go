@@ -0,0 +1,146 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package update
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	// deprecatedAPIsMetric matches the samples of the metric the apiserver exposes for requests to deprecated APIs
+	deprecatedAPIsMetric = regexp.MustCompile(`^apiserver_requested_deprecated_apis\{(.*)\}\s+(\S+)`)
+	metricLabel          = regexp.MustCompile(`(\w+)="([^"]*)"`)
+)
+
+// preflightChecks checks whether the cluster can be upgraded to the given version. The returned
+// problems explain why it can not.
+func (r *Reconciler) preflightChecks(ctx context.Context, cluster *kubermaticv1.Cluster, toVersion string) ([]string, error) {
+	var problems []string
+
+	if cluster.Status.ExtendedHealth.Etcd != kubermaticv1.HealthStatusUp {
+		problems = append(problems, "etcd is not healthy")
+	}
+
+	metrics, err := r.apiserverMetrics(ctx, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get apiserver metrics: %v", err)
+	}
+	removedAPIs, err := removedAPIsInUse(metrics, toVersion)
+	if err != nil {
+		return nil, err
+	}
+	if len(removedAPIs) > 0 {
+		problems = append(problems, fmt.Sprintf("APIs removed in %s are still in use: %s", toVersion, strings.Join(removedAPIs, ", ")))
+	}
+
+	userClusterClient, err := r.userClusterConnectionProvider.GetClient(ctx, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get usercluster client: %v", err)
+	}
+	pdbs := &policyv1beta1.PodDisruptionBudgetList{}
+	if err := userClusterClient.List(ctx, pdbs); err != nil {
+		return nil, fmt.Errorf("failed to list PodDisruptionBudgets: %v", err)
+	}
+	if blocking := blockingPodDisruptionBudgets(pdbs.Items); len(blocking) > 0 {
+		problems = append(problems, fmt.Sprintf("PodDisruptionBudgets do not allow to drain nodes: %s", strings.Join(blocking, ", ")))
+	}
+
+	return problems, nil
+}
+
+// removedAPIsInUse returns the deprecated APIs which were requested according to the given apiserver metrics
+// and are removed in the given version or before.
+func removedAPIsInUse(metrics []byte, version string) ([]string, error) {
+	target, err := semver.NewVersion(version)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version %q: %v", version, err)
+	}
+
+	var apis []string
+	scanner := bufio.NewScanner(bytes.NewReader(metrics))
+	for scanner.Scan() {
+		match := deprecatedAPIsMetric.FindStringSubmatch(scanner.Text())
+		// The metric is reset to 0 once the API was not requested for a while
+		if match == nil || match[2] == "0" {
+			continue
+		}
+
+		labels := map[string]string{}
+		for _, label := range metricLabel.FindAllStringSubmatch(match[1], -1) {
+			labels[label[1]] = label[2]
+		}
+		if labels["removed_release"] == "" {
+			continue
+		}
+		removed, err := semver.NewVersion(labels["removed_release"])
+		if err != nil {
+			continue
+		}
+		if removed.Major() > target.Major() || (removed.Major() == target.Major() && removed.Minor() > target.Minor()) {
+			continue
+		}
+
+		api := labels["resource"] + "." + labels["version"]
+		if labels["group"] != "" {
+			api += "." + labels["group"]
+		}
+		apis = append(apis, api)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read apiserver metrics: %v", err)
+	}
+
+	sort.Strings(apis)
+	return apis, nil
+}
+
+// blockingPodDisruptionBudgets returns the PodDisruptionBudgets which currently do not allow any disruption
+// and would prevent the nodes from being drained when the machine deployments are updated.
+func blockingPodDisruptionBudgets(pdbs []policyv1beta1.PodDisruptionBudget) []string {
+	var blocking []string
+	for _, pdb := range pdbs {
+		if pdb.Status.ExpectedPods > 0 && pdb.Status.DisruptionsAllowed == 0 {
+			blocking = append(blocking, pdb.Namespace+"/"+pdb.Name)
+		}
+	}
+	return blocking
+}
+
+func (r *Reconciler) getApiserverMetrics(ctx context.Context, cluster *kubermaticv1.Cluster) ([]byte, error) {
+	config, err := r.userClusterConnectionProvider.GetClientConfig(ctx, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get usercluster client config: %v", err)
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create usercluster client: %v", err)
+	}
+	return client.CoreV1().RESTClient().Get().AbsPath("/metrics").DoRaw(ctx)
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package update
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const apiserverMetrics = `# HELP apiserver_requested_deprecated_apis [ALPHA] Gauge of deprecated APIs that have been requested, broken out by API group, version, resource, subresource, and removed_release.
+# TYPE apiserver_requested_deprecated_apis gauge
+apiserver_requested_deprecated_apis{group="extensions",removed_release="1.22",resource="ingresses",subresource="",version="v1beta1"} 1
+apiserver_requested_deprecated_apis{group="policy",removed_release="1.25",resource="podsecuritypolicies",subresource="",version="v1beta1"} 1
+apiserver_requested_deprecated_apis{group="apiextensions.k8s.io",removed_release="1.22",resource="customresourcedefinitions",subresource="",version="v1beta1"} 0
+apiserver_requested_deprecated_apis{group="",removed_release="",resource="componentstatuses",subresource="",version="v1"} 1
+# HELP apiserver_request_total [STABLE] Counter of apiserver requests broken out for each verb, dry run value, group, version, resource, scope, component, and HTTP response code.
+apiserver_request_total{code="200",component="apiserver",group="",resource="pods",scope="namespace",subresource="",verb="LIST",version="v1"} 42
+`
+
+func TestRemovedAPIsInUse(t *testing.T) {
+	testCases := []struct {
+		name     string
+		version  string
+		expected []string
+	}{
+		{
+			name:    "no APIs are removed in the version",
+			version: "1.21.2",
+		},
+		{
+			name:     "APIs removed in the version",
+			version:  "1.22.0",
+			expected: []string{"ingresses.v1beta1.extensions"},
+		},
+		{
+			name:     "APIs removed before the version",
+			version:  "1.25.1",
+			expected: []string{"ingresses.v1beta1.extensions", "podsecuritypolicies.v1beta1.policy"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			apis, err := removedAPIsInUse([]byte(apiserverMetrics), tc.version)
+			if err != nil {
+				t.Fatalf("failed to parse metrics: %v", err)
+			}
+			if diff := deep.Equal(apis, tc.expected); diff != nil {
+				t.Errorf("unexpected APIs: %v", diff)
+			}
+		})
+	}
+}
+
+func TestBlockingPodDisruptionBudgets(t *testing.T) {
+	pdb := func(name string, expected, allowed int32) policyv1beta1.PodDisruptionBudget {
+		return policyv1beta1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Status:     policyv1beta1.PodDisruptionBudgetStatus{ExpectedPods: expected, DisruptionsAllowed: allowed},
+		}
+	}
+
+	blocking := blockingPodDisruptionBudgets([]policyv1beta1.PodDisruptionBudget{
+		pdb("allows-disruptions", 3, 1),
+		pdb("no-pods", 0, 0),
+		pdb("blocking", 1, 0),
+	})
+	if diff := deep.Equal(blocking, []string{"default/blocking"}); diff != nil {
+		t.Errorf("unexpected PodDisruptionBudgets: %v", diff)
+	}
+}
@@ -28,12 +28,13 @@ import (
 	"k8c.io/kubermatic/v2/pkg/cluster/client"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1/helper"
-	"k8c.io/kubermatic/v2/pkg/semver"
+	"k8c.io/kubermatic/v2/pkg/util/updatewindow"
 	"k8c.io/kubermatic/v2/pkg/version"
 	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
 
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -47,20 +48,29 @@ const (
 	ControllerName = "kubermatic_update_controller"
 )
 
+// UserClusterConnectionProvider provides clients and client configs for user clusters
+type UserClusterConnectionProvider interface {
+	GetClient(ctx context.Context, c *kubermaticv1.Cluster, options ...client.ConfigOption) (ctrlruntimeclient.Client, error)
+	GetClientConfig(ctx context.Context, c *kubermaticv1.Cluster, options ...client.ConfigOption) (*restclient.Config, error)
+}
+
 type Reconciler struct {
 	ctrlruntimeclient.Client
 
 	workerName                    string
 	updateManager                 *version.Manager
 	recorder                      record.EventRecorder
-	userClusterConnectionProvider *client.Provider
+	userClusterConnectionProvider UserClusterConnectionProvider
 	log                           *zap.SugaredLogger
 	versions                      kubermatic.Versions
+
+	// apiserverMetrics returns the metrics of the apiserver of the given cluster in the text exposition format
+	apiserverMetrics func(ctx context.Context, cluster *kubermaticv1.Cluster) ([]byte, error)
 }
 
 // Add creates a new update controller
 func Add(mgr manager.Manager, numWorkers int, workerName string, updateManager *version.Manager,
-	userClusterConnectionProvider *client.Provider, log *zap.SugaredLogger, versions kubermatic.Versions) error {
+	userClusterConnectionProvider UserClusterConnectionProvider, log *zap.SugaredLogger, versions kubermatic.Versions) error {
 	reconciler := &Reconciler{
 		Client: mgr.GetClient(),
 
@@ -71,6 +81,7 @@ func Add(mgr manager.Manager, numWorkers int, workerName string, updateManager *
 		log:                           log,
 		versions:                      versions,
 	}
+	reconciler.apiserverMetrics = reconciler.getApiserverMetrics
 
 	c, err := controller.New(ControllerName, mgr, controller.Options{
 		Reconciler:              reconciler,
@@ -119,29 +130,47 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 }
 
 func (r *Reconciler) reconcile(ctx context.Context, cluster *kubermaticv1.Cluster) (*reconcile.Result, error) {
+	log := r.log.With("cluster", cluster.Name)
+
+	window, err := updatewindow.ForCluster(cluster)
+	if err != nil {
+		return nil, fmt.Errorf("invalid update window: %v", err)
+	}
+
+	// A running upgrade is continued regardless of the cluster health, as the stages
+	// check the health of the components they upgrade themselves
+	if cluster.Status.Upgrade.InProgress() {
+		return r.continueUpgrade(ctx, log, cluster, window, v1.KubernetesClusterType)
+	}
+
 	if !cluster.Status.ExtendedHealth.AllHealthy() {
 		// Cluster not healthy yet. Nothing to do.
 		// If it gets healthy we'll get notified by the event. No need to requeue
 		return nil, nil
 	}
 
+	if now := time.Now(); !window.Contains(now) {
+		log.Debugw("Update window is closed", "opens", window.Next(now))
+		return &reconcile.Result{RequeueAfter: window.Next(now).Sub(now)}, nil
+	}
+
 	// NodeUpdate may need the controlplane to be updated first
-	updated, err := r.controlPlaneUpgrade(ctx, cluster, v1.KubernetesClusterType)
+	started, waiting, err := r.startUpgrade(ctx, log, cluster, v1.KubernetesClusterType)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update the controlplane: %v", err)
+		return nil, fmt.Errorf("failed to start the controlplane upgrade: %v", err)
 	}
-	// Give the controller time to do the update
-	// TODO: This is not really safe. We should add a `Version` to the status
-	// that gets incremented when the controller does this. Combined with a
-	// `SeedResourcesUpToDate` condition, that should do the trick
-	if updated {
-		return &reconcile.Result{RequeueAfter: time.Minute}, nil
+	if started {
+		return r.continueUpgrade(ctx, log, cluster, window, v1.KubernetesClusterType)
 	}
 
 	if err := r.nodeUpdate(ctx, cluster, v1.KubernetesClusterType); err != nil {
 		return nil, fmt.Errorf("failed to update machineDeployments: %v", err)
 	}
 
+	if waiting {
+		// The rollout progresses with the other clusters, which do not trigger a reconciliation
+		return &reconcile.Result{RequeueAfter: rolloutRequeueInterval}, nil
+	}
 	return nil, nil
 }
 
@@ -175,24 +204,3 @@ func (r *Reconciler) nodeUpdate(ctx context.Context, cluster *kubermaticv1.Clust
 
 	return nil
 }
-
-func (r *Reconciler) controlPlaneUpgrade(ctx context.Context, cluster *kubermaticv1.Cluster, clusterType string) (upgraded bool, err error) {
-	update, err := r.updateManager.AutomaticControlplaneUpdate(cluster.Spec.Version.String(), clusterType)
-	if err != nil {
-		return false, fmt.Errorf("failed to get automatic update for cluster for version %s: %v", cluster.Spec.Version.String(), err)
-	}
-	if update == nil {
-		return false, nil
-	}
-	oldCluster := cluster.DeepCopy()
-
-	cluster.Spec.Version = *semver.NewSemverOrDie(update.Version.String())
-	// Invalidating the health to prevent automatic updates directly on the next processing.
-	cluster.Status.ExtendedHealth.Apiserver = kubermaticv1.HealthStatusDown
-	cluster.Status.ExtendedHealth.Controller = kubermaticv1.HealthStatusDown
-	cluster.Status.ExtendedHealth.Scheduler = kubermaticv1.HealthStatusDown
-	if err := r.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
-		return false, fmt.Errorf("failed to update cluster: %v", err)
-	}
-	return true, nil
-}
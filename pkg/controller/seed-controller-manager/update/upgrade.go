@@ -0,0 +1,372 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package update
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1/helper"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/semver"
+	"k8c.io/kubermatic/v2/pkg/util/updatewindow"
+	"k8c.io/kubermatic/v2/pkg/version/rollout"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// upgradeRequeueInterval is the interval in which the current stage of a running upgrade is checked
+	upgradeRequeueInterval = 30 * time.Second
+	// stageTimeout is the time after which an upgrade halts if the current stage did not become healthy
+	stageTimeout = 15 * time.Minute
+	// machineDeploymentsStageTimeout is the stage timeout of the machine deployments, whose nodes are replaced one by one
+	machineDeploymentsStageTimeout = time.Hour
+	// rolloutRequeueInterval is the interval in which clusters waiting for their rollout wave are checked
+	rolloutRequeueInterval = 5 * time.Minute
+)
+
+// nextStages maps each stage of an upgrade to the stage following it
+var nextStages = map[kubermaticv1.ClusterUpgradeStage]kubermaticv1.ClusterUpgradeStage{
+	kubermaticv1.ClusterUpgradeStagePreflight:                     kubermaticv1.ClusterUpgradeStageEtcd,
+	kubermaticv1.ClusterUpgradeStageEtcd:                          kubermaticv1.ClusterUpgradeStageApiserver,
+	kubermaticv1.ClusterUpgradeStageApiserver:                     kubermaticv1.ClusterUpgradeStageControllerManagerAndScheduler,
+	kubermaticv1.ClusterUpgradeStageControllerManagerAndScheduler: kubermaticv1.ClusterUpgradeStageMachineDeployments,
+	kubermaticv1.ClusterUpgradeStageMachineDeployments:            kubermaticv1.ClusterUpgradeStageCompleted,
+}
+
+// stagesWithinUpdateWindow are the stages which are only entered while the update window of the cluster is open
+var stagesWithinUpdateWindow = sets.NewString(
+	string(kubermaticv1.ClusterUpgradeStageEtcd),
+	string(kubermaticv1.ClusterUpgradeStageMachineDeployments),
+)
+
+// startUpgrade checks whether an automatic upgrade is available for the cluster and records it in the
+// cluster status. The version of the cluster is only changed once the preflight checks passed. If the
+// automatic upgrades are rolled out in waves, the upgrade is only started once the wave of the cluster
+// is reached; waiting is true until then.
+func (r *Reconciler) startUpgrade(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster, clusterType string) (started bool, waiting bool, err error) {
+	update, err := r.updateManager.AutomaticControlplaneUpdate(cluster.Spec.Version.String(), clusterType)
+	if err != nil {
+		return false, false, fmt.Errorf("failed to get automatic update for cluster for version %s: %v", cluster.Spec.Version.String(), err)
+	}
+	if update == nil {
+		return false, false, nil
+	}
+
+	if r.updateManager.GetRollout() != nil {
+		clusters := &kubermaticv1.ClusterList{}
+		if err := r.List(ctx, clusters); err != nil {
+			return false, false, fmt.Errorf("failed to list clusters: %v", err)
+		}
+		plan, err := rollout.Evaluate(r.updateManager, clusters.Items, time.Now())
+		if err != nil {
+			return false, false, fmt.Errorf("failed to evaluate the rollout: %v", err)
+		}
+		if mayStart, message := plan.MayStart(cluster); !mayStart {
+			log.Debugw("Automatic upgrade is waiting for the rollout", "to", update.Version.String(), "reason", message)
+			return false, true, nil
+		}
+	}
+
+	oldCluster := cluster.DeepCopy()
+	cluster.Status.Upgrade = &kubermaticv1.ClusterUpgradeStatus{
+		FromVersion:    cluster.Spec.Version.String(),
+		ToVersion:      update.Version.String(),
+		Stage:          kubermaticv1.ClusterUpgradeStagePreflight,
+		StageStartTime: metav1.Now(),
+	}
+	if err := r.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+		return false, false, fmt.Errorf("failed to update cluster: %v", err)
+	}
+
+	log.Infow("Starting automatic upgrade", "from", cluster.Status.Upgrade.FromVersion, "to", cluster.Status.Upgrade.ToVersion)
+	r.recorder.Eventf(cluster, corev1.EventTypeNormal, "AutoUpgradeStarted", "Started automatic upgrade from %s to %s", cluster.Status.Upgrade.FromVersion, cluster.Status.Upgrade.ToVersion)
+	return true, false, nil
+}
+
+// continueUpgrade checks the current stage of the running upgrade and moves on to the next stage once the
+// stage is healthy. If the preflight checks fail or a stage does not become healthy in time, the upgrade
+// halts and the UpgradeHalted condition is set. Halted upgrades are checked further and continue as soon
+// as the stage becomes healthy. The stages which change the cluster version and the machine deployments
+// are only entered while the update window is open.
+func (r *Reconciler) continueUpgrade(ctx context.Context, log *zap.SugaredLogger, cluster *kubermaticv1.Cluster, window *updatewindow.Window, clusterType string) (*reconcile.Result, error) {
+	oldCluster := cluster.DeepCopy()
+	upgrade := cluster.Status.Upgrade
+	log = log.With("from", upgrade.FromVersion, "to", upgrade.ToVersion, "stage", upgrade.Stage)
+
+	// The version is changed when leaving the preflight stage, if it is changed by anybody else
+	// the upgrade is abandoned
+	expectedVersion := upgrade.ToVersion
+	if upgrade.Stage == kubermaticv1.ClusterUpgradeStagePreflight {
+		expectedVersion = upgrade.FromVersion
+	}
+	if cluster.Spec.Version.String() != expectedVersion {
+		log.Infow("Cluster version was changed during the automatic upgrade, abandoning it", "version", cluster.Spec.Version.String())
+		cluster.Status.Upgrade = nil
+		r.setUpgradeHalted(cluster, false, "UpgradeAbandoned", fmt.Sprintf("The cluster version was changed to %s", cluster.Spec.Version.String()))
+		if err := r.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+			return nil, fmt.Errorf("failed to update cluster: %v", err)
+		}
+		return nil, nil
+	}
+
+	healthy, message, err := r.stageHealth(ctx, cluster, upgrade)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check the %s stage: %v", upgrade.Stage, err)
+	}
+
+	if !healthy {
+		upgrade.Message = message
+		if upgrade.Stage == kubermaticv1.ClusterUpgradeStagePreflight || time.Since(upgrade.StageStartTime.Time) > stageTimeoutFor(upgrade.Stage) {
+			if !cluster.Status.HasConditionValue(kubermaticv1.ClusterConditionUpgradeHalted, corev1.ConditionTrue) {
+				log.Infow("Automatic upgrade halted", "reason", message)
+				r.recorder.Eventf(cluster, corev1.EventTypeWarning, "AutoUpgradeHalted", "Automatic upgrade to %s halted in stage %s: %s", upgrade.ToVersion, upgrade.Stage, message)
+			}
+			r.setUpgradeHalted(cluster, true, string(upgrade.Stage), message)
+		}
+		if err := r.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+			return nil, fmt.Errorf("failed to update cluster: %v", err)
+		}
+		return &reconcile.Result{RequeueAfter: upgradeRequeueInterval}, nil
+	}
+
+	next := nextStages[upgrade.Stage]
+	if now := time.Now(); stagesWithinUpdateWindow.Has(string(next)) && !window.Contains(now) {
+		opens := window.Next(now)
+		upgrade.Message = fmt.Sprintf("Waiting for the update window, which opens at %s", opens.Format(time.RFC3339))
+		if err := r.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+			return nil, fmt.Errorf("failed to update cluster: %v", err)
+		}
+		return &reconcile.Result{RequeueAfter: opens.Sub(now)}, nil
+	}
+
+	if err := r.enterStage(ctx, cluster, next, clusterType); err != nil {
+		return nil, fmt.Errorf("failed to enter the %s stage: %v", next, err)
+	}
+	log.Infow("Upgrade stage finished", "next", next)
+
+	upgrade.Stage = next
+	upgrade.StageStartTime = metav1.Now()
+	upgrade.Message = ""
+	r.setUpgradeHalted(cluster, false, string(next), "")
+	if err := r.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+		return nil, fmt.Errorf("failed to update cluster: %v", err)
+	}
+
+	if next == kubermaticv1.ClusterUpgradeStageCompleted {
+		r.recorder.Eventf(cluster, corev1.EventTypeNormal, "AutoUpgradeCompleted", "Completed automatic upgrade from %s to %s", upgrade.FromVersion, upgrade.ToVersion)
+		return nil, nil
+	}
+	return &reconcile.Result{RequeueAfter: upgradeRequeueInterval}, nil
+}
+
+// enterStage performs the changes which start the given stage. The cluster is patched by the caller.
+func (r *Reconciler) enterStage(ctx context.Context, cluster *kubermaticv1.Cluster, stage kubermaticv1.ClusterUpgradeStage, clusterType string) error {
+	switch stage {
+	case kubermaticv1.ClusterUpgradeStageEtcd:
+		cluster.Spec.Version = *semver.NewSemverOrDie(cluster.Status.Upgrade.ToVersion)
+		// Invalidating the health, it is only computed again once the components have been updated
+		cluster.Status.ExtendedHealth.Apiserver = kubermaticv1.HealthStatusDown
+		cluster.Status.ExtendedHealth.Controller = kubermaticv1.HealthStatusDown
+		cluster.Status.ExtendedHealth.Scheduler = kubermaticv1.HealthStatusDown
+	case kubermaticv1.ClusterUpgradeStageMachineDeployments:
+		return r.nodeUpdate(ctx, cluster, clusterType)
+	}
+	return nil
+}
+
+// stageHealth checks whether the given stage of the upgrade is finished. If it is not, the returned message explains why.
+func (r *Reconciler) stageHealth(ctx context.Context, cluster *kubermaticv1.Cluster, upgrade *kubermaticv1.ClusterUpgradeStatus) (bool, string, error) {
+	switch upgrade.Stage {
+	case kubermaticv1.ClusterUpgradeStagePreflight:
+		problems, err := r.preflightChecks(ctx, cluster, upgrade.ToVersion)
+		if err != nil {
+			return false, "", err
+		}
+		return len(problems) == 0, strings.Join(problems, "; "), nil
+
+	case kubermaticv1.ClusterUpgradeStageEtcd:
+		// The control plane Deployments are only rolled out once their stage is entered, so
+		// the apiserver keeps running the previous version until etcd is healthy
+		if ready, message, err := r.statefulSetRolledOut(ctx, cluster, resources.EtcdStatefulSetName); err != nil || !ready {
+			return false, message, err
+		}
+		return componentHealth("etcd", cluster.Status.ExtendedHealth.Etcd)
+
+	case kubermaticv1.ClusterUpgradeStageApiserver:
+		if ready, message, err := r.deploymentRolledOut(ctx, cluster, resources.ApiserverDeploymentName, upgrade.ToVersion); err != nil || !ready {
+			return false, message, err
+		}
+		return componentHealth("apiserver", cluster.Status.ExtendedHealth.Apiserver)
+
+	case kubermaticv1.ClusterUpgradeStageControllerManagerAndScheduler:
+		for _, name := range []string{resources.ControllerManagerDeploymentName, resources.SchedulerDeploymentName} {
+			if ready, message, err := r.deploymentRolledOut(ctx, cluster, name, upgrade.ToVersion); err != nil || !ready {
+				return false, message, err
+			}
+		}
+		if healthy, message, _ := componentHealth("controller-manager", cluster.Status.ExtendedHealth.Controller); !healthy {
+			return false, message, nil
+		}
+		return componentHealth("scheduler", cluster.Status.ExtendedHealth.Scheduler)
+
+	case kubermaticv1.ClusterUpgradeStageMachineDeployments:
+		return r.machineDeploymentsRolledOut(ctx, cluster)
+	}
+
+	return false, "", fmt.Errorf("unknown upgrade stage %q", upgrade.Stage)
+}
+
+func stageTimeoutFor(stage kubermaticv1.ClusterUpgradeStage) time.Duration {
+	if stage == kubermaticv1.ClusterUpgradeStageMachineDeployments {
+		return machineDeploymentsStageTimeout
+	}
+	return stageTimeout
+}
+
+func componentHealth(name string, health kubermaticv1.HealthStatus) (bool, string, error) {
+	if health != kubermaticv1.HealthStatusUp {
+		return false, fmt.Sprintf("%s is not healthy", name), nil
+	}
+	return true, "", nil
+}
+
+func (r *Reconciler) setUpgradeHalted(cluster *kubermaticv1.Cluster, halted bool, reason, message string) {
+	status := corev1.ConditionTrue
+	if !halted {
+		// There is no need to add the condition to clusters which never halted
+		if _, cond := kubermaticv1helper.GetClusterCondition(cluster, kubermaticv1.ClusterConditionUpgradeHalted); cond == nil {
+			return
+		}
+		status = corev1.ConditionFalse
+	}
+	kubermaticv1helper.SetClusterCondition(cluster, r.versions, kubermaticv1.ClusterConditionUpgradeHalted, status, reason, message)
+}
+
+func referencesVersion(deployment *appsv1.Deployment, version string) bool {
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if strings.HasSuffix(container.Image, ":v"+version) {
+			return true
+		}
+	}
+	return false
+}
+
+// deploymentRolledOut checks that the given control plane Deployment runs the given version on all of its replicas.
+func (r *Reconciler) deploymentRolledOut(ctx context.Context, cluster *kubermaticv1.Cluster, name, version string) (bool, string, error) {
+	deployment := &appsv1.Deployment{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: name}, deployment); err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, fmt.Sprintf("Deployment %s not found", name), nil
+		}
+		return false, "", fmt.Errorf("failed to get Deployment %s: %v", name, err)
+	}
+
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+
+	switch {
+	case !referencesVersion(deployment, version):
+		return false, fmt.Sprintf("Deployment %s does not use version %s yet", name, version), nil
+	case deployment.Status.ObservedGeneration < deployment.Generation:
+		return false, fmt.Sprintf("Deployment %s: rollout has not been observed yet", name), nil
+	case deployment.Status.UpdatedReplicas < replicas:
+		return false, fmt.Sprintf("Deployment %s: %d of %d replicas updated", name, deployment.Status.UpdatedReplicas, replicas), nil
+	case deployment.Status.AvailableReplicas < replicas:
+		return false, fmt.Sprintf("Deployment %s: %d of %d replicas available", name, deployment.Status.AvailableReplicas, replicas), nil
+	default:
+		return true, "", nil
+	}
+}
+
+// statefulSetRolledOut checks that all replicas of the given control plane StatefulSet are updated and ready.
+func (r *Reconciler) statefulSetRolledOut(ctx context.Context, cluster *kubermaticv1.Cluster, name string) (bool, string, error) {
+	statefulSet := &appsv1.StatefulSet{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: name}, statefulSet); err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, fmt.Sprintf("StatefulSet %s not found", name), nil
+		}
+		return false, "", fmt.Errorf("failed to get StatefulSet %s: %v", name, err)
+	}
+
+	replicas := int32(1)
+	if statefulSet.Spec.Replicas != nil {
+		replicas = *statefulSet.Spec.Replicas
+	}
+
+	switch {
+	case statefulSet.Status.ObservedGeneration < statefulSet.Generation:
+		return false, fmt.Sprintf("StatefulSet %s: rollout has not been observed yet", name), nil
+	case statefulSet.Status.UpdatedReplicas < replicas:
+		return false, fmt.Sprintf("StatefulSet %s: %d of %d replicas updated", name, statefulSet.Status.UpdatedReplicas, replicas), nil
+	case statefulSet.Status.ReadyReplicas < replicas:
+		return false, fmt.Sprintf("StatefulSet %s: %d of %d replicas ready", name, statefulSet.Status.ReadyReplicas, replicas), nil
+	default:
+		return true, "", nil
+	}
+}
+
+// machineDeploymentsRolledOut checks that all nodes of the MachineDeployments of the user cluster have been replaced.
+func (r *Reconciler) machineDeploymentsRolledOut(ctx context.Context, cluster *kubermaticv1.Cluster) (bool, string, error) {
+	c, err := r.userClusterConnectionProvider.GetClient(ctx, cluster)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to get usercluster client: %v", err)
+	}
+
+	machineDeployments := &clusterv1alpha1.MachineDeploymentList{}
+	if err := c.List(ctx, machineDeployments, ctrlruntimeclient.InNamespace("kube-system")); err != nil {
+		return false, "", fmt.Errorf("failed to list MachineDeployments: %v", err)
+	}
+
+	var pending []string
+	for _, md := range machineDeployments.Items {
+		replicas := int32(1)
+		if md.Spec.Replicas != nil {
+			replicas = *md.Spec.Replicas
+		}
+
+		switch {
+		case md.Status.ObservedGeneration < md.Generation:
+			pending = append(pending, fmt.Sprintf("MachineDeployment %s: rollout has not been observed yet", md.Name))
+		case md.Status.UpdatedReplicas < replicas:
+			pending = append(pending, fmt.Sprintf("MachineDeployment %s: %d of %d replicas updated", md.Name, md.Status.UpdatedReplicas, replicas))
+		case md.Status.AvailableReplicas < replicas:
+			pending = append(pending, fmt.Sprintf("MachineDeployment %s: %d of %d replicas available", md.Name, md.Status.AvailableReplicas, replicas))
+		}
+	}
+
+	if len(pending) > 0 {
+		return false, strings.Join(pending, "; "), nil
+	}
+	return true, "", nil
+}
@@ -0,0 +1,319 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package update
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	mastersemver "github.com/Masterminds/semver/v3"
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+	clusterclient "k8c.io/kubermatic/v2/pkg/cluster/client"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1/helper"
+	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/semver"
+	"k8c.io/kubermatic/v2/pkg/version"
+	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func init() {
+	if err := clusterv1alpha1.SchemeBuilder.AddToScheme(scheme.Scheme); err != nil {
+		panic(fmt.Sprintf("failed to add clusterv1alpha1 to scheme: %v", err))
+	}
+}
+
+type fakeUserClusterConnectionProvider struct {
+	client ctrlruntimeclient.Client
+}
+
+func (p *fakeUserClusterConnectionProvider) GetClient(_ context.Context, _ *kubermaticv1.Cluster, _ ...clusterclient.ConfigOption) (ctrlruntimeclient.Client, error) {
+	return p.client, nil
+}
+
+func (p *fakeUserClusterConnectionProvider) GetClientConfig(_ context.Context, _ *kubermaticv1.Cluster, _ ...clusterclient.ConfigOption) (*restclient.Config, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func healthy() kubermaticv1.ExtendedClusterHealth {
+	return kubermaticv1.ExtendedClusterHealth{
+		Apiserver:                    kubermaticv1.HealthStatusUp,
+		Scheduler:                    kubermaticv1.HealthStatusUp,
+		Controller:                   kubermaticv1.HealthStatusUp,
+		MachineController:            kubermaticv1.HealthStatusUp,
+		Etcd:                         kubermaticv1.HealthStatusUp,
+		CloudProviderInfrastructure:  kubermaticv1.HealthStatusUp,
+		UserClusterControllerManager: kubermaticv1.HealthStatusUp,
+	}
+}
+
+func TestUpgradeStages(t *testing.T) {
+	const (
+		fromVersion = "1.21.1"
+		toVersion   = "1.22.0"
+	)
+
+	cluster := func(version string, stage kubermaticv1.ClusterUpgradeStage, stageStart time.Time, halted bool) *kubermaticv1.Cluster {
+		c := &kubermaticv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Spec:       kubermaticv1.ClusterSpec{Version: *semver.NewSemverOrDie(version)},
+			Status: kubermaticv1.ClusterStatus{
+				NamespaceName:  "cluster-test",
+				ExtendedHealth: healthy(),
+			},
+		}
+		if stage != "" {
+			c.Status.Upgrade = &kubermaticv1.ClusterUpgradeStatus{
+				FromVersion:    fromVersion,
+				ToVersion:      toVersion,
+				Stage:          stage,
+				StageStartTime: metav1.NewTime(stageStart),
+			}
+		}
+		if halted {
+			kubermaticv1helper.SetClusterCondition(c, kubermatic.NewFakeVersions(), kubermaticv1.ClusterConditionUpgradeHalted, corev1.ConditionTrue, "", "")
+		}
+		return c
+	}
+	deployment := func(name, version string, available int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "cluster-test"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: pointer.Int32Ptr(2),
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: name, Image: "k8s.gcr.io/kube-" + name + ":v" + version}},
+					},
+				},
+			},
+			Status: appsv1.DeploymentStatus{UpdatedReplicas: 2, AvailableReplicas: available},
+		}
+	}
+	etcd := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: resources.EtcdStatefulSetName, Namespace: "cluster-test"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: pointer.Int32Ptr(3)},
+		Status:     appsv1.StatefulSetStatus{UpdatedReplicas: 3, ReadyReplicas: 3},
+	}
+	rollingEtcd := etcd.DeepCopy()
+	rollingEtcd.Status.UpdatedReplicas = 1
+	machineDeployment := func(updated int32) *clusterv1alpha1.MachineDeployment {
+		return &clusterv1alpha1.MachineDeployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "workers", Namespace: "kube-system"},
+			Spec:       clusterv1alpha1.MachineDeploymentSpec{Replicas: pointer.Int32Ptr(3)},
+			Status:     clusterv1alpha1.MachineDeploymentStatus{UpdatedReplicas: updated, AvailableReplicas: 3},
+		}
+	}
+
+	// The window is open for a minute a year, so it is closed while the tests run
+	closedWindow := &kubermaticv1.UpdateWindow{Schedule: "0 0 1 1 *", Length: "1m"}
+
+	canaryCluster := cluster(fromVersion, "", time.Time{}, false)
+	canaryCluster.Name = "canary"
+	canaryCluster.Labels = map[string]string{"canary": "true"}
+	canaryRollout := &version.Rollout{
+		Waves: []version.RolloutWave{{
+			Name:     "canary",
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}},
+		}},
+		SoakTime:               metav1.Duration{Duration: time.Hour},
+		MaxUnhealthyPercentage: 10,
+	}
+
+	testCases := []struct {
+		name            string
+		cluster         *kubermaticv1.Cluster
+		updateWindow    *kubermaticv1.UpdateWindow
+		rollout         *version.Rollout
+		seedObjects     []ctrlruntimeclient.Object
+		userObjects     []ctrlruntimeclient.Object
+		metrics         string
+		expectedVersion string
+		expectedStage   kubermaticv1.ClusterUpgradeStage
+		expectedHalted  *bool
+	}{
+		{
+			name:            "upgrade passes the preflight checks and changes the version",
+			cluster:         cluster(fromVersion, "", time.Time{}, false),
+			expectedVersion: toVersion,
+			expectedStage:   kubermaticv1.ClusterUpgradeStageEtcd,
+		},
+		{
+			name:            "upgrade halts if removed APIs are in use",
+			cluster:         cluster(fromVersion, "", time.Time{}, false),
+			metrics:         `apiserver_requested_deprecated_apis{group="extensions",removed_release="1.22",resource="ingresses",subresource="",version="v1beta1"} 1`,
+			expectedVersion: fromVersion,
+			expectedStage:   kubermaticv1.ClusterUpgradeStagePreflight,
+			expectedHalted:  pointer.BoolPtr(true),
+		},
+		{
+			name:            "etcd stage waits until etcd is rolled out",
+			cluster:         cluster(toVersion, kubermaticv1.ClusterUpgradeStageEtcd, time.Now(), false),
+			seedObjects:     []ctrlruntimeclient.Object{rollingEtcd, deployment(resources.ApiserverDeploymentName, fromVersion, 2)},
+			expectedVersion: toVersion,
+			expectedStage:   kubermaticv1.ClusterUpgradeStageEtcd,
+		},
+		{
+			name:            "etcd stage passes once etcd is rolled out while the apiserver runs the previous version",
+			cluster:         cluster(toVersion, kubermaticv1.ClusterUpgradeStageEtcd, time.Now(), false),
+			seedObjects:     []ctrlruntimeclient.Object{etcd, deployment(resources.ApiserverDeploymentName, fromVersion, 2)},
+			expectedVersion: toVersion,
+			expectedStage:   kubermaticv1.ClusterUpgradeStageApiserver,
+		},
+		{
+			name:            "apiserver stage halts when it is not available in time",
+			cluster:         cluster(toVersion, kubermaticv1.ClusterUpgradeStageApiserver, time.Now().Add(-time.Hour), false),
+			seedObjects:     []ctrlruntimeclient.Object{deployment(resources.ApiserverDeploymentName, toVersion, 1)},
+			expectedVersion: toVersion,
+			expectedStage:   kubermaticv1.ClusterUpgradeStageApiserver,
+			expectedHalted:  pointer.BoolPtr(true),
+		},
+		{
+			name:            "halted upgrade continues once the stage is healthy",
+			cluster:         cluster(toVersion, kubermaticv1.ClusterUpgradeStageApiserver, time.Now().Add(-time.Hour), true),
+			seedObjects:     []ctrlruntimeclient.Object{deployment(resources.ApiserverDeploymentName, toVersion, 2)},
+			expectedVersion: toVersion,
+			expectedStage:   kubermaticv1.ClusterUpgradeStageControllerManagerAndScheduler,
+			expectedHalted:  pointer.BoolPtr(false),
+		},
+		{
+			name:            "upgrade does not start while the update window is closed",
+			cluster:         cluster(fromVersion, "", time.Time{}, false),
+			updateWindow:    closedWindow,
+			expectedVersion: fromVersion,
+		},
+		{
+			name:            "upgrade waits for the canary wave of the rollout",
+			cluster:         cluster(fromVersion, "", time.Time{}, false),
+			rollout:         canaryRollout,
+			seedObjects:     []ctrlruntimeclient.Object{canaryCluster},
+			expectedVersion: fromVersion,
+		},
+		{
+			name:            "canary cluster is upgraded first",
+			cluster:         canaryCluster.DeepCopy(),
+			rollout:         canaryRollout,
+			expectedVersion: toVersion,
+			expectedStage:   kubermaticv1.ClusterUpgradeStageEtcd,
+		},
+		{
+			name:    "machine deployments are not updated while the update window is closed",
+			cluster: cluster(toVersion, kubermaticv1.ClusterUpgradeStageControllerManagerAndScheduler, time.Now(), false),
+			seedObjects: []ctrlruntimeclient.Object{
+				deployment(resources.ControllerManagerDeploymentName, toVersion, 2),
+				deployment(resources.SchedulerDeploymentName, toVersion, 2),
+			},
+			updateWindow:    closedWindow,
+			expectedVersion: toVersion,
+			expectedStage:   kubermaticv1.ClusterUpgradeStageControllerManagerAndScheduler,
+		},
+		{
+			name:            "machine deployments stage waits for the nodes to be replaced",
+			cluster:         cluster(toVersion, kubermaticv1.ClusterUpgradeStageMachineDeployments, time.Now(), false),
+			userObjects:     []ctrlruntimeclient.Object{machineDeployment(1)},
+			expectedVersion: toVersion,
+			expectedStage:   kubermaticv1.ClusterUpgradeStageMachineDeployments,
+		},
+		{
+			name:            "upgrade completes once the machine deployments are rolled out",
+			cluster:         cluster(toVersion, kubermaticv1.ClusterUpgradeStageMachineDeployments, time.Now(), false),
+			userObjects:     []ctrlruntimeclient.Object{machineDeployment(3)},
+			expectedVersion: toVersion,
+			expectedStage:   kubermaticv1.ClusterUpgradeStageCompleted,
+		},
+		{
+			name:            "upgrade is abandoned when the version is changed manually",
+			cluster:         cluster("1.21.2", kubermaticv1.ClusterUpgradeStageApiserver, time.Now(), false),
+			expectedVersion: "1.21.2",
+		},
+	}
+
+	versions := []*version.Version{
+		{Version: mastersemver.MustParse(fromVersion), Type: "kubernetes"},
+		{Version: mastersemver.MustParse(toVersion), Type: "kubernetes"},
+	}
+	updates := []*version.Update{
+		{From: fromVersion, To: toVersion, Automatic: true, Type: "kubernetes"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			updateManager := version.NewWithRollout(versions, updates, tc.rollout)
+			tc.cluster.Spec.UpdateWindow = tc.updateWindow
+			seedClient := ctrlruntimefakeclient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(append(tc.seedObjects, tc.cluster)...).Build()
+			userClusterClient := ctrlruntimefakeclient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(tc.userObjects...).Build()
+
+			r := &Reconciler{
+				Client:                        seedClient,
+				updateManager:                 updateManager,
+				recorder:                      record.NewFakeRecorder(10),
+				userClusterConnectionProvider: &fakeUserClusterConnectionProvider{client: userClusterClient},
+				log:                           kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
+				versions:                      kubermatic.NewFakeVersions(),
+				apiserverMetrics: func(context.Context, *kubermaticv1.Cluster) ([]byte, error) {
+					return []byte(tc.metrics), nil
+				},
+			}
+
+			cluster := &kubermaticv1.Cluster{}
+			if err := seedClient.Get(ctx, types.NamespacedName{Name: tc.cluster.Name}, cluster); err != nil {
+				t.Fatalf("failed to get cluster: %v", err)
+			}
+			if _, err := r.reconcile(ctx, cluster); err != nil {
+				t.Fatalf("failed to reconcile: %v", err)
+			}
+
+			if err := seedClient.Get(ctx, types.NamespacedName{Name: tc.cluster.Name}, cluster); err != nil {
+				t.Fatalf("failed to get cluster: %v", err)
+			}
+			if version := cluster.Spec.Version.String(); version != tc.expectedVersion {
+				t.Errorf("expected version %s, got %s", tc.expectedVersion, version)
+			}
+			var stage kubermaticv1.ClusterUpgradeStage
+			if cluster.Status.Upgrade != nil {
+				stage = cluster.Status.Upgrade.Stage
+			}
+			if stage != tc.expectedStage {
+				t.Errorf("expected stage %q, got %q", tc.expectedStage, stage)
+			}
+
+			_, cond := kubermaticv1helper.GetClusterCondition(cluster, kubermaticv1.ClusterConditionUpgradeHalted)
+			switch {
+			case tc.expectedHalted == nil && cond != nil:
+				t.Errorf("expected no UpgradeHalted condition, got %v", cond.Status)
+			case tc.expectedHalted != nil && cond == nil:
+				t.Errorf("expected UpgradeHalted condition to be %v, got none", *tc.expectedHalted)
+			case tc.expectedHalted != nil && (cond.Status == corev1.ConditionTrue) != *tc.expectedHalted:
+				t.Errorf("expected UpgradeHalted condition to be %v, got %v", *tc.expectedHalted, cond.Status)
+			}
+		})
+	}
+}
@@ -23,6 +23,8 @@ import (
 	"net/http"
 	"sync"
 
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog"
@@ -60,9 +62,10 @@ var mapFn = handler.EnqueueRequestsFromMapFunc(func(o ctrlruntimeclient.Object)
 })
 
 // Add creates a new RBAC generator controller that is responsible for creating Cluster Roles and Cluster Role Bindings
-// for groups: `owners`, `editors` and `viewers``
-func Add(mgr manager.Manager, registerReconciledCheck func(name string, check healthz.Checker) error) error {
-	reconcile := &reconcileRBAC{Client: mgr.GetClient(), rLock: &sync.Mutex{}}
+// for groups: `owners`, `editors` and `viewers“. The OIDC groups bound to the project of the cluster
+// by GroupProjectBindings in the seed cluster are added to the Cluster Role Bindings.
+func Add(mgr, seedMgr manager.Manager, clusterName string, registerReconciledCheck func(name string, check healthz.Checker) error) error {
+	reconcile := &reconcileRBAC{Client: mgr.GetClient(), seedClient: seedMgr.GetClient(), clusterName: clusterName, rLock: &sync.Mutex{}}
 
 	// Create a new controller
 	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: reconcile})
@@ -79,6 +82,21 @@ func Add(mgr manager.Manager, registerReconciledCheck func(name string, check he
 		return err
 	}
 
+	// Watch for changes to the cluster and the GroupProjectBindings in the seed cluster
+	seedTypesToWatch := []ctrlruntimeclient.Object{
+		&kubermaticv1.Cluster{},
+		&kubermaticv1.GroupProjectBinding{},
+	}
+	for _, t := range seedTypesToWatch {
+		seedWatch := &source.Kind{Type: t}
+		if err := seedWatch.InjectCache(seedMgr.GetCache()); err != nil {
+			return fmt.Errorf("failed to inject cache in seed cluster watch for %T: %v", t, err)
+		}
+		if err := c.Watch(seedWatch, mapFn); err != nil {
+			return fmt.Errorf("failed to watch %T in seed: %v", t, err)
+		}
+	}
+
 	// A very simple but limited way to express the first successful reconciling to the seed cluster
 	return registerReconciledCheck(fmt.Sprintf("%s-%s", controllerName, "reconciled_successfully_once"), func(_ *http.Request) error {
 		reconcile.rLock.Lock()
@@ -94,6 +112,8 @@ func Add(mgr manager.Manager, registerReconciledCheck func(name string, check he
 // reconcileRBAC reconciles Cluster Role and Cluster Role Binding objects
 type reconcileRBAC struct {
 	ctrlruntimeclient.Client
+	seedClient  ctrlruntimeclient.Client
+	clusterName string
 
 	rLock                      *sync.Mutex
 	reconciledSuccessfullyOnce bool
@@ -101,7 +121,7 @@ type reconcileRBAC struct {
 
 // Reconcile makes changes in response to Cluster Role and Cluster Role Binding related changes
 func (r *reconcileRBAC) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
-	rdr := reconciler{client: r.Client}
+	rdr := reconciler{client: r.Client, seedClient: r.seedClient, clusterName: r.clusterName}
 
 	if err := rdr.Reconcile(ctx, request.Name); err != nil {
 		klog.Errorf("RBAC reconciliation failed: %v", err)
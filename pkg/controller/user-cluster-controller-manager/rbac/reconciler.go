@@ -20,16 +20,27 @@ import (
 	"context"
 	"fmt"
 
+	"k8c.io/kubermatic/v2/pkg/controller/master-controller-manager/rbac"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// sharedOIDCGroupsPrefix is the prefix of the groups of the OIDC tokens issued by the shared identity provider of Kubermatic
+const sharedOIDCGroupsPrefix = "oidc:"
+
 // reconciler creates and updates ClusterRoles and ClusterRoleBinding to achieve the desired state
 type reconciler struct {
 	client ctrlruntimeclient.Client
+	// seedClient is used to read the cluster and the GroupProjectBindings of its project,
+	// the OIDC groups are not bound if it is nil
+	seedClient  ctrlruntimeclient.Client
+	clusterName string
 }
 
 // Reconcile creates and updates ClusterRoles and ClusterRoleBinding to achieve the desired state
@@ -84,6 +95,11 @@ func (r *reconciler) ensureRBACClusterRoleBinding(ctx context.Context, resourceN
 	if err != nil {
 		return fmt.Errorf("failed to generate the RBAC Cluster Role Binding: %v", err)
 	}
+	groupSubjects, err := r.oidcGroupSubjects(ctx, resourceName)
+	if err != nil {
+		return fmt.Errorf("failed to get the OIDC groups bound to the project: %v", err)
+	}
+	defaultClusterBinding.Subjects = append(defaultClusterBinding.Subjects, groupSubjects...)
 
 	clusterRoleBinding := &rbacv1.ClusterRoleBinding{}
 	if err := r.client.Get(ctx, ctrlruntimeclient.ObjectKey{Namespace: metav1.NamespaceAll, Name: resourceName}, clusterRoleBinding); err != nil {
@@ -99,6 +115,7 @@ func (r *reconciler) ensureRBACClusterRoleBinding(ctx context.Context, resourceN
 
 	// compare cluster role bindings with default. If don't match update for default
 	if !ClusterRoleBindingMatches(clusterRoleBinding, defaultClusterBinding) {
+		defaultClusterBinding.ResourceVersion = clusterRoleBinding.ResourceVersion
 		if err := r.client.Update(ctx, defaultClusterBinding); err != nil {
 			return fmt.Errorf("failed to update the RBAC ClusterRoleBinding: %v", err)
 		}
@@ -106,3 +123,59 @@ func (r *reconciler) ensureRBACClusterRoleBinding(ctx context.Context, resourceN
 	}
 	return nil
 }
+
+// oidcGroupSubjects returns the OIDC groups bound to the project of the cluster with the group of the given resource.
+// The custom project roles have no counterpart in the user cluster, the groups bound with them are viewers.
+func (r *reconciler) oidcGroupSubjects(ctx context.Context, resourceName string) ([]rbacv1.Subject, error) {
+	if r.seedClient == nil {
+		return nil, nil
+	}
+	groupName, err := getGroupName(resourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster := &kubermaticv1.Cluster{}
+	if err := r.seedClient.Get(ctx, ctrlruntimeclient.ObjectKey{Name: r.clusterName}, cluster); err != nil {
+		return nil, fmt.Errorf("failed to get the cluster: %v", err)
+	}
+	projectID := cluster.Labels[kubermaticv1.ProjectIDLabelKey]
+	if projectID == "" {
+		return nil, nil
+	}
+
+	bindings := &kubermaticv1.GroupProjectBindingList{}
+	if err := r.seedClient.List(ctx, bindings); err != nil {
+		return nil, fmt.Errorf("failed to list the GroupProjectBindings: %v", err)
+	}
+
+	// the OIDC settings of the cluster don't prefix the groups, contrary to the shared ones
+	prefix := sharedOIDCGroupsPrefix
+	if cluster.Spec.OIDC.IssuerURL != "" && cluster.Spec.OIDC.ClientID != "" {
+		prefix = ""
+	}
+
+	groups := sets.NewString()
+	for _, binding := range bindings.Items {
+		if binding.Spec.ProjectID != projectID {
+			continue
+		}
+		role := binding.Spec.Role
+		if !rbac.IsBuiltinGroupPrefix(role) {
+			role = rbac.ViewerGroupNamePrefix
+		}
+		if role == groupName {
+			groups.Insert(prefix + binding.Spec.Group)
+		}
+	}
+
+	var subjects []rbacv1.Subject
+	for _, group := range groups.List() {
+		subjects = append(subjects, rbacv1.Subject{
+			APIGroup: rbacv1.GroupName,
+			Kind:     rbacv1.GroupKind,
+			Name:     group,
+		})
+	}
+	return subjects, nil
+}
@@ -21,9 +21,12 @@ import (
 	"sort"
 	"testing"
 
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -204,6 +207,93 @@ func TestReconcileUpdateRole(t *testing.T) {
 	}
 }
 
+func TestReconcileOIDCGroups(t *testing.T) {
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-cluster",
+			Labels: map[string]string{kubermaticv1.ProjectIDLabelKey: "my-project"},
+		},
+	}
+	clusterWithOIDC := cluster.DeepCopy()
+	clusterWithOIDC.Spec.OIDC = kubermaticv1.OIDCSettings{IssuerURL: "https://dex.example.com", ClientID: "kubernetes"}
+
+	genBinding := func(name, group, projectID, role string) *kubermaticv1.GroupProjectBinding {
+		return &kubermaticv1.GroupProjectBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       kubermaticv1.GroupProjectBindingSpec{Group: group, ProjectID: projectID, Role: role},
+		}
+	}
+	bindings := []ctrlruntimeclient.Object{
+		genBinding("b1", "developers", "my-project", "editors"),
+		genBinding("b2", "auditors", "my-project", "viewers"),
+		genBinding("b3", "operators", "my-project", "scalers"),
+		genBinding("b4", "strangers", "other-project", "viewers"),
+	}
+
+	tests := []struct {
+		name             string
+		cluster          *kubermaticv1.Cluster
+		resourceName     string
+		expectedSubjects []string
+	}{
+		{
+			name:             "scenario 1: the editor groups are prefixed for the shared OIDC settings",
+			cluster:          cluster,
+			resourceName:     editors,
+			expectedSubjects: []string{"editors", "oidc:developers"},
+		},
+		{
+			name:             "scenario 2: the custom project roles are viewers",
+			cluster:          cluster,
+			resourceName:     viewers,
+			expectedSubjects: []string{"viewers", "oidc:auditors", "oidc:operators"},
+		},
+		{
+			name:             "scenario 3: the groups are not prefixed for the OIDC settings of the cluster",
+			cluster:          clusterWithOIDC,
+			resourceName:     editors,
+			expectedSubjects: []string{"editors", "developers"},
+		},
+		{
+			name:             "scenario 4: no groups are bound as owners",
+			cluster:          cluster,
+			resourceName:     owners,
+			expectedSubjects: []string{"owners"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			seedClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(append(bindings, test.cluster)...).Build()
+			r := reconciler{client: fake.NewClientBuilder().Build(), seedClient: seedClient, clusterName: test.cluster.Name}
+
+			// an outdated binding is updated
+			if err := r.client.Create(ctx, &rbacv1.ClusterRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: test.resourceName},
+				RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: test.resourceName},
+			}); err != nil {
+				t.Fatalf("failed to create ClusterRoleBinding: %v", err)
+			}
+
+			if err := r.Reconcile(ctx, test.resourceName); err != nil {
+				t.Fatalf("Reconcile method error: %v", err)
+			}
+
+			binding := &rbacv1.ClusterRoleBinding{}
+			if err := r.client.Get(ctx, ctrlruntimeclient.ObjectKey{Name: test.resourceName}, binding); err != nil {
+				t.Fatalf("can't find cluster role binding %v", err)
+			}
+			var subjects []string
+			for _, subject := range binding.Subjects {
+				subjects = append(subjects, subject.Name)
+			}
+			if !equality.Semantic.DeepEqual(subjects, test.expectedSubjects) {
+				t.Fatalf("incorrect subjects, got: %v, want: %v", subjects, test.expectedSubjects)
+			}
+		})
+	}
+}
+
 func genTestClusterRole(t *testing.T, resourceName string) rbacv1.ClusterRole {
 	role, err := GenerateRBACClusterRole(resourceName)
 	if err != nil {
@@ -0,0 +1,256 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceusage
+
+import (
+	"context"
+	"fmt"
+
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+	"go.uber.org/zap"
+
+	predicateutil "k8c.io/kubermatic/v2/pkg/controller/util/predicate"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/quota"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const controllerName = "resource_usage_controller"
+
+type reconciler struct {
+	log         *zap.SugaredLogger
+	seedClient  ctrlruntimeclient.Client
+	userClient  ctrlruntimeclient.Client
+	recorder    record.EventRecorder
+	clusterName string
+}
+
+func Add(ctx context.Context, log *zap.SugaredLogger, seedMgr, userMgr manager.Manager, clusterName string) error {
+	log = log.Named(controllerName)
+
+	r := &reconciler{
+		log:         log,
+		seedClient:  seedMgr.GetClient(),
+		userClient:  userMgr.GetClient(),
+		recorder:    userMgr.GetEventRecorderFor(controllerName),
+		clusterName: clusterName,
+	}
+	c, err := controller.New(controllerName, userMgr, controller.Options{
+		Reconciler: r,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create controller: %v", err)
+	}
+
+	// all changes are reconciled for the whole cluster
+	enqueueCluster := handler.EnqueueRequestsFromMapFunc(func(_ ctrlruntimeclient.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: clusterName}}}
+	})
+
+	if err := c.Watch(&source.Kind{Type: &clusterv1alpha1.MachineDeployment{}}, enqueueCluster); err != nil {
+		return fmt.Errorf("failed to establish watch for the MachineDeployments: %v", err)
+	}
+
+	// the initial machine deployment request of the cluster is accounted until its machine deployment exists
+	clusterWatch := &source.Kind{Type: &kubermaticv1.Cluster{}}
+	if err := clusterWatch.InjectCache(seedMgr.GetCache()); err != nil {
+		return fmt.Errorf("failed to inject cache in seed cluster watch: %v", err)
+	}
+	if err := c.Watch(clusterWatch, enqueueCluster, predicateutil.ByName(clusterName)); err != nil {
+		return fmt.Errorf("failed to establish watch for the cluster: %v", err)
+	}
+
+	return nil
+}
+
+func (r *reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	log := r.log.With("cluster", request.Name)
+	log.Debug("Reconciling")
+
+	err := r.reconcile(ctx, log)
+	if err != nil {
+		log.Errorw("Reconciling failed", zap.Error(err))
+	}
+	return reconcile.Result{}, err
+}
+
+func (r *reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger) error {
+	state, err := getQuotaState(ctx, r.seedClient, r.clusterName)
+	if err != nil {
+		return err
+	}
+	cluster := state.cluster
+	if cluster.DeletionTimestamp != nil {
+		return nil
+	}
+
+	machineDeployments := &clusterv1alpha1.MachineDeploymentList{}
+	if err := r.userClient.List(ctx, machineDeployments, ctrlruntimeclient.InNamespace(metav1.NamespaceSystem)); err != nil {
+		return fmt.Errorf("failed to list machine deployments: %v", err)
+	}
+
+	admitted, err := r.enforceResourceQuota(ctx, log, state, machineDeployments.Items)
+	if err != nil {
+		return err
+	}
+
+	usage, err := quota.ClusterUsage(ctx, cluster, r.userClient)
+	if err != nil {
+		return err
+	}
+	if cluster.Status.ResourceUsage != nil && equality.Semantic.DeepEqual(*cluster.Status.ResourceUsage, usage) &&
+		equality.Semantic.DeepEqual(cluster.Status.AdmittedMachineDeploymentReplicas, admitted) {
+		return nil
+	}
+
+	oldCluster := cluster.DeepCopy()
+	cluster.Status.ResourceUsage = &usage
+	cluster.Status.AdmittedMachineDeploymentReplicas = admitted
+	if err := r.seedClient.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
+		return fmt.Errorf("failed to update the resource usage of the cluster: %v", err)
+	}
+	return nil
+}
+
+// quotaState is what the replicas of the machine deployments of a cluster are admitted on.
+type quotaState struct {
+	cluster       *kubermaticv1.Cluster
+	resourceQuota *kubermaticv1.ProjectResourceQuota
+	// otherUsage is the usage of the other clusters of the project, the one of the cluster is accounted from its
+	// machine deployments
+	otherUsage kubermaticv1.ResourceUsage
+}
+
+func getQuotaState(ctx context.Context, seedClient ctrlruntimeclient.Client, clusterName string) (*quotaState, error) {
+	cluster := &kubermaticv1.Cluster{}
+	if err := seedClient.Get(ctx, types.NamespacedName{Name: clusterName}, cluster); err != nil {
+		return nil, fmt.Errorf("failed to get the cluster: %v", err)
+	}
+
+	state := &quotaState{cluster: cluster}
+	if projectID := cluster.Labels[kubermaticv1.ProjectIDLabelKey]; projectID != "" {
+		// the projects are synced into the seeds by the master controller manager
+		project := &kubermaticv1.Project{}
+		if err := seedClient.Get(ctx, types.NamespacedName{Name: projectID}, project); err != nil {
+			if !kerrors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to get project %s: %v", projectID, err)
+			}
+		} else {
+			state.resourceQuota = project.Spec.ResourceQuota
+			state.otherUsage = quota.ProjectUsage(project)
+		}
+	}
+	if cluster.Status.ResourceUsage != nil {
+		state.otherUsage.Sub(*cluster.Status.ResourceUsage)
+	}
+	return state, nil
+}
+
+// enforceResourceQuota scales the machine deployments which have been scaled up beyond the resource quota of the
+// project back to the replicas the quota allows. The replicas admitted before are always kept, so that lowering
+// the quota doesn't remove any nodes. It returns the admitted replicas of all machine deployments, which are
+// recorded even without a quota, so that setting a quota later on doesn't affect the existing nodes.
+func (r *reconciler) enforceResourceQuota(ctx context.Context, log *zap.SugaredLogger, state *quotaState, machineDeployments []clusterv1alpha1.MachineDeployment) (map[string]int64, error) {
+	usage := *state.otherUsage.DeepCopy()
+	admitted := make([]int64, len(machineDeployments))
+	for i := range machineDeployments {
+		md := &machineDeployments[i]
+		admitted[i] = admittedReplicas(state.cluster, md)
+
+		admittedUsage, err := quota.MachineDeploymentReplicasUsage(md, admitted[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the resources of machine deployment %s: %v", md.Name, err)
+		}
+		usage.Add(admittedUsage)
+	}
+
+	allowedReplicas := make(map[string]int64, len(machineDeployments))
+	for i := range machineDeployments {
+		md := &machineDeployments[i]
+		replicas := specReplicas(md)
+
+		// the additional replicas are kept as far as the quota allows
+		allowed := replicas
+		if state.resourceQuota.LimitsNodes() {
+			for allowed > admitted[i] {
+				requested, err := quota.MachineDeploymentReplicasUsage(md, allowed-admitted[i])
+				if err != nil {
+					return nil, fmt.Errorf("failed to get the resources of machine deployment %s: %v", md.Name, err)
+				}
+				if quota.Check(state.resourceQuota, usage, requested) == nil {
+					usage.Add(requested)
+					break
+				}
+				allowed--
+			}
+		}
+		allowedReplicas[md.Name] = allowed
+
+		if allowed == replicas {
+			continue
+		}
+		log.Infow("Scaling down machine deployment exceeding the resource quota of the project", "machinedeployment", md.Name, "replicas", replicas, "allowed", allowed)
+		r.recorder.Eventf(md, corev1.EventTypeWarning, "ResourceQuotaExceeded",
+			"Scaled down from %d to %d replicas, more would exceed the resource quota of the project", replicas, allowed)
+		oldMD := md.DeepCopy()
+		replicas32 := int32(allowed)
+		md.Spec.Replicas = &replicas32
+		if err := r.userClient.Patch(ctx, md, ctrlruntimeclient.MergeFrom(oldMD)); err != nil {
+			return nil, fmt.Errorf("failed to update machine deployment %s: %v", md.Name, err)
+		}
+	}
+
+	if len(allowedReplicas) == 0 {
+		return nil, nil
+	}
+	return allowedReplicas, nil
+}
+
+// admittedReplicas returns the replicas of the machine deployment which have been admitted by the resource quota.
+// Before the controller recorded the usage of the cluster for the first time, the replicas which already exist are
+// admitted, afterwards the machine deployments without recorded replicas are new.
+func admittedReplicas(cluster *kubermaticv1.Cluster, md *clusterv1alpha1.MachineDeployment) int64 {
+	var admitted int64
+	if recorded, ok := cluster.Status.AdmittedMachineDeploymentReplicas[md.Name]; ok {
+		admitted = recorded
+	} else if cluster.Status.ResourceUsage == nil {
+		admitted = int64(md.Status.Replicas)
+	}
+	if replicas := specReplicas(md); admitted > replicas {
+		return replicas
+	}
+	return admitted
+}
+
+func specReplicas(md *clusterv1alpha1.MachineDeployment) int64 {
+	if md.Spec.Replicas == nil {
+		return 0
+	}
+	return int64(*md.Spec.Replicas)
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceusage
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	clusterName = "test-cluster"
+	projectID   = "my-project"
+)
+
+func TestReconcile(t *testing.T) {
+	int64Ptr := func(i int64) *int64 { return &i }
+
+	testCases := []struct {
+		name                     string
+		resourceQuota            *kubermaticv1.ProjectResourceQuota
+		projectUsage             *kubermaticv1.ResourceUsage
+		clusterUsage             *kubermaticv1.ResourceUsage
+		admittedReplicas         map[string]int64
+		machineDeployments       []ctrlruntimeclient.Object
+		expectedReplicas         map[string]int32
+		expectedAdmittedReplicas map[string]int64
+		expectedClusterUsage     kubermaticv1.ResourceUsage
+	}{
+		{
+			name:                     "replicas are admitted without a quota",
+			machineDeployments:       []ctrlruntimeclient.Object{genMachineDeployment(t, "md-1", 5, 3)},
+			expectedReplicas:         map[string]int32{"md-1": 5},
+			expectedAdmittedReplicas: map[string]int64{"md-1": 5},
+			expectedClusterUsage:     genUsage(1, 5, 10, 20, 50),
+		},
+		{
+			name:             "scaling up within the quota is admitted",
+			resourceQuota:    &kubermaticv1.ProjectResourceQuota{Nodes: int64Ptr(6)},
+			projectUsage:     &kubermaticv1.ResourceUsage{Clusters: 2, Nodes: 4},
+			clusterUsage:     &kubermaticv1.ResourceUsage{Clusters: 1, Nodes: 2},
+			admittedReplicas: map[string]int64{"md-1": 2},
+			machineDeployments: []ctrlruntimeclient.Object{
+				genMachineDeployment(t, "md-1", 4, 2),
+			},
+			expectedReplicas:         map[string]int32{"md-1": 4},
+			expectedAdmittedReplicas: map[string]int64{"md-1": 4},
+			expectedClusterUsage:     genUsage(1, 4, 8, 16, 40),
+		},
+		{
+			name:             "scaling up beyond the quota is scaled back",
+			resourceQuota:    &kubermaticv1.ProjectResourceQuota{Nodes: int64Ptr(6)},
+			projectUsage:     &kubermaticv1.ResourceUsage{Clusters: 2, Nodes: 4},
+			clusterUsage:     &kubermaticv1.ResourceUsage{Clusters: 1, Nodes: 2},
+			admittedReplicas: map[string]int64{"md-1": 2},
+			machineDeployments: []ctrlruntimeclient.Object{
+				genMachineDeployment(t, "md-1", 10, 2),
+			},
+			expectedReplicas:         map[string]int32{"md-1": 4},
+			expectedAdmittedReplicas: map[string]int64{"md-1": 4},
+			expectedClusterUsage:     genUsage(1, 4, 8, 16, 40),
+		},
+		{
+			name: "cpu quota is shared by the machine deployments",
+			resourceQuota: &kubermaticv1.ProjectResourceQuota{
+				CPU: resource.NewQuantity(12, resource.DecimalSI),
+			},
+			projectUsage:     &kubermaticv1.ResourceUsage{Clusters: 1},
+			clusterUsage:     &kubermaticv1.ResourceUsage{Clusters: 1},
+			admittedReplicas: map[string]int64{"md-1": 2, "md-2": 0},
+			machineDeployments: []ctrlruntimeclient.Object{
+				genMachineDeployment(t, "md-1", 2, 2),
+				genMachineDeployment(t, "md-2", 5, 0),
+			},
+			expectedReplicas:         map[string]int32{"md-1": 2, "md-2": 4},
+			expectedAdmittedReplicas: map[string]int64{"md-1": 2, "md-2": 4},
+			expectedClusterUsage:     genUsage(1, 6, 12, 24, 60),
+		},
+		{
+			name:             "admitted replicas are kept when the quota has been lowered",
+			resourceQuota:    &kubermaticv1.ProjectResourceQuota{Nodes: int64Ptr(1)},
+			projectUsage:     &kubermaticv1.ResourceUsage{Clusters: 1, Nodes: 3},
+			clusterUsage:     &kubermaticv1.ResourceUsage{Clusters: 1, Nodes: 3},
+			admittedReplicas: map[string]int64{"md-1": 3},
+			machineDeployments: []ctrlruntimeclient.Object{
+				genMachineDeployment(t, "md-1", 5, 3),
+			},
+			expectedReplicas:         map[string]int32{"md-1": 3},
+			expectedAdmittedReplicas: map[string]int64{"md-1": 3},
+			expectedClusterUsage:     genUsage(1, 3, 6, 12, 30),
+		},
+		{
+			name:          "existing replicas are admitted before the admitted replicas have been recorded",
+			resourceQuota: &kubermaticv1.ProjectResourceQuota{Nodes: int64Ptr(1)},
+			machineDeployments: []ctrlruntimeclient.Object{
+				genMachineDeployment(t, "md-1", 3, 3),
+			},
+			expectedReplicas:         map[string]int32{"md-1": 3},
+			expectedAdmittedReplicas: map[string]int64{"md-1": 3},
+			expectedClusterUsage:     genUsage(1, 3, 6, 12, 30),
+		},
+		{
+			name:          "existing replicas are not admitted after the admitted replicas have been recorded",
+			resourceQuota: &kubermaticv1.ProjectResourceQuota{Nodes: int64Ptr(2)},
+			projectUsage:  &kubermaticv1.ResourceUsage{Clusters: 1, Nodes: 1},
+			clusterUsage:  &kubermaticv1.ResourceUsage{Clusters: 1, Nodes: 1},
+			machineDeployments: []ctrlruntimeclient.Object{
+				genMachineDeployment(t, "md-1", 5, 5),
+			},
+			expectedReplicas:         map[string]int32{"md-1": 2},
+			expectedAdmittedReplicas: map[string]int64{"md-1": 2},
+			expectedClusterUsage:     genUsage(1, 2, 4, 8, 20),
+		},
+	}
+
+	userScheme := runtime.NewScheme()
+	if err := clusterv1alpha1.AddToScheme(userScheme); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			project := &kubermaticv1.Project{
+				ObjectMeta: metav1.ObjectMeta{Name: projectID},
+				Spec:       kubermaticv1.ProjectSpec{Name: projectID, ResourceQuota: tc.resourceQuota},
+				Status:     kubermaticv1.ProjectStatus{ResourceUsage: tc.projectUsage},
+			}
+			cluster := &kubermaticv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   clusterName,
+					Labels: map[string]string{kubermaticv1.ProjectIDLabelKey: projectID},
+				},
+				Status: kubermaticv1.ClusterStatus{
+					ResourceUsage:                     tc.clusterUsage,
+					AdmittedMachineDeploymentReplicas: tc.admittedReplicas,
+				},
+			}
+
+			seedClient := fakectrlruntimeclient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(project, cluster).Build()
+			userClient := fakectrlruntimeclient.NewClientBuilder().WithScheme(userScheme).WithObjects(tc.machineDeployments...).Build()
+
+			r := &reconciler{
+				log:         kubermaticlog.Logger,
+				seedClient:  seedClient,
+				userClient:  userClient,
+				recorder:    record.NewFakeRecorder(10),
+				clusterName: clusterName,
+			}
+			if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: clusterName}}); err != nil {
+				t.Fatalf("reconciling failed: %v", err)
+			}
+
+			for name, expectedReplicas := range tc.expectedReplicas {
+				md := &clusterv1alpha1.MachineDeployment{}
+				if err := userClient.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: name}, md); err != nil {
+					t.Fatalf("failed to get machine deployment %s: %v", name, err)
+				}
+				if *md.Spec.Replicas != expectedReplicas {
+					t.Errorf("expected machine deployment %s to have %d replicas, got %d", name, expectedReplicas, *md.Spec.Replicas)
+				}
+			}
+
+			if err := seedClient.Get(ctx, types.NamespacedName{Name: clusterName}, cluster); err != nil {
+				t.Fatalf("failed to get cluster: %v", err)
+			}
+			if admitted := cluster.Status.AdmittedMachineDeploymentReplicas; !reflect.DeepEqual(admitted, tc.expectedAdmittedReplicas) {
+				t.Errorf("expected admitted replicas %v, got %v", tc.expectedAdmittedReplicas, admitted)
+			}
+			usage := cluster.Status.ResourceUsage
+			if usage == nil {
+				t.Fatal("expected the resource usage of the cluster to be recorded")
+			}
+			expected := tc.expectedClusterUsage
+			if usage.Clusters != expected.Clusters || usage.Nodes != expected.Nodes || usage.CPU.Cmp(expected.CPU) != 0 ||
+				usage.Memory.Cmp(expected.Memory) != 0 || usage.Storage.Cmp(expected.Storage) != 0 {
+				t.Errorf("expected cluster usage %+v, got %+v", expected, *usage)
+			}
+		})
+	}
+}
+
+// genUsage returns a usage with the memory and storage given in GiB.
+func genUsage(clusters, nodes, cpu, memoryGi, storageGi int64) kubermaticv1.ResourceUsage {
+	return kubermaticv1.ResourceUsage{
+		Clusters: clusters,
+		Nodes:    nodes,
+		CPU:      *resource.NewQuantity(cpu, resource.DecimalSI),
+		Memory:   *resource.NewQuantity(memoryGi<<30, resource.BinarySI),
+		Storage:  *resource.NewQuantity(storageGi<<30, resource.BinarySI),
+	}
+}
+
+func genMachineDeployment(t *testing.T, name string, replicas, existingReplicas int32) *clusterv1alpha1.MachineDeployment {
+	providerSpec, err := json.Marshal(map[string]interface{}{
+		"cloudProvider": "vsphere",
+		"cloudProviderSpec": map[string]interface{}{
+			"cpus":       2,
+			"memoryMB":   4096,
+			"diskSizeGB": 10,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	md := &clusterv1alpha1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceSystem},
+	}
+	md.Spec.Replicas = &replicas
+	md.Spec.Template.Spec.ProviderSpec.Value = &runtime.RawExtension{Raw: providerSpec}
+	md.Status.Replicas = existingReplicas
+	return md
+}
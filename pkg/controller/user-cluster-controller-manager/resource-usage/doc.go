@@ -0,0 +1,25 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package resourceusage contains a controller that records the resources used by the machine deployments of the
+user cluster in the status of the cluster in the seed, from where they are aggregated into the status of the
+project. It enforces the resource quota of the project for the machine deployments: replicas added beyond the
+quota, no matter whether through the API or directly in the user cluster, are scaled back to what the quota allows.
+The admitted replicas are recorded in the status of the cluster, where the users can't change them, and a
+validating webhook served from the seed rejects machine deployments created or scaled up beyond the quota.
+*/
+package resourceusage
@@ -0,0 +1,158 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceusage
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+
+	"k8c.io/kubermatic/v2/pkg/quota"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntime "sigs.k8s.io/controller-runtime"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// MachineDeploymentValidationPath is the path on which the admission of the machine deployments is served.
+const MachineDeploymentValidationPath = "/validate-machinedeployment-resource-quota"
+
+// MachineDeploymentValidator rejects the machine deployments which are created or scaled up beyond the resource
+// quota of the project. The controller scales them back nonetheless, but only after the machine controller may
+// have created the machines already.
+type MachineDeploymentValidator struct {
+	log         logr.Logger
+	decoder     *admission.Decoder
+	seedClient  ctrlruntimeclient.Client
+	userClient  ctrlruntimeclient.Client
+	clusterName string
+}
+
+// NewMachineDeploymentValidator returns a new machine deployment validation AdmissionHandler.
+func NewMachineDeploymentValidator(seedClient, userClient ctrlruntimeclient.Client, clusterName string) *MachineDeploymentValidator {
+	return &MachineDeploymentValidator{
+		seedClient:  seedClient,
+		userClient:  userClient,
+		clusterName: clusterName,
+	}
+}
+
+func (v *MachineDeploymentValidator) InjectLogger(l logr.Logger) error {
+	v.log = l.WithName("machinedeployment-resource-quota-handler")
+	return nil
+}
+
+func (v *MachineDeploymentValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+func (v *MachineDeploymentValidator) SetupWebhookWithManager(mgr ctrlruntime.Manager) {
+	mgr.GetWebhookServer().Register(MachineDeploymentValidationPath, &webhook.Admission{Handler: v})
+}
+
+func (v *MachineDeploymentValidator) Handle(ctx context.Context, req webhook.AdmissionRequest) webhook.AdmissionResponse {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return webhook.Allowed(fmt.Sprintf("machine deployment validation request %s allowed", req.UID))
+	}
+
+	md := &clusterv1alpha1.MachineDeployment{}
+	if req.SubResource == "scale" {
+		// the scale subresource only carries the replicas of the machine deployment
+		scale := &autoscalingv1.Scale{}
+		if err := v.decoder.Decode(req, scale); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if err := v.userClient.Get(ctx, types.NamespacedName{Namespace: req.Namespace, Name: req.Name}, md); err != nil {
+			return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to get machine deployment: %v", err))
+		}
+		md.Spec.Replicas = &scale.Spec.Replicas
+	} else {
+		if err := v.decoder.Decode(req, md); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if req.Operation == admissionv1.Create {
+			// the existing replicas are only admitted for the machine deployments which existed before the quota
+			md.Status = clusterv1alpha1.MachineDeploymentStatus{}
+		}
+	}
+
+	if err := v.validateResourceQuota(ctx, md); err != nil {
+		v.log.Info("machine deployment admission failed", "error", err)
+		return webhook.Denied(fmt.Sprintf("machine deployment validation request %s rejected: %v", req.UID, err))
+	}
+	return webhook.Allowed(fmt.Sprintf("machine deployment validation request %s allowed", req.UID))
+}
+
+// validateResourceQuota checks that the replicas of the machine deployment beyond the admitted ones fit into the
+// resource quota of the project, together with the admitted replicas of all machine deployments of the cluster.
+func (v *MachineDeploymentValidator) validateResourceQuota(ctx context.Context, md *clusterv1alpha1.MachineDeployment) error {
+	// the controller only accounts the machine deployments in the kube-system namespace
+	if md.Namespace != metav1.NamespaceSystem {
+		return nil
+	}
+
+	state, err := getQuotaState(ctx, v.seedClient, v.clusterName)
+	if err != nil {
+		return err
+	}
+	if !state.resourceQuota.LimitsNodes() {
+		return nil
+	}
+	admitted := admittedReplicas(state.cluster, md)
+	replicas := specReplicas(md)
+	if replicas <= admitted {
+		return nil
+	}
+
+	machineDeployments := &clusterv1alpha1.MachineDeploymentList{}
+	if err := v.userClient.List(ctx, machineDeployments, ctrlruntimeclient.InNamespace(metav1.NamespaceSystem)); err != nil {
+		return fmt.Errorf("failed to list machine deployments: %v", err)
+	}
+
+	usage := *state.otherUsage.DeepCopy()
+	for i := range machineDeployments.Items {
+		other := &machineDeployments.Items[i]
+		if other.Name == md.Name {
+			continue
+		}
+		admittedUsage, err := quota.MachineDeploymentReplicasUsage(other, admittedReplicas(state.cluster, other))
+		if err != nil {
+			return fmt.Errorf("failed to get the resources of machine deployment %s: %v", other.Name, err)
+		}
+		usage.Add(admittedUsage)
+	}
+	admittedUsage, err := quota.MachineDeploymentReplicasUsage(md, admitted)
+	if err != nil {
+		return fmt.Errorf("failed to get the resources of the machine deployment: %v", err)
+	}
+	usage.Add(admittedUsage)
+
+	requested, err := quota.MachineDeploymentReplicasUsage(md, replicas-admitted)
+	if err != nil {
+		return fmt.Errorf("failed to get the resources of the machine deployment: %v", err)
+	}
+	return quota.Check(state.resourceQuota, usage, requested)
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceusage
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	logrtesting "github.com/go-logr/logr/testing"
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestHandle(t *testing.T) {
+	nodes := int64(4)
+	project := &kubermaticv1.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: projectID},
+		Spec:       kubermaticv1.ProjectSpec{Name: projectID, ResourceQuota: &kubermaticv1.ProjectResourceQuota{Nodes: &nodes}},
+		Status:     kubermaticv1.ProjectStatus{ResourceUsage: &kubermaticv1.ResourceUsage{Clusters: 2, Nodes: 3}},
+	}
+	// one node is used by the other cluster of the project and two are admitted for md-1
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   clusterName,
+			Labels: map[string]string{kubermaticv1.ProjectIDLabelKey: projectID},
+		},
+		Status: kubermaticv1.ClusterStatus{
+			ResourceUsage:                     &kubermaticv1.ResourceUsage{Clusters: 1, Nodes: 2},
+			AdmittedMachineDeploymentReplicas: map[string]int64{"md-1": 2},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		req         webhook.AdmissionRequest
+		wantAllowed bool
+	}{
+		{
+			name:        "Create machine deployment within the quota",
+			req:         genMachineDeploymentRequest(t, admissionv1.Create, genMachineDeployment(t, "md-2", 1, 0)),
+			wantAllowed: true,
+		},
+		{
+			name:        "Create machine deployment beyond the quota",
+			req:         genMachineDeploymentRequest(t, admissionv1.Create, genMachineDeployment(t, "md-2", 2, 0)),
+			wantAllowed: false,
+		},
+		{
+			name:        "Create machine deployment with existing replicas beyond the quota",
+			req:         genMachineDeploymentRequest(t, admissionv1.Create, genMachineDeployment(t, "md-2", 2, 2)),
+			wantAllowed: false,
+		},
+		{
+			name:        "Update machine deployment within the quota",
+			req:         genMachineDeploymentRequest(t, admissionv1.Update, genMachineDeployment(t, "md-1", 3, 2)),
+			wantAllowed: true,
+		},
+		{
+			name:        "Update machine deployment beyond the quota",
+			req:         genMachineDeploymentRequest(t, admissionv1.Update, genMachineDeployment(t, "md-1", 4, 2)),
+			wantAllowed: false,
+		},
+		{
+			name:        "Scale machine deployment within the quota",
+			req:         genScaleRequest(t, "md-1", 3),
+			wantAllowed: true,
+		},
+		{
+			name:        "Scale machine deployment beyond the quota",
+			req:         genScaleRequest(t, "md-1", 4),
+			wantAllowed: false,
+		},
+		{
+			name: "Create machine deployment outside of kube-system",
+			req: func() webhook.AdmissionRequest {
+				md := genMachineDeployment(t, "md-2", 10, 0)
+				md.Namespace = metav1.NamespaceDefault
+				return genMachineDeploymentRequest(t, admissionv1.Create, md)
+			}(),
+			wantAllowed: true,
+		},
+	}
+
+	decoderScheme := runtime.NewScheme()
+	if err := clusterv1alpha1.AddToScheme(decoderScheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := autoscalingv1.AddToScheme(decoderScheme); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		d, err := admission.NewDecoder(decoderScheme)
+		if err != nil {
+			t.Fatalf("error occurred while creating decoder: %v", err)
+		}
+		handler := MachineDeploymentValidator{
+			log:         &logrtesting.NullLogger{},
+			decoder:     d,
+			seedClient:  fakectrlruntimeclient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(project, cluster).Build(),
+			userClient:  fakectrlruntimeclient.NewClientBuilder().WithScheme(decoderScheme).WithObjects(genMachineDeployment(t, "md-1", 2, 2)).Build(),
+			clusterName: clusterName,
+		}
+		t.Run(tt.name, func(t *testing.T) {
+			if res := handler.Handle(context.TODO(), tt.req); res.Allowed != tt.wantAllowed {
+				t.Errorf("Allowed %t, but wanted %t", res.Allowed, tt.wantAllowed)
+			}
+		})
+	}
+}
+
+func genMachineDeploymentRequest(t *testing.T, operation admissionv1.Operation, md *clusterv1alpha1.MachineDeployment) webhook.AdmissionRequest {
+	md.TypeMeta = metav1.TypeMeta{APIVersion: clusterv1alpha1.SchemeGroupVersion.String(), Kind: "MachineDeployment"}
+	raw, err := json.Marshal(md)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return webhook.AdmissionRequest{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: operation,
+			Namespace: md.Namespace,
+			Name:      md.Name,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	}
+}
+
+func genScaleRequest(t *testing.T, name string, replicas int32) webhook.AdmissionRequest {
+	scale := &autoscalingv1.Scale{
+		TypeMeta:   metav1.TypeMeta{APIVersion: autoscalingv1.SchemeGroupVersion.String(), Kind: "Scale"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceSystem},
+		Spec:       autoscalingv1.ScaleSpec{Replicas: replicas},
+	}
+	raw, err := json.Marshal(scale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return webhook.AdmissionRequest{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation:   admissionv1.Update,
+			Namespace:   scale.Namespace,
+			Name:        name,
+			SubResource: "scale",
+			Object:      runtime.RawExtension{Raw: raw},
+		},
+	}
+}
@@ -262,6 +262,22 @@ func (r *reconciler) userSSHKeys(ctx context.Context) (map[string][]byte, error)
 	return secret.Data, nil
 }
 
+func (r *reconciler) tunnelingAgentToken(ctx context.Context) ([]byte, error) {
+	secret := &corev1.Secret{}
+	if err := r.seedClient.Get(
+		ctx,
+		types.NamespacedName{Namespace: r.namespace, Name: resources.TunnelingAgentTokenSecretName},
+		secret,
+	); err != nil {
+		return nil, fmt.Errorf("failed to get tunneling agent token: %v", err)
+	}
+	token, exists := secret.Data[resources.TunnelingAgentTokenSecretKey]
+	if !exists {
+		return nil, fmt.Errorf("tunneling agent token secret contains no data for key %s", resources.TunnelingAgentTokenSecretKey)
+	}
+	return token, nil
+}
+
 func (r *reconciler) cloudConfig(ctx context.Context) ([]byte, error) {
 	configmap := &corev1.ConfigMap{}
 	name := types.NamespacedName{Namespace: r.namespace, Name: resources.CloudConfigConfigMapName}
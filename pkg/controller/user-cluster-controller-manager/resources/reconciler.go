@@ -41,8 +41,10 @@ import (
 	nodelocaldns "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resources/resources/node-local-dns"
 	"k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resources/resources/openvpn"
 	"k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resources/resources/prometheus"
+	resourcequota "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resources/resources/resource-quota"
 	"k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resources/resources/scheduler"
 	systembasicuser "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resources/resources/system-basic-user"
+	tunnelingagent "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resources/resources/tunneling-agent"
 	userauth "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resources/resources/user-auth"
 	"k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resources/resources/usersshkeys"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
@@ -82,6 +84,13 @@ func (r *reconciler) reconcile(ctx context.Context) error {
 		cloudConfig:   cloudConfig,
 	}
 
+	if len(r.tunnelingAgentIP) > 0 {
+		data.tunnelingAgentToken, err = r.tunnelingAgentToken(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
 	if r.userClusterMLA.Monitoring || r.userClusterMLA.Logging {
 		data.mlaGatewayCACert, err = r.mlaGatewayCA(ctx)
 		if err != nil {
@@ -148,11 +157,11 @@ func (r *reconciler) reconcile(ctx context.Context) error {
 		return err
 	}
 
-	if err := r.reconcileDaemonSet(ctx); err != nil {
+	if err := r.reconcileDaemonSet(ctx, data); err != nil {
 		return err
 	}
 
-	if err := r.reconcileValidatingWebhookConfigurations(ctx); err != nil {
+	if err := r.reconcileValidatingWebhookConfigurations(ctx, data); err != nil {
 		return err
 	}
 
@@ -161,7 +170,9 @@ func (r *reconciler) reconcile(ctx context.Context) error {
 		if err := r.ensureOPAIntegrationIsRemoved(ctx); err != nil {
 			return err
 		}
-	} else {
+	}
+
+	if r.opaIntegration || len(r.tunnelingAgentIP) > 0 {
 		if err := r.healthCheck(ctx); err != nil {
 			return err
 		}
@@ -398,6 +409,9 @@ func (r *reconciler) reconcileClusterRoles(ctx context.Context) error {
 		kubernetesdashboard.ClusterRoleCreator(),
 		coredns.ClusterRoleCreator(),
 	}
+	if r.userSSHKeyAgent {
+		creators = append(creators, usersshkeys.ClusterRoleCreator())
+	}
 	if r.opaIntegration {
 		creators = append(creators, gatekeeper.ClusterRoleCreator())
 	}
@@ -434,6 +448,9 @@ func (r *reconciler) reconcileClusterRoleBindings(ctx context.Context) error {
 		kubernetesdashboard.ClusterRoleBindingCreator(),
 		coredns.ClusterRoleBindingCreator(),
 	}
+	if r.userSSHKeyAgent {
+		creators = append(creators, usersshkeys.ClusterRoleBindingCreator())
+	}
 	if r.opaIntegration {
 		creators = append(creators, gatekeeper.ClusterRoleBindingCreator())
 	}
@@ -484,8 +501,10 @@ func (r *reconciler) reconcileMutatingWebhookConfigurations(ctx context.Context,
 	return nil
 }
 
-func (r *reconciler) reconcileValidatingWebhookConfigurations(ctx context.Context) error {
-	creators := []reconciling.NamedValidatingWebhookConfigurationCreatorGetter{}
+func (r *reconciler) reconcileValidatingWebhookConfigurations(ctx context.Context, data reconcileData) error {
+	creators := []reconciling.NamedValidatingWebhookConfigurationCreatorGetter{
+		resourcequota.ValidatingWebhookConfigurationCreator(data.caCert.Cert, r.namespace),
+	}
 	if r.opaIntegration {
 		creators = append(creators, gatekeeper.ValidatingWebhookConfigurationCreator(r.opaWebhookTimeout))
 	}
@@ -600,6 +619,10 @@ func (r *reconciler) reconcileSecrets(ctx context.Context, data reconcileData) e
 		creators = append(creators, usersshkeys.SecretCreator(data.userSSHKeys))
 	}
 
+	if len(r.tunnelingAgentIP) > 0 {
+		creators = append(creators, tunnelingagent.TokenSecretCreator(data.tunnelingAgentToken))
+	}
+
 	if err := reconciling.ReconcileSecrets(ctx, creators, metav1.NamespaceSystem, r.Client); err != nil {
 		return fmt.Errorf("failed to reconcile Secrets in kube-system Namespace: %v", err)
 	}
@@ -650,17 +673,24 @@ func (r *reconciler) reconcileSecrets(ctx context.Context, data reconcileData) e
 	return nil
 }
 
-func (r *reconciler) reconcileDaemonSet(ctx context.Context) error {
+func (r *reconciler) reconcileDaemonSet(ctx context.Context, data reconcileData) error {
 	dsCreators := []reconciling.NamedDaemonSetCreatorGetter{
 		nodelocaldns.DaemonSetCreator(),
 	}
 
 	if r.userSSHKeyAgent {
-		dsCreators = append(dsCreators, usersshkeys.DaemonSetCreator(r.versions))
+		certificateAuthorityEnabled := len(data.userSSHKeys[resources.UserSSHKeysCertificateAuthorityKey]) > 0
+		dsCreators = append(dsCreators, usersshkeys.DaemonSetCreator(r.versions, certificateAuthorityEnabled))
 	}
 
 	if len(r.tunnelingAgentIP) > 0 {
-		dsCreators = append(dsCreators, envoyagent.DaemonSetCreator(r.tunnelingAgentIP, r.versions))
+		dsCreators = append(dsCreators,
+			envoyagent.DaemonSetCreator(r.tunnelingAgentIP, r.versions),
+			tunnelingagent.DaemonSetCreator(tunnelingagent.Config{
+				ProxyAddress: net.JoinHostPort(r.clusterURL.Hostname(), "8088"),
+				Authority:    net.JoinHostPort(fmt.Sprintf("%s.%s.svc.cluster.local", resources.TunnelingServerServiceName, r.namespace), fmt.Sprint(resources.TunnelingServerAgentPort)),
+			}, r.versions),
+		)
 	}
 
 	if err := reconciling.ReconcileDaemonSets(ctx, dsCreators, metav1.NamespaceSystem, r.Client); err != nil {
@@ -753,6 +783,8 @@ type reconcileData struct {
 	mlaGatewayCACert *resources.ECDSAKeyPair
 	userSSHKeys      map[string][]byte
 	cloudConfig      []byte
+	// tunnelingAgentToken is only set when the tunneling agents are deployed
+	tunnelingAgentToken []byte
 }
 
 func (r *reconciler) ensureOPAIntegrationIsRemoved(ctx context.Context) error {
@@ -773,13 +805,27 @@ func (r *reconciler) healthCheck(ctx context.Context) error {
 	}
 	oldCluster := cluster.DeepCopy()
 
-	ctrlHealth, auditHealth, err := r.getGatekeeperHealth(ctx)
-	if err != nil {
-		return err
+	if r.opaIntegration {
+		ctrlHealth, auditHealth, err := r.getGatekeeperHealth(ctx)
+		if err != nil {
+			return err
+		}
+
+		cluster.Status.ExtendedHealth.GatekeeperController = ctrlHealth
+		cluster.Status.ExtendedHealth.GatekeeperAudit = auditHealth
 	}
 
-	cluster.Status.ExtendedHealth.GatekeeperController = ctrlHealth
-	cluster.Status.ExtendedHealth.GatekeeperAudit = auditHealth
+	if len(r.tunnelingAgentIP) > 0 {
+		tunnelingAgentHealth, err := resources.HealthyDaemonSet(ctx,
+			r.Client,
+			types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: resources.TunnelingAgentDaemonSetName},
+			1)
+		if err != nil {
+			return fmt.Errorf("failed to get daemonset health %q: %v", resources.TunnelingAgentDaemonSetName, err)
+		}
+
+		cluster.Status.ExtendedHealth.TunnelingAgent = tunnelingAgentHealth
+	}
 
 	if oldCluster.Status.ExtendedHealth != cluster.Status.ExtendedHealth {
 		if err := r.seedClient.Patch(ctx, cluster, ctrlruntimeclient.MergeFrom(oldCluster)); err != nil {
@@ -0,0 +1,74 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcequota
+
+import (
+	"crypto/x509"
+	"fmt"
+
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+
+	resourceusage "k8c.io/kubermatic/v2/pkg/controller/user-cluster-controller-manager/resource-usage"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/certificates/triple"
+	"k8c.io/kubermatic/v2/pkg/resources/reconciling"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ValidatingWebhookConfigurationCreator returns the ValidatingWebhookConfiguration rejecting the machine deployments
+// which exceed the resource quota of the project. The webhook is served by the usercluster-controller in the seed.
+func ValidatingWebhookConfigurationCreator(caCert *x509.Certificate, namespace string) reconciling.NamedValidatingWebhookConfigurationCreatorGetter {
+	return func() (string, reconciling.ValidatingWebhookConfigurationCreator) {
+		return resources.UserClusterControllerValidatingWebhookConfigurationName, func(validatingWebhookConfiguration *admissionregistrationv1.ValidatingWebhookConfiguration) (*admissionregistrationv1.ValidatingWebhookConfiguration, error) {
+			failurePolicy := admissionregistrationv1.Fail
+			sideEffects := admissionregistrationv1.SideEffectClassNone
+			url := fmt.Sprintf("https://%s.%s.svc.cluster.local.%s", resources.UserClusterControllerWebhookServiceName, namespace, resourceusage.MachineDeploymentValidationPath)
+
+			// This only gets set when the APIServer supports it, so carry it over
+			var scope *admissionregistrationv1.ScopeType
+			if len(validatingWebhookConfiguration.Webhooks) != 1 {
+				validatingWebhookConfiguration.Webhooks = []admissionregistrationv1.ValidatingWebhook{{}}
+			} else if len(validatingWebhookConfiguration.Webhooks[0].Rules) > 0 {
+				scope = validatingWebhookConfiguration.Webhooks[0].Rules[0].Scope
+			}
+
+			validatingWebhookConfiguration.Webhooks[0].Name = fmt.Sprintf("machinedeployments-resource-quota.%s", resources.UserClusterControllerValidatingWebhookConfigurationName)
+			validatingWebhookConfiguration.Webhooks[0].NamespaceSelector = &metav1.LabelSelector{}
+			validatingWebhookConfiguration.Webhooks[0].SideEffects = &sideEffects
+			validatingWebhookConfiguration.Webhooks[0].FailurePolicy = &failurePolicy
+			validatingWebhookConfiguration.Webhooks[0].AdmissionReviewVersions = []string{"v1", "v1beta1"}
+			validatingWebhookConfiguration.Webhooks[0].Rules = []admissionregistrationv1.RuleWithOperations{{
+				Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update},
+				Rule: admissionregistrationv1.Rule{
+					APIGroups:   []string{clusterv1alpha1.SchemeGroupVersion.Group},
+					APIVersions: []string{clusterv1alpha1.SchemeGroupVersion.Version},
+					// the replicas can also be changed through the scale subresource
+					Resources: []string{"machinedeployments", "machinedeployments/scale"},
+					Scope:     scope,
+				},
+			}}
+			validatingWebhookConfiguration.Webhooks[0].ClientConfig = admissionregistrationv1.WebhookClientConfig{
+				URL:      &url,
+				CABundle: triple.EncodeCertPEM(caCert),
+			}
+
+			return validatingWebhookConfiguration, nil
+		}
+	}
+}
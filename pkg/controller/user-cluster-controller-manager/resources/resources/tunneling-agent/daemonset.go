@@ -0,0 +1,181 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnelingagent
+
+import (
+	"fmt"
+
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/reconciling"
+	"k8c.io/kubermatic/v2/pkg/version/kubermatic"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var (
+	defaultResourceRequirements = map[string]*corev1.ResourceRequirements{
+		resources.TunnelingAgentDaemonSetName: {
+			Requests: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("16Mi"),
+				corev1.ResourceCPU:    resource.MustParse("10m"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("64Mi"),
+				corev1.ResourceCPU:    resource.MustParse("200m"),
+			},
+		},
+	}
+
+	daemonSetMaxUnavailable = intstr.FromInt(1)
+)
+
+const (
+	dockerImage = "quay.io/kubermatic/tunneling-agent"
+	metricsPort = 9904
+)
+
+// Config is the configuration of the tunneling agents.
+type Config struct {
+	// ProxyAddress is the host:port of the HTTP CONNECT listener of the
+	// nodeport-proxy.
+	ProxyAddress string
+	// Authority is the host:port of the tunneling server requested to the
+	// nodeport-proxy.
+	Authority string
+}
+
+// DaemonSetCreator returns the function to create and update the tunneling
+// agent DaemonSet.
+func DaemonSetCreator(cfg Config, versions kubermatic.Versions) reconciling.NamedDaemonSetCreatorGetter {
+	return func() (string, reconciling.DaemonSetCreator) {
+		return resources.TunnelingAgentDaemonSetName, func(ds *appsv1.DaemonSet) (*appsv1.DaemonSet, error) {
+			ds.Labels = resources.BaseAppLabels(resources.TunnelingAgentDaemonSetName, nil)
+
+			ds.Spec.UpdateStrategy = appsv1.DaemonSetUpdateStrategy{
+				Type: appsv1.RollingUpdateDaemonSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDaemonSet{
+					MaxUnavailable: &daemonSetMaxUnavailable,
+				},
+			}
+			labels := resources.BaseAppLabels(resources.TunnelingAgentDaemonSetName, nil)
+			ds.Spec.Selector = &metav1.LabelSelector{MatchLabels: labels}
+			ds.Spec.Template.ObjectMeta = metav1.ObjectMeta{
+				Labels: labels,
+				Annotations: map[string]string{
+					"prometheus.io/path":   "/metrics",
+					"prometheus.io/port":   fmt.Sprint(metricsPort),
+					"prometheus.io/scrape": "true",
+				},
+			}
+
+			// The agent runs in the host network, to reach the kubelet of its
+			// node and to be independent of the health of the CNI.
+			ds.Spec.Template.Spec.HostNetwork = true
+			ds.Spec.Template.Spec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+			ds.Spec.Template.Spec.PriorityClassName = "system-node-critical"
+
+			ds.Spec.Template.Spec.Containers = []corev1.Container{
+				{
+					Name:            resources.TunnelingAgentDaemonSetName,
+					Image:           fmt.Sprintf("%s:%s", dockerImage, versions.Kubermatic),
+					ImagePullPolicy: corev1.PullIfNotPresent,
+					Command:         []string{"/usr/local/bin/tunneling-agent"},
+					Args: []string{
+						"-proxy-address", cfg.ProxyAddress,
+						"-authority", cfg.Authority,
+						"-node-name", "$(NODE_NAME)",
+						"-node-addresses", "$(HOST_IP)",
+						"-token-file", "/etc/kubernetes/tunneling/" + resources.TunnelingAgentTokenSecretKey,
+						"-metrics-listen-address", fmt.Sprintf("$(HOST_IP):%d", metricsPort),
+					},
+					Env: []corev1.EnvVar{
+						{
+							Name: "NODE_NAME",
+							ValueFrom: &corev1.EnvVarSource{
+								FieldRef: &corev1.ObjectFieldSelector{
+									FieldPath:  "spec.nodeName",
+									APIVersion: "v1",
+								},
+							},
+						},
+						{
+							Name: "HOST_IP",
+							ValueFrom: &corev1.EnvVarSource{
+								FieldRef: &corev1.ObjectFieldSelector{
+									FieldPath:  "status.hostIP",
+									APIVersion: "v1",
+								},
+							},
+						},
+					},
+					ReadinessProbe: &corev1.Probe{
+						Handler: corev1.Handler{
+							HTTPGet: &corev1.HTTPGetAction{
+								Path:   "/healthz",
+								Port:   intstr.FromInt(metricsPort),
+								Scheme: corev1.URISchemeHTTP,
+							},
+						},
+						FailureThreshold: 3,
+						PeriodSeconds:    10,
+						SuccessThreshold: 1,
+						TimeoutSeconds:   5,
+					},
+					VolumeMounts: []corev1.VolumeMount{
+						{
+							Name:      resources.TunnelingAgentTokenSecretName,
+							MountPath: "/etc/kubernetes/tunneling",
+							ReadOnly:  true,
+						},
+					},
+				},
+			}
+
+			ds.Spec.Template.Spec.Tolerations = []corev1.Toleration{
+				{
+					Effect:   corev1.TaintEffectNoSchedule,
+					Operator: corev1.TolerationOpExists,
+				},
+				{
+					Effect:   corev1.TaintEffectNoExecute,
+					Operator: corev1.TolerationOpExists,
+				},
+			}
+
+			ds.Spec.Template.Spec.Volumes = []corev1.Volume{
+				{
+					Name: resources.TunnelingAgentTokenSecretName,
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: resources.TunnelingAgentTokenSecretName,
+						},
+					},
+				},
+			}
+
+			if err := resources.SetResourceRequirements(ds.Spec.Template.Spec.Containers, defaultResourceRequirements, nil, ds.Annotations); err != nil {
+				return nil, fmt.Errorf("failed to set resource requirements: %v", err)
+			}
+
+			return ds, nil
+		}
+	}
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnelingagent
+
+import (
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/reconciling"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TokenSecretCreator returns the function to create the secret containing the
+// token used by the tunneling agents, copied from the cluster namespace.
+func TokenSecretCreator(token []byte) reconciling.NamedSecretCreatorGetter {
+	return func() (string, reconciling.SecretCreator) {
+		return resources.TunnelingAgentTokenSecretName, func(se *corev1.Secret) (*corev1.Secret, error) {
+			se.Data = map[string][]byte{
+				resources.TunnelingAgentTokenSecretKey: token,
+			}
+			return se, nil
+		}
+	}
+}
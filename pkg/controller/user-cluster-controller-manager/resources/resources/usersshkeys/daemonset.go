@@ -38,7 +38,9 @@ var (
 	hostPathType            = corev1.HostPathDirectoryOrCreate
 )
 
-func DaemonSetCreator(versions kubermatic.Versions) reconciling.NamedDaemonSetCreatorGetter {
+// DaemonSetCreator returns the DaemonSet of the agent. Only if the ssh certificate authority of the project is enabled,
+// the agent gets access to the sshd config and the processes of the nodes, as it has to make sshd trust the authority.
+func DaemonSetCreator(versions kubermatic.Versions, certificateAuthorityEnabled bool) reconciling.NamedDaemonSetCreatorGetter {
 	return func() (string, reconciling.DaemonSetCreator) {
 		return daemonSetName, func(ds *appsv1.DaemonSet) (*appsv1.DaemonSet, error) {
 			ds.Spec.UpdateStrategy = appsv1.DaemonSetUpdateStrategy{
@@ -52,6 +54,8 @@ func DaemonSetCreator(versions kubermatic.Versions) reconciling.NamedDaemonSetCr
 			ds.Spec.Template.ObjectMeta.Labels = labels
 
 			ds.Spec.Template.Spec.ServiceAccountName = serviceAccountName
+			// The agent reloads sshd when it makes it trust the ssh certificate authority of the project.
+			ds.Spec.Template.Spec.HostPID = certificateAuthorityEnabled
 
 			ds.Spec.Template.Spec.Containers = []corev1.Container{
 				{
@@ -59,6 +63,18 @@ func DaemonSetCreator(versions kubermatic.Versions) reconciling.NamedDaemonSetCr
 					ImagePullPolicy: corev1.PullAlways,
 					Image:           fmt.Sprintf("%s:%s", dockerImage, versions.Kubermatic),
 					Command:         []string{fmt.Sprintf("/usr/local/bin/%v", daemonSetName)},
+					Args:            []string{"-node-name", "$(NODE_NAME)"},
+					Env: []corev1.EnvVar{
+						{
+							Name: "NODE_NAME",
+							ValueFrom: &corev1.EnvVarSource{
+								FieldRef: &corev1.ObjectFieldSelector{
+									FieldPath:  "spec.nodeName",
+									APIVersion: "v1",
+								},
+							},
+						},
+					},
 					VolumeMounts: []corev1.VolumeMount{
 						{
 							Name:      "root",
@@ -104,6 +120,24 @@ func DaemonSetCreator(versions kubermatic.Versions) reconciling.NamedDaemonSetCr
 				},
 			}
 
+			if certificateAuthorityEnabled {
+				container := &ds.Spec.Template.Spec.Containers[0]
+				container.Args = append(container.Args, "-sshd-config-dir", "/etc/ssh")
+				container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+					Name:      "sshd-config",
+					MountPath: "/etc/ssh",
+				})
+				ds.Spec.Template.Spec.Volumes = append(ds.Spec.Template.Spec.Volumes, corev1.Volume{
+					Name: "sshd-config",
+					VolumeSource: corev1.VolumeSource{
+						HostPath: &corev1.HostPathVolumeSource{
+							Path: "/etc/ssh",
+							Type: &hostPathType,
+						},
+					},
+				})
+			}
+
 			return ds, nil
 		}
 	}
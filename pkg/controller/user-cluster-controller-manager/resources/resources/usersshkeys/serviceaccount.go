@@ -21,12 +21,15 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
-	serviceAccountName = "user-ssh-keys-agent"
-	roleName           = "user-ssh-keys-agent"
-	roleBindingName    = "user-ssh-keys-agent"
+	serviceAccountName     = "user-ssh-keys-agent"
+	roleName               = "user-ssh-keys-agent"
+	roleBindingName        = "user-ssh-keys-agent"
+	clusterRoleName        = "system:kubermatic:user-ssh-keys-agent"
+	clusterRoleBindingName = "system:kubermatic:user-ssh-keys-agent"
 )
 
 func ServiceAccountCreator() reconciling.NamedServiceAccountCreatorGetter {
@@ -75,3 +78,45 @@ func RoleBindingCreator() reconciling.NamedRoleBindingCreatorGetter {
 		}
 	}
 }
+
+// ClusterRoleCreator returns the ClusterRole allowing the agent to record the
+// changes of the authorized keys as events on its node.
+func ClusterRoleCreator() reconciling.NamedClusterRoleCreatorGetter {
+	return func() (string, reconciling.ClusterRoleCreator) {
+		return clusterRoleName, func(cr *rbacv1.ClusterRole) (*rbacv1.ClusterRole, error) {
+			cr.Rules = []rbacv1.PolicyRule{
+				{
+					APIGroups: []string{""},
+					Resources: []string{"nodes"},
+					Verbs:     []string{"get"},
+				},
+				{
+					APIGroups: []string{""},
+					Resources: []string{"events"},
+					Verbs:     []string{"create", "patch"},
+				},
+			}
+			return cr, nil
+		}
+	}
+}
+
+func ClusterRoleBindingCreator() reconciling.NamedClusterRoleBindingCreatorGetter {
+	return func() (string, reconciling.ClusterRoleBindingCreator) {
+		return clusterRoleBindingName, func(crb *rbacv1.ClusterRoleBinding) (*rbacv1.ClusterRoleBinding, error) {
+			crb.RoleRef = rbacv1.RoleRef{
+				Name:     clusterRoleName,
+				Kind:     "ClusterRole",
+				APIGroup: rbacv1.GroupName,
+			}
+			crb.Subjects = []rbacv1.Subject{
+				{
+					Name:      serviceAccountName,
+					Kind:      rbacv1.ServiceAccountKind,
+					Namespace: metav1.NamespaceSystem,
+				},
+			}
+			return crb, nil
+		}
+	}
+}
@@ -20,6 +20,13 @@ on all usercluster nodes and responsible for synchronizing the `$HOME/.ssh/autho
 for all users we know about (root, core, ubuntu, centos) and that exist with the content of a
 secret.
 
+If the project of the cluster enabled an ssh certificate authority, the secret holds its public key
+instead of the keys and the agent configures sshd to trust the certificates it signs.
+
+Keys targeting a specific user are only written to the file of that user and expired keys are
+dropped as soon as they expire. Every change of the keys authorized for a user is recorded as an
+event on the node.
+
 This secret in turn is synchronized based on a secret in the seed namespace via a controller running
 in the usercluster controller manager and that seed namespace secret is synchronized based on the
 usersshkeys custom resources in the master cluster via a controller running in the master controller
@@ -0,0 +1,202 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usersshkeysagent
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	kubermaticssh "k8c.io/kubermatic/v2/pkg/ssh"
+)
+
+const (
+	sshdConfigFile            = "sshd_config"
+	trustedUserCAKeysFile     = "kubermatic-user-ca.pub"
+	authorizedPrincipalsDir   = "kubermatic-principals"
+	sshdConfigBlockBeginsWith = "# BEGIN kubermatic ssh certificate authority"
+	sshdConfigBlockEndsWith   = "# END kubermatic ssh certificate authority"
+)
+
+// updateCertificateAuthority makes sshd trust the certificates signed by the certificate authority and
+// authorize their principals for the users. If the public key is empty, sshd stops trusting any authority.
+// sshd is reloaded if its config changed, the other files are read on every login.
+func (r *Reconciler) updateCertificateAuthority(publicKey []byte, users []string) error {
+	configPath := filepath.Join(r.sshdConfigDir, sshdConfigFile)
+	caPath := filepath.Join(r.sshdConfigDir, trustedUserCAKeysFile)
+	principalsPath := filepath.Join(r.sshdConfigDir, authorizedPrincipalsDir)
+
+	if len(publicKey) > 0 {
+		if err := writeFileIfChanged(caPath, publicKey); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(principalsPath, 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %v", principalsPath, err)
+		}
+		for _, user := range users {
+			principals := strings.Join(kubermaticssh.AuthorizedPrincipals(user), "\n") + "\n"
+			if err := writeFileIfChanged(filepath.Join(principalsPath, user), []byte(principals)); err != nil {
+				return err
+			}
+		}
+	}
+
+	info, err := os.Lstat(configPath)
+	switch {
+	case err != nil && !os.IsNotExist(err):
+		return fmt.Errorf("failed describing file info: %v", err)
+	case err == nil && info.Mode().IsRegular():
+		if err := r.updateSSHDConfig(configPath, info.Mode().Perm(), len(publicKey) > 0, caPath, principalsPath); err != nil {
+			return err
+		}
+	case len(publicKey) > 0:
+		// Flatcar links the config to a read-only file for example.
+		return fmt.Errorf("%s is not a regular file, replace it with a copy of its target to use the ssh certificate authority", configPath)
+	}
+
+	if len(publicKey) == 0 {
+		if err := os.RemoveAll(principalsPath); err != nil {
+			return fmt.Errorf("failed to remove directory %s: %v", principalsPath, err)
+		}
+		if err := os.Remove(caPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove file %s: %v", caPath, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Reconciler) updateSSHDConfig(path string, perm os.FileMode, trustCertificateAuthority bool, caPath, principalsPath string) error {
+	actualConfig, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed reading file in path %s: %v", path, err)
+	}
+
+	expectedConfig := sshdConfig(actualConfig, trustCertificateAuthority, caPath, principalsPath)
+	if bytes.Equal(actualConfig, expectedConfig) {
+		return nil
+	}
+	if err := ioutil.WriteFile(path, expectedConfig, perm); err != nil {
+		return fmt.Errorf("failed to overwrite file in path %s: %v", path, err)
+	}
+	r.log.Infow("File has been updated successfully", "file", path)
+
+	if err := r.reloadSSHD(); err != nil {
+		return fmt.Errorf("failed to reload sshd: %v", err)
+	}
+	return nil
+}
+
+// sshdConfig returns the sshd config with or without the block trusting the certificate authority. The block is
+// put at the top of the config, as sshd uses the first value of every keyword and the block must not end up in
+// a Match section.
+func sshdConfig(config []byte, trustCertificateAuthority bool, caPath, principalsPath string) []byte {
+	buffer := &bytes.Buffer{}
+	if trustCertificateAuthority {
+		fmt.Fprintln(buffer, sshdConfigBlockBeginsWith)
+		fmt.Fprintf(buffer, "TrustedUserCAKeys %s\n", caPath)
+		fmt.Fprintf(buffer, "AuthorizedPrincipalsFile %s\n", filepath.Join(principalsPath, "%u"))
+		fmt.Fprintln(buffer, sshdConfigBlockEndsWith)
+	}
+
+	inBlock := false
+	for _, line := range strings.SplitAfter(string(config), "\n") {
+		switch strings.TrimSpace(line) {
+		case sshdConfigBlockBeginsWith:
+			inBlock = true
+			continue
+		case sshdConfigBlockEndsWith:
+			inBlock = false
+			continue
+		}
+		if !inBlock {
+			buffer.WriteString(line)
+		}
+	}
+	return buffer.Bytes()
+}
+
+func writeFileIfChanged(path string, data []byte) error {
+	actual, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed reading file in path %s: %v", path, err)
+	}
+	if err == nil && bytes.Equal(actual, data) {
+		return nil
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write file in path %s: %v", path, err)
+	}
+	return nil
+}
+
+// signalSSHD sends SIGHUP to the listening sshd daemons of the host, which makes them re-read their config.
+// Daemons started per connection, as with socket activation, read the config anyway.
+func signalSSHD(procPath string) error {
+	pids, err := sshdListeners(procPath)
+	if err != nil {
+		return err
+	}
+	for _, pid := range pids {
+		if err := syscall.Kill(pid, syscall.SIGHUP); err != nil {
+			return fmt.Errorf("failed to send SIGHUP to sshd with pid %d: %v", pid, err)
+		}
+	}
+	return nil
+}
+
+// sshdListeners returns the pids of the sshd processes whose parent is not sshd, the ones of the sessions are
+// children of them.
+func sshdListeners(procPath string) ([]int, error) {
+	entries, err := ioutil.ReadDir(procPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %v", err)
+	}
+
+	isSSHD := func(pid int) bool {
+		comm, err := ioutil.ReadFile(filepath.Join(procPath, strconv.Itoa(pid), "comm"))
+		return err == nil && strings.TrimSpace(string(comm)) == "sshd"
+	}
+
+	var pids []int
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || !entry.IsDir() || !isSSHD(pid) {
+			continue
+		}
+		stat, err := ioutil.ReadFile(filepath.Join(procPath, entry.Name(), "stat"))
+		if err != nil {
+			// The process is gone already.
+			continue
+		}
+		// The command name in the second field may contain spaces and parentheses, the parent pid is the
+		// second field after it.
+		fields := strings.Fields(string(stat[bytes.LastIndexByte(stat, ')')+1:]))
+		if len(fields) < 2 {
+			continue
+		}
+		if ppid, err := strconv.Atoi(fields[1]); err == nil && !isSSHD(ppid) {
+			pids = append(pids, pid)
+		}
+	}
+	return pids, nil
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usersshkeysagent
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+)
+
+func TestUpdateCertificateAuthority(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "sshd")
+	if err != nil {
+		t.Fatalf("error while creating test base dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	const originalConfig = "PasswordAuthentication no\n\nMatch User backup\n  ForceCommand /usr/bin/backup\n"
+	configPath := filepath.Join(tmpDir, sshdConfigFile)
+	if err := ioutil.WriteFile(configPath, []byte(originalConfig), 0600); err != nil {
+		t.Fatalf("error while creating sshd_config: %v", err)
+	}
+
+	reloads := 0
+	reconciler := &Reconciler{
+		log:           kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
+		sshdConfigDir: tmpDir,
+		reloadSSHD: func() error {
+			reloads++
+			return nil
+		},
+	}
+
+	caPublicKey, _ := generatePublicKey(t)
+	for i := 0; i < 2; i++ {
+		if err := reconciler.updateCertificateAuthority([]byte(caPublicKey+"\n"), []string{"root", "ubuntu"}); err != nil {
+			t.Fatalf("failed to update the certificate authority: %v", err)
+		}
+	}
+	if reloads != 1 {
+		t.Errorf("expected sshd to be reloaded once, got %d reloads", reloads)
+	}
+
+	expectedFiles := map[string]string{
+		sshdConfigFile: sshdConfigBlockBeginsWith + "\n" +
+			"TrustedUserCAKeys " + filepath.Join(tmpDir, trustedUserCAKeysFile) + "\n" +
+			"AuthorizedPrincipalsFile " + filepath.Join(tmpDir, authorizedPrincipalsDir, "%u") + "\n" +
+			sshdConfigBlockEndsWith + "\n" + originalConfig,
+		trustedUserCAKeysFile:                            caPublicKey + "\n",
+		filepath.Join(authorizedPrincipalsDir, "root"):   "kubermatic-owners\n",
+		filepath.Join(authorizedPrincipalsDir, "ubuntu"): "kubermatic-owners\nkubermatic-editors\n",
+	}
+	for name, expected := range expectedFiles {
+		content, err := ioutil.ReadFile(filepath.Join(tmpDir, name))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", name, err)
+		}
+		if string(content) != expected {
+			t.Errorf("unexpected content of %s.\nexpected: %q\ngot:      %q", name, expected, string(content))
+		}
+	}
+
+	// Without a certificate authority the original config is restored.
+	if err := reconciler.updateCertificateAuthority(nil, []string{"root", "ubuntu"}); err != nil {
+		t.Fatalf("failed to remove the certificate authority: %v", err)
+	}
+	if reloads != 2 {
+		t.Errorf("expected sshd to be reloaded again, got %d reloads", reloads)
+	}
+	content, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != originalConfig {
+		t.Errorf("expected the original config to be restored, got %q", string(content))
+	}
+	for _, name := range []string{trustedUserCAKeysFile, authorizedPrincipalsDir} {
+		if _, err := os.Stat(filepath.Join(tmpDir, name)); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed, got %v", name, err)
+		}
+	}
+}
+
+func TestSSHDListeners(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "proc")
+	if err != nil {
+		t.Fatalf("error while creating test base dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	processes := []struct {
+		pid  string
+		comm string
+		ppid string
+	}{
+		{pid: "1", comm: "systemd", ppid: "0"},
+		{pid: "100", comm: "sshd", ppid: "1"},
+		{pid: "200", comm: "sshd", ppid: "100"},
+		{pid: "201", comm: "bash", ppid: "200"},
+		{pid: "300", comm: "sshd", ppid: "1"},
+	}
+	for _, process := range processes {
+		dir := filepath.Join(tmpDir, process.pid)
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, "comm"), []byte(process.comm+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		stat := fmt.Sprintf("%s (%s) S %s 1 1 0 -1\n", process.pid, process.comm, process.ppid)
+		if err := ioutil.WriteFile(filepath.Join(dir, "stat"), []byte(stat), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pids, err := sshdListeners(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to find the sshd listeners: %v", err)
+	}
+	if expected := []int{100, 300}; !reflect.DeepEqual(pids, expected) {
+		t.Errorf("expected the listeners %v, got %v", expected, pids)
+	}
+}
@@ -19,14 +19,18 @@ package usersshkeysagent
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"syscall"
+	"time"
 
 	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
 	"gopkg.in/fsnotify.v1"
 
 	predicateutil "k8c.io/kubermatic/v2/pkg/controller/util/predicate"
@@ -36,6 +40,8 @@ import (
 	kubeapierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -47,6 +53,10 @@ import (
 
 const (
 	operatorName = "usersshkeys-controller"
+
+	// authorizedKeysChangedReason is the reason of the events recorded on the
+	// node when the keys authorized for one of its users change.
+	authorizedKeysChangedReason = "AuthorizedKeysChanged"
 )
 
 type Reconciler struct {
@@ -54,17 +64,40 @@ type Reconciler struct {
 	log                *zap.SugaredLogger
 	authorizedKeysPath []string
 	events             chan event.GenericEvent
+	nodeName           string
+	// apiReader reads the node, which is outside of the namespace of the cache.
+	apiReader ctrlruntimeclient.Reader
+	recorder  record.EventRecorder
+	// sshdConfigDir is the directory of the sshd config, the certificate authority is
+	// not configured if it is empty.
+	sshdConfigDir string
+	reloadSSHD    func() error
+}
+
+// userSSHKey is a key of the user ssh keys secret.
+type userSSHKey struct {
+	resources.UserSSHKeyMetadata
+
+	name      string
+	publicKey []byte
 }
 
 func Add(
 	mgr manager.Manager,
 	log *zap.SugaredLogger,
-	authorizedKeysPaths []string) error {
+	authorizedKeysPaths []string,
+	nodeName string,
+	sshdConfigDir string) error {
 	reconciler := &Reconciler{
 		Client:             mgr.GetClient(),
 		log:                log,
 		authorizedKeysPath: authorizedKeysPaths,
 		events:             make(chan event.GenericEvent),
+		nodeName:           nodeName,
+		apiReader:          mgr.GetAPIReader(),
+		recorder:           mgr.GetEventRecorderFor(operatorName),
+		sshdConfigDir:      sshdConfigDir,
+		reloadSSHD:         func() error { return signalSSHD("/proc") },
 	}
 
 	c, err := controller.New(operatorName, mgr, controller.Options{Reconciler: reconciler})
@@ -108,12 +141,43 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 		return reconcile.Result{}, fmt.Errorf("failed to fetch user ssh keys: %v", err)
 	}
 
-	if err := r.updateAuthorizedKeys(secret.Data); err != nil {
+	keys, err := parseUserSSHKeys(secret.Data)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to parse user ssh keys: %v", err)
+	}
+
+	caPublicKey := secret.Data[resources.UserSSHKeysCertificateAuthorityKey]
+	if r.sshdConfigDir != "" {
+		users := make([]string, 0, len(r.authorizedKeysPath))
+		for _, path := range r.authorizedKeysPath {
+			users = append(users, userFromPath(path))
+		}
+		if err := r.updateCertificateAuthority(caPublicKey, users); err != nil {
+			r.log.Errorw("Failed reconciling the ssh certificate authority", zap.Error(err))
+			return reconcile.Result{}, fmt.Errorf("failed to reconcile the ssh certificate authority: %v", err)
+		}
+	} else if len(caPublicKey) > 0 {
+		r.log.Warn("Not trusting the ssh certificate authority as the sshd config directory is unknown")
+	}
+
+	now := time.Now()
+	if err := r.updateAuthorizedKeys(ctx, keys, now); err != nil {
 		r.log.Errorw("Failed reconciling user ssh key secret", zap.Error(err))
 		return reconcile.Result{}, fmt.Errorf("failed to reconcile user ssh keys: %v", err)
 	}
 
-	return reconcile.Result{}, nil
+	// Come back when the next key expires to remove it from the files.
+	result := reconcile.Result{}
+	for _, key := range keys {
+		if key.ExpiresAt == nil || !now.Before(key.ExpiresAt.Time) {
+			continue
+		}
+		if after := key.ExpiresAt.Sub(now); result.RequeueAfter == 0 || after < result.RequeueAfter {
+			result.RequeueAfter = after
+		}
+	}
+
+	return result, nil
 }
 
 func (r *Reconciler) watchAuthorizedKeys(ctx context.Context, paths []string) error {
@@ -165,13 +229,11 @@ func (r *Reconciler) fetchUserSSHKeySecret(ctx context.Context, namespace string
 	return secret, nil
 }
 
-func (r *Reconciler) updateAuthorizedKeys(sshKeys map[string][]byte) error {
-	expectedUserSSHKeys, err := createBuffer(sshKeys)
-	if err != nil {
-		return fmt.Errorf("failed creating user ssh keys buffer: %v", err)
-	}
-
+func (r *Reconciler) updateAuthorizedKeys(ctx context.Context, keys []userSSHKey, now time.Time) error {
 	for _, path := range r.authorizedKeysPath {
+		user := userFromPath(path)
+		expectedUserSSHKeys := authorizedKeys(keys, user, now)
+
 		if err := updateOwnAndPermissions(path); err != nil {
 			return fmt.Errorf("failed updating permissions %s: %v", path, err)
 		}
@@ -181,38 +243,144 @@ func (r *Reconciler) updateAuthorizedKeys(sshKeys map[string][]byte) error {
 			return fmt.Errorf("failed reading file in path %s: %v", path, err)
 		}
 
-		if !bytes.Equal(actualUserSSHKeys, expectedUserSSHKeys.Bytes()) {
-			if err := ioutil.WriteFile(path, expectedUserSSHKeys.Bytes(), 0600); err != nil {
+		if !bytes.Equal(actualUserSSHKeys, expectedUserSSHKeys) {
+			if err := ioutil.WriteFile(path, expectedUserSSHKeys, 0600); err != nil {
 				return fmt.Errorf("failed to overwrite file in path %s: %v", path, err)
 			}
 			r.log.Infow("File has been updated successfully", "file", path)
+			r.recordChange(ctx, keys, user, actualUserSSHKeys, expectedUserSSHKeys)
 		}
 	}
 
 	return nil
 }
 
-func createBuffer(data map[string][]byte) (*bytes.Buffer, error) {
-	var (
-		keys   = make([]string, 0, len(data))
-		buffer = &bytes.Buffer{}
-	)
+// recordChange records an event on the node listing the keys added to and
+// removed from the authorized keys of the user.
+func (r *Reconciler) recordChange(ctx context.Context, keys []userSSHKey, user string, actual, expected []byte) {
+	added, removed := diffAuthorizedKeys(keys, actual, expected)
+	if len(added) == 0 && len(removed) == 0 {
+		return
+	}
+	if r.nodeName == "" {
+		r.log.Debugw("Not recording the change of the authorized keys as the node name is unknown", "user", user)
+		return
+	}
 
-	for key := range data {
-		keys = append(keys, key)
+	node := &corev1.Node{}
+	if err := r.apiReader.Get(ctx, types.NamespacedName{Name: r.nodeName}, node); err != nil {
+		r.log.Errorw("Failed to get node to record the change of the authorized keys", "node", r.nodeName, zap.Error(err))
+		return
 	}
 
-	sort.Strings(keys)
+	r.recorder.Eventf(node, corev1.EventTypeNormal, authorizedKeysChangedReason,
+		"Updated the authorized keys of user %s: added %s; removed %s", user, describeKeys(added), describeKeys(removed))
+}
+
+// parseUserSSHKeys returns the keys of the user ssh keys secret sorted by name.
+func parseUserSSHKeys(data map[string][]byte) ([]userSSHKey, error) {
+	metadata := map[string]resources.UserSSHKeyMetadata{}
+	if rawMetadata, ok := data[resources.UserSSHKeysMetadataKey]; ok {
+		if err := json.Unmarshal(rawMetadata, &metadata); err != nil {
+			return nil, fmt.Errorf("failed to decode the metadata of the keys: %v", err)
+		}
+	}
 
-	for k := range keys {
-		key := keys[k]
-		data[key] = append(data[key], []byte("\n")...)
-		if _, err := buffer.Write(data[key]); err != nil {
-			return nil, fmt.Errorf("failed writing user ssh keys to buffer: %v", err)
+	keys := make([]userSSHKey, 0, len(data))
+	for name, publicKey := range data {
+		// The metadata and the certificate authority are no keys.
+		if strings.HasPrefix(name, ".") {
+			continue
 		}
+		keys = append(keys, userSSHKey{
+			UserSSHKeyMetadata: metadata[name],
+			name:               name,
+			publicKey:          publicKey,
+		})
 	}
 
-	return buffer, nil
+	sort.Slice(keys, func(i, j int) bool { return keys[i].name < keys[j].name })
+
+	return keys, nil
+}
+
+// authorizedFor tells if the key is authorized for the user at the given time.
+func (k *userSSHKey) authorizedFor(user string, now time.Time) bool {
+	if k.User != "" && k.User != user {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(k.ExpiresAt.Time)
+}
+
+// authorizedKeys returns the content of the authorized_keys file of the user.
+func authorizedKeys(keys []userSSHKey, user string, now time.Time) []byte {
+	buffer := &bytes.Buffer{}
+	for _, key := range keys {
+		if !key.authorizedFor(user, now) {
+			continue
+		}
+		buffer.Write(key.publicKey)
+		buffer.WriteString("\n")
+	}
+	return buffer.Bytes()
+}
+
+// diffAuthorizedKeys returns the descriptions of the keys added to and
+// removed from an authorized_keys file. Keys still found in the secret are
+// described by their name along with their fingerprint.
+func diffAuthorizedKeys(keys []userSSHKey, actual, expected []byte) ([]string, []string) {
+	names := map[string]string{}
+	for _, key := range keys {
+		names[strings.TrimSpace(string(key.publicKey))] = key.name
+	}
+
+	actualLines := authorizedKeysLines(actual)
+	expectedLines := authorizedKeysLines(expected)
+
+	var added, removed []string
+	for _, line := range expectedLines.Difference(actualLines).List() {
+		added = append(added, describeKey(line, names[line]))
+	}
+	for _, line := range actualLines.Difference(expectedLines).List() {
+		removed = append(removed, describeKey(line, names[line]))
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+func authorizedKeysLines(data []byte) sets.String {
+	lines := sets.NewString()
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			lines.Insert(line)
+		}
+	}
+	return lines
+}
+
+func describeKey(line, name string) string {
+	description := "invalid key"
+	if publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(line)); err == nil {
+		description = ssh.FingerprintSHA256(publicKey)
+	}
+	if name != "" {
+		description = fmt.Sprintf("%s (%s)", name, description)
+	}
+	return description
+}
+
+func describeKeys(descriptions []string) string {
+	if len(descriptions) == 0 {
+		return "none"
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+// userFromPath returns the name of the user owning the authorized_keys file,
+// which is the name of its home directory.
+func userFromPath(path string) string {
+	return filepath.Base(filepath.Dir(filepath.Dir(path)))
 }
 
 func updateOwnAndPermissions(path string) error {
@@ -18,12 +18,18 @@ package usersshkeysagent
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
 
 	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
 	"k8c.io/kubermatic/v2/pkg/resources"
@@ -31,6 +37,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -158,6 +165,137 @@ func TestReconcileUserSSHKeys(t *testing.T) {
 	}
 }
 
+func TestReconcileUserSSHKeysMetadata(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "sshkeys")
+	if err != nil {
+		t.Fatalf("error while creating test base dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	paths := map[string]string{}
+	for _, user := range []string{"root", "ubuntu"} {
+		sshPath := filepath.Join(tmpDir, user, ".ssh")
+		if err := os.MkdirAll(sshPath, 0700); err != nil {
+			t.Fatalf("error while creating .ssh dir: %v", err)
+		}
+		paths[user] = filepath.Join(sshPath, "authorized_keys")
+		// A key added manually, which has to be removed.
+		if err := ioutil.WriteFile(paths[user], []byte("ssh-rsa manually_added_key\n"), 0600); err != nil {
+			t.Fatalf("error while creating authorized_keys file: %v", err)
+		}
+	}
+
+	allUsersKey, allUsersFingerprint := generatePublicKey(t)
+	ubuntuKey, ubuntuFingerprint := generatePublicKey(t)
+	expiredKey, _ := generatePublicKey(t)
+	expiringKey, _ := generatePublicKey(t)
+
+	now := time.Now()
+	expired := metav1.NewTime(now.Add(-time.Minute))
+	expiring := metav1.NewTime(now.Add(time.Hour))
+	metadata, err := json.Marshal(map[string]resources.UserSSHKeyMetadata{
+		"key-ubuntu":   {User: "ubuntu"},
+		"key-expired":  {ExpiresAt: &expired},
+		"key-expiring": {ExpiresAt: &expiring, User: "root"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := ctrlruntimefakeclient.NewClientBuilder().WithObjects(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      resources.UserSSHKeys,
+				Namespace: metav1.NamespaceSystem,
+			},
+			Data: map[string][]byte{
+				"key-all":                        []byte(allUsersKey),
+				"key-ubuntu":                     []byte(ubuntuKey),
+				"key-expired":                    []byte(expiredKey),
+				"key-expiring":                   []byte(expiringKey),
+				resources.UserSSHKeysMetadataKey: metadata,
+			},
+		},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+	).Build()
+	recorder := record.NewFakeRecorder(10)
+
+	reconciler := Reconciler{
+		Client:             client,
+		log:                kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(),
+		authorizedKeysPath: []string{paths["root"], paths["ubuntu"]},
+		nodeName:           "node-1",
+		apiReader:          client,
+		recorder:           recorder,
+	}
+
+	result, err := reconciler.Reconcile(context.Background(), reconcile.Request{
+		NamespacedName: types.NamespacedName{Name: resources.UserSSHKeys, Namespace: metav1.NamespaceSystem}})
+	if err != nil {
+		t.Fatalf("failed to run reconcile: %v", err)
+	}
+	if result.RequeueAfter <= 0 || result.RequeueAfter > time.Hour {
+		t.Errorf("expected a requeue before the next key expires, got %v", result.RequeueAfter)
+	}
+
+	expectedKeys := map[string]string{
+		"root":   allUsersKey + "\n" + expiringKey,
+		"ubuntu": allUsersKey + "\n" + ubuntuKey,
+	}
+	for user, expected := range expectedKeys {
+		keys, err := readAuthorizedKeysFile(paths[user])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if keys != expected {
+			t.Errorf("unexpected authorized keys of user %s.\nexpected: %q\ngot:      %q", user, expected, keys)
+		}
+	}
+
+	var events []string
+	for len(recorder.Events) > 0 {
+		events = append(events, <-recorder.Events)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected an event per user, got %v", events)
+	}
+	for _, expected := range []string{
+		fmt.Sprintf("added key-all (%s), key-ubuntu (%s); removed invalid key", allUsersFingerprint, ubuntuFingerprint),
+		"Updated the authorized keys of user root:",
+	} {
+		found := false
+		for _, event := range events {
+			if strings.Contains(event, expected) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected an event containing %q, got %v", expected, events)
+		}
+	}
+
+	// Once the keys are in place nothing changes anymore.
+	if _, err := reconciler.Reconcile(context.Background(), reconcile.Request{
+		NamespacedName: types.NamespacedName{Name: resources.UserSSHKeys, Namespace: metav1.NamespaceSystem}}); err != nil {
+		t.Fatalf("failed to run reconcile: %v", err)
+	}
+	if len(recorder.Events) != 0 {
+		t.Errorf("expected no event when the keys are unchanged, got %q", <-recorder.Events)
+	}
+}
+
+func generatePublicKey(t *testing.T) (string, string) {
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	sshPublicKey, err := ssh.NewPublicKey(publicKey)
+	if err != nil {
+		t.Fatalf("failed to convert key: %v", err)
+	}
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPublicKey))), ssh.FingerprintSHA256(sshPublicKey)
+}
+
 func readAuthorizedKeysFile(path string) (string, error) {
 	data, err := ioutil.ReadFile(path)
 	if err != nil {
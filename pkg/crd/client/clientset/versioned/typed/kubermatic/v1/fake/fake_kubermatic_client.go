@@ -28,6 +28,10 @@ func (c *FakeKubermaticV1) Clusters() v1.ClusterInterface {
 	return &FakeClusters{c}
 }
 
+func (c *FakeKubermaticV1) ClusterTemplates() v1.ClusterTemplateInterface {
+	return &FakeClusterTemplates{c}
+}
+
 func (c *FakeKubermaticV1) Constraints(namespace string) v1.ConstraintInterface {
 	return &FakeConstraints{c, namespace}
 }
@@ -48,6 +52,10 @@ func (c *FakeKubermaticV1) ExternalClusters() v1.ExternalClusterInterface {
 	return &FakeExternalClusters{c}
 }
 
+func (c *FakeKubermaticV1) GroupProjectBindings() v1.GroupProjectBindingInterface {
+	return &FakeGroupProjectBindings{c}
+}
+
 func (c *FakeKubermaticV1) KubermaticSettings() v1.KubermaticSettingInterface {
 	return &FakeKubermaticSettings{c}
 }
@@ -56,6 +64,10 @@ func (c *FakeKubermaticV1) Projects() v1.ProjectInterface {
 	return &FakeProjects{c}
 }
 
+func (c *FakeKubermaticV1) ProjectRoles() v1.ProjectRoleInterface {
+	return &FakeProjectRoles{c}
+}
+
 func (c *FakeKubermaticV1) Users() v1.UserInterface {
 	return &FakeUsers{c}
 }
@@ -10,6 +10,8 @@ type AlertmanagerExpansion interface{}
 
 type ClusterExpansion interface{}
 
+type ClusterTemplateExpansion interface{}
+
 type ConstraintExpansion interface{}
 
 type ConstraintTemplateExpansion interface{}
@@ -20,10 +22,14 @@ type EtcdRestoreExpansion interface{}
 
 type ExternalClusterExpansion interface{}
 
+type GroupProjectBindingExpansion interface{}
+
 type KubermaticSettingExpansion interface{}
 
 type ProjectExpansion interface{}
 
+type ProjectRoleExpansion interface{}
+
 type UserExpansion interface{}
 
 type UserProjectBindingExpansion interface{}
@@ -14,13 +14,16 @@ type KubermaticV1Interface interface {
 	AddonConfigsGetter
 	AlertmanagersGetter
 	ClustersGetter
+	ClusterTemplatesGetter
 	ConstraintsGetter
 	ConstraintTemplatesGetter
 	EtcdBackupConfigsGetter
 	EtcdRestoresGetter
 	ExternalClustersGetter
+	GroupProjectBindingsGetter
 	KubermaticSettingsGetter
 	ProjectsGetter
+	ProjectRolesGetter
 	UsersGetter
 	UserProjectBindingsGetter
 	UserSSHKeysGetter
@@ -47,6 +50,10 @@ func (c *KubermaticV1Client) Clusters() ClusterInterface {
 	return newClusters(c)
 }
 
+func (c *KubermaticV1Client) ClusterTemplates() ClusterTemplateInterface {
+	return newClusterTemplates(c)
+}
+
 func (c *KubermaticV1Client) Constraints(namespace string) ConstraintInterface {
 	return newConstraints(c, namespace)
 }
@@ -67,6 +74,10 @@ func (c *KubermaticV1Client) ExternalClusters() ExternalClusterInterface {
 	return newExternalClusters(c)
 }
 
+func (c *KubermaticV1Client) GroupProjectBindings() GroupProjectBindingInterface {
+	return newGroupProjectBindings(c)
+}
+
 func (c *KubermaticV1Client) KubermaticSettings() KubermaticSettingInterface {
 	return newKubermaticSettings(c)
 }
@@ -75,6 +86,10 @@ func (c *KubermaticV1Client) Projects() ProjectInterface {
 	return newProjects(c)
 }
 
+func (c *KubermaticV1Client) ProjectRoles() ProjectRoleInterface {
+	return newProjectRoles(c)
+}
+
 func (c *KubermaticV1Client) Users() UserInterface {
 	return newUsers(c)
 }
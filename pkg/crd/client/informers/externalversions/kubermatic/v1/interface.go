@@ -16,6 +16,8 @@ type Interface interface {
 	Alertmanagers() AlertmanagerInformer
 	// Clusters returns a ClusterInformer.
 	Clusters() ClusterInformer
+	// ClusterTemplates returns a ClusterTemplateInformer.
+	ClusterTemplates() ClusterTemplateInformer
 	// Constraints returns a ConstraintInformer.
 	Constraints() ConstraintInformer
 	// ConstraintTemplates returns a ConstraintTemplateInformer.
@@ -26,10 +28,14 @@ type Interface interface {
 	EtcdRestores() EtcdRestoreInformer
 	// ExternalClusters returns a ExternalClusterInformer.
 	ExternalClusters() ExternalClusterInformer
+	// GroupProjectBindings returns a GroupProjectBindingInformer.
+	GroupProjectBindings() GroupProjectBindingInformer
 	// KubermaticSettings returns a KubermaticSettingInformer.
 	KubermaticSettings() KubermaticSettingInformer
 	// Projects returns a ProjectInformer.
 	Projects() ProjectInformer
+	// ProjectRoles returns a ProjectRoleInformer.
+	ProjectRoles() ProjectRoleInformer
 	// Users returns a UserInformer.
 	Users() UserInformer
 	// UserProjectBindings returns a UserProjectBindingInformer.
@@ -69,6 +75,11 @@ func (v *version) Clusters() ClusterInformer {
 	return &clusterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// ClusterTemplates returns a ClusterTemplateInformer.
+func (v *version) ClusterTemplates() ClusterTemplateInformer {
+	return &clusterTemplateInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // Constraints returns a ConstraintInformer.
 func (v *version) Constraints() ConstraintInformer {
 	return &constraintInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -94,6 +105,11 @@ func (v *version) ExternalClusters() ExternalClusterInformer {
 	return &externalClusterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// GroupProjectBindings returns a GroupProjectBindingInformer.
+func (v *version) GroupProjectBindings() GroupProjectBindingInformer {
+	return &groupProjectBindingInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // KubermaticSettings returns a KubermaticSettingInformer.
 func (v *version) KubermaticSettings() KubermaticSettingInformer {
 	return &kubermaticSettingInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
@@ -104,6 +120,11 @@ func (v *version) Projects() ProjectInformer {
 	return &projectInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// ProjectRoles returns a ProjectRoleInformer.
+func (v *version) ProjectRoles() ProjectRoleInformer {
+	return &projectRoleInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // Users returns a UserInformer.
 func (v *version) Users() UserInformer {
 	return &userInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
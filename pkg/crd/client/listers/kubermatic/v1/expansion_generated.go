@@ -26,6 +26,10 @@ type AlertmanagerNamespaceListerExpansion interface{}
 // ClusterLister.
 type ClusterListerExpansion interface{}
 
+// ClusterTemplateListerExpansion allows custom methods to be added to
+// ClusterTemplateLister.
+type ClusterTemplateListerExpansion interface{}
+
 // ConstraintListerExpansion allows custom methods to be added to
 // ConstraintLister.
 type ConstraintListerExpansion interface{}
@@ -58,6 +62,10 @@ type EtcdRestoreNamespaceListerExpansion interface{}
 // ExternalClusterLister.
 type ExternalClusterListerExpansion interface{}
 
+// GroupProjectBindingListerExpansion allows custom methods to be added to
+// GroupProjectBindingLister.
+type GroupProjectBindingListerExpansion interface{}
+
 // KubermaticSettingListerExpansion allows custom methods to be added to
 // KubermaticSettingLister.
 type KubermaticSettingListerExpansion interface{}
@@ -66,6 +74,10 @@ type KubermaticSettingListerExpansion interface{}
 // ProjectLister.
 type ProjectListerExpansion interface{}
 
+// ProjectRoleListerExpansion allows custom methods to be added to
+// ProjectRoleLister.
+type ProjectRoleListerExpansion interface{}
+
 // UserListerExpansion allows custom methods to be added to
 // UserLister.
 type UserListerExpansion interface{}
@@ -31,6 +31,9 @@ const (
 	AddonKindName = "Addon"
 
 	AddonResourcesCreated AddonConditionType = "AddonResourcesCreatedSuccessfully"
+	// AddonReady indicates whether all workloads of the addon (Deployments, DaemonSets,
+	// StatefulSets and CustomResourceDefinitions) are ready in the user cluster.
+	AddonReady AddonConditionType = "AddonReady"
 )
 
 //+genclient
@@ -60,6 +63,9 @@ type AddonSpec struct {
 	RequiredResourceTypes []schema.GroupVersionKind `json:"requiredResourceTypes,omitempty"`
 	// IsDefault indicates whether the addon is default
 	IsDefault bool `json:"isDefault,omitempty"`
+	// Version pins the addon to a version of the addon catalog. The addon is not updated
+	// while the catalog provides a different version. If empty, the addon follows the catalog.
+	Version string `json:"version,omitempty"`
 }
 
 // AddonList is a list of addons
@@ -73,6 +79,37 @@ type AddonList struct {
 
 type AddonStatus struct {
 	Conditions []AddonCondition `json:"conditions,omitempty"`
+	// Resources contains the result of applying each object of the addon manifests
+	// to the user cluster during the last reconciliation
+	Resources []AddonResourceStatus `json:"resources,omitempty"`
+	// Version is the catalog version of the addon which was applied during the last reconciliation
+	Version string `json:"version,omitempty"`
+}
+
+// AddonResourceAction is the action that was taken for an object of an addon
+type AddonResourceAction string
+
+const (
+	// AddonResourceCreated means the object did not exist and was created
+	AddonResourceCreated AddonResourceAction = "Created"
+	// AddonResourceUpdated means the existing object was changed
+	AddonResourceUpdated AddonResourceAction = "Updated"
+	// AddonResourceUnchanged means the existing object already matched the manifest
+	AddonResourceUnchanged AddonResourceAction = "Unchanged"
+	// AddonResourcePruned means the object was deleted because it is no longer part of the addon
+	AddonResourcePruned AddonResourceAction = "Pruned"
+	// AddonResourceFailed means the object could not be applied or pruned, see the message for details
+	AddonResourceFailed AddonResourceAction = "Failed"
+)
+
+// AddonResourceStatus is the result of applying a single object of an addon
+type AddonResourceStatus struct {
+	APIVersion string              `json:"apiVersion"`
+	Kind       string              `json:"kind"`
+	Namespace  string              `json:"namespace,omitempty"`
+	Name       string              `json:"name"`
+	Action     AddonResourceAction `json:"action"`
+	Message    string              `json:"message,omitempty"`
 }
 
 type AddonConditionType string
@@ -88,4 +125,7 @@ type AddonCondition struct {
 	// Last time the condition transit from one status to another.
 	// +optional
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Human readable message indicating details about the last transition.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
@@ -95,6 +95,10 @@ type ClusterSpec struct {
 	ClusterNetwork  ClusterNetworkingConfig   `json:"clusterNetwork"`
 	MachineNetworks []MachineNetworkingConfig `json:"machineNetworks,omitempty"`
 
+	// CNIPlugin selects the CNI plugin installed into the cluster. The type can not be changed
+	// after the cluster was created, the version can be upgraded.
+	CNIPlugin *CNIPluginSettings `json:"cniPlugin,omitempty"`
+
 	// Version defines the wanted version of the control plane
 	Version semver.Semver `json:"version"`
 	// MasterVersion is Deprecated
@@ -107,6 +111,10 @@ type ClusterSpec struct {
 	// or via a dedicated LoadBalancer
 	ExposeStrategy ExposeStrategy `json:"exposeStrategy"`
 
+	// Optional: NodeportProxyLimits restricts the resources the cluster can use on the nodeport-proxy
+	// of the seed. The limits that are not set default to the ones of the seed.
+	NodeportProxyLimits *NodeportProxyLimits `json:"nodeportProxyLimits,omitempty"`
+
 	// Pause tells that this cluster is currently not managed by the controller.
 	// It indicates that the user needs to do some action to resolve the pause.
 	Pause bool `json:"pause"`
@@ -125,6 +133,9 @@ type ClusterSpec struct {
 
 	UpdateWindow *UpdateWindow `json:"updateWindow,omitempty"`
 
+	// Hibernation scales the worker nodes and the control plane of the cluster to zero while it is not needed
+	Hibernation *HibernationSettings `json:"hibernation,omitempty"`
+
 	UsePodSecurityPolicyAdmissionPlugin bool `json:"usePodSecurityPolicyAdmissionPlugin,omitempty"`
 	UsePodNodeSelectorAdmissionPlugin   bool `json:"usePodNodeSelectorAdmissionPlugin,omitempty"`
 
@@ -180,9 +191,44 @@ const (
 // the `AllClusterConditionTypes` variable.
 type ClusterConditionType string
 
+// UpdateWindow restricts when automatic updates are applied to a cluster. The window opens at
+// Start or, for more complex recurrences, at every activation of Schedule and stays open for Length.
 type UpdateWindow struct {
-	Start  string `json:"start,omitempty"`
+	// Start is the time of day the window opens, optionally preceded by a weekday, e.g. "04:00" or "Thu 04:00"
+	Start string `json:"start,omitempty"`
+	// Length is the duration the window stays open, e.g. "1h"
 	Length string `json:"length,omitempty"`
+	// Schedule is a cron expression (minute, hour, day of month, month, day of week) at which the window opens,
+	// e.g. "0 2 * * 1-5". It can not be combined with Start and is not used for Flatcar reboots.
+	Schedule string `json:"schedule,omitempty"`
+	// TimeZone is the IANA time zone Start and Schedule are evaluated in, e.g. "Europe/Berlin". Defaults to UTC.
+	// It is not used for Flatcar reboots.
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// HibernationSettings control the hibernation of a cluster. A hibernated cluster has no worker nodes and
+// its control plane is scaled down, only its etcd volumes and cloud provider infrastructure are kept.
+type HibernationSettings struct {
+	// Hibernated requests the cluster to be hibernated, setting it to false resumes the cluster.
+	// It is changed by the schedule at each of its activations.
+	Hibernated bool `json:"hibernated,omitempty"`
+	// EtcdSnapshot takes a one-off etcd backup before the control plane is scaled down. Only the snapshot
+	// of the last hibernation is kept. It requires the etcd backup and restore controllers of the seed.
+	EtcdSnapshot bool `json:"etcdSnapshot,omitempty"`
+	// Schedule hibernates and resumes the cluster automatically
+	Schedule *HibernationSchedule `json:"schedule,omitempty"`
+}
+
+// HibernationSchedule hibernates and resumes a cluster at the activations of cron expressions (minute, hour,
+// day of month, month, day of week), e.g. hibernate "0 20 * * *" and resume "0 7 * * 1-5" to park the cluster
+// nightly and over the weekend. Either expression can be omitted to only hibernate or resume automatically.
+type HibernationSchedule struct {
+	// Hibernate is the cron expression at which the cluster is hibernated
+	Hibernate string `json:"hibernate,omitempty"`
+	// Resume is the cron expression at which the cluster is resumed
+	Resume string `json:"resume,omitempty"`
+	// TimeZone is the IANA time zone the expressions are evaluated in, e.g. "Europe/Berlin". Defaults to UTC.
+	TimeZone string `json:"timeZone,omitempty"`
 }
 
 const (
@@ -208,6 +254,16 @@ const (
 
 	ClusterConditionEtcdClusterInitialized ClusterConditionType = "EtcdClusterInitialized"
 
+	// ClusterConditionUpgradeHalted is set by the update controller when an automatic upgrade of the cluster
+	// stopped because one of its stages did not become healthy. Unlike the other conditions `true` indicates
+	// a problem, which is why it is not part of AllClusterConditionTypes.
+	ClusterConditionUpgradeHalted ClusterConditionType = "UpgradeHalted"
+
+	// ClusterConditionHibernated is set by the hibernation controller once a cluster was hibernated. It is
+	// `true` while the cluster is hibernated, otherwise its reason is the current hibernation phase and its
+	// message describes the progress of the phase. It is not part of AllClusterConditionTypes either.
+	ClusterConditionHibernated ClusterConditionType = "Hibernated"
+
 	// ClusterConditionNone is a special value indicating that no cluster condition should be set
 	ClusterConditionNone ClusterConditionType = ""
 	// This condition is met when a CSI migration is ongoing and the CSI
@@ -295,6 +351,135 @@ type ClusterStatus struct {
 
 	// InheritedLabels are labels the cluster inherited from the project. They are read-only for users.
 	InheritedLabels map[string]string `json:"inheritedLabels,omitempty"`
+
+	// InheritedUpdateWindow is the update window of the project of the cluster, it applies if the
+	// cluster has no update window of its own. It is read-only for users.
+	InheritedUpdateWindow *UpdateWindow `json:"inheritedUpdateWindow,omitempty"`
+
+	// Upgrade describes the progress of the last automatic upgrade of the cluster
+	Upgrade *ClusterUpgradeStatus `json:"upgrade,omitempty"`
+
+	// Hibernation describes the progress of the hibernation or resumption of the cluster
+	Hibernation *ClusterHibernationStatus `json:"hibernation,omitempty"`
+
+	// ResourceUsage is the amount of resources used by the cluster and its machine deployments, it is
+	// kept up to date by the user cluster controller manager
+	ResourceUsage *ResourceUsage `json:"resourceUsage,omitempty"`
+
+	// AdmittedMachineDeploymentReplicas are the replicas of the machine deployments of the cluster which have been
+	// admitted by the resource quota of the project, by the name of the machine deployment. They are recorded by the
+	// user cluster controller manager here, as the users can change the machine deployments in the user cluster.
+	AdmittedMachineDeploymentReplicas map[string]int64 `json:"admittedMachineDeploymentReplicas,omitempty"`
+}
+
+// ClusterHibernationPhase is a phase of the hibernation or resumption of a cluster. A cluster is hibernated by
+// passing through the phases from EtcdSnapshot to Hibernated and resumed from ResumingControlPlane to Running.
+type ClusterHibernationPhase string
+
+const (
+	// ClusterHibernationPhaseEtcdSnapshot takes the etcd snapshot, if one was requested
+	ClusterHibernationPhaseEtcdSnapshot ClusterHibernationPhase = "EtcdSnapshot"
+	// ClusterHibernationPhaseScalingDownMachineDeployments scales the MachineDeployments to zero and waits for the machines to be deleted
+	ClusterHibernationPhaseScalingDownMachineDeployments ClusterHibernationPhase = "ScalingDownMachineDeployments"
+	// ClusterHibernationPhaseScalingDownControlPlane scales the Deployments and StatefulSets of the control plane to zero
+	ClusterHibernationPhaseScalingDownControlPlane ClusterHibernationPhase = "ScalingDownControlPlane"
+	// ClusterHibernationPhaseHibernated indicates that the cluster is hibernated
+	ClusterHibernationPhaseHibernated ClusterHibernationPhase = "Hibernated"
+	// ClusterHibernationPhaseResumingControlPlane restores the control plane and waits for the apiserver to be healthy
+	ClusterHibernationPhaseResumingControlPlane ClusterHibernationPhase = "ResumingControlPlane"
+	// ClusterHibernationPhaseResumingMachineDeployments restores the MachineDeployments and waits for their machines to be available
+	ClusterHibernationPhaseResumingMachineDeployments ClusterHibernationPhase = "ResumingMachineDeployments"
+	// ClusterHibernationPhaseRunning indicates that the cluster was resumed
+	ClusterHibernationPhaseRunning ClusterHibernationPhase = "Running"
+
+	// HibernationReplicasAnnotation records the replicas of a MachineDeployment, Deployment or StatefulSet
+	// before it was scaled down by the hibernation, they are restored when the cluster is resumed.
+	HibernationReplicasAnnotation = "kubermatic.io/hibernation-replicas"
+)
+
+// ClusterHibernationStatus describes the progress of the hibernation or resumption of a cluster
+type ClusterHibernationStatus struct {
+	// Phase is the current phase of the hibernation or resumption
+	Phase ClusterHibernationPhase `json:"phase,omitempty"`
+	// PhaseStartTime is the time the current phase was entered
+	PhaseStartTime metav1.Time `json:"phaseStartTime,omitempty"`
+	// Message explains why the current phase has not finished yet
+	Message string `json:"message,omitempty"`
+	// ScheduleCheckedTime is the time up to which the activations of the hibernation schedule have been applied
+	ScheduleCheckedTime *metav1.Time `json:"scheduleCheckedTime,omitempty"`
+}
+
+// ControlPlaneHibernated returns whether the control plane is scaled down or being scaled down. The
+// controllers which reconcile the control plane leave the cluster alone during these phases.
+func (s *ClusterHibernationStatus) ControlPlaneHibernated() bool {
+	return s != nil && (s.Phase == ClusterHibernationPhaseScalingDownControlPlane || s.Phase == ClusterHibernationPhaseHibernated)
+}
+
+// ClusterUpgradeStage is a stage of an automatic cluster upgrade. The stages are passed in the order
+// in which the constants are declared.
+type ClusterUpgradeStage string
+
+const (
+	// ClusterUpgradeStagePreflight checks that the cluster can be upgraded, the version is not changed yet
+	ClusterUpgradeStagePreflight ClusterUpgradeStage = "Preflight"
+	// ClusterUpgradeStageEtcd changes the cluster version and waits for etcd to be rolled out and healthy,
+	// the apiserver, controller-manager and scheduler keep running the previous version meanwhile
+	ClusterUpgradeStageEtcd ClusterUpgradeStage = "Etcd"
+	// ClusterUpgradeStageApiserver rolls out the new version of the apiserver and waits for it to be healthy
+	ClusterUpgradeStageApiserver ClusterUpgradeStage = "Apiserver"
+	// ClusterUpgradeStageControllerManagerAndScheduler rolls out the new version of the controller-manager and scheduler
+	// and waits for them to be healthy
+	ClusterUpgradeStageControllerManagerAndScheduler ClusterUpgradeStage = "ControllerManagerAndScheduler"
+	// ClusterUpgradeStageMachineDeployments updates the machine deployments and waits for them to be rolled out
+	ClusterUpgradeStageMachineDeployments ClusterUpgradeStage = "MachineDeployments"
+	// ClusterUpgradeStageCompleted indicates that the upgrade finished successfully
+	ClusterUpgradeStageCompleted ClusterUpgradeStage = "Completed"
+)
+
+// clusterUpgradeStages are the stages of an automatic cluster upgrade in the order in which they are passed
+var clusterUpgradeStages = []ClusterUpgradeStage{
+	ClusterUpgradeStagePreflight,
+	ClusterUpgradeStageEtcd,
+	ClusterUpgradeStageApiserver,
+	ClusterUpgradeStageControllerManagerAndScheduler,
+	ClusterUpgradeStageMachineDeployments,
+	ClusterUpgradeStageCompleted,
+}
+
+// ClusterUpgradeStatus describes the progress of an automatic cluster upgrade
+type ClusterUpgradeStatus struct {
+	// FromVersion is the version of the cluster before the upgrade
+	FromVersion string `json:"fromVersion"`
+	// ToVersion is the version the cluster is upgraded to
+	ToVersion string `json:"toVersion"`
+	// Stage is the current stage of the upgrade
+	Stage ClusterUpgradeStage `json:"stage"`
+	// StageStartTime is the time the current stage was entered
+	StageStartTime metav1.Time `json:"stageStartTime"`
+	// Message explains why the current stage has not finished yet
+	Message string `json:"message,omitempty"`
+}
+
+// InProgress returns whether the upgrade has not been completed yet.
+func (s *ClusterUpgradeStatus) InProgress() bool {
+	return s != nil && s.Stage != ClusterUpgradeStageCompleted
+}
+
+// HasReached returns whether the upgrade has entered the given stage or a later one. It is true
+// for all stages if no upgrade is in progress.
+func (s *ClusterUpgradeStatus) HasReached(stage ClusterUpgradeStage) bool {
+	if !s.InProgress() {
+		return true
+	}
+	for _, passed := range clusterUpgradeStages {
+		if passed == stage {
+			return true
+		}
+		if passed == s.Stage {
+			return false
+		}
+	}
+	return false
 }
 
 // HasConditionValue returns true if the cluster status has the given condition with the given status.
@@ -428,6 +613,27 @@ type ClusterNetworkingConfig struct {
 	ProxyMode string `json:"proxyMode"`
 }
 
+// CNIPluginType is the type of the CNI plugin of a cluster.
+type CNIPluginType string
+
+const (
+	// CNIPluginTypeCanal installs Canal, which combines Flannel and the Calico network policies.
+	CNIPluginTypeCanal CNIPluginType = "canal"
+
+	// CNIPluginTypeCilium installs the eBPF based Cilium.
+	CNIPluginTypeCilium CNIPluginType = "cilium"
+
+	// CNIPluginTypeNone installs no CNI plugin, the user brings their own.
+	CNIPluginTypeNone CNIPluginType = "none"
+)
+
+// CNIPluginSettings contains the spec of the CNI plugin used by the cluster.
+type CNIPluginSettings struct {
+	Type CNIPluginType `json:"type"`
+	// Version is the version of the plugin, e.g. "v3.8". It is empty for the type none.
+	Version string `json:"version,omitempty"`
+}
+
 // MachineNetworkingConfig specifies the networking parameters used for IPAM.
 type MachineNetworkingConfig struct {
 	CIDR       string   `json:"cidr"`
@@ -691,10 +897,14 @@ type ExtendedClusterHealth struct {
 	UserClusterControllerManager HealthStatus `json:"userClusterControllerManager"`
 	GatekeeperController         HealthStatus `json:"gatekeeperController,omitempty"`
 	GatekeeperAudit              HealthStatus `json:"gatekeeperAudit,omitempty"`
+	// TunnelingAgent is the readiness of the tunneling agents, only set for clusters using the Tunneling expose strategy
+	TunnelingAgent HealthStatus `json:"tunnelingAgent,omitempty"`
+	// Addons is the aggregated readiness of all addons of the cluster
+	Addons HealthStatus `json:"addons,omitempty"`
 }
 
-// AllHealthy returns if all components are healthy. Gatekeeper components not included as they are optional and not
-// crucial for cluster functioning
+// AllHealthy returns if all components are healthy. Gatekeeper components and addons are not included as they
+// are optional and not crucial for cluster functioning
 func (h *ExtendedClusterHealth) AllHealthy() bool {
 	return h.Etcd == HealthStatusUp &&
 		h.MachineController == HealthStatusUp &&
@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ClusterTemplateResourceName represents "Resource" defined in Kubernetes
+	ClusterTemplateResourceName = "clustertemplates"
+
+	// ClusterTemplateKind represents "Kind" defined in Kubernetes
+	ClusterTemplateKind = "ClusterTemplate"
+
+	// ClusterTemplateLabelKey is the label key of the clusters which holds the name of the template they were created from
+	ClusterTemplateLabelKey = "cluster-template"
+)
+
+// ClusterTemplateScope defines who can see and use a cluster template
+type ClusterTemplateScope string
+
+const (
+	// ClusterTemplateScopeGlobal templates are managed by the admins and can be used in all projects
+	ClusterTemplateScopeGlobal ClusterTemplateScope = "global"
+	// ClusterTemplateScopeUser templates are managed by their owner and can be used by them in all their projects
+	ClusterTemplateScopeUser ClusterTemplateScope = "user"
+	// ClusterTemplateScopeProject templates are managed by the owners and editors of the project and can be used in it
+	ClusterTemplateScopeProject ClusterTemplateScope = "project"
+)
+
+//+genclient
+//+genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterTemplate is a saved cluster configuration from which new clusters can be created.
+type ClusterTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterTemplateSpec `json:"spec"`
+}
+
+// ClusterTemplateSpec specifies a cluster template
+type ClusterTemplateSpec struct {
+	// HumanReadableName is the name of the template
+	HumanReadableName string `json:"humanReadableName"`
+	// Scope defines who can see and use the template
+	Scope ClusterTemplateScope `json:"scope"`
+	// ProjectID is the name of the project of project scoped templates
+	ProjectID string `json:"projectId,omitempty"`
+	// Owner is the email of the user who created the template
+	Owner string `json:"owner"`
+	// ClusterLabels are the labels of the clusters created from the template
+	ClusterLabels map[string]string `json:"clusterLabels,omitempty"`
+	// Credential is the name of the preset providing the cloud credentials of the clusters.
+	// Templates never contain credentials themselves.
+	Credential string `json:"credential,omitempty"`
+	// Cluster is the spec of the clusters created from the template
+	Cluster ClusterSpec `json:"cluster"`
+	// InitialMachineDeployment is the JSON encoded machine deployment which is created in the clusters once they
+	// are ready, in the same format as the annotation of the clusters
+	InitialMachineDeployment string `json:"initialMachineDeployment,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterTemplateList is a list of cluster templates
+type ClusterTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ClusterTemplate `json:"items"`
+}
@@ -108,6 +108,44 @@ type NodeportProxyConfig struct {
 	// Updater configures the component responsible for updating the LoadBalancer
 	// service.
 	Updater NodeportProxyComponent `json:"updater,omitempty"`
+	// Optional: Limits restricts the resources each user cluster can use on the
+	// nodeport-proxy, so a single cluster cannot exhaust it for all others. They
+	// can be overridden per cluster.
+	Limits *NodeportProxyLimits `json:"limits,omitempty"`
+}
+
+// NodeportProxyLimits are the limits enforced by the nodeport-proxy on each port
+// of the control plane services of a user cluster. Unset limits are not enforced.
+type NodeportProxyLimits struct {
+	// Optional: MaxConnections is the maximum number of concurrent connections.
+	MaxConnections *uint32 `json:"maxConnections,omitempty"`
+	// Optional: MaxPendingRequests is the maximum number of requests waiting for
+	// a connection, it only applies to the Tunneling expose strategy.
+	MaxPendingRequests *uint32 `json:"maxPendingRequests,omitempty"`
+	// Optional: MaxRequests is the maximum number of concurrent requests, it only
+	// applies to the Tunneling expose strategy.
+	MaxRequests *uint32 `json:"maxRequests,omitempty"`
+	// Optional: ConnectionsPerSecond is the rate at which new connections are
+	// accepted, the connections above it are closed. It does not apply to the
+	// Tunneling expose strategy.
+	ConnectionsPerSecond *uint32 `json:"connectionsPerSecond,omitempty"`
+}
+
+// Merge applies the limits from l into dst if the corresponding limit in dst
+// is nil.
+func (l *NodeportProxyLimits) Merge(dst *NodeportProxyLimits) {
+	if dst.MaxConnections == nil {
+		dst.MaxConnections = l.MaxConnections
+	}
+	if dst.MaxPendingRequests == nil {
+		dst.MaxPendingRequests = l.MaxPendingRequests
+	}
+	if dst.MaxRequests == nil {
+		dst.MaxRequests = l.MaxRequests
+	}
+	if dst.ConnectionsPerSecond == nil {
+		dst.ConnectionsPerSecond = l.ConnectionsPerSecond
+	}
 }
 
 type NodeportProxyComponent struct {
@@ -31,6 +31,9 @@ const (
 	DefaultKeptBackupsCount = 20
 	MaxKeptBackupsCount     = 50
 
+	// MaxRetainedBackupsCount is the maximum number of backups a retention policy may retain in total
+	MaxRetainedBackupsCount = 100
+
 	// BackupStatusPhase value indicating that the corresponding job has started
 	BackupStatusPhaseRunning = "Running"
 
@@ -66,8 +69,95 @@ type EtcdBackupConfigSpec struct {
 	// once, immediately.
 	Schedule string `json:"schedule,omitempty"`
 	// Keep is the number of backups to keep around before deleting the oldest one
-	// If not set, defaults to DefaultKeptBackupsCount. Only used if Schedule is set.
+	// If not set, defaults to DefaultKeptBackupsCount. Only used if Schedule is set
+	// and Retention is not set.
 	Keep *int `json:"keep,omitempty"`
+	// Retention defines a generational retention policy, e.g. "keep 24 hourly, 7 daily,
+	// 4 weekly and 12 monthly backups". It replaces Keep if set. Only used if Schedule is set.
+	Retention *BackupRetentionPolicy `json:"retention,omitempty"`
+	// Destination overrides where the backups are stored. If not set, the backups are stored
+	// in the S3 bucket configured for the seed's backup containers.
+	Destination *BackupDestination `json:"destination,omitempty"`
+	// Encryption enables client-side encryption of the backups before they are uploaded.
+	// If not set, the backups are stored unencrypted.
+	Encryption *BackupEncryption `json:"encryption,omitempty"`
+}
+
+// BackupEncryption configures the envelope encryption of backups. Every backup is encrypted
+// with a random data key, which is encrypted with the active key and stored alongside the backup.
+// To rotate the key, add a new key to the Secret and change ActiveKeyID. Old keys must be kept
+// in the Secret as long as backups encrypted with them exist, otherwise they cannot be restored.
+type BackupEncryption struct {
+	// KeySecretName is the name of a Secret in the kube-system namespace. Every key in the Secret
+	// is a key ID, its value the 16, 24 or 32 bytes long key used for AES-GCM encryption.
+	KeySecretName string `json:"keySecretName"`
+	// ActiveKeyID is the ID of the key used to encrypt new backups
+	ActiveKeyID string `json:"activeKeyID"`
+}
+
+// BackupRetentionPolicy defines how many backups are retained under each rule. Every rule
+// retains the most recent backup of each of the last N periods (in UTC) that contain a backup,
+// the Latest rule simply retains the N most recent backups. Backups which are not retained by
+// any rule are deleted. The counts must not exceed MaxRetainedBackupsCount in total.
+type BackupRetentionPolicy struct {
+	// Latest is the number of most recent backups to retain
+	Latest int `json:"latest,omitempty"`
+	// Hourly is the number of hours to retain the most recent backup of
+	Hourly int `json:"hourly,omitempty"`
+	// Daily is the number of days to retain the most recent backup of
+	Daily int `json:"daily,omitempty"`
+	// Weekly is the number of weeks to retain the most recent backup of
+	Weekly int `json:"weekly,omitempty"`
+	// Monthly is the number of months to retain the most recent backup of
+	Monthly int `json:"monthly,omitempty"`
+}
+
+// BackupRetentionRule is a rule of a BackupRetentionPolicy
+type BackupRetentionRule string
+
+const (
+	BackupRetentionRuleLatest  BackupRetentionRule = "latest"
+	BackupRetentionRuleHourly  BackupRetentionRule = "hourly"
+	BackupRetentionRuleDaily   BackupRetentionRule = "daily"
+	BackupRetentionRuleWeekly  BackupRetentionRule = "weekly"
+	BackupRetentionRuleMonthly BackupRetentionRule = "monthly"
+)
+
+// BackupDestination defines where the backups of an EtcdBackupConfig are stored.
+// Exactly one of the destination types must be set. The settings are passed to the
+// backup containers as environment variables, see the documentation of the individual types.
+// Only remote destinations are supported, as the backups are downloaded by the etcd pods
+// of the cluster namespace when they are restored.
+type BackupDestination struct {
+	// S3 stores the backups in an S3-compatible bucket. GCS buckets can be used via
+	// their S3 interoperability endpoint.
+	S3 *S3BackupDestination `json:"s3,omitempty"`
+	// AzureBlob stores the backups in an Azure Blob Storage container.
+	AzureBlob *AzureBlobBackupDestination `json:"azureBlob,omitempty"`
+}
+
+// S3BackupDestination is passed to the backup containers as $ENDPOINT, $BUCKET_NAME,
+// $ACCESS_KEY_ID and $SECRET_ACCESS_KEY.
+type S3BackupDestination struct {
+	// Endpoint is the S3 endpoint, e.g. "s3.amazonaws.com"
+	Endpoint string `json:"endpoint"`
+	// BucketName is the name of the bucket to store the backups in
+	BucketName string `json:"bucketName"`
+	// CredentialsSecretName is the name of a Secret in the kube-system namespace containing
+	// the ACCESS_KEY_ID and SECRET_ACCESS_KEY keys. If not set, the seed's default credentials are used.
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
+}
+
+// AzureBlobBackupDestination is passed to the backup containers as $AZURE_STORAGE_ACCOUNT,
+// $AZURE_STORAGE_KEY and $AZURE_CONTAINER_NAME.
+type AzureBlobBackupDestination struct {
+	// AccountName is the name of the Azure storage account
+	AccountName string `json:"accountName"`
+	// ContainerName is the name of the Blob Storage container to store the backups in
+	ContainerName string `json:"containerName"`
+	// CredentialsSecretName is the name of a Secret in the kube-system namespace containing
+	// the storage account key in the ACCOUNT_KEY key.
+	CredentialsSecretName string `json:"credentialsSecretName"`
 }
 
 // EtcdBackupConfigList is a list of etcd backup configs
@@ -104,6 +194,9 @@ type BackupStatus struct {
 	DeleteFinishedTime *metav1.Time      `json:"deleteFinishedTime,omitempty"`
 	DeletePhase        BackupStatusPhase `json:"deletePhase,omitempty"`
 	DeleteMessage      string            `json:"deleteMessage,omitempty"`
+	// RetainedBy lists the rules of the retention policy under which the completed backup
+	// is currently retained
+	RetainedBy []BackupRetentionRule `json:"retainedBy,omitempty"`
 }
 
 type EtcdBackupConfigCondition struct {
@@ -136,6 +229,36 @@ const (
 	EtcdBackupConfigConditionSchedulingActive EtcdBackupConfigConditionType = "SchedulingActive"
 )
 
+// Type returns the type of the destination, which is also passed to the backup
+// containers as $BACKUP_DESTINATION_TYPE.
+func (d *BackupDestination) Type() BackupDestinationType {
+	switch {
+	case d.S3 != nil:
+		return BackupDestinationTypeS3
+	case d.AzureBlob != nil:
+		return BackupDestinationTypeAzureBlob
+	default:
+		return ""
+	}
+}
+
+// BackupDestinationType is the type of a BackupDestination
+type BackupDestinationType string
+
+const (
+	BackupDestinationTypeS3        BackupDestinationType = "s3"
+	BackupDestinationTypeAzureBlob BackupDestinationType = "azureblob"
+)
+
+// GetRetentionPolicy returns the configured retention policy or, if none is set, a policy
+// retaining the latest GetKeptBackupsCount() backups.
+func (bc *EtcdBackupConfig) GetRetentionPolicy() BackupRetentionPolicy {
+	if bc.Spec.Retention != nil {
+		return *bc.Spec.Retention
+	}
+	return BackupRetentionPolicy{Latest: bc.GetKeptBackupsCount()}
+}
+
 func (bc *EtcdBackupConfig) GetKeptBackupsCount() int {
 	if bc.Spec.Keep == nil {
 		return DefaultKeptBackupsCount
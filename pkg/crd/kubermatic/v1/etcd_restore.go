@@ -28,6 +28,14 @@ const (
 	// EtcdRestoreKindName represents "Kind" defined in Kubernetes
 	EtcdRestoreKindName = "EtcdRestore"
 
+	// EtcdRestorePhase value indicating that the backup to restore from has been downloaded and
+	// passed the integrity check
+	EtcdRestorePhaseValidated = "Validated"
+
+	// EtcdRestorePhase value indicating that the backup to restore from is missing or failed the
+	// integrity check. The etcd statefulset has not been touched.
+	EtcdRestorePhaseValidationFailed = "ValidationFailed"
+
 	// EtcdRestorePhase value indicating that the restore has started
 	EtcdRestorePhaseStarted = "Started"
 
@@ -61,11 +69,29 @@ type EtcdRestoreSpec struct {
 	Name string `json:"name"`
 	// Cluster is the reference to the cluster whose etcd will be backed up
 	Cluster corev1.ObjectReference `json:"cluster"`
-	// BackupName is the name of the backup to restore from
-	BackupName string `json:"backupName"`
+	// BackupName is the name of the backup to restore from.
+	// If not set, the backup is selected from the completed backups listed in the
+	// status of the cluster's EtcdBackupConfigs (see BackupConfigName and PointInTime)
+	// and written back to this field.
+	BackupName string `json:"backupName,omitempty"`
+	// BackupConfigName limits the backup selection to the backups of the EtcdBackupConfig
+	// with the given name. Only used if BackupName is not set.
+	BackupConfigName string `json:"backupConfigName,omitempty"`
+	// PointInTime selects the most recent completed backup which finished at or before the
+	// given time. If not set, the latest completed backup is selected. Only used if BackupName is not set.
+	PointInTime *metav1.Time `json:"pointInTime,omitempty"`
+	// Destination is the backup destination the backup is downloaded from. If not set, it is taken from the
+	// EtcdBackupConfig the backup belongs to and written back to this field. Without destination, the backup
+	// is downloaded from the seed's default S3 settings.
+	Destination *BackupDestination `json:"destination,omitempty"`
 	// BackupDownloadCredentialsSecret is the name of a secret in the cluster-xxx namespace containing
-	// credentials needed to download the backup
+	// credentials needed to download the backup. If not set, it is created from the credentials secret of the
+	// destination or the seed's default S3 credentials and settings.
 	BackupDownloadCredentialsSecret string `json:"backupDownloadCredentialsSecret,omitempty"`
+	// BackupDecryptionKeysSecret is the name of a secret in the cluster-xxx namespace containing
+	// the keys needed to decrypt an encrypted backup, see BackupEncryption. If not set, it is created
+	// from the key Secrets of the cluster's EtcdBackupConfigs.
+	BackupDecryptionKeysSecret string `json:"backupDecryptionKeysSecret,omitempty"`
 }
 
 // EtcdRestoreList is a list of etcd restores
@@ -80,4 +106,26 @@ type EtcdRestoreList struct {
 type EtcdRestoreStatus struct {
 	Phase       EtcdRestorePhase `json:"phase"`
 	RestoreTime *metav1.Time     `json:"restoreTime,omitempty"`
+	// Snapshot contains the details of the snapshot that passed validation and is being restored
+	Snapshot *EtcdRestoreSnapshotStatus `json:"snapshot,omitempty"`
+	// Message contains a human readable reason if the restore failed validation
+	Message string `json:"message,omitempty"`
+}
+
+// EtcdRestoreSnapshotStatus describes a validated etcd snapshot
+type EtcdRestoreSnapshotStatus struct {
+	// ObjectName is the name of the snapshot object in the backup storage
+	ObjectName string `json:"objectName"`
+	// Revision is the etcd revision contained in the snapshot
+	Revision int64 `json:"revision"`
+	// Size is the size of the snapshot object in bytes
+	Size int64 `json:"size"`
+	// TotalKeys is the number of keys contained in the snapshot
+	TotalKeys int `json:"totalKeys"`
+	// Hash is the hash of the snapshot's database content, as reported by `etcdctl snapshot status`
+	Hash uint32 `json:"hash"`
+	// EncryptionKeyID is the ID of the key the snapshot was encrypted with, empty if the snapshot is not encrypted
+	EncryptionKeyID string `json:"encryptionKeyID,omitempty"`
+	// ValidationTime is the time at which the snapshot passed validation
+	ValidationTime metav1.Time `json:"validationTime"`
 }
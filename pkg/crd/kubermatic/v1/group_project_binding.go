@@ -0,0 +1,62 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// GroupProjectBindingResourceName represents "Resource" defined in Kubernetes
+	GroupProjectBindingResourceName = "groupprojectbindings"
+
+	// GroupProjectBindingKind represents "Kind" defined in Kubernetes
+	GroupProjectBindingKind = "GroupProjectBinding"
+)
+
+//+genclient
+//+genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GroupProjectBinding specifies a binding between a group of users and a project.
+// The group is a value of the groups claim of the OIDC tokens, all users belonging to it are members of the project.
+type GroupProjectBinding struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GroupProjectBindingSpec `json:"spec"`
+}
+
+// GroupProjectBindingSpec specifies a group of users
+type GroupProjectBindingSpec struct {
+	// Group is the name of the group as contained in the groups claim of the OIDC tokens
+	Group string `json:"group"`
+	// ProjectID is the name of the project
+	ProjectID string `json:"projectId"`
+	// Role is the role of the members in the project, either owners, editors, viewers or the name of a custom ProjectRole
+	Role string `json:"role"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GroupProjectBindingList is a list of group project bindings
+type GroupProjectBindingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []GroupProjectBinding `json:"items"`
+}
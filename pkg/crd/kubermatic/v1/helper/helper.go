@@ -35,6 +35,7 @@ import (
 // ClusterReconcileWrapper is a wrapper that should be used around
 // any cluster reconciliaton. It:
 // * Checks if the cluster is paused
+// * Checks if the control plane of the cluster is hibernated
 // * Checks if the worker-name matches
 // * Sets the ReconcileSuccess condition for the controller
 func ClusterReconcileWrapper(
@@ -52,6 +53,10 @@ func ClusterReconcileWrapper(
 	if cluster.Spec.Pause {
 		return nil, nil
 	}
+	// The hibernation controller scales the control plane down, it must not be scaled up again
+	if cluster.Status.Hibernation.ControlPlaneHibernated() {
+		return nil, nil
+	}
 
 	reconcilingStatus := corev1.ConditionFalse
 	result, err := reconcile()
@@ -17,6 +17,9 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -56,11 +59,109 @@ type Project struct {
 // ProjectSpec is a specification of a project.
 type ProjectSpec struct {
 	Name string `json:"name"`
+
+	// UpdateWindow is the default update window of the clusters of the project, it applies
+	// to all clusters which do not have an update window of their own
+	UpdateWindow *UpdateWindow `json:"updateWindow,omitempty"`
+
+	// SSHCertificateAuthority makes the worker nodes of the clusters of the project trust ssh certificates
+	// signed by the certificate authority of the project instead of authorizing the user ssh keys
+	SSHCertificateAuthority *ProjectSSHCertificateAuthority `json:"sshCertificateAuthority,omitempty"`
+
+	// ResourceQuota limits the total resources the clusters of the project may use, it can only be set by admins
+	ResourceQuota *ProjectResourceQuota `json:"resourceQuota,omitempty"`
+}
+
+// ProjectResourceQuota limits the total resources of the clusters of a project. Limits which are not set are
+// not enforced.
+type ProjectResourceQuota struct {
+	// Clusters is the maximum number of clusters in the project
+	Clusters *int64 `json:"clusters,omitempty"`
+	// Nodes is the maximum number of nodes, the sum of the replicas of all machine deployments of the project
+	Nodes *int64 `json:"nodes,omitempty"`
+	// CPU is the maximum number of vCPUs of all nodes of the project
+	CPU *resource.Quantity `json:"cpu,omitempty"`
+	// Memory is the maximum amount of memory of all nodes of the project
+	Memory *resource.Quantity `json:"memory,omitempty"`
+	// Storage is the maximum size of the disks of all nodes of the project
+	Storage *resource.Quantity `json:"storage,omitempty"`
+}
+
+// LimitsNodes tells if the quota limits anything besides the number of clusters, which requires
+// the machine deployments of the clusters to be accounted.
+func (q *ProjectResourceQuota) LimitsNodes() bool {
+	return q != nil && (q.Nodes != nil || q.CPU != nil || q.Memory != nil || q.Storage != nil)
+}
+
+// ResourceUsage is an amount of resources used by clusters and their nodes.
+type ResourceUsage struct {
+	// Clusters is the number of clusters
+	Clusters int64 `json:"clusters"`
+	// Nodes is the sum of the replicas of the machine deployments
+	Nodes int64 `json:"nodes"`
+	// CPU is the number of vCPUs of the nodes
+	CPU resource.Quantity `json:"cpu"`
+	// Memory is the amount of memory of the nodes
+	Memory resource.Quantity `json:"memory"`
+	// Storage is the size of the disks of the nodes
+	Storage resource.Quantity `json:"storage"`
+}
+
+// Add adds the other usage to the usage.
+func (u *ResourceUsage) Add(other ResourceUsage) {
+	u.Clusters += other.Clusters
+	u.Nodes += other.Nodes
+	u.CPU.Add(other.CPU)
+	u.Memory.Add(other.Memory)
+	u.Storage.Add(other.Storage)
+}
+
+// Sub subtracts the other usage from the usage.
+func (u *ResourceUsage) Sub(other ResourceUsage) {
+	u.Clusters -= other.Clusters
+	u.Nodes -= other.Nodes
+	u.CPU.Sub(other.CPU)
+	u.Memory.Sub(other.Memory)
+	u.Storage.Sub(other.Storage)
+}
+
+const (
+	// DefaultSSHCertificateValidity is how long the ssh certificates are valid if the project doesn't configure it
+	DefaultSSHCertificateValidity = time.Hour
+
+	// MaxSSHCertificateValidity is the longest validity of the ssh certificates a project can configure
+	MaxSSHCertificateValidity = 24 * time.Hour
+)
+
+// ProjectSSHCertificateAuthority configures the ssh certificate authority of a project.
+type ProjectSSHCertificateAuthority struct {
+	// Enabled makes the nodes trust the certificate authority, the user ssh keys are not written to them anymore
+	Enabled bool `json:"enabled"`
+	// Validity is how long the signed certificates are valid, defaults to one hour
+	Validity *metav1.Duration `json:"validity,omitempty"`
+}
+
+// CertificateValidity returns how long the signed certificates are valid.
+func (ca *ProjectSSHCertificateAuthority) CertificateValidity() time.Duration {
+	if ca == nil || ca.Validity == nil || ca.Validity.Duration <= 0 {
+		return DefaultSSHCertificateValidity
+	}
+	return ca.Validity.Duration
+}
+
+// SSHCertificateAuthorityEnabled tells if the nodes of the clusters of the project trust the ssh
+// certificate authority of the project.
+func (p *Project) SSHCertificateAuthorityEnabled() bool {
+	return p.Spec.SSHCertificateAuthority != nil && p.Spec.SSHCertificateAuthority.Enabled
 }
 
 // ProjectStatus represents the current status of a project.
 type ProjectStatus struct {
 	Phase string `json:"phase"`
+
+	// ResourceUsage is the sum of the resources used by the clusters of the project in all seeds, it is
+	// aggregated from the status of the clusters by the master controller manager
+	ResourceUsage *ResourceUsage `json:"resourceUsage,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
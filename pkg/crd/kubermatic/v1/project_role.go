@@ -0,0 +1,131 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	// ProjectRoleResourceName represents "Resource" defined in Kubernetes
+	ProjectRoleResourceName = "projectroles"
+
+	// ProjectRoleKindName represents "Kind" defined in Kubernetes
+	ProjectRoleKindName = "ProjectRole"
+)
+
+// ProjectRoleResource is a type of Kubermatic resource the members of a project can be granted access to
+type ProjectRoleResource string
+
+const (
+	ProjectRoleResourceClusters           ProjectRoleResource = "clusters"
+	ProjectRoleResourceMachineDeployments ProjectRoleResource = "machinedeployments"
+	ProjectRoleResourceSSHKeys            ProjectRoleResource = "sshkeys"
+	ProjectRoleResourceAddons             ProjectRoleResource = "addons"
+	ProjectRoleResourceConstraints        ProjectRoleResource = "constraints"
+	ProjectRoleResourceAlertmanagers      ProjectRoleResource = "alertmanagers"
+)
+
+// AllProjectRoleResources are the resource types a ProjectRole can grant verbs on
+var AllProjectRoleResources = []ProjectRoleResource{
+	ProjectRoleResourceClusters,
+	ProjectRoleResourceMachineDeployments,
+	ProjectRoleResourceSSHKeys,
+	ProjectRoleResourceAddons,
+	ProjectRoleResourceConstraints,
+	ProjectRoleResourceAlertmanagers,
+}
+
+const (
+	// ProjectRoleVerbGet allows to get and list the resources
+	ProjectRoleVerbGet = "get"
+	// ProjectRoleVerbCreate allows to create the resources
+	ProjectRoleVerbCreate = "create"
+	// ProjectRoleVerbUpdate allows to update and patch the resources, for example to scale machine deployments
+	ProjectRoleVerbUpdate = "update"
+	// ProjectRoleVerbDelete allows to delete the resources
+	ProjectRoleVerbDelete = "delete"
+)
+
+// AllProjectRoleVerbs are the verbs a ProjectRole can grant
+var AllProjectRoleVerbs = []string{
+	ProjectRoleVerbGet,
+	ProjectRoleVerbCreate,
+	ProjectRoleVerbUpdate,
+	ProjectRoleVerbDelete,
+}
+
+//+genclient
+//+genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ProjectRole is a custom role for the members of projects which defines the verbs allowed per resource type.
+// Members are bound to the role by a UserProjectBinding with the group "<role name>-<project ID>",
+// the same way as to the built-in owners, editors and viewers groups, thus the name must not contain a dash.
+type ProjectRole struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ProjectRoleSpec `json:"spec"`
+}
+
+// ProjectRoleSpec specifies the verbs granted by a ProjectRole
+type ProjectRoleSpec struct {
+	// Rules grant verbs on resource types, everything not granted is denied.
+	// The members can always get the project itself.
+	Rules []ProjectRoleRule `json:"rules,omitempty"`
+}
+
+// ProjectRoleRule grants verbs on one resource type
+type ProjectRoleRule struct {
+	// Resource is the resource type, one of clusters, machinedeployments, sshkeys, addons, constraints or alertmanagers.
+	Resource ProjectRoleResource `json:"resource"`
+	// Verbs are the granted verbs, any of get, create, update and delete.
+	Verbs []string `json:"verbs"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ProjectRoleList is a list of project roles
+type ProjectRoleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ProjectRole `json:"items"`
+}
+
+// Verbs returns the sorted verbs the role grants on the given resource type
+func (r *ProjectRole) Verbs(resource ProjectRoleResource) []string {
+	verbs := sets.NewString()
+	for _, rule := range r.Spec.Rules {
+		if rule.Resource == resource {
+			verbs.Insert(rule.Verbs...)
+		}
+	}
+	return verbs.List()
+}
+
+// Allows returns true if the role grants the verb on the given resource type
+func (r *ProjectRole) Allows(resource ProjectRoleResource, verb string) bool {
+	for _, rule := range r.Spec.Rules {
+		if rule.Resource == resource && sets.NewString(rule.Verbs...).Has(verb) {
+			return true
+		}
+	}
+	return false
+}
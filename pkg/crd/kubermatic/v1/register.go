@@ -85,6 +85,12 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&ConstraintList{},
 		&Alertmanager{},
 		&AlertmanagerList{},
+		&ProjectRole{},
+		&ProjectRoleList{},
+		&GroupProjectBinding{},
+		&GroupProjectBindingList{},
+		&ClusterTemplate{},
+		&ClusterTemplateList{},
 	)
 
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -47,6 +49,16 @@ type SSHKeySpec struct {
 	Fingerprint string   `json:"fingerprint"`
 	PublicKey   string   `json:"publicKey"`
 	Clusters    []string `json:"clusters"`
+	// User is the Linux user the key is authorized for on the nodes. The key is
+	// authorized for all users when it is empty.
+	User string `json:"user,omitempty"`
+	// ExpiresAt is the time after which the key is removed from the nodes.
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+}
+
+// IsExpired tells if the key is expired at the given time.
+func (sk *UserSSHKey) IsExpired(now time.Time) bool {
+	return sk.Spec.ExpiresAt != nil && !now.Before(sk.Spec.ExpiresAt.Time)
 }
 
 func (sk *UserSSHKey) IsUsedByCluster(clustername string) bool {
@@ -52,6 +52,9 @@ type UserSpec struct {
 	IsAdmin                 bool                                    `json:"admin"`
 	Settings                *UserSettings                           `json:"settings,omitempty"`
 	TokenBlackListReference *providerconfig.GlobalSecretKeySelector `json:"tokenBlackListReference,omitempty"`
+	// Groups are the groups of the user as contained in the groups claim of the OIDC token of the last request,
+	// GroupProjectBindings referencing them make the user a member of projects
+	Groups []string `json:"groups,omitempty"`
 }
 
 // UserSettings represent an user settings
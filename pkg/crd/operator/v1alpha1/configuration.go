@@ -33,7 +33,8 @@ type KubermaticConfiguration struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec KubermaticConfigurationSpec `json:"spec"`
+	Spec   KubermaticConfigurationSpec   `json:"spec"`
+	Status KubermaticConfigurationStatus `json:"status,omitempty"`
 }
 
 // KubermaticConfigurationSpec is the spec for a Kubermatic installation.
@@ -76,14 +77,20 @@ type KubermaticConfigurationSpec struct {
 
 // KubermaticAuthConfiguration defines keys and URLs for Dex.
 type KubermaticAuthConfiguration struct {
-	ClientID                 string `json:"clientID,omitempty"`
-	TokenIssuer              string `json:"tokenIssuer,omitempty"`
-	IssuerRedirectURL        string `json:"issuerRedirectURL,omitempty"`
-	IssuerClientID           string `json:"issuerClientID,omitempty"`
-	IssuerClientSecret       string `json:"issuerClientSecret,omitempty"`
-	IssuerCookieKey          string `json:"issuerCookieKey,omitempty"`
-	ServiceAccountKey        string `json:"serviceAccountKey,omitempty"`
-	SkipTokenIssuerTLSVerify bool   `json:"skipTokenIssuerTLSVerify,omitempty"`
+	ClientID           string `json:"clientID,omitempty"`
+	TokenIssuer        string `json:"tokenIssuer,omitempty"`
+	IssuerRedirectURL  string `json:"issuerRedirectURL,omitempty"`
+	IssuerClientID     string `json:"issuerClientID,omitempty"`
+	IssuerClientSecret string `json:"issuerClientSecret,omitempty"`
+	IssuerCookieKey    string `json:"issuerCookieKey,omitempty"`
+	// ServiceAccountKey signs the tokens of the project service accounts. A PEM encoded RSA or
+	// ECDSA private key signs them with RS256 or ES256, any other value is used as HMAC key.
+	ServiceAccountKey string `json:"serviceAccountKey,omitempty"`
+	// ServiceAccountVerificationKeys are the former service account keys, either a HMAC key or PEM
+	// encoded RSA and ECDSA keys. They verify the tokens issued before the ServiceAccountKey was
+	// rotated until these tokens expire.
+	ServiceAccountVerificationKeys string `json:"serviceAccountVerificationKeys,omitempty"`
+	SkipTokenIssuerTLSVerify       bool   `json:"skipTokenIssuerTLSVerify,omitempty"`
 }
 
 // KubermaticAPIConfiguration configures the dashboard.
@@ -101,6 +108,17 @@ type KubermaticAPIConfiguration struct {
 	DebugLog bool `json:"debugLog,omitempty"`
 	// Replicas sets the number of pod replicas for the API deployment.
 	Replicas *int32 `json:"replicas,omitempty"`
+	// Audit configures where the audit events of the mutating API requests are recorded.
+	Audit KubermaticAPIAuditConfiguration `json:"audit,omitempty"`
+}
+
+// KubermaticAPIAuditConfiguration configures the audit log of the Kubermatic REST API.
+type KubermaticAPIAuditConfiguration struct {
+	// WebhookURL is the URL every audit event is posted to as JSON.
+	WebhookURL string `json:"webhookURL,omitempty"`
+	// KubernetesEvents records every audit event as a Kubernetes event in the Kubermatic namespace.
+	// Admins can only query the audit events via the API if they are recorded as Kubernetes events.
+	KubernetesEvents bool `json:"kubernetesEvents,omitempty"`
 }
 
 // KubermaticUIConfiguration configures the dashboard.
@@ -163,6 +181,9 @@ type KubermaticUserClusterConfiguration struct {
 	// DNATControllerDockerRepository is the repository containing the
 	// dnat-controller image.
 	DNATControllerDockerRepository string `json:"dnatControllerDockerRepository,omitempty"`
+	// DNATControllerBackend is the backend the dnat-controller uses to translate the
+	// node addresses, either "iptables" or "nftables". Defaults to "iptables".
+	DNATControllerBackend string `json:"dnatControllerBackend,omitempty"`
 	// EtcdLauncherDockerRepository is the repository containing the Kubermatic
 	// etcd-launcher image.
 	EtcdLauncherDockerRepository string `json:"etcdLauncherDockerRepository,omitempty"`
@@ -297,6 +318,37 @@ type KubermaticVersioningConfiguration struct {
 	// updates as well. 'automaticNodeUpdate: true' implies 'automatic: true' as well,
 	// because Nodes may not have a newer version than the controlplane.
 	Updates []Update `json:"updates,omitempty"`
+	// Rollout configures the waves in which automatic controlplane updates are rolled out
+	// to the user clusters of each seed. If not set, automatic updates are applied to all
+	// matching user clusters at once.
+	Rollout *KubermaticRolloutConfiguration `json:"rollout,omitempty"`
+}
+
+// KubermaticRolloutConfiguration configures the waves in which automatic updates are rolled out.
+type KubermaticRolloutConfiguration struct {
+	// Waves are rolled out one after another. Each cluster belongs to the first wave it
+	// matches, clusters which do not match any wave are updated in a final wave named "remaining".
+	Waves []KubermaticRolloutWave `json:"waves,omitempty"`
+	// SoakTime is the time to wait after all clusters of a wave were updated before the
+	// next wave is started. Defaults to 1h.
+	SoakTime *metav1.Duration `json:"soakTime,omitempty"`
+	// MaxUnhealthyPercentage pauses the rollout once more than this percentage of the clusters
+	// of a wave which started the update have halted updates, are not healthy or have resources
+	// in the seed which are not up to date. The rollout resumes as soon as the clusters recover.
+	// Defaults to 10.
+	MaxUnhealthyPercentage *int `json:"maxUnhealthyPercentage,omitempty"`
+}
+
+// KubermaticRolloutWave selects the clusters of a rollout wave. Exactly one of Selector
+// and Percentage must be set.
+type KubermaticRolloutWave struct {
+	// Name identifies the wave in the rollout status.
+	Name string `json:"name"`
+	// Selector selects the clusters of the wave by their labels.
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// Percentage selects a stable share of the clusters of each seed. The percentages of
+	// all waves add up, so waves of 5 and 25 percent update 30 percent of the clusters.
+	Percentage *int `json:"percentage,omitempty"`
 }
 
 // Update represents an update option for a user cluster.
@@ -354,6 +406,43 @@ type KubermaticProxyConfiguration struct {
 	NoProxy string `json:"noProxy,omitempty"`
 }
 
+// KubermaticConfigurationStatus is the status of a Kubermatic installation.
+type KubermaticConfigurationStatus struct {
+	// Rollouts is the progress of the automatic updates in each seed, if rollout waves are configured.
+	Rollouts []KubermaticRolloutStatus `json:"rollouts,omitempty"`
+}
+
+// KubermaticRolloutStatus is the progress of an automatic update to a version in a seed.
+type KubermaticRolloutStatus struct {
+	// Seed is the name of the seed the clusters are running in.
+	Seed string `json:"seed"`
+	// Version is the version the clusters are updated to.
+	Version string `json:"version"`
+	// CurrentWave is the name of the wave which is being updated or, while soaking, which was
+	// updated last. It is empty once all waves are updated.
+	CurrentWave string `json:"currentWave,omitempty"`
+	// Paused is true if the rollout is paused because too many clusters of a wave are unhealthy.
+	Paused bool `json:"paused,omitempty"`
+	// Message explains why the next wave was not started yet.
+	Message string `json:"message,omitempty"`
+	// Waves is the progress of each wave.
+	Waves []KubermaticRolloutWaveStatus `json:"waves,omitempty"`
+}
+
+// KubermaticRolloutWaveStatus is the progress of a rollout wave.
+type KubermaticRolloutWaveStatus struct {
+	// Name is the name of the wave.
+	Name string `json:"name"`
+	// Clusters is the number of clusters in the wave.
+	Clusters int `json:"clusters"`
+	// Updated is the number of clusters which completed the update.
+	Updated int `json:"updated"`
+	// Unhealthy is the number of clusters which started the update and are unhealthy.
+	Unhealthy int `json:"unhealthy"`
+	// CompletionTime is the time the last cluster of the wave completed the update.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // KubermaticConfigurationList is a collection of KubermaticConfigurations.
@@ -6,10 +6,27 @@ package v1alpha1
 
 import (
 	v3 "github.com/Masterminds/semver/v3"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	sets "k8s.io/apimachinery/pkg/util/sets"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubermaticAPIAuditConfiguration) DeepCopyInto(out *KubermaticAPIAuditConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubermaticAPIAuditConfiguration.
+func (in *KubermaticAPIAuditConfiguration) DeepCopy() *KubermaticAPIAuditConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(KubermaticAPIAuditConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubermaticAPIConfiguration) DeepCopyInto(out *KubermaticAPIConfiguration) {
 	*out = *in
@@ -29,6 +46,7 @@ func (in *KubermaticAPIConfiguration) DeepCopyInto(out *KubermaticAPIConfigurati
 		*out = new(int32)
 		**out = **in
 	}
+	out.Audit = in.Audit
 	return
 }
 
@@ -118,6 +136,7 @@ func (in *KubermaticConfiguration) DeepCopyInto(out *KubermaticConfiguration) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -206,6 +225,29 @@ func (in *KubermaticConfigurationSpec) DeepCopy() *KubermaticConfigurationSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubermaticConfigurationStatus) DeepCopyInto(out *KubermaticConfigurationStatus) {
+	*out = *in
+	if in.Rollouts != nil {
+		in, out := &in.Rollouts, &out.Rollouts
+		*out = make([]KubermaticRolloutStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubermaticConfigurationStatus.
+func (in *KubermaticConfigurationStatus) DeepCopy() *KubermaticConfigurationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KubermaticConfigurationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubermaticIngressConfiguration) DeepCopyInto(out *KubermaticIngressConfiguration) {
 	*out = *in
@@ -283,6 +325,108 @@ func (in *KubermaticProxyConfiguration) DeepCopy() *KubermaticProxyConfiguration
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubermaticRolloutConfiguration) DeepCopyInto(out *KubermaticRolloutConfiguration) {
+	*out = *in
+	if in.Waves != nil {
+		in, out := &in.Waves, &out.Waves
+		*out = make([]KubermaticRolloutWave, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SoakTime != nil {
+		in, out := &in.SoakTime, &out.SoakTime
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxUnhealthyPercentage != nil {
+		in, out := &in.MaxUnhealthyPercentage, &out.MaxUnhealthyPercentage
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubermaticRolloutConfiguration.
+func (in *KubermaticRolloutConfiguration) DeepCopy() *KubermaticRolloutConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(KubermaticRolloutConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubermaticRolloutStatus) DeepCopyInto(out *KubermaticRolloutStatus) {
+	*out = *in
+	if in.Waves != nil {
+		in, out := &in.Waves, &out.Waves
+		*out = make([]KubermaticRolloutWaveStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubermaticRolloutStatus.
+func (in *KubermaticRolloutStatus) DeepCopy() *KubermaticRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KubermaticRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubermaticRolloutWave) DeepCopyInto(out *KubermaticRolloutWave) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Percentage != nil {
+		in, out := &in.Percentage, &out.Percentage
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubermaticRolloutWave.
+func (in *KubermaticRolloutWave) DeepCopy() *KubermaticRolloutWave {
+	if in == nil {
+		return nil
+	}
+	out := new(KubermaticRolloutWave)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubermaticRolloutWaveStatus) DeepCopyInto(out *KubermaticRolloutWaveStatus) {
+	*out = *in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubermaticRolloutWaveStatus.
+func (in *KubermaticRolloutWaveStatus) DeepCopy() *KubermaticRolloutWaveStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KubermaticRolloutWaveStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubermaticSeedControllerConfiguration) DeepCopyInto(out *KubermaticSeedControllerConfiguration) {
 	*out = *in
@@ -434,6 +578,11 @@ func (in *KubermaticVersioningConfiguration) DeepCopyInto(out *KubermaticVersion
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(KubermaticRolloutConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -19,6 +19,7 @@ package defaulting
 import (
 	"fmt"
 
+	"k8c.io/kubermatic/v2/pkg/cni"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/provider"
 )
@@ -34,5 +35,14 @@ func DefaultCreateClusterSpec(
 	if spec.ComponentsOverride.Etcd.ClusterSize == 0 {
 		spec.ComponentsOverride.Etcd.ClusterSize = kubermaticv1.DefaultEtcdClusterSize
 	}
+	if spec.CNIPlugin == nil {
+		spec.CNIPlugin = &kubermaticv1.CNIPluginSettings{Type: cni.DefaultType}
+	}
+	if spec.CNIPlugin.Version == "" {
+		// An unsupported type is left to the validation
+		if version, err := cni.DefaultVersion(spec.CNIPlugin.Type); err == nil {
+			spec.CNIPlugin.Version = version
+		}
+	}
 	return nil
 }
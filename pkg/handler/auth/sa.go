@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"net/http"
 
+	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
 	"k8c.io/kubermatic/v2/pkg/provider"
 	"k8c.io/kubermatic/v2/pkg/serviceaccount"
 
@@ -70,6 +71,11 @@ func (s *ServiceAccountAuthClient) Verify(ctx context.Context, token string) (To
 		return TokenClaims{}, fmt.Errorf("sa: the token %s has been revoked for %s", customClaims.TokenID, customClaims.Email)
 	}
 
+	// failing to record the usage must not prevent the authentication
+	if err := s.saTokenProvider.UpdateLastUsedUnsecured(rawToken, serviceaccount.Now()); err != nil {
+		kubermaticlog.Logger.Warnw("failed to record the last use of the service account token", "token", customClaims.TokenID, "error", err)
+	}
+
 	return TokenClaims{
 		Name:    customClaims.TokenID,
 		Email:   customClaims.Email,
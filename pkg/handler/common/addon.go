@@ -19,6 +19,7 @@ package common
 import (
 	"context"
 
+	addonutils "k8c.io/kubermatic/v2/pkg/addon"
 	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
 	kubermaticapiv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/handler/middleware"
@@ -50,6 +51,7 @@ func PatchAddonEndpoint(ctx context.Context, userInfoGetter provider.UserInfoGet
 		return nil, common.KubernetesErrorToHTTPError(err)
 	}
 	apiAddon.Spec.Variables = *rawVars
+	apiAddon.Spec.Version = addon.Spec.Version
 
 	if apiAddon.Labels == nil {
 		apiAddon.Labels = map[string]string{}
@@ -86,7 +88,7 @@ func CreateAddonEndpoint(ctx context.Context, userInfoGetter provider.UserInfoGe
 	if addon.Spec.ContinuouslyReconcile {
 		labels[addonEnsureLabelKey] = trueFlag
 	}
-	apiAddon, err := createAddon(ctx, userInfoGetter, cluster, rawVars, labels, projectID, addon.Name)
+	apiAddon, err := createAddon(ctx, userInfoGetter, cluster, rawVars, labels, projectID, addon.Name, addon.Spec.Version)
 	if err != nil {
 		return nil, common.KubernetesErrorToHTTPError(err)
 	}
@@ -135,6 +137,35 @@ func GetAddonEndpoint(ctx context.Context, userInfoGetter provider.UserInfoGette
 }
 
 func ListInstallableAddonEndpoint(ctx context.Context, userInfoGetter provider.UserInfoGetter, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, accessibleAddons sets.String, projectID, clusterID string) (interface{}, error) {
+	installable, err := listInstallableAddons(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, accessibleAddons, projectID, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	return installable.UnsortedList(), nil
+}
+
+// ListInstallableAddonVersionsEndpoint lists the installable addons together with their
+// version and dependencies from the addon catalog, if it is available.
+func ListInstallableAddonVersionsEndpoint(ctx context.Context, userInfoGetter provider.UserInfoGetter, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, accessibleAddons sets.String, catalog addonutils.Catalog, projectID, clusterID string) (interface{}, error) {
+	installable, err := listInstallableAddons(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, accessibleAddons, projectID, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []apiv1.InstallableAddon{}
+	for _, name := range installable.List() {
+		addon := apiv1.InstallableAddon{Name: name}
+		if metadata, ok := catalog[name]; ok {
+			addon.Version = metadata.Version
+			addon.Dependencies = metadata.Dependencies
+			addon.KubernetesVersions = metadata.KubernetesVersions
+		}
+		result = append(result, addon)
+	}
+	return result, nil
+}
+
+func listInstallableAddons(ctx context.Context, userInfoGetter provider.UserInfoGetter, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, accessibleAddons sets.String, projectID, clusterID string) (sets.String, error) {
 	cluster, err := GetCluster(ctx, projectProvider, privilegedProjectProvider, userInfoGetter, projectID, clusterID, nil)
 	if err != nil {
 		return nil, err
@@ -150,7 +181,7 @@ func ListInstallableAddonEndpoint(ctx context.Context, userInfoGetter provider.U
 		installedAddons.Insert(addon.Name)
 	}
 
-	return accessibleAddons.Difference(installedAddons).UnsortedList(), nil
+	return accessibleAddons.Difference(installedAddons), nil
 }
 
 func DeleteAddonEndpoint(ctx context.Context, userInfoGetter provider.UserInfoGetter, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, projectID, clusterID, addonID string) (interface{}, error) {
@@ -213,21 +244,21 @@ func updateAddon(ctx context.Context, userInfoGetter provider.UserInfoGetter, cl
 	return addonProvider.Update(userInfo, cluster, addon)
 }
 
-func createAddon(ctx context.Context, userInfoGetter provider.UserInfoGetter, cluster *kubermaticapiv1.Cluster, rawVars *runtime.RawExtension, labels map[string]string, projectID, name string) (*kubermaticapiv1.Addon, error) {
+func createAddon(ctx context.Context, userInfoGetter provider.UserInfoGetter, cluster *kubermaticapiv1.Cluster, rawVars *runtime.RawExtension, labels map[string]string, projectID, name, version string) (*kubermaticapiv1.Addon, error) {
 	adminUserInfo, err := userInfoGetter(ctx, "")
 	if err != nil {
 		return nil, err
 	}
 	if adminUserInfo.IsAdmin {
 		privilegedAddonProvider := ctx.Value(middleware.PrivilegedAddonProviderContextKey).(provider.PrivilegedAddonProvider)
-		return privilegedAddonProvider.NewUnsecured(cluster, name, rawVars, labels)
+		return privilegedAddonProvider.NewUnsecured(cluster, name, version, rawVars, labels)
 	}
 	userInfo, err := userInfoGetter(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
 	addonProvider := ctx.Value(middleware.AddonProviderContextKey).(provider.AddonProvider)
-	return addonProvider.New(userInfo, cluster, name, rawVars, labels)
+	return addonProvider.New(userInfo, cluster, name, version, rawVars, labels)
 
 }
 
@@ -281,6 +312,7 @@ func convertInternalAddonToExternal(internalAddon *kubermaticapiv1.Addon) (*apiv
 		},
 		Spec: apiv1.AddonSpec{
 			IsDefault: internalAddon.Spec.IsDefault,
+			Version:   internalAddon.Spec.Version,
 		},
 	}
 	if len(internalAddon.Spec.Variables.Raw) > 0 {
@@ -291,6 +323,17 @@ func convertInternalAddonToExternal(internalAddon *kubermaticapiv1.Addon) (*apiv
 	if internalAddon.Labels != nil && internalAddon.Labels[addonEnsureLabelKey] == trueFlag {
 		result.Spec.ContinuouslyReconcile = true
 	}
+	if len(internalAddon.Status.Conditions) > 0 || internalAddon.Status.Version != "" {
+		result.Status = &apiv1.AddonStatus{Version: internalAddon.Status.Version}
+		for _, condition := range internalAddon.Status.Conditions {
+			result.Status.Conditions = append(result.Status.Conditions, apiv1.AddonCondition{
+				Type:               string(condition.Type),
+				Status:             string(condition.Status),
+				Message:            condition.Message,
+				LastTransitionTime: apiv1.NewTime(condition.LastTransitionTime.Time),
+			})
+		}
+	}
 
 	return result, nil
 }
@@ -36,6 +36,7 @@ import (
 	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
 	"k8c.io/kubermatic/v2/pkg/provider"
 	kubernetesprovider "k8c.io/kubermatic/v2/pkg/provider/kubernetes"
+	"k8c.io/kubermatic/v2/pkg/quota"
 	"k8c.io/kubermatic/v2/pkg/resources/cloudcontroller"
 	"k8c.io/kubermatic/v2/pkg/resources/cluster"
 	"k8c.io/kubermatic/v2/pkg/util/errors"
@@ -121,6 +122,9 @@ func CreateEndpoint(ctx context.Context, projectID string, body apiv1.CreateClus
 	if err = validation.ValidateUpdateWindow(spec.UpdateWindow); err != nil {
 		return nil, common.KubernetesErrorToHTTPError(err)
 	}
+	if err = validation.ValidateHibernation(spec.Hibernation); err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
 
 	// Start filling cluster object.
 	partialCluster := &kubermaticv1.Cluster{}
@@ -153,6 +157,22 @@ func CreateEndpoint(ctx context.Context, projectID string, body apiv1.CreateClus
 		}
 	}
 
+	requestedResources := kubermaticv1.ResourceUsage{Clusters: 1}
+	initialNodeDeployment, err := quota.InitialNodeDeployment(partialCluster)
+	if err != nil {
+		return nil, err
+	}
+	if initialNodeDeployment != nil {
+		nodeDeploymentResources, err := quota.NodeDeploymentUsage(initialNodeDeployment)
+		if err != nil {
+			return nil, errors.NewBadRequest("invalid initial node deployment: %v", err)
+		}
+		requestedResources.Add(nodeDeploymentResources)
+	}
+	if err := ensureProjectClusterResourceQuota(ctx, privilegedClusterProvider.GetSeedClusterAdminRuntimeClient(), project, requestedResources); err != nil {
+		return nil, err
+	}
+
 	// Owning project ID must be set early, because it will be inherited by some child objects,
 	// for example the credentials secret.
 	partialCluster.Labels[kubermaticv1.ProjectIDLabelKey] = projectID
@@ -342,6 +362,7 @@ func PatchEndpoint(ctx context.Context, userInfoGetter provider.UserInfoGetter,
 	newInternalCluster.Labels = patchedCluster.Labels
 	newInternalCluster.Spec.Cloud = patchedCluster.Spec.Cloud
 	newInternalCluster.Spec.MachineNetworks = patchedCluster.Spec.MachineNetworks
+	newInternalCluster.Spec.CNIPlugin = patchedCluster.Spec.CNIPlugin
 	newInternalCluster.Spec.Version = patchedCluster.Spec.Version
 	newInternalCluster.Spec.OIDC = patchedCluster.Spec.OIDC
 	newInternalCluster.Spec.UsePodSecurityPolicyAdmissionPlugin = patchedCluster.Spec.UsePodSecurityPolicyAdmissionPlugin
@@ -349,6 +370,7 @@ func PatchEndpoint(ctx context.Context, userInfoGetter provider.UserInfoGetter,
 	newInternalCluster.Spec.AdmissionPlugins = patchedCluster.Spec.AdmissionPlugins
 	newInternalCluster.Spec.AuditLogging = patchedCluster.Spec.AuditLogging
 	newInternalCluster.Spec.UpdateWindow = patchedCluster.Spec.UpdateWindow
+	newInternalCluster.Spec.Hibernation = patchedCluster.Spec.Hibernation
 	newInternalCluster.Spec.OPAIntegration = patchedCluster.Spec.OPAIntegration
 	newInternalCluster.Spec.PodNodeSelectorAdmissionPluginConfig = patchedCluster.Spec.PodNodeSelectorAdmissionPluginConfig
 	newInternalCluster.Spec.ServiceAccount = patchedCluster.Spec.ServiceAccount
@@ -397,6 +419,9 @@ func PatchEndpoint(ctx context.Context, userInfoGetter provider.UserInfoGetter,
 	if err = validation.ValidateUpdateWindow(newInternalCluster.Spec.UpdateWindow); err != nil {
 		return nil, common.KubernetesErrorToHTTPError(err)
 	}
+	if err = validation.ValidateHibernation(newInternalCluster.Spec.Hibernation); err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
 
 	updatedCluster, err := updateCluster(ctx, userInfoGetter, clusterProvider, privilegedClusterProvider, project, newInternalCluster)
 	if err != nil {
@@ -464,6 +489,8 @@ func HealthEndpoint(ctx context.Context, userInfoGetter provider.UserInfoGetter,
 		UserClusterControllerManager: existingCluster.Status.ExtendedHealth.UserClusterControllerManager,
 		GatekeeperController:         existingCluster.Status.ExtendedHealth.GatekeeperController,
 		GatekeeperAudit:              existingCluster.Status.ExtendedHealth.GatekeeperAudit,
+		TunnelingAgent:               existingCluster.Status.ExtendedHealth.TunnelingAgent,
+		Addons:                       existingCluster.Status.ExtendedHealth.Addons,
 	}, nil
 }
 
@@ -828,8 +855,11 @@ func convertInternalClusterToExternal(internalCluster *kubermaticv1.Cluster, fil
 			Cloud:                                internalCluster.Spec.Cloud,
 			Version:                              internalCluster.Spec.Version,
 			MachineNetworks:                      internalCluster.Spec.MachineNetworks,
+			ClusterNetwork:                       ConvertInternalClusterNetworkToExternal(internalCluster.Spec.ClusterNetwork),
+			CNIPlugin:                            internalCluster.Spec.CNIPlugin,
 			OIDC:                                 internalCluster.Spec.OIDC,
 			UpdateWindow:                         internalCluster.Spec.UpdateWindow,
+			Hibernation:                          internalCluster.Spec.Hibernation,
 			AuditLogging:                         internalCluster.Spec.AuditLogging,
 			UsePodSecurityPolicyAdmissionPlugin:  internalCluster.Spec.UsePodSecurityPolicyAdmissionPlugin,
 			UsePodNodeSelectorAdmissionPlugin:    internalCluster.Spec.UsePodNodeSelectorAdmissionPlugin,
@@ -854,6 +884,14 @@ func convertInternalClusterToExternal(internalCluster *kubermaticv1.Cluster, fil
 	return cluster
 }
 
+// ConvertInternalClusterNetworkToExternal returns the network configuration of the cluster, nil if it was not set yet.
+func ConvertInternalClusterNetworkToExternal(network kubermaticv1.ClusterNetworkingConfig) *kubermaticv1.ClusterNetworkingConfig {
+	if len(network.Pods.CIDRBlocks) == 0 && len(network.Services.CIDRBlocks) == 0 && network.DNSDomain == "" && network.ProxyMode == "" {
+		return nil
+	}
+	return &network
+}
+
 func ValidateClusterSpec(clusterType kubermaticv1.ClusterType, updateManager common.UpdateManager, body apiv1.CreateClusterSpec) error {
 	if body.Cluster.Spec.Cloud.DatacenterName == "" {
 		return fmt.Errorf("cluster datacenter name is empty")
@@ -0,0 +1,98 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
+	apiv2 "k8c.io/kubermatic/v2/pkg/api/v2"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/handler/middleware"
+	"k8c.io/kubermatic/v2/pkg/handler/v1/common"
+	"k8c.io/kubermatic/v2/pkg/provider"
+	"k8c.io/kubermatic/v2/pkg/util/errors"
+	"k8c.io/kubermatic/v2/pkg/util/hibernationschedule"
+)
+
+// HibernateEndpoint hibernates or resumes the cluster. A schedule of the cluster overrides the
+// requested state at its next activation.
+func HibernateEndpoint(ctx context.Context, userInfoGetter provider.UserInfoGetter, projectID, clusterID string, hibernated bool, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider) (interface{}, error) {
+	clusterProvider := ctx.Value(middleware.ClusterProviderContextKey).(provider.ClusterProvider)
+	privilegedClusterProvider := ctx.Value(middleware.PrivilegedClusterProviderContextKey).(provider.PrivilegedClusterProvider)
+
+	project, err := common.GetProject(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, projectID, nil)
+	if err != nil {
+		return nil, common.KubernetesErrorToHTTPError(err)
+	}
+
+	cluster, err := GetCluster(ctx, projectProvider, privilegedProjectProvider, userInfoGetter, projectID, clusterID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if cluster.DeletionTimestamp != nil {
+		return nil, errors.NewBadRequest("cluster %s is being deleted", clusterID)
+	}
+
+	if cluster.Spec.Hibernation == nil {
+		cluster.Spec.Hibernation = &kubermaticv1.HibernationSettings{}
+	}
+	cluster.Spec.Hibernation.Hibernated = hibernated
+
+	updatedCluster, err := updateCluster(ctx, userInfoGetter, clusterProvider, privilegedClusterProvider, project, cluster)
+	if err != nil {
+		return nil, common.KubernetesErrorToHTTPError(err)
+	}
+	return convertClusterHibernation(updatedCluster)
+}
+
+// GetHibernationEndpoint returns the hibernation settings and the current hibernation phase of the cluster
+// together with the next activations of its schedule
+func GetHibernationEndpoint(ctx context.Context, userInfoGetter provider.UserInfoGetter, projectID, clusterID string, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider) (interface{}, error) {
+	cluster, err := GetCluster(ctx, projectProvider, privilegedProjectProvider, userInfoGetter, projectID, clusterID, nil)
+	if err != nil {
+		return nil, err
+	}
+	return convertClusterHibernation(cluster)
+}
+
+func convertClusterHibernation(cluster *kubermaticv1.Cluster) (*apiv2.ClusterHibernation, error) {
+	schedule, err := hibernationschedule.ForCluster(cluster)
+	if err != nil {
+		return nil, errors.New(http.StatusInternalServerError, fmt.Sprintf("invalid hibernation schedule: %v", err))
+	}
+
+	hibernation := &apiv2.ClusterHibernation{Settings: cluster.Spec.Hibernation}
+	if status := cluster.Status.Hibernation; status != nil {
+		hibernation.Phase = string(status.Phase)
+		hibernation.Message = status.Message
+	}
+
+	nextHibernation, nextResumption := schedule.Next(time.Now())
+	if !nextHibernation.IsZero() {
+		t := apiv1.NewTime(nextHibernation)
+		hibernation.NextHibernationTime = &t
+	}
+	if !nextResumption.IsZero() {
+		t := apiv1.NewTime(nextResumption)
+		hibernation.NextResumptionTime = &t
+	}
+	return hibernation, nil
+}
@@ -24,11 +24,11 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"strings"
 
 	"github.com/gorilla/securecookie"
 
 	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
+	"k8c.io/kubermatic/v2/pkg/controller/master-controller-manager/rbac"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/handler/auth"
 	"k8c.io/kubermatic/v2/pkg/handler/middleware"
@@ -36,6 +36,7 @@ import (
 	"k8c.io/kubermatic/v2/pkg/provider"
 	kcerrors "k8c.io/kubermatic/v2/pkg/util/errors"
 
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
@@ -54,7 +55,7 @@ const (
 
 var secureCookie *securecookie.SecureCookie
 
-func GetAdminKubeconfigEndpoint(ctx context.Context, userInfoGetter provider.UserInfoGetter, projectID, clusterID string, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider) (interface{}, error) {
+func GetAdminKubeconfigEndpoint(ctx context.Context, userInfoGetter provider.UserInfoGetter, projectID, clusterID string, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, projectRoleProvider provider.ProjectRoleProvider) (interface{}, error) {
 	clusterProvider := ctx.Value(middleware.ClusterProviderContextKey).(provider.ClusterProvider)
 	cluster, err := GetCluster(ctx, projectProvider, privilegedProjectProvider, userInfoGetter, projectID, clusterID, nil)
 	if err != nil {
@@ -81,11 +82,14 @@ func GetAdminKubeconfigEndpoint(ctx context.Context, userInfoGetter provider.Use
 	if err != nil {
 		return nil, common.KubernetesErrorToHTTPError(err)
 	}
-	if strings.HasPrefix(userInfo.Group, "viewers") {
-		filePrefix = "viewer"
-		adminClientCfg, err = clusterProvider.GetViewerKubeconfigForCustomerCluster(cluster)
-	} else {
+	filePrefix, err = kubeconfigPrefixForGroup(projectRoleProvider, userInfo.Group)
+	if err != nil {
+		return nil, err
+	}
+	if filePrefix == "admin" {
 		adminClientCfg, err = clusterProvider.GetAdminKubeconfigForCustomerCluster(cluster)
+	} else {
+		adminClientCfg, err = clusterProvider.GetViewerKubeconfigForCustomerCluster(cluster)
 	}
 	if err != nil {
 		return nil, common.KubernetesErrorToHTTPError(err)
@@ -93,6 +97,26 @@ func GetAdminKubeconfigEndpoint(ctx context.Context, userInfoGetter provider.Use
 	return &encodeKubeConifgResponse{clientCfg: adminClientCfg, filePrefix: filePrefix}, nil
 }
 
+// kubeconfigPrefixForGroup returns the kind of kubeconfig the members of the given group get. Only owners and editors
+// get the admin kubeconfig, the verbs of custom project roles are only enforced by the API, thus their members get the
+// viewer kubeconfig. Groups which are neither built-in nor defined by a custom project role are denied.
+func kubeconfigPrefixForGroup(projectRoleProvider provider.ProjectRoleProvider, groupName string) (string, error) {
+	groupPrefix := rbac.ExtractGroupPrefix(groupName)
+	switch groupPrefix {
+	case rbac.OwnerGroupNamePrefix, rbac.EditorGroupNamePrefix:
+		return "admin", nil
+	case rbac.ViewerGroupNamePrefix:
+		return "viewer", nil
+	}
+	if _, err := projectRoleProvider.Get(groupPrefix); err != nil {
+		if kerrors.IsNotFound(err) {
+			return "", kcerrors.New(http.StatusForbidden, fmt.Sprintf("forbidden: the project role %q does not exist", groupPrefix))
+		}
+		return "", common.KubernetesErrorToHTTPError(err)
+	}
+	return "viewer", nil
+}
+
 func GetOidcKubeconfigEndpoint(ctx context.Context, userInfoGetter provider.UserInfoGetter, projectID, clusterID string, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider) (interface{}, error) {
 	clusterProvider := ctx.Value(middleware.ClusterProviderContextKey).(provider.ClusterProvider)
 
@@ -36,6 +36,7 @@ import (
 	machineconversions "k8c.io/kubermatic/v2/pkg/machine"
 	"k8c.io/kubermatic/v2/pkg/provider"
 	kubernetesprovider "k8c.io/kubermatic/v2/pkg/provider/kubernetes"
+	"k8c.io/kubermatic/v2/pkg/quota"
 	machineresource "k8c.io/kubermatic/v2/pkg/resources/machine"
 	k8cerrors "k8c.io/kubermatic/v2/pkg/util/errors"
 	"k8c.io/kubermatic/v2/pkg/validation/nodeupdate"
@@ -104,6 +105,14 @@ func CreateMachineDeployment(ctx context.Context, userInfoGetter provider.UserIn
 		return nil, k8cerrors.NewBadRequest(fmt.Sprintf("node deployment validation failed: %s", err.Error()))
 	}
 
+	requestedResources, err := quota.NodeDeploymentUsage(nd)
+	if err != nil {
+		return nil, k8cerrors.NewBadRequest(fmt.Sprintf("invalid node deployment: %v", err))
+	}
+	if err := ensureProjectResourceQuota(project, requestedResources); err != nil {
+		return nil, err
+	}
+
 	assertedClusterProvider, ok := clusterProvider.(*kubernetesprovider.ClusterProvider)
 	if !ok {
 		return nil, k8cerrors.New(http.StatusInternalServerError, "clusterprovider is not a kubernetesprovider.Clusterprovider, can not create secret")
@@ -508,6 +517,20 @@ func PatchMachineDeployment(ctx context.Context, userInfoGetter provider.UserInf
 		return nil, fmt.Errorf("failed to create machine deployment from template: %v", err)
 	}
 
+	// Only the difference to the current machine deployment counts against the resource quota of the project.
+	requestedResources, err := quota.NodeDeploymentUsage(patchedNodeDeployment)
+	if err != nil {
+		return nil, k8cerrors.NewBadRequest(fmt.Sprintf("invalid node deployment: %v", err))
+	}
+	currentResources, err := quota.MachineDeploymentUsage(machineDeployment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the resources of the machine deployment: %v", err)
+	}
+	requestedResources.Sub(currentResources)
+	if err := ensureProjectResourceQuota(project, requestedResources); err != nil {
+		return nil, err
+	}
+
 	// Only the fields from NodeDeploymentSpec will be updated by a patch.
 	// It ensures that the name and resource version are set and the selector stays the same.
 	machineDeployment.Spec.Template.Spec = patchedMachineDeployment.Spec.Template.Spec
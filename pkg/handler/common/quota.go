@@ -0,0 +1,57 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/quota"
+	"k8c.io/kubermatic/v2/pkg/util/errors"
+
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ensureProjectResourceQuota returns a forbidden error if the requested resources would exceed the resource quota
+// of the project. The usage of the project is taken from its status, which the controllers keep up to date, so
+// that the clusters of the project don't have to be contacted for every request.
+func ensureProjectResourceQuota(project *kubermaticv1.Project, requested kubermaticv1.ResourceUsage) error {
+	if err := quota.Check(project.Spec.ResourceQuota, quota.ProjectUsage(project), requested); err != nil {
+		return errors.New(http.StatusForbidden, err.Error())
+	}
+	return nil
+}
+
+// ensureProjectClusterResourceQuota returns a forbidden error if a new cluster requesting the given resources would
+// exceed the resource quota of the project. Besides the status of the project, the clusters of the project in the
+// seed are listed, as the status is not updated before the next cluster might be requested.
+func ensureProjectClusterResourceQuota(ctx context.Context, seedClient ctrlruntimeclient.Client, project *kubermaticv1.Project, requested kubermaticv1.ResourceUsage) error {
+	clusters := &kubermaticv1.ClusterList{}
+	if err := seedClient.List(ctx, clusters, ctrlruntimeclient.MatchingLabels{kubermaticv1.ProjectIDLabelKey: project.Name}); err != nil {
+		return fmt.Errorf("failed to list the clusters of the project: %v", err)
+	}
+	usage, err := quota.AdmissionUsage(project, clusters.Items)
+	if err != nil {
+		return err
+	}
+	if err := quota.Check(project.Spec.ResourceQuota, usage, requested); err != nil {
+		return errors.New(http.StatusForbidden, err.Error())
+	}
+	return nil
+}
@@ -18,7 +18,9 @@ package common
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
 
@@ -28,6 +30,7 @@ import (
 	"k8c.io/kubermatic/v2/pkg/handler/v1/common"
 	"k8c.io/kubermatic/v2/pkg/provider"
 	"k8c.io/kubermatic/v2/pkg/util/errors"
+	"k8c.io/kubermatic/v2/pkg/util/updatewindow"
 	"k8c.io/kubermatic/v2/pkg/validation/nodeupdate"
 	"k8c.io/kubermatic/v2/pkg/version"
 
@@ -79,6 +82,43 @@ func GetUpgradesEndpoint(ctx context.Context, userInfoGetter provider.UserInfoGe
 	return upgrades, nil
 }
 
+// GetUpgradeScheduleEndpoint returns the automatic upgrade of the cluster and the earliest time it is applied at
+func GetUpgradeScheduleEndpoint(ctx context.Context, userInfoGetter provider.UserInfoGetter, projectID, clusterID string, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, updateManager common.UpdateManager) (interface{}, error) {
+	cluster, err := GetCluster(ctx, projectProvider, privilegedProjectProvider, userInfoGetter, projectID, clusterID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	window, err := updatewindow.ForCluster(cluster)
+	if err != nil {
+		return nil, errors.New(http.StatusInternalServerError, fmt.Sprintf("invalid update window: %v", err))
+	}
+
+	schedule := &apiv1.ClusterUpgradeSchedule{UpdateWindow: cluster.Spec.UpdateWindow}
+	if schedule.UpdateWindow == nil {
+		schedule.UpdateWindow = cluster.Status.InheritedUpdateWindow
+	}
+
+	if upgrade := cluster.Status.Upgrade; upgrade.InProgress() {
+		schedule.TargetVersion = upgrade.ToVersion
+		schedule.Stage = string(upgrade.Stage)
+		schedule.Message = upgrade.Message
+	} else {
+		update, err := updateManager.AutomaticControlplaneUpdate(cluster.Spec.Version.String(), apiv1.KubernetesClusterType)
+		if err != nil {
+			return nil, err
+		}
+		if update == nil {
+			return schedule, nil
+		}
+		schedule.TargetVersion = update.Version.String()
+	}
+
+	nextUpgradeTime := apiv1.NewTime(window.Next(time.Now()))
+	schedule.NextUpgradeTime = &nextUpgradeTime
+	return schedule, nil
+}
+
 func UpgradeNodeDeploymentsEndpoint(ctx context.Context, userInfoGetter provider.UserInfoGetter, projectID, clusterID string, version apiv1.MasterVersion, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider) (interface{}, error) {
 	clusterProvider := ctx.Value(middleware.ClusterProviderContextKey).(provider.ClusterProvider)
 
@@ -0,0 +1,337 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-kit/kit/endpoint"
+	transporthttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+
+	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
+	apiv2 "k8c.io/kubermatic/v2/pkg/api/v2"
+	"k8c.io/kubermatic/v2/pkg/audit"
+	"k8c.io/kubermatic/v2/pkg/controller/master-controller-manager/rbac"
+	kubermaticapiv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/handler/v1/common"
+	"k8c.io/kubermatic/v2/pkg/provider"
+	kubermaticcontext "k8c.io/kubermatic/v2/pkg/util/context"
+	k8cerrors "k8c.io/kubermatic/v2/pkg/util/errors"
+)
+
+const (
+	// auditRequestContextKey key under which the HTTP details of the current request are kept in the ctx
+	auditRequestContextKey kubermaticcontext.Key = "audit-request"
+
+	// redactedValue replaces sensitive values in the audited request bodies
+	redactedValue = "REDACTED"
+)
+
+// sensitiveFields are the parts of field names in request bodies whose values are never audited
+var sensitiveFields = []string{"password", "secret", "token", "credential", "kubeconfig", "privatekey", "accesskey", "apikey", "serviceaccount"}
+
+// auditRequest are the HTTP details of an audited request
+type auditRequest struct {
+	method   string
+	endpoint string
+	vars     map[string]string
+	// current returns the resource at the path of the request as the user sees it, nil if it can't be read
+	current func() interface{}
+}
+
+// AuditRequestExtractor stores the HTTP method, the route and the route variables of the incoming request in the ctx.
+// The handler serves the GET requests which read the resources before they are changed.
+func AuditRequestExtractor(handler http.Handler) transporthttp.RequestFunc {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		req := auditRequest{method: r.Method, endpoint: r.URL.Path, vars: mux.Vars(r)}
+		if route := mux.CurrentRoute(r); route != nil {
+			if template, err := route.GetPathTemplate(); err == nil {
+				req.endpoint = template
+			}
+		}
+		req.current = func() interface{} {
+			return readResource(handler, r)
+		}
+		return context.WithValue(ctx, auditRequestContextKey, req)
+	}
+}
+
+// readResource sends a GET request with the credentials of the given request to its path and returns the decoded
+// response, nil if the request failed
+func readResource(handler http.Handler, r *http.Request) interface{} {
+	if handler == nil {
+		return nil
+	}
+
+	get, err := http.NewRequestWithContext(r.Context(), http.MethodGet, r.URL.Path, nil)
+	if err != nil {
+		return nil
+	}
+	get.Header = r.Header.Clone()
+
+	resp := &bufferedResponseWriter{header: http.Header{}, code: http.StatusOK}
+	handler.ServeHTTP(resp, get)
+	if resp.code != http.StatusOK {
+		return nil
+	}
+
+	var resource interface{}
+	if err := json.Unmarshal(resp.body.Bytes(), &resource); err != nil {
+		return nil
+	}
+	return resource
+}
+
+// bufferedResponseWriter keeps the response in memory
+type bufferedResponseWriter struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func (w *bufferedResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedResponseWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedResponseWriter) WriteHeader(code int) {
+	w.code = code
+}
+
+// Audit is a middleware that records who sent the request, what it changed and whether it succeeded.
+// It must be used after UserSaver, so that the user is known.
+func Audit(auditLogger *audit.Logger, userInfoGetter provider.UserInfoGetter) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+			event := &apiv2.AuditEvent{
+				Time:    apiv1.Now(),
+				Request: auditRequestBody(request),
+			}
+
+			// the changed resource is read before the request, it is created by POST requests
+			var before interface{}
+			if req, ok := ctx.Value(auditRequestContextKey).(auditRequest); ok {
+				event.Method = req.method
+				event.Endpoint = req.endpoint
+				if len(req.vars) > 0 {
+					event.Resources = req.vars
+				}
+				if req.method != http.MethodPost && req.current != nil {
+					before = req.current()
+				}
+			}
+
+			if user, ok := ctx.Value(kubermaticcontext.UserCRContextKey).(*kubermaticapiv1.User); ok {
+				event.User = user.Spec.Email
+				event.UserID = user.Name
+				event.Admin = user.Spec.IsAdmin
+			}
+
+			// the project role is best effort, the endpoint itself rejects users who are not members
+			if prjIDGetter, ok := request.(common.ProjectIDGetter); ok && prjIDGetter.GetProjectID() != "" {
+				if userInfo, err := userInfoGetter(ctx, prjIDGetter.GetProjectID()); err == nil && userInfo.Group != "" {
+					event.ProjectRole = rbac.ExtractGroupPrefix(userInfo.Group)
+				}
+			}
+
+			response, err = next(ctx, request)
+
+			event.Outcome = apiv2.AuditOutcomeSucceeded
+			if err != nil {
+				event.Outcome = apiv2.AuditOutcomeFailed
+				event.Code = http.StatusInternalServerError
+				event.Error = err.Error()
+				if httpErr, ok := err.(k8cerrors.HTTPError); ok {
+					event.Code = httpErr.StatusCode()
+				}
+			} else {
+				// deleted resources are gone, all other endpoints respond with the changed resource
+				var after interface{}
+				if event.Method != http.MethodDelete {
+					after = decode(response)
+				}
+				event.Before, event.After = auditDiff(before, after)
+			}
+			auditLogger.Log(event)
+
+			return response, err
+		}
+	}
+}
+
+// auditDiff returns the fields which differ between the resource before and after the request, values of
+// sensitive fields are redacted
+func auditDiff(before, after interface{}) (json.RawMessage, json.RawMessage) {
+	before, after = changes(redact(before), redact(after))
+	if isEmptyObject(before) {
+		before = nil
+	}
+	if isEmptyObject(after) {
+		after = nil
+	}
+	return encode(before), encode(after)
+}
+
+// changes returns the parts of before and after which differ, nested objects are compared field by field
+func changes(before, after interface{}) (interface{}, interface{}) {
+	if reflect.DeepEqual(before, after) {
+		return nil, nil
+	}
+
+	beforeFields, beforeIsObject := before.(map[string]interface{})
+	afterFields, afterIsObject := after.(map[string]interface{})
+	if !beforeIsObject || !afterIsObject {
+		return before, after
+	}
+
+	beforeChanges := map[string]interface{}{}
+	afterChanges := map[string]interface{}{}
+	for key := range beforeFields {
+		if _, ok := afterFields[key]; !ok {
+			beforeChanges[key] = beforeFields[key]
+		}
+	}
+	for key, afterField := range afterFields {
+		beforeField, ok := beforeFields[key]
+		if !ok {
+			afterChanges[key] = afterField
+			continue
+		}
+		// objects are omitted on the side which has no changed fields in them
+		beforeChange, afterChange := changes(beforeField, afterField)
+		if !isEmptyObject(beforeChange) {
+			beforeChanges[key] = beforeChange
+		}
+		if !isEmptyObject(afterChange) {
+			afterChanges[key] = afterChange
+		}
+	}
+
+	return beforeChanges, afterChanges
+}
+
+func isEmptyObject(value interface{}) bool {
+	fields, ok := value.(map[string]interface{})
+	return value == nil || ok && len(fields) == 0
+}
+
+// decode returns the value as decoded JSON, nil if it is empty
+func decode(value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil
+	}
+	if fields, ok := decoded.(map[string]interface{}); ok && len(fields) == 0 {
+		return nil
+	}
+	return decoded
+}
+
+// encode returns the decoded JSON value as JSON, nil if the value is empty
+func encode(value interface{}) json.RawMessage {
+	if value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// auditRequestBody returns the body of the request as JSON, values of sensitive fields are redacted
+func auditRequestBody(request interface{}) json.RawMessage {
+	value := reflect.Indirect(reflect.ValueOf(request))
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var body reflect.Value
+	for _, name := range []string{"Body", "Patch"} {
+		if body = value.FieldByName(name); body.IsValid() {
+			break
+		}
+	}
+	if !body.IsValid() {
+		return nil
+	}
+
+	var data []byte
+	if raw, ok := body.Interface().([]byte); ok {
+		data = raw
+	} else if raw, ok := body.Interface().(json.RawMessage); ok {
+		data = raw
+	} else {
+		var err error
+		if data, err = json.Marshal(body.Interface()); err != nil {
+			return nil
+		}
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil
+	}
+	redacted, err := json.Marshal(redact(decoded))
+	if err != nil {
+		return nil
+	}
+	return redacted
+}
+
+// redact replaces the values of all sensitive fields
+func redact(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if isSensitiveField(key) && field != nil && field != "" {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = redact(field)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redact(v[i])
+		}
+	}
+	return value
+}
+
+func isSensitiveField(name string) bool {
+	name = strings.ToLower(name)
+	for _, sensitive := range sensitiveFields {
+		if strings.Contains(name, sensitive) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middleware
+
+import (
+	"encoding/json"
+	"testing"
+
+	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
+)
+
+func TestAuditRequestBody(t *testing.T) {
+	testCases := []struct {
+		name     string
+		request  interface{}
+		expected string
+	}{
+		{
+			name:     "request without body",
+			request:  struct{ ProjectID string }{ProjectID: "my-project"},
+			expected: "",
+		},
+		{
+			name: "typed body",
+			request: struct {
+				ProjectID string
+				Body      apiv1.ServiceAccount
+			}{Body: apiv1.ServiceAccount{ObjectMeta: apiv1.ObjectMeta{Name: "ci"}, Group: "editors"}},
+			expected: `{"creationTimestamp":"0001-01-01T00:00:00Z","group":"editors","name":"ci","status":""}`,
+		},
+		{
+			name: "patch with secrets",
+			request: struct {
+				Patch json.RawMessage
+			}{Patch: json.RawMessage(`{"spec":{"cloud":{"aws":{"accessKeyID":"AKIA","secretAccessKey":"secret","vpcId":"vpc-1"}}}}`)},
+			expected: `{"spec":{"cloud":{"aws":{"accessKeyID":"REDACTED","secretAccessKey":"REDACTED","vpcId":"vpc-1"}}}}`,
+		},
+		{
+			name: "raw body with secrets in a list",
+			request: &struct {
+				Body []byte
+			}{Body: []byte(`{"members":[{"name":"a","password":"hunter2"},{"name":"b","password":""}]}`)},
+			expected: `{"members":[{"name":"a","password":"REDACTED"},{"name":"b","password":""}]}`,
+		},
+		{
+			name: "body which is no JSON",
+			request: struct {
+				Body []byte
+			}{Body: []byte("not json")},
+			expected: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := string(auditRequestBody(tc.request)); result != tc.expected {
+				t.Errorf("expected request body %s, got %s", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestAuditDiff(t *testing.T) {
+	testCases := []struct {
+		name           string
+		before         interface{}
+		after          interface{}
+		expectedBefore string
+		expectedAfter  string
+	}{
+		{
+			name:          "created resource",
+			after:         map[string]interface{}{"name": "ci", "group": "editors"},
+			expectedAfter: `{"group":"editors","name":"ci"}`,
+		},
+		{
+			name:           "deleted resource",
+			before:         map[string]interface{}{"name": "ci", "group": "editors"},
+			expectedBefore: `{"group":"editors","name":"ci"}`,
+		},
+		{
+			name:   "unchanged resource",
+			before: map[string]interface{}{"name": "ci"},
+			after:  map[string]interface{}{"name": "ci"},
+		},
+		{
+			name: "changed, added and removed fields",
+			before: map[string]interface{}{
+				"name":   "cluster",
+				"labels": map[string]interface{}{"team": "a"},
+				"spec":   map[string]interface{}{"version": "1.19.4", "auditLogging": true, "machineNetworks": []interface{}{"10.0.0.0/8"}},
+			},
+			after: map[string]interface{}{
+				"name":   "cluster",
+				"labels": map[string]interface{}{"team": "a", "env": "prod"},
+				"spec":   map[string]interface{}{"version": "1.20.2", "machineNetworks": []interface{}{"10.0.0.0/8", "192.168.0.0/16"}},
+			},
+			expectedBefore: `{"spec":{"auditLogging":true,"machineNetworks":["10.0.0.0/8"],"version":"1.19.4"}}`,
+			expectedAfter:  `{"labels":{"env":"prod"},"spec":{"machineNetworks":["10.0.0.0/8","192.168.0.0/16"],"version":"1.20.2"}}`,
+		},
+		{
+			name:           "changed secrets",
+			before:         map[string]interface{}{"spec": map[string]interface{}{"aws": map[string]interface{}{"secretAccessKey": "old", "vpcId": "vpc-1"}}},
+			after:          map[string]interface{}{"spec": map[string]interface{}{"aws": map[string]interface{}{"secretAccessKey": "new", "vpcId": "vpc-2"}}},
+			expectedBefore: `{"spec":{"aws":{"vpcId":"vpc-1"}}}`,
+			expectedAfter:  `{"spec":{"aws":{"vpcId":"vpc-2"}}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			before, after := auditDiff(tc.before, tc.after)
+			if string(before) != tc.expectedBefore {
+				t.Errorf("expected before %s, got %s", tc.expectedBefore, before)
+			}
+			if string(after) != tc.expectedAfter {
+				t.Errorf("expected after %s, got %s", tc.expectedAfter, after)
+			}
+		})
+	}
+}
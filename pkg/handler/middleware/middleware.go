@@ -47,6 +47,9 @@ const (
 	// TokenExpiryContextKey key under which the current token expiry (OpenID ID Token) is kept in the ctx
 	TokenExpiryContextKey kubermaticcontext.Key = "auth-token-expiry"
 
+	// TokenGroupsContextKey key under which the groups claim of the current token (OpenID ID Token) is kept in the ctx
+	TokenGroupsContextKey kubermaticcontext.Key = "auth-token-groups"
+
 	// noTokenFoundKey key under which an error is kept when no suitable token has been found in a request
 	noTokenFoundKey kubermaticcontext.Key = "no-token-found"
 
@@ -122,7 +125,8 @@ func SetPrivilegedClusterProvider(clusterProviderGetter provider.ClusterProvider
 }
 
 // UserSaver is a middleware that checks if authenticated user already exists in the database
-// next it creates/retrieve an internal object (kubermaticv1.User) and stores it the ctx under UserCRContexKey
+// next it creates/retrieve an internal object (kubermaticv1.User) and stores it the ctx under UserCRContexKey.
+// The groups of the user are updated from the token, so that GroupProjectBindings apply to the current request.
 func UserSaver(userProvider provider.UserProvider) endpoint.Middleware {
 	return func(next endpoint.Endpoint) endpoint.Endpoint {
 		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
@@ -148,6 +152,17 @@ func UserSaver(userProvider provider.UserProvider) endpoint.Middleware {
 					}
 				}
 			}
+
+			tokenGroups, _ := ctx.Value(TokenGroupsContextKey).([]string)
+			if !sets.NewString(user.Spec.Groups...).Equal(sets.NewString(tokenGroups...)) {
+				updatedUser := user.DeepCopy()
+				updatedUser.Spec.Groups = sets.NewString(tokenGroups...).List()
+				// a conflict means that a concurrent request is updating the user, the groups of the token apply nevertheless
+				if _, err := userProvider.UpdateUser(updatedUser); err != nil && !kerrors.IsConflict(err) {
+					return nil, common.KubernetesErrorToHTTPError(err)
+				}
+				user = updatedUser
+			}
 			return next(context.WithValue(ctx, kubermaticcontext.UserCRContextKey, user), request)
 		}
 	}
@@ -237,6 +252,7 @@ func TokenVerifier(tokenVerifier auth.TokenVerifier, userProvider provider.UserP
 			}
 
 			ctx = context.WithValue(ctx, TokenExpiryContextKey, claims.Expiry)
+			ctx = context.WithValue(ctx, TokenGroupsContextKey, claims.Groups)
 			return next(context.WithValue(ctx, AuthenticatedUserContextKey, user), request)
 		}
 	}
@@ -316,7 +332,7 @@ func createUserInfo(user *kubermaticapiv1.User, projectID string, userProjectMap
 	var group string
 	if projectID != "" {
 		var err error
-		group, err = userProjectMapper.MapUserToGroup(user.Spec.Email, projectID)
+		group, err = userProjectMapper.MapUserToGroup(user, projectID)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,138 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-kit/kit/endpoint"
+
+	"k8c.io/kubermatic/v2/pkg/controller/master-controller-manager/rbac"
+	kubermaticapiv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/handler/v1/common"
+	"k8c.io/kubermatic/v2/pkg/provider"
+	k8cerrors "k8c.io/kubermatic/v2/pkg/util/errors"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// projectRoleRouteResources maps the path segments of the routes to the resource types of the custom project roles.
+// Cluster templates and the clusters created from them, their instances, are covered by the clusters resource.
+var projectRoleRouteResources = map[string]kubermaticapiv1.ProjectRoleResource{
+	"clusters":           kubermaticapiv1.ProjectRoleResourceClusters,
+	"clustertemplates":   kubermaticapiv1.ProjectRoleResourceClusters,
+	"instances":          kubermaticapiv1.ProjectRoleResourceClusters,
+	"machinedeployments": kubermaticapiv1.ProjectRoleResourceMachineDeployments,
+	"nodedeployments":    kubermaticapiv1.ProjectRoleResourceMachineDeployments,
+	"nodes":              kubermaticapiv1.ProjectRoleResourceMachineDeployments,
+	"sshkeys":            kubermaticapiv1.ProjectRoleResourceSSHKeys,
+	"addons":             kubermaticapiv1.ProjectRoleResourceAddons,
+	"constraints":        kubermaticapiv1.ProjectRoleResourceConstraints,
+	"alertmanager":       kubermaticapiv1.ProjectRoleResourceAlertmanagers,
+}
+
+// ProjectRoleAuthorizer is a middleware that checks the verbs of the members of custom project roles.
+// The members of the built-in groups and the admins are not restricted by it, neither are the users who are not
+// members of the project, the endpoints reject them. Groups which are neither built-in nor defined by a custom project
+// role, like the ones of a deleted role, are denied. It must be used after UserSaver, so that the user is known.
+func ProjectRoleAuthorizer(projectRoleProvider provider.ProjectRoleProvider, userInfoGetter provider.UserInfoGetter) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+			prjIDGetter, ok := request.(common.ProjectIDGetter)
+			if !ok || prjIDGetter.GetProjectID() == "" {
+				return next(ctx, request)
+			}
+			userInfo, err := userInfoGetter(ctx, prjIDGetter.GetProjectID())
+			if err != nil || userInfo.IsAdmin || userInfo.Group == "" {
+				return next(ctx, request)
+			}
+			groupPrefix := rbac.ExtractGroupPrefix(userInfo.Group)
+			if rbac.IsBuiltinGroupPrefix(groupPrefix) {
+				return next(ctx, request)
+			}
+
+			req, ok := ctx.Value(auditRequestContextKey).(auditRequest)
+			if !ok {
+				return nil, k8cerrors.New(http.StatusForbidden, "forbidden: unable to authorize the request for the project role")
+			}
+
+			role, err := projectRoleProvider.Get(groupPrefix)
+			if err != nil {
+				if kerrors.IsNotFound(err) {
+					return nil, k8cerrors.New(http.StatusForbidden, fmt.Sprintf("forbidden: the project role %q does not exist", groupPrefix))
+				}
+				return nil, common.KubernetesErrorToHTTPError(err)
+			}
+
+			resource, verb := projectRoleResourceAndVerb(req.method, req.endpoint)
+			if resource == "" {
+				if verb == kubermaticapiv1.ProjectRoleVerbGet {
+					return next(ctx, request)
+				}
+				return nil, k8cerrors.New(http.StatusForbidden, fmt.Sprintf("forbidden: the project role %q only allows to read the project", groupPrefix))
+			}
+			if !role.Allows(resource, verb) {
+				return nil, k8cerrors.New(http.StatusForbidden, fmt.Sprintf("forbidden: the project role %q does not allow to %s %s", groupPrefix, verb, resource))
+			}
+			return next(ctx, request)
+		}
+	}
+}
+
+// projectRoleResourceAndVerb returns the resource type and the verb of the custom project roles required for the request.
+// The resource is the last one in the route, the resource is empty if the route doesn't contain any known resource.
+// Requests changing a sub-resource, like the upgrades of a cluster, require the update verb on the resource.
+func projectRoleResourceAndVerb(method, route string) (kubermaticapiv1.ProjectRoleResource, string) {
+	var resource kubermaticapiv1.ProjectRoleResource
+	subResource := false
+	// the config of the alertmanager is the alertmanager itself
+	route = strings.Replace(route, "/alertmanager/config", "/alertmanager", 1)
+	segments := strings.Split(strings.Trim(route, "/"), "/")
+	for i, segment := range segments {
+		if res, ok := projectRoleRouteResources[segment]; ok {
+			resource = res
+			subResource = false
+			continue
+		}
+		// a variable following a resource is its identifier, all other segments address a sub-resource
+		isIdentifier := strings.HasPrefix(segment, "{") && i > 0 && projectRoleRouteResources[segments[i-1]] != ""
+		if resource != "" && !isIdentifier {
+			subResource = true
+		}
+	}
+
+	var verb string
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		return resource, kubermaticapiv1.ProjectRoleVerbGet
+	case http.MethodPost:
+		verb = kubermaticapiv1.ProjectRoleVerbCreate
+	case http.MethodPut, http.MethodPatch:
+		verb = kubermaticapiv1.ProjectRoleVerbUpdate
+	case http.MethodDelete:
+		verb = kubermaticapiv1.ProjectRoleVerbDelete
+	default:
+		verb = kubermaticapiv1.ProjectRoleVerbUpdate
+	}
+	if subResource {
+		verb = kubermaticapiv1.ProjectRoleVerbUpdate
+	}
+	return resource, verb
+}
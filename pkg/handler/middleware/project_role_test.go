@@ -0,0 +1,251 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	kubermaticapiv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/handler/v1/common"
+	"k8c.io/kubermatic/v2/pkg/provider"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestProjectRoleResourceAndVerb(t *testing.T) {
+	testCases := []struct {
+		method           string
+		route            string
+		expectedResource kubermaticapiv1.ProjectRoleResource
+		expectedVerb     string
+	}{
+		{
+			method:       http.MethodGet,
+			route:        "/api/v1/projects/{project_id}",
+			expectedVerb: kubermaticapiv1.ProjectRoleVerbGet,
+		},
+		{
+			method:       http.MethodPut,
+			route:        "/api/v1/projects/{project_id}",
+			expectedVerb: kubermaticapiv1.ProjectRoleVerbUpdate,
+		},
+		{
+			method:       http.MethodPost,
+			route:        "/api/v1/projects/{project_id}/serviceaccounts",
+			expectedVerb: kubermaticapiv1.ProjectRoleVerbCreate,
+		},
+		{
+			method:           http.MethodPost,
+			route:            "/api/v1/projects/{project_id}/dc/{dc}/clusters",
+			expectedResource: kubermaticapiv1.ProjectRoleResourceClusters,
+			expectedVerb:     kubermaticapiv1.ProjectRoleVerbCreate,
+		},
+		{
+			method:           http.MethodDelete,
+			route:            "/api/v2/projects/{project_id}/clusters/{cluster_id}",
+			expectedResource: kubermaticapiv1.ProjectRoleResourceClusters,
+			expectedVerb:     kubermaticapiv1.ProjectRoleVerbDelete,
+		},
+		{
+			method:           http.MethodGet,
+			route:            "/api/v2/projects/{project_id}/clusters/{cluster_id}/kubeconfig",
+			expectedResource: kubermaticapiv1.ProjectRoleResourceClusters,
+			expectedVerb:     kubermaticapiv1.ProjectRoleVerbGet,
+		},
+		{
+			method:           http.MethodPost,
+			route:            "/api/v2/projects/{project_id}/clusters/{cluster_id}/roles",
+			expectedResource: kubermaticapiv1.ProjectRoleResourceClusters,
+			expectedVerb:     kubermaticapiv1.ProjectRoleVerbUpdate,
+		},
+		{
+			method:           http.MethodPatch,
+			route:            "/api/v2/projects/{project_id}/clusters/{cluster_id}/machinedeployments/{machinedeployment_id}",
+			expectedResource: kubermaticapiv1.ProjectRoleResourceMachineDeployments,
+			expectedVerb:     kubermaticapiv1.ProjectRoleVerbUpdate,
+		},
+		{
+			method:           http.MethodDelete,
+			route:            "/api/v2/projects/{project_id}/clusters/{cluster_id}/machinedeployments/nodes/{node_id}",
+			expectedResource: kubermaticapiv1.ProjectRoleResourceMachineDeployments,
+			expectedVerb:     kubermaticapiv1.ProjectRoleVerbDelete,
+		},
+		{
+			method:           http.MethodPut,
+			route:            "/api/v1/projects/{project_id}/dc/{dc}/clusters/{cluster_id}/nodes/upgrades",
+			expectedResource: kubermaticapiv1.ProjectRoleResourceMachineDeployments,
+			expectedVerb:     kubermaticapiv1.ProjectRoleVerbUpdate,
+		},
+		{
+			method:           http.MethodPost,
+			route:            "/api/v1/projects/{project_id}/sshkeys",
+			expectedResource: kubermaticapiv1.ProjectRoleResourceSSHKeys,
+			expectedVerb:     kubermaticapiv1.ProjectRoleVerbCreate,
+		},
+		{
+			method:           http.MethodDelete,
+			route:            "/api/v2/projects/{project_id}/clusters/{cluster_id}/alertmanager/config",
+			expectedResource: kubermaticapiv1.ProjectRoleResourceAlertmanagers,
+			expectedVerb:     kubermaticapiv1.ProjectRoleVerbDelete,
+		},
+		{
+			method:           http.MethodPost,
+			route:            "/api/v2/projects/{project_id}/clusters/{cluster_id}/constraints",
+			expectedResource: kubermaticapiv1.ProjectRoleResourceConstraints,
+			expectedVerb:     kubermaticapiv1.ProjectRoleVerbCreate,
+		},
+		{
+			method:           http.MethodPut,
+			route:            "/api/v2/projects/{project_id}/clustertemplates/{template_id}",
+			expectedResource: kubermaticapiv1.ProjectRoleResourceClusters,
+			expectedVerb:     kubermaticapiv1.ProjectRoleVerbUpdate,
+		},
+		{
+			method:           http.MethodPost,
+			route:            "/api/v2/projects/{project_id}/clustertemplates/{template_id}/instances",
+			expectedResource: kubermaticapiv1.ProjectRoleResourceClusters,
+			expectedVerb:     kubermaticapiv1.ProjectRoleVerbCreate,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.method+" "+tc.route, func(t *testing.T) {
+			resource, verb := projectRoleResourceAndVerb(tc.method, tc.route)
+			if resource != tc.expectedResource {
+				t.Errorf("expected resource %q, got %q", tc.expectedResource, resource)
+			}
+			if verb != tc.expectedVerb {
+				t.Errorf("expected verb %q, got %q", tc.expectedVerb, verb)
+			}
+		})
+	}
+}
+
+type fakeProjectRoleProvider struct {
+	roles map[string]*kubermaticapiv1.ProjectRole
+}
+
+func (p fakeProjectRoleProvider) Get(name string) (*kubermaticapiv1.ProjectRole, error) {
+	if role, ok := p.roles[name]; ok {
+		return role, nil
+	}
+	return nil, kerrors.NewNotFound(schema.GroupResource{Resource: kubermaticapiv1.ProjectRoleResourceName}, name)
+}
+
+func (p fakeProjectRoleProvider) UserClusterGroupPrefix(groupName string) string {
+	return groupName
+}
+
+func TestProjectRoleAuthorizer(t *testing.T) {
+	roleProvider := fakeProjectRoleProvider{roles: map[string]*kubermaticapiv1.ProjectRole{
+		"operators": {
+			ObjectMeta: metav1.ObjectMeta{Name: "operators"},
+			Spec: kubermaticapiv1.ProjectRoleSpec{Rules: []kubermaticapiv1.ProjectRoleRule{
+				{Resource: kubermaticapiv1.ProjectRoleResourceClusters, Verbs: []string{"get"}},
+				{Resource: kubermaticapiv1.ProjectRoleResourceMachineDeployments, Verbs: []string{"get", "update"}},
+			}},
+		},
+	}}
+
+	testCases := []struct {
+		name          string
+		userInfo      provider.UserInfo
+		method        string
+		route         string
+		expectAllowed bool
+	}{
+		{
+			name:          "built-in group is not restricted",
+			userInfo:      provider.UserInfo{Group: "viewers-my-project"},
+			method:        http.MethodDelete,
+			route:         "/api/v2/projects/{project_id}/clusters/{cluster_id}",
+			expectAllowed: true,
+		},
+		{
+			name:          "admin is not restricted",
+			userInfo:      provider.UserInfo{Group: "unknown-my-project", IsAdmin: true},
+			method:        http.MethodDelete,
+			route:         "/api/v2/projects/{project_id}/clusters/{cluster_id}",
+			expectAllowed: true,
+		},
+		{
+			name:          "custom role can scale machine deployments",
+			userInfo:      provider.UserInfo{Group: "operators-my-project"},
+			method:        http.MethodPatch,
+			route:         "/api/v2/projects/{project_id}/clusters/{cluster_id}/machinedeployments/{machinedeployment_id}",
+			expectAllowed: true,
+		},
+		{
+			name:     "custom role cannot delete clusters",
+			userInfo: provider.UserInfo{Group: "operators-my-project"},
+			method:   http.MethodDelete,
+			route:    "/api/v2/projects/{project_id}/clusters/{cluster_id}",
+		},
+		{
+			name:          "custom role can get the project",
+			userInfo:      provider.UserInfo{Group: "operators-my-project"},
+			method:        http.MethodGet,
+			route:         "/api/v1/projects/{project_id}",
+			expectAllowed: true,
+		},
+		{
+			name:     "custom role cannot add members",
+			userInfo: provider.UserInfo{Group: "operators-my-project"},
+			method:   http.MethodPost,
+			route:    "/api/v1/projects/{project_id}/users",
+		},
+		{
+			name:     "group of a deleted custom role is denied",
+			userInfo: provider.UserInfo{Group: "deleted-my-project"},
+			method:   http.MethodGet,
+			route:    "/api/v1/projects/{project_id}",
+		},
+		{
+			name:     "group of a deleted custom role cannot delete clusters",
+			userInfo: provider.UserInfo{Group: "deleted-my-project"},
+			method:   http.MethodDelete,
+			route:    "/api/v2/projects/{project_id}/clusters/{cluster_id}",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			userInfoGetter := func(_ context.Context, _ string) (*provider.UserInfo, error) {
+				return &tc.userInfo, nil
+			}
+			called := false
+			next := func(_ context.Context, _ interface{}) (interface{}, error) {
+				called = true
+				return nil, nil
+			}
+
+			ctx := context.WithValue(context.Background(), auditRequestContextKey, auditRequest{method: tc.method, endpoint: tc.route})
+			_, err := ProjectRoleAuthorizer(roleProvider, userInfoGetter)(next)(ctx, common.ProjectReq{ProjectID: "my-project"})
+
+			if tc.expectAllowed && (err != nil || !called) {
+				t.Fatalf("expected the request to be allowed, got error %v", err)
+			}
+			if !tc.expectAllowed && (err == nil || called) {
+				t.Fatal("expected the request to be denied")
+			}
+		})
+	}
+}
@@ -149,6 +149,7 @@ func (r Routing) patchKubermaticSettings() http.Handler {
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers, r.userProvider),
 			middleware.UserSaver(r.userProvider),
+			middleware.Audit(r.auditLogger, r.userInfoGetter),
 		)(admin.UpdateKubermaticSettingsEndpoint(r.userInfoGetter, r.settingsProvider)),
 		admin.DecodePatchKubermaticSettingsReq,
 		EncodeJSON,
@@ -201,6 +202,7 @@ func (r Routing) setAdmin() http.Handler {
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers, r.userProvider),
 			middleware.UserSaver(r.userProvider),
+			middleware.Audit(r.auditLogger, r.userInfoGetter),
 		)(admin.SetAdminEndpoint(r.userInfoGetter, r.adminProvider)),
 		admin.DecodeSetAdminReq,
 		EncodeJSON,
@@ -273,6 +275,7 @@ func (r Routing) deleteAdmissionPlugin() http.Handler {
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers, r.userProvider),
 			middleware.UserSaver(r.userProvider),
+			middleware.Audit(r.auditLogger, r.userInfoGetter),
 		)(admin.DeleteAdmissionPluginEndpoint(r.userInfoGetter, r.admissionPluginProvider)),
 		admin.DecodeAdmissionPluginReq,
 		EncodeJSON,
@@ -300,6 +303,7 @@ func (r Routing) updateAdmissionPlugin() http.Handler {
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers, r.userProvider),
 			middleware.UserSaver(r.userProvider),
+			middleware.Audit(r.auditLogger, r.userInfoGetter),
 		)(admin.UpdateAdmissionPluginEndpoint(r.userInfoGetter, r.admissionPluginProvider)),
 		admin.DecodeUpdateAdmissionPluginReq,
 		EncodeJSON,
@@ -375,6 +379,7 @@ func (r Routing) updateSeed() http.Handler {
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers, r.userProvider),
 			middleware.UserSaver(r.userProvider),
+			middleware.Audit(r.auditLogger, r.userInfoGetter),
 		)(admin.UpdateSeedEndpoint(r.userInfoGetter, r.seedsGetter, r.seedsClientGetter)),
 		admin.DecodeUpdateSeedReq,
 		EncodeJSON,
@@ -399,6 +404,7 @@ func (r Routing) deleteSeed() http.Handler {
 		endpoint.Chain(
 			middleware.TokenVerifier(r.tokenVerifiers, r.userProvider),
 			middleware.UserSaver(r.userProvider),
+			middleware.Audit(r.auditLogger, r.userInfoGetter),
 		)(admin.DeleteSeedEndpoint(r.userInfoGetter, r.seedsGetter, r.seedsClientGetter)),
 		admin.DecodeSeedReq,
 		EncodeJSON,
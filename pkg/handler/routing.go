@@ -18,6 +18,7 @@ package handler
 
 import (
 	"crypto/x509"
+	"net/http"
 	"os"
 
 	"github.com/go-kit/kit/log"
@@ -25,6 +26,8 @@ import (
 	prometheusapi "github.com/prometheus/client_golang/api"
 	"go.uber.org/zap"
 
+	addonutils "k8c.io/kubermatic/v2/pkg/addon"
+	"k8c.io/kubermatic/v2/pkg/audit"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/handler/auth"
 	"k8c.io/kubermatic/v2/pkg/handler/middleware"
@@ -70,6 +73,7 @@ type Routing struct {
 	eventRecorderProvider                 provider.EventRecorderProvider
 	exposeStrategy                        kubermaticv1.ExposeStrategy
 	accessibleAddons                      sets.String
+	addonCatalog                          addonutils.Catalog
 	userInfoGetter                        provider.UserInfoGetter
 	settingsProvider                      provider.SettingsProvider
 	adminProvider                         provider.AdminProvider
@@ -77,6 +81,9 @@ type Routing struct {
 	settingsWatcher                       watcher.SettingsWatcher
 	userWatcher                           watcher.UserWatcher
 	caBundle                              *x509.CertPool
+	auditLogger                           *audit.Logger
+	auditHandler                          http.Handler
+	projectRoleProvider                   provider.ProjectRoleProvider
 }
 
 // NewRouting creates a new Routing.
@@ -112,6 +119,7 @@ func NewRouting(routingParams RoutingParams) Routing {
 		eventRecorderProvider:                 routingParams.EventRecorderProvider,
 		exposeStrategy:                        routingParams.ExposeStrategy,
 		accessibleAddons:                      routingParams.AccessibleAddons,
+		addonCatalog:                          routingParams.AddonCatalog,
 		userInfoGetter:                        routingParams.UserInfoGetter,
 		settingsProvider:                      routingParams.SettingsProvider,
 		adminProvider:                         routingParams.AdminProvider,
@@ -120,6 +128,9 @@ func NewRouting(routingParams RoutingParams) Routing {
 		userWatcher:                           routingParams.UserWatcher,
 		versions:                              routingParams.Versions,
 		caBundle:                              routingParams.CABundle,
+		auditLogger:                           routingParams.AuditLogger,
+		auditHandler:                          routingParams.AuditHandler,
+		projectRoleProvider:                   routingParams.ProjectRoleProvider,
 	}
 }
 
@@ -128,6 +139,7 @@ func (r Routing) defaultServerOptions() []httptransport.ServerOption {
 		httptransport.ServerErrorLogger(r.logger),
 		httptransport.ServerErrorEncoder(ErrorEncoder),
 		httptransport.ServerBefore(middleware.TokenExtractor(r.tokenExtractors)),
+		httptransport.ServerBefore(middleware.AuditRequestExtractor(r.auditHandler)),
 	}
 }
 
@@ -161,6 +173,7 @@ type RoutingParams struct {
 	EventRecorderProvider                 provider.EventRecorderProvider
 	ExposeStrategy                        kubermaticv1.ExposeStrategy
 	AccessibleAddons                      sets.String
+	AddonCatalog                          addonutils.Catalog
 	UserInfoGetter                        provider.UserInfoGetter
 	SettingsProvider                      provider.SettingsProvider
 	AdminProvider                         provider.AdminProvider
@@ -174,4 +187,11 @@ type RoutingParams struct {
 	AlertmanagerProviderGetter            provider.AlertmanagerProviderGetter
 	Versions                              kubermatic.Versions
 	CABundle                              *x509.CertPool
+	AuditLogger                           *audit.Logger
+	// AuditHandler serves the GET requests which read the audited resources before they are changed
+	AuditHandler                          http.Handler
+	ProjectRoleProvider                   provider.ProjectRoleProvider
+	GroupProjectBindingProvider           provider.GroupProjectBindingProvider
+	PrivilegedGroupProjectBindingProvider provider.PrivilegedGroupProjectBindingProvider
+	ClusterTemplateProvider               provider.ClusterTemplateProvider
 }
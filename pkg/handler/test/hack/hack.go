@@ -23,6 +23,8 @@ import (
 	prometheusapi "github.com/prometheus/client_golang/api"
 	"github.com/prometheus/client_golang/prometheus"
 
+	addonutils "k8c.io/kubermatic/v2/pkg/addon"
+	"k8c.io/kubermatic/v2/pkg/audit"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/handler"
 	"k8c.io/kubermatic/v2/pkg/handler/auth"
@@ -81,10 +83,21 @@ func NewTestRouting(
 	constraintTemplateProvider provider.ConstraintTemplateProvider,
 	constraintProviderGetter provider.ConstraintProviderGetter,
 	alertmanagerProviderGetter provider.AlertmanagerProviderGetter,
-	kubermaticVersions kubermatic.Versions) http.Handler {
+	projectRoleProvider provider.ProjectRoleProvider,
+	groupProjectBindingProvider provider.GroupProjectBindingProvider,
+	privilegedGroupProjectBindingProvider provider.PrivilegedGroupProjectBindingProvider,
+	clusterTemplateProvider provider.ClusterTemplateProvider,
+	kubermaticVersions kubermatic.Versions,
+	auditLogger *audit.Logger) http.Handler {
 
 	updateManager := version.New(versions, updates)
 
+	addonCatalog := addonutils.Catalog{
+		"addon1": {Name: "addon1", Version: "1.0.0"},
+		"addon2": {Name: "addon2", Version: "2.1.0", Dependencies: []string{"addon1"}, KubernetesVersions: ">= 1.18"},
+	}
+
+	mainRouter := mux.NewRouter()
 	routingParams := handler.RoutingParams{
 		Log:                                   kubermaticlog.Logger,
 		PresetsProvider:                       presetsProvider,
@@ -114,7 +127,8 @@ func NewTestRouting(
 		SATokenGenerator:                      saTokenGenerator,
 		EventRecorderProvider:                 eventRecorderProvider,
 		ExposeStrategy:                        kubermaticv1.ExposeStrategyNodePort,
-		AccessibleAddons:                      sets.String{},
+		AccessibleAddons:                      sets.NewString("addon1", "addon2"),
+		AddonCatalog:                          addonCatalog,
 		UserInfoGetter:                        userInfoGetter,
 		SettingsProvider:                      settingsProvider,
 		AdminProvider:                         adminProvider,
@@ -126,14 +140,19 @@ func NewTestRouting(
 		ConstraintTemplateProvider:            constraintTemplateProvider,
 		ConstraintProviderGetter:              constraintProviderGetter,
 		AlertmanagerProviderGetter:            alertmanagerProviderGetter,
+		ProjectRoleProvider:                   projectRoleProvider,
+		GroupProjectBindingProvider:           groupProjectBindingProvider,
+		PrivilegedGroupProjectBindingProvider: privilegedGroupProjectBindingProvider,
+		ClusterTemplateProvider:               clusterTemplateProvider,
 		Versions:                              kubermaticVersions,
 		CABundle:                              certificates.NewFakeCABundle().CertPool(),
+		AuditLogger:                           auditLogger,
+		AuditHandler:                          mainRouter,
 	}
 
 	r := handler.NewRouting(routingParams)
 	rv2 := v2.NewV2Routing(routingParams)
 
-	mainRouter := mux.NewRouter()
 	v1Router := mainRouter.PathPrefix("/api/v1").Subrouter()
 	v2Router := mainRouter.PathPrefix("/api/v2").Subrouter()
 	r.RegisterV1(v1Router, generateDefaultMetrics())
@@ -38,8 +38,8 @@ import (
 	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
 	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
 	apiv2 "k8c.io/kubermatic/v2/pkg/api/v2"
+	"k8c.io/kubermatic/v2/pkg/audit"
 	k8cuserclusterclient "k8c.io/kubermatic/v2/pkg/cluster/client"
-	"k8c.io/kubermatic/v2/pkg/controller/master-controller-manager/rbac"
 	kubermaticfakeclientset "k8c.io/kubermatic/v2/pkg/crd/client/clientset/versioned/fake"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/handler/auth"
@@ -186,7 +186,12 @@ type newRoutingFunc func(
 	constraintTemplateProvider provider.ConstraintTemplateProvider,
 	constraintProviderGetter provider.ConstraintProviderGetter,
 	alertmanagerProviderGetter provider.AlertmanagerProviderGetter,
+	projectRoleProvider provider.ProjectRoleProvider,
+	groupProjectBindingProvider provider.GroupProjectBindingProvider,
+	privilegedGroupProjectBindingProvider provider.PrivilegedGroupProjectBindingProvider,
+	clusterTemplateProvider provider.ClusterTemplateProvider,
 	kubermaticVersions kubermatic.Versions,
+	auditLogger *audit.Logger,
 ) http.Handler
 
 func getRuntimeObjects(objs ...ctrlruntimeclient.Object) []runtime.Object {
@@ -228,13 +233,18 @@ func initTestEndpoint(user apiv1.User, seedsGetter provider.SeedsGetter, kubeObj
 	if err != nil {
 		return nil, nil, err
 	}
-	tokenAuth := serviceaccount.JWTTokenAuthenticator([]byte(TestServiceAccountHashKey))
+	tokenAuth, err := serviceaccount.JWTTokenAuthenticator([]byte(TestServiceAccountHashKey))
+	if err != nil {
+		return nil, nil, err
+	}
 	serviceAccountTokenProvider, err := kubernetes.NewServiceAccountTokenProvider(fakeImpersonationClient, fakeClient)
 	if err != nil {
 		return nil, nil, err
 	}
 	serviceAccountProvider := kubernetes.NewServiceAccountProvider(fakeImpersonationClient, fakeClient, "localhost")
 	projectMemberProvider := kubernetes.NewProjectMemberProvider(fakeImpersonationClient, fakeClient, kubernetes.IsProjectServiceAccount)
+	groupProjectBindingProvider := kubernetes.NewGroupProjectBindingProvider(fakeImpersonationClient, fakeClient)
+	clusterTemplateProvider := kubernetes.NewClusterTemplateProvider(fakeClient)
 	userInfoGetter, err := provider.UserInfoGetterFactory(projectMemberProvider)
 	if err != nil {
 		return nil, nil, err
@@ -247,7 +257,7 @@ func initTestEndpoint(user apiv1.User, seedsGetter provider.SeedsGetter, kubeObj
 		if strings.HasPrefix(user.Email, "serviceaccount-") {
 			saExtractorVerifier := auth.NewServiceAccountAuthClient(
 				auth.NewHeaderBearerTokenExtractor("Authorization"),
-				serviceaccount.JWTTokenAuthenticator([]byte(TestServiceAccountHashKey)),
+				tokenAuth,
 				serviceAccountTokenProvider,
 			)
 			verifiers = append(verifiers, saExtractorVerifier)
@@ -275,6 +285,8 @@ func initTestEndpoint(user apiv1.User, seedsGetter provider.SeedsGetter, kubeObj
 		return nil, nil, err
 	}
 
+	projectRoleProvider := kubernetes.NewProjectRoleProvider(fakeClient)
+
 	kubermaticVersions := kubermatic.NewFakeVersions()
 	fUserClusterConnection := &fakeUserClusterConnection{fakeClient}
 	clusterProvider := kubernetes.NewClusterProvider(
@@ -282,7 +294,7 @@ func initTestEndpoint(user apiv1.User, seedsGetter provider.SeedsGetter, kubeObj
 		fakeImpersonationClient,
 		fUserClusterConnection,
 		"",
-		rbac.ExtractGroupPrefix,
+		projectRoleProvider.UserClusterGroupPrefix,
 		fakeClient,
 		kubernetesClient,
 		false,
@@ -377,6 +389,8 @@ func initTestEndpoint(user apiv1.User, seedsGetter provider.SeedsGetter, kubeObj
 	// Disable the metrics endpoint in tests
 	var prometheusClient prometheusapi.Client
 
+	auditLogger := audit.NewSynchronous(kubermaticlog.Logger, audit.NewEventSink(fakeClient, "kubermatic"))
+
 	mainRouter := routingFunc(
 		adminProvider,
 		settingsProvider,
@@ -416,7 +430,12 @@ func initTestEndpoint(user apiv1.User, seedsGetter provider.SeedsGetter, kubeObj
 		fakeConstraintTemplateProvider,
 		constraintProviderGetter,
 		alertmanagerProviderGetter,
+		projectRoleProvider,
+		groupProjectBindingProvider,
+		groupProjectBindingProvider,
+		clusterTemplateProvider,
 		kubermaticVersions,
+		auditLogger,
 	)
 
 	return mainRouter, &ClientsSets{kubermaticClient, fakeClient, kubernetesClient, tokenAuth, tokenGenerator}, nil
@@ -976,12 +995,11 @@ func GenDefaultSaToken(projectID, saID, name, id string) *corev1.Secret {
 }
 
 func GenDefaultExpiry() (apiv1.Time, error) {
-	authenticator := serviceaccount.JWTTokenAuthenticator([]byte(TestServiceAccountHashKey))
-	claim, _, err := authenticator.Authenticate(TestFakeToken)
+	expiry, err := serviceaccount.Expiry(TestFakeToken)
 	if err != nil {
 		return apiv1.Time{}, err
 	}
-	return apiv1.NewTime(claim.Expiry.Time()), nil
+	return apiv1.NewTime(expiry), nil
 }
 
 func GenTestEvent(eventName, eventType, eventReason, eventMessage, kind, uid string) *corev1.Event {
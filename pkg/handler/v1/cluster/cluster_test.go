@@ -673,6 +673,38 @@ func TestAssignSSHKeyToClusterEndpoint(t *testing.T) {
 	}
 }
 
+func TestCreateClusterEndpointProjectClusterQuota(t *testing.T) {
+	t.Parallel()
+
+	// the usage in the status of the project is only aggregated asynchronously, so it is still empty while the
+	// clusters are created in a row
+	clusters := int64(2)
+	project := test.GenDefaultProject()
+	project.Spec.ResourceQuota = &kubermaticv1.ProjectResourceQuota{Clusters: &clusters}
+	kubermaticObj := []ctrlruntimeclient.Object{project}
+	for _, obj := range test.GenDefaultKubermaticObjects(test.GenTestSeed()) {
+		if _, ok := obj.(*kubermaticv1.Project); !ok {
+			kubermaticObj = append(kubermaticObj, obj)
+		}
+	}
+
+	ep, err := test.CreateTestEndpoint(*test.GenDefaultAPIUser(), []ctrlruntimeclient.Object{}, kubermaticObj, test.GenDefaultVersions(), nil, hack.NewTestRouting)
+	if err != nil {
+		t.Fatalf("failed to create test endpoint due to %v", err)
+	}
+
+	for i, expectedStatus := range []int{http.StatusCreated, http.StatusCreated, http.StatusForbidden} {
+		body := fmt.Sprintf(`{"cluster":{"name":"keen-snyder-%d","spec":{"version":"1.15.0","cloud":{"fake":{"token":"dummy_token"},"dc":"fake-dc"}}}}`, i)
+		req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/projects/%s/dc/us-central1/clusters", project.Name), strings.NewReader(body))
+		res := httptest.NewRecorder()
+		ep.ServeHTTP(res, req)
+
+		if res.Code != expectedStatus {
+			t.Fatalf("Expected HTTP status code %d for cluster %d, got %d: %s", expectedStatus, i+1, res.Code, res.Body.String())
+		}
+	}
+}
+
 func TestCreateClusterEndpoint(t *testing.T) {
 	t.Parallel()
 	testcases := []struct {
@@ -696,11 +728,32 @@ func TestCreateClusterEndpoint(t *testing.T) {
 			ProjectToSync:          test.GenDefaultProject().Name,
 			ExistingAPIUser:        test.GenDefaultAPIUser(),
 		},
+		// scenario 1a
+		{
+			Name:                   "scenario 1a: a cluster with an unsupported CNI plugin version is rejected",
+			Body:                   `{"cluster":{"name":"keen-snyder","spec":{"cloud":{"fake":{"token":"dummy_token"},"dc":"fake-dc"}, "version":"1.15.0", "cniPlugin":{"type":"cilium","version":"v1.2"}}}}`,
+			ExpectedResponse:       `{"error":{"code":400,"message":"invalid cluster: invalid CNI plugin: version \"v1.2\" of CNI plugin type \"cilium\" is not supported, supported versions are [\"v1.9\" \"v1.10\"]"}}`,
+			HTTPStatus:             http.StatusBadRequest,
+			ExistingKubermaticObjs: test.GenDefaultKubermaticObjects(test.GenTestSeed()),
+			ProjectToSync:          test.GenDefaultProject().Name,
+			ExistingAPIUser:        test.GenDefaultAPIUser(),
+		},
+		// scenario 1b
+		{
+			Name:                   "scenario 1b: a cluster with the default version of the selected CNI plugin is created",
+			Body:                   `{"cluster":{"name":"keen-snyder","spec":{"cloud":{"fake":{"token":"dummy_token"},"dc":"fake-dc"}, "version":"1.15.0", "cniPlugin":{"type":"cilium"}}}}`,
+			ExpectedResponse:       `{"id":"%s","name":"keen-snyder","creationTimestamp":"0001-01-01T00:00:00Z","type":"kubernetes","spec":{"cloud":{"dc":"fake-dc","fake":{}},"cniPlugin":{"type":"cilium","version":"v1.10"},"version":"1.15.0","oidc":{},"enableUserSSHKeyAgent":true},"status":{"version":"1.15.0","url":""}}`,
+			RewriteClusterID:       true,
+			HTTPStatus:             http.StatusCreated,
+			ExistingKubermaticObjs: test.GenDefaultKubermaticObjects(test.GenTestSeed()),
+			ProjectToSync:          test.GenDefaultProject().Name,
+			ExistingAPIUser:        test.GenDefaultAPIUser(),
+		},
 		// scenario 2
 		{
 			Name:             "scenario 2: cluster is created when valid spec and ssh key are passed",
 			Body:             `{"cluster":{"name":"keen-snyder","spec":{"version":"1.15.0","cloud":{"fake":{"token":"dummy_token"},"dc":"fake-dc"}}}}`,
-			ExpectedResponse: `{"id":"%s","name":"keen-snyder","creationTimestamp":"0001-01-01T00:00:00Z","type":"kubernetes","spec":{"cloud":{"dc":"fake-dc","fake":{}},"version":"1.15.0","oidc":{},"enableUserSSHKeyAgent":true},"status":{"version":"1.15.0","url":""}}`,
+			ExpectedResponse: `{"id":"%s","name":"keen-snyder","creationTimestamp":"0001-01-01T00:00:00Z","type":"kubernetes","spec":{"cloud":{"dc":"fake-dc","fake":{}},"cniPlugin":{"type":"canal","version":"v3.8"},"version":"1.15.0","oidc":{},"enableUserSSHKeyAgent":true},"status":{"version":"1.15.0","url":""}}`,
 			RewriteClusterID: true,
 			HTTPStatus:       http.StatusCreated,
 			ProjectToSync:    test.GenDefaultProject().Name,
@@ -786,7 +839,7 @@ func TestCreateClusterEndpoint(t *testing.T) {
 		{
 			Name:             "scenario 10a: create a cluster in email-restricted datacenter, to which the user does have access - legacy single domain restriction with requiredEmailDomains",
 			Body:             `{"cluster":{"name":"keen-snyder","spec":{"version":"1.15.0","cloud":{"fake":{"token":"dummy_token"},"dc":"restricted-fake-dc"}}}}`,
-			ExpectedResponse: `{"id":"%s","name":"keen-snyder","creationTimestamp":"0001-01-01T00:00:00Z","type":"kubernetes","spec":{"cloud":{"dc":"restricted-fake-dc","fake":{}},"version":"1.15.0","oidc":{},"enableUserSSHKeyAgent":true},"status":{"version":"1.15.0","url":""}}`,
+			ExpectedResponse: `{"id":"%s","name":"keen-snyder","creationTimestamp":"0001-01-01T00:00:00Z","type":"kubernetes","spec":{"cloud":{"dc":"restricted-fake-dc","fake":{}},"cniPlugin":{"type":"canal","version":"v3.8"},"version":"1.15.0","oidc":{},"enableUserSSHKeyAgent":true},"status":{"version":"1.15.0","url":""}}`,
 			RewriteClusterID: true,
 			HTTPStatus:       http.StatusCreated,
 			ProjectToSync:    test.GenDefaultProject().Name,
@@ -800,7 +853,7 @@ func TestCreateClusterEndpoint(t *testing.T) {
 		{
 			Name:             "scenario 10b: create a cluster in email-restricted datacenter, to which the user does have access - domain array restriction with `requiredEmailDomains`",
 			Body:             `{"cluster":{"name":"keen-snyder","spec":{"version":"1.15.0","cloud":{"fake":{"token":"dummy_token"},"dc":"restricted-fake-dc2"}}}}`,
-			ExpectedResponse: `{"id":"%s","name":"keen-snyder","creationTimestamp":"0001-01-01T00:00:00Z","type":"kubernetes","spec":{"cloud":{"dc":"restricted-fake-dc2","fake":{}},"version":"1.15.0","oidc":{},"enableUserSSHKeyAgent":true},"status":{"version":"1.15.0","url":""}}`,
+			ExpectedResponse: `{"id":"%s","name":"keen-snyder","creationTimestamp":"0001-01-01T00:00:00Z","type":"kubernetes","spec":{"cloud":{"dc":"restricted-fake-dc2","fake":{}},"cniPlugin":{"type":"canal","version":"v3.8"},"version":"1.15.0","oidc":{},"enableUserSSHKeyAgent":true},"status":{"version":"1.15.0","url":""}}`,
 			RewriteClusterID: true,
 			HTTPStatus:       http.StatusCreated,
 			ProjectToSync:    test.GenDefaultProject().Name,
@@ -814,7 +867,7 @@ func TestCreateClusterEndpoint(t *testing.T) {
 		{
 			Name:             "scenario 11: create a cluster in audit-logging-enforced datacenter, without explicitly enabling audit logging",
 			Body:             `{"cluster":{"name":"keen-snyder","spec":{"version":"1.15.0","cloud":{"fake":{"token":"dummy_token"},"dc":"audited-dc"}}}}`,
-			ExpectedResponse: `{"id":"%s","name":"keen-snyder","creationTimestamp":"0001-01-01T00:00:00Z","type":"kubernetes","spec":{"cloud":{"dc":"audited-dc","fake":{}},"version":"1.15.0","oidc":{},"enableUserSSHKeyAgent":true,"auditLogging":{"enabled":true}},"status":{"version":"1.15.0","url":""}}`,
+			ExpectedResponse: `{"id":"%s","name":"keen-snyder","creationTimestamp":"0001-01-01T00:00:00Z","type":"kubernetes","spec":{"cloud":{"dc":"audited-dc","fake":{}},"cniPlugin":{"type":"canal","version":"v3.8"},"version":"1.15.0","oidc":{},"enableUserSSHKeyAgent":true,"auditLogging":{"enabled":true}},"status":{"version":"1.15.0","url":""}}`,
 			RewriteClusterID: true,
 			HTTPStatus:       http.StatusCreated,
 			ProjectToSync:    test.GenDefaultProject().Name,
@@ -828,7 +881,7 @@ func TestCreateClusterEndpoint(t *testing.T) {
 		{
 			Name:             "scenario 12: the admin user can create cluster for any project",
 			Body:             `{"cluster":{"name":"keen-snyder","spec":{"version":"1.15.0","cloud":{"fake":{"token":"dummy_token"},"dc":"fake-dc"}}}}`,
-			ExpectedResponse: `{"id":"%s","name":"keen-snyder","creationTimestamp":"0001-01-01T00:00:00Z","type":"kubernetes","spec":{"cloud":{"dc":"fake-dc","fake":{}},"version":"1.15.0","oidc":{},"enableUserSSHKeyAgent":true},"status":{"version":"1.15.0","url":""}}`,
+			ExpectedResponse: `{"id":"%s","name":"keen-snyder","creationTimestamp":"0001-01-01T00:00:00Z","type":"kubernetes","spec":{"cloud":{"dc":"fake-dc","fake":{}},"cniPlugin":{"type":"canal","version":"v3.8"},"version":"1.15.0","oidc":{},"enableUserSSHKeyAgent":true},"status":{"version":"1.15.0","url":""}}`,
 			RewriteClusterID: true,
 			HTTPStatus:       http.StatusCreated,
 			ProjectToSync:    test.GenDefaultProject().Name,
@@ -1113,6 +1166,42 @@ func TestPatchCluster(t *testing.T) {
 					return cluster
 				}()),
 		},
+		// scenario 1a
+		{
+			Name:             "scenario 1a: upgrade the CNI plugin version",
+			Body:             `{"spec":{"cniPlugin":{"version":"v1.10"}}}`,
+			ExpectedResponse: `{"id":"keen-snyder","name":"clusterAbc","creationTimestamp":"2013-02-03T19:54:00Z","type":"kubernetes","spec":{"cloud":{"dc":"fake-dc","fake":{}},"cniPlugin":{"type":"cilium","version":"v1.10"},"version":"9.9.9","oidc":{},"enableUserSSHKeyAgent":false},"status":{"version":"9.9.9","url":"https://w225mx4z66.asia-east1-a-1.cloud.kubermatic.io:31885"}}`,
+			cluster:          "keen-snyder",
+			HTTPStatus:       http.StatusOK,
+			project:          test.GenDefaultProject().Name,
+			ExistingAPIUser:  test.GenDefaultAPIUser(),
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				test.GenTestSeed(),
+				func() *kubermaticv1.Cluster {
+					cluster := test.GenCluster("keen-snyder", "clusterAbc", test.GenDefaultProject().Name, time.Date(2013, 02, 03, 19, 54, 0, 0, time.UTC))
+					cluster.Spec.Cloud.DatacenterName = fakeDC
+					cluster.Spec.CNIPlugin = &kubermaticv1.CNIPluginSettings{Type: kubermaticv1.CNIPluginTypeCilium, Version: "v1.9"}
+					return cluster
+				}()),
+		},
+		// scenario 1b
+		{
+			Name:             "scenario 1b: fail to change the CNI plugin type",
+			Body:             `{"spec":{"cniPlugin":{"type":"canal","version":"v3.8"}}}`,
+			ExpectedResponse: `{"error":{"code":400,"message":"invalid cluster: invalid CNI plugin: changing the CNI plugin type from \"cilium\" to \"canal\" is not supported"}}`,
+			cluster:          "keen-snyder",
+			HTTPStatus:       http.StatusBadRequest,
+			project:          test.GenDefaultProject().Name,
+			ExistingAPIUser:  test.GenDefaultAPIUser(),
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				test.GenTestSeed(),
+				func() *kubermaticv1.Cluster {
+					cluster := test.GenCluster("keen-snyder", "clusterAbc", test.GenDefaultProject().Name, time.Date(2013, 02, 03, 19, 54, 0, 0, time.UTC))
+					cluster.Spec.Cloud.DatacenterName = fakeDC
+					cluster.Spec.CNIPlugin = &kubermaticv1.CNIPluginSettings{Type: kubermaticv1.CNIPluginTypeCilium, Version: "v1.9"}
+					return cluster
+				}()),
+		},
 		// scenario 2
 		{
 			Name:             "scenario 2: fail on invalid patch json",
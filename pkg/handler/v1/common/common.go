@@ -77,6 +77,7 @@ type UpdateManager interface {
 	GetVersions(string) ([]*version.Version, error)
 	GetDefault() (*version.Version, error)
 	GetPossibleUpdates(from, clusterType string) ([]*version.Version, error)
+	AutomaticControlplaneUpdate(from, clusterType string) (*version.Version, error)
 }
 
 // ServerMetrics defines metrics used by the API.
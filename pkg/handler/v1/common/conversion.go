@@ -36,8 +36,13 @@ func ConvertInternalSSHKeysToExternal(internalKeys []*kubermaticapiv1.UserSSHKey
 			Spec: apiv1.SSHKeySpec{
 				Fingerprint: key.Spec.Fingerprint,
 				PublicKey:   key.Spec.PublicKey,
+				User:        key.Spec.User,
 			},
 		}
+		if key.Spec.ExpiresAt != nil {
+			expiresAt := apiv1.NewTime(key.Spec.ExpiresAt.Time)
+			apiKey.Spec.ExpiresAt = &expiresAt
+		}
 		apiKeys[index] = apiKey
 	}
 	return apiKeys
@@ -86,9 +91,11 @@ func ConvertInternalProjectToExternal(kubermaticProject *kubermaticapiv1.Project
 				return nil
 			}(),
 		},
-		Labels:         label.FilterLabels(label.ProjectResourceType, kubermaticProject.Labels),
-		Status:         kubermaticProject.Status.Phase,
-		Owners:         projectOwners,
-		ClustersNumber: clustersNumber,
+		Labels:                  label.FilterLabels(label.ProjectResourceType, kubermaticProject.Labels),
+		Status:                  kubermaticProject.Status.Phase,
+		Owners:                  projectOwners,
+		ClustersNumber:          clustersNumber,
+		UpdateWindow:            kubermaticProject.Spec.UpdateWindow,
+		SSHCertificateAuthority: kubermaticProject.Spec.SSHCertificateAuthority,
 	}
 }
@@ -33,6 +33,7 @@ import (
 	"k8c.io/kubermatic/v2/pkg/provider"
 	"k8c.io/kubermatic/v2/pkg/provider/kubernetes"
 	"k8c.io/kubermatic/v2/pkg/util/errors"
+	"k8c.io/kubermatic/v2/pkg/validation"
 
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 )
@@ -103,7 +104,7 @@ func validateUserProjectsLimit(user *kubermaticapiv1.User, settings *kubermatica
 		return nil
 	}
 
-	userMappings, err := memberMapper.MappingsFor(user.Spec.Email)
+	userMappings, err := memberMapper.MappingsFor(user)
 	if err != nil {
 		return common.KubernetesErrorToHTTPError(err)
 	}
@@ -163,7 +164,8 @@ func ListEndpoint(userInfoGetter provider.UserInfoGetter, projectProvider provid
 			return getAllProjectsForAdmin(userInfo, projectProvider, memberProvider, userProvider, clusterProviderGetter, seedsGetter)
 		}
 		projects := []*apiv1.Project{}
-		userMappings, err := memberMapper.MappingsFor(userInfo.Email)
+		user := ctx.Value(middleware.UserCRContextKey).(*kubermaticapiv1.User)
+		userMappings, err := memberMapper.MappingsFor(user)
 		if err != nil {
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
@@ -295,6 +297,8 @@ func UpdateEndpoint(projectProvider provider.ProjectProvider, privilegedProjectP
 
 		kubermaticProject.Spec.Name = req.Body.Name
 		kubermaticProject.Labels = req.Body.Labels
+		kubermaticProject.Spec.UpdateWindow = req.Body.UpdateWindow
+		kubermaticProject.Spec.SSHCertificateAuthority = req.Body.SSHCertificateAuthority
 
 		project, err := updateProject(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, kubermaticProject)
 		if err != nil {
@@ -383,7 +387,10 @@ func (r updateRq) validate() error {
 	if len(r.Body.Name) == 0 {
 		return fmt.Errorf("the name of the project cannot be empty")
 	}
-	return nil
+	if err := validation.ValidateUpdateWindow(r.Body.UpdateWindow); err != nil {
+		return err
+	}
+	return validation.ValidateSSHCertificateAuthority(r.Body.SSHCertificateAuthority)
 }
 
 // DecodeUpdateRq decodes an HTTP request into updateRq
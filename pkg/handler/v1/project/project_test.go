@@ -957,7 +957,7 @@ func genToken(sa *kubermaticv1.User, projectID, tokenName string) (*v1.Secret, e
 	if err != nil {
 		return nil, fmt.Errorf("can init token generator %v", err)
 	}
-	token, err := tokenGenerator.Generate(serviceaccount.Claims(sa.Spec.Email, projectID, tokenName))
+	token, err := tokenGenerator.Generate(serviceaccount.Claims(sa.Spec.Email, projectID, tokenName, 0))
 	if err != nil {
 		return nil, fmt.Errorf("can not generate token data %v", err)
 	}
@@ -143,7 +143,7 @@ func ListEndpoint(projectProvider provider.ProjectProvider, privilegedProjectPro
 				continue
 			}
 
-			group, err := memberMapper.MapUserToGroup(sa.Spec.Email, project.Name)
+			group, err := memberMapper.MapUserToGroup(sa, project.Name)
 			if err != nil {
 				errorList = append(errorList, err.Error())
 			} else {
@@ -196,7 +196,7 @@ func UpdateEndpoint(projectProvider provider.ProjectProvider, privilegedProjectP
 			sa.Spec.Name = saFromRequest.Name
 		}
 
-		currentGroup, err := memberMapper.MapUserToGroup(sa.Spec.Email, project.Name)
+		currentGroup, err := memberMapper.MapUserToGroup(sa, project.Name)
 		if err != nil {
 			return nil, common.KubernetesErrorToHTTPError(err)
 
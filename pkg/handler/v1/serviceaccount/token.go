@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"time"
 	"unicode/utf8"
 
 	"github.com/go-kit/kit/endpoint"
@@ -31,6 +32,7 @@ import (
 	kubermaticapiv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/handler/v1/common"
 	"k8c.io/kubermatic/v2/pkg/provider"
+	kubernetesprovider "k8c.io/kubermatic/v2/pkg/provider/kubernetes"
 	"k8c.io/kubermatic/v2/pkg/serviceaccount"
 	"k8c.io/kubermatic/v2/pkg/util/errors"
 
@@ -41,7 +43,7 @@ import (
 )
 
 // CreateTokenEndpoint creates a token for the given service account
-func CreateTokenEndpoint(projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, serviceAccountProvider provider.ServiceAccountProvider, privilegedServiceAccount provider.PrivilegedServiceAccountProvider, serviceAccountTokenProvider provider.ServiceAccountTokenProvider, privilegedServiceAccountTokenProvider provider.PrivilegedServiceAccountTokenProvider, tokenAuthenticator serviceaccount.TokenAuthenticator, tokenGenerator serviceaccount.TokenGenerator, userInfoGetter provider.UserInfoGetter) endpoint.Endpoint {
+func CreateTokenEndpoint(projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, serviceAccountProvider provider.ServiceAccountProvider, privilegedServiceAccount provider.PrivilegedServiceAccountProvider, serviceAccountTokenProvider provider.ServiceAccountTokenProvider, privilegedServiceAccountTokenProvider provider.PrivilegedServiceAccountTokenProvider, tokenGenerator serviceaccount.TokenGenerator, userInfoGetter provider.UserInfoGetter) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(addTokenReq)
 		err := req.Validate()
@@ -69,7 +71,7 @@ func CreateTokenEndpoint(projectProvider provider.ProjectProvider, privilegedPro
 
 		tokenID := rand.String(10)
 
-		token, err := tokenGenerator.Generate(serviceaccount.Claims(sa.Spec.Email, project.Name, tokenID))
+		token, err := tokenGenerator.Generate(serviceaccount.Claims(sa.Spec.Email, project.Name, tokenID, tokenTTL(req.Body.PublicServiceAccountToken)))
 		if err != nil {
 			return nil, errors.New(http.StatusInternalServerError, "can not generate token data")
 		}
@@ -79,7 +81,7 @@ func CreateTokenEndpoint(projectProvider provider.ProjectProvider, privilegedPro
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
 
-		externalToken, err := convertInternalTokenToPrivateExternal(secret, tokenAuthenticator)
+		externalToken, err := convertInternalTokenToPrivateExternal(secret)
 		if err != nil {
 			return nil, errors.New(http.StatusInternalServerError, err.Error())
 		}
@@ -136,7 +138,7 @@ func createSAToken(ctx context.Context, userInfoGetter provider.UserInfoGetter,
 }
 
 // ListTokenEndpoint gets token for the service account
-func ListTokenEndpoint(projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, serviceAccountProvider provider.ServiceAccountProvider, privilegedServiceAccount provider.PrivilegedServiceAccountProvider, serviceAccountTokenProvider provider.ServiceAccountTokenProvider, privilegedServiceAccountTokenProvider provider.PrivilegedServiceAccountTokenProvider, tokenAuthenticator serviceaccount.TokenAuthenticator, userInfoGetter provider.UserInfoGetter) endpoint.Endpoint {
+func ListTokenEndpoint(projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, serviceAccountProvider provider.ServiceAccountProvider, privilegedServiceAccount provider.PrivilegedServiceAccountProvider, serviceAccountTokenProvider provider.ServiceAccountTokenProvider, privilegedServiceAccountTokenProvider provider.PrivilegedServiceAccountTokenProvider, userInfoGetter provider.UserInfoGetter) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		resultList := make([]*apiv1.PublicServiceAccountToken, 0)
 		req := request.(commonTokenReq)
@@ -163,7 +165,7 @@ func ListTokenEndpoint(projectProvider provider.ProjectProvider, privilegedProje
 		var errorList []string
 		for _, secret := range existingSecretList {
 
-			externalToken, err := convertInternalTokenToPublicExternal(secret, tokenAuthenticator)
+			externalToken, err := convertInternalTokenToPublicExternal(secret)
 			if err != nil {
 				errorList = append(errorList, err.Error())
 				continue
@@ -180,7 +182,7 @@ func ListTokenEndpoint(projectProvider provider.ProjectProvider, privilegedProje
 }
 
 // UpdateTokenEndpoint updates and regenerates the token for the given service account
-func UpdateTokenEndpoint(projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, serviceAccountProvider provider.ServiceAccountProvider, privilegedServiceAccount provider.PrivilegedServiceAccountProvider, serviceAccountTokenProvider provider.ServiceAccountTokenProvider, privilegedServiceAccountTokenProvider provider.PrivilegedServiceAccountTokenProvider, tokenAuthenticator serviceaccount.TokenAuthenticator, tokenGenerator serviceaccount.TokenGenerator, userInfoGetter provider.UserInfoGetter) endpoint.Endpoint {
+func UpdateTokenEndpoint(projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, serviceAccountProvider provider.ServiceAccountProvider, privilegedServiceAccount provider.PrivilegedServiceAccountProvider, serviceAccountTokenProvider provider.ServiceAccountTokenProvider, privilegedServiceAccountTokenProvider provider.PrivilegedServiceAccountTokenProvider, tokenGenerator serviceaccount.TokenGenerator, userInfoGetter provider.UserInfoGetter) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(updateTokenReq)
 		err := req.Validate()
@@ -188,12 +190,12 @@ func UpdateTokenEndpoint(projectProvider provider.ProjectProvider, privilegedPro
 			return nil, errors.NewBadRequest(err.Error())
 		}
 
-		secret, err := updateEndpoint(ctx, projectProvider, privilegedProjectProvider, serviceAccountProvider, privilegedServiceAccount, serviceAccountTokenProvider, privilegedServiceAccountTokenProvider, userInfoGetter, tokenGenerator, req.ProjectID, req.ServiceAccountID, req.TokenID, req.Body.Name, true)
+		secret, err := updateEndpoint(ctx, projectProvider, privilegedProjectProvider, serviceAccountProvider, privilegedServiceAccount, serviceAccountTokenProvider, privilegedServiceAccountTokenProvider, userInfoGetter, tokenGenerator, req.ProjectID, req.ServiceAccountID, req.TokenID, req.Body.Name, tokenTTL(req.Body), true)
 		if err != nil {
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
 
-		externalToken, err := convertInternalTokenToPrivateExternal(secret, tokenAuthenticator)
+		externalToken, err := convertInternalTokenToPrivateExternal(secret)
 		if err != nil {
 			return nil, errors.New(http.StatusInternalServerError, err.Error())
 		}
@@ -203,7 +205,7 @@ func UpdateTokenEndpoint(projectProvider provider.ProjectProvider, privilegedPro
 }
 
 // PatchTokenEndpoint patches the token name
-func PatchTokenEndpoint(projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, serviceAccountProvider provider.ServiceAccountProvider, privilegedServiceAccount provider.PrivilegedServiceAccountProvider, serviceAccountTokenProvider provider.ServiceAccountTokenProvider, privilegedServiceAccountTokenProvider provider.PrivilegedServiceAccountTokenProvider, tokenAuthenticator serviceaccount.TokenAuthenticator, tokenGenerator serviceaccount.TokenGenerator, userInfoGetter provider.UserInfoGetter) endpoint.Endpoint {
+func PatchTokenEndpoint(projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, serviceAccountProvider provider.ServiceAccountProvider, privilegedServiceAccount provider.PrivilegedServiceAccountProvider, serviceAccountTokenProvider provider.ServiceAccountTokenProvider, privilegedServiceAccountTokenProvider provider.PrivilegedServiceAccountTokenProvider, tokenGenerator serviceaccount.TokenGenerator, userInfoGetter provider.UserInfoGetter) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(patchTokenReq)
 		err := req.Validate()
@@ -219,12 +221,12 @@ func PatchTokenEndpoint(projectProvider provider.ProjectProvider, privilegedProj
 			return nil, errors.NewBadRequest("new name can not be empty")
 		}
 
-		secret, err := updateEndpoint(ctx, projectProvider, privilegedProjectProvider, serviceAccountProvider, privilegedServiceAccount, serviceAccountTokenProvider, privilegedServiceAccountTokenProvider, userInfoGetter, tokenGenerator, req.ProjectID, req.ServiceAccountID, req.TokenID, tokenReq.Name, false)
+		secret, err := updateEndpoint(ctx, projectProvider, privilegedProjectProvider, serviceAccountProvider, privilegedServiceAccount, serviceAccountTokenProvider, privilegedServiceAccountTokenProvider, userInfoGetter, tokenGenerator, req.ProjectID, req.ServiceAccountID, req.TokenID, tokenReq.Name, 0, false)
 		if err != nil {
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
 
-		externalToken, err := convertInternalTokenToPublicExternal(secret, tokenAuthenticator)
+		externalToken, err := convertInternalTokenToPublicExternal(secret)
 		if err != nil {
 			return nil, errors.New(http.StatusInternalServerError, err.Error())
 		}
@@ -293,7 +295,7 @@ func getSAToken(ctx context.Context, userInfoGetter provider.UserInfoGetter, ser
 
 func updateEndpoint(ctx context.Context, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, serviceAccountProvider provider.ServiceAccountProvider,
 	privilegedServiceAccount provider.PrivilegedServiceAccountProvider, serviceAccountTokenProvider provider.ServiceAccountTokenProvider, privilegedServiceAccountTokenProvider provider.PrivilegedServiceAccountTokenProvider, userInfoGetter provider.UserInfoGetter, tokenGenerator serviceaccount.TokenGenerator,
-	projectID, saID, tokenID, newName string, regenerateToken bool) (*v1.Secret, error) {
+	projectID, saID, tokenID, newName string, ttl time.Duration, regenerateToken bool) (*v1.Secret, error) {
 
 	project, err := common.GetProject(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, projectID, nil)
 	if err != nil {
@@ -331,7 +333,7 @@ func updateEndpoint(ctx context.Context, projectProvider provider.ProjectProvide
 	}
 
 	if regenerateToken {
-		token, err := tokenGenerator.Generate(serviceaccount.Claims(sa.Spec.Email, project.Name, existingSecret.Name))
+		token, err := tokenGenerator.Generate(serviceaccount.Claims(sa.Spec.Email, project.Name, existingSecret.Name, ttl))
 		if err != nil {
 			return nil, fmt.Errorf("can not generate token data")
 		}
@@ -371,6 +373,11 @@ type addTokenReq struct {
 	Body apiv1.ServiceAccountToken
 }
 
+// tokenTTL returns the requested lifetime of the token
+func tokenTTL(token apiv1.PublicServiceAccountToken) time.Duration {
+	return time.Duration(token.ExpirationSeconds) * time.Second
+}
+
 // commonTokenReq defines HTTP request for listServiceAccountTokens
 // swagger:parameters listServiceAccountTokens
 type commonTokenReq struct {
@@ -418,7 +425,7 @@ func (r addTokenReq) Validate() error {
 		return fmt.Errorf("the name is too long, max 50 chars")
 	}
 
-	return nil
+	return serviceaccount.ValidateTTL(tokenTTL(r.Body.PublicServiceAccountToken))
 }
 
 // Validate validates commonTokenReq request
@@ -445,7 +452,7 @@ func (r updateTokenReq) Validate() error {
 		return fmt.Errorf("token ID mismatch, you requested to update token = %s but body contains token = %s", r.TokenID, r.Body.ID)
 	}
 
-	return nil
+	return serviceaccount.ValidateTTL(tokenTTL(r.Body))
 }
 
 // Validate validates updateTokenReq request
@@ -601,9 +608,9 @@ func decodeTokenIDReq(c context.Context, r *http.Request) (tokenIDReq, error) {
 	return req, nil
 }
 
-func convertInternalTokenToPrivateExternal(internal *v1.Secret, authenticator serviceaccount.TokenAuthenticator) (*apiv1.ServiceAccountToken, error) {
+func convertInternalTokenToPrivateExternal(internal *v1.Secret) (*apiv1.ServiceAccountToken, error) {
 	externalToken := &apiv1.ServiceAccountToken{}
-	public, err := convertInternalTokenToPublicExternal(internal, authenticator)
+	public, err := convertInternalTokenToPublicExternal(internal)
 	if err != nil {
 		return nil, err
 	}
@@ -616,19 +623,21 @@ func convertInternalTokenToPrivateExternal(internal *v1.Secret, authenticator se
 	return externalToken, nil
 }
 
-func convertInternalTokenToPublicExternal(internal *v1.Secret, authenticator serviceaccount.TokenAuthenticator) (*apiv1.PublicServiceAccountToken, error) {
+func convertInternalTokenToPublicExternal(internal *v1.Secret) (*apiv1.PublicServiceAccountToken, error) {
 	externalToken := &apiv1.PublicServiceAccountToken{}
 	token, ok := internal.Data["token"]
 	if !ok {
 		return nil, fmt.Errorf("can not find token data")
 	}
 
-	publicClaim, _, err := authenticator.Authenticate(string(token))
+	// the stored token is not verified, so that expired tokens and tokens signed with a rotated
+	// key are listed as well
+	expiry, err := serviceaccount.Expiry(string(token))
 	if err != nil {
 		return nil, fmt.Errorf("unable to create a token for %s due to %v", internal.Name, err)
 	}
 
-	externalToken.Expiry = apiv1.NewTime(publicClaim.Expiry.Time())
+	externalToken.Expiry = apiv1.NewTime(expiry)
 	externalToken.ID = internal.Name
 	name, ok := internal.Labels["name"]
 	if !ok {
@@ -637,5 +646,25 @@ func convertInternalTokenToPublicExternal(internal *v1.Secret, authenticator ser
 	externalToken.Name = name
 
 	externalToken.CreationTimestamp = apiv1.NewTime(internal.CreationTimestamp.Time)
+	if lastUsed, ok := kubernetesprovider.TokenLastUsed(internal); ok {
+		apiLastUsed := apiv1.NewTime(lastUsed)
+		externalToken.LastUsed = &apiLastUsed
+	}
 	return externalToken, nil
 }
+
+// GetTokenKeysEndpoint returns the public keys which verify the service account tokens
+func GetTokenKeysEndpoint(tokenAuthenticator serviceaccount.TokenAuthenticator) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		// the JSON encoding of the keys is defined by go-jose
+		raw, err := json.Marshal(tokenAuthenticator.PublicKeys())
+		if err != nil {
+			return nil, errors.New(http.StatusInternalServerError, err.Error())
+		}
+		keys := &apiv1.JSONWebKeySet{Keys: []apiv1.JSONWebKey{}}
+		if err := json.Unmarshal(raw, keys); err != nil {
+			return nil, errors.New(http.StatusInternalServerError, err.Error())
+		}
+		return keys, nil
+	}
+}
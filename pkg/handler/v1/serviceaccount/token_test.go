@@ -24,6 +24,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
 	kubermaticapiv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
@@ -45,6 +46,7 @@ func TestCreateTokenProject(t *testing.T) {
 		existingKubernetesObjs []ctrlruntimeclient.Object
 		expectedErrorResponse  string
 		expectedName           string
+		expectedLifetime       time.Duration
 		projectToSync          string
 		saToSync               string
 		httpStatus             int
@@ -122,6 +124,47 @@ func TestCreateTokenProject(t *testing.T) {
 			saToSync:               "1",
 			expectedName:           "test",
 		},
+		{
+			name:       "scenario 4: create service account token which expires after an hour",
+			body:       `{"name":"test","expirationSeconds":3600}`,
+			httpStatus: http.StatusCreated,
+			existingKubermaticObjs: []ctrlruntimeclient.Object{
+				/*add projects*/
+				test.GenProject("plan9", kubermaticapiv1.ProjectActive, test.DefaultCreationTimestamp()),
+				/*add bindings*/
+				test.GenBinding("plan9-ID", "john@acme.com", "owners"),
+				test.GenBinding("plan9-ID", "serviceaccount-1@sa.kubermatic.io", "editors"),
+				/*add users*/
+				test.GenUser("", "john", "john@acme.com"),
+				test.GenProjectServiceAccount("1", "test-1", "editors", "plan9-ID"),
+			},
+			existingKubernetesObjs: []ctrlruntimeclient.Object{},
+			existingAPIUser:        *test.GenAPIUser("john", "john@acme.com"),
+			projectToSync:          "plan9-ID",
+			saToSync:               "1",
+			expectedName:           "test",
+			expectedLifetime:       time.Hour,
+		},
+		{
+			name:       "scenario 5: the lifetime of a service account token is too short",
+			body:       `{"name":"test","expirationSeconds":60}`,
+			httpStatus: http.StatusBadRequest,
+			existingKubermaticObjs: []ctrlruntimeclient.Object{
+				/*add projects*/
+				test.GenProject("plan9", kubermaticapiv1.ProjectActive, test.DefaultCreationTimestamp()),
+				/*add bindings*/
+				test.GenBinding("plan9-ID", "john@acme.com", "owners"),
+				test.GenBinding("plan9-ID", "serviceaccount-1@sa.kubermatic.io", "editors"),
+				/*add users*/
+				test.GenUser("", "john", "john@acme.com"),
+				test.GenProjectServiceAccount("1", "test-1", "editors", "plan9-ID"),
+			},
+			existingKubernetesObjs: []ctrlruntimeclient.Object{},
+			existingAPIUser:        *test.GenAPIUser("john", "john@acme.com"),
+			projectToSync:          "plan9-ID",
+			saToSync:               "1",
+			expectedErrorResponse:  `{"error":{"code":400,"message":"the token lifetime must be at least 10m0s"}}`,
+		},
 	}
 
 	for _, tc := range testcases {
@@ -153,10 +196,13 @@ func TestCreateTokenProject(t *testing.T) {
 					t.Fatalf("expected token name %s got %s", tc.expectedName, saToken.Name)
 				}
 
-				_, saTokenClaim, err := fakeClients.TokenAuthenticator.Authenticate(saToken.Token)
+				publicClaim, saTokenClaim, err := fakeClients.TokenAuthenticator.Authenticate(saToken.Token)
 				if err != nil {
 					t.Fatal(err)
 				}
+				if lifetime := publicClaim.Expiry.Time().Sub(publicClaim.IssuedAt.Time()); tc.expectedLifetime > 0 && lifetime != tc.expectedLifetime {
+					t.Fatalf("expected token lifetime %v got %v", tc.expectedLifetime, lifetime)
+				}
 				if saTokenClaim.TokenID != saToken.ID {
 					t.Fatalf("expected ID %s got %s", saToken.ID, saTokenClaim.TokenID)
 				}
@@ -21,17 +21,28 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/go-kit/kit/endpoint"
 	"github.com/gorilla/mux"
+	"golang.org/x/crypto/ssh"
 
 	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
+	"k8c.io/kubermatic/v2/pkg/controller/master-controller-manager/rbac"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/handler/v1/common"
 	"k8c.io/kubermatic/v2/pkg/provider"
+	kubermaticssh "k8c.io/kubermatic/v2/pkg/ssh"
 	"k8c.io/kubermatic/v2/pkg/util/errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// linuxUserNameRegexp matches the user names that are portable across Linux distributions
+var linuxUserNameRegexp = regexp.MustCompile(`^[a-z_][a-z0-9_-]{0,31}$`)
+
 func CreateEndpoint(keyProvider provider.SSHKeyProvider, privilegedSSHKeyProvider provider.PrivilegedSSHKeyProvider, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, userInfoGetter provider.UserInfoGetter) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req, ok := request.(CreateReq)
@@ -52,39 +63,33 @@ func CreateEndpoint(keyProvider provider.SSHKeyProvider, privilegedSSHKeyProvide
 			return nil, errors.NewAlreadyExists("ssh key", req.Key.Name)
 		}
 
-		key, err := createUserSSHKey(ctx, userInfoGetter, keyProvider, privilegedSSHKeyProvider, project, req.Key.Name, req.Key.Spec.PublicKey)
+		var expiresAt *metav1.Time
+		if req.Key.Spec.ExpiresAt != nil {
+			expiresAt = &metav1.Time{Time: req.Key.Spec.ExpiresAt.Time}
+		}
+
+		key, err := createUserSSHKey(ctx, userInfoGetter, keyProvider, privilegedSSHKeyProvider, project, req.Key.Name, req.Key.Spec.PublicKey, req.Key.Spec.User, expiresAt)
 		if err != nil {
 			return nil, common.KubernetesErrorToHTTPError(err)
 		}
 
-		apiKey := apiv1.SSHKey{
-			ObjectMeta: apiv1.ObjectMeta{
-				ID:                key.Name,
-				Name:              key.Spec.Name,
-				CreationTimestamp: apiv1.NewTime(key.CreationTimestamp.Time),
-			},
-			Spec: apiv1.SSHKeySpec{
-				Fingerprint: key.Spec.Fingerprint,
-				PublicKey:   key.Spec.PublicKey,
-			},
-		}
-		return apiKey, nil
+		return common.ConvertInternalSSHKeysToExternal([]*kubermaticv1.UserSSHKey{key})[0], nil
 	}
 }
 
-func createUserSSHKey(ctx context.Context, userInfoGetter provider.UserInfoGetter, keyProvider provider.SSHKeyProvider, privilegedSSHKeyProvider provider.PrivilegedSSHKeyProvider, project *kubermaticv1.Project, keyName, pubKey string) (*kubermaticv1.UserSSHKey, error) {
+func createUserSSHKey(ctx context.Context, userInfoGetter provider.UserInfoGetter, keyProvider provider.SSHKeyProvider, privilegedSSHKeyProvider provider.PrivilegedSSHKeyProvider, project *kubermaticv1.Project, keyName, pubKey, user string, expiresAt *metav1.Time) (*kubermaticv1.UserSSHKey, error) {
 	adminUserInfo, err := userInfoGetter(ctx, "")
 	if err != nil {
 		return nil, err
 	}
 	if adminUserInfo.IsAdmin {
-		return privilegedSSHKeyProvider.CreateUnsecured(project, keyName, pubKey)
+		return privilegedSSHKeyProvider.CreateUnsecured(project, keyName, pubKey, user, expiresAt)
 	}
 	userInfo, err := userInfoGetter(ctx, project.Name)
 	if err != nil {
 		return nil, err
 	}
-	return keyProvider.Create(userInfo, project, keyName, pubKey)
+	return keyProvider.Create(userInfo, project, keyName, pubKey, user, expiresAt)
 }
 
 func DeleteEndpoint(keyProvider provider.SSHKeyProvider, privilegedSSHKeyProvider provider.PrivilegedSSHKeyProvider, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, userInfoGetter provider.UserInfoGetter) endpoint.Endpoint {
@@ -145,6 +150,71 @@ func ListEndpoint(keyProvider provider.SSHKeyProvider, projectProvider provider.
 	}
 }
 
+// SignEndpoint signs a public key with the ssh certificate authority of the project
+func SignEndpoint(privilegedSSHKeyProvider provider.PrivilegedSSHKeyProvider, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, userInfoGetter provider.UserInfoGetter) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(SignReq)
+		if !ok {
+			return nil, errors.NewBadRequest("invalid request")
+		}
+
+		project, err := common.GetProject(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, req.ProjectID, nil)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		if !project.SSHCertificateAuthorityEnabled() {
+			return nil, errors.NewBadRequest("the ssh certificate authority of the project is not enabled")
+		}
+
+		keyID, principals, err := certificateIdentity(ctx, userInfoGetter, project)
+		if err != nil {
+			return nil, err
+		}
+
+		ca, err := privilegedSSHKeyProvider.GetCertificateAuthorityUnsecured(project)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		certificate, err := ca.SignUserKey([]byte(req.Body.PublicKey), keyID, principals, project.Spec.SSHCertificateAuthority.CertificateValidity(), time.Now())
+		if err != nil {
+			return nil, errors.NewBadRequest(err.Error())
+		}
+
+		return apiv1.SSHCertificate{
+			Certificate: strings.TrimSpace(string(ssh.MarshalAuthorizedKey(certificate))),
+			Principals:  certificate.ValidPrincipals,
+			ValidAfter:  apiv1.NewTime(time.Unix(int64(certificate.ValidAfter), 0)),
+			ValidBefore: apiv1.NewTime(time.Unix(int64(certificate.ValidBefore), 0)),
+		}, nil
+	}
+}
+
+// certificateIdentity returns the key id and the principals of the certificate of the user. Owners are allowed to
+// log in as root and editors as the remaining users of the nodes, all other members are not allowed to log in at all.
+func certificateIdentity(ctx context.Context, userInfoGetter provider.UserInfoGetter, project *kubermaticv1.Project) (string, []string, error) {
+	adminUserInfo, err := userInfoGetter(ctx, "")
+	if err != nil {
+		return "", nil, common.KubernetesErrorToHTTPError(err)
+	}
+	if adminUserInfo.IsAdmin {
+		return adminUserInfo.Email, []string{kubermaticssh.OwnersPrincipal}, nil
+	}
+	userInfo, err := userInfoGetter(ctx, project.Name)
+	if err != nil {
+		return "", nil, common.KubernetesErrorToHTTPError(err)
+	}
+
+	switch rbac.ExtractGroupPrefix(userInfo.Group) {
+	case rbac.OwnerGroupNamePrefix:
+		return userInfo.Email, []string{kubermaticssh.OwnersPrincipal}, nil
+	case rbac.EditorGroupNamePrefix:
+		return userInfo.Email, []string{kubermaticssh.EditorsPrincipal}, nil
+	default:
+		return "", nil, errors.New(http.StatusForbidden, fmt.Sprintf("forbidden: members of the group %s are not allowed to access the nodes of the project", userInfo.Group))
+	}
+}
+
 // ListReq defined HTTP request for listSHHKeys endpoint
 // swagger:parameters listSSHKeys
 type ListReq struct {
@@ -213,6 +283,40 @@ func DecodeCreateReq(c context.Context, r *http.Request) (interface{}, error) {
 	if len(req.Key.Spec.PublicKey) == 0 {
 		return nil, fmt.Errorf("'spec.publicKey' field cannot be empty")
 	}
+	if req.Key.Spec.User != "" && !linuxUserNameRegexp.MatchString(req.Key.Spec.User) {
+		return nil, errors.NewBadRequest("'spec.user' field must be a valid Linux user name")
+	}
+	if req.Key.Spec.ExpiresAt != nil && !req.Key.Spec.ExpiresAt.Time.After(time.Now()) {
+		return nil, errors.NewBadRequest("'spec.expiresAt' field must be in the future")
+	}
+
+	return req, nil
+}
+
+// SignReq represent a request to sign a public key with the ssh certificate authority of the project
+// swagger:parameters signSSHKey
+type SignReq struct {
+	common.ProjectReq
+	// in: body
+	Body apiv1.SSHCertificateSigningRequest
+}
+
+func DecodeSignReq(c context.Context, r *http.Request) (interface{}, error) {
+	var req SignReq
+
+	dcr, err := common.DecodeProjectRequest(c, r)
+	if err != nil {
+		return nil, err
+	}
+	req.ProjectReq = dcr.(common.ProjectReq)
+
+	if err := json.NewDecoder(r.Body).Decode(&req.Body); err != nil {
+		return nil, errors.NewBadRequest("unable to parse the input, err = %v", err.Error())
+	}
+
+	if len(req.Body.PublicKey) == 0 {
+		return nil, fmt.Errorf("'publicKey' field cannot be empty")
+	}
 
 	return req, nil
 }
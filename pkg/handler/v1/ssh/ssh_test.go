@@ -25,6 +25,8 @@ import (
 	"testing"
 	"time"
 
+	"golang.org/x/crypto/ssh"
+
 	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/handler/test"
@@ -323,6 +325,64 @@ func TestCreateSSHKeysEndpoint(t *testing.T) {
 			},
 			ExistingAPIUser: test.GenAPIUser("admin", "admin@acme.com"),
 		},
+		// scenario 4
+		{
+			Name:             "scenario 4: a user can create ssh key that is authorized for a single user until it expires",
+			Body:             `{"name":"my-second-ssh-key","spec":{"publicKey":"ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAACAQC8LlXSRW4HUYAjzx1+r5JzpjXIDDyFkWZzBQ8aU14J8LdMyQsU6/ZKuO5IKoWWVoPi0e63qSjkXPTjnUAwpE62hDm6uLaPgIlc3ND+8d9xbItS+gyXk9TSkC3emrsCWpS76W3KjLwyz5euIfnMCQZSASM7F5CrNg6XSppOgRWlyY09VEKi9PmvEDKCy5JNt6afcUzB3rAOK3SYZ0BYDyrVjuqTcMZwRodryxKb/jxDS+qQNplBNuUBqUzqjuKyI5oAk+aVTYIfTwgBTQyZT7So/u70gSDbRp9uHI05PkH60IftAHdYu4TJTmCwJxLW/suOEx3PPvIsUP14XQUZgmDJEuIuWDlsvfOo9DXZNnl832SGvTyhclBpsauWJ1OwOllT+hlM7u8dwcb70GD/OzCG7RSEatVoiNtg4XdeUf4kiqqzKZEqpopHQqwVKMhlhPKKulY0vrtetJxaLokEwPOYyycxlXsNBK2ei/IbGan+uI39v0s30ySWKzr+M9z0QlLAG7rjgCSWFSmy+Ez2fxU5HQQTNCep8+VjNeI79uO9VDJ8qvV/y6fDtrwgl67hUgDcHyv80TzVROTGFBMCP7hyswArT0GxpL9q7PjPU92D43UEDY5YNOZN2A976O5jd4bPrWp0mKsye1BhLrct16Xdn9x68D8nS2T1uSSWovFhkQ== user@example.com ","user":"ubuntu","expiresAt":"2099-01-01T00:00:00Z"}}`,
+			RewriteSSHKeyID:  true,
+			ExpectedResponse: `{"id":"%s","name":"my-second-ssh-key","creationTimestamp":"0001-01-01T00:00:00Z","spec":{"fingerprint":"c0:8a:a5:c7:ab:f3:45:04:f1:85:52:84:64:85:26:7d","publicKey":"ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAACAQC8LlXSRW4HUYAjzx1+r5JzpjXIDDyFkWZzBQ8aU14J8LdMyQsU6/ZKuO5IKoWWVoPi0e63qSjkXPTjnUAwpE62hDm6uLaPgIlc3ND+8d9xbItS+gyXk9TSkC3emrsCWpS76W3KjLwyz5euIfnMCQZSASM7F5CrNg6XSppOgRWlyY09VEKi9PmvEDKCy5JNt6afcUzB3rAOK3SYZ0BYDyrVjuqTcMZwRodryxKb/jxDS+qQNplBNuUBqUzqjuKyI5oAk+aVTYIfTwgBTQyZT7So/u70gSDbRp9uHI05PkH60IftAHdYu4TJTmCwJxLW/suOEx3PPvIsUP14XQUZgmDJEuIuWDlsvfOo9DXZNnl832SGvTyhclBpsauWJ1OwOllT+hlM7u8dwcb70GD/OzCG7RSEatVoiNtg4XdeUf4kiqqzKZEqpopHQqwVKMhlhPKKulY0vrtetJxaLokEwPOYyycxlXsNBK2ei/IbGan+uI39v0s30ySWKzr+M9z0QlLAG7rjgCSWFSmy+Ez2fxU5HQQTNCep8+VjNeI79uO9VDJ8qvV/y6fDtrwgl67hUgDcHyv80TzVROTGFBMCP7hyswArT0GxpL9q7PjPU92D43UEDY5YNOZN2A976O5jd4bPrWp0mKsye1BhLrct16Xdn9x68D8nS2T1uSSWovFhkQ== user@example.com ","user":"ubuntu","expiresAt":"2099-01-01T00:00:00Z"}}`,
+			HTTPStatus:       http.StatusCreated,
+			ExistingProject:  test.GenProject("my-first-project", kubermaticv1.ProjectActive, test.DefaultCreationTimestamp()),
+			ExistingKubermaticObjs: []ctrlruntimeclient.Object{
+				/*add projects*/
+				test.GenProject("my-first-project", kubermaticv1.ProjectActive, test.DefaultCreationTimestamp()),
+				/*add bindings*/
+				test.GenBinding("my-first-project-ID", "john@acme.com", "owners"),
+				/*add users*/
+				test.GenUser("", "john", "john@acme.com"),
+				/*add cluster*/
+				test.GenDefaultCluster(),
+			},
+			ExistingAPIUser: test.GenAPIUser("john", "john@acme.com"),
+		},
+		// scenario 5
+		{
+			Name:             "scenario 5: a user can't create ssh key for an invalid user name",
+			Body:             `{"name":"my-second-ssh-key","spec":{"publicKey":"ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAACAQC8LlXSRW4HUYAjzx1+r5JzpjXIDDyFkWZzBQ8aU14J8LdMyQsU6/ZKuO5IKoWWVoPi0e63qSjkXPTjnUAwpE62hDm6uLaPgIlc3ND+8d9xbItS+gyXk9TSkC3emrsCWpS76W3KjLwyz5euIfnMCQZSASM7F5CrNg6XSppOgRWlyY09VEKi9PmvEDKCy5JNt6afcUzB3rAOK3SYZ0BYDyrVjuqTcMZwRodryxKb/jxDS+qQNplBNuUBqUzqjuKyI5oAk+aVTYIfTwgBTQyZT7So/u70gSDbRp9uHI05PkH60IftAHdYu4TJTmCwJxLW/suOEx3PPvIsUP14XQUZgmDJEuIuWDlsvfOo9DXZNnl832SGvTyhclBpsauWJ1OwOllT+hlM7u8dwcb70GD/OzCG7RSEatVoiNtg4XdeUf4kiqqzKZEqpopHQqwVKMhlhPKKulY0vrtetJxaLokEwPOYyycxlXsNBK2ei/IbGan+uI39v0s30ySWKzr+M9z0QlLAG7rjgCSWFSmy+Ez2fxU5HQQTNCep8+VjNeI79uO9VDJ8qvV/y6fDtrwgl67hUgDcHyv80TzVROTGFBMCP7hyswArT0GxpL9q7PjPU92D43UEDY5YNOZN2A976O5jd4bPrWp0mKsye1BhLrct16Xdn9x68D8nS2T1uSSWovFhkQ== user@example.com ","user":"../root"}}`,
+			ExpectedResponse: `{"error":{"code":400,"message":"'spec.user' field must be a valid Linux user name"}}`,
+			HTTPStatus:       http.StatusBadRequest,
+			ExistingProject:  test.GenProject("my-first-project", kubermaticv1.ProjectActive, test.DefaultCreationTimestamp()),
+			ExistingKubermaticObjs: []ctrlruntimeclient.Object{
+				/*add projects*/
+				test.GenProject("my-first-project", kubermaticv1.ProjectActive, test.DefaultCreationTimestamp()),
+				/*add bindings*/
+				test.GenBinding("my-first-project-ID", "john@acme.com", "owners"),
+				/*add users*/
+				test.GenUser("", "john", "john@acme.com"),
+				/*add cluster*/
+				test.GenDefaultCluster(),
+			},
+			ExistingAPIUser: test.GenAPIUser("john", "john@acme.com"),
+		},
+		// scenario 6
+		{
+			Name:             "scenario 6: a user can't create ssh key that is already expired",
+			Body:             `{"name":"my-second-ssh-key","spec":{"publicKey":"ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAACAQC8LlXSRW4HUYAjzx1+r5JzpjXIDDyFkWZzBQ8aU14J8LdMyQsU6/ZKuO5IKoWWVoPi0e63qSjkXPTjnUAwpE62hDm6uLaPgIlc3ND+8d9xbItS+gyXk9TSkC3emrsCWpS76W3KjLwyz5euIfnMCQZSASM7F5CrNg6XSppOgRWlyY09VEKi9PmvEDKCy5JNt6afcUzB3rAOK3SYZ0BYDyrVjuqTcMZwRodryxKb/jxDS+qQNplBNuUBqUzqjuKyI5oAk+aVTYIfTwgBTQyZT7So/u70gSDbRp9uHI05PkH60IftAHdYu4TJTmCwJxLW/suOEx3PPvIsUP14XQUZgmDJEuIuWDlsvfOo9DXZNnl832SGvTyhclBpsauWJ1OwOllT+hlM7u8dwcb70GD/OzCG7RSEatVoiNtg4XdeUf4kiqqzKZEqpopHQqwVKMhlhPKKulY0vrtetJxaLokEwPOYyycxlXsNBK2ei/IbGan+uI39v0s30ySWKzr+M9z0QlLAG7rjgCSWFSmy+Ez2fxU5HQQTNCep8+VjNeI79uO9VDJ8qvV/y6fDtrwgl67hUgDcHyv80TzVROTGFBMCP7hyswArT0GxpL9q7PjPU92D43UEDY5YNOZN2A976O5jd4bPrWp0mKsye1BhLrct16Xdn9x68D8nS2T1uSSWovFhkQ== user@example.com ","expiresAt":"2000-01-01T00:00:00Z"}}`,
+			ExpectedResponse: `{"error":{"code":400,"message":"'spec.expiresAt' field must be in the future"}}`,
+			HTTPStatus:       http.StatusBadRequest,
+			ExistingProject:  test.GenProject("my-first-project", kubermaticv1.ProjectActive, test.DefaultCreationTimestamp()),
+			ExistingKubermaticObjs: []ctrlruntimeclient.Object{
+				/*add projects*/
+				test.GenProject("my-first-project", kubermaticv1.ProjectActive, test.DefaultCreationTimestamp()),
+				/*add bindings*/
+				test.GenBinding("my-first-project-ID", "john@acme.com", "owners"),
+				/*add users*/
+				test.GenUser("", "john", "john@acme.com"),
+				/*add cluster*/
+				test.GenDefaultCluster(),
+			},
+			ExistingAPIUser: test.GenAPIUser("john", "john@acme.com"),
+		},
 	}
 
 	for _, tc := range testcases {
@@ -358,6 +418,148 @@ func TestCreateSSHKeysEndpoint(t *testing.T) {
 	}
 }
 
+func TestSignSSHKeyEndpoint(t *testing.T) {
+	t.Parallel()
+	const publicKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIKt9MLMT8ZwK6jwCLtvFFqxeovbx5lL9LCmF6sKx9oo6 user@example.com"
+
+	// the custom project roles allow to update ssh keys, so that the requests are not rejected before signing
+	genProjectRole := func(name string) *kubermaticv1.ProjectRole {
+		return &kubermaticv1.ProjectRole{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: kubermaticv1.ProjectRoleSpec{Rules: []kubermaticv1.ProjectRoleRule{
+				{Resource: kubermaticv1.ProjectRoleResourceSSHKeys, Verbs: []string{kubermaticv1.ProjectRoleVerbGet, kubermaticv1.ProjectRoleVerbUpdate}},
+			}},
+		}
+	}
+	genProject := func(validity *metav1.Duration) *kubermaticv1.Project {
+		project := test.GenProject("my-first-project", kubermaticv1.ProjectActive, test.DefaultCreationTimestamp())
+		project.Spec.SSHCertificateAuthority = &kubermaticv1.ProjectSSHCertificateAuthority{Enabled: true, Validity: validity}
+		return project
+	}
+
+	testcases := []struct {
+		Name                   string
+		Body                   string
+		Group                  string
+		Project                *kubermaticv1.Project
+		ExpectedHTTPStatus     int
+		ExpectedPrincipals     []string
+		ExpectedValidity       time.Duration
+		ExistingKubermaticObjs []ctrlruntimeclient.Object
+	}{
+		{
+			Name:               "scenario 1: an owner gets a certificate allowing to log in as root",
+			Body:               fmt.Sprintf(`{"publicKey":%q}`, publicKey),
+			Group:              "owners",
+			Project:            genProject(nil),
+			ExpectedHTTPStatus: http.StatusCreated,
+			ExpectedPrincipals: []string{"kubermatic-owners"},
+			ExpectedValidity:   kubermaticv1.DefaultSSHCertificateValidity,
+		},
+		{
+			Name:               "scenario 2: an editor gets a certificate with the validity of the project",
+			Body:               fmt.Sprintf(`{"publicKey":%q}`, publicKey),
+			Group:              "editors",
+			Project:            genProject(&metav1.Duration{Duration: 15 * time.Minute}),
+			ExpectedHTTPStatus: http.StatusCreated,
+			ExpectedPrincipals: []string{"kubermatic-editors"},
+			ExpectedValidity:   15 * time.Minute,
+		},
+		{
+			Name:               "scenario 3: a viewer can not get a certificate",
+			Body:               fmt.Sprintf(`{"publicKey":%q}`, publicKey),
+			Group:              "viewers",
+			Project:            genProject(nil),
+			ExpectedHTTPStatus: http.StatusForbidden,
+		},
+		{
+			Name:               "scenario 4: the certificate authority of the project must be enabled",
+			Body:               fmt.Sprintf(`{"publicKey":%q}`, publicKey),
+			Group:              "owners",
+			Project:            test.GenProject("my-first-project", kubermaticv1.ProjectActive, test.DefaultCreationTimestamp()),
+			ExpectedHTTPStatus: http.StatusBadRequest,
+		},
+		{
+			Name:               "scenario 5: an invalid public key is rejected",
+			Body:               `{"publicKey":"ssh-ed25519 invalid"}`,
+			Group:              "owners",
+			Project:            genProject(nil),
+			ExpectedHTTPStatus: http.StatusBadRequest,
+		},
+		{
+			Name:                   "scenario 6: a project manager can not get a certificate",
+			Body:                   fmt.Sprintf(`{"publicKey":%q}`, publicKey),
+			Group:                  "projectmanagers",
+			Project:                genProject(nil),
+			ExpectedHTTPStatus:     http.StatusForbidden,
+			ExistingKubermaticObjs: []ctrlruntimeclient.Object{genProjectRole("projectmanagers")},
+		},
+		{
+			Name:                   "scenario 7: a member with a custom role can not get a certificate",
+			Body:                   fmt.Sprintf(`{"publicKey":%q}`, publicKey),
+			Group:                  "devops",
+			Project:                genProject(nil),
+			ExpectedHTTPStatus:     http.StatusForbidden,
+			ExistingKubermaticObjs: []ctrlruntimeclient.Object{genProjectRole("devops")},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/v1/projects/my-first-project-ID/sshkeys/certificate", strings.NewReader(tc.Body))
+			res := httptest.NewRecorder()
+			kubermaticObj := []ctrlruntimeclient.Object{
+				tc.Project,
+				test.GenBinding("my-first-project-ID", "john@acme.com", tc.Group),
+				test.GenUser("", "john", "john@acme.com"),
+			}
+			kubermaticObj = append(kubermaticObj, tc.ExistingKubermaticObjs...)
+			ep, err := test.CreateTestEndpoint(*test.GenAPIUser("john", "john@acme.com"), []ctrlruntimeclient.Object{}, kubermaticObj, nil, nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+
+			ep.ServeHTTP(res, req)
+
+			if res.Code != tc.ExpectedHTTPStatus {
+				t.Fatalf("Expected HTTP status code %d, got %d: %s", tc.ExpectedHTTPStatus, res.Code, res.Body.String())
+			}
+			if res.Code != http.StatusCreated {
+				return
+			}
+
+			response := &apiv1.SSHCertificate{}
+			if err := json.Unmarshal(res.Body.Bytes(), response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(response.Certificate))
+			if err != nil {
+				t.Fatalf("failed to parse certificate: %v", err)
+			}
+			certificate, ok := key.(*ssh.Certificate)
+			if !ok {
+				t.Fatalf("expected a certificate, got %T", key)
+			}
+			if certificate.CertType != ssh.UserCert {
+				t.Errorf("expected a user certificate, got type %d", certificate.CertType)
+			}
+			if certificate.KeyId != "john@acme.com" {
+				t.Errorf("expected key id john@acme.com, got %q", certificate.KeyId)
+			}
+			if got := strings.Join(certificate.ValidPrincipals, ","); got != strings.Join(tc.ExpectedPrincipals, ",") {
+				t.Errorf("expected principals %v, got %v", tc.ExpectedPrincipals, certificate.ValidPrincipals)
+			}
+			if validity := time.Duration(certificate.ValidBefore-certificate.ValidAfter)*time.Second - time.Minute; validity != tc.ExpectedValidity {
+				t.Errorf("expected a validity of %s, got %s", tc.ExpectedValidity, validity)
+			}
+			signedKey, _, _, _, _ := ssh.ParseAuthorizedKey([]byte(publicKey))
+			if string(certificate.Key.Marshal()) != string(signedKey.Marshal()) {
+				t.Error("the certificate doesn't certify the given public key")
+			}
+		})
+	}
+}
+
 func genSSHKey(creationTime time.Time, keyID string, keyName string, projectID string, clusters ...string) *kubermaticv1.UserSSHKey {
 	return &kubermaticv1.UserSSHKey{
 		ObjectMeta: metav1.ObjectMeta{
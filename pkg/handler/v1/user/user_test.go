@@ -801,6 +801,42 @@ func TestAddUserToProject(t *testing.T) {
 			ExistingAPIUser:  *genAPIUser("admin", "admin@acme.com"),
 			ExpectedResponse: `{"id":"405ac8384fa984f787f9486daf34d84d98f20c4d6a12e2cc4ed89be3bcb06ad6","name":"Bob","creationTimestamp":"0001-01-01T00:00:00Z","email":"bob@acme.com","projects":[{"id":"plan9-ID","group":"editors"}]}`,
 		},
+		{
+			Name:          "scenario 10: john the owner of the plan9 project invites bob to the project with a custom project role",
+			Body:          `{"email":"bob@acme.com", "projects":[{"id":"plan9-ID", "group":"operators"}]}`,
+			HTTPStatus:    http.StatusCreated,
+			ProjectToSync: "plan9-ID",
+			ExistingKubermaticObjs: []ctrlruntimeclient.Object{
+				/*add projects*/
+				test.GenProject("plan9", kubermaticapiv1.ProjectActive, test.DefaultCreationTimestamp()),
+				/*add bindings*/
+				test.GenBinding("plan9-ID", "john@acme.com", "owners"),
+				/*add users*/
+				genUser("", "john", "john@acme.com"),
+				genDefaultUser(), /*bob*/
+				/*add project roles*/
+				&kubermaticapiv1.ProjectRole{ObjectMeta: metav1.ObjectMeta{Name: "operators"}},
+			},
+			ExistingAPIUser:  *genAPIUser("john", "john@acme.com"),
+			ExpectedResponse: `{"id":"405ac8384fa984f787f9486daf34d84d98f20c4d6a12e2cc4ed89be3bcb06ad6","name":"Bob","creationTimestamp":"0001-01-01T00:00:00Z","email":"bob@acme.com","projects":[{"id":"plan9-ID","group":"operators"}]}`,
+		},
+		{
+			Name:          "scenario 11: john the owner of the plan9 project tries to invite bob to the project with an unknown group",
+			Body:          `{"email":"bob@acme.com", "projects":[{"id":"plan9-ID", "group":"operators"}]}`,
+			HTTPStatus:    http.StatusBadRequest,
+			ProjectToSync: "plan9-ID",
+			ExistingKubermaticObjs: []ctrlruntimeclient.Object{
+				/*add projects*/
+				test.GenProject("plan9", kubermaticapiv1.ProjectActive, test.DefaultCreationTimestamp()),
+				/*add bindings*/
+				test.GenBinding("plan9-ID", "john@acme.com", "owners"),
+				/*add users*/
+				genUser("", "john", "john@acme.com"),
+				genDefaultUser(), /*bob*/
+			},
+			ExistingAPIUser:  *genAPIUser("john", "john@acme.com"),
+			ExpectedResponse: `{"error":{"code":400,"message":"invalid group name operators"}}`,
+		},
 	}
 
 	for _, tc := range testcases {
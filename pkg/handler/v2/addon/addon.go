@@ -25,6 +25,7 @@ import (
 	"github.com/go-kit/kit/endpoint"
 	"github.com/gorilla/mux"
 
+	addonutils "k8c.io/kubermatic/v2/pkg/addon"
 	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
 	handlercommon "k8c.io/kubermatic/v2/pkg/handler/common"
 	"k8c.io/kubermatic/v2/pkg/handler/v1/common"
@@ -199,6 +200,13 @@ func ListInstallableAddonEndpoint(projectProvider provider.ProjectProvider, priv
 	}
 }
 
+func ListInstallableAddonVersionsEndpoint(projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, userInfoGetter provider.UserInfoGetter, accessibleAddons sets.String, catalog addonutils.Catalog) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+		return handlercommon.ListInstallableAddonVersionsEndpoint(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, accessibleAddons, catalog, req.ProjectID, req.ClusterID)
+	}
+}
+
 func GetAddonEndpoint(projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider, userInfoGetter provider.UserInfoGetter) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(addonReq)
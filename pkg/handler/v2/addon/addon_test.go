@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
 
@@ -466,6 +468,39 @@ func TestCreateAddon(t *testing.T) {
 			},
 			ExistingAPIUser: test.GenAPIUser("john", "john@acme.com"),
 		},
+		// scenario 5
+		{
+			Name: "scenario 5: create an addon pinned to a version",
+			Body: `{
+				"name": "addon2",
+				"spec": {
+					"variables": null,
+					"version": "2.0.0"
+				}
+			}`,
+			ExpectedResponse: apiv1.Addon{
+				ObjectMeta: apiv1.ObjectMeta{
+					ID:   "addon2",
+					Name: "addon2",
+				},
+				Spec: apiv1.AddonSpec{
+					Version: "2.0.0",
+				},
+			},
+			ExpectedHTTPStatus: http.StatusCreated,
+			ExistingKubermaticObjs: []ctrlruntimeclient.Object{
+				test.GenTestSeed(),
+				/*add projects*/
+				test.GenProject("my-first-project", kubermaticv1.ProjectActive, test.DefaultCreationTimestamp()),
+				/*add bindings*/
+				test.GenBinding("my-first-project-ID", "john@acme.com", "owners"),
+				/*add users*/
+				test.GenUser("", "john", "john@acme.com"),
+				/*add cluster*/
+				cluster,
+			},
+			ExistingAPIUser: test.GenAPIUser("john", "john@acme.com"),
+		},
 	}
 
 	for _, tc := range testcases {
@@ -701,3 +736,117 @@ func TestCreatePatchGetAddon(t *testing.T) {
 		})
 	}
 }
+
+func TestListInstallableAddons(t *testing.T) {
+	t.Parallel()
+	creationTime := test.DefaultCreationTimestamp()
+
+	testcases := []struct {
+		Name               string
+		ExistingAddons     []*kubermaticv1.Addon
+		ExpectedResponse   []string
+		ExpectedHTTPStatus int
+	}{
+		{
+			Name:               "scenario 1: list names of installable addons",
+			ExpectedHTTPStatus: http.StatusOK,
+			ExpectedResponse:   []string{"addon1", "addon2"},
+		},
+		{
+			Name: "scenario 2: installed addons are not installable",
+			ExistingAddons: []*kubermaticv1.Addon{
+				test.GenTestAddon("addon1", nil, test.GenDefaultCluster(), creationTime),
+			},
+			ExpectedHTTPStatus: http.StatusOK,
+			ExpectedResponse:   []string{"addon2"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", fmt.Sprintf("/api/v2/projects/%s/clusters/%s/installableaddons", test.GenDefaultProject().Name, test.GenDefaultCluster().Name), strings.NewReader(""))
+			res := httptest.NewRecorder()
+			kubermaticObj := test.GenDefaultKubermaticObjects(test.GenTestSeed(), test.GenDefaultCluster())
+			for _, existingAddon := range tc.ExistingAddons {
+				kubermaticObj = append(kubermaticObj, existingAddon)
+			}
+			ep, err := test.CreateTestEndpoint(*test.GenDefaultAPIUser(), []ctrlruntimeclient.Object{}, kubermaticObj, nil, nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+
+			ep.ServeHTTP(res, req)
+
+			if res.Code != tc.ExpectedHTTPStatus {
+				t.Fatalf("Expected HTTP status code %d, got %d: %s", tc.ExpectedHTTPStatus, res.Code, res.Body.String())
+			}
+
+			var names []string
+			if err := json.Unmarshal(res.Body.Bytes(), &names); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(names, tc.ExpectedResponse) {
+				t.Errorf("expected installable addons %v, got %v", tc.ExpectedResponse, names)
+			}
+		})
+	}
+}
+
+func TestListInstallableAddonVersions(t *testing.T) {
+	t.Parallel()
+	creationTime := test.DefaultCreationTimestamp()
+
+	testcases := []struct {
+		Name               string
+		ExistingAddons     []*kubermaticv1.Addon
+		ExpectedResponse   []apiv1.InstallableAddon
+		ExpectedHTTPStatus int
+	}{
+		{
+			Name:               "scenario 1: list installable addons with their versions",
+			ExpectedHTTPStatus: http.StatusOK,
+			ExpectedResponse: []apiv1.InstallableAddon{
+				{Name: "addon1", Version: "1.0.0"},
+				{Name: "addon2", Version: "2.1.0", Dependencies: []string{"addon1"}, KubernetesVersions: ">= 1.18"},
+			},
+		},
+		{
+			Name: "scenario 2: installed addons are not installable",
+			ExistingAddons: []*kubermaticv1.Addon{
+				test.GenTestAddon("addon1", nil, test.GenDefaultCluster(), creationTime),
+			},
+			ExpectedHTTPStatus: http.StatusOK,
+			ExpectedResponse: []apiv1.InstallableAddon{
+				{Name: "addon2", Version: "2.1.0", Dependencies: []string{"addon1"}, KubernetesVersions: ">= 1.18"},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", fmt.Sprintf("/api/v2/projects/%s/clusters/%s/installableaddons/versions", test.GenDefaultProject().Name, test.GenDefaultCluster().Name), strings.NewReader(""))
+			res := httptest.NewRecorder()
+			kubermaticObj := test.GenDefaultKubermaticObjects(test.GenTestSeed(), test.GenDefaultCluster())
+			for _, existingAddon := range tc.ExistingAddons {
+				kubermaticObj = append(kubermaticObj, existingAddon)
+			}
+			ep, err := test.CreateTestEndpoint(*test.GenDefaultAPIUser(), []ctrlruntimeclient.Object{}, kubermaticObj, nil, nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+
+			ep.ServeHTTP(res, req)
+
+			if res.Code != tc.ExpectedHTTPStatus {
+				t.Fatalf("Expected HTTP status code %d, got %d: %s", tc.ExpectedHTTPStatus, res.Code, res.Body.String())
+			}
+
+			bytes, err := json.Marshal(tc.ExpectedResponse)
+			if err != nil {
+				t.Fatalf("failed to marshall expected response %v", err)
+			}
+			test.CompareWithResult(t, res, string(bytes))
+		})
+	}
+}
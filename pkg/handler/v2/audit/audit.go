@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-kit/kit/endpoint"
+
+	"k8c.io/kubermatic/v2/pkg/audit"
+	"k8c.io/kubermatic/v2/pkg/handler/v1/common"
+	"k8c.io/kubermatic/v2/pkg/provider"
+	k8cerrors "k8c.io/kubermatic/v2/pkg/util/errors"
+)
+
+// defaultLimit is the number of events returned if the request has no limit
+const defaultLimit = 100
+
+// listAuditEventsReq defines HTTP request for listAuditEvents
+// swagger:parameters listAuditEvents
+type listAuditEventsReq struct {
+	// in: query
+	User string `json:"user,omitempty"`
+	// in: query
+	Project string `json:"project,omitempty"`
+	// in: query
+	Limit int `json:"limit,omitempty"`
+}
+
+func DecodeListAuditEventsReq(c context.Context, r *http.Request) (interface{}, error) {
+	req := listAuditEventsReq{
+		User:    r.URL.Query().Get("user"),
+		Project: r.URL.Query().Get("project"),
+		Limit:   defaultLimit,
+	}
+
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		var err error
+		req.Limit, err = strconv.Atoi(limit)
+		if err != nil || req.Limit < 1 {
+			return nil, k8cerrors.NewBadRequest("the limit must be a positive number, got %q", limit)
+		}
+	}
+
+	return req, nil
+}
+
+// ListAuditEventsEndpoint returns the most recent audit events recorded as Kubernetes events, the newest event first
+func ListAuditEventsEndpoint(userInfoGetter provider.UserInfoGetter, auditLogger *audit.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(listAuditEventsReq)
+		if !ok {
+			return nil, k8cerrors.NewBadRequest("invalid request")
+		}
+
+		userInfo, err := userInfoGetter(ctx, "")
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		if !userInfo.IsAdmin {
+			return nil, k8cerrors.New(http.StatusForbidden, fmt.Sprintf("forbidden: \"%s\" doesn't have admin rights", userInfo.Email))
+		}
+
+		events, err := auditLogger.List(ctx, audit.Filter{User: req.User, ProjectID: req.Project, Limit: req.Limit})
+		if errors.Is(err, audit.ErrNoReader) {
+			return nil, k8cerrors.New(http.StatusNotImplemented, "the audit events are not recorded as Kubernetes events")
+		}
+		if err != nil {
+			return nil, err
+		}
+		return events, nil
+	}
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+
+	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
+	apiv2 "k8c.io/kubermatic/v2/pkg/api/v2"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/handler/test"
+	"k8c.io/kubermatic/v2/pkg/handler/test/hack"
+
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type request struct {
+	method string
+	url    string
+	body   string
+}
+
+func TestListAuditEventsEndpoint(t *testing.T) {
+	t.Parallel()
+
+	projectID := test.GenDefaultProject().Name
+	userID := test.GenDefaultUser().Name
+
+	testcases := []struct {
+		name                   string
+		requests               []request
+		queryURL               string
+		existingAPIUser        *apiv1.User
+		existingKubermaticObjs []ctrlruntimeclient.Object
+		expectedHTTPStatus     int
+		expectedEvents         []apiv2.AuditEvent
+	}{
+		{
+			name:                   "scenario 1: a regular user can't list the audit events",
+			queryURL:               "/api/v2/admin/auditevents",
+			existingAPIUser:        test.GenDefaultAPIUser(),
+			existingKubermaticObjs: test.GenDefaultKubermaticObjects(),
+			expectedHTTPStatus:     http.StatusForbidden,
+		},
+		{
+			name: "scenario 2: an admin lists the mutating requests, the newest first",
+			requests: []request{
+				{method: http.MethodGet, url: "/api/v1/projects/" + projectID},
+				{method: http.MethodPut, url: "/api/v1/projects/" + projectID, body: `{"name":"renamed-project"}`},
+				{method: http.MethodDelete, url: "/api/v1/projects/" + projectID + "/sshkeys/missing-key"},
+			},
+			queryURL:               "/api/v2/admin/auditevents",
+			existingAPIUser:        test.GenDefaultAPIUser(),
+			existingKubermaticObjs: []ctrlruntimeclient.Object{test.GenDefaultProject(), genAdminUser(), test.GenDefaultOwnerBinding()},
+			expectedHTTPStatus:     http.StatusOK,
+			expectedEvents: []apiv2.AuditEvent{
+				{
+					User:        "bob@acme.com",
+					UserID:      userID,
+					Admin:       true,
+					ProjectRole: "owners",
+					Method:      http.MethodDelete,
+					Endpoint:    "/api/v1/projects/{project_id}/sshkeys/{key_id}",
+					Resources:   map[string]string{"project_id": projectID, "key_id": "missing-key"},
+					Outcome:     apiv2.AuditOutcomeFailed,
+					Code:        http.StatusNotFound,
+					Error:       `usersshkeies.kubermatic.k8s.io "missing-key" not found`,
+				},
+				{
+					User:        "bob@acme.com",
+					UserID:      userID,
+					Admin:       true,
+					ProjectRole: "owners",
+					Method:      http.MethodPut,
+					Endpoint:    "/api/v1/projects/{project_id}",
+					Resources:   map[string]string{"project_id": projectID},
+					Before:      json.RawMessage(`{"name":"my-first-project"}`),
+					After:       json.RawMessage(`{"name":"renamed-project"}`),
+					Outcome:     apiv2.AuditOutcomeSucceeded,
+				},
+			},
+		},
+		{
+			name: "scenario 3: an admin lists the most recent mutating requests of a project",
+			requests: []request{
+				{method: http.MethodPut, url: "/api/v1/projects/" + projectID, body: `{"name":"renamed-project"}`},
+				{method: http.MethodDelete, url: "/api/v1/projects/" + projectID + "/sshkeys/missing-key"},
+			},
+			queryURL:               "/api/v2/admin/auditevents?project=" + projectID + "&limit=1",
+			existingAPIUser:        test.GenDefaultAPIUser(),
+			existingKubermaticObjs: []ctrlruntimeclient.Object{test.GenDefaultProject(), genAdminUser(), test.GenDefaultOwnerBinding()},
+			expectedHTTPStatus:     http.StatusOK,
+			expectedEvents: []apiv2.AuditEvent{
+				{
+					User:        "bob@acme.com",
+					UserID:      userID,
+					Admin:       true,
+					ProjectRole: "owners",
+					Method:      http.MethodDelete,
+					Endpoint:    "/api/v1/projects/{project_id}/sshkeys/{key_id}",
+					Resources:   map[string]string{"project_id": projectID, "key_id": "missing-key"},
+					Outcome:     apiv2.AuditOutcomeFailed,
+					Code:        http.StatusNotFound,
+					Error:       `usersshkeies.kubermatic.k8s.io "missing-key" not found`,
+				},
+			},
+		},
+		{
+			name: "scenario 4: an admin lists the mutating requests of another user",
+			requests: []request{
+				{method: http.MethodPut, url: "/api/v1/projects/" + projectID, body: `{"name":"renamed-project"}`},
+			},
+			queryURL:               "/api/v2/admin/auditevents?user=john@acme.com",
+			existingAPIUser:        test.GenDefaultAPIUser(),
+			existingKubermaticObjs: []ctrlruntimeclient.Object{test.GenDefaultProject(), genAdminUser(), test.GenDefaultOwnerBinding()},
+			expectedHTTPStatus:     http.StatusOK,
+			expectedEvents:         []apiv2.AuditEvent{},
+		},
+		{
+			name:                   "scenario 5: the limit must be positive",
+			queryURL:               "/api/v2/admin/auditevents?limit=0",
+			existingAPIUser:        test.GenDefaultAPIUser(),
+			existingKubermaticObjs: []ctrlruntimeclient.Object{test.GenDefaultProject(), genAdminUser(), test.GenDefaultOwnerBinding()},
+			expectedHTTPStatus:     http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ep, err := test.CreateTestEndpoint(*tc.existingAPIUser, nil, tc.existingKubermaticObjs, nil, nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+
+			for _, r := range tc.requests {
+				ep.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(r.method, r.url, strings.NewReader(r.body)))
+			}
+
+			req := httptest.NewRequest(http.MethodGet, tc.queryURL, nil)
+			resp := httptest.NewRecorder()
+			ep.ServeHTTP(resp, req)
+
+			if resp.Code != tc.expectedHTTPStatus {
+				t.Fatalf("Expected HTTP status code %d, got %d: %s", tc.expectedHTTPStatus, resp.Code, resp.Body.String())
+			}
+			if resp.Code != http.StatusOK {
+				return
+			}
+
+			events := []apiv2.AuditEvent{}
+			if err := json.Unmarshal(resp.Body.Bytes(), &events); err != nil {
+				t.Fatalf("failed to unmarshal the response: %v", err)
+			}
+			for i := range events {
+				if events[i].Time.IsZero() {
+					t.Errorf("expected event %d to have a time", i)
+				}
+				events[i].Time = apiv1.Time{}
+				if events[i].Method == http.MethodPut && !strings.Contains(string(events[i].Request), `"name":"renamed-project"`) {
+					t.Errorf("expected the request body to be recorded, got %s", events[i].Request)
+				}
+				events[i].Request = nil
+			}
+
+			if diff := deep.Equal(events, tc.expectedEvents); diff != nil {
+				t.Errorf("got different events than expected, diff: %v", diff)
+			}
+		})
+	}
+}
+
+func genAdminUser() *kubermaticv1.User {
+	user := test.GenDefaultUser()
+	user.Spec.IsAdmin = true
+	return user
+}
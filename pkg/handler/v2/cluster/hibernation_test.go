@@ -0,0 +1,152 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
+	apiv2 "k8c.io/kubermatic/v2/pkg/api/v2"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/handler/test"
+	"k8c.io/kubermatic/v2/pkg/handler/test/hack"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestHibernateCluster(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	schedule := &kubermaticv1.HibernationSchedule{Hibernate: "0 20 * * *", Resume: "0 7 * * 1-5"}
+
+	tests := []struct {
+		name               string
+		method             string
+		path               string
+		cluster            *kubermaticv1.Cluster
+		existingAPIUser    *apiv1.User
+		expectedStatus     int
+		expectedHibernated bool
+		expectedPhase      string
+		expectedSchedule   bool
+	}{
+		{
+			name:               "hibernate a cluster",
+			method:             http.MethodPost,
+			path:               "hibernate",
+			cluster:            test.GenCluster("foo", "foo", "project", now),
+			existingAPIUser:    test.GenDefaultAPIUser(),
+			expectedStatus:     http.StatusOK,
+			expectedHibernated: true,
+		},
+		{
+			name:   "resume a hibernated cluster and keep its schedule",
+			method: http.MethodPost,
+			path:   "resume",
+			cluster: test.GenCluster("foo", "foo", "project", now, func(c *kubermaticv1.Cluster) {
+				c.Spec.Hibernation = &kubermaticv1.HibernationSettings{Hibernated: true, Schedule: schedule}
+				c.Status.Hibernation = &kubermaticv1.ClusterHibernationStatus{Phase: kubermaticv1.ClusterHibernationPhaseHibernated}
+			}),
+			existingAPIUser:  test.GenDefaultAPIUser(),
+			expectedStatus:   http.StatusOK,
+			expectedPhase:    string(kubermaticv1.ClusterHibernationPhaseHibernated),
+			expectedSchedule: true,
+		},
+		{
+			name:   "get the hibernation of a cluster with a schedule",
+			method: http.MethodGet,
+			path:   "hibernation",
+			cluster: test.GenCluster("foo", "foo", "project", now, func(c *kubermaticv1.Cluster) {
+				c.Spec.Hibernation = &kubermaticv1.HibernationSettings{Hibernated: true, Schedule: schedule}
+				c.Status.Hibernation = &kubermaticv1.ClusterHibernationStatus{Phase: kubermaticv1.ClusterHibernationPhaseScalingDownControlPlane}
+			}),
+			existingAPIUser:    test.GenDefaultAPIUser(),
+			expectedStatus:     http.StatusOK,
+			expectedHibernated: true,
+			expectedPhase:      string(kubermaticv1.ClusterHibernationPhaseScalingDownControlPlane),
+			expectedSchedule:   true,
+		},
+		{
+			name:   "a cluster which is being deleted can not be hibernated",
+			method: http.MethodPost,
+			path:   "hibernate",
+			cluster: test.GenCluster("foo", "foo", "project", now, func(c *kubermaticv1.Cluster) {
+				c.DeletionTimestamp = &metav1.Time{Time: now}
+				c.Finalizers = []string{"test"}
+			}),
+			existingAPIUser: test.GenDefaultAPIUser(),
+			expectedStatus:  http.StatusBadRequest,
+		},
+		{
+			name:            "a user who is not a member of the project can not hibernate its clusters",
+			method:          http.MethodPost,
+			path:            "hibernate",
+			cluster:         test.GenCluster("foo", "foo", "project", now),
+			existingAPIUser: test.GenAPIUser("John", "john@acme.com"),
+			expectedStatus:  http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, fmt.Sprintf("/api/v2/projects/%s/clusters/foo/%s", test.ProjectName, tc.path), nil)
+			res := httptest.NewRecorder()
+			kubermaticObj := append([]ctrlruntimeclient.Object{tc.cluster, test.GenUser("", "John", "john@acme.com")}, test.GenDefaultKubermaticObjects(test.GenTestSeed())...)
+
+			ep, _, err := test.CreateTestEndpointAndGetClients(*tc.existingAPIUser, nil, []ctrlruntimeclient.Object{}, nil, kubermaticObj, nil, nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+			ep.ServeHTTP(res, req)
+			if res.Code != tc.expectedStatus {
+				t.Fatalf("Expected status code to be %d, got %d\nResponse body: %q", tc.expectedStatus, res.Code, res.Body.String())
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			hibernation := &apiv2.ClusterHibernation{}
+			if err := json.Unmarshal(res.Body.Bytes(), hibernation); err != nil {
+				t.Fatal(err)
+			}
+
+			if hibernation.Settings == nil || hibernation.Settings.Hibernated != tc.expectedHibernated {
+				t.Errorf("expected hibernated %v, got %+v", tc.expectedHibernated, hibernation.Settings)
+			}
+			if hibernation.Phase != tc.expectedPhase {
+				t.Errorf("expected phase %q, got %q", tc.expectedPhase, hibernation.Phase)
+			}
+			if tc.expectedSchedule {
+				if hibernation.Settings == nil || hibernation.Settings.Schedule == nil {
+					t.Errorf("expected the schedule to be kept, got %+v", hibernation.Settings)
+				}
+				if hibernation.NextHibernationTime == nil || hibernation.NextResumptionTime == nil {
+					t.Errorf("expected the next hibernation and resumption times, got %v and %v", hibernation.NextHibernationTime, hibernation.NextResumptionTime)
+				}
+			} else if hibernation.NextHibernationTime != nil || hibernation.NextResumptionTime != nil {
+				t.Errorf("expected no next hibernation and resumption times, got %v and %v", hibernation.NextHibernationTime, hibernation.NextResumptionTime)
+			}
+		})
+	}
+}
@@ -167,6 +167,73 @@ func TestGetMasterKubeconfig(t *testing.T) {
 			ExistingAPIUser:        *test.GenAPIUser("bob", "bob@acme.com"),
 			ExpectedResponseString: `{"error":{"code":403,"message":"forbidden: \"bob@acme.com\" doesn't belong to the given project = foo-ID"}}`,
 		},
+		{
+			Name:         "scenario 5: member of a custom project role gets viewer kubeconfig",
+			HTTPStatus:   http.StatusOK,
+			ProjectToGet: "foo-ID",
+			ClusterToGet: "cluster-foo",
+			ExistingKubermaticObjs: []ctrlruntimeclient.Object{
+				test.GenTestSeed(),
+				/*add projects*/
+				test.GenProject("foo", kubermaticapiv1.ProjectActive, test.DefaultCreationTimestamp()),
+				/*add bindings*/
+				test.GenBinding("foo-ID", "john@acme.com", "operators"),
+				/*add project roles*/
+				&kubermaticapiv1.ProjectRole{
+					ObjectMeta: metav1.ObjectMeta{Name: "operators"},
+					Spec: kubermaticapiv1.ProjectRoleSpec{Rules: []kubermaticapiv1.ProjectRoleRule{
+						{Resource: kubermaticapiv1.ProjectRoleResourceClusters, Verbs: []string{kubermaticapiv1.ProjectRoleVerbGet}},
+					}},
+				},
+
+				/*add users*/
+				test.GenUser("", "john", "john@acme.com"),
+				test.GenCluster("cluster-foo", "cluster-foo", "foo-ID", test.DefaultCreationTimestamp()),
+			},
+			ExistingObjects: []ctrlruntimeclient.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "cluster-cluster-foo",
+						Name:      "viewer-kubeconfig",
+					},
+					Data: map[string][]byte{
+						"kubeconfig": []byte(test.GenerateTestKubeconfig("cluster-foo", test.IDViewerToken)),
+					},
+				},
+			},
+			ExistingAPIUser:        *test.GenAPIUser("john", "john@acme.com"),
+			ExpectedResponseString: genToken(test.IDViewerToken),
+		},
+		{
+			Name:         "scenario 6: member of a group whose custom project role was deleted is forbidden",
+			HTTPStatus:   http.StatusForbidden,
+			ProjectToGet: "foo-ID",
+			ClusterToGet: "cluster-foo",
+			ExistingKubermaticObjs: []ctrlruntimeclient.Object{
+				test.GenTestSeed(),
+				/*add projects*/
+				test.GenProject("foo", kubermaticapiv1.ProjectActive, test.DefaultCreationTimestamp()),
+				/*add bindings*/
+				test.GenBinding("foo-ID", "john@acme.com", "deleted"),
+
+				/*add users*/
+				test.GenUser("", "john", "john@acme.com"),
+				test.GenCluster("cluster-foo", "cluster-foo", "foo-ID", test.DefaultCreationTimestamp()),
+			},
+			ExistingObjects: []ctrlruntimeclient.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "cluster-cluster-foo",
+						Name:      "admin-kubeconfig",
+					},
+					Data: map[string][]byte{
+						"kubeconfig": []byte(test.GenerateTestKubeconfig("cluster-foo", test.IDToken)),
+					},
+				},
+			},
+			ExistingAPIUser:        *test.GenAPIUser("john", "john@acme.com"),
+			ExpectedResponseString: `{"error":{"code":403,"message":"forbidden: the project role \"deleted\" does not exist"}}`,
+		},
 	}
 
 	for _, tc := range testcases {
@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/Masterminds/semver/v3"
+	"github.com/go-test/deep"
 
 	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
 	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
@@ -264,6 +265,122 @@ func TestGetClusterUpgrades(t *testing.T) {
 	}
 }
 
+func TestGetClusterUpgradeSchedule(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	// The window opens once a year on January 1st
+	yearlyWindow := &kubermaticv1.UpdateWindow{Schedule: "0 0 1 1 *", Length: "1h"}
+	nextYear := time.Date(now.Year()+1, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	genCluster := func(version string, modify func(*kubermaticv1.Cluster)) *kubermaticv1.Cluster {
+		c := test.GenCluster("foo", "foo", "project", now)
+		c.Labels = map[string]string{"user": test.UserName}
+		c.Spec.Version = *k8csemver.NewSemverOrDie(version)
+		if modify != nil {
+			modify(c)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name                 string
+		cluster              *kubermaticv1.Cluster
+		expectedTarget       string
+		expectedStage        string
+		expectedNextUpgrade  *time.Time
+		expectedUpdateWindow *kubermaticv1.UpdateWindow
+	}{
+		{
+			name:                "automatic upgrade without update window",
+			cluster:             genCluster("1.6.0", nil),
+			expectedTarget:      "1.6.1",
+			expectedNextUpgrade: &now,
+		},
+		{
+			name: "automatic upgrade in the update window of the project",
+			cluster: genCluster("1.6.0", func(c *kubermaticv1.Cluster) {
+				c.Status.InheritedUpdateWindow = yearlyWindow
+			}),
+			expectedTarget:       "1.6.1",
+			expectedNextUpgrade:  &nextYear,
+			expectedUpdateWindow: yearlyWindow,
+		},
+		{
+			name: "automatic upgrade in progress",
+			cluster: genCluster("1.6.1", func(c *kubermaticv1.Cluster) {
+				c.Spec.UpdateWindow = yearlyWindow
+				c.Status.Upgrade = &kubermaticv1.ClusterUpgradeStatus{
+					FromVersion: "1.6.0",
+					ToVersion:   "1.6.1",
+					Stage:       kubermaticv1.ClusterUpgradeStageControllerManagerAndScheduler,
+				}
+			}),
+			expectedTarget:       "1.6.1",
+			expectedStage:        string(kubermaticv1.ClusterUpgradeStageControllerManagerAndScheduler),
+			expectedNextUpgrade:  &nextYear,
+			expectedUpdateWindow: yearlyWindow,
+		},
+		{
+			name:    "no automatic upgrade",
+			cluster: genCluster("1.6.1", nil),
+		},
+	}
+
+	versions := []*version.Version{
+		{Version: semver.MustParse("1.6.0"), Type: apiv1.KubernetesClusterType},
+		{Version: semver.MustParse("1.6.1"), Type: apiv1.KubernetesClusterType},
+	}
+	updates := []*version.Update{
+		{From: "1.6.0", To: "1.6.1", Automatic: true, Type: apiv1.KubernetesClusterType},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", fmt.Sprintf("/api/v2/projects/%s/clusters/foo/upgrades/schedule", test.ProjectName), nil)
+			res := httptest.NewRecorder()
+			kubermaticObj := append([]ctrlruntimeclient.Object{tc.cluster}, test.GenDefaultKubermaticObjects(test.GenTestSeed())...)
+
+			ep, _, err := test.CreateTestEndpointAndGetClients(*test.GenDefaultAPIUser(), nil, []ctrlruntimeclient.Object{}, nil, kubermaticObj, versions, updates, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+			ep.ServeHTTP(res, req)
+			if res.Code != http.StatusOK {
+				t.Fatalf("Expected status code to be 200, got %d\nResponse body: %q", res.Code, res.Body.String())
+			}
+
+			schedule := &apiv1.ClusterUpgradeSchedule{}
+			if err := json.Unmarshal(res.Body.Bytes(), schedule); err != nil {
+				t.Fatal(err)
+			}
+
+			if schedule.TargetVersion != tc.expectedTarget {
+				t.Errorf("expected target version %q, got %q", tc.expectedTarget, schedule.TargetVersion)
+			}
+			if schedule.Stage != tc.expectedStage {
+				t.Errorf("expected stage %q, got %q", tc.expectedStage, schedule.Stage)
+			}
+			if diff := deep.Equal(schedule.UpdateWindow, tc.expectedUpdateWindow); diff != nil {
+				t.Errorf("unexpected update window: %v", diff)
+			}
+			switch {
+			case tc.expectedNextUpgrade == nil && schedule.NextUpgradeTime != nil:
+				t.Errorf("expected no next upgrade time, got %v", schedule.NextUpgradeTime)
+			case tc.expectedNextUpgrade != nil && schedule.NextUpgradeTime == nil:
+				t.Errorf("expected next upgrade time %v, got none", tc.expectedNextUpgrade)
+			case tc.expectedNextUpgrade != nil && !withinMinute(schedule.NextUpgradeTime.Time, *tc.expectedNextUpgrade):
+				t.Errorf("expected next upgrade time %v, got %v", tc.expectedNextUpgrade, schedule.NextUpgradeTime)
+			}
+		})
+	}
+}
+
+func withinMinute(a, b time.Time) bool {
+	d := a.Sub(b)
+	return d > -time.Minute && d < time.Minute
+}
+
 func TestUpgradeClusterNodeDeployments(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,543 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustertemplate
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/gorilla/mux"
+
+	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
+	apiv2 "k8c.io/kubermatic/v2/pkg/api/v2"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	handlercommon "k8c.io/kubermatic/v2/pkg/handler/common"
+	"k8c.io/kubermatic/v2/pkg/handler/middleware"
+	"k8c.io/kubermatic/v2/pkg/handler/v1/common"
+	"k8c.io/kubermatic/v2/pkg/provider"
+	kubernetesprovider "k8c.io/kubermatic/v2/pkg/provider/kubernetes"
+	utilerrors "k8c.io/kubermatic/v2/pkg/util/errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxInstances is the maximum number of clusters which can be created from a template at once
+const maxInstances = 20
+
+// listClusterTemplatesReq defines HTTP request for listClusterTemplates
+// swagger:parameters listClusterTemplates
+type listClusterTemplatesReq struct {
+	common.ProjectReq
+}
+
+// createClusterTemplateReq defines HTTP request for createClusterTemplate
+// swagger:parameters createClusterTemplate
+type createClusterTemplateReq struct {
+	common.ProjectReq
+	// in: body
+	Body apiv2.ClusterTemplate
+}
+
+// getClusterTemplateReq defines HTTP request for getClusterTemplate and deleteClusterTemplate
+// swagger:parameters getClusterTemplate deleteClusterTemplate
+type getClusterTemplateReq struct {
+	common.ProjectReq
+	// in: path
+	// required: true
+	TemplateID string `json:"template_id"`
+}
+
+// updateClusterTemplateReq defines HTTP request for updateClusterTemplate
+// swagger:parameters updateClusterTemplate
+type updateClusterTemplateReq struct {
+	getClusterTemplateReq
+	// in: body
+	Body apiv2.ClusterTemplate
+}
+
+// createClusterTemplateInstancesReq defines HTTP request for createClusterTemplateInstances
+// swagger:parameters createClusterTemplateInstances
+type createClusterTemplateInstancesReq struct {
+	getClusterTemplateReq
+	// in: body
+	Body apiv2.ClusterTemplateInstances
+}
+
+// seedReq selects the seed of the clusters created from a template for the cluster provider middleware
+type seedReq struct {
+	seedName string
+}
+
+// GetSeedCluster returns the SeedCluster object
+func (req seedReq) GetSeedCluster() apiv1.SeedCluster {
+	return apiv1.SeedCluster{
+		SeedName: req.seedName,
+	}
+}
+
+func DecodeListClusterTemplatesReq(c context.Context, r *http.Request) (interface{}, error) {
+	projectReq, err := common.DecodeProjectRequest(c, r)
+	if err != nil {
+		return nil, err
+	}
+	return listClusterTemplatesReq{ProjectReq: projectReq.(common.ProjectReq)}, nil
+}
+
+func DecodeCreateClusterTemplateReq(c context.Context, r *http.Request) (interface{}, error) {
+	projectReq, err := common.DecodeProjectRequest(c, r)
+	if err != nil {
+		return nil, err
+	}
+	req := createClusterTemplateReq{ProjectReq: projectReq.(common.ProjectReq)}
+	if err := json.NewDecoder(r.Body).Decode(&req.Body); err != nil {
+		return nil, utilerrors.NewBadRequest(err.Error())
+	}
+	return req, nil
+}
+
+func DecodeGetClusterTemplateReq(c context.Context, r *http.Request) (interface{}, error) {
+	projectReq, err := common.DecodeProjectRequest(c, r)
+	if err != nil {
+		return nil, err
+	}
+	req := getClusterTemplateReq{ProjectReq: projectReq.(common.ProjectReq)}
+	req.TemplateID = mux.Vars(r)["template_id"]
+	if req.TemplateID == "" {
+		return nil, utilerrors.NewBadRequest("'template_id' parameter is required but was not provided")
+	}
+	return req, nil
+}
+
+func DecodeUpdateClusterTemplateReq(c context.Context, r *http.Request) (interface{}, error) {
+	getReq, err := DecodeGetClusterTemplateReq(c, r)
+	if err != nil {
+		return nil, err
+	}
+	req := updateClusterTemplateReq{getClusterTemplateReq: getReq.(getClusterTemplateReq)}
+	if err := json.NewDecoder(r.Body).Decode(&req.Body); err != nil {
+		return nil, utilerrors.NewBadRequest(err.Error())
+	}
+	return req, nil
+}
+
+func DecodeCreateClusterTemplateInstancesReq(c context.Context, r *http.Request) (interface{}, error) {
+	getReq, err := DecodeGetClusterTemplateReq(c, r)
+	if err != nil {
+		return nil, err
+	}
+	req := createClusterTemplateInstancesReq{getClusterTemplateReq: getReq.(getClusterTemplateReq)}
+	if err := json.NewDecoder(r.Body).Decode(&req.Body); err != nil {
+		return nil, utilerrors.NewBadRequest(err.Error())
+	}
+	return req, nil
+}
+
+// ListEndpoint returns the global templates, the templates of the project and the templates of the user
+func ListEndpoint(userInfoGetter provider.UserInfoGetter, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider,
+	templateProvider provider.ClusterTemplateProvider) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listClusterTemplatesReq)
+		project, userInfo, err := getProjectAndUserInfo(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, req.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+
+		templates, err := templateProvider.List(userInfo, project.Name)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		result := []apiv2.ClusterTemplate{}
+		for _, template := range templates {
+			apiTemplate, err := convertInternalToAPIClusterTemplate(template)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, *apiTemplate)
+		}
+		return result, nil
+	}
+}
+
+// GetEndpoint returns the given template
+func GetEndpoint(userInfoGetter provider.UserInfoGetter, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider,
+	templateProvider provider.ClusterTemplateProvider) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(getClusterTemplateReq)
+		project, userInfo, err := getProjectAndUserInfo(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, req.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+
+		template, err := templateProvider.Get(userInfo, project.Name, req.TemplateID)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		return convertInternalToAPIClusterTemplate(template)
+	}
+}
+
+// CreateEndpoint saves a new cluster template
+func CreateEndpoint(userInfoGetter provider.UserInfoGetter, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider,
+	templateProvider provider.ClusterTemplateProvider, settingsProvider provider.SettingsProvider, updateManager common.UpdateManager) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createClusterTemplateReq)
+		if err := validateClusterTemplate(req.Body, settingsProvider, updateManager); err != nil {
+			return nil, err
+		}
+		project, userInfo, err := getProjectAndUserInfo(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, req.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+
+		template := &kubermaticv1.ClusterTemplate{}
+		if err := convertAPIToInternalClusterTemplate(req.Body, project, template); err != nil {
+			return nil, err
+		}
+		template.Spec.Owner = userInfo.Email
+
+		template, err = templateProvider.New(userInfo, template)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		return convertInternalToAPIClusterTemplate(template)
+	}
+}
+
+// UpdateEndpoint replaces the configuration and the scope of the given template
+func UpdateEndpoint(userInfoGetter provider.UserInfoGetter, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider,
+	templateProvider provider.ClusterTemplateProvider, settingsProvider provider.SettingsProvider, updateManager common.UpdateManager) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateClusterTemplateReq)
+		if err := validateClusterTemplate(req.Body, settingsProvider, updateManager); err != nil {
+			return nil, err
+		}
+		project, userInfo, err := getProjectAndUserInfo(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, req.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+
+		template, err := templateProvider.Get(userInfo, project.Name, req.TemplateID)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		template = template.DeepCopy()
+		if err := convertAPIToInternalClusterTemplate(req.Body, project, template); err != nil {
+			return nil, err
+		}
+
+		template, err = templateProvider.Update(userInfo, project.Name, template)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		return convertInternalToAPIClusterTemplate(template)
+	}
+}
+
+// DeleteEndpoint deletes the given template, the clusters created from it are kept
+func DeleteEndpoint(userInfoGetter provider.UserInfoGetter, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider,
+	templateProvider provider.ClusterTemplateProvider) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(getClusterTemplateReq)
+		project, userInfo, err := getProjectAndUserInfo(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, req.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, common.KubernetesErrorToHTTPError(templateProvider.Delete(userInfo, project.Name, req.TemplateID))
+	}
+}
+
+// CreateInstancesEndpoint creates clusters from the given template in the project. The clusters are labeled with the
+// name of the template. They are created one after another, if the creation of one fails the clusters created
+// before it are kept and the error is returned.
+func CreateInstancesEndpoint(userInfoGetter provider.UserInfoGetter, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider,
+	templateProvider provider.ClusterTemplateProvider, seedsGetter provider.SeedsGetter, clusterProviderGetter provider.ClusterProviderGetter, credentialManager provider.PresetProvider,
+	exposeStrategy kubermaticv1.ExposeStrategy, settingsProvider provider.SettingsProvider, updateManager common.UpdateManager, caBundle *x509.CertPool) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createClusterTemplateInstancesReq)
+		replicas := req.Body.Replicas
+		if replicas == 0 {
+			replicas = 1
+		}
+		if replicas < 0 || replicas > maxInstances {
+			return nil, utilerrors.NewBadRequest("the number of clusters must be between 1 and %d", maxInstances)
+		}
+
+		project, userInfo, err := getProjectAndUserInfo(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, req.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		template, err := templateProvider.Get(userInfo, project.Name, req.TemplateID)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		// the versions may have changed since the template was saved, the instances are validated like any other cluster
+		globalSettings, err := settingsProvider.GetGlobalSettings()
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		body, err := generateInstance(template, req.Body, "")
+		if err != nil {
+			return nil, err
+		}
+		if err := handlercommon.ValidateClusterSpec(globalSettings.Spec.ClusterTypeOptions, updateManager, *body); err != nil {
+			return nil, utilerrors.NewBadRequest(err.Error())
+		}
+		seedName, err := findSeedNameForDatacenter(seedsGetter, body.Cluster.Spec.Cloud.DatacenterName)
+		if err != nil {
+			return nil, utilerrors.NewBadRequest(err.Error())
+		}
+
+		createInstances := func(ctx context.Context, _ interface{}) (interface{}, error) {
+			clusters := []*apiv1.Cluster{}
+			for i := 1; i <= replicas; i++ {
+				suffix := ""
+				if replicas > 1 {
+					suffix = fmt.Sprintf("-%d", i)
+				}
+				// every instance gets its own body, the creation sets the name of the initial node deployment in it
+				body, err := generateInstance(template, req.Body, suffix)
+				if err != nil {
+					return nil, err
+				}
+				cluster, err := handlercommon.CreateEndpoint(ctx, project.Name, *body, projectProvider, privilegedProjectProvider,
+					seedsGetter, credentialManager, exposeStrategy, userInfoGetter, caBundle)
+				if err != nil {
+					return nil, err
+				}
+				clusters = append(clusters, cluster.(*apiv1.Cluster))
+			}
+			return clusters, nil
+		}
+		return middleware.SetPrivilegedClusterProvider(clusterProviderGetter, seedsGetter)(createInstances)(ctx, seedReq{seedName: seedName})
+	}
+}
+
+// getProjectAndUserInfo returns the project and the user info used to authorize the changes of the templates,
+// the admins are allowed to manage the templates of all projects.
+func getProjectAndUserInfo(ctx context.Context, userInfoGetter provider.UserInfoGetter, projectProvider provider.ProjectProvider,
+	privilegedProjectProvider provider.PrivilegedProjectProvider, projectID string) (*kubermaticv1.Project, *provider.UserInfo, error) {
+	project, err := common.GetProject(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, projectID, nil)
+	if err != nil {
+		return nil, nil, common.KubernetesErrorToHTTPError(err)
+	}
+	adminUserInfo, err := userInfoGetter(ctx, "")
+	if err != nil {
+		return nil, nil, common.KubernetesErrorToHTTPError(err)
+	}
+	if adminUserInfo.IsAdmin {
+		return project, adminUserInfo, nil
+	}
+	userInfo, err := userInfoGetter(ctx, project.Name)
+	if err != nil {
+		return nil, nil, common.KubernetesErrorToHTTPError(err)
+	}
+	return project, userInfo, nil
+}
+
+func validateClusterTemplate(template apiv2.ClusterTemplate, settingsProvider provider.SettingsProvider, updateManager common.UpdateManager) error {
+	if template.Name == "" {
+		return utilerrors.NewBadRequest("the name of the template is required")
+	}
+	switch kubermaticv1.ClusterTemplateScope(template.Scope) {
+	case kubermaticv1.ClusterTemplateScopeGlobal, kubermaticv1.ClusterTemplateScopeUser, kubermaticv1.ClusterTemplateScopeProject:
+	default:
+		return utilerrors.NewBadRequest("invalid scope %q, the scope must be %s, %s or %s", template.Scope,
+			kubermaticv1.ClusterTemplateScopeGlobal, kubermaticv1.ClusterTemplateScopeUser, kubermaticv1.ClusterTemplateScopeProject)
+	}
+	if template.Cluster == nil {
+		return utilerrors.NewBadRequest("the cluster of the template is required")
+	}
+	if kubernetesprovider.CloudSpecContainsCredentials(template.Cluster.Spec.Cloud) {
+		return utilerrors.NewBadRequest("the template must not contain credentials, reference a preset with the credential field instead")
+	}
+
+	cluster := *template.Cluster
+	if cluster.Type == "" {
+		cluster.Type = apiv1.KubernetesClusterType
+	}
+	globalSettings, err := settingsProvider.GetGlobalSettings()
+	if err != nil {
+		return common.KubernetesErrorToHTTPError(err)
+	}
+	if err := handlercommon.ValidateClusterSpec(globalSettings.Spec.ClusterTypeOptions, updateManager, apiv1.CreateClusterSpec{Cluster: cluster}); err != nil {
+		return utilerrors.NewBadRequest(err.Error())
+	}
+	return nil
+}
+
+// generateInstance returns the request to create a cluster from the template with the overrides applied.
+// The suffix is appended to the name of the cluster.
+func generateInstance(template *kubermaticv1.ClusterTemplate, overrides apiv2.ClusterTemplateInstances, suffix string) (*apiv1.CreateClusterSpec, error) {
+	// the creation may change the cloud spec in place, which must not leak into the template
+	apiTemplate, err := convertInternalToAPIClusterTemplate(template.DeepCopy())
+	if err != nil {
+		return nil, err
+	}
+	cluster := apiTemplate.Cluster
+	if overrides.Name != "" {
+		cluster.Name = overrides.Name
+	}
+	cluster.Name += suffix
+	if overrides.Version != nil {
+		cluster.Spec.Version = *overrides.Version
+	}
+	if overrides.Credential != "" {
+		cluster.Credential = overrides.Credential
+	}
+	if cluster.Labels == nil {
+		cluster.Labels = map[string]string{}
+	}
+	for key, value := range overrides.Labels {
+		cluster.Labels[key] = value
+	}
+	cluster.Labels[kubermaticv1.ClusterTemplateLabelKey] = template.Name
+
+	return &apiv1.CreateClusterSpec{Cluster: *cluster, NodeDeployment: apiTemplate.NodeDeployment}, nil
+}
+
+func findSeedNameForDatacenter(seedsGetter provider.SeedsGetter, datacenter string) (string, error) {
+	seeds, err := seedsGetter()
+	if err != nil {
+		return "", fmt.Errorf("failed to list seeds: %v", err)
+	}
+	for name, seed := range seeds {
+		if _, ok := seed.Spec.Datacenters[datacenter]; ok {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("can not find seed for datacenter %s", datacenter)
+}
+
+// convertAPIToInternalClusterTemplate sets the configuration and the scope of the template, its name and owner are kept
+func convertAPIToInternalClusterTemplate(apiTemplate apiv2.ClusterTemplate, project *kubermaticv1.Project, template *kubermaticv1.ClusterTemplate) error {
+	cluster := apiTemplate.Cluster
+	enableUserSSHKeyAgent := true
+	if cluster.Spec.EnableUserSSHKeyAgent != nil {
+		enableUserSSHKeyAgent = *cluster.Spec.EnableUserSSHKeyAgent
+	}
+
+	template.Spec.HumanReadableName = apiTemplate.Name
+	template.Spec.Scope = kubermaticv1.ClusterTemplateScope(apiTemplate.Scope)
+	template.Spec.ClusterLabels = cluster.Labels
+	template.Spec.Credential = cluster.Credential
+	template.Spec.Cluster = kubermaticv1.ClusterSpec{
+		HumanReadableName:                    cluster.Name,
+		Cloud:                                cluster.Spec.Cloud,
+		MachineNetworks:                      cluster.Spec.MachineNetworks,
+		CNIPlugin:                            cluster.Spec.CNIPlugin,
+		OIDC:                                 cluster.Spec.OIDC,
+		UpdateWindow:                         cluster.Spec.UpdateWindow,
+		Hibernation:                          cluster.Spec.Hibernation,
+		Version:                              cluster.Spec.Version,
+		UsePodSecurityPolicyAdmissionPlugin:  cluster.Spec.UsePodSecurityPolicyAdmissionPlugin,
+		UsePodNodeSelectorAdmissionPlugin:    cluster.Spec.UsePodNodeSelectorAdmissionPlugin,
+		EnableUserSSHKeyAgent:                enableUserSSHKeyAgent,
+		AuditLogging:                         cluster.Spec.AuditLogging,
+		AdmissionPlugins:                     cluster.Spec.AdmissionPlugins,
+		OPAIntegration:                       cluster.Spec.OPAIntegration,
+		PodNodeSelectorAdmissionPluginConfig: cluster.Spec.PodNodeSelectorAdmissionPluginConfig,
+		ServiceAccount:                       cluster.Spec.ServiceAccount,
+		MLA:                                  cluster.Spec.MLA,
+	}
+	if cluster.Spec.ClusterNetwork != nil {
+		template.Spec.Cluster.ClusterNetwork = *cluster.Spec.ClusterNetwork
+	}
+
+	template.Spec.InitialMachineDeployment = ""
+	if apiTemplate.NodeDeployment != nil {
+		data, err := json.Marshal(apiTemplate.NodeDeployment)
+		if err != nil {
+			return fmt.Errorf("cannot marshal initial machine deployment: %v", err)
+		}
+		template.Spec.InitialMachineDeployment = string(data)
+	}
+
+	// project templates are deleted together with their project
+	template.Spec.ProjectID = ""
+	template.OwnerReferences = nil
+	if template.Spec.Scope == kubermaticv1.ClusterTemplateScopeProject {
+		template.Spec.ProjectID = project.Name
+		template.OwnerReferences = []metav1.OwnerReference{
+			{
+				APIVersion: kubermaticv1.SchemeGroupVersion.String(),
+				Kind:       kubermaticv1.ProjectKindName,
+				UID:        project.GetUID(),
+				Name:       project.Name,
+			},
+		}
+	}
+	return nil
+}
+
+func convertInternalToAPIClusterTemplate(template *kubermaticv1.ClusterTemplate) (*apiv2.ClusterTemplate, error) {
+	spec := template.Spec.Cluster
+	enableUserSSHKeyAgent := spec.EnableUserSSHKeyAgent
+	labels := map[string]string{}
+	for key, value := range template.Spec.ClusterLabels {
+		labels[key] = value
+	}
+
+	apiTemplate := &apiv2.ClusterTemplate{
+		ID:        template.Name,
+		Name:      template.Spec.HumanReadableName,
+		Scope:     string(template.Spec.Scope),
+		ProjectID: template.Spec.ProjectID,
+		User:      template.Spec.Owner,
+		Cluster: &apiv1.Cluster{
+			ObjectMeta: apiv1.ObjectMeta{
+				Name: spec.HumanReadableName,
+			},
+			Labels:     labels,
+			Type:       apiv1.KubernetesClusterType,
+			Credential: template.Spec.Credential,
+			Spec: apiv1.ClusterSpec{
+				Cloud:                                spec.Cloud,
+				MachineNetworks:                      spec.MachineNetworks,
+				ClusterNetwork:                       handlercommon.ConvertInternalClusterNetworkToExternal(spec.ClusterNetwork),
+				CNIPlugin:                            spec.CNIPlugin,
+				OIDC:                                 spec.OIDC,
+				UpdateWindow:                         spec.UpdateWindow,
+				Hibernation:                          spec.Hibernation,
+				Version:                              spec.Version,
+				UsePodSecurityPolicyAdmissionPlugin:  spec.UsePodSecurityPolicyAdmissionPlugin,
+				UsePodNodeSelectorAdmissionPlugin:    spec.UsePodNodeSelectorAdmissionPlugin,
+				EnableUserSSHKeyAgent:                &enableUserSSHKeyAgent,
+				AdmissionPlugins:                     spec.AdmissionPlugins,
+				AuditLogging:                         spec.AuditLogging,
+				OPAIntegration:                       spec.OPAIntegration,
+				PodNodeSelectorAdmissionPluginConfig: spec.PodNodeSelectorAdmissionPluginConfig,
+				ServiceAccount:                       spec.ServiceAccount,
+				MLA:                                  spec.MLA,
+			},
+		},
+	}
+
+	if template.Spec.InitialMachineDeployment != "" {
+		apiTemplate.NodeDeployment = &apiv1.NodeDeployment{}
+		if err := json.Unmarshal([]byte(template.Spec.InitialMachineDeployment), apiTemplate.NodeDeployment); err != nil {
+			return nil, fmt.Errorf("cannot unmarshal initial machine deployment of template %s: %v", template.Name, err)
+		}
+	}
+	return apiTemplate, nil
+}
@@ -0,0 +1,316 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustertemplate_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+
+	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
+	apiv2 "k8c.io/kubermatic/v2/pkg/api/v2"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/handler/test"
+	"k8c.io/kubermatic/v2/pkg/handler/test/hack"
+	"k8c.io/kubermatic/v2/pkg/semver"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func genClusterTemplate(name string, scope kubermaticv1.ClusterTemplateScope, projectID, owner string) *kubermaticv1.ClusterTemplate {
+	return &kubermaticv1.ClusterTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: kubermaticv1.ClusterTemplateSpec{
+			HumanReadableName: name + "-template",
+			Scope:             scope,
+			ProjectID:         projectID,
+			Owner:             owner,
+			ClusterLabels:     map[string]string{"team": "platform"},
+			Credential:        test.TestFakeCredential,
+			Cluster: kubermaticv1.ClusterSpec{
+				HumanReadableName: "keen-snyder",
+				Cloud: kubermaticv1.CloudSpec{
+					DatacenterName: "fake-dc",
+					Fake:           &kubermaticv1.FakeCloudSpec{},
+				},
+				Version:               *semver.NewSemverOrDie("1.15.0"),
+				EnableUserSSHKeyAgent: true,
+			},
+		},
+	}
+}
+
+func TestListClusterTemplatesEndpoint(t *testing.T) {
+	t.Parallel()
+	projectID := test.GenDefaultProject().Name
+
+	testCases := []struct {
+		Name                      string
+		ExistingKubermaticObjects []ctrlruntimeclient.Object
+		ExistingAPIUser           *apiv1.User
+		ExpectedTemplates         []string
+		ExpectedHTTPStatus        int
+	}{
+		{
+			Name: "scenario 1: list the global templates, the templates of the project and the templates of the user",
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				genClusterTemplate("global", kubermaticv1.ClusterTemplateScopeGlobal, "", "john@acme.com"),
+				genClusterTemplate("bob", kubermaticv1.ClusterTemplateScopeUser, "", test.GenDefaultAPIUser().Email),
+				genClusterTemplate("john", kubermaticv1.ClusterTemplateScopeUser, "", "john@acme.com"),
+				genClusterTemplate("project", kubermaticv1.ClusterTemplateScopeProject, projectID, "john@acme.com"),
+				genClusterTemplate("other-project", kubermaticv1.ClusterTemplateScopeProject, "other-project", "john@acme.com"),
+			),
+			ExistingAPIUser:    test.GenDefaultAPIUser(),
+			ExpectedHTTPStatus: http.StatusOK,
+			ExpectedTemplates:  []string{"bob", "global", "project"},
+		},
+		{
+			Name: "scenario 2: the user john can't list the templates of bob's project",
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				genClusterTemplate("project", kubermaticv1.ClusterTemplateScopeProject, projectID, test.GenDefaultAPIUser().Email),
+				test.GenAdminUser("John", "john@acme.com", false),
+			),
+			ExistingAPIUser:    test.GenAPIUser("John", "john@acme.com"),
+			ExpectedHTTPStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v2/projects/%s/clustertemplates", projectID), nil)
+			resp := httptest.NewRecorder()
+
+			ep, err := test.CreateTestEndpoint(*tc.ExistingAPIUser, nil, tc.ExistingKubermaticObjects, test.GenDefaultVersions(), nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+			ep.ServeHTTP(resp, req)
+
+			if resp.Code != tc.ExpectedHTTPStatus {
+				t.Fatalf("Expected HTTP status code %d, got %d: %s", tc.ExpectedHTTPStatus, resp.Code, resp.Body.String())
+			}
+			if resp.Code == http.StatusOK {
+				templates := []apiv2.ClusterTemplate{}
+				if err := json.Unmarshal(resp.Body.Bytes(), &templates); err != nil {
+					t.Fatalf("failed to unmarshal the response: %v", err)
+				}
+				ids := []string{}
+				for _, template := range templates {
+					ids = append(ids, template.ID)
+				}
+				if diff := deep.Equal(ids, tc.ExpectedTemplates); diff != nil {
+					t.Fatalf("unexpected templates: %v", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateClusterTemplateEndpoint(t *testing.T) {
+	t.Parallel()
+	projectID := test.GenDefaultProject().Name
+
+	testCases := []struct {
+		Name                      string
+		Body                      string
+		ExistingKubermaticObjects []ctrlruntimeclient.Object
+		ExistingAPIUser           *apiv1.User
+		ExpectedResponse          string
+		ExpectedHTTPStatus        int
+	}{
+		{
+			Name:                      "scenario 1: save a project template",
+			Body:                      `{"name":"small","scope":"project","cluster":{"name":"keen-snyder","credential":"fake","labels":{"team":"platform"},"spec":{"version":"1.15.0","cloud":{"fake":{},"dc":"fake-dc"}}},"nodeDeployment":{"spec":{"replicas":1,"template":{"cloud":{},"operatingSystem":{}}}}}`,
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(test.GenTestSeed()),
+			ExistingAPIUser:           test.GenDefaultAPIUser(),
+			ExpectedHTTPStatus:        http.StatusCreated,
+			ExpectedResponse:          `{"id":"%s","name":"small","scope":"project","projectID":"` + projectID + `","user":"bob@acme.com","cluster":{"name":"keen-snyder","creationTimestamp":"0001-01-01T00:00:00Z","labels":{"team":"platform"},"type":"kubernetes","credential":"fake","spec":{"cloud":{"dc":"fake-dc","fake":{}},"version":"1.15.0","oidc":{},"enableUserSSHKeyAgent":true},"status":{"version":"","url":""}},"nodeDeployment":{"name":"","creationTimestamp":"0001-01-01T00:00:00Z","spec":{"replicas":1,"template":{"cloud":{},"operatingSystem":{},"versions":{"kubelet":""}}},"status":{}}}`,
+		},
+		{
+			Name:                      "scenario 2: the template must not contain credentials",
+			Body:                      `{"name":"small","scope":"project","cluster":{"name":"keen-snyder","spec":{"version":"1.15.0","cloud":{"digitalocean":{"token":"secret"},"dc":"regular-do1"}}}}`,
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(test.GenTestSeed()),
+			ExistingAPIUser:           test.GenDefaultAPIUser(),
+			ExpectedHTTPStatus:        http.StatusBadRequest,
+			ExpectedResponse:          `{"error":{"code":400,"message":"the template must not contain credentials, reference a preset with the credential field instead"}}`,
+		},
+		{
+			Name:                      "scenario 3: only admins can save global templates",
+			Body:                      `{"name":"small","scope":"global","cluster":{"name":"keen-snyder","spec":{"version":"1.15.0","cloud":{"fake":{},"dc":"fake-dc"}}}}`,
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(test.GenTestSeed()),
+			ExistingAPIUser:           test.GenDefaultAPIUser(),
+			ExpectedHTTPStatus:        http.StatusForbidden,
+		},
+		{
+			Name:                      "scenario 4: the version of the cluster must be supported",
+			Body:                      `{"name":"small","scope":"user","cluster":{"name":"keen-snyder","spec":{"version":"1.2.3","cloud":{"fake":{},"dc":"fake-dc"}}}}`,
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(test.GenTestSeed()),
+			ExistingAPIUser:           test.GenDefaultAPIUser(),
+			ExpectedHTTPStatus:        http.StatusBadRequest,
+		},
+		{
+			Name:                      "scenario 5: the scope must be valid",
+			Body:                      `{"name":"small","scope":"everyone","cluster":{"name":"keen-snyder","spec":{"version":"1.15.0","cloud":{"fake":{},"dc":"fake-dc"}}}}`,
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(test.GenTestSeed()),
+			ExistingAPIUser:           test.GenDefaultAPIUser(),
+			ExpectedHTTPStatus:        http.StatusBadRequest,
+		},
+		{
+			Name:                      "scenario 6: the network of the cluster is saved",
+			Body:                      `{"name":"dual-stack","scope":"project","cluster":{"name":"keen-snyder","spec":{"version":"1.15.0","cloud":{"fake":{},"dc":"fake-dc"},"clusterNetwork":{"services":{"cidrBlocks":["10.240.16.0/20","fd02::/120"]},"pods":{"cidrBlocks":["172.25.0.0/16","fd01::/48"]},"dnsDomain":"cluster.local","proxyMode":"ipvs"}}}}`,
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(test.GenTestSeed()),
+			ExistingAPIUser:           test.GenDefaultAPIUser(),
+			ExpectedHTTPStatus:        http.StatusCreated,
+			ExpectedResponse:          `{"id":"%s","name":"dual-stack","scope":"project","projectID":"` + projectID + `","user":"bob@acme.com","cluster":{"name":"keen-snyder","creationTimestamp":"0001-01-01T00:00:00Z","type":"kubernetes","spec":{"cloud":{"dc":"fake-dc","fake":{}},"clusterNetwork":{"services":{"cidrBlocks":["10.240.16.0/20","fd02::/120"]},"pods":{"cidrBlocks":["172.25.0.0/16","fd01::/48"]},"dnsDomain":"cluster.local","proxyMode":"ipvs"},"version":"1.15.0","oidc":{},"enableUserSSHKeyAgent":true},"status":{"version":"","url":""}}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v2/projects/%s/clustertemplates", projectID), strings.NewReader(tc.Body))
+			resp := httptest.NewRecorder()
+
+			ep, err := test.CreateTestEndpoint(*tc.ExistingAPIUser, nil, tc.ExistingKubermaticObjects, test.GenDefaultVersions(), nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+			ep.ServeHTTP(resp, req)
+
+			if resp.Code != tc.ExpectedHTTPStatus {
+				t.Fatalf("Expected HTTP status code %d, got %d: %s", tc.ExpectedHTTPStatus, resp.Code, resp.Body.String())
+			}
+			if tc.ExpectedResponse == "" {
+				return
+			}
+			expectedResponse := tc.ExpectedResponse
+			if resp.Code == http.StatusCreated {
+				template := apiv2.ClusterTemplate{}
+				if err := json.Unmarshal(resp.Body.Bytes(), &template); err != nil {
+					t.Fatalf("failed to unmarshal the response: %v", err)
+				}
+				// the ID is generated by the system
+				expectedResponse = fmt.Sprintf(tc.ExpectedResponse, template.ID)
+			}
+			test.CompareWithResult(t, resp, expectedResponse)
+		})
+	}
+}
+
+func TestCreateClusterTemplateInstancesEndpoint(t *testing.T) {
+	t.Parallel()
+	projectID := test.GenDefaultProject().Name
+
+	testCases := []struct {
+		Name                      string
+		TemplateID                string
+		Body                      string
+		ExistingKubermaticObjects []ctrlruntimeclient.Object
+		ExistingAPIUser           *apiv1.User
+		ExpectedClusterNames      []string
+		ExpectedLabels            map[string]string
+		ExpectedHTTPStatus        int
+	}{
+		{
+			Name:       "scenario 1: create a cluster from a template",
+			TemplateID: "project",
+			Body:       `{}`,
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				test.GenTestSeed(),
+				genClusterTemplate("project", kubermaticv1.ClusterTemplateScopeProject, projectID, test.GenDefaultAPIUser().Email),
+			),
+			ExistingAPIUser:      test.GenDefaultAPIUser(),
+			ExpectedHTTPStatus:   http.StatusCreated,
+			ExpectedClusterNames: []string{"keen-snyder"},
+			ExpectedLabels:       map[string]string{"team": "platform", kubermaticv1.ClusterTemplateLabelKey: "project"},
+		},
+		{
+			Name:       "scenario 2: create several clusters from a global template with overrides",
+			TemplateID: "global",
+			Body:       `{"replicas":2,"name":"batch","version":"1.15.0","labels":{"team":"data","stage":"test"}}`,
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				test.GenTestSeed(),
+				genClusterTemplate("global", kubermaticv1.ClusterTemplateScopeGlobal, "", "john@acme.com"),
+			),
+			ExistingAPIUser:      test.GenDefaultAPIUser(),
+			ExpectedHTTPStatus:   http.StatusCreated,
+			ExpectedClusterNames: []string{"batch-1", "batch-2"},
+			ExpectedLabels:       map[string]string{"team": "data", "stage": "test", kubermaticv1.ClusterTemplateLabelKey: "global"},
+		},
+		{
+			Name:       "scenario 3: the templates of other projects can't be used",
+			TemplateID: "other-project",
+			Body:       `{}`,
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				test.GenTestSeed(),
+				genClusterTemplate("other-project", kubermaticv1.ClusterTemplateScopeProject, "other-project", test.GenDefaultAPIUser().Email),
+			),
+			ExistingAPIUser:    test.GenDefaultAPIUser(),
+			ExpectedHTTPStatus: http.StatusNotFound,
+		},
+		{
+			Name:       "scenario 4: the number of clusters is limited",
+			TemplateID: "project",
+			Body:       `{"replicas":100}`,
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				test.GenTestSeed(),
+				genClusterTemplate("project", kubermaticv1.ClusterTemplateScopeProject, projectID, test.GenDefaultAPIUser().Email),
+			),
+			ExistingAPIUser:    test.GenDefaultAPIUser(),
+			ExpectedHTTPStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v2/projects/%s/clustertemplates/%s/instances", projectID, tc.TemplateID), strings.NewReader(tc.Body))
+			resp := httptest.NewRecorder()
+
+			ep, err := test.CreateTestEndpoint(*tc.ExistingAPIUser, nil, tc.ExistingKubermaticObjects, test.GenDefaultVersions(), nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+			ep.ServeHTTP(resp, req)
+
+			if resp.Code != tc.ExpectedHTTPStatus {
+				t.Fatalf("Expected HTTP status code %d, got %d: %s", tc.ExpectedHTTPStatus, resp.Code, resp.Body.String())
+			}
+			if resp.Code != http.StatusCreated {
+				return
+			}
+			clusters := []apiv1.Cluster{}
+			if err := json.Unmarshal(resp.Body.Bytes(), &clusters); err != nil {
+				t.Fatalf("failed to unmarshal the response: %v", err)
+			}
+			names := []string{}
+			for _, cluster := range clusters {
+				names = append(names, cluster.Name)
+				if diff := deep.Equal(cluster.Labels, tc.ExpectedLabels); diff != nil {
+					t.Errorf("unexpected labels of cluster %s: %v", cluster.Name, diff)
+				}
+			}
+			if diff := deep.Equal(names, tc.ExpectedClusterNames); diff != nil {
+				t.Fatalf("unexpected clusters: %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package groupprojectbinding
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/gorilla/mux"
+
+	apiv2 "k8c.io/kubermatic/v2/pkg/api/v2"
+	"k8c.io/kubermatic/v2/pkg/controller/master-controller-manager/rbac"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/handler/v1/common"
+	"k8c.io/kubermatic/v2/pkg/provider"
+	utilerrors "k8c.io/kubermatic/v2/pkg/util/errors"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// listGroupProjectBindingsReq defines HTTP request for listGroupProjectBindings
+// swagger:parameters listGroupProjectBindings
+type listGroupProjectBindingsReq struct {
+	common.ProjectReq
+}
+
+// createGroupProjectBindingReq defines HTTP request for createGroupProjectBinding
+// swagger:parameters createGroupProjectBinding
+type createGroupProjectBindingReq struct {
+	common.ProjectReq
+	// in: body
+	Body apiv2.GroupProjectBinding
+}
+
+// deleteGroupProjectBindingReq defines HTTP request for deleteGroupProjectBinding
+// swagger:parameters deleteGroupProjectBinding
+type deleteGroupProjectBindingReq struct {
+	common.ProjectReq
+	// in: path
+	// required: true
+	BindingName string `json:"binding_name"`
+}
+
+func DecodeListGroupProjectBindingsReq(c context.Context, r *http.Request) (interface{}, error) {
+	projectReq, err := common.DecodeProjectRequest(c, r)
+	if err != nil {
+		return nil, err
+	}
+	return listGroupProjectBindingsReq{ProjectReq: projectReq.(common.ProjectReq)}, nil
+}
+
+func DecodeCreateGroupProjectBindingReq(c context.Context, r *http.Request) (interface{}, error) {
+	projectReq, err := common.DecodeProjectRequest(c, r)
+	if err != nil {
+		return nil, err
+	}
+	req := createGroupProjectBindingReq{ProjectReq: projectReq.(common.ProjectReq)}
+	if err := json.NewDecoder(r.Body).Decode(&req.Body); err != nil {
+		return nil, utilerrors.NewBadRequest(err.Error())
+	}
+	return req, nil
+}
+
+func DecodeDeleteGroupProjectBindingReq(c context.Context, r *http.Request) (interface{}, error) {
+	projectReq, err := common.DecodeProjectRequest(c, r)
+	if err != nil {
+		return nil, err
+	}
+	req := deleteGroupProjectBindingReq{ProjectReq: projectReq.(common.ProjectReq)}
+	req.BindingName = mux.Vars(r)["binding_name"]
+	if req.BindingName == "" {
+		return nil, utilerrors.NewBadRequest("'binding_name' parameter is required but was not provided")
+	}
+	return req, nil
+}
+
+// Validate validates createGroupProjectBindingReq request
+func (r createGroupProjectBindingReq) Validate(projectRoleProvider provider.ProjectRoleProvider) error {
+	if r.Body.Group == "" || r.Body.Role == "" {
+		return utilerrors.NewBadRequest("both the group and the role fields are required")
+	}
+	if rbac.IsBuiltinGroupPrefix(r.Body.Role) {
+		return nil
+	}
+	// the role can also be a custom project role, whose name never contains a dash
+	if strings.Contains(r.Body.Role, "-") {
+		return utilerrors.NewBadRequest("invalid role %s", r.Body.Role)
+	}
+	if _, err := projectRoleProvider.Get(r.Body.Role); err != nil {
+		if kerrors.IsNotFound(err) {
+			return utilerrors.NewBadRequest("invalid role %s", r.Body.Role)
+		}
+		return common.KubernetesErrorToHTTPError(err)
+	}
+	return nil
+}
+
+// ListEndpoint returns the OIDC groups bound to the given project
+func ListEndpoint(userInfoGetter provider.UserInfoGetter, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider,
+	bindingProvider provider.GroupProjectBindingProvider, privilegedBindingProvider provider.PrivilegedGroupProjectBindingProvider) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listGroupProjectBindingsReq)
+		project, err := common.GetProject(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, req.ProjectID, nil)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		bindings, err := listBindings(ctx, userInfoGetter, bindingProvider, privilegedBindingProvider, project)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		result := []apiv2.GroupProjectBinding{}
+		for _, binding := range bindings {
+			result = append(result, convertInternalToAPIGroupProjectBinding(binding))
+		}
+		return result, nil
+	}
+}
+
+// CreateEndpoint binds an OIDC group to the given project
+func CreateEndpoint(userInfoGetter provider.UserInfoGetter, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider,
+	bindingProvider provider.GroupProjectBindingProvider, privilegedBindingProvider provider.PrivilegedGroupProjectBindingProvider, projectRoleProvider provider.ProjectRoleProvider) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createGroupProjectBindingReq)
+		if err := req.Validate(projectRoleProvider); err != nil {
+			return nil, err
+		}
+		project, err := common.GetProject(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, req.ProjectID, nil)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		bindings, err := privilegedBindingProvider.ListUnsecured(project)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		for _, binding := range bindings {
+			if binding.Spec.Group == req.Body.Group {
+				return nil, utilerrors.New(http.StatusConflict, fmt.Sprintf("the group %s is already bound to the project %s", req.Body.Group, req.ProjectID))
+			}
+		}
+
+		adminUserInfo, err := userInfoGetter(ctx, "")
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		var binding *kubermaticv1.GroupProjectBinding
+		if adminUserInfo.IsAdmin {
+			binding, err = privilegedBindingProvider.CreateUnsecured(project, req.Body.Group, req.Body.Role)
+		} else {
+			var userInfo *provider.UserInfo
+			userInfo, err = userInfoGetter(ctx, project.Name)
+			if err != nil {
+				return nil, common.KubernetesErrorToHTTPError(err)
+			}
+			binding, err = bindingProvider.Create(userInfo, project, req.Body.Group, req.Body.Role)
+		}
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		return convertInternalToAPIGroupProjectBinding(binding), nil
+	}
+}
+
+// DeleteEndpoint removes the given binding of an OIDC group from the project
+func DeleteEndpoint(userInfoGetter provider.UserInfoGetter, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider,
+	bindingProvider provider.GroupProjectBindingProvider, privilegedBindingProvider provider.PrivilegedGroupProjectBindingProvider) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(deleteGroupProjectBindingReq)
+		project, err := common.GetProject(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, req.ProjectID, nil)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		bindings, err := privilegedBindingProvider.ListUnsecured(project)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		found := false
+		for _, binding := range bindings {
+			if binding.Name == req.BindingName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, utilerrors.NewNotFound("GroupProjectBinding", req.BindingName)
+		}
+
+		adminUserInfo, err := userInfoGetter(ctx, "")
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+		if adminUserInfo.IsAdmin {
+			err = privilegedBindingProvider.DeleteUnsecured(req.BindingName)
+		} else {
+			var userInfo *provider.UserInfo
+			userInfo, err = userInfoGetter(ctx, project.Name)
+			if err != nil {
+				return nil, common.KubernetesErrorToHTTPError(err)
+			}
+			err = bindingProvider.Delete(userInfo, req.BindingName)
+		}
+		return nil, common.KubernetesErrorToHTTPError(err)
+	}
+}
+
+func listBindings(ctx context.Context, userInfoGetter provider.UserInfoGetter, bindingProvider provider.GroupProjectBindingProvider,
+	privilegedBindingProvider provider.PrivilegedGroupProjectBindingProvider, project *kubermaticv1.Project) ([]*kubermaticv1.GroupProjectBinding, error) {
+	adminUserInfo, err := userInfoGetter(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	if adminUserInfo.IsAdmin {
+		return privilegedBindingProvider.ListUnsecured(project)
+	}
+
+	userInfo, err := userInfoGetter(ctx, project.Name)
+	if err != nil {
+		return nil, err
+	}
+	return bindingProvider.List(userInfo, project)
+}
+
+func convertInternalToAPIGroupProjectBinding(binding *kubermaticv1.GroupProjectBinding) apiv2.GroupProjectBinding {
+	return apiv2.GroupProjectBinding{
+		Name:      binding.Name,
+		Group:     binding.Spec.Group,
+		ProjectID: binding.Spec.ProjectID,
+		Role:      binding.Spec.Role,
+	}
+}
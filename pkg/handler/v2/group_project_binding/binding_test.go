@@ -0,0 +1,252 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package groupprojectbinding_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+
+	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
+	apiv2 "k8c.io/kubermatic/v2/pkg/api/v2"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/handler/test"
+	"k8c.io/kubermatic/v2/pkg/handler/test/hack"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func genGroupProjectBinding(name, group, projectID, role string) *kubermaticv1.GroupProjectBinding {
+	return &kubermaticv1.GroupProjectBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       kubermaticv1.GroupProjectBindingSpec{Group: group, ProjectID: projectID, Role: role},
+	}
+}
+
+func TestListGroupProjectBindingsEndpoint(t *testing.T) {
+	t.Parallel()
+	projectID := test.GenDefaultProject().Name
+
+	testCases := []struct {
+		Name                      string
+		ExistingKubermaticObjects []ctrlruntimeclient.Object
+		ExistingAPIUser           *apiv1.User
+		ExpectedResponse          []apiv2.GroupProjectBinding
+		ExpectedHTTPStatus        int
+	}{
+		{
+			Name: "scenario 1: list the groups bound to the project",
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				genGroupProjectBinding("b1", "developers", projectID, "editors"),
+				genGroupProjectBinding("b2", "auditors", projectID, "viewers"),
+				genGroupProjectBinding("b3", "strangers", "other-project", "owners"),
+			),
+			ExistingAPIUser:    test.GenDefaultAPIUser(),
+			ExpectedHTTPStatus: http.StatusOK,
+			ExpectedResponse: []apiv2.GroupProjectBinding{
+				{Name: "b2", Group: "auditors", ProjectID: projectID, Role: "viewers"},
+				{Name: "b1", Group: "developers", ProjectID: projectID, Role: "editors"},
+			},
+		},
+		{
+			Name: "scenario 2: the user john can't list the groups bound to bob's project",
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				genGroupProjectBinding("b1", "developers", projectID, "editors"),
+				test.GenAdminUser("John", "john@acme.com", false),
+			),
+			ExistingAPIUser:    test.GenAPIUser("John", "john@acme.com"),
+			ExpectedHTTPStatus: http.StatusForbidden,
+		},
+		{
+			Name: "scenario 3: the admin john can list the groups bound to bob's project",
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				genGroupProjectBinding("b1", "developers", projectID, "editors"),
+				test.GenAdminUser("John", "john@acme.com", true),
+			),
+			ExistingAPIUser:    test.GenAPIUser("John", "john@acme.com"),
+			ExpectedHTTPStatus: http.StatusOK,
+			ExpectedResponse: []apiv2.GroupProjectBinding{
+				{Name: "b1", Group: "developers", ProjectID: projectID, Role: "editors"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v2/projects/%s/groupbindings", projectID), nil)
+			resp := httptest.NewRecorder()
+
+			ep, err := test.CreateTestEndpoint(*tc.ExistingAPIUser, nil, tc.ExistingKubermaticObjects, nil, nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+			ep.ServeHTTP(resp, req)
+
+			if resp.Code != tc.ExpectedHTTPStatus {
+				t.Fatalf("Expected HTTP status code %d, got %d: %s", tc.ExpectedHTTPStatus, resp.Code, resp.Body.String())
+			}
+			if resp.Code == http.StatusOK {
+				b, err := json.Marshal(tc.ExpectedResponse)
+				if err != nil {
+					t.Fatalf("failed to marshall expected response %v", err)
+				}
+				test.CompareWithResult(t, resp, string(b))
+			}
+		})
+	}
+}
+
+func TestCreateGroupProjectBindingEndpoint(t *testing.T) {
+	t.Parallel()
+	projectID := test.GenDefaultProject().Name
+
+	testCases := []struct {
+		Name                      string
+		Body                      string
+		ExistingKubermaticObjects []ctrlruntimeclient.Object
+		ExistingAPIUser           *apiv1.User
+		ExpectedResponse          apiv2.GroupProjectBinding
+		ExpectedHTTPStatus        int
+	}{
+		{
+			Name:                      "scenario 1: bind a group to the project as editors",
+			Body:                      `{"group":"developers","role":"editors"}`,
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(),
+			ExistingAPIUser:           test.GenDefaultAPIUser(),
+			ExpectedHTTPStatus:        http.StatusCreated,
+			ExpectedResponse:          apiv2.GroupProjectBinding{Group: "developers", ProjectID: projectID, Role: "editors"},
+		},
+		{
+			Name: "scenario 2: bind a group to the project with a custom project role",
+			Body: `{"group":"operators","role":"scalers"}`,
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				&kubermaticv1.ProjectRole{ObjectMeta: metav1.ObjectMeta{Name: "scalers"}},
+			),
+			ExistingAPIUser:    test.GenDefaultAPIUser(),
+			ExpectedHTTPStatus: http.StatusCreated,
+			ExpectedResponse:   apiv2.GroupProjectBinding{Group: "operators", ProjectID: projectID, Role: "scalers"},
+		},
+		{
+			Name:                      "scenario 3: the role must exist",
+			Body:                      `{"group":"developers","role":"unknown"}`,
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(),
+			ExistingAPIUser:           test.GenDefaultAPIUser(),
+			ExpectedHTTPStatus:        http.StatusBadRequest,
+		},
+		{
+			Name:                      "scenario 4: the group is required",
+			Body:                      `{"role":"editors"}`,
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(),
+			ExistingAPIUser:           test.GenDefaultAPIUser(),
+			ExpectedHTTPStatus:        http.StatusBadRequest,
+		},
+		{
+			Name: "scenario 5: a group can only be bound once to the project",
+			Body: `{"group":"developers","role":"viewers"}`,
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				genGroupProjectBinding("b1", "developers", projectID, "editors"),
+			),
+			ExistingAPIUser:    test.GenDefaultAPIUser(),
+			ExpectedHTTPStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v2/projects/%s/groupbindings", projectID), strings.NewReader(tc.Body))
+			resp := httptest.NewRecorder()
+
+			ep, err := test.CreateTestEndpoint(*tc.ExistingAPIUser, nil, tc.ExistingKubermaticObjects, nil, nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+			ep.ServeHTTP(resp, req)
+
+			if resp.Code != tc.ExpectedHTTPStatus {
+				t.Fatalf("Expected HTTP status code %d, got %d: %s", tc.ExpectedHTTPStatus, resp.Code, resp.Body.String())
+			}
+			if resp.Code == http.StatusCreated {
+				binding := apiv2.GroupProjectBinding{}
+				if err := json.Unmarshal(resp.Body.Bytes(), &binding); err != nil {
+					t.Fatalf("failed to unmarshal the response: %v", err)
+				}
+				if binding.Name == "" {
+					t.Fatal("expected the binding to have a name")
+				}
+				binding.Name = ""
+				if diff := deep.Equal(binding, tc.ExpectedResponse); diff != nil {
+					t.Fatalf("unexpected binding: %v", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestDeleteGroupProjectBindingEndpoint(t *testing.T) {
+	t.Parallel()
+	projectID := test.GenDefaultProject().Name
+
+	testCases := []struct {
+		Name                      string
+		BindingName               string
+		ExistingKubermaticObjects []ctrlruntimeclient.Object
+		ExistingAPIUser           *apiv1.User
+		ExpectedHTTPStatus        int
+	}{
+		{
+			Name:        "scenario 1: remove a group from the project",
+			BindingName: "b1",
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				genGroupProjectBinding("b1", "developers", projectID, "editors"),
+			),
+			ExistingAPIUser:    test.GenDefaultAPIUser(),
+			ExpectedHTTPStatus: http.StatusOK,
+		},
+		{
+			Name:        "scenario 2: the binding of another project can't be removed",
+			BindingName: "b1",
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				genGroupProjectBinding("b1", "developers", "other-project", "editors"),
+			),
+			ExistingAPIUser:    test.GenDefaultAPIUser(),
+			ExpectedHTTPStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v2/projects/%s/groupbindings/%s", projectID, tc.BindingName), nil)
+			resp := httptest.NewRecorder()
+
+			ep, err := test.CreateTestEndpoint(*tc.ExistingAPIUser, nil, tc.ExistingKubermaticObjects, nil, nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+			ep.ServeHTTP(resp, req)
+
+			if resp.Code != tc.ExpectedHTTPStatus {
+				t.Fatalf("Expected HTTP status code %d, got %d: %s", tc.ExpectedHTTPStatus, resp.Code, resp.Body.String())
+			}
+		})
+	}
+}
@@ -158,6 +158,29 @@ func TestCreateMachineDeployment(t *testing.T) {
 			),
 			ExistingAPIUser: test.GenDefaultAPIUser(),
 		},
+
+		// scenario 8
+		{
+			Name:             "scenario 8: a machine deployment exceeding the resource quota of the project is rejected",
+			Body:             `{"spec":{"replicas":2,"template":{"cloud":{"digitalocean":{"size":"s-1vcpu-1gb","backups":false,"ipv6":false,"monitoring":false,"tags":[]}},"operatingSystem":{"ubuntu":{"distUpgradeOnBoot":false}},"versions":{"kubelet":"9.9.9"}}}}`,
+			ExpectedResponse: `{"error":{"code":403,"message":"project resource quota exceeded: nodes (used 0, requested 2, limit 1)"}}`,
+			HTTPStatus:       http.StatusForbidden,
+			ProjectID:        test.GenDefaultProject().Name,
+			ClusterID:        test.GenDefaultCluster().Name,
+			ExistingKubermaticObjs: []ctrlruntimeclient.Object{
+				func() *kubermaticv1.Project {
+					project := test.GenDefaultProject()
+					nodes := int64(1)
+					project.Spec.ResourceQuota = &kubermaticv1.ProjectResourceQuota{Nodes: &nodes}
+					return project
+				}(),
+				test.GenDefaultUser(),
+				test.GenDefaultOwnerBinding(),
+				test.GenTestSeed(),
+				genTestCluster(true),
+			},
+			ExistingAPIUser: test.GenDefaultAPIUser(),
+		},
 	}
 
 	for _, tc := range testcases {
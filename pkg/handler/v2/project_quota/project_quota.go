@@ -0,0 +1,90 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package projectquota
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-kit/kit/endpoint"
+
+	apiv2 "k8c.io/kubermatic/v2/pkg/api/v2"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/handler/v1/common"
+	"k8c.io/kubermatic/v2/pkg/provider"
+	"k8c.io/kubermatic/v2/pkg/quota"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// getProjectResourceQuotaReq defines HTTP request for getProjectResourceQuota
+// swagger:parameters getProjectResourceQuota
+type getProjectResourceQuotaReq struct {
+	common.ProjectReq
+}
+
+func DecodeGetProjectResourceQuotaReq(c context.Context, r *http.Request) (interface{}, error) {
+	projectReq, err := common.DecodeProjectRequest(c, r)
+	if err != nil {
+		return nil, err
+	}
+	return getProjectResourceQuotaReq{ProjectReq: projectReq.(common.ProjectReq)}, nil
+}
+
+// GetEndpoint returns the resource quota of the project and the resources used by its clusters in all seeds
+func GetEndpoint(userInfoGetter provider.UserInfoGetter, projectProvider provider.ProjectProvider, privilegedProjectProvider provider.PrivilegedProjectProvider) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(getProjectResourceQuotaReq)
+
+		project, err := common.GetProject(ctx, userInfoGetter, projectProvider, privilegedProjectProvider, req.ProjectID, nil)
+		if err != nil {
+			return nil, common.KubernetesErrorToHTTPError(err)
+		}
+
+		result := &apiv2.ProjectResourceQuota{
+			Usage: convertUsageToAPI(quota.ProjectUsage(project)),
+		}
+		if q := project.Spec.ResourceQuota; q != nil {
+			result.Quota = apiv2.ProjectResources{
+				Clusters: q.Clusters,
+				Nodes:    q.Nodes,
+				CPU:      quantityString(q.CPU),
+				Memory:   quantityString(q.Memory),
+				Storage:  quantityString(q.Storage),
+			}
+		}
+		return result, nil
+	}
+}
+
+func convertUsageToAPI(usage kubermaticv1.ResourceUsage) apiv2.ProjectResources {
+	clusters, nodes := usage.Clusters, usage.Nodes
+	return apiv2.ProjectResources{
+		Clusters: &clusters,
+		Nodes:    &nodes,
+		CPU:      usage.CPU.String(),
+		Memory:   usage.Memory.String(),
+		Storage:  usage.Storage.String(),
+	}
+}
+
+func quantityString(q *resource.Quantity) string {
+	if q == nil {
+		return ""
+	}
+	return q.String()
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package projectquota_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/handler/test"
+	"k8c.io/kubermatic/v2/pkg/handler/test/hack"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func genProjectWithQuota() *kubermaticv1.Project {
+	project := test.GenDefaultProject()
+	clusters := int64(2)
+	memory := resource.MustParse("8Gi")
+	project.Spec.ResourceQuota = &kubermaticv1.ProjectResourceQuota{
+		Clusters: &clusters,
+		Memory:   &memory,
+	}
+	return project
+}
+
+func TestGetProjectResourceQuotaEndpoint(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		Name                      string
+		ExistingKubermaticObjects []ctrlruntimeclient.Object
+		ExistingAPIUser           *apiv1.User
+		ExpectedResponse          string
+		ExpectedHTTPStatus        int
+	}{
+		{
+			Name: "scenario 1: get the quota and the usage of a project",
+			ExistingKubermaticObjects: []ctrlruntimeclient.Object{
+				func() *kubermaticv1.Project {
+					project := genProjectWithQuota()
+					project.Status.ResourceUsage = &kubermaticv1.ResourceUsage{
+						Clusters: 1,
+						Nodes:    2,
+						CPU:      resource.MustParse("4"),
+						Memory:   resource.MustParse("4Gi"),
+						Storage:  resource.MustParse("50Gi"),
+					}
+					return project
+				}(),
+				test.GenDefaultUser(),
+				test.GenDefaultOwnerBinding(),
+				test.GenTestSeed(),
+				test.GenDefaultCluster(),
+			},
+			ExistingAPIUser:    test.GenDefaultAPIUser(),
+			ExpectedResponse:   `{"quota":{"clusters":2,"memory":"8Gi"},"usage":{"clusters":1,"nodes":2,"cpu":"4","memory":"4Gi","storage":"50Gi"}}`,
+			ExpectedHTTPStatus: http.StatusOK,
+		},
+		{
+			Name: "scenario 2: get the usage of a project without a quota",
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				test.GenTestSeed(),
+			),
+			ExistingAPIUser:    test.GenDefaultAPIUser(),
+			ExpectedResponse:   `{"quota":{},"usage":{"clusters":0,"nodes":0,"cpu":"0","memory":"0","storage":"0"}}`,
+			ExpectedHTTPStatus: http.StatusOK,
+		},
+		{
+			Name: "scenario 3: the user john can't get the quota of bob's project",
+			ExistingKubermaticObjects: test.GenDefaultKubermaticObjects(
+				test.GenTestSeed(),
+				test.GenAdminUser("John", "john@acme.com", false),
+			),
+			ExistingAPIUser:    test.GenAPIUser("John", "john@acme.com"),
+			ExpectedResponse:   `{"error":{"code":403,"message":"forbidden: \"john@acme.com\" doesn't belong to the given project = my-first-project-ID"}}`,
+			ExpectedHTTPStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v2/projects/%s/quota", test.GenDefaultProject().Name), nil)
+			resp := httptest.NewRecorder()
+
+			ep, err := test.CreateTestEndpoint(*tc.ExistingAPIUser, nil, tc.ExistingKubermaticObjects, nil, nil, hack.NewTestRouting)
+			if err != nil {
+				t.Fatalf("failed to create test endpoint due to %v", err)
+			}
+			ep.ServeHTTP(resp, req)
+
+			if resp.Code != tc.ExpectedHTTPStatus {
+				t.Fatalf("Expected HTTP status code %d, got %d: %s", tc.ExpectedHTTPStatus, resp.Code, resp.Body.String())
+			}
+			test.CompareWithResult(t, resp, tc.ExpectedResponse)
+		})
+	}
+}
@@ -18,6 +18,7 @@ package v2
 
 import (
 	"crypto/x509"
+	"net/http"
 	"os"
 
 	"github.com/go-kit/kit/log"
@@ -25,6 +26,8 @@ import (
 	prometheusapi "github.com/prometheus/client_golang/api"
 	"go.uber.org/zap"
 
+	addonutils "k8c.io/kubermatic/v2/pkg/addon"
+	"k8c.io/kubermatic/v2/pkg/audit"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/handler"
 	"k8c.io/kubermatic/v2/pkg/handler/auth"
@@ -70,6 +73,7 @@ type Routing struct {
 	eventRecorderProvider                 provider.EventRecorderProvider
 	exposeStrategy                        kubermaticv1.ExposeStrategy
 	accessibleAddons                      sets.String
+	addonCatalog                          addonutils.Catalog
 	userInfoGetter                        provider.UserInfoGetter
 	settingsProvider                      provider.SettingsProvider
 	adminProvider                         provider.AdminProvider
@@ -83,6 +87,12 @@ type Routing struct {
 	alertmanagerProviderGetter            provider.AlertmanagerProviderGetter
 	versions                              kubermatic.Versions
 	caBundle                              *x509.CertPool
+	auditLogger                           *audit.Logger
+	auditHandler                          http.Handler
+	projectRoleProvider                   provider.ProjectRoleProvider
+	groupProjectBindingProvider           provider.GroupProjectBindingProvider
+	privilegedGroupProjectBindingProvider provider.PrivilegedGroupProjectBindingProvider
+	clusterTemplateProvider               provider.ClusterTemplateProvider
 }
 
 // NewV2Routing creates a new Routing.
@@ -118,6 +128,7 @@ func NewV2Routing(routingParams handler.RoutingParams) Routing {
 		eventRecorderProvider:                 routingParams.EventRecorderProvider,
 		exposeStrategy:                        routingParams.ExposeStrategy,
 		accessibleAddons:                      routingParams.AccessibleAddons,
+		addonCatalog:                          routingParams.AddonCatalog,
 		userInfoGetter:                        routingParams.UserInfoGetter,
 		settingsProvider:                      routingParams.SettingsProvider,
 		adminProvider:                         routingParams.AdminProvider,
@@ -131,6 +142,12 @@ func NewV2Routing(routingParams handler.RoutingParams) Routing {
 		alertmanagerProviderGetter:            routingParams.AlertmanagerProviderGetter,
 		versions:                              routingParams.Versions,
 		caBundle:                              routingParams.CABundle,
+		auditLogger:                           routingParams.AuditLogger,
+		auditHandler:                          routingParams.AuditHandler,
+		projectRoleProvider:                   routingParams.ProjectRoleProvider,
+		groupProjectBindingProvider:           routingParams.GroupProjectBindingProvider,
+		privilegedGroupProjectBindingProvider: routingParams.PrivilegedGroupProjectBindingProvider,
+		clusterTemplateProvider:               routingParams.ClusterTemplateProvider,
 	}
 }
 
@@ -139,6 +156,7 @@ func (r Routing) defaultServerOptions() []httptransport.ServerOption {
 		httptransport.ServerErrorLogger(r.logger),
 		httptransport.ServerErrorEncoder(handler.ErrorEncoder),
 		httptransport.ServerBefore(middleware.TokenExtractor(r.tokenExtractors)),
+		httptransport.ServerBefore(middleware.AuditRequestExtractor(r.auditHandler)),
 		httptransport.ServerBefore(middleware.SetSeedsGetter(r.seedsGetter)),
 	}
 }
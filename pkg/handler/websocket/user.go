@@ -36,7 +36,7 @@ func WriteUser(providers watcher.Providers, ws *websocket.Conn, userEmail string
 		return
 	}
 
-	bindings, err := providers.MemberMapper.MappingsFor(initialUser.Spec.Email)
+	bindings, err := providers.MemberMapper.MappingsFor(initialUser)
 	if err != nil {
 		log.Logger.Debug("cannot get project mappings for user %s: %v", initialUser.Name, err)
 		return
@@ -69,7 +69,7 @@ func WriteUser(providers watcher.Providers, ws *websocket.Conn, userEmail string
 				return
 			}
 
-			bindings, err := providers.MemberMapper.MappingsFor(user.Spec.Email)
+			bindings, err := providers.MemberMapper.MappingsFor(user)
 			if err != nil {
 				log.Logger.Debug("cannot get project mappings for user %s: %v", user.Name, err)
 				return
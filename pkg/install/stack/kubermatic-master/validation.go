@@ -66,6 +66,10 @@ func validateKubermaticConfiguration(config *operatorv1alpha1.KubermaticConfigur
 		failures = append(failures, fmt.Errorf("spec.auth.serviceAccountKey is invalid: %v", err))
 	}
 
+	if _, err := serviceaccount.JWTTokenAuthenticator([]byte(config.Spec.Auth.ServiceAccountVerificationKeys)); err != nil {
+		failures = append(failures, fmt.Errorf("spec.auth.serviceAccountVerificationKeys is invalid: %v", err))
+	}
+
 	if config.Spec.FeatureGates.Has(features.OIDCKubeCfgEndpoint) {
 		failures = validateRandomSecret(config, config.Spec.Auth.IssuerClientSecret, "spec.auth.issuerClientSecret", failures)
 		failures = validateRandomSecret(config, config.Spec.Auth.IssuerCookieKey, "spec.auth.issuerCookieKey", failures)
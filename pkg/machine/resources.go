@@ -0,0 +1,127 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"fmt"
+	"sync"
+
+	ec2 "github.com/cristim/ec2-instances-info"
+
+	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var (
+	awsInstanceData     *ec2.InstanceData
+	awsInstanceDataErr  error
+	awsInstanceDataOnce sync.Once
+)
+
+// NodeResources are the resources of a single node.
+type NodeResources struct {
+	CPU     resource.Quantity
+	Memory  resource.Quantity
+	Storage resource.Quantity
+}
+
+// GetNodeResources returns the resources of a node with the given cloud spec. The vCPUs and memory are only
+// known for providers which configure them explicitly and for AWS, whose instance types are known offline,
+// the nodes of the other providers only account for their disk.
+func GetNodeResources(spec *apiv1.NodeCloudSpec) (*NodeResources, error) {
+	res := &NodeResources{}
+
+	switch {
+	case spec.AWS != nil:
+		vcpus, memoryGiB, found, err := getAWSInstanceSize(spec.AWS.InstanceType)
+		if err != nil {
+			return nil, err
+		}
+		// instance types newer than the instance data only account for their disk
+		if found {
+			res.CPU = *resource.NewQuantity(int64(vcpus), resource.DecimalSI)
+			res.Memory = megabytes(int64(memoryGiB * 1024))
+		}
+		res.Storage = gigabytes(spec.AWS.VolumeSize)
+	case spec.Azure != nil:
+		res.Storage = gigabytes(int64(spec.Azure.OSDiskSize) + int64(spec.Azure.DataDiskSize))
+	case spec.GCP != nil:
+		res.Storage = gigabytes(spec.GCP.DiskSize)
+	case spec.Openstack != nil:
+		if spec.Openstack.RootDiskSizeGB != nil {
+			res.Storage = gigabytes(int64(*spec.Openstack.RootDiskSizeGB))
+		}
+	case spec.VSphere != nil:
+		res.CPU = *resource.NewQuantity(int64(spec.VSphere.CPUs), resource.DecimalSI)
+		res.Memory = megabytes(int64(spec.VSphere.Memory))
+		if spec.VSphere.DiskSizeGB != nil {
+			res.Storage = gigabytes(*spec.VSphere.DiskSizeGB)
+		}
+	case spec.Anexia != nil:
+		res.CPU = *resource.NewQuantity(int64(spec.Anexia.CPUs), resource.DecimalSI)
+		res.Memory = megabytes(spec.Anexia.Memory)
+		res.Storage = gigabytes(spec.Anexia.DiskSize)
+	case spec.Kubevirt != nil:
+		var err error
+		if res.CPU, err = resource.ParseQuantity(spec.Kubevirt.CPUs); err != nil {
+			return nil, fmt.Errorf("invalid kubevirt cpus %q: %v", spec.Kubevirt.CPUs, err)
+		}
+		if res.Memory, err = resource.ParseQuantity(spec.Kubevirt.Memory); err != nil {
+			return nil, fmt.Errorf("invalid kubevirt memory %q: %v", spec.Kubevirt.Memory, err)
+		}
+		if res.Storage, err = resource.ParseQuantity(spec.Kubevirt.PVCSize); err != nil {
+			return nil, fmt.Errorf("invalid kubevirt pvc size %q: %v", spec.Kubevirt.PVCSize, err)
+		}
+	case spec.Alibaba != nil:
+		if spec.Alibaba.DiskSize != "" {
+			size, err := resource.ParseQuantity(spec.Alibaba.DiskSize + "G")
+			if err != nil {
+				return nil, fmt.Errorf("invalid alibaba disk size %q: %v", spec.Alibaba.DiskSize, err)
+			}
+			res.Storage = size
+		}
+	}
+
+	return res, nil
+}
+
+// getAWSInstanceSize returns the vCPUs and the memory in GiB of the given AWS instance type. The instance
+// data is big, so it is only loaded once it's needed.
+func getAWSInstanceSize(instanceType string) (int, float32, bool, error) {
+	awsInstanceDataOnce.Do(func() {
+		awsInstanceData, awsInstanceDataErr = ec2.Data()
+	})
+	if awsInstanceDataErr != nil {
+		return 0, 0, false, fmt.Errorf("failed to load AWS instance type data: %v", awsInstanceDataErr)
+	}
+
+	for _, instance := range *awsInstanceData {
+		if instance.InstanceType == instanceType {
+			return instance.VCPU, instance.Memory, true, nil
+		}
+	}
+	return 0, 0, false, nil
+}
+
+func gigabytes(size int64) resource.Quantity {
+	return *resource.NewQuantity(size*1024*1024*1024, resource.BinarySI)
+}
+
+func megabytes(size int64) resource.Quantity {
+	return *resource.NewQuantity(size*1024*1024, resource.BinarySI)
+}
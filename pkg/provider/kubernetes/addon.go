@@ -57,7 +57,7 @@ func NewAddonProvider(
 }
 
 // New creates a new addon in the given cluster
-func (p *AddonProvider) New(userInfo *provider.UserInfo, cluster *kubermaticv1.Cluster, addonName string, variables *runtime.RawExtension, labels map[string]string) (*kubermaticv1.Addon, error) {
+func (p *AddonProvider) New(userInfo *provider.UserInfo, cluster *kubermaticv1.Cluster, addonName, version string, variables *runtime.RawExtension, labels map[string]string) (*kubermaticv1.Addon, error) {
 	if !p.accessibleAddons.Has(addonName) {
 		return nil, kerrors.NewUnauthorized(fmt.Sprintf("addon not accessible: %v", addonName))
 	}
@@ -67,7 +67,7 @@ func (p *AddonProvider) New(userInfo *provider.UserInfo, cluster *kubermaticv1.C
 		return nil, err
 	}
 
-	addon := genAddon(cluster, addonName, variables, labels)
+	addon := genAddon(cluster, addonName, version, variables, labels)
 
 	if err = seedImpersonatedClient.Create(context.Background(), addon); err != nil {
 		return nil, err
@@ -80,12 +80,12 @@ func (p *AddonProvider) New(userInfo *provider.UserInfo, cluster *kubermaticv1.C
 //
 // Note that this function:
 // is unsafe in a sense that it uses privileged account to create the resource
-func (p *AddonProvider) NewUnsecured(cluster *kubermaticv1.Cluster, addonName string, variables *runtime.RawExtension, labels map[string]string) (*kubermaticv1.Addon, error) {
+func (p *AddonProvider) NewUnsecured(cluster *kubermaticv1.Cluster, addonName, version string, variables *runtime.RawExtension, labels map[string]string) (*kubermaticv1.Addon, error) {
 	if !p.accessibleAddons.Has(addonName) {
 		return nil, kerrors.NewUnauthorized(fmt.Sprintf("addon not accessible: %v", addonName))
 	}
 
-	addon := genAddon(cluster, addonName, variables, labels)
+	addon := genAddon(cluster, addonName, version, variables, labels)
 
 	if err := p.clientPrivileged.Create(context.Background(), addon); err != nil {
 		return nil, err
@@ -94,7 +94,7 @@ func (p *AddonProvider) NewUnsecured(cluster *kubermaticv1.Cluster, addonName st
 	return addon, nil
 }
 
-func genAddon(cluster *kubermaticv1.Cluster, addonName string, variables *runtime.RawExtension, labels map[string]string) *kubermaticv1.Addon {
+func genAddon(cluster *kubermaticv1.Cluster, addonName, version string, variables *runtime.RawExtension, labels map[string]string) *kubermaticv1.Addon {
 	gv := kubermaticv1.SchemeGroupVersion
 	if labels == nil {
 		labels = map[string]string{}
@@ -116,6 +116,7 @@ func genAddon(cluster *kubermaticv1.Cluster, addonName string, variables *runtim
 				Kind:       "Cluster",
 			},
 			Variables: *variables,
+			Version:   version,
 		},
 	}
 }
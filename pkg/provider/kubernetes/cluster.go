@@ -362,8 +362,8 @@ func (p *ClusterProvider) GetClientForCustomerCluster(ctx context.Context, userI
 }
 
 func (p *ClusterProvider) GetTokenForCustomerCluster(ctx context.Context, userInfo *provider.UserInfo, cluster *kubermaticv1.Cluster) (string, error) {
-	parts := strings.Split(userInfo.Group, "-")
-	switch parts[0] {
+	// the members of custom project roles get the token of the built-in group they are impersonated as
+	switch p.extractGroupPrefix(userInfo.Group) {
 	case "editors":
 		return cluster.Address.AdminToken, nil
 	case "owners":
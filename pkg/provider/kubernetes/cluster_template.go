@@ -0,0 +1,163 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"k8c.io/kubermatic/v2/pkg/controller/master-controller-manager/rbac"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/provider"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/rand"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var clusterTemplateResource = schema.GroupResource{Group: kubermaticv1.SchemeGroupVersion.Group, Resource: kubermaticv1.ClusterTemplateResourceName}
+
+// NewClusterTemplateProvider returns a cluster template provider
+func NewClusterTemplateProvider(clientPrivileged ctrlruntimeclient.Client) *ClusterTemplateProvider {
+	return &ClusterTemplateProvider{
+		clientPrivileged: clientPrivileged,
+	}
+}
+
+var _ provider.ClusterTemplateProvider = &ClusterTemplateProvider{}
+
+// ClusterTemplateProvider manages cluster templates. The templates are not covered by the RBAC of the projects,
+// the provider authorizes the users itself.
+type ClusterTemplateProvider struct {
+	// treat clientPrivileged as a privileged user and use wisely
+	clientPrivileged ctrlruntimeclient.Client
+}
+
+// New creates the given template if the user is allowed to manage templates of its scope
+func (p *ClusterTemplateProvider) New(userInfo *provider.UserInfo, template *kubermaticv1.ClusterTemplate) (*kubermaticv1.ClusterTemplate, error) {
+	if err := authorizeClusterTemplateChange(userInfo, template); err != nil {
+		return nil, err
+	}
+	if template.Name == "" {
+		template.Name = rand.String(10)
+	}
+	if err := p.clientPrivileged.Create(context.Background(), template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// Get returns the given template if the user can use it in the given project
+func (p *ClusterTemplateProvider) Get(userInfo *provider.UserInfo, projectID, templateName string) (*kubermaticv1.ClusterTemplate, error) {
+	template := &kubermaticv1.ClusterTemplate{}
+	if err := p.clientPrivileged.Get(context.Background(), ctrlruntimeclient.ObjectKey{Name: templateName}, template); err != nil {
+		return nil, err
+	}
+	// templates which can not be used in the project are hidden from the user
+	if !clusterTemplateVisible(userInfo, projectID, template) {
+		return nil, kerrors.NewNotFound(clusterTemplateResource, templateName)
+	}
+	return template, nil
+}
+
+// List gets the global templates, the templates of the given project and the templates of the user
+func (p *ClusterTemplateProvider) List(userInfo *provider.UserInfo, projectID string) ([]*kubermaticv1.ClusterTemplate, error) {
+	allTemplates := &kubermaticv1.ClusterTemplateList{}
+	if err := p.clientPrivileged.List(context.Background(), allTemplates); err != nil {
+		return nil, err
+	}
+
+	templates := []*kubermaticv1.ClusterTemplate{}
+	for _, template := range allTemplates.Items {
+		if clusterTemplateVisible(userInfo, projectID, &template) {
+			templates = append(templates, template.DeepCopy())
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Spec.HumanReadableName < templates[j].Spec.HumanReadableName
+	})
+	return templates, nil
+}
+
+// Update updates the given template if the user is allowed to manage it
+func (p *ClusterTemplateProvider) Update(userInfo *provider.UserInfo, projectID string, template *kubermaticv1.ClusterTemplate) (*kubermaticv1.ClusterTemplate, error) {
+	existing, err := p.Get(userInfo, projectID, template.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeClusterTemplateChange(userInfo, existing); err != nil {
+		return nil, err
+	}
+	// the scope may change as well, the user must be allowed to manage the templates of the new one
+	if err := authorizeClusterTemplateChange(userInfo, template); err != nil {
+		return nil, err
+	}
+	if err := p.clientPrivileged.Update(context.Background(), template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// Delete deletes the given template if the user is allowed to manage it
+func (p *ClusterTemplateProvider) Delete(userInfo *provider.UserInfo, projectID, templateName string) error {
+	template, err := p.Get(userInfo, projectID, templateName)
+	if err != nil {
+		return err
+	}
+	if err := authorizeClusterTemplateChange(userInfo, template); err != nil {
+		return err
+	}
+	return p.clientPrivileged.Delete(context.Background(), template)
+}
+
+// clusterTemplateVisible returns true if the template can be used in the project by the user
+func clusterTemplateVisible(userInfo *provider.UserInfo, projectID string, template *kubermaticv1.ClusterTemplate) bool {
+	switch template.Spec.Scope {
+	case kubermaticv1.ClusterTemplateScopeGlobal:
+		return true
+	case kubermaticv1.ClusterTemplateScopeUser:
+		return template.Spec.Owner == userInfo.Email
+	case kubermaticv1.ClusterTemplateScopeProject:
+		return template.Spec.ProjectID == projectID
+	}
+	return false
+}
+
+// authorizeClusterTemplateChange returns a forbidden error if the user is not allowed to manage the template
+func authorizeClusterTemplateChange(userInfo *provider.UserInfo, template *kubermaticv1.ClusterTemplate) error {
+	if userInfo.IsAdmin {
+		return nil
+	}
+	switch template.Spec.Scope {
+	case kubermaticv1.ClusterTemplateScopeGlobal:
+		return kerrors.NewForbidden(clusterTemplateResource, template.Name, fmt.Errorf("%q doesn't have admin rights", userInfo.Email))
+	case kubermaticv1.ClusterTemplateScopeUser:
+		if template.Spec.Owner != userInfo.Email {
+			return kerrors.NewForbidden(clusterTemplateResource, template.Name, fmt.Errorf("%q is not the owner of the template", userInfo.Email))
+		}
+		return nil
+	case kubermaticv1.ClusterTemplateScopeProject:
+		groupPrefix := rbac.ExtractGroupPrefix(userInfo.Group)
+		if userInfo.Group == "" || groupPrefix == rbac.ViewerGroupNamePrefix {
+			return kerrors.NewForbidden(clusterTemplateResource, template.Name, fmt.Errorf("%q is not allowed to manage the templates of project %s", userInfo.Email, template.Spec.ProjectID))
+		}
+		return nil
+	}
+	return kerrors.NewBadRequest(fmt.Sprintf("invalid cluster template scope %q", template.Spec.Scope))
+}
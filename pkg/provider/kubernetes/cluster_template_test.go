@@ -0,0 +1,186 @@
+/*
+Copyright 2020 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes_test
+
+import (
+	"testing"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/provider"
+	"k8c.io/kubermatic/v2/pkg/provider/kubernetes"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func genClusterTemplate(name string, scope kubermaticv1.ClusterTemplateScope, projectID, owner string) *kubermaticv1.ClusterTemplate {
+	return &kubermaticv1.ClusterTemplate{
+		ObjectMeta: v1.ObjectMeta{
+			Name: name,
+		},
+		Spec: kubermaticv1.ClusterTemplateSpec{
+			HumanReadableName: name,
+			Scope:             scope,
+			ProjectID:         projectID,
+			Owner:             owner,
+		},
+	}
+}
+
+func clusterTemplateObjects() []ctrlruntimeclient.Object {
+	return []ctrlruntimeclient.Object{
+		genClusterTemplate("global", kubermaticv1.ClusterTemplateScopeGlobal, "", "admin@acme.com"),
+		genClusterTemplate("bob", kubermaticv1.ClusterTemplateScopeUser, "", "bob@acme.com"),
+		genClusterTemplate("john", kubermaticv1.ClusterTemplateScopeUser, "", "john@acme.com"),
+		genClusterTemplate("my-project", kubermaticv1.ClusterTemplateScopeProject, "my-project", "john@acme.com"),
+		genClusterTemplate("other-project", kubermaticv1.ClusterTemplateScopeProject, "other-project", "john@acme.com"),
+	}
+}
+
+func TestListClusterTemplates(t *testing.T) {
+	testcases := []struct {
+		name              string
+		userInfo          *provider.UserInfo
+		projectID         string
+		expectedTemplates []string
+	}{
+		{
+			name:              "scenario 1: the user gets the global templates, the templates of the project and its own templates",
+			userInfo:          &provider.UserInfo{Email: "bob@acme.com", Group: "editors-my-project"},
+			projectID:         "my-project",
+			expectedTemplates: []string{"bob", "global", "my-project"},
+		},
+		{
+			name:              "scenario 2: the admin gets the same templates as any other user",
+			userInfo:          &provider.UserInfo{Email: "admin@acme.com", IsAdmin: true},
+			projectID:         "other-project",
+			expectedTemplates: []string{"global", "other-project"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := fakectrlruntimeclient.
+				NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(clusterTemplateObjects()...).
+				Build()
+
+			templateProvider := kubernetes.NewClusterTemplateProvider(fakeClient)
+
+			templates, err := templateProvider.List(tc.userInfo, tc.projectID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			names := []string{}
+			for _, template := range templates {
+				names = append(names, template.Name)
+			}
+			if len(names) != len(tc.expectedTemplates) {
+				t.Fatalf("expected templates %v, got %v", tc.expectedTemplates, names)
+			}
+			for i := range names {
+				if names[i] != tc.expectedTemplates[i] {
+					t.Fatalf("expected templates %v, got %v", tc.expectedTemplates, names)
+				}
+			}
+		})
+	}
+}
+
+func TestDeleteClusterTemplate(t *testing.T) {
+	testcases := []struct {
+		name          string
+		userInfo      *provider.UserInfo
+		projectID     string
+		templateName  string
+		expectedError func(error) bool
+	}{
+		{
+			name:         "scenario 1: the admin can delete a global template",
+			userInfo:     &provider.UserInfo{Email: "admin@acme.com", IsAdmin: true},
+			projectID:    "my-project",
+			templateName: "global",
+		},
+		{
+			name:          "scenario 2: a project owner can not delete a global template",
+			userInfo:      &provider.UserInfo{Email: "bob@acme.com", Group: "owners-my-project"},
+			projectID:     "my-project",
+			templateName:  "global",
+			expectedError: kerrors.IsForbidden,
+		},
+		{
+			name:         "scenario 3: the owner can delete its user template",
+			userInfo:     &provider.UserInfo{Email: "bob@acme.com", Group: "viewers-my-project"},
+			projectID:    "my-project",
+			templateName: "bob",
+		},
+		{
+			name:          "scenario 4: the user templates of other users are not found",
+			userInfo:      &provider.UserInfo{Email: "bob@acme.com", Group: "owners-my-project"},
+			projectID:     "my-project",
+			templateName:  "john",
+			expectedError: kerrors.IsNotFound,
+		},
+		{
+			name:         "scenario 5: an editor can delete a template of the project",
+			userInfo:     &provider.UserInfo{Email: "bob@acme.com", Group: "editors-my-project"},
+			projectID:    "my-project",
+			templateName: "my-project",
+		},
+		{
+			name:          "scenario 6: a viewer can not delete a template of the project",
+			userInfo:      &provider.UserInfo{Email: "bob@acme.com", Group: "viewers-my-project"},
+			projectID:     "my-project",
+			templateName:  "my-project",
+			expectedError: kerrors.IsForbidden,
+		},
+		{
+			name:          "scenario 7: the templates of other projects are not found",
+			userInfo:      &provider.UserInfo{Email: "bob@acme.com", Group: "owners-my-project"},
+			projectID:     "my-project",
+			templateName:  "other-project",
+			expectedError: kerrors.IsNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := fakectrlruntimeclient.
+				NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(clusterTemplateObjects()...).
+				Build()
+
+			templateProvider := kubernetes.NewClusterTemplateProvider(fakeClient)
+
+			err := templateProvider.Delete(tc.userInfo, tc.projectID, tc.templateName)
+			if tc.expectedError == nil {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			if !tc.expectedError(err) {
+				t.Fatalf("unexpected error %v", err)
+			}
+		})
+	}
+}
@@ -72,6 +72,39 @@ func CreateOrUpdateCredentialSecretForCluster(ctx context.Context, seedClient ct
 	return nil
 }
 
+// CloudSpecContainsCredentials returns true if the cloud spec contains inline credentials or a reference to a
+// credentials secret. The fields are the ones CreateOrUpdateCredentialSecretForCluster moves into the secret.
+func CloudSpecContainsCredentials(cloud kubermaticv1.CloudSpec) bool {
+	switch {
+	case cloud.AWS != nil:
+		return cloud.AWS.CredentialsReference != nil || cloud.AWS.AccessKeyID != "" || cloud.AWS.SecretAccessKey != ""
+	case cloud.Azure != nil:
+		spec := cloud.Azure
+		return spec.CredentialsReference != nil || spec.TenantID != "" || spec.SubscriptionID != "" || spec.ClientID != "" || spec.ClientSecret != ""
+	case cloud.Digitalocean != nil:
+		return cloud.Digitalocean.CredentialsReference != nil || cloud.Digitalocean.Token != ""
+	case cloud.GCP != nil:
+		return cloud.GCP.CredentialsReference != nil || cloud.GCP.ServiceAccount != ""
+	case cloud.Hetzner != nil:
+		return cloud.Hetzner.CredentialsReference != nil || cloud.Hetzner.Token != ""
+	case cloud.Openstack != nil:
+		spec := cloud.Openstack
+		return spec.CredentialsReference != nil || spec.Username != "" || spec.Password != "" || spec.Tenant != "" || spec.TenantID != "" || spec.Domain != ""
+	case cloud.Packet != nil:
+		return cloud.Packet.CredentialsReference != nil || cloud.Packet.APIKey != "" || cloud.Packet.ProjectID != ""
+	case cloud.Kubevirt != nil:
+		return cloud.Kubevirt.CredentialsReference != nil || cloud.Kubevirt.Kubeconfig != ""
+	case cloud.VSphere != nil:
+		spec := cloud.VSphere
+		return spec.CredentialsReference != nil || spec.Username != "" || spec.Password != "" || spec.InfraManagementUser.Username != "" || spec.InfraManagementUser.Password != ""
+	case cloud.Alibaba != nil:
+		return cloud.Alibaba.CredentialsReference != nil || cloud.Alibaba.AccessKeyID != "" || cloud.Alibaba.AccessKeySecret != ""
+	case cloud.Anexia != nil:
+		return cloud.Anexia.CredentialsReference != nil || cloud.Anexia.Token != ""
+	}
+	return false
+}
+
 func ensureCredentialSecret(ctx context.Context, seedClient ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster, secretData map[string][]byte) (*providerconfig.GlobalSecretKeySelector, error) {
 	name := cluster.GetSecretName()
 
@@ -0,0 +1,149 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"sort"
+
+	kubermaticapiv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/provider"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NewGroupProjectBindingProvider returns a group project binding provider
+func NewGroupProjectBindingProvider(createMasterImpersonatedClient impersonationClient, clientPrivileged ctrlruntimeclient.Client) *GroupProjectBindingProvider {
+	return &GroupProjectBindingProvider{
+		createMasterImpersonatedClient: createMasterImpersonatedClient,
+		clientPrivileged:               clientPrivileged,
+	}
+}
+
+var _ provider.GroupProjectBindingProvider = &GroupProjectBindingProvider{}
+var _ provider.PrivilegedGroupProjectBindingProvider = &GroupProjectBindingProvider{}
+
+// GroupProjectBindingProvider binds OIDC groups with projects
+type GroupProjectBindingProvider struct {
+	// createMasterImpersonatedClient is used as a ground for impersonation
+	createMasterImpersonatedClient impersonationClient
+
+	// treat clientPrivileged as a privileged user and use wisely
+	clientPrivileged ctrlruntimeclient.Client
+}
+
+// Create creates a binding for the given group and the given project
+func (p *GroupProjectBindingProvider) Create(userInfo *provider.UserInfo, project *kubermaticapiv1.Project, group, role string) (*kubermaticapiv1.GroupProjectBinding, error) {
+	masterImpersonatedClient, err := createImpersonationClientWrapperFromUserInfo(userInfo, p.createMasterImpersonatedClient)
+	if err != nil {
+		return nil, err
+	}
+	binding := genGroupProjectBinding(project, group, role)
+	if err := masterImpersonatedClient.Create(context.Background(), binding); err != nil {
+		return nil, err
+	}
+	return binding, nil
+}
+
+// List gets all group bindings of the given project
+func (p *GroupProjectBindingProvider) List(userInfo *provider.UserInfo, project *kubermaticapiv1.Project) ([]*kubermaticapiv1.GroupProjectBinding, error) {
+	bindings, err := p.ListUnsecured(project)
+	if err != nil {
+		return nil, err
+	}
+
+	// Note:
+	// After we get the list of bindings we try to get at least one item using unprivileged account to see if the user have read access
+	if len(bindings) > 0 {
+		masterImpersonatedClient, err := createImpersonationClientWrapperFromUserInfo(userInfo, p.createMasterImpersonatedClient)
+		if err != nil {
+			return nil, err
+		}
+		if err := masterImpersonatedClient.Get(context.Background(), ctrlruntimeclient.ObjectKey{Name: bindings[0].Name}, &kubermaticapiv1.GroupProjectBinding{}); err != nil {
+			return nil, err
+		}
+	}
+	return bindings, nil
+}
+
+// Delete deletes the given binding
+func (p *GroupProjectBindingProvider) Delete(userInfo *provider.UserInfo, bindingName string) error {
+	masterImpersonatedClient, err := createImpersonationClientWrapperFromUserInfo(userInfo, p.createMasterImpersonatedClient)
+	if err != nil {
+		return err
+	}
+	return masterImpersonatedClient.Delete(context.Background(), &kubermaticapiv1.GroupProjectBinding{ObjectMeta: metav1.ObjectMeta{Name: bindingName}})
+}
+
+// CreateUnsecured creates a binding for the given group and the given project
+// This function is unsafe in a sense that it uses privileged account to create the resource
+func (p *GroupProjectBindingProvider) CreateUnsecured(project *kubermaticapiv1.Project, group, role string) (*kubermaticapiv1.GroupProjectBinding, error) {
+	binding := genGroupProjectBinding(project, group, role)
+	if err := p.clientPrivileged.Create(context.Background(), binding); err != nil {
+		return nil, err
+	}
+	return binding, nil
+}
+
+// ListUnsecured gets all group bindings of the given project
+// This function is unsafe in a sense that it uses privileged account to list the resources
+func (p *GroupProjectBindingProvider) ListUnsecured(project *kubermaticapiv1.Project) ([]*kubermaticapiv1.GroupProjectBinding, error) {
+	allBindings := &kubermaticapiv1.GroupProjectBindingList{}
+	if err := p.clientPrivileged.List(context.Background(), allBindings); err != nil {
+		return nil, err
+	}
+
+	bindings := []*kubermaticapiv1.GroupProjectBinding{}
+	for _, binding := range allBindings.Items {
+		if binding.Spec.ProjectID == project.Name {
+			bindings = append(bindings, binding.DeepCopy())
+		}
+	}
+	sort.Slice(bindings, func(i, j int) bool {
+		return bindings[i].Spec.Group < bindings[j].Spec.Group
+	})
+	return bindings, nil
+}
+
+// DeleteUnsecured deletes the given binding
+// This function is unsafe in a sense that it uses privileged account to delete the resource
+func (p *GroupProjectBindingProvider) DeleteUnsecured(bindingName string) error {
+	return p.clientPrivileged.Delete(context.Background(), &kubermaticapiv1.GroupProjectBinding{ObjectMeta: metav1.ObjectMeta{Name: bindingName}})
+}
+
+func genGroupProjectBinding(project *kubermaticapiv1.Project, group, role string) *kubermaticapiv1.GroupProjectBinding {
+	return &kubermaticapiv1.GroupProjectBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: kubermaticapiv1.SchemeGroupVersion.String(),
+					Kind:       kubermaticapiv1.ProjectKindName,
+					UID:        project.GetUID(),
+					Name:       project.Name,
+				},
+			},
+			Name: rand.String(10),
+		},
+		Spec: kubermaticapiv1.GroupProjectBindingSpec{
+			ProjectID: project.Name,
+			Group:     group,
+			Role:      role,
+		},
+	}
+}
@@ -19,6 +19,7 @@ package kubernetes
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"k8c.io/kubermatic/v2/pkg/controller/master-controller-manager/rbac"
@@ -30,6 +31,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/sets"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -159,41 +161,120 @@ func (p *ProjectMemberProvider) Update(userInfo *provider.UserInfo, binding *kub
 	return binding, nil
 }
 
-// MapUserToGroup maps the given user to a specific group of the given project
+// MapUserToGroup maps the given user to a specific group of the given project, either by a binding of the user
+// or by a binding of one of the OIDC groups of the user
 // This function is unsafe in a sense that it uses privileged account to list all members in the system
-func (p *ProjectMemberProvider) MapUserToGroup(userEmail string, projectID string) (string, error) {
+func (p *ProjectMemberProvider) MapUserToGroup(user *kubermaticapiv1.User, projectID string) (string, error) {
 	allMembers := &kubermaticapiv1.UserProjectBindingList{}
 	if err := p.clientPrivileged.List(context.Background(), allMembers); err != nil {
 		return "", err
 	}
 
 	for _, member := range allMembers.Items {
-		if strings.EqualFold(member.Spec.UserEmail, userEmail) && member.Spec.ProjectID == projectID {
+		if strings.EqualFold(member.Spec.UserEmail, user.Spec.Email) && member.Spec.ProjectID == projectID {
 			return member.Spec.Group, nil
 		}
 	}
 
-	return "", kerrors.NewForbidden(schema.GroupResource{}, projectID, fmt.Errorf("%q doesn't belong to the given project = %s", userEmail, projectID))
+	groupBindings, err := p.groupBindingsFor(user)
+	if err != nil {
+		return "", err
+	}
+	if binding, ok := groupBindings[projectID]; ok {
+		return rbac.GenerateActualGroupNameFor(projectID, binding.Spec.Role), nil
+	}
+
+	return "", kerrors.NewForbidden(schema.GroupResource{}, projectID, fmt.Errorf("%q doesn't belong to the given project = %s", user.Spec.Email, projectID))
 }
 
-// MappingsFor returns the list of projects (bindings) for the given user
+// MappingsFor returns the list of projects (bindings) for the given user, the projects the user is a member of
+// by one of its OIDC groups are returned as bindings named after the GroupProjectBinding
 // This function is unsafe in a sense that it uses privileged account to list all members in the system
-func (p *ProjectMemberProvider) MappingsFor(userEmail string) ([]*kubermaticapiv1.UserProjectBinding, error) {
+func (p *ProjectMemberProvider) MappingsFor(user *kubermaticapiv1.User) ([]*kubermaticapiv1.UserProjectBinding, error) {
 	allMemberMappings := &kubermaticapiv1.UserProjectBindingList{}
 	if err := p.clientPrivileged.List(context.Background(), allMemberMappings); err != nil {
 		return nil, err
 	}
 
 	memberMappings := []*kubermaticapiv1.UserProjectBinding{}
+	boundProjects := sets.NewString()
 	for _, memberMapping := range allMemberMappings.Items {
-		if strings.EqualFold(memberMapping.Spec.UserEmail, userEmail) {
+		if strings.EqualFold(memberMapping.Spec.UserEmail, user.Spec.Email) {
 			memberMappings = append(memberMappings, memberMapping.DeepCopy())
+			boundProjects.Insert(memberMapping.Spec.ProjectID)
 		}
 	}
 
+	groupBindings, err := p.groupBindingsFor(user)
+	if err != nil {
+		return nil, err
+	}
+	projectIDs := make([]string, 0, len(groupBindings))
+	for projectID := range groupBindings {
+		projectIDs = append(projectIDs, projectID)
+	}
+	sort.Strings(projectIDs)
+	for _, projectID := range projectIDs {
+		if boundProjects.Has(projectID) {
+			continue
+		}
+		binding := groupBindings[projectID]
+		memberMappings = append(memberMappings, &kubermaticapiv1.UserProjectBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: binding.Name},
+			Spec: kubermaticapiv1.UserProjectBindingSpec{
+				UserEmail: user.Spec.Email,
+				ProjectID: projectID,
+				Group:     rbac.GenerateActualGroupNameFor(projectID, binding.Spec.Role),
+			},
+		})
+	}
+
 	return memberMappings, nil
 }
 
+// groupBindingsFor returns the GroupProjectBindings of the OIDC groups of the given user by project,
+// if several groups of the user are bound to the same project the binding with the most privileged role is returned
+func (p *ProjectMemberProvider) groupBindingsFor(user *kubermaticapiv1.User) (map[string]kubermaticapiv1.GroupProjectBinding, error) {
+	if len(user.Spec.Groups) == 0 {
+		return nil, nil
+	}
+
+	allGroupBindings := &kubermaticapiv1.GroupProjectBindingList{}
+	if err := p.clientPrivileged.List(context.Background(), allGroupBindings); err != nil {
+		return nil, err
+	}
+
+	userGroups := sets.NewString(user.Spec.Groups...)
+	groupBindings := map[string]kubermaticapiv1.GroupProjectBinding{}
+	for _, binding := range allGroupBindings.Items {
+		if !userGroups.Has(binding.Spec.Group) {
+			continue
+		}
+		if existing, ok := groupBindings[binding.Spec.ProjectID]; ok && !morePrivilegedGroupBinding(binding, existing) {
+			continue
+		}
+		groupBindings[binding.Spec.ProjectID] = binding
+	}
+	return groupBindings, nil
+}
+
+// morePrivilegedGroupBinding returns true if the role of the first binding is more privileged than the role of the second one.
+// The built-in roles are ordered by their privileges and are more privileged than custom roles, ties are broken by the names of the bindings.
+func morePrivilegedGroupBinding(binding, other kubermaticapiv1.GroupProjectBinding) bool {
+	rank := func(role string) int {
+		for i, prefix := range rbac.AllGroupsPrefixes {
+			if role == prefix {
+				return i
+			}
+		}
+		return len(rbac.AllGroupsPrefixes)
+	}
+	if rank(binding.Spec.Role) != rank(other.Spec.Role) {
+		return rank(binding.Spec.Role) < rank(other.Spec.Role)
+	}
+	return binding.Name < other.Name
+}
+
 // CreateUnsecured creates a binding for the given member and the given project
 // This function is unsafe in a sense that it uses privileged account to create the resource
 func (p *ProjectMemberProvider) CreateUnsecured(project *kubermaticapiv1.Project, memberEmail, group string) (*kubermaticapiv1.UserProjectBinding, error) {
@@ -26,6 +26,7 @@ import (
 	"k8c.io/kubermatic/v2/pkg/provider"
 	"k8c.io/kubermatic/v2/pkg/provider/kubernetes"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	restclient "k8s.io/client-go/rest"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -162,3 +163,104 @@ func TestListBinding(t *testing.T) {
 		})
 	}
 }
+
+func TestMapUserToGroupByOIDCGroups(t *testing.T) {
+	genGroupBinding := func(name, group, projectID, role string) *kubermaticv1.GroupProjectBinding {
+		return &kubermaticv1.GroupProjectBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       kubermaticv1.GroupProjectBindingSpec{Group: group, ProjectID: projectID, Role: role},
+		}
+	}
+
+	testcases := []struct {
+		name              string
+		userGroups        []string
+		existingObjects   []ctrlruntimeclient.Object
+		projectID         string
+		expectedGroup     string
+		expectedError     bool
+		expectedProjectID []string
+	}{
+		{
+			name:       "scenario 1: the user is a member by its group",
+			userGroups: []string{"developers"},
+			existingObjects: []ctrlruntimeclient.Object{
+				genGroupBinding("b1", "developers", "my-first-project-ID", "editors"),
+				genGroupBinding("b2", "developers", "other-project-ID", "viewers"),
+			},
+			projectID:         "my-first-project-ID",
+			expectedGroup:     "editors-my-first-project-ID",
+			expectedProjectID: []string{"my-first-project-ID", "other-project-ID"},
+		},
+		{
+			name:       "scenario 2: the most privileged role of the groups of the user applies",
+			userGroups: []string{"auditors", "developers", "operators"},
+			existingObjects: []ctrlruntimeclient.Object{
+				genGroupBinding("b1", "auditors", "my-first-project-ID", "viewers"),
+				genGroupBinding("b2", "developers", "my-first-project-ID", "editors"),
+				genGroupBinding("b3", "operators", "my-first-project-ID", "scalers"),
+			},
+			projectID:         "my-first-project-ID",
+			expectedGroup:     "editors-my-first-project-ID",
+			expectedProjectID: []string{"my-first-project-ID"},
+		},
+		{
+			name:       "scenario 3: the binding of the user takes precedence over its groups",
+			userGroups: []string{"developers"},
+			existingObjects: []ctrlruntimeclient.Object{
+				genGroupBinding("b1", "developers", "my-first-project-ID", "editors"),
+				createBinding("userBinding", "my-first-project-ID", "john@acme.com", "viewers"),
+			},
+			projectID:         "my-first-project-ID",
+			expectedGroup:     "viewers-my-first-project-ID",
+			expectedProjectID: []string{"my-first-project-ID"},
+		},
+		{
+			name:       "scenario 4: the groups of the token are not bound to the project",
+			userGroups: []string{"strangers"},
+			existingObjects: []ctrlruntimeclient.Object{
+				genGroupBinding("b1", "developers", "my-first-project-ID", "editors"),
+			},
+			projectID:     "my-first-project-ID",
+			expectedError: true,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := fakectrlruntimeclient.
+				NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(tc.existingObjects...).
+				Build()
+			user := createAuthenitactedUser()
+			user.Spec.Groups = tc.userGroups
+
+			target := kubernetes.NewProjectMemberProvider(nil, fakeClient, kubernetes.IsProjectServiceAccount)
+			group, err := target.MapUserToGroup(user, tc.projectID)
+			if tc.expectedError {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if group != tc.expectedGroup {
+				t.Fatalf("expected group %s, got %s", tc.expectedGroup, group)
+			}
+
+			mappings, err := target.MappingsFor(user)
+			if err != nil {
+				t.Fatal(err)
+			}
+			projectIDs := []string{}
+			for _, mapping := range mappings {
+				projectIDs = append(projectIDs, mapping.Spec.ProjectID)
+			}
+			if diff := deep.Equal(projectIDs, tc.expectedProjectID); diff != nil {
+				t.Fatalf("unexpected projects of the user: %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+
+	"k8c.io/kubermatic/v2/pkg/controller/master-controller-manager/rbac"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/provider"
+
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ProjectRoleProvider reads the custom project roles
+type ProjectRoleProvider struct {
+	client ctrlruntimeclient.Client
+}
+
+var _ provider.ProjectRoleProvider = &ProjectRoleProvider{}
+
+// NewProjectRoleProvider returns a provider reading the custom project roles with the given privileged client
+func NewProjectRoleProvider(client ctrlruntimeclient.Client) *ProjectRoleProvider {
+	return &ProjectRoleProvider{client: client}
+}
+
+// Get returns the custom project role with the given name
+//
+// Note that this function:
+// is unsafe in a sense that it uses privileged account to get the resource
+func (p *ProjectRoleProvider) Get(name string) (*kubermaticv1.ProjectRole, error) {
+	role := &kubermaticv1.ProjectRole{}
+	if err := p.client.Get(context.Background(), ctrlruntimeclient.ObjectKey{Name: name}, role); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// UserClusterGroupPrefix returns the group prefix the members of the given project group are impersonated as in the user clusters.
+// The RBAC of the user clusters only knows the built-in groups, thus the members of custom project roles are impersonated
+// as editors if they can change machine deployments and as viewers otherwise. The API enforces their finer-grained verbs.
+func (p *ProjectRoleProvider) UserClusterGroupPrefix(groupName string) string {
+	groupPrefix := rbac.ExtractGroupPrefix(groupName)
+	if rbac.IsBuiltinGroupPrefix(groupPrefix) {
+		return groupPrefix
+	}
+
+	role, err := p.Get(groupPrefix)
+	if err != nil {
+		return rbac.ViewerGroupNamePrefix
+	}
+	for _, verb := range []string{kubermaticv1.ProjectRoleVerbCreate, kubermaticv1.ProjectRoleVerbUpdate, kubermaticv1.ProjectRoleVerbDelete} {
+		if role.Allows(kubermaticv1.ProjectRoleResourceMachineDeployments, verb) {
+			return rbac.EditorGroupNamePrefix
+		}
+	}
+	return rbac.ViewerGroupNamePrefix
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/provider"
@@ -35,6 +36,13 @@ import (
 const (
 	labelTokenName = "token"
 	tokenPrefix    = "sa-token-"
+
+	// TokenLastUsedAnnotation is the annotation of the token secret containing the time the
+	// token was last used
+	TokenLastUsedAnnotation = "kubermatic.io/last-used"
+	// tokenLastUsedInterval is the precision of the recorded last used time, so that
+	// the secret is not updated on every request
+	tokenLastUsedInterval = time.Minute
 )
 
 // NewServiceAccountProvider returns a service account provider
@@ -380,6 +388,44 @@ func (p *ServiceAccountTokenProvider) DeleteUnsecured(name string) error {
 	return p.kubernetesClientPrivileged.Delete(context.Background(), secret)
 }
 
+// UpdateLastUsedUnsecured records the time the token was last used. The time is only updated
+// if the recorded time is older than a minute.
+//
+// Note that this function:
+// is unsafe in a sense that it uses privileged account to update the resource
+func (p *ServiceAccountTokenProvider) UpdateLastUsedUnsecured(secret *v1.Secret, lastUsed time.Time) error {
+	if secret == nil {
+		return kerrors.NewBadRequest("secret cannot be empty")
+	}
+	if previous, ok := TokenLastUsed(secret); ok && lastUsed.Sub(previous) < tokenLastUsedInterval {
+		return nil
+	}
+
+	oldSecret := secret.DeepCopy()
+	oldSecret.Name = addTokenPrefix(oldSecret.Name)
+	oldSecret.Namespace = resources.KubermaticNamespace
+	newSecret := oldSecret.DeepCopy()
+	if newSecret.Annotations == nil {
+		newSecret.Annotations = map[string]string{}
+	}
+	newSecret.Annotations[TokenLastUsedAnnotation] = lastUsed.UTC().Format(time.RFC3339)
+
+	return p.kubernetesClientPrivileged.Patch(context.Background(), newSecret, ctrlruntimeclient.MergeFrom(oldSecret))
+}
+
+// TokenLastUsed returns the time the token was last used and false if it was never used
+func TokenLastUsed(secret *v1.Secret) (time.Time, bool) {
+	value, ok := secret.Annotations[TokenLastUsedAnnotation]
+	if !ok {
+		return time.Time{}, false
+	}
+	lastUsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return lastUsed, true
+}
+
 // removeTokenPrefix removes "sa-token-" from a token's ID
 // for example given "sa-token-gmtzqz692d" it returns "gmtzqz692d"
 func removeTokenPrefix(id string) string {
@@ -20,6 +20,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/handler/test"
@@ -68,7 +69,7 @@ func TestCreateToken(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			fakeClient := fakectrlruntimeclient.NewClientBuilder().WithScheme(scheme.Scheme).Build()
 			tokenGenerator := &fakeJWTTokenGenerator{}
-			token, err := tokenGenerator.Generate(serviceaccount.Claims(tc.saEmail, tc.projectToSync, tc.tokenID))
+			token, err := tokenGenerator.Generate(serviceaccount.Claims(tc.saEmail, tc.projectToSync, tc.tokenID, 0))
 			if err != nil {
 				t.Fatalf("unable to generate token, err = %v", err)
 			}
@@ -433,3 +434,76 @@ func rmTokenPrefix(token *v1.Secret) *v1.Secret {
 	token.Name = strings.TrimPrefix(token.Name, "sa-token-")
 	return token
 }
+
+func TestUpdateTokenLastUsed(t *testing.T) {
+	now := time.Date(2021, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	// test data
+	testcases := []struct {
+		name             string
+		previousLastUsed string
+		lastUsed         time.Time
+		expectedLastUsed time.Time
+	}{
+		{
+			name:             "scenario 1, record the first use of the token",
+			lastUsed:         now,
+			expectedLastUsed: now,
+		},
+		{
+			name:             "scenario 2, record a later use of the token",
+			previousLastUsed: now.Add(-time.Hour).Format(time.RFC3339),
+			lastUsed:         now,
+			expectedLastUsed: now,
+		},
+		{
+			name:             "scenario 3, skip recording a use within a minute",
+			previousLastUsed: now.Add(-30 * time.Second).Format(time.RFC3339),
+			lastUsed:         now,
+			expectedLastUsed: now.Add(-30 * time.Second),
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			secret := test.GenDefaultSaToken("my-first-project-ID", "1", "test-token-1", "1")
+			if tc.previousLastUsed != "" {
+				secret.Annotations = map[string]string{kubernetes.TokenLastUsedAnnotation: tc.previousLastUsed}
+			}
+
+			fakeClient := fakectrlruntimeclient.
+				NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(secret).
+				Build()
+
+			fakeImpersonationClient := func(impCfg restclient.ImpersonationConfig) (ctrlruntimeclient.Client, error) {
+				return fakeClient, nil
+			}
+			// act
+			target, err := kubernetes.NewServiceAccountTokenProvider(fakeImpersonationClient, fakeClient)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			token, err := target.GetUnsecured(secret.Name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := target.UpdateLastUsedUnsecured(token, tc.lastUsed); err != nil {
+				t.Fatal(err)
+			}
+
+			updated, err := target.GetUnsecured(secret.Name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lastUsed, ok := kubernetes.TokenLastUsed(updated)
+			if !ok {
+				t.Fatal("expected the last use of the token to be recorded")
+			}
+			if !lastUsed.Equal(tc.expectedLastUsed) {
+				t.Fatalf("expected last use %v, got %v", tc.expectedLastUsed, lastUsed)
+			}
+		})
+	}
+}
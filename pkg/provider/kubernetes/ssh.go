@@ -26,6 +26,8 @@ import (
 
 	kubermaticapiv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/provider"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	kubermaticssh "k8c.io/kubermatic/v2/pkg/ssh"
 	"k8c.io/kubermatic/v2/pkg/uuid"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -62,7 +64,7 @@ type SSHKeyProvider struct {
 }
 
 // Create creates a ssh key that will belong to the given project
-func (p *SSHKeyProvider) Create(userInfo *provider.UserInfo, project *kubermaticapiv1.Project, keyName, pubKey string) (*kubermaticapiv1.UserSSHKey, error) {
+func (p *SSHKeyProvider) Create(userInfo *provider.UserInfo, project *kubermaticapiv1.Project, keyName, pubKey, user string, expiresAt *metav1.Time) (*kubermaticapiv1.UserSSHKey, error) {
 	if keyName == "" {
 		return nil, fmt.Errorf("the ssh key name is missing but required")
 	}
@@ -73,7 +75,7 @@ func (p *SSHKeyProvider) Create(userInfo *provider.UserInfo, project *kubermatic
 		return nil, errors.New("a userInfo is missing but required")
 	}
 
-	sshKey, err := genUserSSHKey(project, keyName, pubKey)
+	sshKey, err := genUserSSHKey(project, keyName, pubKey, user, expiresAt)
 	if err != nil {
 		return nil, err
 	}
@@ -90,7 +92,7 @@ func (p *SSHKeyProvider) Create(userInfo *provider.UserInfo, project *kubermatic
 
 // Create creates a ssh key that belongs to the given project
 // This function is unsafe in a sense that it uses privileged account to create the ssh key
-func (p *PrivilegedSSHKeyProvider) CreateUnsecured(project *kubermaticapiv1.Project, keyName, pubKey string) (*kubermaticapiv1.UserSSHKey, error) {
+func (p *PrivilegedSSHKeyProvider) CreateUnsecured(project *kubermaticapiv1.Project, keyName, pubKey, user string, expiresAt *metav1.Time) (*kubermaticapiv1.UserSSHKey, error) {
 	if keyName == "" {
 		return nil, fmt.Errorf("the ssh key name is missing but required")
 	}
@@ -98,7 +100,7 @@ func (p *PrivilegedSSHKeyProvider) CreateUnsecured(project *kubermaticapiv1.Proj
 		return nil, fmt.Errorf("the ssh public part of the key is missing but required")
 	}
 
-	sshKey, err := genUserSSHKey(project, keyName, pubKey)
+	sshKey, err := genUserSSHKey(project, keyName, pubKey, user, expiresAt)
 	if err != nil {
 		return nil, err
 	}
@@ -109,7 +111,7 @@ func (p *PrivilegedSSHKeyProvider) CreateUnsecured(project *kubermaticapiv1.Proj
 	return sshKey, nil
 }
 
-func genUserSSHKey(project *kubermaticapiv1.Project, keyName, pubKey string) (*kubermaticapiv1.UserSSHKey, error) {
+func genUserSSHKey(project *kubermaticapiv1.Project, keyName, pubKey, user string, expiresAt *metav1.Time) (*kubermaticapiv1.UserSSHKey, error) {
 	pubKeyParsed, _, _, _, err := ssh.ParseAuthorizedKey([]byte(pubKey))
 	if err != nil {
 		return nil, fmt.Errorf("the provided ssh key is invalid due to = %v", err)
@@ -134,6 +136,8 @@ func genUserSSHKey(project *kubermaticapiv1.Project, keyName, pubKey string) (*k
 			Fingerprint: sshKeyHash,
 			Name:        keyName,
 			Clusters:    []string{},
+			User:        user,
+			ExpiresAt:   expiresAt,
 		},
 	}, nil
 }
@@ -255,3 +259,9 @@ func (p *PrivilegedSSHKeyProvider) GetUnsecured(keyName string) (*kubermaticapiv
 	}
 	return userSSHKey, nil
 }
+
+// GetCertificateAuthorityUnsecured returns the ssh certificate authority of the project, it is created if it doesn't exist yet
+// This function is unsafe in a sense that it uses privileged account to get the certificate authority
+func (p *PrivilegedSSHKeyProvider) GetCertificateAuthorityUnsecured(project *kubermaticapiv1.Project) (*kubermaticssh.CertificateAuthority, error) {
+	return kubermaticssh.EnsureCertificateAuthority(context.Background(), p.clientPrivileged, resources.KubermaticNamespace, project)
+}
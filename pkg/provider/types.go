@@ -20,13 +20,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	providerconfig "github.com/kubermatic/machine-controller/pkg/providerconfig/types"
 	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	ksemver "k8c.io/kubermatic/v2/pkg/semver"
+	kubermaticssh "k8c.io/kubermatic/v2/pkg/ssh"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -249,7 +252,7 @@ type SSHKeyProvider interface {
 	List(project *kubermaticv1.Project, options *SSHKeyListOptions) ([]*kubermaticv1.UserSSHKey, error)
 
 	// Create creates a ssh key that belongs to the given project
-	Create(userInfo *UserInfo, project *kubermaticv1.Project, keyName, pubKey string) (*kubermaticv1.UserSSHKey, error)
+	Create(userInfo *UserInfo, project *kubermaticv1.Project, keyName, pubKey, user string, expiresAt *metav1.Time) (*kubermaticv1.UserSSHKey, error)
 
 	// Delete deletes the given ssh key
 	Delete(userInfo *UserInfo, keyName string) error
@@ -273,11 +276,15 @@ type PrivilegedSSHKeyProvider interface {
 
 	// Create creates a ssh key that belongs to the given project
 	// This function is unsafe in a sense that it uses privileged account to create the ssh key
-	CreateUnsecured(project *kubermaticv1.Project, keyName, pubKey string) (*kubermaticv1.UserSSHKey, error)
+	CreateUnsecured(project *kubermaticv1.Project, keyName, pubKey, user string, expiresAt *metav1.Time) (*kubermaticv1.UserSSHKey, error)
 
 	// Delete deletes the given ssh key
 	// This function is unsafe in a sense that it uses privileged account to delete the ssh key
 	DeleteUnsecured(keyName string) error
+
+	// GetCertificateAuthorityUnsecured returns the ssh certificate authority of the project, it is created if it doesn't exist yet
+	// This function is unsafe in a sense that it uses privileged account to get the certificate authority
+	GetCertificateAuthorityUnsecured(project *kubermaticv1.Project) (*kubermaticssh.CertificateAuthority, error)
 }
 
 // UserProvider declares the set of methods for interacting with kubermatic users
@@ -381,16 +388,64 @@ type PrivilegedProjectMemberProvider interface {
 	UpdateUnsecured(binding *kubermaticv1.UserProjectBinding) (*kubermaticv1.UserProjectBinding, error)
 }
 
+// GroupProjectBindingProvider binds OIDC groups with projects
+type GroupProjectBindingProvider interface {
+	// Create creates a binding for the given group and the given project
+	Create(userInfo *UserInfo, project *kubermaticv1.Project, group, role string) (*kubermaticv1.GroupProjectBinding, error)
+
+	// List gets all group bindings of the given project
+	List(userInfo *UserInfo, project *kubermaticv1.Project) ([]*kubermaticv1.GroupProjectBinding, error)
+
+	// Delete deletes the given binding
+	Delete(userInfo *UserInfo, bindingName string) error
+}
+
+// PrivilegedGroupProjectBindingProvider binds OIDC groups with projects and uses privileged account for it
+type PrivilegedGroupProjectBindingProvider interface {
+	// CreateUnsecured creates a binding for the given group and the given project
+	// This function is unsafe in a sense that it uses privileged account to create the resource
+	CreateUnsecured(project *kubermaticv1.Project, group, role string) (*kubermaticv1.GroupProjectBinding, error)
+
+	// ListUnsecured gets all group bindings of the given project
+	// This function is unsafe in a sense that it uses privileged account to list the resources
+	ListUnsecured(project *kubermaticv1.Project) ([]*kubermaticv1.GroupProjectBinding, error)
+
+	// DeleteUnsecured deletes the given binding
+	// This function is unsafe in a sense that it uses privileged account to delete the resource
+	DeleteUnsecured(bindingName string) error
+}
+
+// ClusterTemplateProvider declares the set of methods for interacting with cluster templates.
+// Global templates are managed by the admins, user templates by their owner and project templates by
+// the owners and editors of the project.
+type ClusterTemplateProvider interface {
+	// New creates the given template if the user is allowed to manage templates of its scope
+	New(userInfo *UserInfo, template *kubermaticv1.ClusterTemplate) (*kubermaticv1.ClusterTemplate, error)
+
+	// Get returns the given template if the user can use it in the given project
+	Get(userInfo *UserInfo, projectID, templateName string) (*kubermaticv1.ClusterTemplate, error)
+
+	// List gets the global templates, the templates of the given project and the templates of the user
+	List(userInfo *UserInfo, projectID string) ([]*kubermaticv1.ClusterTemplate, error)
+
+	// Update updates the given template if the user is allowed to manage it
+	Update(userInfo *UserInfo, projectID string, template *kubermaticv1.ClusterTemplate) (*kubermaticv1.ClusterTemplate, error)
+
+	// Delete deletes the given template if the user is allowed to manage it
+	Delete(userInfo *UserInfo, projectID, templateName string) error
+}
+
 // ProjectMemberMapper exposes method that knows how to map
 // a user to a group for a project
 type ProjectMemberMapper interface {
-	// MapUserToGroup maps the given user to a specific group of the given project
+	// MapUserToGroup maps the given user to a specific group of the given project,
+	// either by a binding of the user or by a binding of one of the OIDC groups of the user
 	// This function is unsafe in a sense that it uses privileged account to list all members in the system
-	MapUserToGroup(userEmail string, projectID string) (string, error)
+	MapUserToGroup(user *kubermaticv1.User, projectID string) (string, error)
 
-	// MappingsFor returns the list of projects (bindings) for the given user
+	// MappingsFor returns the list of projects (bindings) for the given user, including the projects of its OIDC groups
 	// This function is unsafe in a sense that it uses privileged account to list all members in the system
-	MappingsFor(userEmail string) ([]*kubermaticv1.UserProjectBinding, error)
+	MappingsFor(user *kubermaticv1.User) ([]*kubermaticv1.UserProjectBinding, error)
 }
 
 // ClusterCloudProviderName returns the provider name for the given CloudSpec.
@@ -631,6 +686,12 @@ type PrivilegedServiceAccountTokenProvider interface {
 	// Note that this function:
 	// is unsafe in a sense that it uses privileged account to delete the resource
 	DeleteUnsecured(name string) error
+
+	// UpdateLastUsedUnsecured records the time the token was last used
+	//
+	// Note that this function:
+	// is unsafe in a sense that it uses privileged account to update the resource
+	UpdateLastUsedUnsecured(secret *corev1.Secret, lastUsed time.Time) error
 }
 
 // EventRecorderProvider allows to record events for objects that can be read using K8S API.
@@ -643,7 +704,7 @@ type EventRecorderProvider interface {
 // AddonProvider declares the set of methods for interacting with addons
 type AddonProvider interface {
 	// New creates a new addon in the given cluster
-	New(userInfo *UserInfo, cluster *kubermaticv1.Cluster, addonName string, variables *runtime.RawExtension, labels map[string]string) (*kubermaticv1.Addon, error)
+	New(userInfo *UserInfo, cluster *kubermaticv1.Cluster, addonName, version string, variables *runtime.RawExtension, labels map[string]string) (*kubermaticv1.Addon, error)
 
 	// List gets all addons that belong to the given cluster
 	// If you want to filter the result please take a look at ClusterListOptions
@@ -671,7 +732,7 @@ type PrivilegedAddonProvider interface {
 	//
 	// Note that this function:
 	// is unsafe in a sense that it uses privileged account to create the resource
-	NewUnsecured(cluster *kubermaticv1.Cluster, addonName string, variables *runtime.RawExtension, labels map[string]string) (*kubermaticv1.Addon, error)
+	NewUnsecured(cluster *kubermaticv1.Cluster, addonName, version string, variables *runtime.RawExtension, labels map[string]string) (*kubermaticv1.Addon, error)
 
 	// GetUnsecured returns the given addon
 	//
@@ -875,3 +936,16 @@ type PrivilegedAlertmanagerProvider interface {
 	// is unsafe in a sense that it uses privileged account to reset the resource
 	ResetUnsecured(cluster *kubermaticv1.Cluster) error
 }
+
+// ProjectRoleProvider declares the set of methods for reading the custom project roles
+type ProjectRoleProvider interface {
+	// Get returns the custom project role with the given name
+	//
+	// Note that this function:
+	// is unsafe in a sense that it uses privileged account to get the resource
+	Get(name string) (*kubermaticv1.ProjectRole, error)
+
+	// UserClusterGroupPrefix returns the group prefix the members of the given project group
+	// are impersonated as in the user clusters
+	UserClusterGroupPrefix(groupName string) string
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+
+	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/machine"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NodeDeploymentUsage returns the resources of all replicas of the given node deployment.
+func NodeDeploymentUsage(nd *apiv1.NodeDeployment) (kubermaticv1.ResourceUsage, error) {
+	return nodesUsage(int64(nd.Spec.Replicas), &nd.Spec.Template.Cloud)
+}
+
+// MachineDeploymentUsage returns the resources of all replicas of the given machine deployment.
+func MachineDeploymentUsage(md *clusterv1alpha1.MachineDeployment) (kubermaticv1.ResourceUsage, error) {
+	var replicas int64
+	if md.Spec.Replicas != nil {
+		replicas = int64(*md.Spec.Replicas)
+	}
+	return MachineDeploymentReplicasUsage(md, replicas)
+}
+
+// MachineDeploymentReplicasUsage returns the resources of the given number of replicas of the machine deployment.
+func MachineDeploymentReplicasUsage(md *clusterv1alpha1.MachineDeployment, replicas int64) (kubermaticv1.ResourceUsage, error) {
+	cloudSpec, err := machine.GetAPIV2NodeCloudSpec(md.Spec.Template.Spec)
+	if err != nil {
+		return kubermaticv1.ResourceUsage{}, err
+	}
+	return nodesUsage(replicas, cloudSpec)
+}
+
+func nodesUsage(replicas int64, cloudSpec *apiv1.NodeCloudSpec) (kubermaticv1.ResourceUsage, error) {
+	node, err := machine.GetNodeResources(cloudSpec)
+	if err != nil {
+		return kubermaticv1.ResourceUsage{}, err
+	}
+
+	usage := kubermaticv1.ResourceUsage{Nodes: replicas}
+	for i := int64(0); i < replicas; i++ {
+		usage.CPU.Add(node.CPU)
+		usage.Memory.Add(node.Memory)
+		usage.Storage.Add(node.Storage)
+	}
+	return usage, nil
+}
+
+// InitialNodeDeployment returns the initial node deployment requested for the cluster, it is nil once the
+// node deployment has been created in the user cluster or if none was requested.
+func InitialNodeDeployment(cluster *kubermaticv1.Cluster) (*apiv1.NodeDeployment, error) {
+	request := cluster.Annotations[apiv1.InitialMachineDeploymentRequestAnnotation]
+	if request == "" {
+		return nil, nil
+	}
+
+	nd := &apiv1.NodeDeployment{}
+	if err := json.Unmarshal([]byte(request), nd); err != nil {
+		return nil, fmt.Errorf("cannot parse initial machine deployment request: %v", err)
+	}
+	return nd, nil
+}
+
+// ClusterUsage returns the resources used by the given cluster. The machine deployments are listed with the
+// client of the user cluster, if it's nil only the initial node deployment request of the cluster is accounted,
+// which is all there is for clusters which are not running yet.
+func ClusterUsage(ctx context.Context, cluster *kubermaticv1.Cluster, userClusterClient ctrlruntimeclient.Client) (kubermaticv1.ResourceUsage, error) {
+	usage := kubermaticv1.ResourceUsage{Clusters: 1}
+
+	initialNodeDeployment, err := InitialNodeDeployment(cluster)
+	if err != nil {
+		return kubermaticv1.ResourceUsage{}, err
+	}
+
+	if userClusterClient != nil {
+		machineDeployments := &clusterv1alpha1.MachineDeploymentList{}
+		if err := userClusterClient.List(ctx, machineDeployments, ctrlruntimeclient.InNamespace(metav1.NamespaceSystem)); err != nil {
+			return kubermaticv1.ResourceUsage{}, fmt.Errorf("failed to list machine deployments of cluster %s: %v", cluster.Name, err)
+		}
+
+		for i := range machineDeployments.Items {
+			md := &machineDeployments.Items[i]
+			// the initial request is about to be removed from the cluster
+			if initialNodeDeployment != nil && initialNodeDeployment.Name == md.Name {
+				initialNodeDeployment = nil
+			}
+
+			mdUsage, err := MachineDeploymentUsage(md)
+			if err != nil {
+				return kubermaticv1.ResourceUsage{}, fmt.Errorf("failed to get the resources of machine deployment %s of cluster %s: %v", md.Name, cluster.Name, err)
+			}
+			usage.Add(mdUsage)
+		}
+	}
+
+	if initialNodeDeployment != nil {
+		ndUsage, err := NodeDeploymentUsage(initialNodeDeployment)
+		if err != nil {
+			return kubermaticv1.ResourceUsage{}, fmt.Errorf("failed to get the resources of the initial machine deployment of cluster %s: %v", cluster.Name, err)
+		}
+		usage.Add(ndUsage)
+	}
+
+	return usage, nil
+}
+
+// CachedClusterUsage returns the resources used by the given cluster as recorded in its status by the user cluster
+// controller manager. Until the usage has been recorded, the initial node deployment request is accounted.
+func CachedClusterUsage(cluster *kubermaticv1.Cluster) (kubermaticv1.ResourceUsage, error) {
+	if cluster.Status.ResourceUsage != nil {
+		return *cluster.Status.ResourceUsage.DeepCopy(), nil
+	}
+	return ClusterUsage(context.Background(), cluster, nil)
+}
+
+// ProjectUsage returns the resources used by the clusters of a project according to its status. It is empty
+// until the master controller manager aggregated the usage of the clusters.
+func ProjectUsage(project *kubermaticv1.Project) kubermaticv1.ResourceUsage {
+	if project.Status.ResourceUsage == nil {
+		return kubermaticv1.ResourceUsage{}
+	}
+	return *project.Status.ResourceUsage.DeepCopy()
+}
+
+// AdmissionUsage returns the usage of a project to admit new clusters against. The status of the project is only
+// aggregated asynchronously, so the given clusters of the project, listed at admission time, are accounted as well
+// and the higher of both usages counts for every resource. This way clusters created in a row are not all admitted
+// against the same outdated status.
+func AdmissionUsage(project *kubermaticv1.Project, clusters []kubermaticv1.Cluster) (kubermaticv1.ResourceUsage, error) {
+	listed := kubermaticv1.ResourceUsage{}
+	for i := range clusters {
+		clusterUsage, err := CachedClusterUsage(&clusters[i])
+		if err != nil {
+			return kubermaticv1.ResourceUsage{}, fmt.Errorf("failed to get the resources of cluster %s: %v", clusters[i].Name, err)
+		}
+		listed.Add(clusterUsage)
+	}
+
+	usage := ProjectUsage(project)
+	if listed.Clusters > usage.Clusters {
+		usage.Clusters = listed.Clusters
+	}
+	if listed.Nodes > usage.Nodes {
+		usage.Nodes = listed.Nodes
+	}
+	if listed.CPU.Cmp(usage.CPU) > 0 {
+		usage.CPU = listed.CPU
+	}
+	if listed.Memory.Cmp(usage.Memory) > 0 {
+		usage.Memory = listed.Memory
+	}
+	if listed.Storage.Cmp(usage.Storage) > 0 {
+		usage.Storage = listed.Storage
+	}
+	return usage, nil
+}
+
+// Check returns an error naming every limit of the quota which the requested resources would exceed if they
+// were added to the current usage. Resources which are not requested are not checked, so that a project which
+// is over its quota, for example because the quota has been lowered, can always shrink.
+func Check(quota *kubermaticv1.ProjectResourceQuota, usage, requested kubermaticv1.ResourceUsage) error {
+	if quota == nil {
+		return nil
+	}
+
+	var exceeded []string
+	checkCount := func(name string, limit *int64, used, requested int64) {
+		if limit != nil && requested > 0 && used+requested > *limit {
+			exceeded = append(exceeded, fmt.Sprintf("%s (used %d, requested %d, limit %d)", name, used, requested, *limit))
+		}
+	}
+	checkQuantity := func(name string, limit *resource.Quantity, used, requested resource.Quantity) {
+		if limit == nil || requested.Sign() <= 0 {
+			return
+		}
+		total := used.DeepCopy()
+		total.Add(requested)
+		if total.Cmp(*limit) > 0 {
+			exceeded = append(exceeded, fmt.Sprintf("%s (used %s, requested %s, limit %s)", name, used.String(), requested.String(), limit.String()))
+		}
+	}
+
+	checkCount("clusters", quota.Clusters, usage.Clusters, requested.Clusters)
+	checkCount("nodes", quota.Nodes, usage.Nodes, requested.Nodes)
+	checkQuantity("cpu", quota.CPU, usage.CPU, requested.CPU)
+	checkQuantity("memory", quota.Memory, usage.Memory, requested.Memory)
+	checkQuantity("storage", quota.Storage, usage.Storage, requested.Storage)
+
+	if len(exceeded) > 0 {
+		return fmt.Errorf("project resource quota exceeded: %s", strings.Join(exceeded, ", "))
+	}
+	return nil
+}
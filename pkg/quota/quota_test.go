@@ -0,0 +1,277 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+
+	apiv1 "k8c.io/kubermatic/v2/pkg/api/v1"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCheck(t *testing.T) {
+	cpu := resource.MustParse("8")
+	quota := &kubermaticv1.ProjectResourceQuota{
+		Clusters: pointer.Int64Ptr(2),
+		CPU:      &cpu,
+	}
+
+	testcases := []struct {
+		name          string
+		quota         *kubermaticv1.ProjectResourceQuota
+		usage         kubermaticv1.ResourceUsage
+		requested     kubermaticv1.ResourceUsage
+		expectedError string
+	}{
+		{
+			name:      "no quota",
+			usage:     kubermaticv1.ResourceUsage{Clusters: 10},
+			requested: kubermaticv1.ResourceUsage{Clusters: 1},
+		},
+		{
+			name:      "within the quota",
+			quota:     quota,
+			usage:     kubermaticv1.ResourceUsage{Clusters: 1, CPU: resource.MustParse("4")},
+			requested: kubermaticv1.ResourceUsage{Clusters: 1, CPU: resource.MustParse("4")},
+		},
+		{
+			name:          "clusters exceeded",
+			quota:         quota,
+			usage:         kubermaticv1.ResourceUsage{Clusters: 2},
+			requested:     kubermaticv1.ResourceUsage{Clusters: 1},
+			expectedError: "project resource quota exceeded: clusters (used 2, requested 1, limit 2)",
+		},
+		{
+			name:          "cpu exceeded",
+			quota:         quota,
+			usage:         kubermaticv1.ResourceUsage{Clusters: 1, CPU: resource.MustParse("6")},
+			requested:     kubermaticv1.ResourceUsage{CPU: resource.MustParse("4")},
+			expectedError: "project resource quota exceeded: cpu (used 6, requested 4, limit 8)",
+		},
+		{
+			name:      "a project over its quota can shrink",
+			quota:     quota,
+			usage:     kubermaticv1.ResourceUsage{Clusters: 3, CPU: resource.MustParse("12")},
+			requested: kubermaticv1.ResourceUsage{CPU: resource.MustParse("-2")},
+		},
+		{
+			name:      "limits which are not set are not enforced",
+			quota:     quota,
+			usage:     kubermaticv1.ResourceUsage{Nodes: 100, Memory: resource.MustParse("1Ti")},
+			requested: kubermaticv1.ResourceUsage{Nodes: 10, Memory: resource.MustParse("100Gi")},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Check(tc.quota, tc.usage, tc.requested)
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.expectedError {
+				t.Fatalf("expected error %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestClusterUsage(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clusterv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	initialNodeDeployment := &apiv1.NodeDeployment{
+		ObjectMeta: apiv1.ObjectMeta{Name: "initial"},
+		Spec: apiv1.NodeDeploymentSpec{
+			Replicas: 2,
+			Template: apiv1.NodeSpec{
+				Cloud: apiv1.NodeCloudSpec{
+					VSphere: &apiv1.VSphereNodeSpec{CPUs: 2, Memory: 4096, DiskSizeGB: pointer.Int64Ptr(20), Template: "ubuntu"},
+				},
+			},
+		},
+	}
+	request, err := json.Marshal(initialNodeDeployment)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testcases := []struct {
+		name               string
+		annotations        map[string]string
+		machineDeployments []*clusterv1alpha1.MachineDeployment
+		withClient         bool
+		expectedUsage      kubermaticv1.ResourceUsage
+	}{
+		{
+			name:          "cluster without nodes",
+			expectedUsage: kubermaticv1.ResourceUsage{Clusters: 1},
+		},
+		{
+			name:        "cluster which is not running yet accounts for its initial request",
+			annotations: map[string]string{apiv1.InitialMachineDeploymentRequestAnnotation: string(request)},
+			expectedUsage: kubermaticv1.ResourceUsage{
+				Clusters: 1,
+				Nodes:    2,
+				CPU:      resource.MustParse("4"),
+				Memory:   resource.MustParse("8Gi"),
+				Storage:  resource.MustParse("40Gi"),
+			},
+		},
+		{
+			name:        "the initial request is not accounted twice once its machine deployment exists",
+			annotations: map[string]string{apiv1.InitialMachineDeploymentRequestAnnotation: string(request)},
+			machineDeployments: []*clusterv1alpha1.MachineDeployment{
+				genVSphereMachineDeployment(t, "initial", 2, 2, 4096, 20),
+				genVSphereMachineDeployment(t, "other", 1, 4, 8192, 50),
+			},
+			withClient: true,
+			expectedUsage: kubermaticv1.ResourceUsage{
+				Clusters: 1,
+				Nodes:    3,
+				CPU:      resource.MustParse("8"),
+				Memory:   resource.MustParse("16Gi"),
+				Storage:  resource.MustParse("90Gi"),
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := &kubermaticv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster", Annotations: tc.annotations},
+			}
+
+			var userClusterClient ctrlruntimeclient.Client
+			if tc.withClient {
+				builder := ctrlruntimefakeclient.NewClientBuilder().WithScheme(scheme)
+				for _, md := range tc.machineDeployments {
+					builder = builder.WithObjects(md)
+				}
+				userClusterClient = builder.Build()
+			}
+
+			usage, err := ClusterUsage(context.Background(), cluster, userClusterClient)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if usage.Clusters != tc.expectedUsage.Clusters || usage.Nodes != tc.expectedUsage.Nodes ||
+				usage.CPU.Cmp(tc.expectedUsage.CPU) != 0 || usage.Memory.Cmp(tc.expectedUsage.Memory) != 0 ||
+				usage.Storage.Cmp(tc.expectedUsage.Storage) != 0 {
+				t.Fatalf("expected usage %s, got %s", usageString(tc.expectedUsage), usageString(usage))
+			}
+		})
+	}
+}
+
+func TestAdmissionUsage(t *testing.T) {
+	genCluster := func(name string, usage *kubermaticv1.ResourceUsage) kubermaticv1.Cluster {
+		return kubermaticv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     kubermaticv1.ClusterStatus{ResourceUsage: usage},
+		}
+	}
+
+	testcases := []struct {
+		name          string
+		projectUsage  *kubermaticv1.ResourceUsage
+		clusters      []kubermaticv1.Cluster
+		expectedUsage kubermaticv1.ResourceUsage
+	}{
+		{
+			name:          "clusters not yet accounted in the project",
+			clusters:      []kubermaticv1.Cluster{genCluster("a", nil), genCluster("b", nil)},
+			expectedUsage: kubermaticv1.ResourceUsage{Clusters: 2},
+		},
+		{
+			name:         "clusters of other seeds are accounted in the project",
+			projectUsage: &kubermaticv1.ResourceUsage{Clusters: 3, Nodes: 4, CPU: resource.MustParse("8")},
+			clusters: []kubermaticv1.Cluster{
+				genCluster("a", &kubermaticv1.ResourceUsage{Clusters: 1, Nodes: 2, CPU: resource.MustParse("4")}),
+			},
+			expectedUsage: kubermaticv1.ResourceUsage{Clusters: 3, Nodes: 4, CPU: resource.MustParse("8")},
+		},
+		{
+			name:         "the higher usage counts for every resource",
+			projectUsage: &kubermaticv1.ResourceUsage{Clusters: 1, Nodes: 4, CPU: resource.MustParse("8")},
+			clusters: []kubermaticv1.Cluster{
+				genCluster("a", &kubermaticv1.ResourceUsage{Clusters: 1, Nodes: 2, CPU: resource.MustParse("4")}),
+				genCluster("b", &kubermaticv1.ResourceUsage{Clusters: 1, Memory: resource.MustParse("8Gi")}),
+			},
+			expectedUsage: kubermaticv1.ResourceUsage{Clusters: 2, Nodes: 4, CPU: resource.MustParse("8"), Memory: resource.MustParse("8Gi")},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			project := &kubermaticv1.Project{Status: kubermaticv1.ProjectStatus{ResourceUsage: tc.projectUsage}}
+
+			usage, err := AdmissionUsage(project, tc.clusters)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if usage.Clusters != tc.expectedUsage.Clusters || usage.Nodes != tc.expectedUsage.Nodes ||
+				usage.CPU.Cmp(tc.expectedUsage.CPU) != 0 || usage.Memory.Cmp(tc.expectedUsage.Memory) != 0 ||
+				usage.Storage.Cmp(tc.expectedUsage.Storage) != 0 {
+				t.Fatalf("expected usage %s, got %s", usageString(tc.expectedUsage), usageString(usage))
+			}
+		})
+	}
+}
+
+func genVSphereMachineDeployment(t *testing.T, name string, replicas int32, cpus int, memoryMB int, diskSizeGB int) *clusterv1alpha1.MachineDeployment {
+	providerSpec, err := json.Marshal(map[string]interface{}{
+		"cloudProvider": "vsphere",
+		"cloudProviderSpec": map[string]interface{}{
+			"cpus":       cpus,
+			"memoryMB":   memoryMB,
+			"diskSizeGB": diskSizeGB,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	md := &clusterv1alpha1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceSystem},
+	}
+	md.Spec.Replicas = &replicas
+	md.Spec.Template.Spec.ProviderSpec.Value = &runtime.RawExtension{Raw: providerSpec}
+	return md
+}
+
+func usageString(u kubermaticv1.ResourceUsage) string {
+	return fmt.Sprintf("clusters=%d nodes=%d cpu=%s memory=%s storage=%s", u.Clusters, u.Nodes, u.CPU.String(), u.Memory.String(), u.Storage.String())
+}
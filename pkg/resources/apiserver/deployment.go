@@ -91,6 +91,34 @@ func DeploymentCreator(data *resources.TemplateData, enableOIDCAuthentication bo
 
 			volumes := getVolumes()
 			volumeMounts := getVolumeMounts()
+			if data.Cluster().Spec.ExposeStrategy == kubermaticv1.ExposeStrategyTunneling {
+				volumes = append(volumes, corev1.Volume{
+					Name: resources.ApiserverEgressSelectorConfigMapName,
+					VolumeSource: corev1.VolumeSource{
+						ConfigMap: &corev1.ConfigMapVolumeSource{
+							LocalObjectReference: corev1.LocalObjectReference{
+								Name: resources.ApiserverEgressSelectorConfigMapName,
+							},
+						},
+					},
+				}, corev1.Volume{
+					Name: resources.TunnelingServerEgressClientCertificateSecretName,
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: resources.TunnelingServerEgressClientCertificateSecretName,
+						},
+					},
+				})
+				volumeMounts = append(volumeMounts, corev1.VolumeMount{
+					Name:      resources.ApiserverEgressSelectorConfigMapName,
+					MountPath: "/etc/kubernetes/egress-selector",
+					ReadOnly:  true,
+				}, corev1.VolumeMount{
+					Name:      resources.TunnelingServerEgressClientCertificateSecretName,
+					MountPath: "/etc/kubernetes/tunneling-egress",
+					ReadOnly:  true,
+				})
+			}
 
 			podLabels, err := data.GetPodTemplateLabels(name, volumes, nil)
 			if err != nil {
@@ -118,19 +146,6 @@ func DeploymentCreator(data *resources.TemplateData, enableOIDCAuthentication bo
 				etcdrunning.Container(etcdEndpoints, data),
 			}
 
-			openvpnSidecar, err := vpnsidecar.OpenVPNSidecarContainer(data, "openvpn-client")
-			if err != nil {
-				return nil, fmt.Errorf("failed to get openvpn-client sidecar: %v", err)
-			}
-
-			dnatControllerSidecar, err := vpnsidecar.DnatControllerContainer(
-				data,
-				"dnat-controller",
-				fmt.Sprintf("https://127.0.0.1:%d", data.Cluster().Address.Port),
-			)
-			if err != nil {
-				return nil, fmt.Errorf("failed to get dnat-controller sidecar: %v", err)
-			}
 			auditLogEnabled := data.Cluster().Spec.AuditLogging != nil && data.Cluster().Spec.AuditLogging.Enabled
 			flags, err := getApiserverFlags(data, etcdEndpoints, enableOIDCAuthentication, auditLogEnabled)
 			if err != nil {
@@ -143,8 +158,6 @@ func DeploymentCreator(data *resources.TemplateData, enableOIDCAuthentication bo
 			}
 
 			dep.Spec.Template.Spec.Containers = []corev1.Container{
-				*openvpnSidecar,
-				*dnatControllerSidecar,
 				{
 					Name:    resources.ApiserverDeploymentName,
 					Image:   data.ImageRegistry(resources.RegistryK8SGCR) + "/kube-apiserver:v" + data.Cluster().Spec.Version.String(),
@@ -189,10 +202,35 @@ func DeploymentCreator(data *resources.TemplateData, enableOIDCAuthentication bo
 			}
 
 			defResourceRequirements := map[string]*corev1.ResourceRequirements{
-				name:                       defaultResourceRequirements.DeepCopy(),
-				openvpnSidecar.Name:        openvpnSidecar.Resources.DeepCopy(),
-				dnatControllerSidecar.Name: dnatControllerSidecar.Resources.DeepCopy(),
+				name: defaultResourceRequirements.DeepCopy(),
+			}
+
+			// With the tunneling expose strategy the user cluster is reached
+			// through the tunneling server, which is configured as egress
+			// selector, instead of the OpenVPN tunnel.
+			if data.Cluster().Spec.ExposeStrategy != kubermaticv1.ExposeStrategyTunneling {
+				openvpnSidecar, err := vpnsidecar.OpenVPNSidecarContainer(data, "openvpn-client")
+				if err != nil {
+					return nil, fmt.Errorf("failed to get openvpn-client sidecar: %v", err)
+				}
+
+				dnatControllerSidecar, err := vpnsidecar.DnatControllerContainer(
+					data,
+					"dnat-controller",
+					fmt.Sprintf("https://127.0.0.1:%d", data.Cluster().Address.Port),
+				)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get dnat-controller sidecar: %v", err)
+				}
+
+				dep.Spec.Template.Spec.Containers = append([]corev1.Container{
+					*openvpnSidecar,
+					*dnatControllerSidecar,
+				}, dep.Spec.Template.Spec.Containers...)
+				defResourceRequirements[openvpnSidecar.Name] = openvpnSidecar.Resources.DeepCopy()
+				defResourceRequirements[dnatControllerSidecar.Name] = dnatControllerSidecar.Resources.DeepCopy()
 			}
+
 			err = resources.SetResourceRequirements(dep.Spec.Template.Spec.Containers, defResourceRequirements, resources.GetOverrides(data.Cluster().Spec.ComponentsOverride), dep.Annotations)
 			if err != nil {
 				return nil, fmt.Errorf("failed to set resource requirements: %v", err)
@@ -275,8 +313,8 @@ func getApiserverFlags(data *resources.TemplateData, etcdEndpoints []string, ena
 		"--token-auth-file", "/etc/kubernetes/tokens/tokens.csv",
 		"--enable-bootstrap-token-auth",
 		"--service-account-key-file", serviceAccountKeyFile,
-		// There are efforts upstream adding support for multiple cidr's. Until that has landed, we'll take the first entry
-		"--service-cluster-ip-range", cluster.Spec.ClusterNetwork.Services.CIDRBlocks[0],
+		// Dual-stack clusters have an IPv4 and an IPv6 block, the first one is the primary family of the services
+		"--service-cluster-ip-range", strings.Join(cluster.Spec.ClusterNetwork.Services.CIDRBlocks, ","),
 		"--service-node-port-range", overrideFlags.NodePortRange,
 		"--allow-privileged",
 		"--audit-log-maxage", "30",
@@ -305,7 +343,9 @@ func getApiserverFlags(data *resources.TemplateData, etcdEndpoints []string, ena
 			// The secure port is used as target port for the kubernetes service in
 			// the default namespace of the user cluster, we use the NodePort value
 			// for being able to access the apiserver from the usercluster side.
-			"--secure-port", fmt.Sprint(cluster.Address.Port))
+			"--secure-port", fmt.Sprint(cluster.Address.Port),
+			// The traffic to the user cluster goes through the tunneling server.
+			"--egress-selector-config-file", "/etc/kubernetes/egress-selector/"+resources.ApiserverEgressSelectorConfigMapKey)
 	} else {
 		// pre-pend to have advertise-address as first argument and avoid
 		// triggering unneeded redeployments.
@@ -359,6 +399,9 @@ func getApiserverFlags(data *resources.TemplateData, etcdEndpoints []string, ena
 
 	if cluster.Spec.Cloud.GCP != nil {
 		flags = append(flags, "--kubelet-preferred-address-types", "InternalIP")
+	} else if cluster.Spec.ExposeStrategy == kubermaticv1.ExposeStrategyTunneling {
+		// The tunneling agents register the internal addresses of their nodes.
+		flags = append(flags, "--kubelet-preferred-address-types", "InternalIP,ExternalIP")
 	} else {
 		flags = append(flags, "--kubelet-preferred-address-types", "ExternalIP,InternalIP")
 	}
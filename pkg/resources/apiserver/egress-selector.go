@@ -0,0 +1,137 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"fmt"
+	"net"
+
+	"gopkg.in/yaml.v2"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/reconciling"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// EgressSelectorConfiguration provides versioned configuration for the egress
+// selector of the apiserver.
+type EgressSelectorConfiguration struct {
+	Kind string `yaml:"kind,omitempty"`
+
+	APIVersion string `yaml:"apiVersion,omitempty"`
+
+	// EgressSelections are the connections used for each kind of egress
+	// traffic.
+	EgressSelections []EgressSelection `yaml:"egressSelections"`
+}
+
+// EgressSelection is the connection used for a kind of egress traffic.
+type EgressSelection struct {
+	// Name is the kind of egress traffic, cluster is the traffic to the
+	// nodes, pods and services of the user cluster.
+	Name string `yaml:"name"`
+
+	Connection EgressConnection `yaml:"connection"`
+}
+
+// EgressConnection is the proxy used for a kind of egress traffic.
+type EgressConnection struct {
+	ProxyProtocol string          `yaml:"proxyProtocol"`
+	Transport     EgressTransport `yaml:"transport"`
+}
+
+// EgressTransport is the transport to the proxy.
+type EgressTransport struct {
+	TCP EgressTCPTransport `yaml:"tcp"`
+}
+
+// EgressTCPTransport is a TCP transport to the proxy.
+type EgressTCPTransport struct {
+	URL       string           `yaml:"url"`
+	TLSConfig *EgressTLSConfig `yaml:"tlsConfig,omitempty"`
+}
+
+// EgressTLSConfig are the files used to authenticate the proxy and the
+// apiserver to each other.
+type EgressTLSConfig struct {
+	CABundle   string `yaml:"caBundle"`
+	ClientKey  string `yaml:"clientKey"`
+	ClientCert string `yaml:"clientCert"`
+}
+
+type egressSelectorData interface {
+	Cluster() *kubermaticv1.Cluster
+	ClusterIPByServiceName(name string) (string, error)
+}
+
+// EgressSelectorConfigCreator returns the function to create the egress
+// selector configuration, sending the traffic of the apiserver to the user
+// cluster through the HTTP CONNECT proxy of the tunneling server. The proxy
+// only accepts the client certificate of the apiserver.
+func EgressSelectorConfigCreator(data egressSelectorData) reconciling.NamedConfigMapCreatorGetter {
+	return func() (string, reconciling.ConfigMapCreator) {
+		return resources.ApiserverEgressSelectorConfigMapName, func(cm *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+			if cm.Data == nil {
+				cm.Data = map[string]string{}
+			}
+
+			// The cluster IP is used as the names of the services of the
+			// control plane are resolved in the user cluster.
+			ip, err := data.ClusterIPByServiceName(resources.TunnelingServerEgressServiceName)
+			if err != nil {
+				return nil, err
+			}
+
+			config := EgressSelectorConfiguration{
+				APIVersion: "apiserver.k8s.io/v1beta1",
+				Kind:       "EgressSelectorConfiguration",
+				EgressSelections: []EgressSelection{
+					{
+						Name: "cluster",
+						Connection: EgressConnection{
+							ProxyProtocol: "HTTPConnect",
+							Transport: EgressTransport{
+								TCP: EgressTCPTransport{
+									URL: "https://" + net.JoinHostPort(ip, fmt.Sprint(resources.TunnelingServerEgressPort)),
+									TLSConfig: &EgressTLSConfig{
+										CABundle:   "/etc/kubernetes/pki/ca/" + resources.CACertSecretKey,
+										ClientKey:  "/etc/kubernetes/tunneling-egress/" + resources.TunnelingServerEgressClientKeySecretKey,
+										ClientCert: "/etc/kubernetes/tunneling-egress/" + resources.TunnelingServerEgressClientCertSecretKey,
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			// apiserver.k8s.io/v1beta1 is only served since v1.20
+			if data.Cluster().Spec.Version.Minor() < 20 {
+				config.APIVersion = "apiserver.k8s.io/v1alpha1"
+			}
+
+			rawConfig, err := yaml.Marshal(config)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal egress selector configuration: %v", err)
+			}
+			cm.Data[resources.ApiserverEgressSelectorConfigMapKey] = string(rawConfig)
+
+			return cm, nil
+		}
+	}
+}
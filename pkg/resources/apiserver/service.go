@@ -62,6 +62,9 @@ func ServiceCreator(exposeStrategy kubermaticv1.ExposeStrategy, externalURL stri
 			default:
 				return nil, fmt.Errorf("unsupported expose strategy: %q", exposeStrategy)
 			}
+			// Let Envoy stop sending connections to the API servers that are
+			// not ready, e.g. during rollouts.
+			se.Annotations[nodeportproxy.HealthCheckMappingAnnotationKey] = `{"secure": {"path": "/healthz", "tls": true}}`
 
 			se.Spec.Selector = map[string]string{
 				resources.AppLabelKey: name,
@@ -0,0 +1,40 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/certificates"
+	"k8c.io/kubermatic/v2/pkg/resources/certificates/triple"
+	"k8c.io/kubermatic/v2/pkg/resources/reconciling"
+)
+
+type tunnelingEgressClientCertificateCreatorData interface {
+	GetRootCA() (*triple.KeyPair, error)
+}
+
+// TunnelingEgressClientCertificateCreator returns a function to create/update a secret with the client certificate for the apiserver -> tunneling server connection.
+func TunnelingEgressClientCertificateCreator(data tunnelingEgressClientCertificateCreatorData) reconciling.NamedSecretCreatorGetter {
+	return certificates.GetClientCertificateCreator(
+		resources.TunnelingServerEgressClientCertificateSecretName,
+		resources.TunnelingServerEgressClientCertUsername,
+		nil,
+		resources.TunnelingServerEgressClientCertSecretKey,
+		resources.TunnelingServerEgressClientKeySecretKey,
+		data.GetRootCA,
+	)
+}
@@ -86,6 +86,12 @@ func hetznerDeploymentCreator(data *resources.TemplateData) reconciling.NamedDep
 				network = data.DC().Spec.Hetzner.Network
 			}
 
+			// The routes of Hetzner networks are IPv4 only
+			podCIDR, err := resources.IPv4CIDRBlock(data.Cluster().Spec.ClusterNetwork.Pods)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get the pod network: %v", err)
+			}
+
 			dep.Spec.Template.Spec.Volumes = getVolumes()
 
 			dep.Spec.Template.Spec.Containers = []corev1.Container{
@@ -99,7 +105,7 @@ func hetznerDeploymentCreator(data *resources.TemplateData) reconciling.NamedDep
 						"--cloud-provider=hcloud",
 						"--allow-untagged-cloud",
 						"--allocate-node-cidrs=true",
-						fmt.Sprintf("--cluster-cidr=%s", data.Cluster().Spec.ClusterNetwork.Pods.CIDRBlocks[0]),
+						fmt.Sprintf("--cluster-cidr=%s", podCIDR),
 					},
 					Env: []corev1.EnvVar{
 						{
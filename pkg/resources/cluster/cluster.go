@@ -41,8 +41,10 @@ func Spec(apiCluster apiv1.Cluster, dc *kubermaticv1.Datacenter, secretKeyGetter
 		HumanReadableName:                    apiCluster.Name,
 		Cloud:                                apiCluster.Spec.Cloud,
 		MachineNetworks:                      apiCluster.Spec.MachineNetworks,
+		CNIPlugin:                            apiCluster.Spec.CNIPlugin,
 		OIDC:                                 apiCluster.Spec.OIDC,
 		UpdateWindow:                         apiCluster.Spec.UpdateWindow,
+		Hibernation:                          apiCluster.Spec.Hibernation,
 		Version:                              apiCluster.Spec.Version,
 		UsePodSecurityPolicyAdmissionPlugin:  apiCluster.Spec.UsePodSecurityPolicyAdmissionPlugin,
 		UsePodNodeSelectorAdmissionPlugin:    apiCluster.Spec.UsePodNodeSelectorAdmissionPlugin,
@@ -54,6 +56,9 @@ func Spec(apiCluster apiv1.Cluster, dc *kubermaticv1.Datacenter, secretKeyGetter
 		ServiceAccount:                       apiCluster.Spec.ServiceAccount,
 		MLA:                                  apiCluster.Spec.MLA,
 	}
+	if apiCluster.Spec.ClusterNetwork != nil {
+		spec.ClusterNetwork = *apiCluster.Spec.ClusterNetwork
+	}
 
 	providerName, err := provider.ClusterCloudProviderName(spec.Cloud)
 	if err != nil {
@@ -215,12 +215,17 @@ func getFlags(data *resources.TemplateData) ([]string, error) {
 		"--root-ca-file", "/etc/kubernetes/pki/ca/ca.crt",
 		"--cluster-signing-cert-file", "/etc/kubernetes/pki/ca/ca.crt",
 		"--cluster-signing-key-file", "/etc/kubernetes/pki/ca/ca.key",
-		"--cluster-cidr", data.Cluster().Spec.ClusterNetwork.Pods.CIDRBlocks[0],
+		"--cluster-cidr", strings.Join(data.Cluster().Spec.ClusterNetwork.Pods.CIDRBlocks, ","),
 		"--allocate-node-cidrs",
 		"--controllers", strings.Join(controllers, ","),
 		"--use-service-account-credentials",
 	}
 
+	// The node IPAM controller must not allocate pod ranges overlapping the IPv4 or IPv6 service range
+	if resources.IsDualStackCluster(data.Cluster()) {
+		flags = append(flags, "--service-cluster-ip-range", strings.Join(data.Cluster().Spec.ClusterNetwork.Services.CIDRBlocks, ","))
+	}
+
 	featureGates := []string{"RotateKubeletServerCertificate=true"}
 
 	// starting with k8s 1.21, this is always true and cannot be toggled anymore
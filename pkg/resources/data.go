@@ -70,6 +70,7 @@ type TemplateData struct {
 	kubermaticImage          string
 	etcdLauncherImage        string
 	dnatControllerImage      string
+	dnatControllerBackend    string
 	backupSchedule           time.Duration
 	versions                 kubermatic.Versions
 	caBundle                 CABundle
@@ -203,6 +204,11 @@ func (td *TemplateDataBuilder) WithDnatControllerImage(image string) *TemplateDa
 	return td
 }
 
+func (td *TemplateDataBuilder) WithDnatControllerBackend(backend string) *TemplateDataBuilder {
+	td.data.dnatControllerBackend = backend
+	return td
+}
+
 func (td *TemplateDataBuilder) WithVersions(v kubermatic.Versions) *TemplateDataBuilder {
 	td.data.versions = v
 	return td
@@ -346,6 +352,20 @@ func (d *TemplateData) NodePortProxyTag() string {
 	return d.versions.Kubermatic
 }
 
+// NodeportProxyLimits returns the limits enforced by the nodeport-proxy on the
+// control plane services of the cluster. The limits of the cluster take
+// precedence over the ones of the seed.
+func (d *TemplateData) NodeportProxyLimits() *kubermaticv1.NodeportProxyLimits {
+	limits := &kubermaticv1.NodeportProxyLimits{}
+	if d.cluster.Spec.NodeportProxyLimits != nil {
+		limits = d.cluster.Spec.NodeportProxyLimits.DeepCopy()
+	}
+	if d.seed != nil && d.seed.Spec.NodeportProxy.Limits != nil {
+		d.seed.Spec.NodeportProxy.Limits.Merge(limits)
+	}
+	return limits
+}
+
 // MonitoringScrapeAnnotationPrefix returns the scrape annotation prefix
 func (d *TemplateData) MonitoringScrapeAnnotationPrefix() string {
 	return strings.NewReplacer(".", "_", "/", "").Replace(d.monitoringScrapeAnnotationPrefix)
@@ -528,6 +548,11 @@ func (d *TemplateData) DNATControllerTag() string {
 	return d.versions.Kubermatic
 }
 
+// DNATControllerBackend returns the backend the dnat controller uses to translate the node addresses
+func (d *TemplateData) DNATControllerBackend() string {
+	return d.dnatControllerBackend
+}
+
 func (d *TemplateData) SupportsFailureDomainZoneAntiAffinity() bool {
 	return d.supportsFailureDomainZoneAntiAffinity
 }
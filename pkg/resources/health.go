@@ -65,3 +65,22 @@ func HealthyStatefulSet(ctx context.Context, client ctrlruntimeclient.Client, nn
 	}
 	return kubermaticv1.HealthStatusUp, nil
 }
+
+// HealthyDaemonSet tells if the daemonset has a minimum of minReady pods in Ready status
+func HealthyDaemonSet(ctx context.Context, client ctrlruntimeclient.Client, nn types.NamespacedName, minReady int32) (kubermaticv1.HealthStatus, error) {
+	daemonSet := &appsv1.DaemonSet{}
+	if err := client.Get(ctx, nn, daemonSet); err != nil {
+		if kerrors.IsNotFound(err) {
+			return kubermaticv1.HealthStatusDown, nil
+		}
+		return kubermaticv1.HealthStatusDown, err
+	}
+
+	if daemonSet.Status.NumberReady < minReady {
+		return kubermaticv1.HealthStatusDown, nil
+	}
+	if daemonSet.Status.UpdatedNumberScheduled != daemonSet.Status.DesiredNumberScheduled || daemonSet.Status.NumberReady != daemonSet.Status.DesiredNumberScheduled {
+		return kubermaticv1.HealthStatusProvisioning, nil
+	}
+	return kubermaticv1.HealthStatusUp, nil
+}
@@ -65,6 +65,7 @@ type metricsServerData interface {
 	ImageRegistry(string) string
 	DNATControllerImage() string
 	DNATControllerTag() string
+	DNATControllerBackend() string
 	NodeAccessNetwork() string
 }
 
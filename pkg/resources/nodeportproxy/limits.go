@@ -0,0 +1,59 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeportproxy
+
+import (
+	"strconv"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources/reconciling"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// WithLimits wraps the creator of an exposed service so that the service
+// carries the annotations configuring the limits enforced by Envoy on it.
+func WithLimits(getter reconciling.NamedServiceCreatorGetter, limits *kubermaticv1.NodeportProxyLimits) reconciling.NamedServiceCreatorGetter {
+	return func() (string, reconciling.ServiceCreator) {
+		name, create := getter()
+		return name, func(se *corev1.Service) (*corev1.Service, error) {
+			se, err := create(se)
+			if err != nil {
+				return nil, err
+			}
+			if limits == nil {
+				limits = &kubermaticv1.NodeportProxyLimits{}
+			}
+			if se.Annotations == nil {
+				se.Annotations = map[string]string{}
+			}
+			setLimitAnnotation(se, MaxConnectionsAnnotationKey, limits.MaxConnections)
+			setLimitAnnotation(se, MaxPendingRequestsAnnotationKey, limits.MaxPendingRequests)
+			setLimitAnnotation(se, MaxRequestsAnnotationKey, limits.MaxRequests)
+			setLimitAnnotation(se, ConnectionsPerSecondAnnotationKey, limits.ConnectionsPerSecond)
+			return se, nil
+		}
+	}
+}
+
+func setLimitAnnotation(se *corev1.Service, key string, value *uint32) {
+	if value == nil {
+		delete(se.Annotations, key)
+		return
+	}
+	se.Annotations[key] = strconv.FormatUint(uint64(*value), 10)
+}
@@ -50,6 +50,21 @@ const (
 	// exposed and the hostname, this is only used when the ExposeType is
 	// SNIType.
 	PortHostMappingAnnotationKey = "nodeport-proxy.k8s.io/port-mapping"
+
+	// MaxConnectionsAnnotationKey, MaxPendingRequestsAnnotationKey and
+	// MaxRequestsAnnotationKey contain the circuit breaker thresholds of the
+	// Envoy clusters of the exposed ports of the service.
+	MaxConnectionsAnnotationKey     = "nodeport-proxy.k8s.io/max-connections"
+	MaxPendingRequestsAnnotationKey = "nodeport-proxy.k8s.io/max-pending-requests"
+	MaxRequestsAnnotationKey        = "nodeport-proxy.k8s.io/max-requests"
+	// ConnectionsPerSecondAnnotationKey contains the rate at which new
+	// connections to each exposed port of the service are accepted, this is
+	// not supported when the ExposeType is TunnelingType.
+	ConnectionsPerSecondAnnotationKey = "nodeport-proxy.k8s.io/connections-per-second"
+	// HealthCheckMappingAnnotationKey contains the mapping between the port
+	// names and the HTTP health checks of their endpoints, the endpoints of
+	// the ports that are not mapped are checked by opening a TCP connection.
+	HealthCheckMappingAnnotationKey = "nodeport-proxy.k8s.io/health-check-mapping"
 )
 
 // ExposeType defines the strategy used to expose the service.
@@ -111,6 +126,16 @@ func (e ExposeTypes) Insert(item ExposeType) {
 	e[item] = sets.Empty{}
 }
 
+// HTTPHealthCheck is the HTTP health check of the endpoints of a port.
+type HTTPHealthCheck struct {
+	// Path is the requested path, the endpoints are healthy when it answers
+	// with 200.
+	Path string `json:"path"`
+	// TLS tells if the endpoints serve HTTPS, their certificates are not
+	// verified.
+	TLS bool `json:"tls,omitempty"`
+}
+
 var (
 	defaultResourceRequirements = map[string]*corev1.ResourceRequirements{
 		"envoy-manager": {
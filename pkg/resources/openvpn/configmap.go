@@ -41,11 +41,12 @@ func ServerClientConfigsConfigMapCreator(data serverClientConfigsData) reconcili
 
 			var iroutes []string
 
-			// iroute for pod network
-			if len(data.Cluster().Spec.ClusterNetwork.Pods.CIDRBlocks) < 1 {
-				return nil, fmt.Errorf("cluster.Spec.ClusterNetwork.Pods.CIDRBlocks must contain at least one entry")
+			// iroute for pod network, the VPN is IPv4 only
+			podCIDR, err := resources.IPv4CIDRBlock(data.Cluster().Spec.ClusterNetwork.Pods)
+			if err != nil {
+				return nil, fmt.Errorf("cluster.Spec.ClusterNetwork.Pods.CIDRBlocks must contain an IPv4 block: %v", err)
 			}
-			_, podNet, err := net.ParseCIDR(data.Cluster().Spec.ClusterNetwork.Pods.CIDRBlocks[0])
+			_, podNet, err := net.ParseCIDR(podCIDR)
 			if err != nil {
 				return nil, err
 			}
@@ -54,10 +55,11 @@ func ServerClientConfigsConfigMapCreator(data serverClientConfigsData) reconcili
 				net.IP(podNet.Mask).String()))
 
 			// iroute for service network
-			if len(data.Cluster().Spec.ClusterNetwork.Services.CIDRBlocks) < 1 {
-				return nil, fmt.Errorf("cluster.Spec.ClusterNetwork.Services.CIDRBlocks must contain at least one entry")
+			serviceCIDR, err := resources.IPv4CIDRBlock(data.Cluster().Spec.ClusterNetwork.Services)
+			if err != nil {
+				return nil, fmt.Errorf("cluster.Spec.ClusterNetwork.Services.CIDRBlocks must contain an IPv4 block: %v", err)
 			}
-			_, serviceNet, err := net.ParseCIDR(data.Cluster().Spec.ClusterNetwork.Services.CIDRBlocks[0])
+			_, serviceNet, err := net.ParseCIDR(serviceCIDR)
 			if err != nil {
 				return nil, err
 			}
@@ -115,12 +115,21 @@ func DeploymentCreator(data openVPNDeploymentCreatorData) reconciling.NamedDeplo
 				},
 			}
 
-			_, podNet, err := net.ParseCIDR(data.Cluster().Spec.ClusterNetwork.Pods.CIDRBlocks[0])
+			// The VPN is IPv4 only, so only the IPv4 blocks of dual-stack clusters are routed through it
+			podCIDR, err := resources.IPv4CIDRBlock(data.Cluster().Spec.ClusterNetwork.Pods)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get the pod network: %v", err)
+			}
+			_, podNet, err := net.ParseCIDR(podCIDR)
 			if err != nil {
 				return nil, err
 			}
 
-			_, serviceNet, err := net.ParseCIDR(data.Cluster().Spec.ClusterNetwork.Services.CIDRBlocks[0])
+			serviceCIDR, err := resources.IPv4CIDRBlock(data.Cluster().Spec.ClusterNetwork.Services)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get the service network: %v", err)
+			}
+			_, serviceNet, err := net.ParseCIDR(serviceCIDR)
 			if err != nil {
 				return nil, err
 			}
@@ -17,25 +17,23 @@ limitations under the License.
 package resources
 
 import (
+	"bytes"
 	"context"
 	"crypto/ecdsa"
 	"crypto/rsa"
-	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net"
-	"net/http"
 	"time"
 
-	"github.com/minio/minio-go"
-
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/resources/certificates/triple"
 	"k8c.io/kubermatic/v2/pkg/resources/reconciling"
 	"k8c.io/kubermatic/v2/pkg/semver"
+	"k8c.io/kubermatic/v2/pkg/storeuploader"
 
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
@@ -48,6 +46,7 @@ import (
 	certutil "k8s.io/client-go/util/cert"
 	"k8s.io/klog"
 	apiregistrationv1beta1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1beta1"
+	netutils "k8s.io/utils/net"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -90,6 +89,12 @@ const (
 	KubeStateMetricsDeploymentName = "kube-state-metrics"
 	// UserClusterControllerDeploymentName is the name of the usercluster-controller deployment
 	UserClusterControllerDeploymentName = "usercluster-controller"
+	// UserClusterControllerWebhookServiceName is the name of the service exposing the admission webhook of the
+	// usercluster-controller to the apiserver
+	UserClusterControllerWebhookServiceName = "usercluster-controller-webhook"
+	// UserClusterControllerWebhookServingCertSecretName is the name of the secret containing the serving cert of the
+	// admission webhook of the usercluster-controller
+	UserClusterControllerWebhookServingCertSecretName = "usercluster-controller-webhook-serving-cert"
 	// ClusterAutoscalerDeploymentName is the name of the cluster-autoscaler deployment
 	ClusterAutoscalerDeploymentName = "cluster-autoscaler"
 	// KubernetesDashboardDeploymentName is the name of the Kubernetes Dashboard deployment
@@ -387,6 +392,10 @@ const (
 	// configuration
 	GatekeeperValidatingWebhookConfigurationName = "gatekeeper-validating-webhook-configuration"
 
+	// UserClusterControllerValidatingWebhookConfigurationName is the name of the validating webhook configuration of
+	// the usercluster-controller
+	UserClusterControllerValidatingWebhookConfigurationName = "usercluster-controller.kubermatic.io"
+
 	// InternalUserClusterAdminKubeconfigSecretName is the name of the secret containing an admin kubeconfig that can only be used from
 	// within the seed cluster
 	InternalUserClusterAdminKubeconfigSecretName = "internal-admin-kubeconfig"
@@ -455,6 +464,9 @@ const (
 	EtcdRestoreS3EndpointKey       = "ENDPOINT"
 	EtcdRestoreDefaultS3SEndpoint  = "s3.amazonaws.com"
 
+	// EtcdBackupAzureAccountKeyKey is the key in the credentials secret of an Azure Blob backup destination containing the storage account key
+	EtcdBackupAzureAccountKeyKey = "ACCOUNT_KEY"
+
 	// KubeconfigDefaultContextKey is the context key used for all kubeconfigs
 	KubeconfigDefaultContextKey = "default"
 
@@ -528,6 +540,15 @@ const (
 	AnexiaToken = "token"
 
 	UserSSHKeys = "usersshkeys"
+	// UserSSHKeysMetadataKey is the key of the user ssh keys secret holding
+	// the UserSSHKeyMetadata of the other keys as JSON. It cannot collide with
+	// the names of the keys as these have to start with an alphanumeric character.
+	UserSSHKeysMetadataKey = ".metadata"
+	// UserSSHKeysCertificateAuthorityKey is the key of the user ssh keys secret
+	// holding the public key of the ssh certificate authority of the project.
+	// If it is present, the secret holds no keys as the nodes trust the
+	// certificates signed by the authority instead.
+	UserSSHKeysCertificateAuthorityKey = ".ca.pub"
 )
 
 const (
@@ -548,6 +569,46 @@ const (
 	EnvoyAgentDeviceSetupImage                 = "kubermatic/kubeletdnat-controller"
 )
 
+const (
+	// TunnelingServerDeploymentName is the name of the tunneling server deployment
+	TunnelingServerDeploymentName = "tunneling-server"
+	// TunnelingServerServiceName is the name of the service exposing the agent port of the tunneling server
+	// through the nodeport-proxy
+	TunnelingServerServiceName = "tunneling-server"
+	// TunnelingServerEgressServiceName is the name of the service exposing the HTTP CONNECT proxy of the tunneling
+	// server to the apiserver
+	TunnelingServerEgressServiceName = "tunneling-server-egress"
+	// TunnelingServerAgentPort is the port of the tunneling server accepting the tunnels of the agents
+	TunnelingServerAgentPort = 8132
+	// TunnelingServerEgressPort is the port of the HTTP CONNECT proxy of the tunneling server
+	TunnelingServerEgressPort = 8131
+	// TunnelingServerEgressServingCertSecretName is the name of the secret containing the serving certificate of the
+	// HTTP CONNECT proxy of the tunneling server
+	TunnelingServerEgressServingCertSecretName = "tunneling-server-egress-serving-cert"
+	// TunnelingServerEgressClientCertificateSecretName is the name of the secret containing the client certificate of
+	// the apiserver for the HTTP CONNECT proxy of the tunneling server
+	TunnelingServerEgressClientCertificateSecretName = "tunneling-server-egress-client-certificate"
+	// TunnelingServerEgressClientCertSecretKey tunneling-egress-client.crt
+	TunnelingServerEgressClientCertSecretKey = "tunneling-egress-client.crt"
+	// TunnelingServerEgressClientKeySecretKey tunneling-egress-client.key
+	TunnelingServerEgressClientKeySecretKey = "tunneling-egress-client.key"
+	// TunnelingServerEgressClientCertUsername is the common name of the client certificate of the apiserver, the only
+	// client accepted by the HTTP CONNECT proxy of the tunneling server
+	TunnelingServerEgressClientCertUsername = "kube-apiserver-tunneling-egress-client"
+	// TunnelingAgentTokenSecretName is the name of the secret containing the token shared by the tunneling server
+	// and agents, both in the cluster namespace and in the kube-system namespace of the user cluster
+	TunnelingAgentTokenSecretName = "tunneling-agent-token"
+	// TunnelingAgentTokenSecretKey is the key of the token in the tunneling agent token secret
+	TunnelingAgentTokenSecretKey = "token"
+	// TunnelingAgentDaemonSetName is the name of the tunneling agent DaemonSet in the user cluster
+	TunnelingAgentDaemonSetName = "tunneling-agent"
+	// ApiserverEgressSelectorConfigMapName is the name of the configmap containing the egress selector
+	// configuration of the apiserver
+	ApiserverEgressSelectorConfigMapName = "apiserver-egress-selector"
+	// ApiserverEgressSelectorConfigMapKey is the key of the egress selector configuration in its configmap
+	ApiserverEgressSelectorConfigMapKey = "egress-selector-configuration.yaml"
+)
+
 const (
 	NodeLocalDNSServiceAccountName = "node-local-dns"
 	NodeLocalDNSConfigMapName      = "node-local-dns"
@@ -636,6 +697,15 @@ type ECDSAKeyPair struct {
 	Cert *x509.Certificate
 }
 
+// UserSSHKeyMetadata describes where and until when a key of the user ssh
+// keys secret is authorized.
+type UserSSHKeyMetadata struct {
+	// User is the Linux user the key is authorized for, all users if empty.
+	User string `json:"user,omitempty"`
+	// ExpiresAt is the time after which the key is not authorized anymore.
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+}
+
 // CRDCreateor defines an interface to create/update CustomRessourceDefinitions
 type CRDCreateor = func(version semver.Semver, existing *apiextensionsv1beta1.CustomResourceDefinition) (*apiextensionsv1beta1.CustomResourceDefinition, error)
 
@@ -690,7 +760,8 @@ func String(v string) *string {
 }
 
 // UserClusterDNSResolverIP returns the 9th usable IP address
-// from the first Service CIDR block from ClusterNetwork spec.
+// from the first Service CIDR block from ClusterNetwork spec,
+// whose family is the primary one of dual-stack clusters.
 // This is by convention the IP address of the DNS resolver.
 // Returns "" on error.
 func UserClusterDNSResolverIP(cluster *kubermaticv1.Cluster) (string, error) {
@@ -724,6 +795,33 @@ func InClusterApiserverIP(cluster *kubermaticv1.Cluster) (*net.IP, error) {
 	return &ip, nil
 }
 
+// IPv4CIDRBlock returns the IPv4 block of the network ranges. Every cluster has one, dual-stack
+// clusters have an additional IPv6 block.
+func IPv4CIDRBlock(ranges kubermaticv1.NetworkRanges) (string, error) {
+	for _, block := range ranges.CIDRBlocks {
+		if netutils.IsIPv4CIDRString(block) {
+			return block, nil
+		}
+	}
+	return "", fmt.Errorf("no IPv4 block in %v", ranges.CIDRBlocks)
+}
+
+// IPv6CIDRBlock returns the IPv6 block of the network ranges of dual-stack clusters
+// or an empty string for single-stack clusters.
+func IPv6CIDRBlock(ranges kubermaticv1.NetworkRanges) string {
+	for _, block := range ranges.CIDRBlocks {
+		if netutils.IsIPv6CIDRString(block) {
+			return block
+		}
+	}
+	return ""
+}
+
+// IsDualStackCluster returns true if the pods and services of the cluster get IPv4 and IPv6 addresses.
+func IsDualStackCluster(cluster *kubermaticv1.Cluster) bool {
+	return IPv6CIDRBlock(cluster.Spec.ClusterNetwork.Pods) != "" && IPv6CIDRBlock(cluster.Spec.ClusterNetwork.Services) != ""
+}
+
 type userClusterDNSPolicyAndConfigData interface {
 	Cluster() *kubermaticv1.Cluster
 	ClusterIPByServiceName(name string) (string, error)
@@ -1167,35 +1265,115 @@ func BackupCABundleConfigMapName(cluster *kubermaticv1.Cluster) string {
 	return fmt.Sprintf("cluster-%s-ca-bundle", cluster.Name)
 }
 
-// GetEtcdRestoreS3Client returns an S3 client for downloading the backup for a given EtcdRestore.
-// If the EtcdRestore doesn't reference a secret containing the credentials and endpoint and bucket name data,
-// one can optionally be created from a well-known secret and configmap in kube-system.
-func GetEtcdRestoreS3Client(ctx context.Context, restore *kubermaticv1.EtcdRestore, createSecretIfMissing bool, client ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster) (*minio.Client, string, error) {
+// GetEtcdRestoreBackupStore returns the backup store for downloading the backup for a given EtcdRestore from its destination.
+// If the EtcdRestore doesn't reference a secret containing the credentials (and, without destination, the endpoint and bucket name),
+// one can optionally be created from the credentials secret of the destination or the well-known secret and configmap in kube-system.
+func GetEtcdRestoreBackupStore(ctx context.Context, restore *kubermaticv1.EtcdRestore, createSecretIfMissing bool, client ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster) (storeuploader.BackupStore, error) {
+	destination := restore.Spec.Destination
+	secretData, err := getEtcdRestoreCredentials(ctx, restore, createSecretIfMissing, client, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	if destination != nil && destination.AzureBlob != nil {
+		store, err := storeuploader.NewAzureBlobStore(destination.AzureBlob.AccountName, secretData[EtcdBackupAzureAccountKeyKey], destination.AzureBlob.ContainerName)
+		if err != nil {
+			return nil, fmt.Errorf("error creating azure blob client: %w", err)
+		}
+		return store, nil
+	}
+
+	accessKeyID := secretData[EtcdRestoreS3AccessKeyIDKey]
+	secretAccessKey := secretData[EtcdRestoreS3SecretKeyAccessKeyKey]
+	bucketName := secretData[EtcdRestoreS3BucketNameKey]
+	endpoint := secretData[EtcdRestoreS3EndpointKey]
+	if destination != nil && destination.S3 != nil {
+		bucketName = destination.S3.BucketName
+		endpoint = destination.S3.Endpoint
+	}
+
+	if bucketName == "" {
+		return nil, fmt.Errorf("s3 bucket name not set")
+	}
+	if endpoint == "" {
+		endpoint = EtcdRestoreDefaultS3SEndpoint
+	}
+
+	caBundleConfigMap := &corev1.ConfigMap{}
+	caBundleKey := types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: BackupCABundleConfigMapName(cluster)}
+	if err := client.Get(ctx, caBundleKey, caBundleConfigMap); err != nil {
+		return nil, fmt.Errorf("failed to get CA bundle ConfigMap: %w", err)
+	}
+	bundle, ok := caBundleConfigMap.Data[CABundleConfigMapKey]
+	if !ok {
+		return nil, fmt.Errorf("ConfigMap does not contain key %q", CABundleConfigMapKey)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(bundle)) {
+		return nil, errors.New("CA bundle does not contain any valid certificates")
+	}
+
+	store, err := storeuploader.NewS3Store(endpoint, true, accessKeyID, secretAccessKey, bucketName, false, pool)
+	if err != nil {
+		return nil, fmt.Errorf("error creating s3 client: %w", err)
+	}
+
+	return store, nil
+}
+
+// getEtcdRestoreCredentials returns the content of the BackupDownloadCredentialsSecret of the restore. If it is not set
+// and createSecretIfMissing is true, the secret is created from the credentials secret of the restore's destination or,
+// without destination credentials, from the well-known S3 secret and configmap in kube-system.
+func getEtcdRestoreCredentials(ctx context.Context, restore *kubermaticv1.EtcdRestore, createSecretIfMissing bool, client ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster) (map[string]string, error) {
 	secretData := make(map[string]string)
 
 	if restore.Spec.BackupDownloadCredentialsSecret != "" {
 		secret := &corev1.Secret{}
 		if err := client.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: restore.Spec.BackupDownloadCredentialsSecret}, secret); err != nil {
-			return nil, "", fmt.Errorf("failed to get BackupDownloadCredentialsSecret credentials secret %v: %v", restore.Spec.BackupDownloadCredentialsSecret, err)
+			return nil, fmt.Errorf("failed to get BackupDownloadCredentialsSecret credentials secret %v: %v", restore.Spec.BackupDownloadCredentialsSecret, err)
 		}
 
 		for k, v := range secret.Data {
 			secretData[k] = string(v)
 		}
-	} else {
-		if !createSecretIfMissing {
-			return nil, "", fmt.Errorf("BackupDownloadCredentialsSecret not set")
+		return secretData, nil
+	}
+
+	if !createSecretIfMissing {
+		return nil, fmt.Errorf("BackupDownloadCredentialsSecret not set")
+	}
+
+	// create BackupDownloadCredentialsSecret containing values from the destination's credentials secret
+	// or kube-system/s3-credentials / kube-system/s3-settings
+
+	var destinationCredentialsSecret string
+	if destination := restore.Spec.Destination; destination != nil {
+		switch {
+		case destination.S3 != nil:
+			destinationCredentialsSecret = destination.S3.CredentialsSecretName
+		case destination.AzureBlob != nil:
+			destinationCredentialsSecret = destination.AzureBlob.CredentialsSecretName
 		}
+	}
 
-		// create BackupDownloadCredentialsSecret containing values from kube-system/s3-credentials / kube-system/s3-settings
+	if destinationCredentialsSecret != "" {
+		credsSecret := &corev1.Secret{}
+		if err := client.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: destinationCredentialsSecret}, credsSecret); err != nil {
+			return nil, fmt.Errorf("failed to get backup destination credentials secret %v/%v: %w", metav1.NamespaceSystem, destinationCredentialsSecret, err)
+		}
 
+		for k, v := range credsSecret.Data {
+			secretData[k] = string(v)
+		}
+	} else {
 		credsSecret := &corev1.Secret{}
 		if err := client.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: EtcdRestoreS3CredentialsSecret}, credsSecret); err != nil {
-			return nil, "", fmt.Errorf("failed to get s3 credentials secret %v/%v: %w", metav1.NamespaceSystem, EtcdRestoreS3CredentialsSecret, err)
+			return nil, fmt.Errorf("failed to get s3 credentials secret %v/%v: %w", metav1.NamespaceSystem, EtcdRestoreS3CredentialsSecret, err)
 		}
 		settingsConfigMap := &corev1.ConfigMap{}
 		if err := client.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: EtcdRestoreS3SettingsConfigMap}, settingsConfigMap); err != nil {
-			return nil, "", fmt.Errorf("failed to get s3 settings configmap %v/%v: %w", metav1.NamespaceSystem, EtcdRestoreS3SettingsConfigMap, err)
+			return nil, fmt.Errorf("failed to get s3 settings configmap %v/%v: %w", metav1.NamespaceSystem, EtcdRestoreS3SettingsConfigMap, err)
 		}
 
 		for k, v := range credsSecret.Data {
@@ -1204,73 +1382,114 @@ func GetEtcdRestoreS3Client(ctx context.Context, restore *kubermaticv1.EtcdResto
 		for k, v := range settingsConfigMap.Data {
 			secretData[k] = v
 		}
+	}
 
-		creator := func(se *corev1.Secret) (*corev1.Secret, error) {
-			if se.Data == nil {
-				se.Data = map[string][]byte{}
-			}
-			for k, v := range secretData {
-				se.Data[k] = []byte(v)
-			}
-			return se, nil
+	creator := func(se *corev1.Secret) (*corev1.Secret, error) {
+		if se.Data == nil {
+			se.Data = map[string][]byte{}
+		}
+		for k, v := range secretData {
+			se.Data[k] = []byte(v)
 		}
+		return se, nil
+	}
+
+	wrappedCreator := reconciling.SecretObjectWrapper(creator)
+	wrappedCreator = reconciling.OwnerRefWrapper(GetEtcdRestoreRef(restore))(wrappedCreator)
+
+	secretName := fmt.Sprintf("%s-backupdownload-%s", restore.Name, rand.String(10))
+
+	if err := reconciling.EnsureNamedObject(
+		ctx,
+		types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: secretName},
+		wrappedCreator, client, &corev1.Secret{}, false); err != nil {
+		return nil, fmt.Errorf("failed to ensure Secret %s: %w", secretName, err)
+	}
 
-		wrappedCreator := reconciling.SecretObjectWrapper(creator)
-		wrappedCreator = reconciling.OwnerRefWrapper(GetEtcdRestoreRef(restore))(wrappedCreator)
+	oldRestore := restore.DeepCopy()
+	restore.Spec.BackupDownloadCredentialsSecret = secretName
+	if err := client.Patch(ctx, restore, ctrlruntimeclient.MergeFrom(oldRestore)); err != nil {
+		return nil, fmt.Errorf("failed to write etcdrestore.backupDownloadCredentialsSecret: %w", err)
+	}
+
+	return secretData, nil
+}
 
-		secretName := fmt.Sprintf("%s-backupdownload-%s", restore.Name, rand.String(10))
+// GetEtcdRestoreDecryptionKeys returns the keys to decrypt encrypted backups with, indexed by key ID.
+// If the restore does not reference a secret containing the keys yet and createSecretIfMissing is true,
+// the secret is created from the key secrets of the cluster's EtcdBackupConfigs.
+func GetEtcdRestoreDecryptionKeys(ctx context.Context, restore *kubermaticv1.EtcdRestore, createSecretIfMissing bool, client ctrlruntimeclient.Client, cluster *kubermaticv1.Cluster) (map[string][]byte, error) {
+	keys := make(map[string][]byte)
 
-		if err := reconciling.EnsureNamedObject(
-			ctx,
-			types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: secretName},
-			wrappedCreator, client, &corev1.Secret{}, false); err != nil {
-			return nil, "", fmt.Errorf("failed to ensure Secret %s: %w", secretName, err)
+	if restore.Spec.BackupDecryptionKeysSecret != "" {
+		secret := &corev1.Secret{}
+		if err := client.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: restore.Spec.BackupDecryptionKeysSecret}, secret); err != nil {
+			return nil, fmt.Errorf("failed to get BackupDecryptionKeysSecret %v: %v", restore.Spec.BackupDecryptionKeysSecret, err)
 		}
 
-		oldRestore := restore.DeepCopy()
-		restore.Spec.BackupDownloadCredentialsSecret = secretName
-		if err := client.Patch(ctx, restore, ctrlruntimeclient.MergeFrom(oldRestore)); err != nil {
-			return nil, "", fmt.Errorf("failed to write etcdrestore.backupDownloadCredentialsSecret: %w", err)
+		for k, v := range secret.Data {
+			keys[k] = v
 		}
-	}
 
-	accessKeyID := secretData[EtcdRestoreS3AccessKeyIDKey]
-	secretAccessKey := secretData[EtcdRestoreS3SecretKeyAccessKeyKey]
-	bucketName := secretData[EtcdRestoreS3BucketNameKey]
-	endpoint := secretData[EtcdRestoreS3EndpointKey]
+		return keys, nil
+	}
 
-	if bucketName == "" {
-		return nil, "", fmt.Errorf("s3 bucket name not set")
+	if !createSecretIfMissing {
+		return nil, fmt.Errorf("BackupDecryptionKeysSecret not set")
 	}
-	if endpoint == "" {
-		endpoint = EtcdRestoreDefaultS3SEndpoint
+
+	// create BackupDecryptionKeysSecret containing the keys of all EtcdBackupConfigs of the cluster
+
+	backupConfigs := &kubermaticv1.EtcdBackupConfigList{}
+	if err := client.List(ctx, backupConfigs, ctrlruntimeclient.InNamespace(cluster.Status.NamespaceName)); err != nil {
+		return nil, fmt.Errorf("failed to list EtcdBackupConfigs: %w", err)
 	}
 
-	caBundleConfigMap := &corev1.ConfigMap{}
-	caBundleKey := types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: BackupCABundleConfigMapName(cluster)}
-	if err := client.Get(ctx, caBundleKey, caBundleConfigMap); err != nil {
-		return nil, "", fmt.Errorf("failed to get CA bundle ConfigMap: %w", err)
+	for _, backupConfig := range backupConfigs.Items {
+		if backupConfig.Spec.Cluster.Name != cluster.Name || backupConfig.Spec.Encryption == nil {
+			continue
+		}
+
+		keySecretName := backupConfig.Spec.Encryption.KeySecretName
+		keySecret := &corev1.Secret{}
+		if err := client.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: keySecretName}, keySecret); err != nil {
+			return nil, fmt.Errorf("failed to get backup encryption key secret %v/%v: %w", metav1.NamespaceSystem, keySecretName, err)
+		}
+
+		for k, v := range keySecret.Data {
+			if existing, ok := keys[k]; ok && !bytes.Equal(existing, v) {
+				return nil, fmt.Errorf("backup encryption key %q is defined with different values in multiple secrets", k)
+			}
+			keys[k] = v
+		}
 	}
-	bundle, ok := caBundleConfigMap.Data[CABundleConfigMapKey]
-	if !ok {
-		return nil, "", fmt.Errorf("ConfigMap does not contain key %q", CABundleConfigMapKey)
+
+	if len(keys) == 0 {
+		return nil, errors.New("none of the cluster's EtcdBackupConfigs has encryption configured")
 	}
 
-	pool := x509.NewCertPool()
-	if !pool.AppendCertsFromPEM([]byte(bundle)) {
-		return nil, "", errors.New("CA bundle does not contain any valid certificates")
+	creator := func(se *corev1.Secret) (*corev1.Secret, error) {
+		se.Data = keys
+		return se, nil
 	}
 
-	s3Client, err := minio.New(endpoint, accessKeyID, secretAccessKey, true)
-	if err != nil {
-		return nil, "", fmt.Errorf("error creating s3 client: %w", err)
+	wrappedCreator := reconciling.SecretObjectWrapper(creator)
+	wrappedCreator = reconciling.OwnerRefWrapper(GetEtcdRestoreRef(restore))(wrappedCreator)
+
+	secretName := fmt.Sprintf("%s-backupdecryption-%s", restore.Name, rand.String(10))
+
+	if err := reconciling.EnsureNamedObject(
+		ctx,
+		types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: secretName},
+		wrappedCreator, client, &corev1.Secret{}, false); err != nil {
+		return nil, fmt.Errorf("failed to ensure Secret %s: %w", secretName, err)
 	}
-	s3Client.SetAppInfo("kubermatic", "v0.1")
 
-	s3Client.SetCustomTransport(&http.Transport{
-		TLSClientConfig:    &tls.Config{RootCAs: pool},
-		DisableCompression: true,
-	})
+	oldRestore := restore.DeepCopy()
+	restore.Spec.BackupDecryptionKeysSecret = secretName
+	if err := client.Patch(ctx, restore, ctrlruntimeclient.MergeFrom(oldRestore)); err != nil {
+		return nil, fmt.Errorf("failed to write etcdrestore.backupDecryptionKeysSecret: %w", err)
+	}
 
-	return s3Client, bucketName, nil
+	return keys, nil
 }
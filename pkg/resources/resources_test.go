@@ -17,14 +17,20 @@ limitations under the License.
 package resources
 
 import (
+	"context"
 	"testing"
 
 	"github.com/go-test/deep"
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources/certificates/triple"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestInClusterApiserverIP(t *testing.T) {
@@ -579,3 +585,104 @@ func TestSetResourceRequirementsDoesNotChangeDefaults(t *testing.T) {
 		t.Fatalf("The defaults have changed: %v\n", diff)
 	}
 }
+
+func TestGetEtcdRestoreBackupStoreCredentials(t *testing.T) {
+	ca, err := triple.NewCA("test")
+	if err != nil {
+		t.Fatalf("failed to create CA: %v", err)
+	}
+
+	cluster := &kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Status:     kubermaticv1.ClusterStatus{NamespaceName: "cluster-test"},
+	}
+	caBundle := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceSystem, Name: BackupCABundleConfigMapName(cluster)},
+		Data:       map[string]string{CABundleConfigMapKey: string(triple.EncodeCertPEM(ca.Cert))},
+	}
+	defaultCredentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceSystem, Name: EtcdRestoreS3CredentialsSecret},
+		Data:       map[string][]byte{EtcdRestoreS3AccessKeyIDKey: []byte("default-id"), EtcdRestoreS3SecretKeyAccessKeyKey: []byte("default-secret")},
+	}
+	defaultSettings := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceSystem, Name: EtcdRestoreS3SettingsConfigMap},
+		Data:       map[string]string{EtcdRestoreS3BucketNameKey: "default-bucket", EtcdRestoreS3EndpointKey: "s3.example.com"},
+	}
+	destinationCredentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceSystem, Name: "custom-credentials"},
+		Data:       map[string][]byte{EtcdRestoreS3AccessKeyIDKey: []byte("custom-id"), EtcdRestoreS3SecretKeyAccessKeyKey: []byte("custom-secret")},
+	}
+
+	testCases := []struct {
+		name                string
+		destination         *kubermaticv1.BackupDestination
+		expectedAccessKeyID string
+		expectedErr         bool
+	}{
+		{
+			name:                "default S3 location",
+			expectedAccessKeyID: "default-id",
+		},
+		{
+			name: "S3 destination with credentials",
+			destination: &kubermaticv1.BackupDestination{
+				S3: &kubermaticv1.S3BackupDestination{Endpoint: "s3.custom.example.com", BucketName: "custom", CredentialsSecretName: "custom-credentials"},
+			},
+			expectedAccessKeyID: "custom-id",
+		},
+		{
+			name: "S3 destination with the default credentials",
+			destination: &kubermaticv1.BackupDestination{
+				S3: &kubermaticv1.S3BackupDestination{Endpoint: "s3.custom.example.com", BucketName: "custom"},
+			},
+			expectedAccessKeyID: "default-id",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			restore := &kubermaticv1.EtcdRestore{
+				ObjectMeta: metav1.ObjectMeta{Namespace: cluster.Status.NamespaceName, Name: "restore"},
+				Spec: kubermaticv1.EtcdRestoreSpec{
+					BackupName:  "backup",
+					Destination: tc.destination,
+				},
+			}
+			client := ctrlruntimefakeclient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+				restore, caBundle, defaultCredentials, defaultSettings, destinationCredentials,
+			).Build()
+
+			store, err := GetEtcdRestoreBackupStore(ctx, restore, true, client, cluster)
+			if tc.expectedErr {
+				if err == nil {
+					t.Fatal("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if store == nil {
+				t.Fatal("expected a backup store, got none")
+			}
+
+			// the credentials are copied into the cluster namespace for the etcd-launcher, which
+			// gets the backup store of the same restore without creating the secret
+			if restore.Spec.BackupDownloadCredentialsSecret == "" {
+				t.Fatal("expected the backup download credentials secret to be set")
+			}
+			secret := &corev1.Secret{}
+			if err := client.Get(ctx, types.NamespacedName{Namespace: cluster.Status.NamespaceName, Name: restore.Spec.BackupDownloadCredentialsSecret}, secret); err != nil {
+				t.Fatalf("failed to get backup download credentials secret: %v", err)
+			}
+			if accessKeyID := string(secret.Data[EtcdRestoreS3AccessKeyIDKey]); accessKeyID != tc.expectedAccessKeyID {
+				t.Errorf("expected access key ID %q, got %q", tc.expectedAccessKeyID, accessKeyID)
+			}
+
+			if _, err := GetEtcdRestoreBackupStore(ctx, restore, false, client, cluster); err != nil {
+				t.Errorf("failed to get backup store from the backup download credentials secret: %v", err)
+			}
+		})
+	}
+}
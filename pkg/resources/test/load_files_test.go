@@ -104,9 +104,10 @@ func checkTestResult(t *testing.T, resFile string, testObj interface{}) {
 }
 
 type testCase struct {
-	provider string
-	version  semver.Version
-	features map[string]bool
+	provider  string
+	version   semver.Version
+	features  map[string]bool
+	dualStack bool
 }
 
 // name returns the name for the current test case.
@@ -122,6 +123,9 @@ func (tc testCase) name() string {
 			b.WriteString(f)
 		}
 	}
+	if tc.dualStack {
+		b.WriteString("-dualstack")
+	}
 	return b.String()
 }
 
@@ -141,6 +145,9 @@ func (tc testCase) fixturePath(resType, resName string) string {
 			b.WriteString(f)
 		}
 	}
+	if tc.dualStack {
+		b.WriteString("-dualstack")
+	}
 	return b.String()
 }
 
@@ -263,549 +270,574 @@ func TestLoadFiles(t *testing.T) {
 	kubermaticVersions := kubermatic.NewFakeVersions()
 	caBundle := certificates.NewFakeCABundle()
 
+	testCases := []testCase{}
 	for _, ver := range versions {
-		for prov, cloudspec := range clouds {
+		for prov := range clouds {
 			for _, features := range featureSets {
-				tc := testCase{
+				testCases = append(testCases, testCase{
 					provider: prov,
 					version:  *ver.Version,
 					features: features,
-				}
-				t.Run(tc.name(), func(t *testing.T) {
-					cluster := &kubermaticv1.Cluster{
+				})
+			}
+			// dual-stack requires Kubernetes 1.21
+			if ver.Version.Minor() >= 21 {
+				testCases = append(testCases, testCase{
+					provider:  prov,
+					version:   *ver.Version,
+					features:  map[string]bool{},
+					dualStack: true,
+				})
+			}
+		}
+	}
+
+	for _, tc := range testCases {
+		serviceCIDRBlocks, podCIDRBlocks := []string{"10.240.16.0/20"}, []string{"172.25.0.0/16"}
+		if tc.dualStack {
+			serviceCIDRBlocks = append(serviceCIDRBlocks, "fd01::/108")
+			podCIDRBlocks = append(podCIDRBlocks, "fd00::/56")
+		}
+		t.Run(tc.name(), func(t *testing.T) {
+			cluster := &kubermaticv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "de-test-01",
+					UID:  types.UID("1234567890"),
+					Labels: map[string]string{
+						"my-label": "my-value",
+					},
+				},
+				Spec: kubermaticv1.ClusterSpec{
+					Features:       tc.features,
+					ExposeStrategy: kubermaticv1.ExposeStrategyLoadBalancer,
+					Cloud:          clouds[tc.provider],
+					Version:        *ksemver.NewSemverOrDie(tc.version.String()),
+					ClusterNetwork: kubermaticv1.ClusterNetworkingConfig{
+						Services: kubermaticv1.NetworkRanges{
+							CIDRBlocks: serviceCIDRBlocks,
+						},
+						Pods: kubermaticv1.NetworkRanges{
+							CIDRBlocks: podCIDRBlocks,
+						},
+						DNSDomain: "cluster.local",
+						ProxyMode: resources.IPVSProxyMode,
+					},
+					MachineNetworks: []kubermaticv1.MachineNetworkingConfig{
+						{
+							CIDR: "192.168.1.1/24",
+							DNSServers: []string{
+								"8.8.8.8",
+							},
+							Gateway: "192.168.1.1",
+						},
+					},
+					ServiceAccount: &kubermaticv1.ServiceAccountSettings{
+						TokenVolumeProjectionEnabled: true,
+					},
+					MLA: &kubermaticv1.MLASettings{
+						MonitoringEnabled: true,
+						LoggingEnabled:    false,
+					},
+				},
+				Address: kubermaticv1.ClusterAddress{
+					ExternalName: "jh8j81chn.europe-west3-c.dev.kubermatic.io",
+					IP:           "35.198.93.90",
+					AdminToken:   "6hzr76.u8txpkk4vhgmtgdp",
+					InternalName: "apiserver-external.cluster-de-test-01.svc.cluster.local.",
+					URL:          "https://jh8j81chn.europe-west3-c.dev.kubermatic.io:30000",
+					Port:         30000,
+				},
+				Status: kubermaticv1.ClusterStatus{
+					NamespaceName: "cluster-de-test-01",
+				},
+			}
+
+			caBundleConfigMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					ResourceVersion: "123456",
+					Name:            resources.CABundleConfigMapName,
+					Namespace:       cluster.Status.NamespaceName,
+				},
+			}
+
+			if tc.features[kubermaticv1.ClusterFeatureExternalCloudProvider] && !cloudcontroller.ExternalCloudControllerFeatureSupported(dc, cluster) {
+				t.Logf("Unsupported configuration")
+				return
+			}
+
+			dynamicClient := ctrlruntimefakeclient.
+				NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(
+					&corev1.Secret{
 						ObjectMeta: metav1.ObjectMeta{
-							Name: "de-test-01",
-							UID:  types.UID("1234567890"),
-							Labels: map[string]string{
-								"my-label": "my-value",
-							},
+							ResourceVersion: "123456",
+							Name:            metricsserver.ServingCertSecretName,
+							Namespace:       cluster.Status.NamespaceName,
 						},
-						Spec: kubermaticv1.ClusterSpec{
-							Features:       features,
-							ExposeStrategy: kubermaticv1.ExposeStrategyLoadBalancer,
-							Cloud:          cloudspec,
-							Version:        *ksemver.NewSemverOrDie(ver.Version.String()),
-							ClusterNetwork: kubermaticv1.ClusterNetworkingConfig{
-								Services: kubermaticv1.NetworkRanges{
-									CIDRBlocks: []string{"10.240.16.0/20"},
-								},
-								Pods: kubermaticv1.NetworkRanges{
-									CIDRBlocks: []string{"172.25.0.0/16"},
-								},
-								DNSDomain: "cluster.local",
-								ProxyMode: resources.IPVSProxyMode,
-							},
-							MachineNetworks: []kubermaticv1.MachineNetworkingConfig{
-								{
-									CIDR: "192.168.1.1/24",
-									DNSServers: []string{
-										"8.8.8.8",
-									},
-									Gateway: "192.168.1.1",
-								},
-							},
-							ServiceAccount: &kubermaticv1.ServiceAccountSettings{
-								TokenVolumeProjectionEnabled: true,
-							},
-							MLA: &kubermaticv1.MLASettings{
-								MonitoringEnabled: true,
-								LoggingEnabled:    false,
-							},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.TokensSecretName,
+							Namespace:       cluster.Status.NamespaceName,
 						},
-						Address: kubermaticv1.ClusterAddress{
-							ExternalName: "jh8j81chn.europe-west3-c.dev.kubermatic.io",
-							IP:           "35.198.93.90",
-							AdminToken:   "6hzr76.u8txpkk4vhgmtgdp",
-							InternalName: "apiserver-external.cluster-de-test-01.svc.cluster.local.",
-							URL:          "https://jh8j81chn.europe-west3-c.dev.kubermatic.io:30000",
-							Port:         30000,
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.ServiceAccountKeySecretName,
+							Namespace:       cluster.Status.NamespaceName,
 						},
-						Status: kubermaticv1.ClusterStatus{
-							NamespaceName: "cluster-de-test-01",
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.ApiserverTLSSecretName,
+							Namespace:       cluster.Status.NamespaceName,
 						},
-					}
-
-					caBundleConfigMap := &corev1.ConfigMap{
+					},
+					&corev1.Secret{
 						ObjectMeta: metav1.ObjectMeta{
 							ResourceVersion: "123456",
-							Name:            resources.CABundleConfigMapName,
+							Name:            resources.KubeletClientCertificatesSecretName,
 							Namespace:       cluster.Status.NamespaceName,
 						},
-					}
-
-					if features[kubermaticv1.ClusterFeatureExternalCloudProvider] && !cloudcontroller.ExternalCloudControllerFeatureSupported(dc, cluster) {
-						t.Logf("Unsupported configuration")
-						return
-					}
-
-					dynamicClient := ctrlruntimefakeclient.
-						NewClientBuilder().
-						WithScheme(scheme.Scheme).
-						WithObjects(
-							&corev1.Secret{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            metricsserver.ServingCertSecretName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.Secret{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.TokensSecretName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.Secret{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.ServiceAccountKeySecretName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.Secret{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.ApiserverTLSSecretName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.Secret{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.KubeletClientCertificatesSecretName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.Secret{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.CASecretName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.Secret{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.OpenVPNCASecretName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.Secret{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.ApiserverEtcdClientCertificateSecretName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.Secret{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.ApiserverFrontProxyClientCertificateSecretName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.Secret{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.EtcdTLSCertificateSecretName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.Secret{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.MachineControllerKubeconfigSecretName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.Secret{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.OpenVPNServerCertificatesSecretName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.Secret{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.OpenVPNClientCertificatesSecretName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.Secret{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.ControllerManagerKubeconfigSecretName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.Secret{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.KubeStateMetricsKubeconfigSecretName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.Secret{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.SchedulerKubeconfigSecretName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.Secret{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.KubeletDnatControllerKubeconfigSecretName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.Secret{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.FrontProxyCASecretName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.Secret{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.MetricsServerKubeconfigSecretName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.Secret{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.PrometheusApiserverClientCertificateSecretName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.Secret{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.MachineControllerWebhookServingCertSecretName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.Secret{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.InternalUserClusterAdminKubeconfigSecretName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.Secret{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.KubernetesDashboardKubeconfigSecretName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.Secret{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.UserSSHKeys,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.Secret{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.GatekeeperWebhookServerCertSecretName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.Secret{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.AdminKubeconfigSecretName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.ConfigMap{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.OpenVPNClientConfigsConfigMapName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.ConfigMap{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.CloudConfigConfigMapName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							caBundleConfigMap,
-							&corev1.ConfigMap{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.PrometheusConfigConfigMapName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.ConfigMap{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.DNSResolverConfigMapName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.ConfigMap{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.AuditConfigMapName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.ConfigMap{
-								ObjectMeta: metav1.ObjectMeta{
-									ResourceVersion: "123456",
-									Name:            resources.AdmissionControlConfigMapName,
-									Namespace:       cluster.Status.NamespaceName,
-								},
-							},
-							&corev1.Service{
-								ObjectMeta: metav1.ObjectMeta{
-									Name:      resources.ApiserverServiceName,
-									Namespace: cluster.Status.NamespaceName,
-								},
-								Spec: corev1.ServiceSpec{
-									Ports: []corev1.ServicePort{
-										{
-											NodePort: 30000,
-										},
-									},
-									ClusterIP: "192.0.2.10",
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.CASecretName,
+							Namespace:       cluster.Status.NamespaceName,
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.OpenVPNCASecretName,
+							Namespace:       cluster.Status.NamespaceName,
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.ApiserverEtcdClientCertificateSecretName,
+							Namespace:       cluster.Status.NamespaceName,
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.ApiserverFrontProxyClientCertificateSecretName,
+							Namespace:       cluster.Status.NamespaceName,
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.EtcdTLSCertificateSecretName,
+							Namespace:       cluster.Status.NamespaceName,
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.MachineControllerKubeconfigSecretName,
+							Namespace:       cluster.Status.NamespaceName,
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.OpenVPNServerCertificatesSecretName,
+							Namespace:       cluster.Status.NamespaceName,
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.OpenVPNClientCertificatesSecretName,
+							Namespace:       cluster.Status.NamespaceName,
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.ControllerManagerKubeconfigSecretName,
+							Namespace:       cluster.Status.NamespaceName,
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.KubeStateMetricsKubeconfigSecretName,
+							Namespace:       cluster.Status.NamespaceName,
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.SchedulerKubeconfigSecretName,
+							Namespace:       cluster.Status.NamespaceName,
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.KubeletDnatControllerKubeconfigSecretName,
+							Namespace:       cluster.Status.NamespaceName,
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.FrontProxyCASecretName,
+							Namespace:       cluster.Status.NamespaceName,
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.MetricsServerKubeconfigSecretName,
+							Namespace:       cluster.Status.NamespaceName,
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.PrometheusApiserverClientCertificateSecretName,
+							Namespace:       cluster.Status.NamespaceName,
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.MachineControllerWebhookServingCertSecretName,
+							Namespace:       cluster.Status.NamespaceName,
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.UserClusterControllerWebhookServingCertSecretName,
+							Namespace:       cluster.Status.NamespaceName,
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.InternalUserClusterAdminKubeconfigSecretName,
+							Namespace:       cluster.Status.NamespaceName,
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.KubernetesDashboardKubeconfigSecretName,
+							Namespace:       cluster.Status.NamespaceName,
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.UserSSHKeys,
+							Namespace:       cluster.Status.NamespaceName,
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.GatekeeperWebhookServerCertSecretName,
+							Namespace:       cluster.Status.NamespaceName,
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.AdminKubeconfigSecretName,
+							Namespace:       cluster.Status.NamespaceName,
+						},
+					},
+					&corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.OpenVPNClientConfigsConfigMapName,
+							Namespace:       cluster.Status.NamespaceName,
+						},
+					},
+					&corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.CloudConfigConfigMapName,
+							Namespace:       cluster.Status.NamespaceName,
+						},
+					},
+					caBundleConfigMap,
+					&corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.PrometheusConfigConfigMapName,
+							Namespace:       cluster.Status.NamespaceName,
+						},
+					},
+					&corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.DNSResolverConfigMapName,
+							Namespace:       cluster.Status.NamespaceName,
+						},
+					},
+					&corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.AuditConfigMapName,
+							Namespace:       cluster.Status.NamespaceName,
+						},
+					},
+					&corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{
+							ResourceVersion: "123456",
+							Name:            resources.AdmissionControlConfigMapName,
+							Namespace:       cluster.Status.NamespaceName,
+						},
+					},
+					&corev1.Service{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      resources.ApiserverServiceName,
+							Namespace: cluster.Status.NamespaceName,
+						},
+						Spec: corev1.ServiceSpec{
+							Ports: []corev1.ServicePort{
+								{
+									NodePort: 30000,
 								},
 							},
-							&corev1.Service{
-								ObjectMeta: metav1.ObjectMeta{
-									Name:      resources.OpenVPNServerServiceName,
-									Namespace: cluster.Status.NamespaceName,
-								},
-								Spec: corev1.ServiceSpec{
-									Ports: []corev1.ServicePort{
-										{
-											NodePort: 30003,
-										},
-									},
-									ClusterIP: "192.0.2.13",
+							ClusterIP: "192.0.2.10",
+						},
+					},
+					&corev1.Service{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      resources.OpenVPNServerServiceName,
+							Namespace: cluster.Status.NamespaceName,
+						},
+						Spec: corev1.ServiceSpec{
+							Ports: []corev1.ServicePort{
+								{
+									NodePort: 30003,
 								},
 							},
-							&corev1.Service{
-								ObjectMeta: metav1.ObjectMeta{
-									Name:      resources.DNSResolverServiceName,
-									Namespace: cluster.Status.NamespaceName,
-								},
-								Spec: corev1.ServiceSpec{
-									Ports: []corev1.ServicePort{
-										{
-											NodePort: 30003,
-										},
-									},
-									ClusterIP: "192.0.2.14",
+							ClusterIP: "192.0.2.13",
+						},
+					},
+					&corev1.Service{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      resources.DNSResolverServiceName,
+							Namespace: cluster.Status.NamespaceName,
+						},
+						Spec: corev1.ServiceSpec{
+							Ports: []corev1.ServicePort{
+								{
+									NodePort: 30003,
 								},
 							},
-							&corev1.Service{
-								ObjectMeta: metav1.ObjectMeta{
-									Name:      resources.MLAGatewayExternalServiceName,
-									Namespace: cluster.Status.NamespaceName,
-								},
-								Spec: corev1.ServiceSpec{
-									Ports: []corev1.ServicePort{
-										{
-											NodePort: 30005,
-										},
-									},
-									ClusterIP: "192.0.2.15",
+							ClusterIP: "192.0.2.14",
+						},
+					},
+					&corev1.Service{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      resources.MLAGatewayExternalServiceName,
+							Namespace: cluster.Status.NamespaceName,
+						},
+						Spec: corev1.ServiceSpec{
+							Ports: []corev1.ServicePort{
+								{
+									NodePort: 30005,
 								},
 							},
-						).
-						Build()
-
-					var group wait.Group
-					defer group.Wait()
-					stopCh := make(chan struct{})
-					defer func() {
-						close(stopCh)
-					}()
-
-					promTmpFile, err := ioutil.TempFile("", "kubermatic")
-					if err != nil {
-						t.Fatalf("couldn't create temp file, see: %v", err)
-					}
-
-					promTmpFilePath := promTmpFile.Name()
-					_, err = promTmpFile.WriteString(`- job_name: custom-test-config
+							ClusterIP: "192.0.2.15",
+						},
+					},
+				).
+				Build()
+
+			var group wait.Group
+			defer group.Wait()
+			stopCh := make(chan struct{})
+			defer func() {
+				close(stopCh)
+			}()
+
+			promTmpFile, err := ioutil.TempFile("", "kubermatic")
+			if err != nil {
+				t.Fatalf("couldn't create temp file, see: %v", err)
+			}
+
+			promTmpFilePath := promTmpFile.Name()
+			_, err = promTmpFile.WriteString(`- job_name: custom-test-config
   scheme: https
   metrics_path: '/metrics'
   static_configs:
   - targets:
     - 'foo.bar:12345'
 `)
-					if err != nil {
-						t.Fatalf("couldn't write to temp file, see: %v", err)
-					}
-					defer (func() {
-						err = os.Remove(promTmpFilePath)
-						if err != nil {
-							t.Fatalf("couldn't delete temp file, see: %v", err)
-						}
-					})()
-
-					ctx := context.Background()
-					data := resources.NewTemplateDataBuilder().
-						WithContext(ctx).
-						WithClient(dynamicClient).
-						WithCluster(cluster).
-						WithDatacenter(dc).
-						WithSeed(&kubermaticv1.Seed{
-							ObjectMeta: metav1.ObjectMeta{Name: "testdc"},
-							Spec: kubermaticv1.SeedSpec{
-								ProxySettings: &kubermaticv1.ProxySettings{
-									HTTPProxy: kubermaticv1.NewProxyValue("http://my-corp"),
-								},
-								MLA: &kubermaticv1.SeedMLASettings{
-									UserClusterMLAEnabled: true,
-								},
-							},
-						}).
-						WithNodeAccessNetwork("192.0.2.0/24").
-						WithEtcdDiskSize(resource.MustParse("5Gi")).
-						WithBackupPeriod(20 * time.Minute).
-						WithMonitoringScrapeAnnotationPrefix("kubermatic_io_monitoring").
-						WithInClusterPrometheusScrapingConfigsFile(promTmpFilePath).
-						WithUserClusterMLAEnabled(true).
-						WithCABundle(caBundle).
-						WithOIDCIssuerURL("https://dev.kubermatic.io/dex").
-						WithOIDCIssuerClientID("kubermaticIssuer").
-						WithNodeLocalDNSCacheEnabled(true).
-						WithKubermaticImage("quay.io/kubermatic/kubermatic").
-						WithEtcdLauncherImage("quay.io/kubermatic/etcd-launcher").
-						WithDnatControllerImage("quay.io/kubermatic/kubeletdnat-controller").
-						WithVersions(kubermaticVersions).
-						Build()
-
-					var deploymentCreators []reconciling.NamedDeploymentCreatorGetter
-					deploymentCreators = append(deploymentCreators, kubernetescontroller.GetDeploymentCreators(data, true)...)
-					deploymentCreators = append(deploymentCreators, monitoringcontroller.GetDeploymentCreators(data)...)
-					for _, create := range deploymentCreators {
-						_, creator := create()
-						res, err := creator(&appsv1.Deployment{})
-						if err != nil {
-							t.Fatalf("failed to create Deployment: %v", err)
-						}
-						fixturePath := tc.fixturePath("deployment", res.Name)
-
-						verifyContainerResources(fmt.Sprintf("Deployment/%s", res.Name), res.Spec.Template, t)
-
-						checkTestResult(t, fixturePath, res)
-					}
-
-					var namedConfigMapCreatorGetters []reconciling.NamedConfigMapCreatorGetter
-					namedConfigMapCreatorGetters = append(namedConfigMapCreatorGetters, kubernetescontroller.GetConfigMapCreators(data)...)
-					namedConfigMapCreatorGetters = append(namedConfigMapCreatorGetters, monitoringcontroller.GetConfigMapCreators(data)...)
-					for _, namedGetter := range namedConfigMapCreatorGetters {
-						name, create := namedGetter()
-						res, err := create(&corev1.ConfigMap{})
-						if err != nil {
-							t.Fatalf("failed to create ConfigMap: %v", err)
-						}
-
-						checkTestResult(t, tc.fixturePath("configmap", name), res)
-					}
-
-					serviceCreators := kubernetescontroller.GetServiceCreators(data)
-					for _, creatorGetter := range serviceCreators {
-						name, create := creatorGetter()
-						res, err := create(&corev1.Service{})
-						if err != nil {
-							t.Fatalf("failed to create Service: %v", err)
-						}
-
-						checkTestResult(t, tc.fixturePath("service", name), res)
-					}
-
-					var statefulSetCreators []reconciling.NamedStatefulSetCreatorGetter
-					statefulSetCreators = append(statefulSetCreators, kubernetescontroller.GetStatefulSetCreators(data, false)...)
-					statefulSetCreators = append(statefulSetCreators, monitoringcontroller.GetStatefulSetCreators(data)...)
-					for _, creatorGetter := range statefulSetCreators {
-						_, create := creatorGetter()
-						res, err := create(&appsv1.StatefulSet{})
-						if err != nil {
-							t.Fatalf("failed to create StatefulSet: %v", err)
-						}
-
-						fixturePath := tc.fixturePath("statefulset", res.Name)
-						if err != nil {
-							t.Fatalf("failed to create StatefulSet for %s: %v", fixturePath, err)
-						}
-
-						// Verify that every StatefulSet has the ImagePullSecret set
-						if len(res.Spec.Template.Spec.ImagePullSecrets) == 0 {
-							t.Errorf("StatefulSet %s is missing the ImagePullSecret on the PodTemplate", res.Name)
-						}
-
-						verifyContainerResources(fmt.Sprintf("StatefulSet/%s", res.Name), res.Spec.Template, t)
-
-						checkTestResult(t, fixturePath, res)
-					}
-
-					for _, creatorGetter := range kubernetescontroller.GetPodDisruptionBudgetCreators(data) {
-						name, create := creatorGetter()
-						res, err := create(&policyv1beta1.PodDisruptionBudget{})
-						if err != nil {
-							t.Fatalf("failed to create PodDisruptionBudget: %v", err)
-						}
-
-						fixturePath := tc.fixturePath("poddisruptionbudget", name)
-						if err != nil {
-							t.Fatalf("failed to create PodDisruptionBudget for %s: %v", fixturePath, err)
-						}
-
-						checkTestResult(t, fixturePath, res)
-					}
-
-					for _, creatorGetter := range kubernetescontroller.GetCronJobCreators(data) {
-						_, create := creatorGetter()
-						res, err := create(&batchv1beta1.CronJob{})
-						if err != nil {
-							t.Fatalf("failed to create CronJob: %v", err)
-						}
-
-						fixturePath := tc.fixturePath("cronjob", res.Name)
-						if err != nil {
-							t.Fatalf("failed to create CronJob for %s: %v", fixturePath, err)
-						}
-
-						// Verify that every CronJob has the ImagePullSecret set
-						if len(res.Spec.JobTemplate.Spec.Template.Spec.ImagePullSecrets) == 0 {
-							t.Errorf("CronJob %s is missing the ImagePullSecret on the PodTemplate", res.Name)
-						}
-
-						checkTestResult(t, fixturePath, res)
-					}
-
-					for _, creatorGetter := range kubernetescontroller.GetEtcdBackupConfigCreators(data) {
-						_, create := creatorGetter()
-						res, err := create(&kubermaticv1.EtcdBackupConfig{})
-						if err != nil {
-							t.Fatalf("failed to create EtcdBackupConfig: %v", err)
-						}
-
-						fixturePath := tc.fixturePath("etcdbackupconfig", res.Name)
-						if err != nil {
-							t.Fatalf("failed to create EtcdBackupConfig for %s: %v", fixturePath, err)
-						}
-
-						checkTestResult(t, fixturePath, res)
-					}
-				})
+			if err != nil {
+				t.Fatalf("couldn't write to temp file, see: %v", err)
 			}
-		}
+			defer (func() {
+				err = os.Remove(promTmpFilePath)
+				if err != nil {
+					t.Fatalf("couldn't delete temp file, see: %v", err)
+				}
+			})()
+
+			ctx := context.Background()
+			data := resources.NewTemplateDataBuilder().
+				WithContext(ctx).
+				WithClient(dynamicClient).
+				WithCluster(cluster).
+				WithDatacenter(dc).
+				WithSeed(&kubermaticv1.Seed{
+					ObjectMeta: metav1.ObjectMeta{Name: "testdc"},
+					Spec: kubermaticv1.SeedSpec{
+						ProxySettings: &kubermaticv1.ProxySettings{
+							HTTPProxy: kubermaticv1.NewProxyValue("http://my-corp"),
+						},
+						MLA: &kubermaticv1.SeedMLASettings{
+							UserClusterMLAEnabled: true,
+						},
+					},
+				}).
+				WithNodeAccessNetwork("192.0.2.0/24").
+				WithEtcdDiskSize(resource.MustParse("5Gi")).
+				WithBackupPeriod(20 * time.Minute).
+				WithMonitoringScrapeAnnotationPrefix("kubermatic_io_monitoring").
+				WithInClusterPrometheusScrapingConfigsFile(promTmpFilePath).
+				WithUserClusterMLAEnabled(true).
+				WithCABundle(caBundle).
+				WithOIDCIssuerURL("https://dev.kubermatic.io/dex").
+				WithOIDCIssuerClientID("kubermaticIssuer").
+				WithNodeLocalDNSCacheEnabled(true).
+				WithKubermaticImage("quay.io/kubermatic/kubermatic").
+				WithEtcdLauncherImage("quay.io/kubermatic/etcd-launcher").
+				WithDnatControllerImage("quay.io/kubermatic/kubeletdnat-controller").
+				WithVersions(kubermaticVersions).
+				Build()
+
+			var deploymentCreators []reconciling.NamedDeploymentCreatorGetter
+			deploymentCreators = append(deploymentCreators, kubernetescontroller.GetDeploymentCreators(data, true)...)
+			deploymentCreators = append(deploymentCreators, monitoringcontroller.GetDeploymentCreators(data)...)
+			for _, create := range deploymentCreators {
+				_, creator := create()
+				res, err := creator(&appsv1.Deployment{})
+				if err != nil {
+					t.Fatalf("failed to create Deployment: %v", err)
+				}
+				fixturePath := tc.fixturePath("deployment", res.Name)
+
+				verifyContainerResources(fmt.Sprintf("Deployment/%s", res.Name), res.Spec.Template, t)
+
+				checkTestResult(t, fixturePath, res)
+			}
+
+			var namedConfigMapCreatorGetters []reconciling.NamedConfigMapCreatorGetter
+			namedConfigMapCreatorGetters = append(namedConfigMapCreatorGetters, kubernetescontroller.GetConfigMapCreators(data)...)
+			namedConfigMapCreatorGetters = append(namedConfigMapCreatorGetters, monitoringcontroller.GetConfigMapCreators(data)...)
+			for _, namedGetter := range namedConfigMapCreatorGetters {
+				name, create := namedGetter()
+				res, err := create(&corev1.ConfigMap{})
+				if err != nil {
+					t.Fatalf("failed to create ConfigMap: %v", err)
+				}
+
+				checkTestResult(t, tc.fixturePath("configmap", name), res)
+			}
+
+			serviceCreators := kubernetescontroller.GetServiceCreators(data)
+			for _, creatorGetter := range serviceCreators {
+				name, create := creatorGetter()
+				res, err := create(&corev1.Service{})
+				if err != nil {
+					t.Fatalf("failed to create Service: %v", err)
+				}
+
+				checkTestResult(t, tc.fixturePath("service", name), res)
+			}
+
+			var statefulSetCreators []reconciling.NamedStatefulSetCreatorGetter
+			statefulSetCreators = append(statefulSetCreators, kubernetescontroller.GetStatefulSetCreators(data, false)...)
+			statefulSetCreators = append(statefulSetCreators, monitoringcontroller.GetStatefulSetCreators(data)...)
+			for _, creatorGetter := range statefulSetCreators {
+				_, create := creatorGetter()
+				res, err := create(&appsv1.StatefulSet{})
+				if err != nil {
+					t.Fatalf("failed to create StatefulSet: %v", err)
+				}
+
+				fixturePath := tc.fixturePath("statefulset", res.Name)
+				if err != nil {
+					t.Fatalf("failed to create StatefulSet for %s: %v", fixturePath, err)
+				}
+
+				// Verify that every StatefulSet has the ImagePullSecret set
+				if len(res.Spec.Template.Spec.ImagePullSecrets) == 0 {
+					t.Errorf("StatefulSet %s is missing the ImagePullSecret on the PodTemplate", res.Name)
+				}
+
+				verifyContainerResources(fmt.Sprintf("StatefulSet/%s", res.Name), res.Spec.Template, t)
+
+				checkTestResult(t, fixturePath, res)
+			}
+
+			for _, creatorGetter := range kubernetescontroller.GetPodDisruptionBudgetCreators(data) {
+				name, create := creatorGetter()
+				res, err := create(&policyv1beta1.PodDisruptionBudget{})
+				if err != nil {
+					t.Fatalf("failed to create PodDisruptionBudget: %v", err)
+				}
+
+				fixturePath := tc.fixturePath("poddisruptionbudget", name)
+				if err != nil {
+					t.Fatalf("failed to create PodDisruptionBudget for %s: %v", fixturePath, err)
+				}
+
+				checkTestResult(t, fixturePath, res)
+			}
+
+			for _, creatorGetter := range kubernetescontroller.GetCronJobCreators(data) {
+				_, create := creatorGetter()
+				res, err := create(&batchv1beta1.CronJob{})
+				if err != nil {
+					t.Fatalf("failed to create CronJob: %v", err)
+				}
+
+				fixturePath := tc.fixturePath("cronjob", res.Name)
+				if err != nil {
+					t.Fatalf("failed to create CronJob for %s: %v", fixturePath, err)
+				}
+
+				// Verify that every CronJob has the ImagePullSecret set
+				if len(res.Spec.JobTemplate.Spec.Template.Spec.ImagePullSecrets) == 0 {
+					t.Errorf("CronJob %s is missing the ImagePullSecret on the PodTemplate", res.Name)
+				}
+
+				checkTestResult(t, fixturePath, res)
+			}
+
+			for _, creatorGetter := range kubernetescontroller.GetEtcdBackupConfigCreators(data) {
+				_, create := creatorGetter()
+				res, err := create(&kubermaticv1.EtcdBackupConfig{})
+				if err != nil {
+					t.Fatalf("failed to create EtcdBackupConfig: %v", err)
+				}
+
+				fixturePath := tc.fixturePath("etcdbackupconfig", res.Name)
+				if err != nil {
+					t.Fatalf("failed to create EtcdBackupConfig for %s: %v", fixturePath, err)
+				}
+
+				checkTestResult(t, fixturePath, res)
+			}
+		})
 	}
 }
 
@@ -0,0 +1,193 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnelingserver
+
+import (
+	"fmt"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/reconciling"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var (
+	defaultResourceRequirements = map[string]*corev1.ResourceRequirements{
+		name: {
+			Requests: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("16Mi"),
+				corev1.ResourceCPU:    resource.MustParse("10m"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("128Mi"),
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+			},
+		},
+	}
+)
+
+const (
+	name        = "tunneling-server"
+	metricsPort = 8085
+)
+
+type tunnelingServerData interface {
+	Cluster() *kubermaticv1.Cluster
+	GetPodTemplateLabels(string, []corev1.Volume, map[string]string) (map[string]string, error)
+	KubermaticAPIImage() string
+	KubermaticDockerTag() string
+}
+
+// DeploymentCreator returns the function to create and update the tunneling
+// server deployment. It runs a single replica, as the tunnels of the agents are
+// only known to the replica that accepted them. The HTTP CONNECT proxy only
+// accepts the client certificate of the apiserver.
+func DeploymentCreator(data tunnelingServerData) reconciling.NamedDeploymentCreatorGetter {
+	return func() (string, reconciling.DeploymentCreator) {
+		return resources.TunnelingServerDeploymentName, func(dep *appsv1.Deployment) (*appsv1.Deployment, error) {
+			dep.Name = resources.TunnelingServerDeploymentName
+			dep.Labels = resources.BaseAppLabels(name, nil)
+
+			dep.Spec.Replicas = resources.Int32(1)
+			dep.Spec.Selector = &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					resources.AppLabelKey: name,
+				},
+			}
+			dep.Spec.Template.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: resources.ImagePullSecretName}}
+
+			volumes := []corev1.Volume{
+				{
+					Name: resources.TunnelingAgentTokenSecretName,
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: resources.TunnelingAgentTokenSecretName,
+						},
+					},
+				},
+				{
+					Name: resources.TunnelingServerEgressServingCertSecretName,
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: resources.TunnelingServerEgressServingCertSecretName,
+						},
+					},
+				},
+				{
+					Name: resources.CASecretName,
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: resources.CASecretName,
+							Items: []corev1.KeyToPath{
+								{
+									Path: resources.CACertSecretKey,
+									Key:  resources.CACertSecretKey,
+								},
+							},
+						},
+					},
+				},
+			}
+			podLabels, err := data.GetPodTemplateLabels(name, volumes, nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create pod labels: %v", err)
+			}
+
+			dep.Spec.Template.ObjectMeta = metav1.ObjectMeta{
+				Labels: podLabels,
+				Annotations: map[string]string{
+					"prometheus.io/path":   "/metrics",
+					"prometheus.io/port":   fmt.Sprint(metricsPort),
+					"prometheus.io/scrape": "true",
+				},
+			}
+			dep.Spec.Template.Spec.Volumes = volumes
+
+			dep.Spec.Template.Spec.Containers = []corev1.Container{
+				{
+					Name:    name,
+					Image:   data.KubermaticAPIImage() + ":" + data.KubermaticDockerTag(),
+					Command: []string{"/usr/local/bin/tunneling-server"},
+					Args: []string{
+						"-agent-listen-address", fmt.Sprintf("0.0.0.0:%d", resources.TunnelingServerAgentPort),
+						"-egress-listen-address", fmt.Sprintf("0.0.0.0:%d", resources.TunnelingServerEgressPort),
+						"-metrics-listen-address", fmt.Sprintf("0.0.0.0:%d", metricsPort),
+						"-token-file", "/etc/kubernetes/tunneling/" + resources.TunnelingAgentTokenSecretKey,
+						"-tls-cert-file", "/etc/kubernetes/egress-tls/" + resources.ServingCertSecretKey,
+						"-tls-key-file", "/etc/kubernetes/egress-tls/" + resources.ServingCertKeySecretKey,
+						"-client-ca-file", "/etc/kubernetes/pki/ca/" + resources.CACertSecretKey,
+						"-client-common-name", resources.TunnelingServerEgressClientCertUsername,
+					},
+					Ports: []corev1.ContainerPort{
+						{
+							Name:          "agents",
+							ContainerPort: resources.TunnelingServerAgentPort,
+							Protocol:      corev1.ProtocolTCP,
+						},
+						{
+							Name:          "egress",
+							ContainerPort: resources.TunnelingServerEgressPort,
+							Protocol:      corev1.ProtocolTCP,
+						},
+					},
+					ReadinessProbe: &corev1.Probe{
+						Handler: corev1.Handler{
+							HTTPGet: &corev1.HTTPGetAction{
+								Path:   "/healthz",
+								Port:   intstr.FromInt(metricsPort),
+								Scheme: corev1.URISchemeHTTP,
+							},
+						},
+						FailureThreshold: 3,
+						PeriodSeconds:    10,
+						SuccessThreshold: 1,
+						TimeoutSeconds:   5,
+					},
+					VolumeMounts: []corev1.VolumeMount{
+						{
+							Name:      resources.TunnelingAgentTokenSecretName,
+							MountPath: "/etc/kubernetes/tunneling",
+							ReadOnly:  true,
+						},
+						{
+							Name:      resources.TunnelingServerEgressServingCertSecretName,
+							MountPath: "/etc/kubernetes/egress-tls",
+							ReadOnly:  true,
+						},
+						{
+							Name:      resources.CASecretName,
+							MountPath: "/etc/kubernetes/pki/ca",
+							ReadOnly:  true,
+						},
+					},
+				},
+			}
+
+			err = resources.SetResourceRequirements(dep.Spec.Template.Spec.Containers, defaultResourceRequirements, resources.GetOverrides(data.Cluster().Spec.ComponentsOverride), dep.Annotations)
+			if err != nil {
+				return nil, fmt.Errorf("failed to set resource requirements: %v", err)
+			}
+
+			return dep, nil
+		}
+	}
+}
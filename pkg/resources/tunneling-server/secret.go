@@ -0,0 +1,79 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnelingserver
+
+import (
+	"fmt"
+	"net"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/kubernetes"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/certificates/servingcerthelper"
+	"k8c.io/kubermatic/v2/pkg/resources/certificates/triple"
+	"k8c.io/kubermatic/v2/pkg/resources/reconciling"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TokenSecretCreator returns the function to create the secret containing the
+// token shared by the tunneling server and agents. The token is generated once
+// and copied into the user cluster by the user cluster controller manager.
+func TokenSecretCreator() reconciling.NamedSecretCreatorGetter {
+	return func() (string, reconciling.SecretCreator) {
+		return resources.TunnelingAgentTokenSecretName, func(se *corev1.Secret) (*corev1.Secret, error) {
+			if se.Data == nil {
+				se.Data = map[string][]byte{}
+			}
+
+			if _, ok := se.Data[resources.TunnelingAgentTokenSecretKey]; !ok {
+				se.Data[resources.TunnelingAgentTokenSecretKey] = []byte(kubernetes.GenerateToken())
+			}
+
+			return se, nil
+		}
+	}
+}
+
+type egressServingCertData interface {
+	Cluster() *kubermaticv1.Cluster
+	ClusterIPByServiceName(name string) (string, error)
+	GetRootCA() (*triple.KeyPair, error)
+}
+
+// EgressServingCertificateCreator returns the function to create the serving
+// certificate of the HTTP CONNECT proxy of the tunneling server. The apiserver
+// connects to the cluster IP of the egress service, thus the certificate is
+// valid for it.
+func EgressServingCertificateCreator(data egressServingCertData) reconciling.NamedSecretCreatorGetter {
+	return func() (string, reconciling.SecretCreator) {
+		return resources.TunnelingServerEgressServingCertSecretName, func(se *corev1.Secret) (*corev1.Secret, error) {
+			ip, err := data.ClusterIPByServiceName(resources.TunnelingServerEgressServiceName)
+			if err != nil {
+				return nil, err
+			}
+			dnsName := fmt.Sprintf("%s.%s.svc", resources.TunnelingServerEgressServiceName, data.Cluster().Status.NamespaceName)
+
+			_, creator := servingcerthelper.ServingCertSecretCreator(data.GetRootCA,
+				resources.TunnelingServerEgressServingCertSecretName,
+				dnsName,
+				[]string{dnsName},
+				[]net.IP{net.ParseIP(ip)})()
+			return creator(se)
+		}
+	}
+}
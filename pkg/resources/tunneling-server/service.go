@@ -0,0 +1,84 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnelingserver
+
+import (
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/nodeportproxy"
+	"k8c.io/kubermatic/v2/pkg/resources/reconciling"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// ServiceCreator returns the function to reconcile the service exposing the
+// agent port of the tunneling server through the nodeport-proxy.
+func ServiceCreator() reconciling.NamedServiceCreatorGetter {
+	return func() (string, reconciling.ServiceCreator) {
+		return resources.TunnelingServerServiceName, func(se *corev1.Service) (*corev1.Service, error) {
+			se.Name = resources.TunnelingServerServiceName
+			se.Labels = resources.BaseAppLabels(name, nil)
+
+			if se.Annotations == nil {
+				se.Annotations = map[string]string{}
+			}
+			se.Annotations[nodeportproxy.DefaultExposeAnnotationKey] = nodeportproxy.TunnelingType.String()
+
+			se.Spec.Type = corev1.ServiceTypeClusterIP
+			se.Spec.Selector = map[string]string{
+				resources.AppLabelKey: name,
+			}
+			se.Spec.Ports = []corev1.ServicePort{
+				{
+					Name:       "agents",
+					Port:       resources.TunnelingServerAgentPort,
+					Protocol:   corev1.ProtocolTCP,
+					TargetPort: intstr.FromInt(resources.TunnelingServerAgentPort),
+				},
+			}
+
+			return se, nil
+		}
+	}
+}
+
+// EgressServiceCreator returns the function to reconcile the service exposing
+// the HTTP CONNECT proxy of the tunneling server to the apiserver. It must not
+// be exposed by the nodeport-proxy.
+func EgressServiceCreator() reconciling.NamedServiceCreatorGetter {
+	return func() (string, reconciling.ServiceCreator) {
+		return resources.TunnelingServerEgressServiceName, func(se *corev1.Service) (*corev1.Service, error) {
+			se.Name = resources.TunnelingServerEgressServiceName
+			se.Labels = resources.BaseAppLabels(name, nil)
+
+			se.Spec.Type = corev1.ServiceTypeClusterIP
+			se.Spec.Selector = map[string]string{
+				resources.AppLabelKey: name,
+			}
+			se.Spec.Ports = []corev1.ServicePort{
+				{
+					Name:       "egress",
+					Port:       resources.TunnelingServerEgressPort,
+					Protocol:   corev1.ProtocolTCP,
+					TargetPort: intstr.FromInt(resources.TunnelingServerEgressPort),
+				},
+			}
+
+			return se, nil
+		}
+	}
+}
@@ -134,6 +134,10 @@ func DeploymentCreator(data userclusterControllerData) reconciling.NamedDeployme
 				fmt.Sprintf("-enable-ssh-key-agent=%t", data.Cluster().Spec.EnableUserSSHKeyAgent),
 				fmt.Sprintf("-opa-integration=%t", data.Cluster().Spec.OPAIntegration != nil && data.Cluster().Spec.OPAIntegration.Enabled),
 				fmt.Sprintf("-ca-bundle=/opt/ca-bundle/%s", resources.CABundleConfigMapKey),
+				"-admissionwebhook-listen-port", fmt.Sprint(webhookPort),
+				"-admissionwebhook-cert-dir", "/opt/webhook-serving-cert/",
+				"-admissionwebhook-cert-name", resources.ServingCertSecretKey,
+				"-admissionwebhook-key-name", resources.ServingCertKeySecretKey,
 			}, getNetworkArgs(data)...)
 
 			if data.Cluster().Spec.ExposeStrategy == kubermaticv1.ExposeStrategyTunneling {
@@ -181,6 +185,13 @@ func DeploymentCreator(data userclusterControllerData) reconciling.NamedDeployme
 					Image:   data.KubermaticAPIImage() + ":" + data.KubermaticDockerTag(),
 					Command: []string{"/usr/local/bin/user-cluster-controller-manager"},
 					Args:    args,
+					Ports: []corev1.ContainerPort{
+						{
+							Name:          "webhook",
+							ContainerPort: webhookPort,
+							Protocol:      corev1.ProtocolTCP,
+						},
+					},
 					Env: []corev1.EnvVar{
 						{
 							Name: "NAMESPACE",
@@ -216,6 +227,11 @@ func DeploymentCreator(data userclusterControllerData) reconciling.NamedDeployme
 							MountPath: "/opt/ca-bundle/",
 							ReadOnly:  true,
 						},
+						{
+							Name:      resources.UserClusterControllerWebhookServingCertSecretName,
+							MountPath: "/opt/webhook-serving-cert/",
+							ReadOnly:  true,
+						},
 					},
 				},
 			}
@@ -256,6 +272,14 @@ func getVolumes() []corev1.Volume {
 				},
 			},
 		},
+		{
+			Name: resources.UserClusterControllerWebhookServingCertSecretName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: resources.UserClusterControllerWebhookServingCertSecretName,
+				},
+			},
+		},
 	}
 }
 
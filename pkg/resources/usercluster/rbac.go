@@ -115,6 +115,24 @@ func ClusterRoleCreator() (string, reconciling.ClusterRoleCreator) {
 					"update",
 				},
 			},
+			{
+				APIGroups: []string{"kubermatic.k8s.io"},
+				Resources: []string{"groupprojectbindings"},
+				Verbs: []string{
+					"get",
+					"list",
+					"watch",
+				},
+			},
+			{
+				APIGroups: []string{"kubermatic.k8s.io"},
+				Resources: []string{"projects"},
+				Verbs: []string{
+					"get",
+					"list",
+					"watch",
+				},
+			},
 		}
 		return r, nil
 	}
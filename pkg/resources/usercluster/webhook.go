@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usercluster
+
+import (
+	"fmt"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/resources/certificates/servingcerthelper"
+	"k8c.io/kubermatic/v2/pkg/resources/certificates/triple"
+	"k8c.io/kubermatic/v2/pkg/resources/reconciling"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// webhookPort is the port on which the usercluster-controller serves its admission webhook
+const webhookPort = 9443
+
+// WebhookServiceCreator returns the function to reconcile the service exposing the admission webhook of the
+// usercluster-controller to the apiserver
+func WebhookServiceCreator() reconciling.NamedServiceCreatorGetter {
+	return func() (string, reconciling.ServiceCreator) {
+		return resources.UserClusterControllerWebhookServiceName, func(se *corev1.Service) (*corev1.Service, error) {
+			se.Name = resources.UserClusterControllerWebhookServiceName
+			se.Labels = resources.BaseAppLabels(name, nil)
+
+			se.Spec.Type = corev1.ServiceTypeClusterIP
+			se.Spec.Selector = resources.BaseAppLabels(name, nil)
+			se.Spec.Ports = []corev1.ServicePort{
+				{
+					Name:       "webhook",
+					Port:       443,
+					Protocol:   corev1.ProtocolTCP,
+					TargetPort: intstr.FromInt(webhookPort),
+				},
+			}
+
+			return se, nil
+		}
+	}
+}
+
+type webhookServingCertCreatorData interface {
+	GetRootCA() (*triple.KeyPair, error)
+	Cluster() *kubermaticv1.Cluster
+}
+
+// WebhookServingCertificateCreator returns a function to create/update the secret with the serving cert of the
+// admission webhook of the usercluster-controller
+func WebhookServingCertificateCreator(data webhookServingCertCreatorData) reconciling.NamedSecretCreatorGetter {
+	return func() (string, reconciling.SecretCreator) {
+		return resources.UserClusterControllerWebhookServingCertSecretName, func(se *corev1.Secret) (*corev1.Secret, error) {
+			namespace := data.Cluster().Status.NamespaceName
+			// the name the apiserver validates against must be in the SANs, having it as CN is not enough
+			commonName := fmt.Sprintf("%s.%s.svc.cluster.local.", resources.UserClusterControllerWebhookServiceName, namespace)
+			altNames := []string{
+				commonName,
+				fmt.Sprintf("%s.%s.svc.cluster.local", resources.UserClusterControllerWebhookServiceName, namespace),
+				fmt.Sprintf("%s.%s.svc", resources.UserClusterControllerWebhookServiceName, namespace),
+			}
+
+			_, creator := servingcerthelper.ServingCertSecretCreator(data.GetRootCA,
+				resources.UserClusterControllerWebhookServingCertSecretName,
+				commonName,
+				altNames,
+				nil)()
+			return creator(se)
+		}
+	}
+}
@@ -41,6 +41,7 @@ type dnatControllerData interface {
 	NodeAccessNetwork() string
 	DNATControllerImage() string
 	DNATControllerTag() string
+	DNATControllerBackend() string
 }
 
 // DnatControllerContainer returns a sidecar container for running the dnat controller.
@@ -53,6 +54,9 @@ func DnatControllerContainer(data dnatControllerData, name, apiserverAddress str
 	if apiserverAddress != "" {
 		args = append(args, "-master", apiserverAddress)
 	}
+	if backend := data.DNATControllerBackend(); backend != "" {
+		args = append(args, "-backend", backend)
+	}
 
 	return &corev1.Container{
 		Name:    name,
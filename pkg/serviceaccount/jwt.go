@@ -42,6 +42,9 @@ type TokenGenerator interface {
 type TokenAuthenticator interface {
 	// Authenticate checks given token and transform it to custom claim object
 	Authenticate(tokenData string) (*jwt.Claims, *CustomTokenClaim, error)
+	// PublicKeys returns the public keys which verify the tokens, tokens signed with a HMAC key
+	// can not be verified by anybody else and their keys are not published
+	PublicKeys() *jose.JSONWebKeySet
 }
 
 // CustomTokenClaim represents authenticated user
@@ -51,12 +54,22 @@ type CustomTokenClaim struct {
 	TokenID   string `json:"token_id,omitempty"`
 }
 
-func Claims(email, projectID, tokenID string) (*jwt.Claims, *CustomTokenClaim) {
+// MinTokenTTL is the shortest lifetime of a token
+const MinTokenTTL = 10 * time.Minute
+
+// Claims returns the claims of a token which expires after the given ttl. A ttl of zero
+// issues a token which expires after three years.
+func Claims(email, projectID, tokenID string, ttl time.Duration) (*jwt.Claims, *CustomTokenClaim) {
+	now := Now()
+	expiry := now.AddDate(3, 0, 0)
+	if ttl > 0 {
+		expiry = now.Add(ttl)
+	}
 
 	sc := &jwt.Claims{
-		IssuedAt:  jwt.NewNumericDate(Now()),
-		NotBefore: jwt.NewNumericDate(Now()),
-		Expiry:    jwt.NewNumericDate(Now().AddDate(3, 0, 0)),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		Expiry:    jwt.NewNumericDate(expiry),
 	}
 	pc := &CustomTokenClaim{
 		Email:     email,
@@ -67,12 +80,39 @@ func Claims(email, projectID, tokenID string) (*jwt.Claims, *CustomTokenClaim) {
 	return sc, pc
 }
 
+// ValidateTTL checks that a token with the given ttl expires neither too soon nor later than
+// the default three years
+func ValidateTTL(ttl time.Duration) error {
+	now := Now()
+	switch {
+	case ttl < 0:
+		return fmt.Errorf("the token lifetime can not be negative")
+	case ttl > 0 && ttl < MinTokenTTL:
+		return fmt.Errorf("the token lifetime must be at least %v", MinTokenTTL)
+	case now.Add(ttl).After(now.AddDate(3, 0, 0)):
+		return fmt.Errorf("the token lifetime can not be longer than three years")
+	}
+	return nil
+}
+
 // JWTTokenGenerator returns a TokenGenerator that generates signed JWT tokens, using the given privateKey.
+// A PEM encoded RSA or ECDSA private key signs the tokens with RS256 or ES256 and sets the ID of
+// the key in the token header, any other key is used as HMAC key for HS256.
 func JWTTokenGenerator(privateKey []byte) (TokenGenerator, error) {
 	if err := ValidateKey(privateKey); err != nil {
 		return nil, err
 	}
-	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: privateKey}, &jose.SignerOptions{})
+
+	signingKey := jose.SigningKey{Algorithm: jose.HS256, Key: privateKey}
+	if isPEM(privateKey) {
+		keys, err := parseKeys(privateKey)
+		if err != nil {
+			return nil, err
+		}
+		signingKey = jose.SigningKey{Algorithm: jose.SignatureAlgorithm(keys[0].Algorithm), Key: keys[0]}
+	}
+
+	signer, err := jose.NewSigner(signingKey, &jose.SignerOptions{})
 	if err != nil {
 		return nil, err
 	}
@@ -86,7 +126,10 @@ type jwtTokenGenerator struct {
 }
 
 type jwtTokenAuthenticator struct {
-	key interface{}
+	// hmacKeys verify tokens without key ID
+	hmacKeys [][]byte
+	// publicKeys verify tokens by their key ID
+	publicKeys *jose.JSONWebKeySet
 }
 
 // Generate generates new token from claims
@@ -98,11 +141,34 @@ func (j *jwtTokenGenerator) Generate(claims *jwt.Claims, customClaims *CustomTok
 		CompactSerialize()
 }
 
-// JWTTokenAuthenticator authenticates tokens as JWT tokens produced by JWTTokenGenerator
-func JWTTokenAuthenticator(privateKey []byte) TokenAuthenticator {
-	return &jwtTokenAuthenticator{
-		key: privateKey,
+// JWTTokenAuthenticator authenticates tokens as JWT tokens produced by JWTTokenGenerator. Every key
+// is either a HMAC key or a PEM bundle of RSA and ECDSA keys. Besides the current signing key,
+// the keys which signed tokens before a key rotation can be passed, so that those tokens stay
+// valid until they expire.
+func JWTTokenAuthenticator(keys ...[]byte) (TokenAuthenticator, error) {
+	authenticator := &jwtTokenAuthenticator{
+		publicKeys: &jose.JSONWebKeySet{},
+	}
+
+	for _, key := range keys {
+		if len(key) == 0 {
+			continue
+		}
+		if !isPEM(key) {
+			authenticator.hmacKeys = append(authenticator.hmacKeys, key)
+			continue
+		}
+
+		parsed, err := parseKeys(key)
+		if err != nil {
+			return nil, err
+		}
+		for _, jwk := range parsed {
+			authenticator.publicKeys.Keys = append(authenticator.publicKeys.Keys, jwk.Public())
+		}
 	}
+
+	return authenticator, nil
 }
 
 // Authenticate decrypts signed token data to CustomTokenClaim object and checks if token expired
@@ -116,7 +182,7 @@ func (a *jwtTokenAuthenticator) Authenticate(tokenData string) (*jwt.Claims, *Cu
 	public := &jwt.Claims{}
 	customClaims := &CustomTokenClaim{}
 
-	if err := tok.Claims(a.key, customClaims, public); err != nil {
+	if err := a.verify(tok, customClaims, public); err != nil {
 		return nil, nil, err
 	}
 
@@ -134,12 +200,57 @@ func (a *jwtTokenAuthenticator) Authenticate(tokenData string) (*jwt.Claims, *Cu
 	return public, customClaims, nil
 }
 
-func ValidateKey(privateKey []byte) error {
-	if len(privateKey) == 0 {
-		return fmt.Errorf("the signing key can not be empty")
+// verify checks the signature of the token with the key matching its key ID or, for tokens
+// without key ID, with the HMAC keys
+func (a *jwtTokenAuthenticator) verify(tok *jwt.JSONWebToken, claims ...interface{}) error {
+	if len(tok.Headers) != 1 {
+		return fmt.Errorf("token must have exactly one signature")
 	}
-	if len(privateKey) < 32 {
-		return fmt.Errorf("the signing key is to short, use 32 bytes or longer")
+	header := tok.Headers[0]
+
+	if header.KeyID != "" {
+		keys := a.publicKeys.Key(header.KeyID)
+		if len(keys) == 0 {
+			return fmt.Errorf("token is signed with the unknown key %q", header.KeyID)
+		}
+		if header.Algorithm != keys[0].Algorithm {
+			return fmt.Errorf("token is signed with %s, but key %q uses %s", header.Algorithm, header.KeyID, keys[0].Algorithm)
+		}
+		return tok.Claims(keys[0].Key, claims...)
 	}
-	return nil
+
+	if header.Algorithm != string(jose.HS256) || len(a.hmacKeys) == 0 {
+		return fmt.Errorf("token without key ID must be signed with %s", jose.HS256)
+	}
+	var err error
+	for _, key := range a.hmacKeys {
+		if err = tok.Claims(key, claims...); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// Expiry returns the expiry of the token without verifying its signature and expiry, it
+// must only be used for tokens from a trusted source
+func Expiry(tokenData string) (time.Time, error) {
+	tok, err := jwt.ParseSigned(tokenData)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	public := &jwt.Claims{}
+	if err := tok.UnsafeClaimsWithoutVerification(public); err != nil {
+		return time.Time{}, err
+	}
+	if public.Expiry == nil {
+		return time.Time{}, fmt.Errorf("token has no expiry")
+	}
+
+	return public.Expiry.Time(), nil
+}
+
+// PublicKeys returns the public keys which verify the tokens
+func (a *jwtTokenAuthenticator) PublicKeys() *jose.JSONWebKeySet {
+	return a.publicKeys
 }
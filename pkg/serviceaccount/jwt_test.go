@@ -17,6 +17,12 @@ limitations under the License.
 package serviceaccount_test
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"testing"
 	"time"
@@ -26,34 +32,92 @@ import (
 )
 
 func TestServiceAccountIssuer(t *testing.T) {
+	rsaKey := generateRSAKey(t)
+	ecKey := generateECKey(t)
+	rotatedKey := generateECKey(t)
 
 	testcases := []struct {
-		name            string
-		expectedEmail   string
-		expectedProject string
-		expectedToken   string
+		name               string
+		signingKey         []byte
+		verificationKeys   [][]byte
+		ttl                time.Duration
+		expectedEmail      string
+		expectedProject    string
+		expectedToken      string
+		expectedPublicKeys int
+		expectedError      bool
 	}{
 		{
-			name:            "scenario 1, check signed token",
-			expectedEmail:   "test@example.com",
-			expectedProject: "testProject",
-			expectedToken:   "testToken",
+			name:             "scenario 1, check signed token",
+			signingKey:       []byte(test.TestServiceAccountHashKey),
+			verificationKeys: [][]byte{[]byte(test.TestServiceAccountHashKey)},
+			expectedEmail:    "test@example.com",
+			expectedProject:  "testProject",
+			expectedToken:    "testToken",
+		},
+		{
+			name:               "scenario 2, token signed with RS256 and a custom lifetime",
+			signingKey:         rsaKey,
+			verificationKeys:   [][]byte{rsaKey},
+			ttl:                time.Hour,
+			expectedEmail:      "test@example.com",
+			expectedProject:    "testProject",
+			expectedToken:      "testToken",
+			expectedPublicKeys: 1,
+		},
+		{
+			name:               "scenario 3, token signed with ES256 before a key rotation",
+			signingKey:         ecKey,
+			verificationKeys:   [][]byte{rotatedKey, append(ecKey, rsaKey...)},
+			expectedEmail:      "test@example.com",
+			expectedProject:    "testProject",
+			expectedToken:      "testToken",
+			expectedPublicKeys: 3,
+		},
+		{
+			name:               "scenario 4, token signed with a HMAC key before switching to ES256",
+			signingKey:         []byte(test.TestServiceAccountHashKey),
+			verificationKeys:   [][]byte{ecKey, []byte(test.TestServiceAccountHashKey)},
+			expectedEmail:      "test@example.com",
+			expectedProject:    "testProject",
+			expectedToken:      "testToken",
+			expectedPublicKeys: 1,
+		},
+		{
+			name:               "scenario 5, token signed with a removed key",
+			signingKey:         ecKey,
+			verificationKeys:   [][]byte{rotatedKey, []byte(test.TestServiceAccountHashKey)},
+			expectedPublicKeys: 1,
+			expectedError:      true,
 		},
 	}
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
-			tokenGenerator, err := serviceaccount.JWTTokenGenerator([]byte(test.TestServiceAccountHashKey))
+			tokenGenerator, err := serviceaccount.JWTTokenGenerator(tc.signingKey)
 			if err != nil {
 				t.Fatal(err)
 			}
 
-			token, err := tokenGenerator.Generate(serviceaccount.Claims(tc.expectedEmail, tc.expectedProject, tc.expectedToken))
+			token, err := tokenGenerator.Generate(serviceaccount.Claims(tc.expectedEmail, tc.expectedProject, tc.expectedToken, tc.ttl))
 			if err != nil {
 				t.Fatal(err)
 			}
 
-			tokenAuthenticator := serviceaccount.JWTTokenAuthenticator([]byte(test.TestServiceAccountHashKey))
+			tokenAuthenticator, err := serviceaccount.JWTTokenAuthenticator(tc.verificationKeys...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if keys := len(tokenAuthenticator.PublicKeys().Keys); keys != tc.expectedPublicKeys {
+				t.Fatalf("expected %d public keys, got %d", tc.expectedPublicKeys, keys)
+			}
+
 			public, custom, err := tokenAuthenticator.Authenticate(token)
+			if tc.expectedError {
+				if err == nil {
+					t.Fatal("expected the token to be rejected")
+				}
+				return
+			}
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -70,6 +134,13 @@ func TestServiceAccountIssuer(t *testing.T) {
 				t.Fatalf("expected token %s got %s", tc.expectedToken, custom.TokenID)
 			}
 
+			if tc.ttl > 0 {
+				if lifetime := public.Expiry.Time().Sub(public.IssuedAt.Time()); lifetime != tc.ttl {
+					t.Fatalf("expected expire after %v. Got %v", tc.ttl, lifetime)
+				}
+				return
+			}
+
 			threeYearsString := formatTime(serviceaccount.Now().AddDate(3, 0, 0))
 			expiryString := formatTime(public.Expiry.Time())
 
@@ -81,6 +152,47 @@ func TestServiceAccountIssuer(t *testing.T) {
 	}
 }
 
+func TestValidateTTL(t *testing.T) {
+	testcases := []struct {
+		ttl           time.Duration
+		expectedError bool
+	}{
+		{ttl: 0},
+		{ttl: time.Hour},
+		{ttl: 2 * 365 * 24 * time.Hour},
+		{ttl: time.Minute, expectedError: true},
+		{ttl: -time.Hour, expectedError: true},
+		{ttl: 4 * 365 * 24 * time.Hour, expectedError: true},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.ttl.String(), func(t *testing.T) {
+			if err := serviceaccount.ValidateTTL(tc.ttl); (err != nil) != tc.expectedError {
+				t.Fatalf("expected error: %v, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func generateRSAKey(t *testing.T) []byte {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+func generateECKey(t *testing.T) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+}
+
 func formatTime(t time.Time) string {
 	return fmt.Sprintf("%d-%02d-%02d",
 		t.Year(), t.Month(), t.Day())
@@ -0,0 +1,159 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceaccount
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+
+	"gopkg.in/square/go-jose.v2"
+)
+
+// isPEM returns true if the key is PEM encoded, otherwise it is a HMAC key
+func isPEM(key []byte) bool {
+	block, _ := pem.Decode(key)
+	return block != nil
+}
+
+// parseKeys parses all PEM encoded RSA and ECDSA keys. Private keys can be used to sign tokens,
+// public keys only to verify them. Every key is identified by the thumbprint of its public key.
+func parseKeys(data []byte) ([]jose.JSONWebKey, error) {
+	var keys []jose.JSONWebKey
+
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		key, err := parseKey(block)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", block.Type, err)
+		}
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no PEM encoded keys found")
+	}
+
+	return keys, nil
+}
+
+func parseKey(block *pem.Block) (jose.JSONWebKey, error) {
+	var (
+		key interface{}
+		err error
+	)
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return jose.JSONWebKey{}, fmt.Errorf("unsupported PEM block")
+	}
+	if err != nil {
+		return jose.JSONWebKey{}, err
+	}
+
+	algorithm, err := signatureAlgorithm(key)
+	if err != nil {
+		return jose.JSONWebKey{}, err
+	}
+
+	jwk := jose.JSONWebKey{Key: key, Algorithm: string(algorithm), Use: "sig"}
+	public := jwk.Public()
+	thumbprint, err := public.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return jose.JSONWebKey{}, fmt.Errorf("failed to compute the key ID: %v", err)
+	}
+	jwk.KeyID = base64.RawURLEncoding.EncodeToString(thumbprint)
+
+	return jwk, nil
+}
+
+// signatureAlgorithm returns the algorithm used to sign tokens with the key, RS256 for RSA keys
+// and the ECDSA algorithm matching the curve of EC keys
+func signatureAlgorithm(key interface{}) (jose.SignatureAlgorithm, error) {
+	var curve elliptic.Curve
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return jose.RS256, nil
+	case *rsa.PublicKey:
+		return jose.RS256, nil
+	case *ecdsa.PrivateKey:
+		curve = k.Curve
+	case *ecdsa.PublicKey:
+		curve = k.Curve
+	default:
+		return "", fmt.Errorf("unsupported key type %T, only RSA and ECDSA keys are supported", key)
+	}
+
+	switch curve {
+	case elliptic.P256():
+		return jose.ES256, nil
+	case elliptic.P384():
+		return jose.ES384, nil
+	case elliptic.P521():
+		return jose.ES512, nil
+	default:
+		return "", fmt.Errorf("unsupported elliptic curve %s", curve.Params().Name)
+	}
+}
+
+// ValidateKey checks that the key can be used to sign service account tokens. It is either a
+// PEM encoded RSA or ECDSA private key or a HMAC key of at least 32 bytes.
+func ValidateKey(privateKey []byte) error {
+	if len(privateKey) == 0 {
+		return fmt.Errorf("the signing key can not be empty")
+	}
+
+	if isPEM(privateKey) {
+		keys, err := parseKeys(privateKey)
+		if err != nil {
+			return err
+		}
+		if len(keys) != 1 {
+			return fmt.Errorf("the signing key must contain exactly one private key, got %d keys", len(keys))
+		}
+		if keys[0].IsPublic() {
+			return fmt.Errorf("the signing key must be a private key")
+		}
+		return nil
+	}
+
+	if len(privateKey) < 32 {
+		return fmt.Errorf("the signing key is to short, use 32 bytes or longer")
+	}
+	return nil
+}
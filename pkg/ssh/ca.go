@@ -0,0 +1,190 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssh
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	kubeapierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// OwnersPrincipal is the principal of the certificates signed for the owners of a project,
+	// it allows to log in as any user including root
+	OwnersPrincipal = "kubermatic-owners"
+
+	// EditorsPrincipal is the principal of the certificates signed for the other members of a project
+	// who are allowed to manage ssh keys, it allows to log in as any user but root
+	EditorsPrincipal = "kubermatic-editors"
+
+	// CertificateAuthorityKeySecretKey is the key of the private key in the secret of the certificate authority
+	CertificateAuthorityKeySecretKey = "ca.key"
+
+	// certificateClockSkew backdates the certificates to tolerate nodes whose clock is slightly behind
+	certificateClockSkew = time.Minute
+)
+
+// CertificateAuthoritySecretName returns the name of the secret holding the ssh certificate authority of the project.
+func CertificateAuthoritySecretName(projectID string) string {
+	return fmt.Sprintf("ssh-ca-%s", projectID)
+}
+
+// AuthorizedPrincipals returns the principals a certificate must contain to log in as the given user.
+func AuthorizedPrincipals(user string) []string {
+	if user == "root" {
+		return []string{OwnersPrincipal}
+	}
+	return []string{OwnersPrincipal, EditorsPrincipal}
+}
+
+// CertificateAuthority signs the ssh keys of the members of a project.
+type CertificateAuthority struct {
+	signer ssh.Signer
+}
+
+// GenerateCertificateAuthorityKey returns a new ed25519 private key encoded as PKCS #8 PEM block.
+func GenerateCertificateAuthorityKey() ([]byte, error) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// NewCertificateAuthority returns the certificate authority of the PEM encoded private key.
+func NewCertificateAuthority(privateKey []byte) (*CertificateAuthority, error) {
+	signer, err := ssh.ParsePrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the private key of the certificate authority: %v", err)
+	}
+	return &CertificateAuthority{signer: signer}, nil
+}
+
+// PublicKey returns the public key of the certificate authority in the authorized_keys format,
+// as expected in the TrustedUserCAKeys file of sshd.
+func (ca *CertificateAuthority) PublicKey() []byte {
+	return ssh.MarshalAuthorizedKey(ca.signer.PublicKey())
+}
+
+// SignUserKey signs the public key, given in the authorized_keys format, with a user certificate
+// valid for the principals from now on for the given duration.
+func (ca *CertificateAuthority) SignUserKey(publicKey []byte, keyID string, principals []string, validity time.Duration, now time.Time) (*ssh.Certificate, error) {
+	key, _, _, _, err := ssh.ParseAuthorizedKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("the provided ssh key is invalid due to = %v", err)
+	}
+	if _, ok := key.(*ssh.Certificate); ok {
+		return nil, errors.New("the provided ssh key is a certificate already")
+	}
+	if len(principals) == 0 {
+		return nil, errors.New("at least one principal is required")
+	}
+
+	serial := make([]byte, 8)
+	if _, err := rand.Read(serial); err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %v", err)
+	}
+
+	certificate := &ssh.Certificate{
+		Key:             key,
+		Serial:          binary.BigEndian.Uint64(serial),
+		CertType:        ssh.UserCert,
+		KeyId:           keyID,
+		ValidPrincipals: principals,
+		ValidAfter:      uint64(now.Add(-certificateClockSkew).Unix()),
+		ValidBefore:     uint64(now.Add(validity).Unix()),
+		Permissions: ssh.Permissions{
+			Extensions: map[string]string{
+				"permit-X11-forwarding":   "",
+				"permit-agent-forwarding": "",
+				"permit-port-forwarding":  "",
+				"permit-pty":              "",
+				"permit-user-rc":          "",
+			},
+		},
+	}
+	if err := certificate.SignCert(rand.Reader, ca.signer); err != nil {
+		return nil, fmt.Errorf("failed to sign certificate: %v", err)
+	}
+	return certificate, nil
+}
+
+// EnsureCertificateAuthority returns the ssh certificate authority of the project. Its private key is
+// kept in a secret in the given namespace, which is created along with a new key if it doesn't exist yet.
+func EnsureCertificateAuthority(ctx context.Context, client ctrlruntimeclient.Client, namespace string, project *kubermaticv1.Project) (*CertificateAuthority, error) {
+	name := types.NamespacedName{Namespace: namespace, Name: CertificateAuthoritySecretName(project.Name)}
+
+	secret := &corev1.Secret{}
+	err := client.Get(ctx, name, secret)
+	if err == nil {
+		return NewCertificateAuthority(secret.Data[CertificateAuthorityKeySecretKey])
+	}
+	if !kubeapierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get secret %s: %v", name, err)
+	}
+
+	privateKey, err := GenerateCertificateAuthorityKey()
+	if err != nil {
+		return nil, err
+	}
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name.Name,
+			Namespace: name.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: kubermaticv1.SchemeGroupVersion.String(),
+					Kind:       kubermaticv1.ProjectKindName,
+					UID:        project.GetUID(),
+					Name:       project.Name,
+				},
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{CertificateAuthorityKeySecretKey: privateKey},
+	}
+	if err := client.Create(ctx, secret); err != nil {
+		if !kubeapierrors.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("failed to create secret %s: %v", name, err)
+		}
+		// Someone else was faster, use their key.
+		if err := client.Get(ctx, name, secret); err != nil {
+			return nil, fmt.Errorf("failed to get secret %s: %v", name, err)
+		}
+	}
+	return NewCertificateAuthority(secret.Data[CertificateAuthorityKeySecretKey])
+}
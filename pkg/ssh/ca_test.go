@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssh
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testPublicKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIKt9MLMT8ZwK6jwCLtvFFqxeovbx5lL9LCmF6sKx9oo6 user@example.com"
+
+func TestSignUserKey(t *testing.T) {
+	privateKey, err := GenerateCertificateAuthorityKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	ca, err := NewCertificateAuthority(privateKey)
+	if err != nil {
+		t.Fatalf("failed to load certificate authority: %v", err)
+	}
+
+	now := time.Now()
+	certificate, err := ca.SignUserKey([]byte(testPublicKey), "john@acme.com", []string{EditorsPrincipal}, time.Hour, now)
+	if err != nil {
+		t.Fatalf("failed to sign key: %v", err)
+	}
+
+	caPublicKey, _, _, _, err := ssh.ParseAuthorizedKey(ca.PublicKey())
+	if err != nil {
+		t.Fatalf("failed to parse the public key of the certificate authority: %v", err)
+	}
+	checker := &ssh.CertChecker{
+		IsUserAuthority: func(auth ssh.PublicKey) bool {
+			return string(auth.Marshal()) == string(caPublicKey.Marshal())
+		},
+		Clock: func() time.Time { return now },
+	}
+	if err := checker.CheckCert(OwnersPrincipal, certificate); err == nil {
+		t.Error("expected the certificate to be rejected for another principal")
+	}
+	if err := checker.CheckCert(EditorsPrincipal, certificate); err != nil {
+		t.Errorf("expected the certificate to be valid: %v", err)
+	}
+
+	checker.Clock = func() time.Time { return now.Add(time.Hour + time.Second) }
+	if err := checker.CheckCert(EditorsPrincipal, certificate); err == nil {
+		t.Error("expected the certificate to be expired")
+	}
+
+	if _, err := ca.SignUserKey(ssh.MarshalAuthorizedKey(certificate), "john@acme.com", []string{EditorsPrincipal}, time.Hour, now); err == nil {
+		t.Error("expected an error when signing a certificate")
+	}
+}
+
+func TestEnsureCertificateAuthority(t *testing.T) {
+	project := &kubermaticv1.Project{ObjectMeta: metav1.ObjectMeta{Name: "my-project", UID: "uid"}}
+	client := fakectrlruntimeclient.NewClientBuilder().Build()
+
+	ca, err := EnsureCertificateAuthority(context.Background(), client, "kubermatic", project)
+	if err != nil {
+		t.Fatalf("failed to create the certificate authority: %v", err)
+	}
+	again, err := EnsureCertificateAuthority(context.Background(), client, "kubermatic", project)
+	if err != nil {
+		t.Fatalf("failed to get the certificate authority: %v", err)
+	}
+	if string(ca.PublicKey()) != string(again.PublicKey()) {
+		t.Error("expected the existing certificate authority to be reused")
+	}
+}
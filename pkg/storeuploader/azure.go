@@ -0,0 +1,144 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storeuploader
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/storage"
+)
+
+// azureBlockSize is the size of the blocks a backup is uploaded in. Single
+// put requests are limited to 256 MiB, which etcd snapshots can exceed.
+const azureBlockSize = 4 * 1024 * 1024
+
+// AzureBlobStore stores backups as block blobs in an Azure Blob Storage container.
+type AzureBlobStore struct {
+	container *storage.Container
+}
+
+var _ BackupStore = &AzureBlobStore{}
+
+// NewAzureBlobStore returns a new AzureBlobStore. The container is created if it does not exist yet.
+func NewAzureBlobStore(accountName, accountKey, containerName string) (*AzureBlobStore, error) {
+	if len(containerName) == 0 {
+		return nil, errors.New("container cannot be empty")
+	}
+
+	client, err := storage.NewBasicClient(accountName, accountKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Azure storage client: %v", err)
+	}
+
+	blobService := client.GetBlobService()
+	container := blobService.GetContainerReference(containerName)
+	if _, err := container.CreateIfNotExists(nil); err != nil {
+		return nil, fmt.Errorf("failed to ensure container %s: %v", containerName, err)
+	}
+
+	return &AzureBlobStore{container: container}, nil
+}
+
+func (s *AzureBlobStore) Upload(file, objectName string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	blob := s.container.GetBlobReference(objectName)
+	if err := blob.CreateBlockBlob(nil); err != nil {
+		return err
+	}
+
+	var blocks []storage.Block
+	chunk := make([]byte, azureBlockSize)
+	for i := 0; ; i++ {
+		n, err := io.ReadFull(f, chunk)
+		if n > 0 {
+			// all block IDs of a blob must have the same length
+			blockID := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%08d", i)))
+			if err := blob.PutBlock(blockID, chunk[:n], nil); err != nil {
+				return fmt.Errorf("failed to upload block %d: %v", i, err)
+			}
+			blocks = append(blocks, storage.Block{ID: blockID, Status: storage.BlockStatusUncommitted})
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return blob.PutBlockList(blocks, nil)
+}
+
+func (s *AzureBlobStore) Download(objectName, file string) error {
+	reader, err := s.container.GetBlobReference(objectName).Get(nil)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, reader); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func (s *AzureBlobStore) List(prefix string) ([]Object, error) {
+	var objects []Object
+
+	params := storage.ListBlobsParameters{Prefix: prefix}
+	for {
+		response, err := s.container.ListBlobs(params)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, blob := range response.Blobs {
+			objects = append(objects, Object{
+				Name:         blob.Name,
+				LastModified: time.Time(blob.Properties.LastModified),
+				Size:         blob.Properties.ContentLength,
+			})
+		}
+
+		if response.NextMarker == "" {
+			return objects, nil
+		}
+		params.Marker = response.NextMarker
+	}
+}
+
+func (s *AzureBlobStore) Delete(objectName string) error {
+	_, err := s.container.GetBlobReference(objectName).DeleteIfExists(nil)
+	return err
+}
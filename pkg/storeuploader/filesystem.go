@@ -0,0 +1,143 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storeuploader
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FilesystemStore stores backups as plain files in a local directory,
+// usually the mount point of a PersistentVolumeClaim.
+type FilesystemStore struct {
+	directory string
+}
+
+var _ BackupStore = &FilesystemStore{}
+
+// NewFilesystemStore returns a new FilesystemStore. The directory is created if it does not exist.
+func NewFilesystemStore(directory string) (*FilesystemStore, error) {
+	if len(directory) == 0 {
+		return nil, errors.New("directory cannot be empty")
+	}
+
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %v", directory, err)
+	}
+
+	return &FilesystemStore{directory: directory}, nil
+}
+
+func (s *FilesystemStore) path(objectName string) (string, error) {
+	if objectName == "" || strings.ContainsRune(objectName, filepath.Separator) {
+		return "", fmt.Errorf("invalid object name %q", objectName)
+	}
+	return filepath.Join(s.directory, objectName), nil
+}
+
+func (s *FilesystemStore) Upload(file, objectName string) error {
+	dst, err := s.path(objectName)
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first, so that an interrupted upload never
+	// leaves a truncated backup behind
+	tmp, err := ioutil.TempFile(s.directory, ".upload-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := copyFile(file, tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), dst)
+}
+
+func (s *FilesystemStore) Download(objectName, file string) error {
+	src, err := s.path(objectName)
+	if err != nil {
+		return err
+	}
+
+	dst, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	if err := copyFile(src, dst); err != nil {
+		dst.Close()
+		return err
+	}
+
+	return dst.Close()
+}
+
+func (s *FilesystemStore) List(prefix string) ([]Object, error) {
+	entries, err := ioutil.ReadDir(s.directory)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []Object
+	for _, entry := range entries {
+		if !entry.Mode().IsRegular() || !strings.HasPrefix(entry.Name(), prefix) || strings.HasPrefix(entry.Name(), ".upload-") {
+			continue
+		}
+		objects = append(objects, Object{
+			Name:         entry.Name(),
+			LastModified: entry.ModTime(),
+			Size:         entry.Size(),
+		})
+	}
+
+	return objects, nil
+}
+
+func (s *FilesystemStore) Delete(objectName string) error {
+	path, err := s.path(objectName)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func copyFile(src string, dst io.Writer) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(dst, f)
+	return err
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storeuploader
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+
+	"github.com/minio/minio-go"
+)
+
+// S3Store stores backups in a bucket of an S3-compatible object storage.
+// This includes GCS buckets accessed via HMAC keys.
+type S3Store struct {
+	client       *minio.Client
+	bucket       string
+	createBucket bool
+}
+
+var _ BackupStore = &S3Store{}
+
+// NewS3Store returns a new S3Store. If createBucket is true, the bucket will be
+// created on the first upload if it does not exist yet.
+func NewS3Store(endpoint string, secure bool, accessKeyID, secretAccessKey, bucket string, createBucket bool, rootCAs *x509.CertPool) (*S3Store, error) {
+	if len(bucket) == 0 {
+		return nil, errors.New("bucket cannot be empty")
+	}
+
+	client, err := minio.New(endpoint, accessKeyID, secretAccessKey, secure)
+	if err != nil {
+		return nil, err
+	}
+	client.SetAppInfo("kubermatic-store-uploader", "v0.1")
+
+	if rootCAs != nil {
+		client.SetCustomTransport(&http.Transport{
+			TLSClientConfig:    &tls.Config{RootCAs: rootCAs},
+			DisableCompression: true,
+		})
+	}
+
+	return &S3Store{
+		client:       client,
+		bucket:       bucket,
+		createBucket: createBucket,
+	}, nil
+}
+
+func (s *S3Store) Upload(file, objectName string) error {
+	if s.createBucket {
+		exists, err := s.client.BucketExists(s.bucket)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			if err := s.client.MakeBucket(s.bucket, ""); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := s.client.FPutObject(s.bucket, objectName, file, minio.PutObjectOptions{})
+	return err
+}
+
+func (s *S3Store) Download(objectName, file string) error {
+	return s.client.FGetObject(s.bucket, objectName, file, minio.GetObjectOptions{})
+}
+
+func (s *S3Store) List(prefix string) ([]Object, error) {
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	var objects []Object
+	for object := range s.client.ListObjects(s.bucket, prefix, true, doneCh) {
+		if object.Err != nil {
+			return nil, object.Err
+		}
+		objects = append(objects, Object{
+			Name:         object.Key,
+			LastModified: object.LastModified,
+			Size:         object.Size,
+		})
+	}
+
+	return objects, nil
+}
+
+func (s *S3Store) Delete(objectName string) error {
+	return s.client.RemoveObject(s.bucket, objectName)
+}
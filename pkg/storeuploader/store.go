@@ -0,0 +1,44 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storeuploader
+
+import (
+	"time"
+)
+
+// Object describes a single backup stored in a BackupStore
+type Object struct {
+	// Name is the unique name of the object within the store
+	Name string
+	// LastModified is the time the object was last written
+	LastModified time.Time
+	// Size is the size of the object in bytes
+	Size int64
+}
+
+// BackupStore is implemented by all backends the StoreUploader can manage backups in
+type BackupStore interface {
+	// Upload stores the content of the given file as an object with the given name,
+	// overwriting any existing object of the same name
+	Upload(file, objectName string) error
+	// Download writes the content of the given object to the given file
+	Download(objectName, file string) error
+	// List returns all objects whose name starts with the given prefix
+	List(prefix string) ([]Object, error)
+	// Delete removes the given object. Deleting an object that does not exist is not an error.
+	Delete(objectName string) error
+}
@@ -17,18 +17,16 @@ limitations under the License.
 package storeuploader
 
 import (
-	"crypto/tls"
-	"crypto/x509"
 	"errors"
 	"fmt"
-	"net/http"
 	"os"
 	"path"
 	"sort"
 	"time"
 
-	"github.com/minio/minio-go"
 	"go.uber.org/zap"
+
+	"k8c.io/kubermatic/v2/pkg/util/retention"
 )
 
 // prefix separator separates the prefix
@@ -40,34 +38,21 @@ const prefixSeparator = "storeuploader"
 // StoreUploader is the configuration
 // for the StoreUploader
 type StoreUploader struct {
-	// client is a pointer to an initialized client
-	client *minio.Client
+	// store is the backend the backups are managed in
+	store  BackupStore
 	logger *zap.SugaredLogger
 }
 
 // New returns a new instance of the StoreUploader
-func New(endpoint string, secure bool, accessKeyID, secretAccessKey string, logger *zap.SugaredLogger, rootCAs *x509.CertPool) (*StoreUploader, error) {
-	client, err := minio.New(endpoint, accessKeyID, secretAccessKey, secure)
-	if err != nil {
-		return nil, err
-	}
-	client.SetAppInfo("kubermatic-store-uploader", "v0.1")
-
-	if rootCAs != nil {
-		client.SetCustomTransport(&http.Transport{
-			TLSClientConfig:    &tls.Config{RootCAs: rootCAs},
-			DisableCompression: true,
-		})
-	}
-
+func New(store BackupStore, logger *zap.SugaredLogger) *StoreUploader {
 	return &StoreUploader{
-		client: client,
+		store:  store,
 		logger: logger,
-	}, nil
+	}
 }
 
-// Store uploads the given file to S3
-func (u *StoreUploader) Store(file, bucket, prefix string, createBucket bool) error {
+// Store uploads the given file to the backup store
+func (u *StoreUploader) Store(file, prefix string) error {
 	if len(prefix) == 0 {
 		return errors.New("prefix cannot be empty")
 	}
@@ -76,55 +61,32 @@ func (u *StoreUploader) Store(file, bucket, prefix string, createBucket bool) er
 		return fmt.Errorf("%s not found", file)
 	}
 
-	logger := u.logger.With("bucket", bucket)
-
-	if createBucket {
-		logger.Debug("Check if bucket exists")
-		exists, err := u.client.BucketExists(bucket)
-		if err != nil {
-			return err
-		}
-		if !exists {
-			logger.Infow("Creating bucket")
-			if err := u.client.MakeBucket(bucket, ""); err != nil {
-				return err
-			}
-		}
-	}
-
 	objectName := fmt.Sprintf("%s-%s-%s-%s", prefix, prefixSeparator, time.Now().Format("2006-01-02T15:04:05"), path.Base(file))
-	logger.Infow("Uploading file", "src", file, "dst", objectName)
+	u.logger.Infow("Uploading file", "src", file, "dst", objectName)
 
-	_, err := u.client.FPutObject(bucket, objectName, file, minio.PutObjectOptions{})
-	return err
+	return u.store.Upload(file, objectName)
 }
 
-// DeleteOldBackups deletes revisions of all files of the given prefix which are older than max-revisions
-func (u *StoreUploader) DeleteOldBackups(bucket, prefix string, revisionsToKeep int) error {
+// DeleteOldBackups deletes revisions of all files of the given prefix which are not retained by the given policy
+func (u *StoreUploader) DeleteOldBackups(prefix string, policy retention.Policy) error {
 	if len(prefix) == 0 {
 		return errors.New("prefix cannot be empty")
 	}
 
-	doneCh := make(chan struct{})
-	defer close(doneCh)
-
-	logger := u.logger.With("bucket", bucket, "prefix", prefix, "keep", revisionsToKeep)
+	logger := u.logger.With("prefix", prefix, "policy", policy)
 
 	logger.Debugw("Listing existing objects")
 
-	var existingObjects []minio.ObjectInfo
-	for object := range u.client.ListObjects(bucket, fmt.Sprintf("%s-%s", prefix, prefixSeparator), true, doneCh) {
-		if object.Err != nil {
-			return object.Err
-		}
-		existingObjects = append(existingObjects, object)
+	existingObjects, err := u.store.List(fmt.Sprintf("%s-%s", prefix, prefixSeparator))
+	if err != nil {
+		return err
 	}
 
-	logger.Debugw("Done listing bucket", "objects", len(existingObjects))
+	logger.Debugw("Done listing objects", "objects", len(existingObjects))
 
-	for _, object := range u.getObjectsToDelete(existingObjects, revisionsToKeep) {
-		logger.Infow("Removing object", "object", object.Key)
-		if err := u.client.RemoveObject(bucket, object.Key); err != nil {
+	for _, object := range u.getObjectsToDelete(existingObjects, policy) {
+		logger.Infow("Removing object", "object", object.Name)
+		if err := u.store.Delete(object.Name); err != nil {
 			return err
 		}
 	}
@@ -133,31 +95,25 @@ func (u *StoreUploader) DeleteOldBackups(bucket, prefix string, revisionsToKeep
 }
 
 // DeleteAll deletes all revisions of all files matching the given prefix
-func (u *StoreUploader) DeleteAll(bucket, prefix string) error {
+func (u *StoreUploader) DeleteAll(prefix string) error {
 	if len(prefix) == 0 {
 		return errors.New("prefix cannot be empty")
 	}
 
-	doneCh := make(chan struct{})
-	defer close(doneCh)
-
-	logger := u.logger.With("bucket", bucket, "prefix", prefix)
+	logger := u.logger.With("prefix", prefix)
 
 	logger.Debugw("Listing existing objects")
 
-	var existingObjects []minio.ObjectInfo
-	for object := range u.client.ListObjects(bucket, fmt.Sprintf("%s-%s", prefix, prefixSeparator), true, doneCh) {
-		if object.Err != nil {
-			return object.Err
-		}
-		existingObjects = append(existingObjects, object)
+	existingObjects, err := u.store.List(fmt.Sprintf("%s-%s", prefix, prefixSeparator))
+	if err != nil {
+		return err
 	}
 
-	logger.Debugw("Done listing bucket", "objects", len(existingObjects))
+	logger.Debugw("Done listing objects", "objects", len(existingObjects))
 
 	for _, object := range existingObjects {
-		logger.Infow("Removing object", "object", object.Key)
-		if err := u.client.RemoveObject(bucket, object.Key); err != nil {
+		logger.Infow("Removing object", "object", object.Name)
+		if err := u.store.Delete(object.Name); err != nil {
 			return err
 		}
 	}
@@ -165,23 +121,21 @@ func (u *StoreUploader) DeleteAll(bucket, prefix string) error {
 	return nil
 }
 
-func (u *StoreUploader) getObjectsToDelete(objects []minio.ObjectInfo, revisionsToKeep int) []minio.ObjectInfo {
-	if len(objects) <= revisionsToKeep {
-		return nil
-	}
-
+func (u *StoreUploader) getObjectsToDelete(objects []Object, policy retention.Policy) []Object {
 	sort.Slice(objects, func(i, j int) bool {
 		return objects[i].LastModified.Before(objects[j].LastModified)
 	})
 
-	numRevisionsToDelete := len(objects) - revisionsToKeep
+	times := make([]time.Time, len(objects))
+	for i, object := range objects {
+		times[i] = object.LastModified
+	}
 
-	var objectsToDelete []minio.ObjectInfo
-	for idx, object := range objects {
-		if idx >= numRevisionsToDelete {
-			return objectsToDelete
+	var objectsToDelete []Object
+	for i, rules := range retention.Evaluate(policy, times) {
+		if len(rules) == 0 {
+			objectsToDelete = append(objectsToDelete, objects[i])
 		}
-		objectsToDelete = append(objectsToDelete, object)
 	}
 
 	return objectsToDelete
@@ -17,51 +17,89 @@ limitations under the License.
 package storeuploader
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
 	"testing"
 	"time"
 
 	"github.com/go-test/deep"
-	"github.com/minio/minio-go"
+
+	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+	"k8c.io/kubermatic/v2/pkg/util/retention"
 )
 
 func TestGetObjectsToDelete(t *testing.T) {
 	tests := []struct {
 		name             string
-		existingObjects  []minio.ObjectInfo
-		expectedToDelete []minio.ObjectInfo
-		revisions        int
+		existingObjects  []Object
+		expectedToDelete []Object
+		policy           retention.Policy
 	}{
 		{
-			name:      "nothing gets deleted as revisions==existing-backups",
-			revisions: 1,
-			existingObjects: []minio.ObjectInfo{
+			name:   "nothing gets deleted as revisions==existing-backups",
+			policy: retention.Policy{Latest: 1},
+			existingObjects: []Object{
 				{
-					Key:          "foo",
+					Name:         "foo",
 					LastModified: time.Unix(1, 0),
 				},
 			},
 			expectedToDelete: nil,
 		},
 		{
-			name:      "oldest should be deleted as revisions < existing-backups",
-			revisions: 1,
-			existingObjects: []minio.ObjectInfo{
+			name:   "oldest should be deleted as revisions < existing-backups",
+			policy: retention.Policy{Latest: 1},
+			existingObjects: []Object{
 				{
-					Key:          "foo",
+					Name:         "foo",
 					LastModified: time.Unix(1, 0),
 				},
 				{
-					Key:          "bar",
+					Name:         "bar",
 					LastModified: time.Unix(10, 0),
 				},
 			},
-			expectedToDelete: []minio.ObjectInfo{
+			expectedToDelete: []Object{
 				{
-					Key:          "foo",
+					Name:         "foo",
 					LastModified: time.Unix(1, 0),
 				},
 			},
 		},
+		{
+			name:   "most recent object of each day is kept",
+			policy: retention.Policy{Daily: 2},
+			existingObjects: []Object{
+				{
+					Name:         "day-2-late",
+					LastModified: time.Date(2021, 3, 2, 18, 0, 0, 0, time.UTC),
+				},
+				{
+					Name:         "day-1-early",
+					LastModified: time.Date(2021, 3, 1, 6, 0, 0, 0, time.UTC),
+				},
+				{
+					Name:         "day-1-late",
+					LastModified: time.Date(2021, 3, 1, 18, 0, 0, 0, time.UTC),
+				},
+				{
+					Name:         "day-0",
+					LastModified: time.Date(2021, 2, 28, 18, 0, 0, 0, time.UTC),
+				},
+			},
+			expectedToDelete: []Object{
+				{
+					Name:         "day-0",
+					LastModified: time.Date(2021, 2, 28, 18, 0, 0, 0, time.UTC),
+				},
+				{
+					Name:         "day-1-early",
+					LastModified: time.Date(2021, 3, 1, 6, 0, 0, 0, time.UTC),
+				},
+			},
+		},
 	}
 
 	uploader := StoreUploader{}
@@ -69,13 +107,13 @@ func TestGetObjectsToDelete(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			t.Log("existing objects:")
 			for _, object := range test.existingObjects {
-				t.Logf("existing object: %s - %s", object.LastModified.Format("2006-01-02T15:04:05"), object.Key)
+				t.Logf("existing object: %s - %s", object.LastModified.Format("2006-01-02T15:04:05"), object.Name)
 			}
 
-			gotToDelete := uploader.getObjectsToDelete(test.existingObjects, test.revisions)
+			gotToDelete := uploader.getObjectsToDelete(test.existingObjects, test.policy)
 			t.Log("objects to delete:")
 			for _, object := range gotToDelete {
-				t.Logf("existing object: %s - %s", object.LastModified.Format("2006-01-02T15:04:05"), object.Key)
+				t.Logf("existing object: %s - %s", object.LastModified.Format("2006-01-02T15:04:05"), object.Name)
 			}
 
 			if diff := deep.Equal(gotToDelete, test.expectedToDelete); diff != nil {
@@ -84,3 +122,71 @@ func TestGetObjectsToDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestFilesystemStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "storeuploader-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := NewFilesystemStore(filepath.Join(dir, "backups"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	file := filepath.Join(dir, "snapshot.db")
+	if err := ioutil.WriteFile(file, []byte("snapshot"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, name := range []string{"cluster-a-storeuploader-1", "cluster-a-storeuploader-2", "cluster-a-storeuploader-3", "cluster-b-storeuploader-1"} {
+		if err := store.Upload(file, name); err != nil {
+			t.Fatalf("failed to upload %s: %v", name, err)
+		}
+		// ensure a stable order of the objects by their modification time
+		modTime := time.Unix(int64(i), 0)
+		if err := os.Chtimes(filepath.Join(dir, "backups", name), modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	uploader := New(store, kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar())
+	if err := uploader.DeleteOldBackups("cluster-a", retention.Policy{Latest: 1}); err != nil {
+		t.Fatalf("failed to delete old backups: %v", err)
+	}
+
+	objects, err := store.List("cluster-")
+	if err != nil {
+		t.Fatalf("failed to list objects: %v", err)
+	}
+	var names []string
+	for _, object := range objects {
+		names = append(names, object.Name)
+	}
+	sort.Strings(names)
+
+	expected := []string{"cluster-a-storeuploader-3", "cluster-b-storeuploader-1"}
+	if diff := deep.Equal(names, expected); diff != nil {
+		t.Errorf("Expected objects %v, got %v", expected, names)
+	}
+
+	downloaded := filepath.Join(dir, "downloaded.db")
+	if err := store.Download("cluster-b-storeuploader-1", downloaded); err != nil {
+		t.Fatalf("failed to download object: %v", err)
+	}
+	content, err := ioutil.ReadFile(downloaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "snapshot" {
+		t.Errorf("Expected downloaded content %q, got %q", "snapshot", string(content))
+	}
+
+	if err := uploader.DeleteAll("cluster-b"); err != nil {
+		t.Fatalf("failed to delete all backups: %v", err)
+	}
+	if objects, err := store.List("cluster-b"); err != nil || len(objects) != 0 {
+		t.Errorf("Expected no objects after deleting all backups, got %v (err: %v)", objects, err)
+	}
+}
@@ -6,8 +6,10 @@ package models
 // Editing this file might prove futile when you re-run the swagger generate command
 
 import (
+	"github.com/go-openapi/errors"
 	"github.com/go-openapi/strfmt"
 	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
 )
 
 // SSHKeySpec SSHKeySpec represents the details of a ssh key
@@ -15,15 +17,45 @@ import (
 // swagger:model SSHKeySpec
 type SSHKeySpec struct {
 
+	// ExpiresAt is the time after which the key is removed from the nodes.
+	// Format: date-time
+	ExpiresAt strfmt.DateTime `json:"expiresAt,omitempty"`
+
 	// fingerprint
 	Fingerprint string `json:"fingerprint,omitempty"`
 
 	// public key
 	PublicKey string `json:"publicKey,omitempty"`
+
+	// User is the Linux user the key is authorized for on the nodes. The key is
+	// authorized for all users when it is empty.
+	User string `json:"user,omitempty"`
 }
 
 // Validate validates this SSH key spec
 func (m *SSHKeySpec) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateExpiresAt(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *SSHKeySpec) validateExpiresAt(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.ExpiresAt) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("expiresAt", "body", "date-time", m.ExpiresAt.String(), formats); err != nil {
+		return err
+	}
+
 	return nil
 }
 
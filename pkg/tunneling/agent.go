@@ -0,0 +1,279 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunneling
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// AgentOptions are the options of the tunneling agent.
+type AgentOptions struct {
+	// ProxyAddress is the host:port of the HTTP CONNECT listener of the
+	// nodeport-proxy.
+	ProxyAddress string
+	// Authority is the host:port requested to the nodeport-proxy, it selects
+	// the agent port of the tunneling server of the cluster.
+	Authority string
+	// NodeName is the name of the node the agent is running on.
+	NodeName string
+	// Addresses are the addresses of the node, the server sends the streams
+	// for these addresses to the tunnels of this agent.
+	Addresses []string
+	// Token is the secret shared with the tunneling server.
+	Token string
+	// Tunnels is the number of idle tunnels kept open to the server.
+	Tunnels int
+	// DialTimeout is the timeout for opening a tunnel or dialing a target.
+	DialTimeout time.Duration
+	// IdleTimeout is the time after which an idle tunnel that did not receive
+	// any ping is considered lost.
+	IdleTimeout time.Duration
+	// MinBackoff and MaxBackoff bound the exponential backoff between failed
+	// attempts to open a tunnel.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// Agent keeps a pool of tunnels open to the tunneling server and carries the
+// streams requested by it.
+type Agent struct {
+	opts    AgentOptions
+	log     *zap.SugaredLogger
+	metrics *AgentMetrics
+
+	// dialTarget dials the targets requested by the server.
+	dialTarget func(ctx context.Context, network, address string) (net.Conn, error)
+
+	// tunnels is the number of idle registered tunnels.
+	tunnels int32
+}
+
+// NewAgent returns a new tunneling agent.
+func NewAgent(opts AgentOptions, log *zap.SugaredLogger, metrics *AgentMetrics) *Agent {
+	dialer := &net.Dialer{Timeout: opts.DialTimeout}
+	return &Agent{
+		opts:       opts,
+		log:        log,
+		metrics:    metrics,
+		dialTarget: dialer.DialContext,
+	}
+}
+
+// Healthy tells if at least one tunnel is registered with the server.
+func (a *Agent) Healthy() bool {
+	return atomic.LoadInt32(&a.tunnels) > 0
+}
+
+// HealthzHandler returns a handler answering 200 when the agent is healthy.
+func (a *Agent) HealthzHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if !a.Healthy() {
+			http.Error(w, "no tunnel registered", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	})
+}
+
+// Run keeps the tunnels open until the context is done.
+func (a *Agent) Run(ctx context.Context) {
+	wg := sync.WaitGroup{}
+	for i := 0; i < a.opts.Tunnels; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.keepTunnel(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+// keepTunnel opens a tunnel, waits for the server to use it and opens a new
+// one, until the context is done.
+func (a *Agent) keepTunnel(ctx context.Context) {
+	backoff := a.opts.MinBackoff
+	for ctx.Err() == nil {
+		conn, err := a.openTunnel(ctx)
+		if err != nil {
+			a.metrics.Reconnects.Inc()
+			a.log.Debugw("Failed to open tunnel", zap.Error(err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+			if backoff > a.opts.MaxBackoff {
+				backoff = a.opts.MaxBackoff
+			}
+			continue
+		}
+		backoff = a.opts.MinBackoff
+
+		target, err := a.waitForStream(ctx, conn)
+		if err != nil {
+			conn.Close()
+			if ctx.Err() == nil {
+				a.metrics.Reconnects.Inc()
+				a.log.Debugw("Lost idle tunnel", zap.Error(err))
+			}
+			continue
+		}
+		go a.serveStream(ctx, conn, target)
+	}
+}
+
+// openTunnel connects to the server through the nodeport-proxy and registers
+// the tunnel.
+func (a *Agent) openTunnel(ctx context.Context) (*bufferedConn, error) {
+	dialer := &net.Dialer{Timeout: a.opts.DialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", a.opts.ProxyAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial proxy: %v", err)
+	}
+	if err := conn.SetDeadline(time.Now().Add(a.opts.DialTimeout)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	bc, err := a.register(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return bc, nil
+}
+
+func (a *Agent) register(conn net.Conn) (*bufferedConn, error) {
+	if _, err := fmt.Fprintf(conn, "CONNECT %s HTTP/1.1\r\nHost: %s\r\n\r\n", a.opts.Authority, a.opts.Authority); err != nil {
+		return nil, fmt.Errorf("failed to send CONNECT request: %v", err)
+	}
+
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, &http.Request{Method: http.MethodConnect})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CONNECT response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("proxy refused the tunnel: %s", resp.Status)
+	}
+
+	args, err := expectMessage(r, msgChallenge)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read challenge: %v", err)
+	}
+	if len(args) != 1 {
+		return nil, fmt.Errorf("malformed challenge")
+	}
+	if err := writeMessage(conn, msgHello, a.opts.NodeName, signNonce(a.opts.Token, args[0]), strings.Join(a.opts.Addresses, ",")); err != nil {
+		return nil, fmt.Errorf("failed to send hello: %v", err)
+	}
+	if _, err := expectMessage(r, msgOK); err != nil {
+		return nil, fmt.Errorf("server refused the tunnel: %v", err)
+	}
+
+	return &bufferedConn{Conn: conn, r: r}, nil
+}
+
+// waitForStream answers the pings of the server until it requests a stream,
+// and returns the address to dial for it.
+func (a *Agent) waitForStream(ctx context.Context, conn *bufferedConn) (string, error) {
+	atomic.AddInt32(&a.tunnels, 1)
+	a.metrics.Tunnels.Inc()
+	defer func() {
+		atomic.AddInt32(&a.tunnels, -1)
+		a.metrics.Tunnels.Dec()
+	}()
+
+	// unblock the read when the context is done
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(a.opts.IdleTimeout)); err != nil {
+			return "", err
+		}
+		msg, err := readMessage(conn.r)
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case msg[0] == msgPing:
+			if err := writeMessage(conn, msgPong); err != nil {
+				return "", err
+			}
+		case msg[0] == msgDial && len(msg) == 2:
+			return msg[1], conn.SetReadDeadline(time.Time{})
+		default:
+			return "", fmt.Errorf("unexpected message %q", msg[0])
+		}
+	}
+}
+
+// serveStream dials the target and carries the stream between it and the
+// tunnel.
+func (a *Agent) serveStream(ctx context.Context, conn *bufferedConn, target string) {
+	dialCtx, cancel := context.WithTimeout(ctx, a.opts.DialTimeout)
+	defer cancel()
+
+	targetConn, err := a.dialTarget(dialCtx, "tcp", target)
+	if err != nil {
+		a.metrics.Streams.WithLabelValues(resultError).Inc()
+		a.log.Debugw("Failed to dial target", "target", target, zap.Error(err))
+		_ = writeMessage(conn, msgError, err.Error())
+		conn.Close()
+		return
+	}
+	if err := writeMessage(conn, msgOK); err != nil {
+		a.metrics.Streams.WithLabelValues(resultError).Inc()
+		targetConn.Close()
+		conn.Close()
+		return
+	}
+	a.metrics.Streams.WithLabelValues(resultSuccess).Inc()
+
+	a.metrics.ActiveStreams.Inc()
+	defer a.metrics.ActiveStreams.Dec()
+
+	in := a.metrics.Bytes.WithLabelValues(DirectionIn)
+	out := a.metrics.Bytes.WithLabelValues(DirectionOut)
+	pipe(conn, targetConn,
+		func(n int) { in.Add(float64(n)) },
+		func(n int) { out.Add(float64(n)) })
+}
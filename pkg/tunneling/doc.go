@@ -0,0 +1,37 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package tunneling implements the reverse tunnels used by clusters exposed with
+the Tunneling expose strategy to reach the kubelets and the cluster network
+from the control plane.
+
+The Agent runs on every node of the user cluster. It opens a pool of tunnels to
+the Server running in the cluster namespace on the seed, through the HTTP
+CONNECT listener of the nodeport-proxy. Every tunnel is registered with the
+node name and addresses, and is authenticated answering a challenge with the
+cluster token. Idle tunnels are kept alive with pings.
+
+The Server exposes an HTTP CONNECT proxy that is used by the kube-apiserver as
+egress selector for the cluster network. The proxy is served with TLS and only
+accepts the client certificate of the kube-apiserver, signed by the cluster CA,
+as any other pod of the seed could otherwise reach the cluster network through
+it. For every CONNECT request it consumes
+an idle tunnel, preferring the ones of the node owning the requested address,
+and asks the agent to dial the target. The agent replaces the consumed tunnel
+with a new one.
+*/
+package tunneling
@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunneling
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// DirectionIn is the direction of the data sent from the control plane to
+	// the user cluster.
+	DirectionIn = "in"
+	// DirectionOut is the direction of the data sent from the user cluster to
+	// the control plane.
+	DirectionOut = "out"
+
+	resultSuccess = "success"
+	resultError   = "error"
+)
+
+// AgentMetrics are the metrics of the tunneling agent.
+type AgentMetrics struct {
+	// Tunnels is the number of idle tunnels registered with the server.
+	Tunnels prometheus.Gauge
+	// Reconnects counts the tunnels that could not be established or were
+	// lost while idle.
+	Reconnects prometheus.Counter
+	// Streams counts the streams requested by the server by result.
+	Streams *prometheus.CounterVec
+	// ActiveStreams is the number of streams currently carried.
+	ActiveStreams prometheus.Gauge
+	// Bytes counts the bytes carried by the streams by direction.
+	Bytes *prometheus.CounterVec
+}
+
+// NewAgentMetrics returns the metrics of the tunneling agent.
+func NewAgentMetrics() *AgentMetrics {
+	return &AgentMetrics{
+		Tunnels: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "kubermatic_tunneling_agent_tunnels",
+			Help: "The number of idle tunnels registered with the tunneling server",
+		}),
+		Reconnects: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "kubermatic_tunneling_agent_reconnects_total",
+			Help: "The number of tunnels that could not be established or were lost while idle",
+		}),
+		Streams: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "kubermatic_tunneling_agent_streams_total",
+			Help: "The number of streams requested by the tunneling server",
+		}, []string{"result"}),
+		ActiveStreams: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "kubermatic_tunneling_agent_active_streams",
+			Help: "The number of streams currently carried by the agent",
+		}),
+		Bytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "kubermatic_tunneling_agent_bytes_total",
+			Help: "The number of bytes carried by the streams, in is towards the user cluster",
+		}, []string{"direction"}),
+	}
+}
+
+// MustRegister registers the metrics with the registerer.
+func (m *AgentMetrics) MustRegister(registerer prometheus.Registerer) {
+	registerer.MustRegister(m.Tunnels, m.Reconnects, m.Streams, m.ActiveStreams, m.Bytes)
+}
+
+// ServerMetrics are the metrics of the tunneling server.
+type ServerMetrics struct {
+	// Tunnels is the number of idle tunnels registered by the agents.
+	Tunnels prometheus.Gauge
+	// Registrations counts the tunnels opened by the agents by result.
+	Registrations *prometheus.CounterVec
+	// Streams counts the CONNECT requests by result.
+	Streams *prometheus.CounterVec
+	// ActiveStreams is the number of streams currently carried.
+	ActiveStreams prometheus.Gauge
+	// Bytes counts the bytes carried by the streams by direction.
+	Bytes *prometheus.CounterVec
+}
+
+// NewServerMetrics returns the metrics of the tunneling server.
+func NewServerMetrics() *ServerMetrics {
+	return &ServerMetrics{
+		Tunnels: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "kubermatic_tunneling_server_tunnels",
+			Help: "The number of idle tunnels registered by the tunneling agents",
+		}),
+		Registrations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "kubermatic_tunneling_server_registrations_total",
+			Help: "The number of tunnels opened by the tunneling agents",
+		}, []string{"result"}),
+		Streams: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "kubermatic_tunneling_server_streams_total",
+			Help: "The number of CONNECT requests handled by the tunneling server",
+		}, []string{"result"}),
+		ActiveStreams: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "kubermatic_tunneling_server_active_streams",
+			Help: "The number of streams currently carried by the server",
+		}),
+		Bytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "kubermatic_tunneling_server_bytes_total",
+			Help: "The number of bytes carried by the streams, in is towards the user cluster",
+		}, []string{"direction"}),
+	}
+}
+
+// MustRegister registers the metrics with the registerer.
+func (m *ServerMetrics) MustRegister(registerer prometheus.Registerer) {
+	registerer.MustRegister(m.Tunnels, m.Registrations, m.Streams, m.ActiveStreams, m.Bytes)
+}
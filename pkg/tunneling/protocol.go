@@ -0,0 +1,179 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunneling
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+)
+
+// The messages exchanged on a tunnel before it carries a stream. Every message
+// is a single line of space separated fields.
+const (
+	// msgChallenge is sent by the server with a random nonce right after the
+	// tunnel has been opened.
+	msgChallenge = "CHALLENGE"
+	// msgHello is the answer of the agent to the challenge, carrying the node
+	// name, the signed nonce and the comma separated node addresses.
+	msgHello = "HELLO"
+	// msgDial is sent by the server to ask the agent to dial the given
+	// address, the agent answers with msgOK or msgError.
+	msgDial = "DIAL"
+	// msgPing is sent by the server on idle tunnels, the agent answers with
+	// msgPong.
+	msgPing = "PING"
+	msgPong = "PONG"
+
+	msgOK    = "OK"
+	msgError = "ERR"
+
+	// maxLineLength is the maximum length of a message.
+	maxLineLength = 4096
+)
+
+var errLineTooLong = errors.New("message too long")
+
+// newNonce returns a random hex encoded nonce.
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// signNonce returns the hex encoded HMAC of the nonce keyed with the token.
+func signNonce(token, nonce string) string {
+	mac := hmac.New(sha256.New, []byte(token))
+	mac.Write([]byte(nonce))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// validSignature tells if the signature is the one of the nonce keyed with the
+// token.
+func validSignature(token, nonce, signature string) bool {
+	return hmac.Equal([]byte(signNonce(token, nonce)), []byte(signature))
+}
+
+func writeMessage(w io.Writer, fields ...string) error {
+	_, err := io.WriteString(w, strings.Join(fields, " ")+"\n")
+	return err
+}
+
+func readMessage(r *bufio.Reader) ([]string, error) {
+	var line []byte
+	for {
+		chunk, isPrefix, err := r.ReadLine()
+		if err != nil {
+			return nil, err
+		}
+		line = append(line, chunk...)
+		if len(line) > maxLineLength {
+			return nil, errLineTooLong
+		}
+		if !isPrefix {
+			break
+		}
+	}
+	fields := strings.Fields(string(line))
+	if len(fields) == 0 {
+		return nil, errors.New("empty message")
+	}
+	return fields, nil
+}
+
+// expectMessage reads a message and returns its arguments if it has the
+// expected type. A msgError is returned as error.
+func expectMessage(r *bufio.Reader, msgType string) ([]string, error) {
+	fields, err := readMessage(r)
+	if err != nil {
+		return nil, err
+	}
+	if fields[0] == msgError && msgType != msgError {
+		return nil, &remoteError{msg: strings.Join(fields[1:], " ")}
+	}
+	if fields[0] != msgType {
+		return nil, fmt.Errorf("expected %s message, got %s", msgType, fields[0])
+	}
+	return fields[1:], nil
+}
+
+// remoteError is an error reported by the other end of the tunnel.
+type remoteError struct {
+	msg string
+}
+
+func (e *remoteError) Error() string {
+	return e.msg
+}
+
+// bufferedConn is a connection whose reads go through the reader used to read
+// the messages, so that no data buffered after the last message is lost.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
+
+// countingWriter calls add with the number of bytes written to w.
+type countingWriter struct {
+	w   io.Writer
+	add func(int)
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.add(n)
+	return n, err
+}
+
+// pipe copies the data between a and b until one of the directions is done,
+// then closes both connections. The number of bytes copied from a to b and
+// from b to a are passed to aToB and bToA.
+func pipe(a, b net.Conn, aToB, bToA func(int)) {
+	var once sync.Once
+	closeBoth := func() {
+		a.Close()
+		b.Close()
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(&countingWriter{w: b, add: aToB}, a)
+		once.Do(closeBoth)
+	}()
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(&countingWriter{w: a, add: bToA}, b)
+		once.Do(closeBoth)
+	}()
+	wg.Wait()
+}
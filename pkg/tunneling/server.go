@@ -0,0 +1,334 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunneling
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// maxDialAttempts is the number of tunnels tried for a CONNECT request before
+// giving up, tunnels can be found broken only when they are used.
+const maxDialAttempts = 3
+
+// ServerOptions are the options of the tunneling server.
+type ServerOptions struct {
+	// Token is the secret shared with the tunneling agents.
+	Token string
+	// HandshakeTimeout is the timeout for the registration of a tunnel.
+	HandshakeTimeout time.Duration
+	// PingInterval is the interval between the pings sent on idle tunnels.
+	PingInterval time.Duration
+	// DialTimeout is the timeout for the agent to dial a target.
+	DialTimeout time.Duration
+	// ClientCommonName is the common name of the verified client certificate
+	// required for the CONNECT requests, no certificate is required if empty.
+	ClientCommonName string
+}
+
+// Server accepts the tunnels opened by the agents and serves an HTTP CONNECT
+// proxy carrying the streams through them.
+type Server struct {
+	opts    ServerOptions
+	log     *zap.SugaredLogger
+	metrics *ServerMetrics
+
+	lock sync.Mutex
+	// idle are the idle tunnels by node name.
+	idle map[string][]*tunnel
+	// nodes are the node names by address.
+	nodes map[string]string
+}
+
+// tunnel is a registered tunnel of an agent.
+type tunnel struct {
+	node string
+	conn *bufferedConn
+}
+
+// NewServer returns a new tunneling server.
+func NewServer(opts ServerOptions, log *zap.SugaredLogger, metrics *ServerMetrics) *Server {
+	return &Server{
+		opts:    opts,
+		log:     log,
+		metrics: metrics,
+		idle:    map[string][]*tunnel{},
+		nodes:   map[string]string{},
+	}
+}
+
+// ServeAgents accepts the tunnels of the agents on the listener until it is
+// closed.
+func (s *Server) ServeAgents(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go s.handleTunnel(conn)
+	}
+}
+
+func (s *Server) handleTunnel(conn net.Conn) {
+	t, err := s.register(conn)
+	if err != nil {
+		s.metrics.Registrations.WithLabelValues(resultError).Inc()
+		s.log.Debugw("Failed to register tunnel", "remote", conn.RemoteAddr(), zap.Error(err))
+		conn.Close()
+		return
+	}
+	s.metrics.Registrations.WithLabelValues(resultSuccess).Inc()
+	s.put(t)
+
+	// Keep the tunnel alive while it is idle, a tunnel taken for a stream is
+	// not put back.
+	for {
+		time.Sleep(s.opts.PingInterval)
+		if !s.takeTunnel(t) {
+			return
+		}
+		if err := s.ping(t); err != nil {
+			s.log.Debugw("Lost idle tunnel", "node", t.node, zap.Error(err))
+			t.conn.Close()
+			return
+		}
+		s.put(t)
+	}
+}
+
+func (s *Server) register(conn net.Conn) (*tunnel, error) {
+	if err := conn.SetDeadline(time.Now().Add(s.opts.HandshakeTimeout)); err != nil {
+		return nil, err
+	}
+
+	nonce, err := newNonce()
+	if err != nil {
+		return nil, err
+	}
+	if err := writeMessage(conn, msgChallenge, nonce); err != nil {
+		return nil, err
+	}
+
+	r := bufio.NewReader(conn)
+	args, err := expectMessage(r, msgHello)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) < 2 || len(args) > 3 {
+		return nil, errors.New("malformed hello")
+	}
+	if !validSignature(s.opts.Token, nonce, args[1]) {
+		_ = writeMessage(conn, msgError, "invalid signature")
+		return nil, fmt.Errorf("invalid signature from node %q", args[0])
+	}
+	if err := writeMessage(conn, msgOK); err != nil {
+		return nil, err
+	}
+
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+
+	t := &tunnel{node: args[0], conn: &bufferedConn{Conn: conn, r: r}}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(args) == 3 {
+		for _, address := range strings.Split(args[2], ",") {
+			if address != "" {
+				s.nodes[address] = t.node
+			}
+		}
+	}
+	return t, nil
+}
+
+func (s *Server) ping(t *tunnel) error {
+	if err := t.conn.SetDeadline(time.Now().Add(s.opts.DialTimeout)); err != nil {
+		return err
+	}
+	if err := writeMessage(t.conn, msgPing); err != nil {
+		return err
+	}
+	if _, err := expectMessage(t.conn.r, msgPong); err != nil {
+		return err
+	}
+	return t.conn.SetDeadline(time.Time{})
+}
+
+// put adds the tunnel to the idle ones.
+func (s *Server) put(t *tunnel) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.idle[t.node] = append(s.idle[t.node], t)
+	s.metrics.Tunnels.Inc()
+}
+
+// takeTunnel removes the tunnel from the idle ones and tells if it was idle.
+func (s *Server) takeTunnel(t *tunnel) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	tunnels := s.idle[t.node]
+	for i := range tunnels {
+		if tunnels[i] == t {
+			s.removeIdle(t.node, i)
+			return true
+		}
+	}
+	return false
+}
+
+// take removes and returns an idle tunnel for the host, preferring the ones of
+// the node owning the host address. It returns nil when no tunnel is idle.
+func (s *Server) take(host string) *tunnel {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	node, ok := s.nodes[host]
+	if !ok || len(s.idle[node]) == 0 {
+		node = ""
+		for name, tunnels := range s.idle {
+			if len(tunnels) > 0 {
+				node = name
+				break
+			}
+		}
+	}
+	if node == "" {
+		return nil
+	}
+
+	last := len(s.idle[node]) - 1
+	t := s.idle[node][last]
+	s.removeIdle(node, last)
+	return t
+}
+
+func (s *Server) removeIdle(node string, i int) {
+	tunnels := s.idle[node]
+	tunnels = append(tunnels[:i], tunnels[i+1:]...)
+	if len(tunnels) == 0 {
+		delete(s.idle, node)
+	} else {
+		s.idle[node] = tunnels
+	}
+	s.metrics.Tunnels.Dec()
+}
+
+// dial asks the agent to dial the address on the tunnel.
+func (s *Server) dial(t *tunnel, address string) error {
+	if err := t.conn.SetDeadline(time.Now().Add(s.opts.DialTimeout)); err != nil {
+		return err
+	}
+	if err := writeMessage(t.conn, msgDial, address); err != nil {
+		return err
+	}
+	if _, err := expectMessage(t.conn.r, msgOK); err != nil {
+		return err
+	}
+	return t.conn.SetDeadline(time.Time{})
+}
+
+// clientCommonName returns the common name of the verified client certificate
+// of the request, it is empty if the client did not present a valid one.
+func clientCommonName(r *http.Request) string {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	return r.TLS.VerifiedChains[0][0].Subject.CommonName
+}
+
+// ServeHTTP serves the CONNECT requests, carrying them through the tunnels.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodConnect {
+		http.Error(w, "only CONNECT requests are supported", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.opts.ClientCommonName != "" && clientCommonName(r) != s.opts.ClientCommonName {
+		http.Error(w, "a valid client certificate is required", http.StatusForbidden)
+		return
+	}
+	host, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid address %q: %v", r.Host, err), http.StatusBadRequest)
+		return
+	}
+
+	var t *tunnel
+	for attempt := 0; attempt < maxDialAttempts; attempt++ {
+		t = s.take(host)
+		if t == nil {
+			break
+		}
+		err = s.dial(t, r.Host)
+		if err == nil {
+			break
+		}
+		t.conn.Close()
+		t = nil
+
+		var remoteErr *remoteError
+		if errors.As(err, &remoteErr) {
+			s.metrics.Streams.WithLabelValues(resultError).Inc()
+			http.Error(w, fmt.Sprintf("failed to dial %s: %v", r.Host, err), http.StatusBadGateway)
+			return
+		}
+		s.log.Debugw("Failed to use tunnel", zap.Error(err))
+	}
+	if t == nil {
+		s.metrics.Streams.WithLabelValues(resultError).Inc()
+		http.Error(w, "no tunnel available", http.StatusServiceUnavailable)
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		t.conn.Close()
+		http.Error(w, "connection cannot be hijacked", http.StatusInternalServerError)
+		return
+	}
+	clientConn, buf, err := hijacker.Hijack()
+	if err != nil {
+		t.conn.Close()
+		http.Error(w, fmt.Sprintf("failed to hijack connection: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if _, err := clientConn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n")); err != nil {
+		s.metrics.Streams.WithLabelValues(resultError).Inc()
+		t.conn.Close()
+		clientConn.Close()
+		return
+	}
+	s.metrics.Streams.WithLabelValues(resultSuccess).Inc()
+
+	s.metrics.ActiveStreams.Inc()
+	defer s.metrics.ActiveStreams.Dec()
+
+	in := s.metrics.Bytes.WithLabelValues(DirectionIn)
+	out := s.metrics.Bytes.WithLabelValues(DirectionOut)
+	pipe(&bufferedConn{Conn: clientConn, r: buf.Reader}, t.conn,
+		func(n int) { in.Add(float64(n)) },
+		func(n int) { out.Add(float64(n)) })
+}
@@ -0,0 +1,419 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunneling
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	testToken     = "secret"
+	testAuthority = "tunneling-server.cluster-test.svc.cluster.local:8132"
+)
+
+// envoyStandIn stands in for the HTTP CONNECT listener of the nodeport-proxy,
+// it tunnels the CONNECT requests for the known authorities to their backends.
+type envoyStandIn struct {
+	listener net.Listener
+	routes   map[string]string
+
+	lock  sync.Mutex
+	conns []net.Conn
+}
+
+func newEnvoyStandIn(t *testing.T, routes map[string]string) *envoyStandIn {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	e := &envoyStandIn{listener: l, routes: routes}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go e.handle(conn)
+		}
+	}()
+	t.Cleanup(func() { l.Close() })
+	return e
+}
+
+func (e *envoyStandIn) Addr() string {
+	return e.listener.Addr().String()
+}
+
+func (e *envoyStandIn) handle(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	req, err := http.ReadRequest(r)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	backend, ok := e.routes[req.Host]
+	if req.Method != http.MethodConnect || !ok {
+		_, _ = conn.Write([]byte("HTTP/1.1 404 Not Found\r\n\r\n"))
+		conn.Close()
+		return
+	}
+	backendConn, err := net.Dial("tcp", backend)
+	if err != nil {
+		_, _ = conn.Write([]byte("HTTP/1.1 503 Service Unavailable\r\n\r\n"))
+		conn.Close()
+		return
+	}
+	if _, err := conn.Write([]byte("HTTP/1.1 200 OK\r\n\r\n")); err != nil {
+		conn.Close()
+		backendConn.Close()
+		return
+	}
+
+	e.lock.Lock()
+	e.conns = append(e.conns, conn, backendConn)
+	e.lock.Unlock()
+
+	pipe(&bufferedConn{Conn: conn, r: r}, backendConn, func(int) {}, func(int) {})
+}
+
+// dropConnections closes all the tunneled connections, like a restart of the
+// proxy.
+func (e *envoyStandIn) dropConnections() {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	for _, conn := range e.conns {
+		conn.Close()
+	}
+	e.conns = nil
+}
+
+// startEchoServer starts a server writing back everything it reads.
+func startEchoServer(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+	t.Cleanup(func() { l.Close() })
+	return l.Addr().String()
+}
+
+type testSetup struct {
+	server        *Server
+	serverMetrics *ServerMetrics
+	agent         *Agent
+	agentMetrics  *AgentMetrics
+	envoy         *envoyStandIn
+	egress        *httptest.Server
+}
+
+func setup(t *testing.T, agentToken string) *testSetup {
+	log := kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar()
+
+	serverMetrics := NewServerMetrics()
+	server := NewServer(ServerOptions{
+		Token:            testToken,
+		HandshakeTimeout: time.Second,
+		PingInterval:     100 * time.Millisecond,
+		DialTimeout:      time.Second,
+	}, log, serverMetrics)
+
+	agentListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	go func() { _ = server.ServeAgents(agentListener) }()
+	t.Cleanup(func() { agentListener.Close() })
+
+	egress := httptest.NewServer(server)
+	t.Cleanup(egress.Close)
+
+	envoy := newEnvoyStandIn(t, map[string]string{testAuthority: agentListener.Addr().String()})
+
+	agentMetrics := NewAgentMetrics()
+	agent := NewAgent(AgentOptions{
+		ProxyAddress: envoy.Addr(),
+		Authority:    testAuthority,
+		NodeName:     "node-1",
+		Addresses:    []string{"10.0.0.1"},
+		Token:        agentToken,
+		Tunnels:      2,
+		DialTimeout:  time.Second,
+		IdleTimeout:  time.Second,
+		MinBackoff:   10 * time.Millisecond,
+		MaxBackoff:   100 * time.Millisecond,
+	}, log, agentMetrics)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go agent.Run(ctx)
+
+	return &testSetup{
+		server:        server,
+		serverMetrics: serverMetrics,
+		agent:         agent,
+		agentMetrics:  agentMetrics,
+		envoy:         envoy,
+		egress:        egress,
+	}
+}
+
+func waitForTunnels(t *testing.T, s *testSetup, tunnels float64) {
+	err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		return s.agent.Healthy() && testutil.ToFloat64(s.serverMetrics.Tunnels) == tunnels, nil
+	})
+	if err != nil {
+		t.Fatalf("expected %v idle tunnels, got %v", tunnels, testutil.ToFloat64(s.serverMetrics.Tunnels))
+	}
+}
+
+// connect sends a CONNECT request for the address to the egress proxy and
+// returns the connection and the response status code.
+func connect(t *testing.T, egress *httptest.Server, address string) (net.Conn, *bufio.Reader, int) {
+	conn, err := net.Dial("tcp", egress.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial egress proxy: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	if _, err := fmt.Fprintf(conn, "CONNECT %s HTTP/1.1\r\nHost: %s\r\n\r\n", address, address); err != nil {
+		t.Fatalf("failed to send CONNECT request: %v", err)
+	}
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, &http.Request{Method: http.MethodConnect})
+	if err != nil {
+		t.Fatalf("failed to read CONNECT response: %v", err)
+	}
+	return conn, r, resp.StatusCode
+}
+
+func echo(t *testing.T, conn net.Conn, r *bufio.Reader, msg string) {
+	if _, err := conn.Write([]byte(msg)); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	buf := make([]byte, len(msg))
+	if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatalf("failed to set deadline: %v", err)
+	}
+	if _, err := io.ReadFull(r, buf); err != nil {
+		t.Fatalf("failed to read: %v", err)
+	}
+	if string(buf) != msg {
+		t.Fatalf("expected %q, got %q", msg, string(buf))
+	}
+}
+
+func TestStreamThroughTunnel(t *testing.T) {
+	s := setup(t, testToken)
+	echoAddress := startEchoServer(t)
+	waitForTunnels(t, s, 2)
+
+	conn, r, code := connect(t, s.egress, echoAddress)
+	if code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, code)
+	}
+	echo(t, conn, r, "hello from the control plane")
+
+	// the consumed tunnel is replaced
+	waitForTunnels(t, s, 2)
+
+	if v := testutil.ToFloat64(s.agentMetrics.Streams.WithLabelValues(resultSuccess)); v != 1 {
+		t.Errorf("expected 1 successful stream on the agent, got %v", v)
+	}
+	if v := testutil.ToFloat64(s.agentMetrics.ActiveStreams); v != 1 {
+		t.Errorf("expected 1 active stream on the agent, got %v", v)
+	}
+	// the bytes are counted after being written, they can lag behind the echo
+	expected := float64(len("hello from the control plane"))
+	err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		return testutil.ToFloat64(s.agentMetrics.Bytes.WithLabelValues(DirectionIn)) == expected &&
+			testutil.ToFloat64(s.agentMetrics.Bytes.WithLabelValues(DirectionOut)) == expected &&
+			testutil.ToFloat64(s.serverMetrics.Bytes.WithLabelValues(DirectionIn)) == expected &&
+			testutil.ToFloat64(s.serverMetrics.Bytes.WithLabelValues(DirectionOut)) == expected, nil
+	})
+	if err != nil {
+		t.Errorf("expected %v bytes in each direction, got %v in and %v out on the agent",
+			expected, testutil.ToFloat64(s.agentMetrics.Bytes.WithLabelValues(DirectionIn)), testutil.ToFloat64(s.agentMetrics.Bytes.WithLabelValues(DirectionOut)))
+	}
+	if v := testutil.ToFloat64(s.agentMetrics.Reconnects); v != 0 {
+		t.Errorf("expected no reconnects, got %v", v)
+	}
+}
+
+func TestStreamToUnreachableTarget(t *testing.T) {
+	s := setup(t, testToken)
+	waitForTunnels(t, s, 2)
+
+	// grab a free port and release it so nothing listens on it
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	address := l.Addr().String()
+	l.Close()
+
+	if _, _, code := connect(t, s.egress, address); code != http.StatusBadGateway {
+		t.Fatalf("expected status %d, got %d", http.StatusBadGateway, code)
+	}
+	if v := testutil.ToFloat64(s.agentMetrics.Streams.WithLabelValues(resultError)); v != 1 {
+		t.Errorf("expected 1 failed stream on the agent, got %v", v)
+	}
+}
+
+func TestAgentWithInvalidToken(t *testing.T) {
+	s := setup(t, "wrong")
+
+	err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		return testutil.ToFloat64(s.serverMetrics.Registrations.WithLabelValues(resultError)) >= 2, nil
+	})
+	if err != nil {
+		t.Fatal("expected the tunnels to be refused")
+	}
+	if s.agent.Healthy() {
+		t.Error("expected the agent not to be healthy")
+	}
+	if v := testutil.ToFloat64(s.agentMetrics.Reconnects); v < 2 {
+		t.Errorf("expected the agent to retry, got %v reconnects", v)
+	}
+
+	if _, _, code := connect(t, s.egress, "127.0.0.1:1"); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status %d, got %d", http.StatusServiceUnavailable, code)
+	}
+}
+
+func TestAgentReconnects(t *testing.T) {
+	s := setup(t, testToken)
+	echoAddress := startEchoServer(t)
+	waitForTunnels(t, s, 2)
+
+	s.envoy.dropConnections()
+
+	err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		return testutil.ToFloat64(s.agentMetrics.Reconnects) >= 2, nil
+	})
+	if err != nil {
+		t.Fatalf("expected the agent to reconnect, got %v reconnects", testutil.ToFloat64(s.agentMetrics.Reconnects))
+	}
+
+	// the server finds out about the broken tunnels with the pings
+	waitForTunnels(t, s, 2)
+
+	conn, r, code := connect(t, s.egress, echoAddress)
+	if code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, code)
+	}
+	echo(t, conn, r, "hello again")
+}
+
+func TestTakePrefersNodeOwningAddress(t *testing.T) {
+	s := NewServer(ServerOptions{}, kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(), NewServerMetrics())
+	s.nodes["10.0.0.1"] = "node-1"
+	s.nodes["10.0.0.2"] = "node-2"
+	s.put(&tunnel{node: "node-1"})
+	s.put(&tunnel{node: "node-2"})
+
+	if tun := s.take("10.0.0.2"); tun == nil || tun.node != "node-2" {
+		t.Fatalf("expected a tunnel of node-2, got %v", tun)
+	}
+	// no tunnel left for node-2, any other node is used
+	if tun := s.take("10.0.0.2"); tun == nil || tun.node != "node-1" {
+		t.Fatalf("expected a tunnel of node-1, got %v", tun)
+	}
+	if tun := s.take("10.0.0.1"); tun != nil {
+		t.Fatalf("expected no tunnel, got %v", tun)
+	}
+}
+
+func TestConnectRequiresClientCommonName(t *testing.T) {
+	s := NewServer(ServerOptions{ClientCommonName: "apiserver"}, kubermaticlog.New(true, kubermaticlog.FormatConsole).Sugar(), NewServerMetrics())
+
+	verifiedState := func(commonName string) *tls.ConnectionState {
+		return &tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: commonName}}}},
+		}
+	}
+
+	testCases := []struct {
+		name         string
+		tls          *tls.ConnectionState
+		expectedCode int
+	}{
+		{
+			name:         "no TLS",
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "no client certificate",
+			tls:          &tls.ConnectionState{},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "other client certificate",
+			tls:          verifiedState("admin"),
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			// no tunnel is registered, but the request is accepted
+			name:         "client certificate of the apiserver",
+			tls:          verifiedState("apiserver"),
+			expectedCode: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodConnect, "http://10.0.0.1:10250", nil)
+			req.Host = "10.0.0.1:10250"
+			req.TLS = tc.tls
+			rec := httptest.NewRecorder()
+
+			s.ServeHTTP(rec, req)
+
+			if rec.Code != tc.expectedCode {
+				t.Fatalf("expected status %d, got %d: %s", tc.expectedCode, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,341 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package encryption implements envelope encryption of files, like etcd snapshots, using AES-GCM.
+//
+// Every file is encrypted with a random data key, which is itself encrypted with a long-lived key
+// encryption key and stored together with the key's ID in a clear text header in front of the
+// encrypted content. This allows to rotate key encryption keys without re-encrypting existing files,
+// as long as the old keys are still available for decryption.
+//
+// The content is encrypted in chunks, so that arbitrarily large files can be processed without
+// loading them into memory. The nonce of every chunk contains the chunk's index and a flag marking
+// the last chunk, so that reordered or truncated files fail to decrypt.
+package encryption
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// Algorithm is the algorithm used for both the data and the key encryption
+	Algorithm = "AES-GCM"
+
+	// magic identifies encrypted files
+	magic = "KKPENC01"
+	// chunkSize is the size of the plain text chunks
+	chunkSize = 64 * 1024
+	// dataKeySize is the size of the random per-file data keys
+	dataKeySize = 32
+	// maxHeaderSize protects against allocating huge buffers for corrupt headers
+	maxHeaderSize = 64 * 1024
+	// maxChunkSize protects against allocating huge chunk buffers for corrupt or forged headers
+	maxChunkSize = 16 * 1024 * 1024
+)
+
+// Header is stored in clear text in front of the encrypted content
+type Header struct {
+	// Algorithm is the encryption algorithm, always Algorithm
+	Algorithm string `json:"algorithm"`
+	// KeyID identifies the key encryption key the data key was encrypted with
+	KeyID string `json:"keyID"`
+	// EncryptedDataKey is the nonce and the encrypted data key
+	EncryptedDataKey []byte `json:"encryptedDataKey"`
+	// ChunkSize is the size of the plain text chunks
+	ChunkSize int `json:"chunkSize"`
+}
+
+// KeyNotFoundError is returned when a file was encrypted with a key that is not available
+type KeyNotFoundError struct {
+	KeyID string
+}
+
+func (e *KeyNotFoundError) Error() string {
+	return fmt.Sprintf("key %q not found", e.KeyID)
+}
+
+// Encrypt reads plain text from src and writes the encrypted content, including the header, to dst.
+func Encrypt(dst io.Writer, src io.Reader, keyID string, key []byte) error {
+	if keyID == "" {
+		return errors.New("key ID must not be empty")
+	}
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return fmt.Errorf("failed to generate data key: %v", err)
+	}
+
+	keyAEAD, err := newAEAD(key)
+	if err != nil {
+		return fmt.Errorf("invalid key %q: %v", keyID, err)
+	}
+	nonce := make([]byte, keyAEAD.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %v", err)
+	}
+
+	header := Header{
+		Algorithm:        Algorithm,
+		KeyID:            keyID,
+		EncryptedDataKey: keyAEAD.Seal(nonce, nonce, dataKey, []byte(keyID)),
+		ChunkSize:        chunkSize,
+	}
+	if err := writeHeader(dst, header); err != nil {
+		return err
+	}
+
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return err
+	}
+
+	return processChunks(dst, src, chunkSize, func(index uint64, last bool, chunk []byte) ([]byte, error) {
+		return dataAEAD.Seal(nil, chunkNonce(dataAEAD, index, last), chunk, nil), nil
+	})
+}
+
+// Decrypt reads encrypted content from src and writes the plain text to dst. The key to decrypt the
+// data key with is looked up in keys by the key ID stored in the header.
+func Decrypt(dst io.Writer, src io.Reader, keys map[string][]byte) (*Header, error) {
+	r := bufio.NewReader(src)
+
+	header, err := readHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	if header.Algorithm != Algorithm {
+		return nil, fmt.Errorf("unsupported algorithm %q", header.Algorithm)
+	}
+	if header.ChunkSize <= 0 || header.ChunkSize > maxChunkSize {
+		return nil, fmt.Errorf("invalid chunk size %d", header.ChunkSize)
+	}
+
+	key, ok := keys[header.KeyID]
+	if !ok {
+		return nil, &KeyNotFoundError{KeyID: header.KeyID}
+	}
+
+	keyAEAD, err := newAEAD(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key %q: %v", header.KeyID, err)
+	}
+	if len(header.EncryptedDataKey) < keyAEAD.NonceSize() {
+		return nil, errors.New("encrypted data key is too short")
+	}
+	nonce, encryptedDataKey := header.EncryptedDataKey[:keyAEAD.NonceSize()], header.EncryptedDataKey[keyAEAD.NonceSize():]
+	dataKey, err := keyAEAD.Open(nil, nonce, encryptedDataKey, []byte(header.KeyID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data key with key %q: %v", header.KeyID, err)
+	}
+
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	err = processChunks(dst, r, header.ChunkSize+dataAEAD.Overhead(), func(index uint64, last bool, chunk []byte) ([]byte, error) {
+		plain, err := dataAEAD.Open(nil, chunkNonce(dataAEAD, index, last), chunk, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt chunk %d, content is corrupt or truncated: %v", index, err)
+		}
+		return plain, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return header, nil
+}
+
+// IsEncrypted returns true if the given file starts with the header of an encrypted file.
+func IsEncrypted(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, len(magic))
+	if _, err := io.ReadFull(f, buf); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return string(buf) == magic, nil
+}
+
+// EncryptFile encrypts the given file in place.
+func EncryptFile(path, keyID string, key []byte) error {
+	return rewriteFile(path, path, func(dst io.Writer, src io.Reader) error {
+		return Encrypt(dst, src, keyID, key)
+	})
+}
+
+// DecryptFile decrypts src and writes the plain text to dst, which may be the same file.
+func DecryptFile(src, dst string, keys map[string][]byte) (*Header, error) {
+	var header *Header
+	err := rewriteFile(src, dst, func(w io.Writer, r io.Reader) error {
+		var err error
+		header, err = Decrypt(w, r, keys)
+		return err
+	})
+	return header, err
+}
+
+// rewriteFile writes the output of transform into a temporary file next to dst and
+// renames it once the transformation succeeded, so that dst is never left half-written.
+func rewriteFile(src, dst string, transform func(io.Writer, io.Reader) error) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := ioutil.TempFile(filepath.Dir(dst), "."+filepath.Base(dst)+"-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(out.Name())
+
+	w := bufio.NewWriter(out)
+	if err := transform(w, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(out.Name(), dst)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// chunkNonce returns the nonce for the chunk with the given index. The data key is unique
+// per file, so a counter is sufficient to never reuse a nonce.
+func chunkNonce(aead cipher.AEAD, index uint64, last bool) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint64(nonce, index)
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+	return nonce
+}
+
+// processChunks reads src in chunks of the given size, passes them to process and writes the
+// result to dst. An empty src results in a single empty last chunk.
+func processChunks(dst io.Writer, src io.Reader, size int, process func(index uint64, last bool, chunk []byte) ([]byte, error)) error {
+	r := bufio.NewReaderSize(src, size)
+	buf := make([]byte, size)
+
+	for index := uint64(0); ; index++ {
+		n, err := io.ReadFull(r, buf)
+
+		last := false
+		switch err {
+		case nil:
+			if _, err := r.Peek(1); err == io.EOF {
+				last = true
+			} else if err != nil {
+				return err
+			}
+		case io.EOF, io.ErrUnexpectedEOF:
+			last = true
+		default:
+			return err
+		}
+
+		out, err := process(index, last, buf[:n])
+		if err != nil {
+			return err
+		}
+		if _, err := dst.Write(out); err != nil {
+			return err
+		}
+
+		if last {
+			return nil
+		}
+	}
+}
+
+func writeHeader(w io.Writer, header Header) error {
+	encoded, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString(magic)
+	if err := binary.Write(buf, binary.BigEndian, uint32(len(encoded))); err != nil {
+		return err
+	}
+	buf.Write(encoded)
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+func readHeader(r io.Reader) (*Header, error) {
+	buf := make([]byte, len(magic))
+	if _, err := io.ReadFull(r, buf); err != nil || string(buf) != magic {
+		return nil, errors.New("content is not encrypted")
+	}
+
+	var length uint32
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return nil, fmt.Errorf("failed to read header length: %v", err)
+	}
+	if length > maxHeaderSize {
+		return nil, fmt.Errorf("header size %d exceeds maximum of %d bytes", length, maxHeaderSize)
+	}
+
+	encoded := make([]byte, length)
+	if _, err := io.ReadFull(r, encoded); err != nil {
+		return nil, fmt.Errorf("failed to read header: %v", err)
+	}
+
+	header := &Header{}
+	if err := json.Unmarshal(encoded, header); err != nil {
+		return nil, fmt.Errorf("failed to parse header: %v", err)
+	}
+
+	return header, nil
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func randomBytes(t *testing.T, n int) []byte {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	oldKey := randomBytes(t, 32)
+	newKey := randomBytes(t, 32)
+
+	testCases := []struct {
+		name      string
+		plainSize int
+	}{
+		{name: "empty", plainSize: 0},
+		{name: "smaller than a chunk", plainSize: 100},
+		{name: "exactly one chunk", plainSize: chunkSize},
+		{name: "multiple chunks", plainSize: 3*chunkSize + 42},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			plain := randomBytes(t, tc.plainSize)
+
+			encrypted := &bytes.Buffer{}
+			if err := Encrypt(encrypted, bytes.NewReader(plain), "old", oldKey); err != nil {
+				t.Fatalf("failed to encrypt: %v", err)
+			}
+			if bytes.Contains(encrypted.Bytes(), plain) && tc.plainSize > 0 {
+				t.Fatal("encrypted content contains the plain text")
+			}
+
+			// a rotated key ring still contains the old key
+			keys := map[string][]byte{"old": oldKey, "new": newKey}
+			decrypted := &bytes.Buffer{}
+			header, err := Decrypt(decrypted, bytes.NewReader(encrypted.Bytes()), keys)
+			if err != nil {
+				t.Fatalf("failed to decrypt: %v", err)
+			}
+			if header.KeyID != "old" {
+				t.Errorf("expected key ID %q, got %q", "old", header.KeyID)
+			}
+			if !bytes.Equal(decrypted.Bytes(), plain) {
+				t.Error("decrypted content does not match the plain text")
+			}
+		})
+	}
+}
+
+func TestDecryptFailures(t *testing.T) {
+	key := randomBytes(t, 32)
+	plain := randomBytes(t, 2*chunkSize+10)
+
+	encrypted := &bytes.Buffer{}
+	if err := Encrypt(encrypted, bytes.NewReader(plain), "key", key); err != nil {
+		t.Fatalf("failed to encrypt: %v", err)
+	}
+	content := encrypted.Bytes()
+
+	testCases := []struct {
+		name    string
+		content func() []byte
+		keys    map[string][]byte
+		checkFn func(error) bool
+	}{
+		{
+			name:    "unknown key",
+			content: func() []byte { return content },
+			keys:    map[string][]byte{"other": key},
+			checkFn: func(err error) bool {
+				var keyErr *KeyNotFoundError
+				return errors.As(err, &keyErr) && keyErr.KeyID == "key"
+			},
+		},
+		{
+			name:    "wrong key material",
+			content: func() []byte { return content },
+			keys:    map[string][]byte{"key": randomBytes(t, 32)},
+		},
+		{
+			name: "modified content",
+			content: func() []byte {
+				modified := append([]byte{}, content...)
+				modified[len(modified)-100] ^= 0xff
+				return modified
+			},
+			keys: map[string][]byte{"key": key},
+		},
+		{
+			name: "truncated at chunk boundary",
+			content: func() []byte {
+				// drop the last chunk, including its authentication tag
+				return content[:len(content)-10-16]
+			},
+			keys: map[string][]byte{"key": key},
+		},
+		{
+			name: "chunk size too large",
+			content: func() []byte {
+				forged := &bytes.Buffer{}
+				if err := writeHeader(forged, Header{Algorithm: Algorithm, KeyID: "key", ChunkSize: maxChunkSize + 1}); err != nil {
+					t.Fatalf("failed to write header: %v", err)
+				}
+				return forged.Bytes()
+			},
+			keys: map[string][]byte{"key": key},
+			checkFn: func(err error) bool {
+				return strings.Contains(err.Error(), "invalid chunk size")
+			},
+		},
+		{
+			name:    "not encrypted",
+			content: func() []byte { return plain },
+			keys:    map[string][]byte{"key": key},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Decrypt(&bytes.Buffer{}, bytes.NewReader(tc.content()), tc.keys)
+			if err == nil {
+				t.Fatal("expected decryption to fail")
+			}
+			if tc.checkFn != nil && !tc.checkFn(err) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
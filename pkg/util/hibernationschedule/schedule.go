@@ -0,0 +1,129 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hibernationschedule evaluates the hibernation schedules of clusters, which hibernate and
+// resume clusters automatically.
+package hibernationschedule
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+)
+
+// maxLookback limits how far back the activations of a schedule are searched, so that an outdated
+// checked time does not require iterating over a long history of activations.
+const maxLookback = 7 * 24 * time.Hour
+
+// Schedule is a parsed hibernation schedule
+type Schedule struct {
+	location *time.Location
+	// hibernate and resume are nil if the schedule has no such expression
+	hibernate cron.Schedule
+	resume    cron.Schedule
+}
+
+// Parse parses the given hibernation schedule. It returns nil if neither expression is set.
+func Parse(schedule *kubermaticv1.HibernationSchedule) (*Schedule, error) {
+	if schedule == nil || (schedule.Hibernate == "" && schedule.Resume == "") {
+		return nil, nil
+	}
+
+	s := &Schedule{location: time.UTC}
+	if schedule.TimeZone != "" {
+		location, err := time.LoadLocation(schedule.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %v", schedule.TimeZone, err)
+		}
+		s.location = location
+	}
+
+	if schedule.Hibernate != "" {
+		hibernate, err := cron.ParseStandard(schedule.Hibernate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hibernate schedule %q: %v", schedule.Hibernate, err)
+		}
+		s.hibernate = hibernate
+	}
+	if schedule.Resume != "" {
+		resume, err := cron.ParseStandard(schedule.Resume)
+		if err != nil {
+			return nil, fmt.Errorf("invalid resume schedule %q: %v", schedule.Resume, err)
+		}
+		s.resume = resume
+	}
+
+	return s, nil
+}
+
+// ForCluster returns the hibernation schedule of the cluster, or nil if it has none.
+func ForCluster(cluster *kubermaticv1.Cluster) (*Schedule, error) {
+	if cluster.Spec.Hibernation == nil {
+		return nil, nil
+	}
+	return Parse(cluster.Spec.Hibernation.Schedule)
+}
+
+// Next returns the time of the next hibernation and resumption after t. A zero time is returned for
+// the expressions which are not set.
+func (s *Schedule) Next(t time.Time) (hibernate, resume time.Time) {
+	if s == nil {
+		return time.Time{}, time.Time{}
+	}
+	t = t.In(s.location)
+	if s.hibernate != nil {
+		hibernate = s.hibernate.Next(t)
+	}
+	if s.resume != nil {
+		resume = s.resume.Next(t)
+	}
+	return hibernate, resume
+}
+
+// Due returns whether the schedule was activated after since and up to now and, if so, whether
+// its last activation hibernates the cluster. If both expressions were last activated at the same
+// time, the cluster is resumed.
+func (s *Schedule) Due(since, now time.Time) (due bool, hibernate bool) {
+	if s == nil {
+		return false, false
+	}
+	if earliest := now.Add(-maxLookback); since.Before(earliest) {
+		since = earliest
+	}
+	since, now = since.In(s.location), now.In(s.location)
+
+	lastHibernate := lastActivation(s.hibernate, since, now)
+	lastResume := lastActivation(s.resume, since, now)
+	if lastHibernate.IsZero() && lastResume.IsZero() {
+		return false, false
+	}
+	return true, lastHibernate.After(lastResume)
+}
+
+// lastActivation returns the last activation of the schedule after since and up to now, or a zero time.
+func lastActivation(schedule cron.Schedule, since, now time.Time) time.Time {
+	var last time.Time
+	if schedule == nil {
+		return last
+	}
+	for next := schedule.Next(since); !next.IsZero() && !next.After(now); next = schedule.Next(next) {
+		last = next
+	}
+	return last
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hibernationschedule
+
+import (
+	"testing"
+	"time"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+)
+
+func mustParseTime(t *testing.T, value string) time.Time {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatalf("failed to parse time %q: %v", value, err)
+	}
+	return parsed
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name          string
+		schedule      *kubermaticv1.HibernationSchedule
+		expectedNil   bool
+		expectedError bool
+	}{
+		{
+			name:        "no schedule",
+			expectedNil: true,
+		},
+		{
+			name:        "no expressions",
+			schedule:    &kubermaticv1.HibernationSchedule{TimeZone: "Europe/Berlin"},
+			expectedNil: true,
+		},
+		{
+			name:     "hibernate and resume",
+			schedule: &kubermaticv1.HibernationSchedule{Hibernate: "0 20 * * *", Resume: "0 7 * * 1-5", TimeZone: "Europe/Berlin"},
+		},
+		{
+			name:     "only hibernate",
+			schedule: &kubermaticv1.HibernationSchedule{Hibernate: "0 20 * * *"},
+		},
+		{
+			name:          "invalid expression",
+			schedule:      &kubermaticv1.HibernationSchedule{Hibernate: "every night"},
+			expectedError: true,
+		},
+		{
+			name:          "invalid time zone",
+			schedule:      &kubermaticv1.HibernationSchedule{Resume: "0 7 * * *", TimeZone: "Mars/Olympus_Mons"},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := Parse(tc.schedule)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("expected error %v, got %v", tc.expectedError, err)
+			}
+			if !tc.expectedError && (schedule == nil) != tc.expectedNil {
+				t.Fatalf("expected nil schedule %v, got %v", tc.expectedNil, schedule)
+			}
+		})
+	}
+}
+
+func TestDue(t *testing.T) {
+	nightly := &kubermaticv1.HibernationSchedule{Hibernate: "0 20 * * *", Resume: "0 7 * * 1-5", TimeZone: "Europe/Berlin"}
+
+	testCases := []struct {
+		name              string
+		schedule          *kubermaticv1.HibernationSchedule
+		since             string
+		now               string
+		expectedDue       bool
+		expectedHibernate bool
+	}{
+		{
+			name:     "no activation",
+			schedule: nightly,
+			since:    "2021-06-01T08:00:00Z",
+			now:      "2021-06-01T17:00:00Z",
+		},
+		{
+			name:              "hibernated in the evening",
+			schedule:          nightly,
+			since:             "2021-06-01T17:00:00Z",
+			now:               "2021-06-01T18:00:00Z",
+			expectedDue:       true,
+			expectedHibernate: true,
+		},
+		{
+			name:        "resumed in the morning",
+			schedule:    nightly,
+			since:       "2021-06-01T18:00:00Z",
+			now:         "2021-06-02T05:00:00Z",
+			expectedDue: true,
+		},
+		{
+			name:              "stays hibernated over the weekend",
+			schedule:          nightly,
+			since:             "2021-06-04T17:59:00Z",
+			now:               "2021-06-07T04:00:00Z",
+			expectedDue:       true,
+			expectedHibernate: true,
+		},
+		{
+			name:        "the last of several activations applies",
+			schedule:    nightly,
+			since:       "2021-06-01T12:00:00Z",
+			now:         "2021-06-03T12:00:00Z",
+			expectedDue: true,
+		},
+		{
+			name:     "only resume",
+			schedule: &kubermaticv1.HibernationSchedule{Resume: "0 7 * * *"},
+			since:    "2021-06-01T06:00:00Z",
+			now:      "2021-06-01T06:59:59Z",
+		},
+		{
+			name:        "activation at now",
+			schedule:    &kubermaticv1.HibernationSchedule{Resume: "0 7 * * *"},
+			since:       "2021-06-01T06:00:00Z",
+			now:         "2021-06-01T07:00:00Z",
+			expectedDue: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := Parse(tc.schedule)
+			if err != nil {
+				t.Fatalf("failed to parse schedule: %v", err)
+			}
+			due, hibernate := schedule.Due(mustParseTime(t, tc.since), mustParseTime(t, tc.now))
+			if due != tc.expectedDue || hibernate != tc.expectedHibernate {
+				t.Errorf("expected due %v and hibernate %v, got %v and %v", tc.expectedDue, tc.expectedHibernate, due, hibernate)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	schedule, err := Parse(&kubermaticv1.HibernationSchedule{Hibernate: "0 20 * * *", TimeZone: "Europe/Berlin"})
+	if err != nil {
+		t.Fatalf("failed to parse schedule: %v", err)
+	}
+
+	hibernate, resume := schedule.Next(mustParseTime(t, "2021-06-01T19:00:00Z"))
+	if expected := mustParseTime(t, "2021-06-02T18:00:00Z"); !hibernate.Equal(expected) {
+		t.Errorf("expected the next hibernation at %s, got %s", expected, hibernate)
+	}
+	if !resume.IsZero() {
+		t.Errorf("expected no resumption, got %s", resume)
+	}
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package retention implements generational retention policies for backups, like
+// "keep the last 24 hourly, 7 daily, 4 weekly and 12 monthly backups".
+//
+// Every rule keeps the most recent backup of each of the last N periods (hours, days, ...)
+// that contain a backup. A backup is retained if at least one rule retains it, so the
+// rules of a policy do not interfere with each other.
+package retention
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Rule is a single rule of a retention policy
+type Rule string
+
+const (
+	// Latest retains the most recent backups, regardless of when they were created
+	Latest Rule = "latest"
+	// Hourly retains the most recent backup of every hour
+	Hourly Rule = "hourly"
+	// Daily retains the most recent backup of every day
+	Daily Rule = "daily"
+	// Weekly retains the most recent backup of every ISO 8601 week
+	Weekly Rule = "weekly"
+	// Monthly retains the most recent backup of every month
+	Monthly Rule = "monthly"
+)
+
+// Rules lists all rules in the order they are evaluated and reported
+var Rules = []Rule{Latest, Hourly, Daily, Weekly, Monthly}
+
+// Policy defines how many backups are retained under each rule. Rules with a count of zero
+// or less do not retain any backups.
+type Policy struct {
+	Latest  int
+	Hourly  int
+	Daily   int
+	Weekly  int
+	Monthly int
+}
+
+// Count returns the number of backups retained under the given rule.
+func (p Policy) Count(rule Rule) int {
+	switch rule {
+	case Latest:
+		return p.Latest
+	case Hourly:
+		return p.Hourly
+	case Daily:
+		return p.Daily
+	case Weekly:
+		return p.Weekly
+	case Monthly:
+		return p.Monthly
+	default:
+		return 0
+	}
+}
+
+// MaxRetained returns the maximum number of backups retained by the policy.
+func (p Policy) MaxRetained() int {
+	total := 0
+	for _, rule := range Rules {
+		if count := p.Count(rule); count > 0 {
+			total += count
+		}
+	}
+	return total
+}
+
+// Evaluate returns the rules under which each of the given backup times is retained, in
+// the same order as times. Backups without any rule are not retained and can be deleted.
+// Periods are evaluated in UTC.
+func Evaluate(policy Policy, times []time.Time) [][]Rule {
+	// newest first, so that the most recent backup of each period is retained
+	order := make([]int, len(times))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return times[order[i]].After(times[order[j]])
+	})
+
+	result := make([][]Rule, len(times))
+	for _, rule := range Rules {
+		count := policy.Count(rule)
+		kept := 0
+		lastPeriod := ""
+
+		for _, idx := range order {
+			if kept >= count {
+				break
+			}
+			if rule != Latest {
+				period := periodOf(rule, times[idx].UTC())
+				if period == lastPeriod {
+					continue
+				}
+				lastPeriod = period
+			}
+			kept++
+			result[idx] = append(result[idx], rule)
+		}
+	}
+
+	return result
+}
+
+// periodOf returns an identifier of the period t belongs to under the given rule.
+func periodOf(rule Rule, t time.Time) string {
+	switch rule {
+	case Hourly:
+		return t.Format("2006-01-02T15")
+	case Daily:
+		return t.Format("2006-01-02")
+	case Weekly:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case Monthly:
+		return t.Format("2006-01")
+	default:
+		return ""
+	}
+}
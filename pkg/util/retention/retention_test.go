@@ -0,0 +1,112 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retention
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+)
+
+func TestEvaluate(t *testing.T) {
+	// a Monday
+	base := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time {
+		return base.Add(d)
+	}
+
+	testCases := []struct {
+		name     string
+		policy   Policy
+		times    []time.Time
+		expected [][]Rule
+	}{
+		{
+			name:     "empty policy retains nothing",
+			times:    []time.Time{at(0), at(time.Hour)},
+			expected: [][]Rule{nil, nil},
+		},
+		{
+			name:     "latest retains the most recent backups regardless of order",
+			policy:   Policy{Latest: 2},
+			times:    []time.Time{at(2 * time.Hour), at(0), at(time.Hour)},
+			expected: [][]Rule{{Latest}, nil, {Latest}},
+		},
+		{
+			name:   "hourly retains the most recent backup of each hour",
+			policy: Policy{Hourly: 2},
+			times: []time.Time{
+				at(0),
+				at(30 * time.Minute),
+				at(time.Hour),
+				at(time.Hour + 30*time.Minute),
+				at(2*time.Hour + 10*time.Minute),
+			},
+			expected: [][]Rule{nil, nil, nil, {Hourly}, {Hourly}},
+		},
+		{
+			name:   "rules are combined",
+			policy: Policy{Latest: 1, Daily: 2, Weekly: 2},
+			times: []time.Time{
+				// Sunday of the previous week
+				at(-12 * time.Hour),
+				// Monday
+				at(6 * time.Hour),
+				at(12 * time.Hour),
+				// Tuesday
+				at(30 * time.Hour),
+				at(36 * time.Hour),
+			},
+			expected: [][]Rule{
+				{Weekly},
+				nil,
+				{Daily},
+				nil,
+				{Latest, Daily, Weekly},
+			},
+		},
+		{
+			name:   "monthly skips months without backups",
+			policy: Policy{Monthly: 2},
+			times: []time.Time{
+				time.Date(2021, 1, 15, 0, 0, 0, 0, time.UTC),
+				time.Date(2021, 1, 20, 0, 0, 0, 0, time.UTC),
+				time.Date(2021, 3, 5, 0, 0, 0, 0, time.UTC),
+			},
+			expected: [][]Rule{nil, {Monthly}, {Monthly}},
+		},
+		{
+			name:   "periods are evaluated in UTC",
+			policy: Policy{Daily: 2},
+			times: []time.Time{
+				time.Date(2021, 3, 1, 23, 0, 0, 0, time.UTC),
+				// same UTC day as above, but a different local day
+				time.Date(2021, 3, 2, 0, 30, 0, 0, time.FixedZone("CET", 3600)),
+			},
+			expected: [][]Rule{nil, {Daily}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := deep.Equal(Evaluate(tc.policy, tc.times), tc.expected); diff != nil {
+				t.Errorf("unexpected result, diff: %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package updatewindow evaluates the update windows of clusters, which restrict when
+// automatic updates are applied.
+package updatewindow
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/coreos/locksmith/pkg/timeutil"
+	"github.com/robfig/cron"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+)
+
+// Window is a parsed update window. A nil Window is always open.
+type Window struct {
+	location *time.Location
+	length   time.Duration
+	// exactly one of periodic and schedule is set
+	periodic *timeutil.Periodic
+	schedule cron.Schedule
+}
+
+// Parse parses the given update window. It returns nil if no window is configured. For
+// compatibility with Flatcar reboots, a start without a length does not configure a window.
+func Parse(updateWindow *kubermaticv1.UpdateWindow) (*Window, error) {
+	if updateWindow == nil {
+		return nil, nil
+	}
+	if updateWindow.Start != "" && updateWindow.Schedule != "" {
+		return nil, errors.New("start and schedule are mutually exclusive")
+	}
+	if updateWindow.Schedule != "" && updateWindow.Length == "" {
+		return nil, errors.New("a length is required for the schedule")
+	}
+	if updateWindow.Length == "" || (updateWindow.Start == "" && updateWindow.Schedule == "") {
+		return nil, nil
+	}
+
+	w := &Window{location: time.UTC}
+	if updateWindow.TimeZone != "" {
+		location, err := time.LoadLocation(updateWindow.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %v", updateWindow.TimeZone, err)
+		}
+		w.location = location
+	}
+
+	if updateWindow.Start != "" {
+		periodic, err := timeutil.ParsePeriodic(updateWindow.Start, updateWindow.Length)
+		if err != nil {
+			return nil, err
+		}
+		w.periodic = periodic
+		return w, nil
+	}
+
+	length, err := time.ParseDuration(updateWindow.Length)
+	if err != nil {
+		return nil, fmt.Errorf("invalid length %q: %v", updateWindow.Length, err)
+	}
+	if length <= 0 {
+		return nil, fmt.Errorf("length must be positive, got %q", updateWindow.Length)
+	}
+	w.length = length
+
+	schedule, err := cron.ParseStandard(updateWindow.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: %v", updateWindow.Schedule, err)
+	}
+	w.schedule = schedule
+
+	return w, nil
+}
+
+// ForCluster returns the update window which applies to the cluster: its own update window
+// or, if it has none, the window it inherited from its project.
+func ForCluster(cluster *kubermaticv1.Cluster) (*Window, error) {
+	if cluster.Spec.UpdateWindow != nil {
+		return Parse(cluster.Spec.UpdateWindow)
+	}
+	return Parse(cluster.Status.InheritedUpdateWindow)
+}
+
+// Period returns the period of the window which is open at the given time or, if the window is
+// closed, the next period it will be open.
+func (w *Window) Period(t time.Time) (start, end time.Time) {
+	if w == nil {
+		return t, t
+	}
+
+	t = t.In(w.location)
+	if w.periodic != nil {
+		if previous := w.periodic.Previous(t); !previous.Start.After(t) && previous.End.After(t) {
+			return previous.Start, previous.End
+		}
+		next := w.periodic.Next(t)
+		return next.Start, next.End
+	}
+
+	// The first activation after t-length is either within the window which is currently
+	// open or the start of the next one.
+	start = w.schedule.Next(t.Add(-w.length))
+	return start, start.Add(w.length)
+}
+
+// Contains returns whether the window is open at the given time.
+func (w *Window) Contains(t time.Time) bool {
+	if w == nil {
+		return true
+	}
+	start, _ := w.Period(t)
+	return !start.After(t)
+}
+
+// Next returns the time the window opens next after t, or t if the window is open.
+func (w *Window) Next(t time.Time) time.Time {
+	start, _ := w.Period(t)
+	if start.Before(t) {
+		return t
+	}
+	return start
+}
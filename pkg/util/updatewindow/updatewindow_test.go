@@ -0,0 +1,164 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package updatewindow
+
+import (
+	"testing"
+	"time"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+)
+
+func mustParseTime(t *testing.T, value string) time.Time {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatalf("failed to parse time %q: %v", value, err)
+	}
+	return parsed
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name          string
+		updateWindow  *kubermaticv1.UpdateWindow
+		expectedNil   bool
+		expectedError bool
+	}{
+		{
+			name:        "no window",
+			expectedNil: true,
+		},
+		{
+			name:         "start without length is no window",
+			updateWindow: &kubermaticv1.UpdateWindow{Start: "04:00"},
+			expectedNil:  true,
+		},
+		{
+			name:         "start",
+			updateWindow: &kubermaticv1.UpdateWindow{Start: "Thu 04:00", Length: "1h", TimeZone: "Europe/Berlin"},
+		},
+		{
+			name:         "schedule",
+			updateWindow: &kubermaticv1.UpdateWindow{Schedule: "0 2 * * 1-5", Length: "2h"},
+		},
+		{
+			name:          "schedule without length",
+			updateWindow:  &kubermaticv1.UpdateWindow{Schedule: "0 2 * * 1-5"},
+			expectedError: true,
+		},
+		{
+			name:          "start and schedule",
+			updateWindow:  &kubermaticv1.UpdateWindow{Start: "04:00", Schedule: "0 2 * * *", Length: "1h"},
+			expectedError: true,
+		},
+		{
+			name:          "invalid schedule",
+			updateWindow:  &kubermaticv1.UpdateWindow{Schedule: "every night", Length: "1h"},
+			expectedError: true,
+		},
+		{
+			name:          "invalid time zone",
+			updateWindow:  &kubermaticv1.UpdateWindow{Start: "04:00", Length: "1h", TimeZone: "Mars/Olympus_Mons"},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			window, err := Parse(tc.updateWindow)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("expected error to be %v, got %v", tc.expectedError, err)
+			}
+			if !tc.expectedError && (window == nil) != tc.expectedNil {
+				t.Errorf("expected window to be nil: %v, got %v", tc.expectedNil, window)
+			}
+		})
+	}
+}
+
+func TestWindow(t *testing.T) {
+	testCases := []struct {
+		name             string
+		updateWindow     *kubermaticv1.UpdateWindow
+		now              string
+		expectedContains bool
+		expectedNext     string
+	}{
+		{
+			name:             "no window is always open",
+			now:              "2021-03-04T13:00:00Z",
+			expectedContains: true,
+			expectedNext:     "2021-03-04T13:00:00Z",
+		},
+		{
+			name:             "weekly start is open",
+			updateWindow:     &kubermaticv1.UpdateWindow{Start: "Thu 04:00", Length: "2h"},
+			now:              "2021-03-04T05:00:00Z",
+			expectedContains: true,
+			expectedNext:     "2021-03-04T05:00:00Z",
+		},
+		{
+			name:         "weekly start is closed",
+			updateWindow: &kubermaticv1.UpdateWindow{Start: "Thu 04:00", Length: "2h"},
+			now:          "2021-03-04T13:00:00Z",
+			expectedNext: "2021-03-11T04:00:00Z",
+		},
+		{
+			name:             "start in time zone",
+			updateWindow:     &kubermaticv1.UpdateWindow{Start: "04:00", Length: "1h", TimeZone: "Europe/Berlin"},
+			now:              "2021-03-04T03:30:00Z",
+			expectedContains: true,
+			expectedNext:     "2021-03-04T03:30:00Z",
+		},
+		{
+			name:         "schedule on weekdays skips the weekend",
+			updateWindow: &kubermaticv1.UpdateWindow{Schedule: "0 2 * * 1-5", Length: "2h"},
+			now:          "2021-03-05T13:00:00Z",
+			expectedNext: "2021-03-08T02:00:00Z",
+		},
+		{
+			name:             "schedule is open",
+			updateWindow:     &kubermaticv1.UpdateWindow{Schedule: "0 2 * * 1-5", Length: "2h"},
+			now:              "2021-03-05T03:59:00Z",
+			expectedContains: true,
+			expectedNext:     "2021-03-05T03:59:00Z",
+		},
+		{
+			name:         "schedule in time zone",
+			updateWindow: &kubermaticv1.UpdateWindow{Schedule: "30 22 * * *", Length: "30m", TimeZone: "America/New_York"},
+			now:          "2021-03-05T12:00:00Z",
+			expectedNext: "2021-03-06T03:30:00Z",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			window, err := Parse(tc.updateWindow)
+			if err != nil {
+				t.Fatalf("failed to parse window: %v", err)
+			}
+
+			now := mustParseTime(t, tc.now)
+			if contains := window.Contains(now); contains != tc.expectedContains {
+				t.Errorf("expected window to contain %v: %v, got %v", now, tc.expectedContains, contains)
+			}
+			if next := window.Next(now); !next.Equal(mustParseTime(t, tc.expectedNext)) {
+				t.Errorf("expected window to open next at %s, got %v", tc.expectedNext, next)
+			}
+		})
+	}
+}
@@ -23,22 +23,29 @@ import (
 	"fmt"
 	"net"
 
-	"github.com/coreos/locksmith/pkg/timeutil"
+	"github.com/Masterminds/semver/v3"
 
+	"k8c.io/kubermatic/v2/pkg/cni"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	kuberneteshelper "k8c.io/kubermatic/v2/pkg/kubernetes"
 	"k8c.io/kubermatic/v2/pkg/provider"
 	"k8c.io/kubermatic/v2/pkg/provider/cloud"
 	kubernetesprovider "k8c.io/kubermatic/v2/pkg/provider/kubernetes"
 	"k8c.io/kubermatic/v2/pkg/resources"
+	"k8c.io/kubermatic/v2/pkg/util/hibernationschedule"
+	"k8c.io/kubermatic/v2/pkg/util/updatewindow"
 
 	"k8s.io/apimachinery/pkg/api/equality"
 	utilerror "k8s.io/apimachinery/pkg/util/errors"
+	netutils "k8s.io/utils/net"
 )
 
 var (
 	// ErrCloudChangeNotAllowed describes that it is not allowed to change the cloud provider
 	ErrCloudChangeNotAllowed = errors.New("not allowed to change the cloud provider")
+
+	// dualStackMinimumVersion is the first Kubernetes version with dual-stack enabled by default
+	dualStackMinimumVersion = semver.MustParse("1.21.0")
 )
 
 // ValidateCreateClusterSpec validates the given cluster spec
@@ -63,9 +70,82 @@ func ValidateCreateClusterSpec(spec *kubermaticv1.ClusterSpec, dc *kubermaticv1.
 		return fmt.Errorf("machine network validation failed, see: %v", err)
 	}
 
+	if spec.CNIPlugin != nil {
+		if err := cni.ValidatePlugin(*spec.CNIPlugin); err != nil {
+			return fmt.Errorf("invalid CNI plugin: %v", err)
+		}
+	}
+
+	if err := ValidateClusterNetworkConfig(spec); err != nil {
+		return fmt.Errorf("invalid cluster network: %v", err)
+	}
+
 	return nil
 }
 
+// ValidateClusterNetworkConfig validates the pod and service network ranges of the cluster. Empty ranges
+// are defaulted later on. Single-stack clusters have one IPv4 block, dual-stack clusters have an
+// additional IPv6 block for both the pods and the services.
+func ValidateClusterNetworkConfig(spec *kubermaticv1.ClusterSpec) error {
+	if err := validateNetworkRanges(spec.ClusterNetwork.Pods); err != nil {
+		return fmt.Errorf("invalid pod network: %v", err)
+	}
+	if err := validateNetworkRanges(spec.ClusterNetwork.Services); err != nil {
+		return fmt.Errorf("invalid service network: %v", err)
+	}
+
+	podsDualStack := resources.IPv6CIDRBlock(spec.ClusterNetwork.Pods) != ""
+	servicesDualStack := resources.IPv6CIDRBlock(spec.ClusterNetwork.Services) != ""
+	if !podsDualStack && !servicesDualStack {
+		return nil
+	}
+	if podsDualStack != servicesDualStack {
+		return errors.New("either both the pod and the service network or none of them must have an IPv6 block")
+	}
+	// The node IPAM controller gives every node a /64 of the IPv6 pod block and may use up to 16 bits for them
+	if _, podNet, _ := net.ParseCIDR(resources.IPv6CIDRBlock(spec.ClusterNetwork.Pods)); podNet != nil {
+		if ones, _ := podNet.Mask.Size(); ones < 48 || ones > 63 {
+			return fmt.Errorf("the prefix length of the IPv6 pod block must be between 48 and 63, got %d", ones)
+		}
+	}
+	// The apiserver allocates the IPv6 service IPs from at most 20 bits
+	if _, serviceNet, _ := net.ParseCIDR(resources.IPv6CIDRBlock(spec.ClusterNetwork.Services)); serviceNet != nil {
+		if ones, _ := serviceNet.Mask.Size(); ones < 108 {
+			return fmt.Errorf("the prefix length of the IPv6 service block must be at least 108, got %d", ones)
+		}
+	}
+	if spec.Version.Semver() != nil && spec.Version.Semver().LessThan(dualStackMinimumVersion) {
+		return fmt.Errorf("dual-stack requires Kubernetes %s or newer", dualStackMinimumVersion)
+	}
+	if plugin := cni.ClusterPlugin(spec); !cni.SupportsDualStack(plugin.Type) {
+		return fmt.Errorf("dual-stack is not supported by the CNI plugin %q", plugin.Type)
+	}
+	return nil
+}
+
+func validateNetworkRanges(ranges kubermaticv1.NetworkRanges) error {
+	switch len(ranges.CIDRBlocks) {
+	case 0:
+		return nil
+	case 1:
+		if !netutils.IsIPv4CIDRString(ranges.CIDRBlocks[0]) {
+			return fmt.Errorf("%q is no IPv4 CIDR block, IPv6 requires an additional IPv4 block", ranges.CIDRBlocks[0])
+		}
+		return nil
+	case 2:
+		dualStack, err := netutils.IsDualStackCIDRStrings(ranges.CIDRBlocks)
+		if err != nil {
+			return err
+		}
+		if !dualStack {
+			return errors.New("two CIDR blocks must be one IPv4 and one IPv6 block")
+		}
+		return nil
+	default:
+		return fmt.Errorf("at most two CIDR blocks are allowed, got %d", len(ranges.CIDRBlocks))
+	}
+}
+
 func validateMachineNetworksFromClusterSpec(spec *kubermaticv1.ClusterSpec) error {
 	networks := spec.MachineNetworks
 
@@ -219,6 +299,41 @@ func ValidateUpdateCluster(ctx context.Context, newCluster, oldCluster *kubermat
 		return fmt.Errorf("invalid cloud spec modification: %v", err)
 	}
 
+	if err := ValidateCNIPluginUpdate(&newCluster.Spec, &oldCluster.Spec); err != nil {
+		return err
+	}
+
+	if err := ValidateClusterNetworkUpdate(&newCluster.Spec, &oldCluster.Spec); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ValidateCNIPluginUpdate validates the change of the CNI plugin of a cluster. Clusters without a
+// CNI plugin run the legacy one, which can be set explicitly.
+func ValidateCNIPluginUpdate(newSpec, oldSpec *kubermaticv1.ClusterSpec) error {
+	if newSpec.CNIPlugin == nil {
+		if oldSpec.CNIPlugin != nil {
+			return errors.New("removing the CNI plugin is not allowed")
+		}
+		return nil
+	}
+	if err := cni.ValidateUpgrade(cni.ClusterPlugin(oldSpec), *newSpec.CNIPlugin); err != nil {
+		return fmt.Errorf("invalid CNI plugin: %v", err)
+	}
+	return nil
+}
+
+// ValidateClusterNetworkUpdate validates the change of the pod and service network ranges of a cluster. The IP
+// families of running clusters can not be changed, so IPv6 blocks can neither be added nor removed.
+func ValidateClusterNetworkUpdate(newSpec, oldSpec *kubermaticv1.ClusterSpec) error {
+	if oldDualStack, newDualStack := resources.IPv6CIDRBlock(oldSpec.ClusterNetwork.Pods) != "", resources.IPv6CIDRBlock(newSpec.ClusterNetwork.Pods) != ""; oldDualStack != newDualStack {
+		return errors.New("adding or removing the IPv6 block of the pod network is not allowed")
+	}
+	if oldDualStack, newDualStack := resources.IPv6CIDRBlock(oldSpec.ClusterNetwork.Services) != "", resources.IPv6CIDRBlock(newSpec.ClusterNetwork.Services) != ""; oldDualStack != newDualStack {
+		return errors.New("adding or removing the IPv6 block of the service network is not allowed")
+	}
 	return nil
 }
 
@@ -481,11 +596,33 @@ func validateAnexiaCloudSpec(spec *kubermaticv1.AnexiaCloudSpec) error {
 }
 
 func ValidateUpdateWindow(updateWindow *kubermaticv1.UpdateWindow) error {
-	if updateWindow != nil && updateWindow.Start != "" && updateWindow.Length != "" {
-		_, err := timeutil.ParsePeriodic(updateWindow.Start, updateWindow.Length)
-		if err != nil {
-			return fmt.Errorf("error parsing update window: %s", err)
-		}
+	if _, err := updatewindow.Parse(updateWindow); err != nil {
+		return fmt.Errorf("error parsing update window: %s", err)
+	}
+	return nil
+}
+
+// ValidateHibernation validates the hibernation settings of a cluster.
+func ValidateHibernation(hibernation *kubermaticv1.HibernationSettings) error {
+	if hibernation == nil {
+		return nil
+	}
+	if _, err := hibernationschedule.Parse(hibernation.Schedule); err != nil {
+		return fmt.Errorf("error parsing hibernation schedule: %s", err)
+	}
+	return nil
+}
+
+// ValidateSSHCertificateAuthority validates the ssh certificate authority of a project.
+func ValidateSSHCertificateAuthority(ca *kubermaticv1.ProjectSSHCertificateAuthority) error {
+	if ca == nil || ca.Validity == nil {
+		return nil
+	}
+	if ca.Validity.Duration <= 0 {
+		return fmt.Errorf("the validity of the ssh certificates must be positive, got %s", ca.Validity.Duration)
+	}
+	if ca.Validity.Duration > kubermaticv1.MaxSSHCertificateValidity {
+		return fmt.Errorf("the validity of the ssh certificates can not exceed %s, got %s", kubermaticv1.MaxSSHCertificateValidity, ca.Validity.Duration)
 	}
 	return nil
 }
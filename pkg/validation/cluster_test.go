@@ -23,6 +23,7 @@ import (
 	"testing"
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/semver"
 
 	"k8s.io/utils/pointer"
 )
@@ -145,6 +146,24 @@ func TestValidateUpdateWindow(t *testing.T) {
 			},
 			err: errors.New("missing unit in duration"),
 		},
+		{
+			name: "valid schedule",
+			updateWindow: kubermaticv1.UpdateWindow{
+				Schedule: "0 2 * * 1-5",
+				Length:   "2h",
+				TimeZone: "Europe/Berlin",
+			},
+			err: nil,
+		},
+		{
+			name: "start and schedule",
+			updateWindow: kubermaticv1.UpdateWindow{
+				Start:    "04:00",
+				Schedule: "0 2 * * 1-5",
+				Length:   "2h",
+			},
+			err: errors.New("mutually exclusive"),
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -232,3 +251,250 @@ func TestValidateLeaderElectionSettings(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateCNIPluginUpdate(t *testing.T) {
+	canal := &kubermaticv1.CNIPluginSettings{Type: kubermaticv1.CNIPluginTypeCanal, Version: "v3.8"}
+	cilium := &kubermaticv1.CNIPluginSettings{Type: kubermaticv1.CNIPluginTypeCilium, Version: "v1.9"}
+
+	tests := []struct {
+		name      string
+		oldPlugin *kubermaticv1.CNIPluginSettings
+		newPlugin *kubermaticv1.CNIPluginSettings
+		err       error
+	}{
+		{
+			name: "legacy cluster without plugin",
+		},
+		{
+			name:      "legacy cluster gets its plugin set",
+			newPlugin: canal,
+		},
+		{
+			name:      "legacy cluster can not change the type",
+			newPlugin: cilium,
+			err:       errors.New(`changing the CNI plugin type from "canal" to "cilium" is not supported`),
+		},
+		{
+			name:      "plugin can not be removed",
+			oldPlugin: cilium,
+			err:       errors.New("removing the CNI plugin is not allowed"),
+		},
+		{
+			name:      "version can be upgraded",
+			oldPlugin: cilium,
+			newPlugin: &kubermaticv1.CNIPluginSettings{Type: kubermaticv1.CNIPluginTypeCilium, Version: "v1.10"},
+		},
+		{
+			name:      "type can not be changed",
+			oldPlugin: cilium,
+			newPlugin: &kubermaticv1.CNIPluginSettings{Type: kubermaticv1.CNIPluginTypeNone},
+			err:       errors.New(`changing the CNI plugin type from "cilium" to "none" is not supported`),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateCNIPluginUpdate(&kubermaticv1.ClusterSpec{CNIPlugin: test.newPlugin}, &kubermaticv1.ClusterSpec{CNIPlugin: test.oldPlugin})
+			if (err != nil) != (test.err != nil) {
+				t.Errorf("Extected err to be %v, got %v", test.err, err)
+			}
+
+			// loosely validate the returned error message
+			if test.err != nil && !strings.Contains(err.Error(), test.err.Error()) {
+				t.Errorf("Extected err to contain \"%v\", but got \"%v\"", test.err, err)
+			}
+		})
+	}
+}
+
+func TestValidateClusterNetworkUpdate(t *testing.T) {
+	singleStack := kubermaticv1.ClusterNetworkingConfig{
+		Pods:     kubermaticv1.NetworkRanges{CIDRBlocks: []string{"172.25.0.0/16"}},
+		Services: kubermaticv1.NetworkRanges{CIDRBlocks: []string{"10.240.16.0/20"}},
+	}
+	dualStack := kubermaticv1.ClusterNetworkingConfig{
+		Pods:     kubermaticv1.NetworkRanges{CIDRBlocks: []string{"172.25.0.0/16", "fd00::/56"}},
+		Services: kubermaticv1.NetworkRanges{CIDRBlocks: []string{"10.240.16.0/20", "fd01::/108"}},
+	}
+
+	tests := []struct {
+		name       string
+		oldNetwork kubermaticv1.ClusterNetworkingConfig
+		newNetwork kubermaticv1.ClusterNetworkingConfig
+		err        error
+	}{
+		{
+			name:       "networks get defaulted",
+			newNetwork: singleStack,
+		},
+		{
+			name:       "single-stack is kept",
+			oldNetwork: singleStack,
+			newNetwork: singleStack,
+		},
+		{
+			name:       "dual-stack is kept",
+			oldNetwork: dualStack,
+			newNetwork: dualStack,
+		},
+		{
+			name:       "IPv6 block can not be added",
+			oldNetwork: singleStack,
+			newNetwork: dualStack,
+			err:        errors.New("adding or removing the IPv6 block of the pod network is not allowed"),
+		},
+		{
+			name:       "IPv6 block can not be removed",
+			oldNetwork: dualStack,
+			newNetwork: singleStack,
+			err:        errors.New("adding or removing the IPv6 block of the pod network is not allowed"),
+		},
+		{
+			name:       "IPv6 service block can not be removed",
+			oldNetwork: dualStack,
+			newNetwork: kubermaticv1.ClusterNetworkingConfig{Pods: dualStack.Pods, Services: singleStack.Services},
+			err:        errors.New("adding or removing the IPv6 block of the service network is not allowed"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateClusterNetworkUpdate(&kubermaticv1.ClusterSpec{ClusterNetwork: test.newNetwork}, &kubermaticv1.ClusterSpec{ClusterNetwork: test.oldNetwork})
+			if (err != nil) != (test.err != nil) {
+				t.Errorf("Extected err to be %v, got %v", test.err, err)
+			}
+
+			// loosely validate the returned error message
+			if test.err != nil && !strings.Contains(err.Error(), test.err.Error()) {
+				t.Errorf("Extected err to contain \"%v\", but got \"%v\"", test.err, err)
+			}
+		})
+	}
+}
+
+func TestValidateClusterNetworkConfig(t *testing.T) {
+	cilium := &kubermaticv1.CNIPluginSettings{Type: kubermaticv1.CNIPluginTypeCilium, Version: "v1.10"}
+
+	tests := []struct {
+		name      string
+		pods      []string
+		services  []string
+		version   string
+		cniPlugin *kubermaticv1.CNIPluginSettings
+		err       error
+	}{
+		{
+			name:    "networks not defaulted yet",
+			version: "1.20.0",
+		},
+		{
+			name:     "single-stack",
+			pods:     []string{"172.25.0.0/16"},
+			services: []string{"10.240.16.0/20"},
+			version:  "1.20.0",
+		},
+		{
+			name:      "dual-stack",
+			pods:      []string{"172.25.0.0/16", "fd00::/56"},
+			services:  []string{"10.240.16.0/20", "fd01::/108"},
+			version:   "1.21.0",
+			cniPlugin: cilium,
+		},
+		{
+			name:      "dual-stack with IPv6 as primary family",
+			pods:      []string{"fd00::/56", "172.25.0.0/16"},
+			services:  []string{"fd01::/108", "10.240.16.0/20"},
+			version:   "1.21.0",
+			cniPlugin: cilium,
+		},
+		{
+			name:     "invalid block",
+			pods:     []string{"172.25.0.0"},
+			services: []string{"10.240.16.0/20"},
+			version:  "1.21.0",
+			err:      errors.New(`invalid pod network: "172.25.0.0" is no IPv4 CIDR block`),
+		},
+		{
+			name:      "IPv6 only",
+			pods:      []string{"fd00::/56"},
+			services:  []string{"fd01::/108"},
+			version:   "1.21.0",
+			cniPlugin: cilium,
+			err:       errors.New(`invalid pod network: "fd00::/56" is no IPv4 CIDR block, IPv6 requires an additional IPv4 block`),
+		},
+		{
+			name:      "two IPv4 blocks",
+			pods:      []string{"172.25.0.0/16"},
+			services:  []string{"10.240.16.0/20", "10.240.32.0/20"},
+			version:   "1.21.0",
+			cniPlugin: cilium,
+			err:       errors.New("invalid service network: two CIDR blocks must be one IPv4 and one IPv6 block"),
+		},
+		{
+			name:      "three blocks",
+			pods:      []string{"172.25.0.0/16", "fd00::/56", "172.26.0.0/16"},
+			services:  []string{"10.240.16.0/20", "fd01::/108"},
+			version:   "1.21.0",
+			cniPlugin: cilium,
+			err:       errors.New("invalid pod network: at most two CIDR blocks are allowed, got 3"),
+		},
+		{
+			name:      "IPv6 pod block too small for the nodes",
+			pods:      []string{"172.25.0.0/16", "fd00::/104"},
+			services:  []string{"10.240.16.0/20", "fd01::/108"},
+			version:   "1.21.0",
+			cniPlugin: cilium,
+			err:       errors.New("the prefix length of the IPv6 pod block must be between 48 and 63, got 104"),
+		},
+		{
+			name:      "IPv6 service block too large",
+			pods:      []string{"172.25.0.0/16", "fd00::/56"},
+			services:  []string{"10.240.16.0/20", "fd01::/64"},
+			version:   "1.21.0",
+			cniPlugin: cilium,
+			err:       errors.New("the prefix length of the IPv6 service block must be at least 108, got 64"),
+		},
+		{
+			name:      "dual-stack pods with single-stack services",
+			pods:      []string{"172.25.0.0/16", "fd00::/56"},
+			services:  []string{"10.240.16.0/20"},
+			version:   "1.21.0",
+			cniPlugin: cilium,
+			err:       errors.New("either both the pod and the service network or none of them must have an IPv6 block"),
+		},
+		{
+			name:      "dual-stack on an old version",
+			pods:      []string{"172.25.0.0/16", "fd00::/56"},
+			services:  []string{"10.240.16.0/20", "fd01::/108"},
+			version:   "1.20.0",
+			cniPlugin: cilium,
+			err:       errors.New("dual-stack requires Kubernetes 1.21.0 or newer"),
+		},
+		{
+			name:     "dual-stack with the legacy CNI plugin",
+			pods:     []string{"172.25.0.0/16", "fd00::/56"},
+			services: []string{"10.240.16.0/20", "fd01::/108"},
+			version:  "1.21.0",
+			err:      errors.New(`dual-stack is not supported by the CNI plugin "canal"`),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec := &kubermaticv1.ClusterSpec{
+				ClusterNetwork: kubermaticv1.ClusterNetworkingConfig{
+					Pods:     kubermaticv1.NetworkRanges{CIDRBlocks: test.pods},
+					Services: kubermaticv1.NetworkRanges{CIDRBlocks: test.services},
+				},
+				Version:   *semver.NewSemverOrDie(test.version),
+				CNIPlugin: test.cniPlugin,
+			}
+			err := ValidateClusterNetworkConfig(spec)
+			if (err != nil) != (test.err != nil) {
+				t.Errorf("Extected err to be %v, got %v", test.err, err)
+			}
+
+			// loosely validate the returned error message
+			if test.err != nil && !strings.Contains(err.Error(), test.err.Error()) {
+				t.Errorf("Extected err to contain \"%v\", but got \"%v\"", test.err, err)
+			}
+		})
+	}
+}
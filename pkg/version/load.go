@@ -24,8 +24,13 @@ import (
 	"sigs.k8s.io/yaml"
 )
 
-// LoadUpdates loads the update definition file and returns the defined MasterUpdate
-func LoadUpdates(path string) ([]*Update, error) {
+// updatesFile is the content of the update definition file
+type updatesFile struct {
+	Updates []*Update `json:"updates"`
+	Rollout *Rollout  `json:"rollout,omitempty"`
+}
+
+func loadUpdatesFile(path string) (*updatesFile, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, err
@@ -36,11 +41,8 @@ func LoadUpdates(path string) ([]*Update, error) {
 		return nil, err
 	}
 
-	s := struct {
-		Updates []*Update `json:"updates"`
-	}{}
-
-	err = yaml.UnmarshalStrict(bytes, &s)
+	s := &updatesFile{}
+	err = yaml.UnmarshalStrict(bytes, s)
 	if err != nil {
 		return nil, err
 	}
@@ -52,6 +54,16 @@ func LoadUpdates(path string) ([]*Update, error) {
 		}
 	}
 
+	return s, nil
+}
+
+// LoadUpdates loads the update definition file and returns the defined MasterUpdate
+func LoadUpdates(path string) ([]*Update, error) {
+	s, err := loadUpdatesFile(path)
+	if err != nil {
+		return nil, err
+	}
+
 	return s.Updates, nil
 }
 
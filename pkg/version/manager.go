@@ -25,6 +25,8 @@ import (
 	v1 "k8c.io/kubermatic/v2/pkg/api/v1"
 	kubermaticlog "k8c.io/kubermatic/v2/pkg/log"
 	"k8c.io/kubermatic/v2/pkg/validation/nodeupdate"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var (
@@ -36,6 +38,7 @@ var (
 type Manager struct {
 	versions []*Version
 	updates  []*Update
+	rollout  *Rollout
 }
 
 // Version is the object representing a Kubernetes version.
@@ -54,6 +57,21 @@ type Update struct {
 	Type                string `json:"type,omitempty"`
 }
 
+// Rollout configures the waves in which automatic controlplane updates are rolled out to the
+// clusters of a seed
+type Rollout struct {
+	Waves                  []RolloutWave   `json:"waves,omitempty"`
+	SoakTime               metav1.Duration `json:"soakTime,omitempty"`
+	MaxUnhealthyPercentage int             `json:"maxUnhealthyPercentage,omitempty"`
+}
+
+// RolloutWave selects the clusters of a rollout wave either by their labels or by a percentage
+type RolloutWave struct {
+	Name       string                `json:"name"`
+	Selector   *metav1.LabelSelector `json:"selector,omitempty"`
+	Percentage int                   `json:"percentage,omitempty"`
+}
+
 // New returns a instance of Manager
 func New(versions []*Version, updates []*Update) *Manager {
 	return &Manager{
@@ -62,12 +80,20 @@ func New(versions []*Version, updates []*Update) *Manager {
 	}
 }
 
+// NewWithRollout returns a instance of Manager which rolls out automatic updates in waves
+func NewWithRollout(versions []*Version, updates []*Update, rollout *Rollout) *Manager {
+	m := New(versions, updates)
+	m.rollout = rollout
+	return m
+}
+
 // NewFromFiles returns a instance of manager with the versions & updates loaded from the given paths
 func NewFromFiles(versionsFilename, updatesFilename string) (*Manager, error) {
-	updates, err := LoadUpdates(updatesFilename)
+	updatesFile, err := loadUpdatesFile(updatesFilename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load updates from %s: %v", updatesFilename, err)
 	}
+	updates := updatesFile.Updates
 	for _, update := range updates {
 		// set default type if empty
 		if len(update.Type) == 0 {
@@ -85,7 +111,13 @@ func NewFromFiles(versionsFilename, updatesFilename string) (*Manager, error) {
 		}
 	}
 
-	return New(versions, updates), nil
+	return NewWithRollout(versions, updates, updatesFile.Rollout), nil
+}
+
+// GetRollout returns the configured rollout waves or nil if automatic updates are applied to all
+// clusters at once
+func (m *Manager) GetRollout() *Rollout {
+	return m.rollout
 }
 
 // GetDefault returns the default version
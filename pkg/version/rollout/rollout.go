@@ -0,0 +1,293 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rollout decides which clusters of a seed may start an automatic controlplane update
+// when the updates are rolled out in waves.
+//
+// The rollout does not keep any state of its own, it is derived from the clusters: every cluster
+// belongs to a wave, either by its labels or by a stable bucket computed from its name, and the
+// upgrade status of the clusters tells which waves are updated. A wave is started once all
+// previous waves are updated and the soak time passed since the last cluster of the previous wave
+// completed its update. The rollout pauses as long as too many clusters of a wave are unhealthy.
+package rollout
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+
+	v1 "k8c.io/kubermatic/v2/pkg/api/v1"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/version"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// FinalWaveName is the name of the wave containing all clusters which do not match any configured wave
+const FinalWaveName = "remaining"
+
+// Rollout is the progress of the automatic update to a version
+type Rollout struct {
+	// Version is the version the clusters are updated to
+	Version string
+	// Waves is the progress of each wave, including the final wave
+	Waves []Wave
+	// CurrentWave is the index of the wave which is being updated or, while soaking, which was
+	// updated last. It is -1 once all waves are updated.
+	CurrentWave int
+	// Paused is true if too many clusters of a wave are unhealthy
+	Paused bool
+	// Message explains why the next wave was not started yet
+	Message string
+
+	// startedWaves is the number of waves whose clusters may start the update
+	startedWaves int
+	// version is the parsed Version, it is nil if the Version is invalid
+	version *semver.Version
+}
+
+// Wave is the progress of a wave of a rollout
+type Wave struct {
+	Name string
+	// Clusters is the number of clusters in the wave
+	Clusters int
+	// Updating is the number of clusters which are being updated
+	Updating int
+	// Updated is the number of clusters which completed the update
+	Updated int
+	// Unhealthy is the number of clusters which started the update and are unhealthy
+	Unhealthy int
+	// CompletionTime is the time the last cluster of the wave completed the update, it is
+	// nil until all clusters of the wave are updated
+	CompletionTime *metav1.Time
+}
+
+// Plan is the progress of all automatic updates which are rolled out in a seed
+type Plan struct {
+	// Rollouts are the rollouts in progress, sorted by version
+	Rollouts []*Rollout
+
+	// clusters maps the name of each cluster waiting for an update to its rollout and wave
+	clusters map[string]clusterWave
+}
+
+type clusterWave struct {
+	rollout *Rollout
+	wave    int
+}
+
+// Evaluate computes the progress of the automatic updates configured in the version manager for
+// the given clusters at the given time. It returns nil if no rollout waves are configured.
+func Evaluate(updateManager *version.Manager, clusters []kubermaticv1.Cluster, now time.Time) (*Plan, error) {
+	config := updateManager.GetRollout()
+	if config == nil {
+		return nil, nil
+	}
+
+	selectors := make([]labels.Selector, len(config.Waves))
+	for i, wave := range config.Waves {
+		if wave.Selector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(wave.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector of wave %s: %v", wave.Name, err)
+		}
+		selectors[i] = selector
+	}
+
+	plan := &Plan{clusters: map[string]clusterWave{}}
+	rollouts := map[string]*Rollout{}
+	getRollout := func(version string) *Rollout {
+		if rollouts[version] == nil {
+			rollout := &Rollout{Version: version}
+			// the versions are written by the controllers, an invalid one is sorted after all valid ones
+			rollout.version, _ = semver.NewVersion(version)
+			for _, wave := range config.Waves {
+				rollout.Waves = append(rollout.Waves, Wave{Name: wave.Name})
+			}
+			rollout.Waves = append(rollout.Waves, Wave{Name: FinalWaveName})
+			rollouts[version] = rollout
+		}
+		return rollouts[version]
+	}
+
+	for i := range clusters {
+		cluster := &clusters[i]
+		if cluster.DeletionTimestamp != nil || cluster.Spec.Pause {
+			continue
+		}
+		waveIndex := waveOf(config, selectors, cluster)
+
+		upgrade := cluster.Status.Upgrade
+		switch {
+		case upgrade.InProgress():
+			wave := &getRollout(upgrade.ToVersion).Waves[waveIndex]
+			wave.Clusters++
+			wave.Updating++
+			if cluster.Status.HasConditionValue(kubermaticv1.ClusterConditionUpgradeHalted, corev1.ConditionTrue) {
+				wave.Unhealthy++
+			}
+			continue
+
+		case upgrade != nil && upgrade.Stage == kubermaticv1.ClusterUpgradeStageCompleted && upgrade.ToVersion == cluster.Spec.Version.String():
+			wave := &getRollout(upgrade.ToVersion).Waves[waveIndex]
+			wave.Clusters++
+			wave.Updated++
+			if !cluster.Status.ExtendedHealth.AllHealthy() || !cluster.Status.HasConditionValue(kubermaticv1.ClusterConditionSeedResourcesUpToDate, corev1.ConditionTrue) {
+				wave.Unhealthy++
+			}
+			if wave.CompletionTime == nil || wave.CompletionTime.Before(&upgrade.StageStartTime) {
+				completionTime := upgrade.StageStartTime
+				wave.CompletionTime = &completionTime
+			}
+		}
+
+		target, err := updateManager.AutomaticControlplaneUpdate(cluster.Spec.Version.String(), v1.KubernetesClusterType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get automatic update for cluster %s: %v", cluster.Name, err)
+		}
+		if target == nil {
+			continue
+		}
+		rollout := getRollout(target.Version.String())
+		rollout.Waves[waveIndex].Clusters++
+		plan.clusters[cluster.Name] = clusterWave{rollout: rollout, wave: waveIndex}
+	}
+
+	for _, rollout := range rollouts {
+		rollout.evaluate(config, now)
+		if rollout.CurrentWave != -1 {
+			plan.Rollouts = append(plan.Rollouts, rollout)
+		}
+	}
+	sort.Slice(plan.Rollouts, func(i, j int) bool {
+		return versionLess(plan.Rollouts[i], plan.Rollouts[j])
+	})
+
+	return plan, nil
+}
+
+// versionLess compares the versions of the rollouts semantically, so that 1.9.0 comes before 1.10.0
+func versionLess(a, b *Rollout) bool {
+	if a.version == nil || b.version == nil {
+		if a.version != nil || b.version != nil {
+			return a.version != nil
+		}
+		return a.Version < b.Version
+	}
+	return a.version.LessThan(b.version)
+}
+
+// waveOf returns the index of the wave the cluster belongs to
+func waveOf(config *version.Rollout, selectors []labels.Selector, cluster *kubermaticv1.Cluster) int {
+	bucket := clusterBucket(cluster.Name)
+	percentage := 0
+	for i, wave := range config.Waves {
+		if selectors[i] != nil {
+			if selectors[i].Matches(labels.Set(cluster.Labels)) {
+				return i
+			}
+			continue
+		}
+		percentage += wave.Percentage
+		if bucket < percentage {
+			return i
+		}
+	}
+	return len(config.Waves)
+}
+
+// clusterBucket assigns the cluster to one of 100 buckets, so that percentages of the clusters
+// always select the same clusters
+func clusterBucket(name string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	return int(h.Sum32() % 100)
+}
+
+// evaluate determines the waves which are started and whether the rollout is paused
+func (r *Rollout) evaluate(config *version.Rollout, now time.Time) {
+	for _, wave := range r.Waves {
+		started := wave.Updating + wave.Updated
+		if started > 0 && wave.Unhealthy*100 > config.MaxUnhealthyPercentage*started {
+			r.Paused = true
+			r.Message = fmt.Sprintf("Paused because %d of %d updated clusters of wave %s are unhealthy", wave.Unhealthy, started, wave.Name)
+			break
+		}
+	}
+
+	r.CurrentWave = -1
+	var previousCompletion *metav1.Time
+	for i := range r.Waves {
+		wave := &r.Waves[i]
+		if wave.Clusters == 0 {
+			// empty waves do not soak
+			wave.CompletionTime = previousCompletion
+			r.startedWaves = i + 1
+			continue
+		}
+
+		if previousCompletion != nil {
+			if soakEnd := previousCompletion.Add(config.SoakTime.Duration); now.Before(soakEnd) {
+				if r.Message == "" {
+					r.Message = fmt.Sprintf("Soaking wave %s until %s", r.Waves[r.CurrentWave].Name, soakEnd.Format(time.RFC3339))
+				}
+				return
+			}
+		}
+
+		r.CurrentWave = i
+		r.startedWaves = i + 1
+		if wave.Updated < wave.Clusters {
+			wave.CompletionTime = nil
+			return
+		}
+		previousCompletion = wave.CompletionTime
+	}
+
+	r.CurrentWave = -1
+}
+
+// MayStart returns whether the cluster may start its automatic controlplane update. If it may not,
+// the returned message explains why.
+func (p *Plan) MayStart(cluster *kubermaticv1.Cluster) (bool, string) {
+	if p == nil {
+		return true, ""
+	}
+	entry, ok := p.clusters[cluster.Name]
+	if !ok {
+		return true, ""
+	}
+
+	rollout := entry.rollout
+	switch {
+	case rollout.Paused:
+		return false, fmt.Sprintf("The rollout of %s is paused: %s", rollout.Version, rollout.Message)
+	case entry.wave >= rollout.startedWaves:
+		message := fmt.Sprintf("Waiting for the rollout of %s to reach wave %s", rollout.Version, rollout.Waves[entry.wave].Name)
+		if rollout.Message != "" {
+			message = fmt.Sprintf("%s: %s", message, rollout.Message)
+		}
+		return false, message
+	}
+	return true, ""
+}
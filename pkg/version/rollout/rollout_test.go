@@ -0,0 +1,245 @@
+/*
+Copyright 2021 The Kubermatic Kubernetes Platform contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollout
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	semverlib "github.com/Masterminds/semver/v3"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/semver"
+	"k8c.io/kubermatic/v2/pkg/version"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+var (
+	now = time.Date(2021, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	healthy = kubermaticv1.ExtendedClusterHealth{
+		Apiserver:                    kubermaticv1.HealthStatusUp,
+		Scheduler:                    kubermaticv1.HealthStatusUp,
+		Controller:                   kubermaticv1.HealthStatusUp,
+		MachineController:            kubermaticv1.HealthStatusUp,
+		Etcd:                         kubermaticv1.HealthStatusUp,
+		CloudProviderInfrastructure:  kubermaticv1.HealthStatusUp,
+		UserClusterControllerManager: kubermaticv1.HealthStatusUp,
+	}
+
+	canaryWaves = &version.Rollout{
+		Waves: []version.RolloutWave{{
+			Name:     "canary",
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}},
+		}},
+		SoakTime:               metav1.Duration{Duration: time.Hour},
+		MaxUnhealthyPercentage: 10,
+	}
+)
+
+func updateManager(rollout *version.Rollout) *version.Manager {
+	versions := []*version.Version{
+		{Version: semverlib.MustParse("1.18.0"), Type: "kubernetes"},
+		{Version: semverlib.MustParse("1.19.0"), Type: "kubernetes"},
+	}
+	updates := []*version.Update{{From: "1.18.*", To: "1.19.0", Automatic: true, Type: "kubernetes"}}
+	return version.NewWithRollout(versions, updates, rollout)
+}
+
+func pendingCluster(name string, canary bool) kubermaticv1.Cluster {
+	cluster := kubermaticv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"canary": fmt.Sprint(canary)}},
+		Spec:       kubermaticv1.ClusterSpec{Version: *semver.NewSemverOrDie("1.18.0")},
+	}
+	cluster.Status.ExtendedHealth = healthy
+	cluster.Status.Conditions = []kubermaticv1.ClusterCondition{{
+		Type:   kubermaticv1.ClusterConditionSeedResourcesUpToDate,
+		Status: corev1.ConditionTrue,
+	}}
+	return cluster
+}
+
+func updatedCluster(name string, canary bool, completed time.Time) kubermaticv1.Cluster {
+	cluster := pendingCluster(name, canary)
+	cluster.Spec.Version = *semver.NewSemverOrDie("1.19.0")
+	cluster.Status.Upgrade = &kubermaticv1.ClusterUpgradeStatus{
+		FromVersion:    "1.18.0",
+		ToVersion:      "1.19.0",
+		Stage:          kubermaticv1.ClusterUpgradeStageCompleted,
+		StageStartTime: metav1.NewTime(completed),
+	}
+	return cluster
+}
+
+func TestMayStart(t *testing.T) {
+	unhealthyCanary := updatedCluster("canary", true, now.Add(-2*time.Hour))
+	unhealthyCanary.Status.ExtendedHealth.Apiserver = kubermaticv1.HealthStatusDown
+
+	testCases := []struct {
+		name             string
+		rollout          *version.Rollout
+		clusters         []kubermaticv1.Cluster
+		expectedMayStart map[string]bool
+		expectedWave     string
+		expectedPaused   bool
+	}{
+		{
+			name:             "no waves",
+			clusters:         []kubermaticv1.Cluster{pendingCluster("canary", true), pendingCluster("other", false)},
+			expectedMayStart: map[string]bool{"canary": true, "other": true},
+		},
+		{
+			name:             "canary is updated first",
+			rollout:          canaryWaves,
+			clusters:         []kubermaticv1.Cluster{pendingCluster("canary", true), pendingCluster("other", false)},
+			expectedMayStart: map[string]bool{"canary": true, "other": false},
+			expectedWave:     "canary",
+		},
+		{
+			name:             "updated canary soaks",
+			rollout:          canaryWaves,
+			clusters:         []kubermaticv1.Cluster{updatedCluster("canary", true, now.Add(-30*time.Minute)), pendingCluster("other", false)},
+			expectedMayStart: map[string]bool{"other": false},
+			expectedWave:     "canary",
+		},
+		{
+			name:             "next wave starts after the soak time",
+			rollout:          canaryWaves,
+			clusters:         []kubermaticv1.Cluster{updatedCluster("canary", true, now.Add(-2*time.Hour)), pendingCluster("other", false)},
+			expectedMayStart: map[string]bool{"other": true},
+			expectedWave:     FinalWaveName,
+		},
+		{
+			name:             "empty canary wave is skipped",
+			rollout:          canaryWaves,
+			clusters:         []kubermaticv1.Cluster{pendingCluster("other", false)},
+			expectedMayStart: map[string]bool{"other": true},
+			expectedWave:     FinalWaveName,
+		},
+		{
+			name:             "unhealthy canary pauses the rollout",
+			rollout:          canaryWaves,
+			clusters:         []kubermaticv1.Cluster{unhealthyCanary, pendingCluster("other", false)},
+			expectedMayStart: map[string]bool{"other": false},
+			expectedWave:     FinalWaveName,
+			expectedPaused:   true,
+		},
+		{
+			name: "percentage of all clusters",
+			rollout: &version.Rollout{
+				Waves:                  []version.RolloutWave{{Name: "all", Percentage: 100}},
+				MaxUnhealthyPercentage: 10,
+			},
+			clusters:         []kubermaticv1.Cluster{pendingCluster("canary", true), pendingCluster("other", false)},
+			expectedMayStart: map[string]bool{"canary": true, "other": true},
+			expectedWave:     "all",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			plan, err := Evaluate(updateManager(tc.rollout), tc.clusters, now)
+			if err != nil {
+				t.Fatalf("failed to evaluate rollout: %v", err)
+			}
+
+			for i := range tc.clusters {
+				cluster := &tc.clusters[i]
+				expected, ok := tc.expectedMayStart[cluster.Name]
+				if !ok {
+					continue
+				}
+				if mayStart, message := plan.MayStart(cluster); mayStart != expected {
+					t.Errorf("expected cluster %s to be allowed to start: %v, got %v (%s)", cluster.Name, expected, mayStart, message)
+				}
+			}
+
+			if tc.rollout == nil {
+				if plan != nil {
+					t.Errorf("expected no plan without rollout waves, got %v", plan)
+				}
+				return
+			}
+			if len(plan.Rollouts) != 1 {
+				t.Fatalf("expected exactly one rollout, got %d", len(plan.Rollouts))
+			}
+			rollout := plan.Rollouts[0]
+			if wave := rollout.Waves[rollout.CurrentWave].Name; wave != tc.expectedWave {
+				t.Errorf("expected current wave %q, got %q", tc.expectedWave, wave)
+			}
+			if rollout.Paused != tc.expectedPaused {
+				t.Errorf("expected rollout to be paused: %v, got %v (%s)", tc.expectedPaused, rollout.Paused, rollout.Message)
+			}
+		})
+	}
+}
+
+func TestClusterBucketIsStable(t *testing.T) {
+	config := &version.Rollout{
+		Waves: []version.RolloutWave{{Name: "first", Percentage: 30}, {Name: "second", Percentage: 30}},
+	}
+	cluster := &kubermaticv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "abcdefgh"}}
+	selectors := make([]labels.Selector, len(config.Waves))
+
+	wave := waveOf(config, selectors, cluster)
+	for i := 0; i < 10; i++ {
+		if w := waveOf(config, selectors, cluster); w != wave {
+			t.Fatalf("expected cluster to stay in wave %d, got %d", wave, w)
+		}
+	}
+
+	bucket := clusterBucket(cluster.Name)
+	expected := 2
+	switch {
+	case bucket < 30:
+		expected = 0
+	case bucket < 60:
+		expected = 1
+	}
+	if wave != expected {
+		t.Errorf("expected cluster in bucket %d to be in wave %d, got %d", bucket, expected, wave)
+	}
+}
+
+func TestRolloutsAreSortedByVersion(t *testing.T) {
+	var clusters []kubermaticv1.Cluster
+	for _, toVersion := range []string{"1.10.0", "1.9.0"} {
+		cluster := pendingCluster("updating-to-"+toVersion, true)
+		cluster.Status.Upgrade = &kubermaticv1.ClusterUpgradeStatus{
+			FromVersion: "1.18.0",
+			ToVersion:   toVersion,
+			Stage:       kubermaticv1.ClusterUpgradeStageApiserver,
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	plan, err := Evaluate(updateManager(canaryWaves), clusters, now)
+	if err != nil {
+		t.Fatalf("failed to evaluate the rollouts: %v", err)
+	}
+	var versions []string
+	for _, rollout := range plan.Rollouts {
+		versions = append(versions, rollout.Version)
+	}
+	if expected := []string{"1.9.0", "1.10.0"}; fmt.Sprint(versions) != fmt.Sprint(expected) {
+		t.Errorf("expected the rollouts to be sorted as %v, got %v", expected, versions)
+	}
+}
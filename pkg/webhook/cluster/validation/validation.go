@@ -26,6 +26,7 @@ import (
 
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/crd/kubermatic/v1"
 	"k8c.io/kubermatic/v2/pkg/features"
+	"k8c.io/kubermatic/v2/pkg/quota"
 	"k8c.io/kubermatic/v2/pkg/validation"
 
 	admissionv1 "k8s.io/api/admission/v1"
@@ -86,6 +87,13 @@ func (h *AdmissionHandler) Handle(ctx context.Context, req webhook.AdmissionRequ
 			h.log.Info("cluster admission failed", "error", immutabilityErr)
 			return webhook.Denied(fmt.Sprintf("cluster validation request %s rejected: %v", req.UID, immutabilityErr))
 		}
+
+		if req.Operation == admissionv1.Create {
+			if quotaErr := h.validateProjectResourceQuota(ctx, cluster); quotaErr != nil {
+				h.log.Info("cluster admission failed", "error", quotaErr)
+				return webhook.Denied(fmt.Sprintf("cluster validation request %s rejected: %v", req.UID, quotaErr))
+			}
+		}
 	case admissionv1.Delete:
 		// NOP we always allow delete operarions at the moment
 	default:
@@ -108,6 +116,9 @@ func (h *AdmissionHandler) validateCreateOrUpdate(ctx context.Context, c *kuberm
 	if err := validation.ValidateLeaderElectionSettings(c.Spec.ComponentsOverride.Scheduler.LeaderElectionSettings); err != nil {
 		return fmt.Errorf("scheduler leader election settings are not valid: %w", err)
 	}
+	if err := validation.ValidateClusterNetworkConfig(&c.Spec); err != nil {
+		return fmt.Errorf("cluster network is not valid: %w", err)
+	}
 
 	if err := h.rejectUserSSHKeyAgentChanges(ctx, c); err != nil {
 		h.log.Info("cluster admission failed", "error", err)
@@ -134,6 +145,10 @@ func (h *AdmissionHandler) validateUpdateImmutability(req webhook.AdmissionReque
 		return fmt.Errorf("feature gate %q cannot be disabled once it's enabled", kubermaticv1.ClusterFeatureExternalCloudProvider)
 	}
 
+	if err := validation.ValidateClusterNetworkUpdate(&c.Spec, &oldCluster.Spec); err != nil {
+		return fmt.Errorf("cluster network update is not valid: %w", err)
+	}
+
 	return nil
 }
 
@@ -163,3 +178,41 @@ func (h *AdmissionHandler) rejectUserSSHKeyAgentChanges(ctx context.Context, clu
 
 	return nil
 }
+
+// validateProjectResourceQuota rejects new clusters which, together with their initial machine deployment, would
+// exceed the resource quota of their project. The usage of the project in all seeds is taken from its status, which
+// the master controller manager aggregates from the status of the clusters, but at least the clusters of the project
+// in this seed are counted.
+func (h *AdmissionHandler) validateProjectResourceQuota(ctx context.Context, cluster *kubermaticv1.Cluster) error {
+	projectID := cluster.Labels[kubermaticv1.ProjectIDLabelKey]
+	if h.client == nil || projectID == "" {
+		return nil
+	}
+
+	// the projects are synced into the seeds by the master controller manager
+	project := &kubermaticv1.Project{}
+	if err := h.client.Get(ctx, types.NamespacedName{Name: projectID}, project); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to fetch project name=%s: %v", projectID, err)
+	}
+	if project.Spec.ResourceQuota == nil {
+		return nil
+	}
+
+	clusters := &kubermaticv1.ClusterList{}
+	if err := h.client.List(ctx, clusters, ctrlruntimeclient.MatchingLabels{kubermaticv1.ProjectIDLabelKey: projectID}); err != nil {
+		return fmt.Errorf("failed to list the clusters of project name=%s: %v", projectID, err)
+	}
+	usage, err := quota.AdmissionUsage(project, clusters.Items)
+	if err != nil {
+		return err
+	}
+
+	requested, err := quota.ClusterUsage(ctx, cluster, nil)
+	if err != nil {
+		return err
+	}
+	return quota.Check(project.Spec.ResourceQuota, usage, requested)
+}
@@ -326,6 +326,113 @@ func TestHandle(t *testing.T) {
 				},
 			).Build(),
 		},
+		{
+			name: "Accept a cluster update keeping the IP families",
+			req: webhook.AdmissionRequest{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Update,
+					RequestKind: &metav1.GroupVersionKind{
+						Group:   kubermaticv1.GroupName,
+						Version: kubermaticv1.GroupVersion,
+						Kind:    "Cluster",
+					},
+					Name: "foo",
+					Object: runtime.RawExtension{
+						Raw: rawClusterGen{Name: "foo", Namespace: "kubermatic", ExposeStrategy: "NodePort", Pods: []string{"172.25.0.0/16"}, Services: []string{"10.240.16.0/20"}}.Do(),
+					},
+					OldObject: runtime.RawExtension{
+						Raw: rawClusterGen{Name: "foo", Namespace: "kubermatic", ExposeStrategy: "NodePort", Pods: []string{"172.25.0.0/16"}, Services: []string{"10.240.16.0/20"}}.Do(),
+					},
+				},
+			},
+			wantAllowed: true,
+		},
+		{
+			name: "Reject removing the IPv6 blocks of a dual-stack cluster",
+			req: webhook.AdmissionRequest{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Update,
+					RequestKind: &metav1.GroupVersionKind{
+						Group:   kubermaticv1.GroupName,
+						Version: kubermaticv1.GroupVersion,
+						Kind:    "Cluster",
+					},
+					Name: "foo",
+					Object: runtime.RawExtension{
+						Raw: rawClusterGen{Name: "foo", Namespace: "kubermatic", ExposeStrategy: "NodePort", Pods: []string{"172.25.0.0/16"}, Services: []string{"10.240.16.0/20"}}.Do(),
+					},
+					OldObject: runtime.RawExtension{
+						Raw: rawClusterGen{Name: "foo", Namespace: "kubermatic", ExposeStrategy: "NodePort", Pods: []string{"172.25.0.0/16", "fd00::/56"}, Services: []string{"10.240.16.0/20", "fd01::/108"}}.Do(),
+					},
+				},
+			},
+			wantAllowed: false,
+		},
+		{
+			name: "Reject cluster creation exceeding the cluster quota of the project",
+			req: webhook.AdmissionRequest{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					RequestKind: &metav1.GroupVersionKind{
+						Group:   kubermaticv1.GroupName,
+						Version: kubermaticv1.GroupVersion,
+						Kind:    "Cluster",
+					},
+					Name: "foo",
+					Object: runtime.RawExtension{
+						Raw: rawClusterGen{Name: "foo", Namespace: "kubermatic", ExposeStrategy: "NodePort", ProjectID: "my-project"}.Do(),
+					},
+				},
+			},
+			wantAllowed: false,
+			client: ctrlruntimefakeclient.NewClientBuilder().WithObjects(
+				genProject("my-project", 1, 1),
+			).Build(),
+		},
+		{
+			name: "Accept cluster creation within the cluster quota of the project",
+			req: webhook.AdmissionRequest{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					RequestKind: &metav1.GroupVersionKind{
+						Group:   kubermaticv1.GroupName,
+						Version: kubermaticv1.GroupVersion,
+						Kind:    "Cluster",
+					},
+					Name: "foo",
+					Object: runtime.RawExtension{
+						Raw: rawClusterGen{Name: "foo", Namespace: "kubermatic", ExposeStrategy: "NodePort", ProjectID: "my-project"}.Do(),
+					},
+				},
+			},
+			wantAllowed: true,
+			client: ctrlruntimefakeclient.NewClientBuilder().WithObjects(
+				genProject("my-project", 2, 1),
+			).Build(),
+		},
+		{
+			name: "Reject cluster creation exceeding the cluster quota with clusters not yet accounted in the project",
+			req: webhook.AdmissionRequest{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					RequestKind: &metav1.GroupVersionKind{
+						Group:   kubermaticv1.GroupName,
+						Version: kubermaticv1.GroupVersion,
+						Kind:    "Cluster",
+					},
+					Name: "foo",
+					Object: runtime.RawExtension{
+						Raw: rawClusterGen{Name: "foo", Namespace: "kubermatic", ExposeStrategy: "NodePort", ProjectID: "my-project"}.Do(),
+					},
+				},
+			},
+			wantAllowed: false,
+			client: ctrlruntimefakeclient.NewClientBuilder().WithObjects(
+				genProject("my-project", 2, 0),
+				&kubermaticv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "bar", Labels: map[string]string{kubermaticv1.ProjectIDLabelKey: "my-project"}}},
+				&kubermaticv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "baz", Labels: map[string]string{kubermaticv1.ProjectIDLabelKey: "my-project"}}},
+			).Build(),
+		},
 	}
 	for _, tt := range tests {
 		d, err := admission.NewDecoder(testScheme)
@@ -350,9 +457,12 @@ func TestHandle(t *testing.T) {
 type rawClusterGen struct {
 	Name                  string
 	Namespace             string
+	ProjectID             string
 	ExposeStrategy        string
 	EnableUserSSHKey      bool
 	ExternalCloudProvider bool
+	Pods                  []string
+	Services              []string
 }
 
 func (r rawClusterGen) Do() []byte {
@@ -361,13 +471,24 @@ func (r rawClusterGen) Do() []byte {
   "kind": "Cluster",
   "metadata": {
 	"name": "{{ .Name }}",
-	"namespace": "{{ .Namespace}}"
+	"namespace": "{{ .Namespace}}",
+	"labels": {
+		"project-id": "{{ .ProjectID }}"
+	}
   },
   "spec": {
 	"exposeStrategy": "{{ .ExposeStrategy }}",
 	"enableUserSSHKey": {{ .EnableUserSSHKey }},
 	"features": {
 		"externalCloudProvider": {{ .ExternalCloudProvider }}
+	},
+	"clusterNetwork": {
+		"pods": {
+			"cidrBlocks": [{{ range $i, $block := .Pods }}{{ if $i }}, {{ end }}"{{ $block }}"{{ end }}]
+		},
+		"services": {
+			"cidrBlocks": [{{ range $i, $block := .Services }}{{ if $i }}, {{ end }}"{{ $block }}"{{ end }}]
+		}
 	}
   }
 }`)
@@ -375,3 +496,19 @@ func (r rawClusterGen) Do() []byte {
 	_ = tmpl.Execute(&sb, r)
 	return sb.Bytes()
 }
+
+func genProject(name string, clusters, usedClusters int64) *kubermaticv1.Project {
+	return &kubermaticv1.Project{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: kubermaticv1.ProjectSpec{
+			ResourceQuota: &kubermaticv1.ProjectResourceQuota{
+				Clusters: &clusters,
+			},
+		},
+		Status: kubermaticv1.ProjectStatus{
+			ResourceUsage: &kubermaticv1.ResourceUsage{Clusters: usedClusters},
+		},
+	}
+}